@@ -0,0 +1,1449 @@
+package jscal
+
+// Accessors for optional fields. Get returns the field's value or the zero
+// value if it isn't set, Set sets it and Has reports whether it is set.
+// Durations are read with the typed getters such as Event.GetDuration.
+
+// deref returns the value p points to, or the zero value if p is nil
+func deref[T any](p *T) T {
+	if p == nil {
+		var zero T
+		return zero
+	}
+	return *p
+}
+
+// Event accessors
+
+// GetSequence returns the sequence, or 0 if it isn't set
+func (e *Event) GetSequence() int {
+	return deref(e.Sequence)
+}
+
+// SetSequence sets the sequence
+func (e *Event) SetSequence(sequence int) {
+	e.Sequence = &sequence
+}
+
+// HasSequence reports whether the sequence is set
+func (e *Event) HasSequence() bool {
+	return e.Sequence != nil
+}
+
+// GetMethod returns the method, or "" if it isn't set
+func (e *Event) GetMethod() string {
+	return deref(e.Method)
+}
+
+// SetMethod sets the method
+func (e *Event) SetMethod(method string) {
+	e.Method = &method
+}
+
+// HasMethod reports whether the method is set
+func (e *Event) HasMethod() bool {
+	return e.Method != nil
+}
+
+// GetProdId returns the prodId, or "" if it isn't set
+func (e *Event) GetProdId() string {
+	return deref(e.ProdId)
+}
+
+// SetProdId sets the prodId
+func (e *Event) SetProdId(prodId string) {
+	e.ProdId = &prodId
+}
+
+// HasProdId reports whether the prodId is set
+func (e *Event) HasProdId() bool {
+	return e.ProdId != nil
+}
+
+// GetTitle returns the title, or "" if it isn't set
+func (e *Event) GetTitle() string {
+	return deref(e.Title)
+}
+
+// SetTitle sets the title
+func (e *Event) SetTitle(title string) {
+	e.Title = &title
+}
+
+// HasTitle reports whether the title is set
+func (e *Event) HasTitle() bool {
+	return e.Title != nil
+}
+
+// GetDescription returns the description, or "" if it isn't set
+func (e *Event) GetDescription() string {
+	return deref(e.Description)
+}
+
+// SetDescription sets the description
+func (e *Event) SetDescription(description string) {
+	e.Description = &description
+}
+
+// HasDescription reports whether the description is set
+func (e *Event) HasDescription() bool {
+	return e.Description != nil
+}
+
+// GetDescriptionContentType returns the descriptionContentType, or "" if it isn't set
+func (e *Event) GetDescriptionContentType() string {
+	return deref(e.DescriptionContentType)
+}
+
+// SetDescriptionContentType sets the descriptionContentType
+func (e *Event) SetDescriptionContentType(descriptionContentType string) {
+	e.DescriptionContentType = &descriptionContentType
+}
+
+// HasDescriptionContentType reports whether the descriptionContentType is set
+func (e *Event) HasDescriptionContentType() bool {
+	return e.DescriptionContentType != nil
+}
+
+// GetShowWithoutTime returns the showWithoutTime, or false if it isn't set
+func (e *Event) GetShowWithoutTime() bool {
+	return deref(e.ShowWithoutTime)
+}
+
+// SetShowWithoutTime sets the showWithoutTime
+func (e *Event) SetShowWithoutTime(showWithoutTime bool) {
+	e.ShowWithoutTime = &showWithoutTime
+}
+
+// HasShowWithoutTime reports whether the showWithoutTime is set
+func (e *Event) HasShowWithoutTime() bool {
+	return e.ShowWithoutTime != nil
+}
+
+// GetLocale returns the locale, or "" if it isn't set
+func (e *Event) GetLocale() string {
+	return deref(e.Locale)
+}
+
+// SetLocale sets the locale
+func (e *Event) SetLocale(locale string) {
+	e.Locale = &locale
+}
+
+// HasLocale reports whether the locale is set
+func (e *Event) HasLocale() bool {
+	return e.Locale != nil
+}
+
+// GetColor returns the color, or "" if it isn't set
+func (e *Event) GetColor() string {
+	return deref(e.Color)
+}
+
+// SetColor sets the color
+func (e *Event) SetColor(color string) {
+	e.Color = &color
+}
+
+// HasColor reports whether the color is set
+func (e *Event) HasColor() bool {
+	return e.Color != nil
+}
+
+// GetTimeZone returns the timeZone, or "" if it isn't set
+func (e *Event) GetTimeZone() string {
+	return deref(e.TimeZone)
+}
+
+// SetTimeZone sets the timeZone
+func (e *Event) SetTimeZone(timeZone string) {
+	e.TimeZone = &timeZone
+}
+
+// HasTimeZone reports whether the timeZone is set
+func (e *Event) HasTimeZone() bool {
+	return e.TimeZone != nil
+}
+
+// GetRecurrenceIdTimeZone returns the recurrenceIdTimeZone, or "" if it isn't set
+func (e *Event) GetRecurrenceIdTimeZone() string {
+	return deref(e.RecurrenceIdTimeZone)
+}
+
+// SetRecurrenceIdTimeZone sets the recurrenceIdTimeZone
+func (e *Event) SetRecurrenceIdTimeZone(recurrenceIdTimeZone string) {
+	e.RecurrenceIdTimeZone = &recurrenceIdTimeZone
+}
+
+// HasRecurrenceIdTimeZone reports whether the recurrenceIdTimeZone is set
+func (e *Event) HasRecurrenceIdTimeZone() bool {
+	return e.RecurrenceIdTimeZone != nil
+}
+
+// GetExcluded returns the excluded, or false if it isn't set
+func (e *Event) GetExcluded() bool {
+	return deref(e.Excluded)
+}
+
+// SetExcluded sets the excluded
+func (e *Event) SetExcluded(excluded bool) {
+	e.Excluded = &excluded
+}
+
+// HasExcluded reports whether the excluded is set
+func (e *Event) HasExcluded() bool {
+	return e.Excluded != nil
+}
+
+// GetPriority returns the priority, or 0 if it isn't set
+func (e *Event) GetPriority() int {
+	return deref(e.Priority)
+}
+
+// SetPriority sets the priority
+func (e *Event) SetPriority(priority int) {
+	e.Priority = &priority
+}
+
+// HasPriority reports whether the priority is set
+func (e *Event) HasPriority() bool {
+	return e.Priority != nil
+}
+
+// GetFreeBusyStatus returns the freeBusyStatus, or "" if it isn't set
+func (e *Event) GetFreeBusyStatus() string {
+	return deref(e.FreeBusyStatus)
+}
+
+// SetFreeBusyStatus sets the freeBusyStatus
+func (e *Event) SetFreeBusyStatus(freeBusyStatus string) {
+	e.FreeBusyStatus = &freeBusyStatus
+}
+
+// HasFreeBusyStatus reports whether the freeBusyStatus is set
+func (e *Event) HasFreeBusyStatus() bool {
+	return e.FreeBusyStatus != nil
+}
+
+// GetPrivacy returns the privacy, or "" if it isn't set
+func (e *Event) GetPrivacy() string {
+	return deref(e.Privacy)
+}
+
+// SetPrivacy sets the privacy
+func (e *Event) SetPrivacy(privacy string) {
+	e.Privacy = &privacy
+}
+
+// HasPrivacy reports whether the privacy is set
+func (e *Event) HasPrivacy() bool {
+	return e.Privacy != nil
+}
+
+// GetSentBy returns the sentBy, or "" if it isn't set
+func (e *Event) GetSentBy() string {
+	return deref(e.SentBy)
+}
+
+// SetSentBy sets the sentBy
+func (e *Event) SetSentBy(sentBy string) {
+	e.SentBy = &sentBy
+}
+
+// HasSentBy reports whether the sentBy is set
+func (e *Event) HasSentBy() bool {
+	return e.SentBy != nil
+}
+
+// GetRequestStatus returns the requestStatus, or "" if it isn't set
+func (e *Event) GetRequestStatus() string {
+	return deref(e.RequestStatus)
+}
+
+// SetRequestStatus sets the requestStatus
+func (e *Event) SetRequestStatus(requestStatus string) {
+	e.RequestStatus = &requestStatus
+}
+
+// HasRequestStatus reports whether the requestStatus is set
+func (e *Event) HasRequestStatus() bool {
+	return e.RequestStatus != nil
+}
+
+// GetMayInviteSelf returns the mayInviteSelf, or false if it isn't set
+func (e *Event) GetMayInviteSelf() bool {
+	return deref(e.MayInviteSelf)
+}
+
+// SetMayInviteSelf sets the mayInviteSelf
+func (e *Event) SetMayInviteSelf(mayInviteSelf bool) {
+	e.MayInviteSelf = &mayInviteSelf
+}
+
+// HasMayInviteSelf reports whether the mayInviteSelf is set
+func (e *Event) HasMayInviteSelf() bool {
+	return e.MayInviteSelf != nil
+}
+
+// GetMayInviteOthers returns the mayInviteOthers, or false if it isn't set
+func (e *Event) GetMayInviteOthers() bool {
+	return deref(e.MayInviteOthers)
+}
+
+// SetMayInviteOthers sets the mayInviteOthers
+func (e *Event) SetMayInviteOthers(mayInviteOthers bool) {
+	e.MayInviteOthers = &mayInviteOthers
+}
+
+// HasMayInviteOthers reports whether the mayInviteOthers is set
+func (e *Event) HasMayInviteOthers() bool {
+	return e.MayInviteOthers != nil
+}
+
+// GetHideAttendees returns the hideAttendees, or false if it isn't set
+func (e *Event) GetHideAttendees() bool {
+	return deref(e.HideAttendees)
+}
+
+// SetHideAttendees sets the hideAttendees
+func (e *Event) SetHideAttendees(hideAttendees bool) {
+	e.HideAttendees = &hideAttendees
+}
+
+// HasHideAttendees reports whether the hideAttendees is set
+func (e *Event) HasHideAttendees() bool {
+	return e.HideAttendees != nil
+}
+
+// GetUseDefaultAlerts returns the useDefaultAlerts, or false if it isn't set
+func (e *Event) GetUseDefaultAlerts() bool {
+	return deref(e.UseDefaultAlerts)
+}
+
+// SetUseDefaultAlerts sets the useDefaultAlerts
+func (e *Event) SetUseDefaultAlerts(useDefaultAlerts bool) {
+	e.UseDefaultAlerts = &useDefaultAlerts
+}
+
+// HasUseDefaultAlerts reports whether the useDefaultAlerts is set
+func (e *Event) HasUseDefaultAlerts() bool {
+	return e.UseDefaultAlerts != nil
+}
+
+// GetStatus returns the status, or "" if it isn't set
+func (e *Event) GetStatus() string {
+	return deref(e.Status)
+}
+
+// SetStatus sets the status
+func (e *Event) SetStatus(status string) {
+	e.Status = &status
+}
+
+// HasStatus reports whether the status is set
+func (e *Event) HasStatus() bool {
+	return e.Status != nil
+}
+
+// Task accessors
+
+// GetSequence returns the sequence, or 0 if it isn't set
+func (t *Task) GetSequence() int {
+	return deref(t.Sequence)
+}
+
+// SetSequence sets the sequence
+func (t *Task) SetSequence(sequence int) {
+	t.Sequence = &sequence
+}
+
+// HasSequence reports whether the sequence is set
+func (t *Task) HasSequence() bool {
+	return t.Sequence != nil
+}
+
+// GetMethod returns the method, or "" if it isn't set
+func (t *Task) GetMethod() string {
+	return deref(t.Method)
+}
+
+// SetMethod sets the method
+func (t *Task) SetMethod(method string) {
+	t.Method = &method
+}
+
+// HasMethod reports whether the method is set
+func (t *Task) HasMethod() bool {
+	return t.Method != nil
+}
+
+// GetProdId returns the prodId, or "" if it isn't set
+func (t *Task) GetProdId() string {
+	return deref(t.ProdId)
+}
+
+// SetProdId sets the prodId
+func (t *Task) SetProdId(prodId string) {
+	t.ProdId = &prodId
+}
+
+// HasProdId reports whether the prodId is set
+func (t *Task) HasProdId() bool {
+	return t.ProdId != nil
+}
+
+// GetTitle returns the title, or "" if it isn't set
+func (t *Task) GetTitle() string {
+	return deref(t.Title)
+}
+
+// SetTitle sets the title
+func (t *Task) SetTitle(title string) {
+	t.Title = &title
+}
+
+// HasTitle reports whether the title is set
+func (t *Task) HasTitle() bool {
+	return t.Title != nil
+}
+
+// GetDescription returns the description, or "" if it isn't set
+func (t *Task) GetDescription() string {
+	return deref(t.Description)
+}
+
+// SetDescription sets the description
+func (t *Task) SetDescription(description string) {
+	t.Description = &description
+}
+
+// HasDescription reports whether the description is set
+func (t *Task) HasDescription() bool {
+	return t.Description != nil
+}
+
+// GetDescriptionContentType returns the descriptionContentType, or "" if it isn't set
+func (t *Task) GetDescriptionContentType() string {
+	return deref(t.DescriptionContentType)
+}
+
+// SetDescriptionContentType sets the descriptionContentType
+func (t *Task) SetDescriptionContentType(descriptionContentType string) {
+	t.DescriptionContentType = &descriptionContentType
+}
+
+// HasDescriptionContentType reports whether the descriptionContentType is set
+func (t *Task) HasDescriptionContentType() bool {
+	return t.DescriptionContentType != nil
+}
+
+// GetShowWithoutTime returns the showWithoutTime, or false if it isn't set
+func (t *Task) GetShowWithoutTime() bool {
+	return deref(t.ShowWithoutTime)
+}
+
+// SetShowWithoutTime sets the showWithoutTime
+func (t *Task) SetShowWithoutTime(showWithoutTime bool) {
+	t.ShowWithoutTime = &showWithoutTime
+}
+
+// HasShowWithoutTime reports whether the showWithoutTime is set
+func (t *Task) HasShowWithoutTime() bool {
+	return t.ShowWithoutTime != nil
+}
+
+// GetLocale returns the locale, or "" if it isn't set
+func (t *Task) GetLocale() string {
+	return deref(t.Locale)
+}
+
+// SetLocale sets the locale
+func (t *Task) SetLocale(locale string) {
+	t.Locale = &locale
+}
+
+// HasLocale reports whether the locale is set
+func (t *Task) HasLocale() bool {
+	return t.Locale != nil
+}
+
+// GetColor returns the color, or "" if it isn't set
+func (t *Task) GetColor() string {
+	return deref(t.Color)
+}
+
+// SetColor sets the color
+func (t *Task) SetColor(color string) {
+	t.Color = &color
+}
+
+// HasColor reports whether the color is set
+func (t *Task) HasColor() bool {
+	return t.Color != nil
+}
+
+// GetTimeZone returns the timeZone, or "" if it isn't set
+func (t *Task) GetTimeZone() string {
+	return deref(t.TimeZone)
+}
+
+// SetTimeZone sets the timeZone
+func (t *Task) SetTimeZone(timeZone string) {
+	t.TimeZone = &timeZone
+}
+
+// HasTimeZone reports whether the timeZone is set
+func (t *Task) HasTimeZone() bool {
+	return t.TimeZone != nil
+}
+
+// GetPercentComplete returns the percentComplete, or 0 if it isn't set
+func (t *Task) GetPercentComplete() int {
+	return deref(t.PercentComplete)
+}
+
+// SetPercentComplete sets the percentComplete
+func (t *Task) SetPercentComplete(percentComplete int) {
+	t.PercentComplete = &percentComplete
+}
+
+// HasPercentComplete reports whether the percentComplete is set
+func (t *Task) HasPercentComplete() bool {
+	return t.PercentComplete != nil
+}
+
+// GetProgress returns the progress, or "" if it isn't set
+func (t *Task) GetProgress() string {
+	return deref(t.Progress)
+}
+
+// HasProgress reports whether the progress is set
+func (t *Task) HasProgress() bool {
+	return t.Progress != nil
+}
+
+// GetRecurrenceIdTimeZone returns the recurrenceIdTimeZone, or "" if it isn't set
+func (t *Task) GetRecurrenceIdTimeZone() string {
+	return deref(t.RecurrenceIdTimeZone)
+}
+
+// SetRecurrenceIdTimeZone sets the recurrenceIdTimeZone
+func (t *Task) SetRecurrenceIdTimeZone(recurrenceIdTimeZone string) {
+	t.RecurrenceIdTimeZone = &recurrenceIdTimeZone
+}
+
+// HasRecurrenceIdTimeZone reports whether the recurrenceIdTimeZone is set
+func (t *Task) HasRecurrenceIdTimeZone() bool {
+	return t.RecurrenceIdTimeZone != nil
+}
+
+// GetExcluded returns the excluded, or false if it isn't set
+func (t *Task) GetExcluded() bool {
+	return deref(t.Excluded)
+}
+
+// SetExcluded sets the excluded
+func (t *Task) SetExcluded(excluded bool) {
+	t.Excluded = &excluded
+}
+
+// HasExcluded reports whether the excluded is set
+func (t *Task) HasExcluded() bool {
+	return t.Excluded != nil
+}
+
+// GetPriority returns the priority, or 0 if it isn't set
+func (t *Task) GetPriority() int {
+	return deref(t.Priority)
+}
+
+// SetPriority sets the priority
+func (t *Task) SetPriority(priority int) {
+	t.Priority = &priority
+}
+
+// HasPriority reports whether the priority is set
+func (t *Task) HasPriority() bool {
+	return t.Priority != nil
+}
+
+// GetFreeBusyStatus returns the freeBusyStatus, or "" if it isn't set
+func (t *Task) GetFreeBusyStatus() string {
+	return deref(t.FreeBusyStatus)
+}
+
+// SetFreeBusyStatus sets the freeBusyStatus
+func (t *Task) SetFreeBusyStatus(freeBusyStatus string) {
+	t.FreeBusyStatus = &freeBusyStatus
+}
+
+// HasFreeBusyStatus reports whether the freeBusyStatus is set
+func (t *Task) HasFreeBusyStatus() bool {
+	return t.FreeBusyStatus != nil
+}
+
+// GetPrivacy returns the privacy, or "" if it isn't set
+func (t *Task) GetPrivacy() string {
+	return deref(t.Privacy)
+}
+
+// SetPrivacy sets the privacy
+func (t *Task) SetPrivacy(privacy string) {
+	t.Privacy = &privacy
+}
+
+// HasPrivacy reports whether the privacy is set
+func (t *Task) HasPrivacy() bool {
+	return t.Privacy != nil
+}
+
+// GetSentBy returns the sentBy, or "" if it isn't set
+func (t *Task) GetSentBy() string {
+	return deref(t.SentBy)
+}
+
+// SetSentBy sets the sentBy
+func (t *Task) SetSentBy(sentBy string) {
+	t.SentBy = &sentBy
+}
+
+// HasSentBy reports whether the sentBy is set
+func (t *Task) HasSentBy() bool {
+	return t.SentBy != nil
+}
+
+// GetRequestStatus returns the requestStatus, or "" if it isn't set
+func (t *Task) GetRequestStatus() string {
+	return deref(t.RequestStatus)
+}
+
+// SetRequestStatus sets the requestStatus
+func (t *Task) SetRequestStatus(requestStatus string) {
+	t.RequestStatus = &requestStatus
+}
+
+// HasRequestStatus reports whether the requestStatus is set
+func (t *Task) HasRequestStatus() bool {
+	return t.RequestStatus != nil
+}
+
+// GetMayInviteSelf returns the mayInviteSelf, or false if it isn't set
+func (t *Task) GetMayInviteSelf() bool {
+	return deref(t.MayInviteSelf)
+}
+
+// SetMayInviteSelf sets the mayInviteSelf
+func (t *Task) SetMayInviteSelf(mayInviteSelf bool) {
+	t.MayInviteSelf = &mayInviteSelf
+}
+
+// HasMayInviteSelf reports whether the mayInviteSelf is set
+func (t *Task) HasMayInviteSelf() bool {
+	return t.MayInviteSelf != nil
+}
+
+// GetMayInviteOthers returns the mayInviteOthers, or false if it isn't set
+func (t *Task) GetMayInviteOthers() bool {
+	return deref(t.MayInviteOthers)
+}
+
+// SetMayInviteOthers sets the mayInviteOthers
+func (t *Task) SetMayInviteOthers(mayInviteOthers bool) {
+	t.MayInviteOthers = &mayInviteOthers
+}
+
+// HasMayInviteOthers reports whether the mayInviteOthers is set
+func (t *Task) HasMayInviteOthers() bool {
+	return t.MayInviteOthers != nil
+}
+
+// GetHideAttendees returns the hideAttendees, or false if it isn't set
+func (t *Task) GetHideAttendees() bool {
+	return deref(t.HideAttendees)
+}
+
+// SetHideAttendees sets the hideAttendees
+func (t *Task) SetHideAttendees(hideAttendees bool) {
+	t.HideAttendees = &hideAttendees
+}
+
+// HasHideAttendees reports whether the hideAttendees is set
+func (t *Task) HasHideAttendees() bool {
+	return t.HideAttendees != nil
+}
+
+// GetUseDefaultAlerts returns the useDefaultAlerts, or false if it isn't set
+func (t *Task) GetUseDefaultAlerts() bool {
+	return deref(t.UseDefaultAlerts)
+}
+
+// SetUseDefaultAlerts sets the useDefaultAlerts
+func (t *Task) SetUseDefaultAlerts(useDefaultAlerts bool) {
+	t.UseDefaultAlerts = &useDefaultAlerts
+}
+
+// HasUseDefaultAlerts reports whether the useDefaultAlerts is set
+func (t *Task) HasUseDefaultAlerts() bool {
+	return t.UseDefaultAlerts != nil
+}
+
+// GetStatus returns the status, or "" if it isn't set
+func (t *Task) GetStatus() string {
+	return deref(t.Status)
+}
+
+// SetStatus sets the status
+func (t *Task) SetStatus(status string) {
+	t.Status = &status
+}
+
+// HasStatus reports whether the status is set
+func (t *Task) HasStatus() bool {
+	return t.Status != nil
+}
+
+// Group accessors
+
+// GetSequence returns the sequence, or 0 if it isn't set
+func (g *Group) GetSequence() int {
+	return deref(g.Sequence)
+}
+
+// SetSequence sets the sequence
+func (g *Group) SetSequence(sequence int) {
+	g.Sequence = &sequence
+}
+
+// HasSequence reports whether the sequence is set
+func (g *Group) HasSequence() bool {
+	return g.Sequence != nil
+}
+
+// GetMethod returns the method, or "" if it isn't set
+func (g *Group) GetMethod() string {
+	return deref(g.Method)
+}
+
+// SetMethod sets the method
+func (g *Group) SetMethod(method string) {
+	g.Method = &method
+}
+
+// HasMethod reports whether the method is set
+func (g *Group) HasMethod() bool {
+	return g.Method != nil
+}
+
+// GetProdId returns the prodId, or "" if it isn't set
+func (g *Group) GetProdId() string {
+	return deref(g.ProdId)
+}
+
+// SetProdId sets the prodId
+func (g *Group) SetProdId(prodId string) {
+	g.ProdId = &prodId
+}
+
+// HasProdId reports whether the prodId is set
+func (g *Group) HasProdId() bool {
+	return g.ProdId != nil
+}
+
+// GetTitle returns the title, or "" if it isn't set
+func (g *Group) GetTitle() string {
+	return deref(g.Title)
+}
+
+// SetTitle sets the title
+func (g *Group) SetTitle(title string) {
+	g.Title = &title
+}
+
+// HasTitle reports whether the title is set
+func (g *Group) HasTitle() bool {
+	return g.Title != nil
+}
+
+// GetDescription returns the description, or "" if it isn't set
+func (g *Group) GetDescription() string {
+	return deref(g.Description)
+}
+
+// SetDescription sets the description
+func (g *Group) SetDescription(description string) {
+	g.Description = &description
+}
+
+// HasDescription reports whether the description is set
+func (g *Group) HasDescription() bool {
+	return g.Description != nil
+}
+
+// GetLocale returns the locale, or "" if it isn't set
+func (g *Group) GetLocale() string {
+	return deref(g.Locale)
+}
+
+// SetLocale sets the locale
+func (g *Group) SetLocale(locale string) {
+	g.Locale = &locale
+}
+
+// HasLocale reports whether the locale is set
+func (g *Group) HasLocale() bool {
+	return g.Locale != nil
+}
+
+// GetColor returns the color, or "" if it isn't set
+func (g *Group) GetColor() string {
+	return deref(g.Color)
+}
+
+// SetColor sets the color
+func (g *Group) SetColor(color string) {
+	g.Color = &color
+}
+
+// HasColor reports whether the color is set
+func (g *Group) HasColor() bool {
+	return g.Color != nil
+}
+
+// GetSource returns the source, or "" if it isn't set
+func (g *Group) GetSource() string {
+	return deref(g.Source)
+}
+
+// SetSource sets the source
+func (g *Group) SetSource(source string) {
+	g.Source = &source
+}
+
+// HasSource reports whether the source is set
+func (g *Group) HasSource() bool {
+	return g.Source != nil
+}
+
+// Participant accessors
+
+// GetName returns the name, or "" if it isn't set
+func (p *Participant) GetName() string {
+	return deref(p.Name)
+}
+
+// SetName sets the name
+func (p *Participant) SetName(name string) {
+	p.Name = &name
+}
+
+// HasName reports whether the name is set
+func (p *Participant) HasName() bool {
+	return p.Name != nil
+}
+
+// GetEmail returns the email, or "" if it isn't set
+func (p *Participant) GetEmail() string {
+	return deref(p.Email)
+}
+
+// SetEmail sets the email
+func (p *Participant) SetEmail(email string) {
+	p.Email = &email
+}
+
+// HasEmail reports whether the email is set
+func (p *Participant) HasEmail() bool {
+	return p.Email != nil
+}
+
+// GetKind returns the kind, or "" if it isn't set
+func (p *Participant) GetKind() string {
+	return deref(p.Kind)
+}
+
+// SetKind sets the kind
+func (p *Participant) SetKind(kind string) {
+	p.Kind = &kind
+}
+
+// HasKind reports whether the kind is set
+func (p *Participant) HasKind() bool {
+	return p.Kind != nil
+}
+
+// GetLocationId returns the locationId, or "" if it isn't set
+func (p *Participant) GetLocationId() string {
+	return deref(p.LocationId)
+}
+
+// SetLocationId sets the locationId
+func (p *Participant) SetLocationId(locationId string) {
+	p.LocationId = &locationId
+}
+
+// HasLocationId reports whether the locationId is set
+func (p *Participant) HasLocationId() bool {
+	return p.LocationId != nil
+}
+
+// GetLanguage returns the language, or "" if it isn't set
+func (p *Participant) GetLanguage() string {
+	return deref(p.Language)
+}
+
+// SetLanguage sets the language
+func (p *Participant) SetLanguage(language string) {
+	p.Language = &language
+}
+
+// HasLanguage reports whether the language is set
+func (p *Participant) HasLanguage() bool {
+	return p.Language != nil
+}
+
+// GetParticipationStatus returns the participationStatus, or "" if it isn't set
+func (p *Participant) GetParticipationStatus() string {
+	return deref(p.ParticipationStatus)
+}
+
+// SetParticipationStatus sets the participationStatus
+func (p *Participant) SetParticipationStatus(participationStatus string) {
+	p.ParticipationStatus = &participationStatus
+}
+
+// HasParticipationStatus reports whether the participationStatus is set
+func (p *Participant) HasParticipationStatus() bool {
+	return p.ParticipationStatus != nil
+}
+
+// GetParticipationComment returns the participationComment, or "" if it isn't set
+func (p *Participant) GetParticipationComment() string {
+	return deref(p.ParticipationComment)
+}
+
+// SetParticipationComment sets the participationComment
+func (p *Participant) SetParticipationComment(participationComment string) {
+	p.ParticipationComment = &participationComment
+}
+
+// HasParticipationComment reports whether the participationComment is set
+func (p *Participant) HasParticipationComment() bool {
+	return p.ParticipationComment != nil
+}
+
+// GetExpectReply returns the expectReply, or false if it isn't set
+func (p *Participant) GetExpectReply() bool {
+	return deref(p.ExpectReply)
+}
+
+// SetExpectReply sets the expectReply
+func (p *Participant) SetExpectReply(expectReply bool) {
+	p.ExpectReply = &expectReply
+}
+
+// HasExpectReply reports whether the expectReply is set
+func (p *Participant) HasExpectReply() bool {
+	return p.ExpectReply != nil
+}
+
+// GetScheduleAgent returns the scheduleAgent, or "" if it isn't set
+func (p *Participant) GetScheduleAgent() string {
+	return deref(p.ScheduleAgent)
+}
+
+// SetScheduleAgent sets the scheduleAgent
+func (p *Participant) SetScheduleAgent(scheduleAgent string) {
+	p.ScheduleAgent = &scheduleAgent
+}
+
+// HasScheduleAgent reports whether the scheduleAgent is set
+func (p *Participant) HasScheduleAgent() bool {
+	return p.ScheduleAgent != nil
+}
+
+// GetScheduleForceSend returns the scheduleForceSend, or false if it isn't set
+func (p *Participant) GetScheduleForceSend() bool {
+	return deref(p.ScheduleForceSend)
+}
+
+// SetScheduleForceSend sets the scheduleForceSend
+func (p *Participant) SetScheduleForceSend(scheduleForceSend bool) {
+	p.ScheduleForceSend = &scheduleForceSend
+}
+
+// HasScheduleForceSend reports whether the scheduleForceSend is set
+func (p *Participant) HasScheduleForceSend() bool {
+	return p.ScheduleForceSend != nil
+}
+
+// GetScheduleSequence returns the scheduleSequence, or 0 if it isn't set
+func (p *Participant) GetScheduleSequence() int {
+	return deref(p.ScheduleSequence)
+}
+
+// SetScheduleSequence sets the scheduleSequence
+func (p *Participant) SetScheduleSequence(scheduleSequence int) {
+	p.ScheduleSequence = &scheduleSequence
+}
+
+// HasScheduleSequence reports whether the scheduleSequence is set
+func (p *Participant) HasScheduleSequence() bool {
+	return p.ScheduleSequence != nil
+}
+
+// GetSentBy returns the sentBy, or "" if it isn't set
+func (p *Participant) GetSentBy() string {
+	return deref(p.SentBy)
+}
+
+// SetSentBy sets the sentBy
+func (p *Participant) SetSentBy(sentBy string) {
+	p.SentBy = &sentBy
+}
+
+// HasSentBy reports whether the sentBy is set
+func (p *Participant) HasSentBy() bool {
+	return p.SentBy != nil
+}
+
+// GetInvitedBy returns the invitedBy, or "" if it isn't set
+func (p *Participant) GetInvitedBy() string {
+	return deref(p.InvitedBy)
+}
+
+// SetInvitedBy sets the invitedBy
+func (p *Participant) SetInvitedBy(invitedBy string) {
+	p.InvitedBy = &invitedBy
+}
+
+// HasInvitedBy reports whether the invitedBy is set
+func (p *Participant) HasInvitedBy() bool {
+	return p.InvitedBy != nil
+}
+
+// Location accessors
+
+// GetName returns the name, or "" if it isn't set
+func (l *Location) GetName() string {
+	return deref(l.Name)
+}
+
+// SetName sets the name
+func (l *Location) SetName(name string) {
+	l.Name = &name
+}
+
+// HasName reports whether the name is set
+func (l *Location) HasName() bool {
+	return l.Name != nil
+}
+
+// GetDescription returns the description, or "" if it isn't set
+func (l *Location) GetDescription() string {
+	return deref(l.Description)
+}
+
+// SetDescription sets the description
+func (l *Location) SetDescription(description string) {
+	l.Description = &description
+}
+
+// HasDescription reports whether the description is set
+func (l *Location) HasDescription() bool {
+	return l.Description != nil
+}
+
+// GetRelativeTo returns the relativeTo, or "" if it isn't set
+func (l *Location) GetRelativeTo() string {
+	return deref(l.RelativeTo)
+}
+
+// SetRelativeTo sets the relativeTo
+func (l *Location) SetRelativeTo(relativeTo string) {
+	l.RelativeTo = &relativeTo
+}
+
+// HasRelativeTo reports whether the relativeTo is set
+func (l *Location) HasRelativeTo() bool {
+	return l.RelativeTo != nil
+}
+
+// GetTimeZone returns the timeZone, or "" if it isn't set
+func (l *Location) GetTimeZone() string {
+	return deref(l.TimeZone)
+}
+
+// SetTimeZone sets the timeZone
+func (l *Location) SetTimeZone(timeZone string) {
+	l.TimeZone = &timeZone
+}
+
+// HasTimeZone reports whether the timeZone is set
+func (l *Location) HasTimeZone() bool {
+	return l.TimeZone != nil
+}
+
+// GetCoordinates returns the coordinates, or "" if it isn't set
+func (l *Location) GetCoordinates() string {
+	return deref(l.Coordinates)
+}
+
+// HasCoordinates reports whether the coordinates is set
+func (l *Location) HasCoordinates() bool {
+	return l.Coordinates != nil
+}
+
+// GetRel returns the rel, or "" if it isn't set
+func (l *Location) GetRel() string {
+	return deref(l.Rel)
+}
+
+// SetRel sets the rel
+func (l *Location) SetRel(rel string) {
+	l.Rel = &rel
+}
+
+// HasRel reports whether the rel is set
+func (l *Location) HasRel() bool {
+	return l.Rel != nil
+}
+
+// GetTitle returns the title, or "" if it isn't set
+func (l *Location) GetTitle() string {
+	return deref(l.Title)
+}
+
+// SetTitle sets the title
+func (l *Location) SetTitle(title string) {
+	l.Title = &title
+}
+
+// HasTitle reports whether the title is set
+func (l *Location) HasTitle() bool {
+	return l.Title != nil
+}
+
+// Address accessors
+
+// GetStreet returns the street, or "" if it isn't set
+func (a *Address) GetStreet() string {
+	return deref(a.Street)
+}
+
+// SetStreet sets the street
+func (a *Address) SetStreet(street string) {
+	a.Street = &street
+}
+
+// HasStreet reports whether the street is set
+func (a *Address) HasStreet() bool {
+	return a.Street != nil
+}
+
+// GetLocality returns the locality, or "" if it isn't set
+func (a *Address) GetLocality() string {
+	return deref(a.Locality)
+}
+
+// SetLocality sets the locality
+func (a *Address) SetLocality(locality string) {
+	a.Locality = &locality
+}
+
+// HasLocality reports whether the locality is set
+func (a *Address) HasLocality() bool {
+	return a.Locality != nil
+}
+
+// GetRegion returns the region, or "" if it isn't set
+func (a *Address) GetRegion() string {
+	return deref(a.Region)
+}
+
+// SetRegion sets the region
+func (a *Address) SetRegion(region string) {
+	a.Region = &region
+}
+
+// HasRegion reports whether the region is set
+func (a *Address) HasRegion() bool {
+	return a.Region != nil
+}
+
+// GetPostcode returns the postcode, or "" if it isn't set
+func (a *Address) GetPostcode() string {
+	return deref(a.Postcode)
+}
+
+// SetPostcode sets the postcode
+func (a *Address) SetPostcode(postcode string) {
+	a.Postcode = &postcode
+}
+
+// HasPostcode reports whether the postcode is set
+func (a *Address) HasPostcode() bool {
+	return a.Postcode != nil
+}
+
+// GetCountry returns the country, or "" if it isn't set
+func (a *Address) GetCountry() string {
+	return deref(a.Country)
+}
+
+// SetCountry sets the country
+func (a *Address) SetCountry(country string) {
+	a.Country = &country
+}
+
+// HasCountry reports whether the country is set
+func (a *Address) HasCountry() bool {
+	return a.Country != nil
+}
+
+// GetCountryCode returns the countryCode, or "" if it isn't set
+func (a *Address) GetCountryCode() string {
+	return deref(a.CountryCode)
+}
+
+// SetCountryCode sets the countryCode
+func (a *Address) SetCountryCode(countryCode string) {
+	a.CountryCode = &countryCode
+}
+
+// HasCountryCode reports whether the countryCode is set
+func (a *Address) HasCountryCode() bool {
+	return a.CountryCode != nil
+}
+
+// GetFull returns the full, or "" if it isn't set
+func (a *Address) GetFull() string {
+	return deref(a.Full)
+}
+
+// SetFull sets the full
+func (a *Address) SetFull(full string) {
+	a.Full = &full
+}
+
+// HasFull reports whether the full is set
+func (a *Address) HasFull() bool {
+	return a.Full != nil
+}
+
+// VirtualLocation accessors
+
+// GetName returns the name, or "" if it isn't set
+func (v *VirtualLocation) GetName() string {
+	return deref(v.Name)
+}
+
+// SetName sets the name
+func (v *VirtualLocation) SetName(name string) {
+	v.Name = &name
+}
+
+// HasName reports whether the name is set
+func (v *VirtualLocation) HasName() bool {
+	return v.Name != nil
+}
+
+// GetDescription returns the description, or "" if it isn't set
+func (v *VirtualLocation) GetDescription() string {
+	return deref(v.Description)
+}
+
+// SetDescription sets the description
+func (v *VirtualLocation) SetDescription(description string) {
+	v.Description = &description
+}
+
+// HasDescription reports whether the description is set
+func (v *VirtualLocation) HasDescription() bool {
+	return v.Description != nil
+}
+
+// Link accessors
+
+// GetCid returns the cid, or "" if it isn't set
+func (l *Link) GetCid() string {
+	return deref(l.Cid)
+}
+
+// SetCid sets the cid
+func (l *Link) SetCid(cid string) {
+	l.Cid = &cid
+}
+
+// HasCid reports whether the cid is set
+func (l *Link) HasCid() bool {
+	return l.Cid != nil
+}
+
+// GetContentType returns the contentType, or "" if it isn't set
+func (l *Link) GetContentType() string {
+	return deref(l.ContentType)
+}
+
+// SetContentType sets the contentType
+func (l *Link) SetContentType(contentType string) {
+	l.ContentType = &contentType
+}
+
+// HasContentType reports whether the contentType is set
+func (l *Link) HasContentType() bool {
+	return l.ContentType != nil
+}
+
+// GetSize returns the size, or 0 if it isn't set
+func (l *Link) GetSize() int {
+	return deref(l.Size)
+}
+
+// SetSize sets the size
+func (l *Link) SetSize(size int) {
+	l.Size = &size
+}
+
+// HasSize reports whether the size is set
+func (l *Link) HasSize() bool {
+	return l.Size != nil
+}
+
+// GetRel returns the rel, or "" if it isn't set
+func (l *Link) GetRel() string {
+	return deref(l.Rel)
+}
+
+// SetRel sets the rel
+func (l *Link) SetRel(rel string) {
+	l.Rel = &rel
+}
+
+// HasRel reports whether the rel is set
+func (l *Link) HasRel() bool {
+	return l.Rel != nil
+}
+
+// GetDisplay returns the display, or "" if it isn't set
+func (l *Link) GetDisplay() string {
+	return deref(l.Display)
+}
+
+// SetDisplay sets the display
+func (l *Link) SetDisplay(display string) {
+	l.Display = &display
+}
+
+// HasDisplay reports whether the display is set
+func (l *Link) HasDisplay() bool {
+	return l.Display != nil
+}
+
+// GetTitle returns the title, or "" if it isn't set
+func (l *Link) GetTitle() string {
+	return deref(l.Title)
+}
+
+// SetTitle sets the title
+func (l *Link) SetTitle(title string) {
+	l.Title = &title
+}
+
+// HasTitle reports whether the title is set
+func (l *Link) HasTitle() bool {
+	return l.Title != nil
+}
+
+// Alert accessors
+
+// GetAction returns the action, or "" if it isn't set
+func (a *Alert) GetAction() string {
+	return deref(a.Action)
+}
+
+// SetAction sets the action
+func (a *Alert) SetAction(action string) {
+	a.Action = &action
+}
+
+// HasAction reports whether the action is set
+func (a *Alert) HasAction() bool {
+	return a.Action != nil
+}
+
+// RecurrenceRule accessors
+
+// GetInterval returns the interval, or 0 if it isn't set
+func (r *RecurrenceRule) GetInterval() int {
+	return deref(r.Interval)
+}
+
+// SetInterval sets the interval
+func (r *RecurrenceRule) SetInterval(interval int) {
+	r.Interval = &interval
+}
+
+// HasInterval reports whether the interval is set
+func (r *RecurrenceRule) HasInterval() bool {
+	return r.Interval != nil
+}
+
+// GetRScale returns the rscale, or "" if it isn't set
+func (r *RecurrenceRule) GetRScale() string {
+	return deref(r.RScale)
+}
+
+// SetRScale sets the rscale
+func (r *RecurrenceRule) SetRScale(rscale string) {
+	r.RScale = &rscale
+}
+
+// HasRScale reports whether the rscale is set
+func (r *RecurrenceRule) HasRScale() bool {
+	return r.RScale != nil
+}
+
+// GetSkip returns the skip, or "" if it isn't set
+func (r *RecurrenceRule) GetSkip() string {
+	return deref(r.Skip)
+}
+
+// SetSkip sets the skip
+func (r *RecurrenceRule) SetSkip(skip string) {
+	r.Skip = &skip
+}
+
+// HasSkip reports whether the skip is set
+func (r *RecurrenceRule) HasSkip() bool {
+	return r.Skip != nil
+}
+
+// GetFirstDayOfWeek returns the firstDayOfWeek, or 0 if it isn't set
+func (r *RecurrenceRule) GetFirstDayOfWeek() int {
+	return deref(r.FirstDayOfWeek)
+}
+
+// SetFirstDayOfWeek sets the firstDayOfWeek
+func (r *RecurrenceRule) SetFirstDayOfWeek(firstDayOfWeek int) {
+	r.FirstDayOfWeek = &firstDayOfWeek
+}
+
+// HasFirstDayOfWeek reports whether the firstDayOfWeek is set
+func (r *RecurrenceRule) HasFirstDayOfWeek() bool {
+	return r.FirstDayOfWeek != nil
+}
+
+// GetCount returns the count, or 0 if it isn't set
+func (r *RecurrenceRule) GetCount() int {
+	return deref(r.Count)
+}
+
+// SetCount sets the count
+func (r *RecurrenceRule) SetCount(count int) {
+	r.Count = &count
+}
+
+// HasCount reports whether the count is set
+func (r *RecurrenceRule) HasCount() bool {
+	return r.Count != nil
+}
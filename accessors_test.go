@@ -0,0 +1,52 @@
+package jscal
+
+import "testing"
+
+func TestEventAccessors(t *testing.T) {
+	event := &Event{Type: "Event", UID: "accessors"}
+	if event.GetTitle() != "" || event.HasTitle() {
+		t.Errorf("Expected no title, got %q", event.GetTitle())
+	}
+	if event.GetPriority() != 0 || event.HasPriority() {
+		t.Errorf("Expected no priority, got %d", event.GetPriority())
+	}
+	if event.GetShowWithoutTime() {
+		t.Error("Expected showWithoutTime to default to false")
+	}
+
+	event.SetTitle("Planning")
+	event.SetPriority(0)
+	event.SetShowWithoutTime(true)
+	if event.GetTitle() != "Planning" || *event.Title != "Planning" {
+		t.Errorf("Expected title Planning, got %q", event.GetTitle())
+	}
+	if !event.HasPriority() || event.GetPriority() != 0 {
+		t.Error("Expected priority 0 to be set")
+	}
+	if !event.GetShowWithoutTime() || !event.IsAllDay() {
+		t.Error("Expected an all-day event")
+	}
+}
+
+func TestSubObjectAccessors(t *testing.T) {
+	p := &Participant{}
+	p.SetEmail("jd@example.com")
+	p.SetExpectReply(true)
+	if p.GetEmail() != "jd@example.com" || !p.GetExpectReply() || p.HasName() {
+		t.Errorf("Unexpected participant %+v", p)
+	}
+
+	rule := NewRecurrenceRule("weekly")
+	if rule.GetInterval() != 0 || rule.HasCount() {
+		t.Errorf("Expected no interval or count, got %+v", rule)
+	}
+	rule.SetCount(3)
+	if rule.GetCount() != 3 {
+		t.Errorf("Expected count 3, got %d", rule.GetCount())
+	}
+
+	task := NewTask("accessors", "Task")
+	if task.GetProgress() != ProgressNeedsAction || task.HasPercentComplete() {
+		t.Errorf("Expected needs-action without percentComplete, got %+v", task)
+	}
+}
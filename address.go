@@ -0,0 +1,196 @@
+package jscal
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+var addressType = reflect.TypeOf(Address{})
+
+// countryCodePattern matches an ISO 3166-1 alpha-2 country code
+var countryCodePattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// Address is the postal address of a Location, in its "address" property.
+// RFC 8984 only has a free-text name and description for locations; the
+// members follow the JSContact Address (RFC 9553 Section 2.5.1), with
+// Full for an address whose parts aren't known. Unknown members are kept
+// in Extensions.
+type Address struct {
+	Type        string  `json:"@type"`                 // Always "Address"
+	Street      *string `json:"street,omitempty"`      // Street and number, one line each
+	Locality    *string `json:"locality,omitempty"`    // City or town
+	Region      *string `json:"region,omitempty"`      // State or province
+	Postcode    *string `json:"postcode,omitempty"`    // Postal code
+	Country     *string `json:"country,omitempty"`     // Country name
+	CountryCode *string `json:"countryCode,omitempty"` // ISO 3166-1 alpha-2 code
+	Full        *string `json:"full,omitempty"`        // Free-text address
+
+	// Free-form properties for extensions
+	Extensions map[string]interface{} `json:"-"`
+}
+
+// NewAddress creates an address from its most common parts, leaving out
+// those that are empty
+func NewAddress(street, locality, postcode, countryCode string) *Address {
+	a := &Address{Type: "Address"}
+	for _, part := range []struct {
+		value string
+		dst   **string
+	}{{street, &a.Street}, {locality, &a.Locality}, {postcode, &a.Postcode}, {countryCode, &a.CountryCode}} {
+		if part.value != "" {
+			*part.dst = String(part.value)
+		}
+	}
+	return a
+}
+
+// postcodeFirst lists the countries writing the postcode before the
+// locality, as in "1010 Wien"
+var postcodeFirst = map[string]bool{
+	"AT": true, "BE": true, "CH": true, "CZ": true, "DE": true, "DK": true,
+	"ES": true, "FI": true, "FR": true, "IT": true, "LU": true, "NL": true,
+	"NO": true, "PL": true, "PT": true, "SE": true,
+}
+
+// Lines returns the address as lines for display: the street, the
+// locality with region and postcode in the order of the country, and the
+// country. An address with no parts but Full returns its lines.
+func (a *Address) Lines() []string {
+	if a == nil {
+		return nil
+	}
+	var lines []string
+	add := func(line string) {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	for _, line := range strings.Split(deref(a.Street), "\n") {
+		add(line)
+	}
+	locality, region, postcode := deref(a.Locality), deref(a.Region), deref(a.Postcode)
+	if postcodeFirst[deref(a.CountryCode)] {
+		add(postcode + " " + locality)
+		add(region)
+	} else {
+		place := locality
+		if locality != "" && (region != "" || postcode != "") {
+			place += ","
+		}
+		add(strings.Join([]string{place, region, postcode}, " "))
+	}
+	if country := deref(a.Country); country != "" {
+		add(country)
+	} else {
+		add(deref(a.CountryCode))
+	}
+
+	if len(lines) == 0 {
+		for _, line := range strings.Split(deref(a.Full), "\n") {
+			add(line)
+		}
+	}
+	return lines
+}
+
+// String returns the address on one line, its Lines separated by commas
+func (a *Address) String() string {
+	return strings.Join(a.Lines(), ", ")
+}
+
+// IsEmpty reports whether the address has neither parts nor Full
+func (a *Address) IsEmpty() bool {
+	return len(a.Lines()) == 0
+}
+
+// MarshalJSON implements json.Marshaler, writing Extensions as members
+func (a Address) MarshalJSON() ([]byte, error) {
+	return a.appendJSON(make([]byte, 0, 128))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, capturing unknown members into
+// Extensions
+func (a *Address) UnmarshalJSON(data []byte) error {
+	*a = Address{}
+	if json.Valid(data) {
+		var extensions map[string]interface{}
+		if err := decodeObject(data, addressType, a.decodeMember, &extensions); err == nil {
+			a.Extensions = extensions
+			return nil
+		}
+	}
+
+	// Decode again with encoding/json, which reports errors with context
+	*a = Address{}
+	type Alias Address
+	if err := json.Unmarshal(data, (*Alias)(a)); err != nil {
+		return err
+	}
+	extensions, err := extractExtensions(data, reflect.TypeOf(Alias{}))
+	if err != nil {
+		return err
+	}
+	a.Extensions = extensions
+	return nil
+}
+
+// appendJSON appends the Address as JSON
+func (a *Address) appendJSON(buf []byte) ([]byte, error) {
+	o := objectEncoder{buf: buf}
+	o.begin()
+	o.str("@type", a.Type)
+	o.stringPtr("street", a.Street)
+	o.stringPtr("locality", a.Locality)
+	o.stringPtr("region", a.Region)
+	o.stringPtr("postcode", a.Postcode)
+	o.stringPtr("country", a.Country)
+	o.stringPtr("countryCode", a.CountryCode)
+	o.stringPtr("full", a.Full)
+	o.extensions(a.Extensions, addressType)
+	return o.end()
+}
+
+// decodeMember decodes a declared property of the Address
+func (a *Address) decodeMember(key string, value []byte) (bool, error) {
+	switch key {
+	case "@type":
+		return true, decodeString(value, &a.Type)
+	case "street":
+		return true, decodeStringPtr(value, &a.Street)
+	case "locality":
+		return true, decodeStringPtr(value, &a.Locality)
+	case "region":
+		return true, decodeStringPtr(value, &a.Region)
+	case "postcode":
+		return true, decodeStringPtr(value, &a.Postcode)
+	case "country":
+		return true, decodeStringPtr(value, &a.Country)
+	case "countryCode":
+		return true, decodeStringPtr(value, &a.CountryCode)
+	case "full":
+		return true, decodeStringPtr(value, &a.Full)
+	}
+	return false, nil
+}
+
+// validateAddress validates the address of a location
+func validateAddress(field string, a *Address) ValidationErrors {
+	if a == nil {
+		return nil
+	}
+	var errors ValidationErrors
+	if a.Type != "Address" {
+		errors = append(errors, ValidationError{Field: field + ".@type", Value: a.Type, Message: "must be 'Address'"})
+	}
+	if a.CountryCode != nil && !countryCodePattern.MatchString(*a.CountryCode) {
+		errors = append(errors, ValidationError{
+			Field:   field + ".countryCode",
+			Value:   *a.CountryCode,
+			Message: "must be an ISO 3166-1 alpha-2 code such as AT",
+		})
+	}
+	return errors
+}
@@ -0,0 +1,128 @@
+package jscal
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestAddressLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		address  *Address
+		expected string
+	}{
+		{
+			name: "postcode after region",
+			address: &Address{Type: "Address", Street: String("1600 Amphitheatre Pkwy"), Locality: String("Mountain View"),
+				Region: String("CA"), Postcode: String("94043"), CountryCode: String("US"), Country: String("USA")},
+			expected: "1600 Amphitheatre Pkwy, Mountain View, CA 94043, USA",
+		},
+		{
+			name:     "postcode first",
+			address:  NewAddress("Stephansplatz 1", "Wien", "1010", "AT"),
+			expected: "Stephansplatz 1, 1010 Wien, AT",
+		},
+		{
+			name:     "multi-line street",
+			address:  &Address{Type: "Address", Street: String("Building 4\nFloor 2"), Locality: String("Springfield")},
+			expected: "Building 4, Floor 2, Springfield",
+		},
+		{
+			name:     "only full",
+			address:  &Address{Type: "Address", Full: String("Main Square\nOld Town")},
+			expected: "Main Square, Old Town",
+		},
+		{
+			name:     "empty",
+			address:  &Address{Type: "Address"},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.address.String(); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+			if tt.address.IsEmpty() != (tt.expected == "") {
+				t.Errorf("Expected IsEmpty %v", tt.expected == "")
+			}
+		})
+	}
+}
+
+func TestAddressJSON(t *testing.T) {
+	data := []byte(`{"@type": "Event", "uid": "u", "start": "2025-03-01T09:00:00",
+		"locations": {"hq": {"@type": "Location", "name": "HQ",
+			"address": {"@type": "Address", "street": "Stephansplatz 1", "locality": "Wien", "postcode": "1010",
+				"countryCode": "AT", "example.com:floor": 3}}}}`)
+	event, err := ParseEvent(data)
+	if err != nil {
+		t.Fatalf("ParseEvent failed: %v", err)
+	}
+	address := event.Locations["hq"].Address
+	if address == nil || address.GetLocality() != "Wien" || address.Extensions["example.com:floor"] != 3.0 {
+		t.Fatalf("Expected the address to be decoded, got %+v", address)
+	}
+
+	out, err := event.JSON()
+	if err != nil {
+		t.Fatalf("JSON failed: %v", err)
+	}
+	if !strings.Contains(string(out), `"example.com:floor":3`) {
+		t.Errorf("Expected the extension to be kept, got %s", out)
+	}
+	if err := ValidateAgainstSchema(out); err != nil {
+		t.Errorf("Expected the event to match the schema, got %v", err)
+	}
+	if clone := event.Clone(); clone.Locations["hq"].Address.String() != address.String() {
+		t.Errorf("Expected Clone to copy the address")
+	}
+
+	var bad Address
+	if err := json.Unmarshal([]byte(`{"@type": "Address", "street": 12}`), &bad); err == nil {
+		t.Error("Expected an error for a non-string street")
+	}
+}
+
+func TestValidateAddress(t *testing.T) {
+	event := NewEvent("address", "Visit")
+	location := NewLocation("Office")
+	location.Address = NewAddress("Main St 1", "Springfield", "", "usa")
+	location.Address.Type = ""
+	event.AddLocation("office", location)
+
+	errs, ok := event.Validate().(ValidationErrors)
+	if !ok {
+		t.Fatalf("Expected ValidationErrors, got %v", event.Validate())
+	}
+	fields := make(map[string]bool)
+	for _, err := range errs {
+		fields[err.Field] = true
+	}
+	for _, want := range []string{"locations[office].address.@type", "locations[office].address.countryCode"} {
+		if !fields[want] {
+			t.Errorf("Expected an error for %s, got %v", want, errs)
+		}
+	}
+}
+
+func TestAnonymizeAddress(t *testing.T) {
+	event := NewEvent("address", "Visit")
+	location := NewLocation("Office")
+	location.Address = NewAddress("Main St 1", "Springfield", "12345", "US")
+	event.AddLocation("office", location)
+
+	anonymized, err := Anonymize(event, AnonymizeOptions{Key: []byte("secret")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := anonymized.Locations["office"].Address
+	if strings.Contains(address.String(), "Main") || strings.Contains(address.String(), "12345") {
+		t.Errorf("Expected the address to be anonymized, got %s", address)
+	}
+	if address.GetCountryCode() != "US" || address.Type != "Address" {
+		t.Errorf("Expected the country to be kept, got %+v", address)
+	}
+}
@@ -0,0 +1,418 @@
+// Package alerts computes when the alerts of JSCalendar events and tasks
+// fire (RFC 8984 Section 4.5), so reminder daemons don't need to reimplement
+// the trigger math.
+//
+// Basic usage:
+//
+//	schedule, err := alerts.Schedule(event, jscal.Now(), alerts.Options{})
+//	for _, f := range schedule {
+//		fmt.Println(f.Time, f.UID, f.AlertID)
+//	}
+//
+// Recurring events and tasks are expanded: each alert is scheduled for the
+// first occurrence at which it fires at or after now, with the occurrence's
+// recurrenceOverrides patch applied, so a patched or acknowledged alert is
+// scheduled as the occurrence has it.
+package alerts
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/airtrafik/jscal"
+)
+
+// Firing is a single scheduled alert
+type Firing struct {
+	UID     string       // UID of the event or task
+	AlertID string       // Key of the alert in Alerts, or in the default alerts
+	Alert   *jscal.Alert // The alert itself
+	Time    time.Time    // When the alert fires
+	Default bool         // True if the alert is one of the default alerts
+}
+
+// Options configures Schedule
+type Options struct {
+	// Defaults supplies the alerts of objects with useDefaultAlerts set, as
+	// RFC 8984 asks clients to use their own defaults
+	Defaults jscal.DefaultAlertsProvider
+
+	// DefaultAlerts are the same defaults for every object, used if
+	// Defaults is nil
+	DefaultAlerts map[string]*jscal.Alert
+
+	// Location resolves floating times (no timeZone). Defaults to time.Local.
+	Location *time.Location
+
+	// IncludeMissed also returns alerts that fired before now but were
+	// never acknowledged. For recurring objects, that is the last firing of
+	// each alert within the year before now.
+	IncludeMissed bool
+}
+
+// searchYears is how far ahead of now the occurrences of recurring objects
+// are searched for firings
+const searchYears = 100
+
+// defaults returns the configured default alerts
+func (o Options) defaults() jscal.DefaultAlertsProvider {
+	if o.Defaults != nil {
+		return o.Defaults
+	}
+	return jscal.StaticDefaultAlerts(o.DefaultAlerts)
+}
+
+// location returns the configured location for floating times
+func (o Options) location() *time.Location {
+	if o.Location != nil {
+		return o.Location
+	}
+	return time.Local
+}
+
+// Schedule returns the alerts of an Event, a Task, or the entries of a Group
+// that fire at or after now, sorted by firing time. Alerts acknowledged at
+// or after their firing time are done and left out.
+func Schedule(obj jscal.CalendarObject, now time.Time, opts Options) ([]Firing, error) {
+	var firings []Firing
+	switch o := obj.(type) {
+	case *jscal.Event:
+		f, err := scheduleEvent(o, now, opts)
+		if err != nil {
+			return nil, err
+		}
+		firings = f
+	case *jscal.Task:
+		f, err := scheduleTask(o, now, opts)
+		if err != nil {
+			return nil, err
+		}
+		firings = f
+	case *jscal.Group:
+		if o == nil {
+			return nil, nil
+		}
+		for _, entry := range o.Entries {
+			f, err := Schedule(entry, now, opts)
+			if err != nil {
+				return nil, err
+			}
+			firings = append(firings, f...)
+		}
+	case nil:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unsupported object type %T", obj)
+	}
+
+	sortFirings(firings)
+	return firings, nil
+}
+
+// Next returns the earliest alert of obj that fires at or after now
+func Next(obj jscal.CalendarObject, now time.Time, opts Options) (Firing, bool, error) {
+	opts.IncludeMissed = false
+	firings, err := Schedule(obj, now, opts)
+	if err != nil || len(firings) == 0 {
+		return Firing{}, false, err
+	}
+	return firings[0], true, nil
+}
+
+// scheduleEvent schedules the alerts of an Event. The end of an event is
+// its start plus its duration.
+func scheduleEvent(e *jscal.Event, now time.Time, opts Options) ([]Firing, error) {
+	if e == nil {
+		return nil, nil
+	}
+	if e.Start == nil {
+		return nil, fmt.Errorf("event %s: start is required", e.UID)
+	}
+
+	loc, err := resolveLocation(e.TimeZone, opts)
+	if err != nil {
+		return nil, fmt.Errorf("event %s: %w", e.UID, err)
+	}
+	if len(e.RecurrenceRules) == 0 && len(e.RecurrenceOverrides) == 0 {
+		return eventFirings(e, loc, now, opts)
+	}
+
+	set := e.EffectiveAlerts(opts.defaults())
+	if len(set) == 0 {
+		return nil, nil
+	}
+	var length time.Duration
+	if e.Duration != nil {
+		if length, err = jscal.ParseDuration(*e.Duration); err != nil {
+			return nil, fmt.Errorf("event %s: invalid duration: %w", e.UID, err)
+		}
+	}
+
+	c := newCollector(set, now, opts)
+	from := jscal.NewLocalDateTime(c.searchFrom(set, length).In(loc))
+
+	// Search a year ahead, doubling the window, so that infinite rules end
+	for years := 1; !c.done(); years *= 2 {
+		to := jscal.NewLocalDateTime(now.In(loc).AddDate(years, 0, 0))
+		occurrences, err := e.Occurrences(*from, *to)
+		if err != nil {
+			return nil, err
+		}
+		for _, occurrence := range occurrences {
+			firings, err := eventFirings(occurrence.Event, loc, now, Options{Defaults: opts.defaults(), IncludeMissed: true})
+			if err != nil {
+				return nil, err
+			}
+			c.add(firings)
+		}
+		if years >= searchYears {
+			break
+		}
+		from = to
+	}
+	return c.firings(), nil
+}
+
+// eventFirings schedules the alerts of a single instance of an event
+func eventFirings(e *jscal.Event, loc *time.Location, now time.Time, opts Options) ([]Firing, error) {
+	start := e.Start.In(loc)
+	end := start
+	if e.Duration != nil {
+		duration, err := jscal.ParseDuration(*e.Duration)
+		if err != nil {
+			return nil, fmt.Errorf("event %s: invalid duration: %w", e.UID, err)
+		}
+		end = start.Add(duration)
+	}
+
+	return scheduleAlerts(e.UID, e.EffectiveAlerts(opts.defaults()), e.GetUseDefaultAlerts(), &start, &end, now, opts)
+}
+
+// scheduleTask schedules the alerts of a Task. The end of a task is its due
+// time; alerts relative to a missing start or due are skipped. Only open
+// occurrences of recurring tasks are scheduled.
+func scheduleTask(t *jscal.Task, now time.Time, opts Options) ([]Firing, error) {
+	if t == nil {
+		return nil, nil
+	}
+
+	loc, err := resolveLocation(t.TimeZone, opts)
+	if err != nil {
+		return nil, fmt.Errorf("task %s: %w", t.UID, err)
+	}
+	if len(t.RecurrenceRules) == 0 {
+		return taskFirings(t, loc, now, opts)
+	}
+
+	set := t.EffectiveAlerts(opts.defaults())
+	if len(set) == 0 {
+		return nil, nil
+	}
+	var length time.Duration
+	if t.Start != nil && t.Due != nil {
+		length = t.Due.Sub(*t.Start)
+	}
+
+	c := newCollector(set, now, opts)
+	after := jscal.NewLocalDateTime(c.searchFrom(set, length).In(loc).Add(-time.Nanosecond))
+	limit := now.In(loc).AddDate(searchYears, 0, 0)
+	for !c.done() {
+		instance, err := t.NextOccurrence(*after)
+		if err != nil {
+			return nil, err
+		}
+		if instance == nil || instance.RecurrenceId == nil || instance.RecurrenceId.In(loc).After(limit) {
+			break
+		}
+		firings, err := taskFirings(instance, loc, now, Options{Defaults: opts.defaults(), IncludeMissed: true})
+		if err != nil {
+			return nil, err
+		}
+		c.add(firings)
+		after = instance.RecurrenceId
+	}
+	return c.firings(), nil
+}
+
+// taskFirings schedules the alerts of a single instance of a task
+func taskFirings(t *jscal.Task, loc *time.Location, now time.Time, opts Options) ([]Firing, error) {
+	var start, due *time.Time
+	if t.Start != nil {
+		s := t.Start.In(loc)
+		start = &s
+	}
+	if t.Due != nil {
+		d := t.Due.In(loc)
+		due = &d
+	}
+
+	return scheduleAlerts(t.UID, t.EffectiveAlerts(opts.defaults()), t.GetUseDefaultAlerts(), start, due, now, opts)
+}
+
+// collector gathers the firings of the occurrences of a recurring object,
+// keeping the first firing of each alert at or after now and, if missed
+// alerts are wanted, the last one before it
+type collector struct {
+	now           time.Time
+	includeMissed bool
+	want          int
+	next          map[string]Firing
+	missed        map[string]Firing
+}
+
+// newCollector returns a collector for the alerts in set
+func newCollector(set map[string]*jscal.Alert, now time.Time, opts Options) *collector {
+	return &collector{
+		now:           now,
+		includeMissed: opts.IncludeMissed,
+		want:          len(set),
+		next:          make(map[string]Firing),
+		missed:        make(map[string]Firing),
+	}
+}
+
+// searchFrom returns the time from which occurrences must be searched: an
+// occurrence starting before now fires after it for alerts with a positive
+// offset, relative to the end for objects of the given length. Missed
+// alerts are looked for a year further back.
+func (c *collector) searchFrom(set map[string]*jscal.Alert, length time.Duration) time.Time {
+	var lookback time.Duration
+	for _, alert := range set {
+		if alert == nil {
+			continue
+		}
+		trigger, ok := alert.Trigger.(*jscal.OffsetTrigger)
+		if !ok {
+			continue
+		}
+		offset, err := jscal.ParseDuration(trigger.Offset)
+		if err != nil {
+			continue
+		}
+		if trigger.RelativeTo != nil && *trigger.RelativeTo == jscal.RelativeToEnd {
+			offset += length
+		}
+		if offset > lookback {
+			lookback = offset
+		}
+	}
+	from := c.now.Add(-lookback)
+	if c.includeMissed {
+		from = from.AddDate(-1, 0, 0)
+	}
+	return from
+}
+
+// add records the firings of an occurrence
+func (c *collector) add(firings []Firing) {
+	for _, f := range firings {
+		if f.Time.Before(c.now) {
+			if last, ok := c.missed[f.AlertID]; c.includeMissed && (!ok || f.Time.After(last.Time)) {
+				c.missed[f.AlertID] = f
+			}
+			continue
+		}
+		if first, ok := c.next[f.AlertID]; !ok || f.Time.Before(first.Time) {
+			c.next[f.AlertID] = f
+		}
+	}
+}
+
+// done reports whether every alert has a firing at or after now
+func (c *collector) done() bool {
+	return len(c.next) >= c.want
+}
+
+// firings returns the collected firings
+func (c *collector) firings() []Firing {
+	firings := make([]Firing, 0, len(c.next)+len(c.missed))
+	for _, f := range c.missed {
+		firings = append(firings, f)
+	}
+	for _, f := range c.next {
+		firings = append(firings, f)
+	}
+	return firings
+}
+
+// scheduleAlerts computes the firing times of a set of alerts
+func scheduleAlerts(uid string, set map[string]*jscal.Alert, isDefault bool,
+	start, end *time.Time, now time.Time, opts Options) ([]Firing, error) {
+	var firings []Firing
+	for id, alert := range set {
+		if alert == nil || alert.Trigger == nil {
+			continue
+		}
+
+		at, ok, err := fireTime(alert.Trigger, start, end)
+		if err != nil {
+			return nil, fmt.Errorf("%s: alert %s: %w", uid, id, err)
+		}
+		if !ok {
+			continue
+		}
+		if alert.Acknowledged != nil && !alert.Acknowledged.Time().Before(at) {
+			continue
+		}
+		if at.Before(now) && !opts.IncludeMissed {
+			continue
+		}
+
+		firings = append(firings, Firing{UID: uid, AlertID: id, Alert: alert, Time: at, Default: isDefault})
+	}
+	return firings, nil
+}
+
+// fireTime returns when a trigger fires. ok is false for unknown triggers,
+// which RFC 8984 says to ignore, and for offsets relative to a time the
+// object doesn't have.
+func fireTime(trigger jscal.Trigger, start, end *time.Time) (at time.Time, ok bool, err error) {
+	switch t := trigger.(type) {
+	case *jscal.AbsoluteTrigger:
+		return t.When.Time(), true, nil
+	case *jscal.OffsetTrigger:
+		offset, err := jscal.ParseDuration(t.Offset)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid offset: %w", err)
+		}
+
+		base := start
+		if t.RelativeTo != nil && *t.RelativeTo == jscal.RelativeToEnd {
+			base = end
+		}
+		if base == nil {
+			return time.Time{}, false, nil
+		}
+		return base.Add(offset), true, nil
+	default:
+		return time.Time{}, false, nil
+	}
+}
+
+// resolveLocation returns the location of an IANA time zone, or the
+// configured location for floating times
+func resolveLocation(timeZone *string, opts Options) (*time.Location, error) {
+	if timeZone == nil || *timeZone == "" {
+		return opts.location(), nil
+	}
+	loc, err := time.LoadLocation(*timeZone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %s: %w", *timeZone, err)
+	}
+	return loc, nil
+}
+
+// sortFirings orders firings by time, then UID and alert ID for stable output
+func sortFirings(firings []Firing) {
+	sort.Slice(firings, func(i, j int) bool {
+		a, b := firings[i], firings[j]
+		if !a.Time.Equal(b.Time) {
+			return a.Time.Before(b.Time)
+		}
+		if a.UID != b.UID {
+			return a.UID < b.UID
+		}
+		return a.AlertID < b.AlertID
+	})
+}
@@ -0,0 +1,308 @@
+package alerts
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/airtrafik/jscal"
+)
+
+func offsetAlert(offset string, relativeTo *string) *jscal.Alert {
+	return &jscal.Alert{
+		Type:    "Alert",
+		Trigger: &jscal.OffsetTrigger{Type: "OffsetTrigger", Offset: offset, RelativeTo: relativeTo},
+	}
+}
+
+func loadBerlin(t *testing.T) *time.Location {
+	t.Helper()
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	return berlin
+}
+
+func TestScheduleEvent(t *testing.T) {
+	berlin := loadBerlin(t)
+	event := jscal.NewEvent("alert-event", "Dentist")
+	event.Start = jscal.NewLocalDateTime(time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC))
+	event.TimeZone = jscal.String("Europe/Berlin")
+	event.Duration = jscal.String("PT1H")
+	event.AddAlert("day-before", offsetAlert("-P1D", nil))
+	event.AddAlert("15-min", offsetAlert("-PT15M", jscal.String(jscal.RelativeToStart)))
+	event.AddAlert("after-end", offsetAlert("PT5M", jscal.String(jscal.RelativeToEnd)))
+
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	schedule, err := Schedule(event, now, Options{})
+	if err != nil {
+		t.Fatalf("Schedule failed: %v", err)
+	}
+
+	want := []struct {
+		id string
+		at time.Time
+	}{
+		{"day-before", time.Date(2025, 3, 9, 9, 0, 0, 0, berlin)},
+		{"15-min", time.Date(2025, 3, 10, 8, 45, 0, 0, berlin)},
+		{"after-end", time.Date(2025, 3, 10, 10, 5, 0, 0, berlin)},
+	}
+	if len(schedule) != len(want) {
+		t.Fatalf("Expected %d firings, got %+v", len(want), schedule)
+	}
+	for i, w := range want {
+		if schedule[i].AlertID != w.id || !schedule[i].Time.Equal(w.at) {
+			t.Errorf("Firing %d: expected %s at %s, got %s at %s", i, w.id, w.at, schedule[i].AlertID, schedule[i].Time)
+		}
+		if schedule[i].UID != "alert-event" || schedule[i].Default {
+			t.Errorf("Unexpected firing %+v", schedule[i])
+		}
+	}
+
+	next, ok, err := Next(event, time.Date(2025, 3, 9, 12, 0, 0, 0, berlin), Options{})
+	if err != nil || !ok || next.AlertID != "15-min" {
+		t.Errorf("Expected next alert 15-min, got %+v (ok=%v, err=%v)", next, ok, err)
+	}
+}
+
+func TestScheduleRecurringEvent(t *testing.T) {
+	berlin := loadBerlin(t)
+	event := jscal.NewEvent("alert-event", "Dentist")
+	event.Start = jscal.NewLocalDateTime(time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC))
+	event.TimeZone = jscal.String("Europe/Berlin")
+	event.Duration = jscal.String("PT1H")
+	event.Start = jscal.NewLocalDateTime(time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC))
+	event.RecurrenceRules = []jscal.RecurrenceRule{*jscal.NewRecurrenceRule(jscal.FrequencyWeekly)}
+	event.AddAlert("15-min", offsetAlert("-PT15M", nil))
+	event.RecurrenceOverrides = map[string]map[string]interface{}{
+		"2025-03-17T09:00:00": {"alerts/15-min/trigger/offset": "-PT1H"},
+		"2025-03-24T09:00:00": {"excluded": true},
+	}
+
+	tests := []struct {
+		name          string
+		now           time.Time
+		includeMissed bool
+		want          []time.Time
+	}{
+		{"before the series", time.Date(2025, 2, 1, 0, 0, 0, 0, berlin), false,
+			[]time.Time{time.Date(2025, 3, 3, 8, 45, 0, 0, berlin)}},
+		{"patched occurrence", time.Date(2025, 3, 12, 0, 0, 0, 0, berlin), false,
+			[]time.Time{time.Date(2025, 3, 17, 8, 0, 0, 0, berlin)}},
+		{"past an excluded occurrence", time.Date(2025, 3, 18, 0, 0, 0, 0, berlin), false,
+			[]time.Time{time.Date(2025, 3, 31, 8, 45, 0, 0, berlin)}},
+		{"missed", time.Date(2025, 3, 18, 0, 0, 0, 0, berlin), true,
+			[]time.Time{time.Date(2025, 3, 17, 8, 0, 0, 0, berlin), time.Date(2025, 3, 31, 8, 45, 0, 0, berlin)}},
+		{"years later", time.Date(2030, 1, 1, 0, 0, 0, 0, berlin), false,
+			[]time.Time{time.Date(2030, 1, 7, 8, 45, 0, 0, berlin)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := Schedule(event, tt.now, Options{IncludeMissed: tt.includeMissed})
+			if err != nil {
+				t.Fatalf("Schedule failed: %v", err)
+			}
+			if len(schedule) != len(tt.want) {
+				t.Fatalf("Expected %d firings, got %+v", len(tt.want), schedule)
+			}
+			for i, at := range tt.want {
+				if schedule[i].AlertID != "15-min" || !schedule[i].Time.Equal(at) {
+					t.Errorf("Firing %d: expected 15-min at %s, got %s at %s", i, at, schedule[i].AlertID, schedule[i].Time)
+				}
+			}
+		})
+	}
+
+	// An alert acknowledged at an occurrence is done for the earlier ones
+	event.Alerts["15-min"].Acknowledged = jscal.NewUTCDateTime(time.Date(2025, 3, 31, 8, 50, 0, 0, berlin))
+	next, ok, err := Next(event, time.Date(2025, 3, 18, 0, 0, 0, 0, berlin), Options{})
+	if err != nil || !ok || !next.Time.Equal(time.Date(2025, 4, 7, 8, 45, 0, 0, berlin)) {
+		t.Errorf("Expected the next firing on 2025-04-07, got %+v (ok=%v, err=%v)", next, ok, err)
+	}
+}
+
+func TestScheduleRecurringTask(t *testing.T) {
+	task := jscal.NewTask("recurring-task", "Water plants")
+	task.Due = jscal.NewLocalDateTime(time.Date(2025, 3, 3, 18, 0, 0, 0, time.UTC))
+	task.RecurrenceRules = []jscal.RecurrenceRule{*jscal.NewRecurrenceRule(jscal.FrequencyWeekly)}
+	task.AddAlert("due", offsetAlert("-PT1H", jscal.String(jscal.RelativeToEnd)))
+	task.RecurrenceOverrides = map[string]map[string]interface{}{
+		"2025-03-10T18:00:00": {"progress": jscal.ProgressCompleted},
+	}
+
+	schedule, err := Schedule(task, time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC), Options{Location: time.UTC})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2025, 3, 17, 17, 0, 0, 0, time.UTC)
+	if len(schedule) != 1 || !schedule[0].Time.Equal(want) {
+		t.Errorf("Expected the alert of the next open occurrence at %s, got %+v", want, schedule)
+	}
+}
+
+func TestScheduleAcknowledgedAndMissed(t *testing.T) {
+	berlin := loadBerlin(t)
+	event := jscal.NewEvent("alert-event", "Dentist")
+	event.Start = jscal.NewLocalDateTime(time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC))
+	event.TimeZone = jscal.String("Europe/Berlin")
+	event.Duration = jscal.String("PT1H")
+	acked := offsetAlert("-PT30M", nil)
+	acked.Acknowledged = jscal.NewUTCDateTime(time.Date(2025, 3, 10, 8, 31, 0, 0, berlin))
+	event.AddAlert("acked", acked)
+	snoozedEarlier := offsetAlert("-PT10M", nil)
+	snoozedEarlier.Acknowledged = jscal.NewUTCDateTime(time.Date(2025, 3, 10, 8, 0, 0, 0, berlin))
+	event.AddAlert("ack-before-fire", snoozedEarlier)
+	event.AddAlert("missed", offsetAlert("-PT20M", nil))
+
+	now := time.Date(2025, 3, 10, 8, 45, 0, 0, berlin)
+	schedule, err := Schedule(event, now, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schedule) != 1 || schedule[0].AlertID != "ack-before-fire" {
+		t.Errorf("Expected only ack-before-fire, got %+v", schedule)
+	}
+
+	schedule, err = Schedule(event, now, Options{IncludeMissed: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schedule) != 2 || schedule[0].AlertID != "missed" {
+		t.Errorf("Expected missed and ack-before-fire, got %+v", schedule)
+	}
+}
+
+func TestScheduleSnoozedAlert(t *testing.T) {
+	event := jscal.NewEvent("snoozed", "Stand-up")
+	now := time.Now().UTC()
+	event.Start = jscal.NewLocalDateTime(now.Add(5 * time.Minute))
+	event.AddAlert("10-min", offsetAlert("-PT10M", nil))
+
+	until := now.Add(3 * time.Minute)
+	snoozeID, err := event.SnoozeAlert("10-min", until)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schedule, err := Schedule(event, now, Options{Location: time.UTC, IncludeMissed: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schedule) != 1 || schedule[0].AlertID != snoozeID || !schedule[0].Time.Equal(until) {
+		t.Errorf("Expected only the snooze alert at %s, got %+v", until, schedule)
+	}
+}
+
+func TestScheduleDefaultAlerts(t *testing.T) {
+	event := jscal.NewEvent("alert-event", "Dentist")
+	event.Start = jscal.NewLocalDateTime(time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC))
+	event.TimeZone = jscal.String("Europe/Berlin")
+	event.Duration = jscal.String("PT1H")
+	event.AddAlert("own", offsetAlert("-PT5M", nil))
+	event.UseDefaultAlerts = jscal.Bool(true)
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	schedule, err := Schedule(event, now, Options{})
+	if err != nil || len(schedule) != 0 {
+		t.Errorf("Expected no alerts without defaults, got %+v (%v)", schedule, err)
+	}
+
+	defaults := map[string]*jscal.Alert{"default": offsetAlert("-PT10M", nil)}
+	schedule, err = Schedule(event, now, Options{DefaultAlerts: defaults})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schedule) != 1 || schedule[0].AlertID != "default" || !schedule[0].Default {
+		t.Errorf("Expected the default alert to replace the event's alerts, got %+v", schedule)
+	}
+
+	// A provider takes precedence over DefaultAlerts
+	calendar := jscal.DefaultAlertsFunc(func(obj jscal.CalendarObject) map[string]*jscal.Alert {
+		return map[string]*jscal.Alert{"calendar-" + obj.GetUID(): offsetAlert("-PT30M", nil)}
+	})
+	schedule, err = Schedule(event, now, Options{Defaults: calendar, DefaultAlerts: defaults})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schedule) != 1 || schedule[0].AlertID != "calendar-alert-event" || !schedule[0].Default {
+		t.Errorf("Expected the provider's alert, got %+v", schedule)
+	}
+}
+
+func TestScheduleTaskAndGroup(t *testing.T) {
+	task := jscal.NewTask("alert-task", "File taxes")
+	task.Due = jscal.NewLocalDateTime(time.Date(2025, 4, 15, 17, 0, 0, 0, time.UTC))
+	task.AddAlert("due-soon", offsetAlert("-P1D", jscal.String(jscal.RelativeToEnd)))
+	task.AddAlert("no-start", offsetAlert("PT0S", nil))
+
+	event := jscal.NewEvent("floating", "Lunch")
+	event.Start = jscal.NewLocalDateTime(time.Date(2025, 4, 14, 12, 0, 0, 0, time.UTC))
+	event.AddAlert("a", offsetAlert("-PT1H", nil))
+
+	group := jscal.NewGroup("alert-group", "Mixed")
+	for _, entry := range []jscal.CalendarObject{task, event} {
+		if err := group.AddEntry(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	schedule, err := Schedule(group, now, Options{Location: time.UTC})
+	if err != nil {
+		t.Fatalf("Schedule failed: %v", err)
+	}
+	if len(schedule) != 2 {
+		t.Fatalf("Expected 2 firings, got %+v", schedule)
+	}
+	if schedule[0].UID != "floating" || !schedule[0].Time.Equal(time.Date(2025, 4, 14, 11, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected floating event alert first, got %+v", schedule[0])
+	}
+	if schedule[1].UID != "alert-task" || !schedule[1].Time.Equal(time.Date(2025, 4, 14, 17, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected task alert relative to due, got %+v", schedule[1])
+	}
+}
+
+func TestScheduleErrors(t *testing.T) {
+	now := time.Now()
+
+	event := jscal.NewEvent("alert-event", "Dentist")
+	event.Start = jscal.NewLocalDateTime(time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC))
+	event.TimeZone = jscal.String("Europe/Berlin")
+	event.Duration = jscal.String("PT1H")
+	event.AddAlert("bad", offsetAlert("soon", nil))
+	if _, err := Schedule(event, now, Options{}); err == nil || !strings.Contains(err.Error(), "alert bad") {
+		t.Errorf("Expected invalid offset error, got %v", err)
+	}
+
+	event = jscal.NewEvent("alert-event", "Dentist")
+	event.Start = jscal.NewLocalDateTime(time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC))
+	event.TimeZone = jscal.String("Mars/Olympus_Mons")
+	if _, err := Schedule(event, now, Options{}); err == nil || !strings.Contains(err.Error(), "unknown time zone") {
+		t.Errorf("Expected time zone error, got %v", err)
+	}
+
+	event = jscal.NewEvent("alert-event", "Dentist")
+	event.Start = nil
+	if _, err := Schedule(event, now, Options{}); err == nil {
+		t.Error("Expected error for event without start")
+	}
+}
+
+func TestScheduleAbsoluteTrigger(t *testing.T) {
+	event := jscal.NewEvent("alert-event", "Dentist")
+	event.Start = jscal.NewLocalDateTime(time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC))
+	event.TimeZone = jscal.String("Europe/Berlin")
+	event.Duration = jscal.String("PT1H")
+	when := time.Date(2025, 3, 9, 18, 0, 0, 0, time.UTC)
+	event.AddAlert("absolute", &jscal.Alert{Type: "Alert", Trigger: jscal.NewAbsoluteTrigger(when)})
+	event.AddAlert("unknown", &jscal.Alert{Type: "Alert", Trigger: &jscal.UnknownTrigger{Type: "LocationTrigger"}})
+
+	schedule, err := Schedule(event, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schedule) != 1 || schedule[0].AlertID != "absolute" || !schedule[0].Time.Equal(when) {
+		t.Errorf("Expected only the absolute alert at %s, got %+v", when, schedule)
+	}
+}
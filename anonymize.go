@@ -0,0 +1,173 @@
+package jscal
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+)
+
+// AnonymizeOptions configures Anonymize
+type AnonymizeOptions struct {
+	// Key keys the HMAC behind the pseudonyms: with the same key a value
+	// always gets the same pseudonym, across events and runs. Without one a
+	// random key is used, so pseudonyms only match within one event.
+	Key []byte
+
+	// KeepExtensions keeps vendor extension properties, which are removed by
+	// default since they may hold anything
+	KeepExtensions bool
+}
+
+// Anonymize returns a copy of the event with personal data replaced by
+// stable pseudonyms, for bug reports and analytics. Titles, descriptions and
+// names become opaque text, email addresses and URIs point to example.com
+// and coordinates to 0,0. Location addresses keep only their country. UIDs, participant IDs, keywords and categories are
+// renamed consistently, including where recurrence overrides and
+// localizations refer to them. Timing, recurrence and the shape of the event
+// are kept.
+func Anonymize(event *Event, opts AnonymizeOptions) (*Event, error) {
+	key := opts.Key
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		_, _ = rand.Read(key)
+	}
+	a := anonymizer{key: key}
+
+	obj, err := toJSONObject(event)
+	if err != nil {
+		return nil, err
+	}
+	if !opts.KeepExtensions {
+		known := knownProperties(eventType)
+		for name := range obj {
+			if !known[name] {
+				delete(obj, name)
+			}
+		}
+	}
+
+	data, err := json.Marshal(a.object(nil, obj))
+	if err != nil {
+		return nil, err
+	}
+	var anonymized Event
+	if err := json.Unmarshal(data, &anonymized); err != nil {
+		return nil, err
+	}
+	return &anonymized, nil
+}
+
+// anonymizer replaces values by property path, the names leading to them
+// from the top of the event
+type anonymizer struct {
+	key []byte
+}
+
+// token returns the pseudonym of value among values of the same kind
+func (a anonymizer) token(kind, value string) string {
+	mac := hmac.New(sha256.New, a.key)
+	mac.Write([]byte(kind))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil)[:6])
+}
+
+func (a anonymizer) email(address string) string {
+	return a.token("email", strings.ToLower(address)) + "@example.com"
+}
+
+func (a anonymizer) uri(uri string) string {
+	if address, ok := strings.CutPrefix(uri, "mailto:"); ok {
+		return "mailto:" + a.email(address)
+	}
+	return "https://example.com/" + a.token("uri", uri)
+}
+
+// object anonymizes a JSON object at path
+func (a anonymizer) object(path []string, obj map[string]interface{}) map[string]interface{} {
+	// Overrides and localizations are patches keyed by JSON pointers from
+	// the top of the event
+	patch := len(path) == 2 && (path[0] == "recurrenceOverrides" || path[0] == "localizations")
+
+	result := make(map[string]interface{}, len(obj))
+	for key, value := range obj {
+		if !patch {
+			result[a.rename(path, key)] = a.value(append(path[:len(path):len(path)], key), value)
+			continue
+		}
+		segments := splitPointer(key)
+		renamed := make([]string, len(segments))
+		for i, segment := range segments {
+			renamed[i] = strings.NewReplacer("~", "~0", "/", "~1").Replace(a.rename(segments[:i], segment))
+		}
+		result[strings.Join(renamed, "/")] = a.value(segments, value)
+	}
+	return result
+}
+
+// rename returns the name of the member key of the object at path
+func (a anonymizer) rename(path []string, key string) string {
+	switch parent := lastSegment(path); {
+	case parent == "participants" || parent == "delegatedTo" || parent == "delegatedFrom" || parent == "memberOf":
+		return a.token("participant", key)
+	case parent == "keywords" || parent == "categories":
+		return a.token("keyword", key)
+	case parent == "relatedTo" && len(path) == 1:
+		return a.token("uid", key)
+	}
+	return key
+}
+
+// value anonymizes the value at path
+func (a anonymizer) value(path []string, value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return a.object(path, v)
+	case []interface{}:
+		for i := range v {
+			v[i] = a.value(path, v[i])
+		}
+		return v
+	case string:
+		if v == "" {
+			return v
+		}
+		name := lastSegment(path)
+		if len(path) > 1 && (path[len(path)-2] == "sendTo" || path[len(path)-2] == "replyTo") {
+			return a.uri(v)
+		}
+		if len(path) == 3 && path[0] == "localizedStrings" {
+			return name + "-" + a.token("text", v)
+		}
+		if len(path) > 1 && path[len(path)-2] == "address" && name != "@type" && name != "country" && name != "countryCode" {
+			return name + "-" + a.token("text", v)
+		}
+		switch name {
+		case "title", "description", "name", "participationComment":
+			return name + "-" + a.token("text", v)
+		case "email", "sentBy":
+			return a.email(v)
+		case "uri", "href":
+			return a.uri(v)
+		case "coordinates":
+			return "geo:0,0"
+		case "invitedBy":
+			return a.token("participant", v)
+		case "uid":
+			if len(path) == 1 {
+				return a.token("uid", v)
+			}
+		}
+	}
+	return value
+}
+
+func lastSegment(path []string) string {
+	if len(path) == 0 {
+		return ""
+	}
+	return path[len(path)-1]
+}
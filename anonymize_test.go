@@ -0,0 +1,104 @@
+package jscal
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAnonymize(t *testing.T) {
+	var event Event
+	if err := json.Unmarshal(benchmarkEvent, &event); err != nil {
+		t.Fatal(err)
+	}
+	event.Participants["dG9tQGZvb2Jhci5xlLmNvbQ"].DelegatedTo = map[string]bool{"em9lQGZvb2GFtcGxlLmNvbQ": true}
+	event.RecurrenceOverrides["2020-03-11T09:00:00"] = map[string]interface{}{
+		"title": "Special edition",
+		"participants/em9lQGZvb2GFtcGxlLmNvbQ/name": "Zoe Z.",
+		"locations/l1": map[string]interface{}{"@type": "Location", "name": "Room 5C"},
+	}
+	event.RelatedTo = map[string]*Relation{"parent@example.com": {Type: "Relation"}}
+	original, _ := json.Marshal(&event)
+
+	opts := AnonymizeOptions{Key: []byte("secret")}
+	anonymized, err := Anonymize(&event, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(anonymized)
+	for _, personal := range []string{"FooBar", "Tom Tool", "Zoe", "foobar.example.com", "chatme", "Room",
+		"-26.2041", "dG9tQGZvb2Jhci5xlLmNvbQ", "team-meeting-weekly-2020", "Special", "parent@", "meetings", "HQ"} {
+		if strings.Contains(string(data), personal) {
+			t.Errorf("Expected %q to be anonymized in %s", personal, data)
+		}
+	}
+	if again, _ := json.Marshal(&event); string(again) != string(original) {
+		t.Error("Expected Anonymize to leave the event unchanged")
+	}
+
+	// Timing and structure are kept
+	if !anonymized.Start.Time().Equal(event.Start.Time()) || *anonymized.Duration != "PT1H" ||
+		len(anonymized.RecurrenceRules) != 1 || len(anonymized.Participants) != 2 || len(anonymized.RecurrenceOverrides) != 2 {
+		t.Errorf("Expected timing and structure to be kept, got %s", data)
+	}
+	if anonymized.Extensions != nil {
+		t.Errorf("Expected extensions to be removed, got %v", anonymized.Extensions)
+	}
+
+	// References follow the renamed participants
+	var tom, zoe string
+	for id, p := range anonymized.Participants {
+		if p.DelegatedTo != nil {
+			tom = id
+		} else {
+			zoe = id
+		}
+	}
+	if !anonymized.Participants[tom].DelegatedTo[zoe] {
+		t.Errorf("Expected delegatedTo to refer to the renamed participant %s", zoe)
+	}
+	override := anonymized.RecurrenceOverrides["2020-03-11T09:00:00"]
+	if _, ok := override["participants/"+zoe+"/name"]; !ok {
+		t.Errorf("Expected the override path to use the renamed participant, got %v", override)
+	}
+	if !strings.HasPrefix(override["title"].(string), "title-") {
+		t.Errorf("Expected the override title to be anonymized, got %v", override["title"])
+	}
+
+	// The same key gives the same pseudonyms, a different one doesn't
+	same, _ := Anonymize(&event, opts)
+	if sameData, _ := json.Marshal(same); string(sameData) != string(data) {
+		t.Error("Expected the same pseudonyms for the same key")
+	}
+	other, _ := Anonymize(&event, AnonymizeOptions{Key: []byte("other")})
+	if other.UID == anonymized.UID || *other.Title == *anonymized.Title {
+		t.Error("Expected different pseudonyms for a different key")
+	}
+	if random, _ := Anonymize(&event, AnonymizeOptions{}); random.UID == anonymized.UID {
+		t.Error("Expected a random key without Key")
+	}
+
+	// Emails are pseudonymized the same way wherever they appear
+	p := anonymized.Participants[zoe]
+	if p.SendTo["imip"] != "mailto:"+*p.Email {
+		t.Errorf("Expected sendTo to match the email, got %s and %s", p.SendTo["imip"], *p.Email)
+	}
+
+	kept, _ := Anonymize(&event, AnonymizeOptions{Key: []byte("secret"), KeepExtensions: true})
+	if kept.Extensions["example.com:room"] == nil {
+		t.Error("Expected KeepExtensions to keep extensions")
+	}
+}
+
+func TestAnonymizeKeepsEmptyAndTimeValues(t *testing.T) {
+	created := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	event := &Event{Type: "Event", UID: "e1", Title: String(""), Created: NewUTCDateTime(created)}
+	anonymized, err := Anonymize(event, AnonymizeOptions{Key: []byte("k")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *anonymized.Title != "" || !anonymized.Created.Equal(NewUTCDateTime(created)) {
+		t.Errorf("Expected the empty title and created to be kept, got %q and %v", *anonymized.Title, anonymized.Created)
+	}
+}
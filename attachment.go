@@ -0,0 +1,127 @@
+package jscal
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// AttachOptions limits the files AttachFile inlines. The zero value has no
+// limits.
+type AttachOptions struct {
+	// MaxSize rejects files larger than this many bytes; 0 means no limit
+	MaxSize int
+
+	// MaxTotalSize rejects files that would make the event's inline
+	// attachments larger than this many bytes in total; 0 means no limit
+	MaxTotalSize int
+}
+
+// AttachFile adds a file as an inline attachment: a link with rel
+// "enclosure" whose href is a base64 data: URI, titled with the file name.
+// An empty contentType is detected from the data. Files over the limits
+// are rejected with an error wrapping ErrTooLarge; an attachment with the
+// same id is replaced.
+func (e *Event) AttachFile(id, name, contentType string, data []byte, opts AttachOptions) error {
+	if opts.MaxSize > 0 && len(data) > opts.MaxSize {
+		return fmt.Errorf("attachment %s is %d bytes, more than %d: %w", name, len(data), opts.MaxSize, ErrTooLarge)
+	}
+	if opts.MaxTotalSize > 0 {
+		total := len(data)
+		for linkID, link := range e.Attachments() {
+			if linkID != id {
+				total += link.inlineSize()
+			}
+		}
+		if total > opts.MaxTotalSize {
+			return fmt.Errorf("attachments would be %d bytes, more than %d: %w", total, opts.MaxTotalSize, ErrTooLarge)
+		}
+	}
+
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+	size := len(data)
+	link := &Link{
+		Href:        "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(data),
+		ContentType: String(contentType),
+		Size:        &size,
+		Rel:         String(LinkRelationEnclosure),
+	}
+	if name != "" {
+		link.Title = String(name)
+	}
+	e.AddLink(id, link)
+	return nil
+}
+
+// Attachments returns the event's links with rel "enclosure", by id
+func (e *Event) Attachments() map[string]*Link {
+	attachments := make(map[string]*Link)
+	for id, link := range e.Links {
+		if link != nil && link.GetRel() == LinkRelationEnclosure {
+			attachments[id] = link
+		}
+	}
+	return attachments
+}
+
+// IsInline reports whether the link carries its content as a data: URI
+func (l *Link) IsInline() bool {
+	return len(l.Href) >= 5 && strings.EqualFold(l.Href[:5], "data:")
+}
+
+// Data returns the content and media type of an inline link, decoding
+// base64 and percent-encoded data: URIs. The media type defaults to
+// text/plain;charset=US-ASCII as in RFC 2397.
+func (l *Link) Data() ([]byte, string, error) {
+	if !l.IsInline() {
+		return nil, "", fmt.Errorf("link %s is not a data: URI", l.Href)
+	}
+	header, payload, ok := strings.Cut(l.Href[5:], ",")
+	if !ok {
+		return nil, "", fmt.Errorf("data: URI without a comma")
+	}
+
+	mediaType, isBase64 := strings.CutSuffix(header, ";base64")
+	if mediaType == "" {
+		mediaType = "text/plain;charset=US-ASCII"
+	}
+	if isBase64 {
+		data, err := base64.StdEncoding.DecodeString(payload)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid base64 in data: URI: %w", err)
+		}
+		return data, mediaType, nil
+	}
+	data, err := url.PathUnescape(payload)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid data: URI: %w", err)
+	}
+	return []byte(data), mediaType, nil
+}
+
+// InlineAttachmentSize returns the decoded size in bytes of the event's
+// inline attachments
+func (e *Event) InlineAttachmentSize() int {
+	total := 0
+	for _, link := range e.Attachments() {
+		total += link.inlineSize()
+	}
+	return total
+}
+
+// inlineSize returns the decoded size of an inline link, 0 for other links
+// and undecodable data
+func (l *Link) inlineSize() int {
+	if !l.IsInline() {
+		return 0
+	}
+	data, _, err := l.Data()
+	if err != nil {
+		return 0
+	}
+	return len(data)
+}
@@ -0,0 +1,95 @@
+package jscal
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAttachFile(t *testing.T) {
+	event := NewEvent("attach-test", "Review")
+	if err := event.AttachFile("1", "notes.txt", "text/plain", []byte("hello"), AttachOptions{}); err != nil {
+		t.Fatalf("AttachFile failed: %v", err)
+	}
+
+	link := event.Links["1"]
+	if link.Href != "data:text/plain;base64,aGVsbG8=" {
+		t.Errorf("Expected a base64 data: URI, got %s", link.Href)
+	}
+	if link.GetRel() != LinkRelationEnclosure || link.Title == nil || *link.Title != "notes.txt" {
+		t.Errorf("Expected an enclosure titled notes.txt, got %+v", link)
+	}
+	if link.Size == nil || *link.Size != 5 || link.ContentType == nil || *link.ContentType != "text/plain" {
+		t.Errorf("Expected size 5 and content type text/plain, got %+v", link)
+	}
+
+	data, contentType, err := link.Data()
+	if err != nil || string(data) != "hello" || contentType != "text/plain" {
+		t.Errorf("Expected hello as text/plain, got %q as %s (%v)", data, contentType, err)
+	}
+
+	// The content type is detected when not given
+	if err := event.AttachFile("2", "image.png", "", []byte("\x89PNG\r\n\x1a\n"), AttachOptions{}); err != nil {
+		t.Fatalf("AttachFile failed: %v", err)
+	}
+	if got := *event.Links["2"].ContentType; got != "image/png" {
+		t.Errorf("Expected detected content type image/png, got %s", got)
+	}
+
+	event.AddLink("web", NewLink("https://example.com"))
+	if attachments := event.Attachments(); len(attachments) != 2 || attachments["web"] != nil {
+		t.Errorf("Expected 2 attachments, got %v", attachments)
+	}
+	if size := event.InlineAttachmentSize(); size != 13 {
+		t.Errorf("Expected 13 bytes of inline attachments, got %d", size)
+	}
+}
+
+func TestAttachFileLimits(t *testing.T) {
+	event := NewEvent("limits-test", "Review")
+	opts := AttachOptions{MaxSize: 10, MaxTotalSize: 15}
+
+	if err := event.AttachFile("1", "big.bin", "", make([]byte, 11), opts); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Expected ErrTooLarge for a file over MaxSize, got %v", err)
+	}
+	if err := event.AttachFile("1", "a.bin", "", make([]byte, 10), opts); err != nil {
+		t.Fatalf("AttachFile failed: %v", err)
+	}
+	if err := event.AttachFile("2", "b.bin", "", make([]byte, 6), opts); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Expected ErrTooLarge for files over MaxTotalSize, got %v", err)
+	}
+	// Replacing an attachment doesn't count the old one
+	if err := event.AttachFile("1", "a.bin", "", make([]byte, 9), opts); err != nil {
+		t.Errorf("Expected the replacement to fit, got %v", err)
+	}
+	if len(event.Links) != 1 {
+		t.Errorf("Expected 1 link, got %d", len(event.Links))
+	}
+}
+
+func TestLinkData(t *testing.T) {
+	tests := []struct {
+		href        string
+		data        string
+		contentType string
+		wantErr     bool
+	}{
+		{"data:text/plain;base64,aGVsbG8=", "hello", "text/plain", false},
+		{"data:,hello%20world", "hello world", "text/plain;charset=US-ASCII", false},
+		{"DATA:text/csv,a%2Cb", "a,b", "text/csv", false},
+		{"data:;base64,aGk=", "hi", "text/plain;charset=US-ASCII", false},
+		{"data:text/plain;base64,!!!", "", "", true},
+		{"data:text/plain", "", "", true},
+		{"https://example.com/a.txt", "", "", true},
+	}
+
+	for _, tt := range tests {
+		data, contentType, err := NewLink(tt.href).Data()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Data(%s): expected error %v, got %v", tt.href, tt.wantErr, err)
+			continue
+		}
+		if string(data) != tt.data || contentType != tt.contentType {
+			t.Errorf("Data(%s): expected %q as %q, got %q as %q", tt.href, tt.data, tt.contentType, data, contentType)
+		}
+	}
+}
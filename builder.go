@@ -0,0 +1,448 @@
+package jscal
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// EventOption configures an Event under construction. Options can be passed
+// to NewEventBuilder or Apply to share settings between builders.
+type EventOption func(*Event) error
+
+// EventBuilder builds an Event with a fluent API:
+//
+//	event, err := jscal.NewEventBuilder(uid).
+//		Title("Standup").
+//		StartsAt(start, "Europe/Vienna").
+//		Lasts("PT15M").
+//		Recurs(jscal.NewRecurrenceRule("daily")).
+//		Build()
+//
+// Errors are collected and returned by Build, which also validates the
+// Event. A builder must not be used after Build.
+type EventBuilder struct {
+	event *Event
+	errs  []error
+}
+
+// NewEventBuilder creates an EventBuilder for an untitled Event starting now
+func NewEventBuilder(uid string, opts ...EventOption) *EventBuilder {
+	b := &EventBuilder{event: NewEvent(uid, "")}
+	b.event.Title = nil
+	return b.Apply(opts...)
+}
+
+// Apply applies options to the Event
+func (b *EventBuilder) Apply(opts ...EventOption) *EventBuilder {
+	for _, opt := range opts {
+		if err := opt(b.event); err != nil {
+			b.errs = append(b.errs, err)
+		}
+	}
+	return b
+}
+
+// Title sets the title
+func (b *EventBuilder) Title(title string) *EventBuilder {
+	b.event.Title = &title
+	return b
+}
+
+// Description sets the plain text description
+func (b *EventBuilder) Description(description string) *EventBuilder {
+	b.event.Description = &description
+	return b
+}
+
+// StartsAt sets the start to the wall clock time of t in the time zone tz.
+// An empty tz makes the start floating, using the wall clock time of t.
+func (b *EventBuilder) StartsAt(t time.Time, tz string) *EventBuilder {
+	start, err := localTimeIn(t, tz)
+	if err != nil {
+		b.errs = append(b.errs, err)
+		return b
+	}
+	b.event.Start = start
+	b.event.TimeZone = nil
+	if tz != "" {
+		b.event.TimeZone = &tz
+	}
+	b.event.ShowWithoutTime = nil
+	return b
+}
+
+// AllDay makes the Event an all-day event on the date of t, lasting days days
+func (b *EventBuilder) AllDay(t time.Time, days int) *EventBuilder {
+	b.event.Start = NewLocalDateTime(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC))
+	b.event.TimeZone = nil
+	b.event.ShowWithoutTime = Bool(true)
+	b.event.Duration = String(fmt.Sprintf("P%dD", days))
+	return b
+}
+
+// Lasts sets the duration, an ISO 8601 duration such as "PT1H"
+func (b *EventBuilder) Lasts(duration string) *EventBuilder {
+	if _, err := ParseDuration(duration); err != nil {
+		b.errs = append(b.errs, fmt.Errorf("duration: %w", err))
+		return b
+	}
+	b.event.Duration = &duration
+	return b
+}
+
+// Recurs adds a recurrence rule
+func (b *EventBuilder) Recurs(rule *RecurrenceRule) *EventBuilder {
+	if rule == nil {
+		b.errs = append(b.errs, errors.New("recurrence rule: nil"))
+		return b
+	}
+	b.event.RecurrenceRules = append(b.event.RecurrenceRules, *rule)
+	return b
+}
+
+// Participant adds a participant
+func (b *EventBuilder) Participant(id string, participant *Participant) *EventBuilder {
+	b.event.AddParticipant(id, participant)
+	return b
+}
+
+// Location adds a location
+func (b *EventBuilder) Location(id string, location *Location) *EventBuilder {
+	b.event.AddLocation(id, location)
+	return b
+}
+
+// VirtualLocation adds a virtual location
+func (b *EventBuilder) VirtualLocation(id string, virtualLocation *VirtualLocation) *EventBuilder {
+	b.event.AddVirtualLocation(id, virtualLocation)
+	return b
+}
+
+// Alert adds an alert
+func (b *EventBuilder) Alert(id string, alert *Alert) *EventBuilder {
+	b.event.AddAlert(id, alert)
+	return b
+}
+
+// Link adds a link
+func (b *EventBuilder) Link(id string, link *Link) *EventBuilder {
+	b.event.AddLink(id, link)
+	return b
+}
+
+// Category adds a category
+func (b *EventBuilder) Category(category string) *EventBuilder {
+	b.event.AddCategory(category)
+	return b
+}
+
+// Keyword adds a keyword
+func (b *EventBuilder) Keyword(keyword string) *EventBuilder {
+	b.event.AddKeyword(keyword)
+	return b
+}
+
+// Status sets the status, one of the Status constants
+func (b *EventBuilder) Status(status string) *EventBuilder {
+	b.event.Status = &status
+	return b
+}
+
+// Priority sets the priority, 1 (highest) to 9 (lowest) or 0 for undefined
+func (b *EventBuilder) Priority(priority int) *EventBuilder {
+	b.event.Priority = &priority
+	return b
+}
+
+// Privacy sets the privacy, one of the Privacy constants
+func (b *EventBuilder) Privacy(privacy string) *EventBuilder {
+	b.event.Privacy = &privacy
+	return b
+}
+
+// FreeBusyStatus sets the free/busy status, one of the FreeBusyStatus constants
+func (b *EventBuilder) FreeBusyStatus(status string) *EventBuilder {
+	b.event.FreeBusyStatus = &status
+	return b
+}
+
+// Color sets the CSS color
+func (b *EventBuilder) Color(color string) *EventBuilder {
+	b.event.Color = &color
+	return b
+}
+
+// Build validates and returns the Event
+func (b *EventBuilder) Build() (*Event, error) {
+	if len(b.errs) > 0 {
+		return nil, errors.Join(b.errs...)
+	}
+	if err := b.event.Validate(); err != nil {
+		return nil, err
+	}
+	return b.event, nil
+}
+
+// TaskOption configures a Task under construction, see EventOption
+type TaskOption func(*Task) error
+
+// TaskBuilder builds a Task with a fluent API, see EventBuilder
+type TaskBuilder struct {
+	task *Task
+	errs []error
+}
+
+// NewTaskBuilder creates a TaskBuilder for an untitled Task without start or due
+func NewTaskBuilder(uid string, opts ...TaskOption) *TaskBuilder {
+	b := &TaskBuilder{task: NewTask(uid, "")}
+	b.task.Title = nil
+	return b.Apply(opts...)
+}
+
+// Apply applies options to the Task
+func (b *TaskBuilder) Apply(opts ...TaskOption) *TaskBuilder {
+	for _, opt := range opts {
+		if err := opt(b.task); err != nil {
+			b.errs = append(b.errs, err)
+		}
+	}
+	return b
+}
+
+// Title sets the title
+func (b *TaskBuilder) Title(title string) *TaskBuilder {
+	b.task.Title = &title
+	return b
+}
+
+// Description sets the plain text description
+func (b *TaskBuilder) Description(description string) *TaskBuilder {
+	b.task.Description = &description
+	return b
+}
+
+// StartsAt sets the start to the wall clock time of t in the time zone tz,
+// see EventBuilder.StartsAt. Start and due share the task's time zone.
+func (b *TaskBuilder) StartsAt(t time.Time, tz string) *TaskBuilder {
+	if start, ok := b.localTime(t, tz); ok {
+		b.task.Start = start
+	}
+	return b
+}
+
+// DueAt sets the due time to the wall clock time of t in the time zone tz
+func (b *TaskBuilder) DueAt(t time.Time, tz string) *TaskBuilder {
+	if due, ok := b.localTime(t, tz); ok {
+		b.task.Due = due
+	}
+	return b
+}
+
+// localTime converts t for StartsAt and DueAt, setting the task's time zone
+func (b *TaskBuilder) localTime(t time.Time, tz string) (*LocalDateTime, bool) {
+	if b.task.TimeZone != nil && *b.task.TimeZone != tz {
+		b.errs = append(b.errs, fmt.Errorf("time zone %q conflicts with %q", tz, *b.task.TimeZone))
+		return nil, false
+	}
+	local, err := localTimeIn(t, tz)
+	if err != nil {
+		b.errs = append(b.errs, err)
+		return nil, false
+	}
+	if tz != "" {
+		b.task.TimeZone = &tz
+	}
+	return local, true
+}
+
+// Estimate sets the estimated duration, an ISO 8601 duration such as "PT2H"
+func (b *TaskBuilder) Estimate(duration string) *TaskBuilder {
+	if _, err := ParseDuration(duration); err != nil {
+		b.errs = append(b.errs, fmt.Errorf("estimatedDuration: %w", err))
+		return b
+	}
+	b.task.EstimatedDuration = &duration
+	return b
+}
+
+// Progress sets the progress and percent complete, see Task.SetProgress
+func (b *TaskBuilder) Progress(progress string, percentComplete int) *TaskBuilder {
+	b.task.SetProgress(progress, percentComplete)
+	return b
+}
+
+// Recurs adds a recurrence rule
+func (b *TaskBuilder) Recurs(rule *RecurrenceRule) *TaskBuilder {
+	if rule == nil {
+		b.errs = append(b.errs, errors.New("recurrence rule: nil"))
+		return b
+	}
+	b.task.RecurrenceRules = append(b.task.RecurrenceRules, *rule)
+	return b
+}
+
+// Participant adds a participant
+func (b *TaskBuilder) Participant(id string, participant *Participant) *TaskBuilder {
+	b.task.AddParticipant(id, participant)
+	return b
+}
+
+// Location adds a location
+func (b *TaskBuilder) Location(id string, location *Location) *TaskBuilder {
+	b.task.AddLocation(id, location)
+	return b
+}
+
+// Alert adds an alert
+func (b *TaskBuilder) Alert(id string, alert *Alert) *TaskBuilder {
+	b.task.AddAlert(id, alert)
+	return b
+}
+
+// Link adds a link
+func (b *TaskBuilder) Link(id string, link *Link) *TaskBuilder {
+	b.task.AddLink(id, link)
+	return b
+}
+
+// Category adds a category
+func (b *TaskBuilder) Category(category string) *TaskBuilder {
+	b.task.AddCategory(category)
+	return b
+}
+
+// Keyword adds a keyword
+func (b *TaskBuilder) Keyword(keyword string) *TaskBuilder {
+	b.task.AddKeyword(keyword)
+	return b
+}
+
+// Priority sets the priority, 1 (highest) to 9 (lowest) or 0 for undefined
+func (b *TaskBuilder) Priority(priority int) *TaskBuilder {
+	b.task.Priority = &priority
+	return b
+}
+
+// Privacy sets the privacy, one of the Privacy constants
+func (b *TaskBuilder) Privacy(privacy string) *TaskBuilder {
+	b.task.Privacy = &privacy
+	return b
+}
+
+// Color sets the CSS color
+func (b *TaskBuilder) Color(color string) *TaskBuilder {
+	b.task.Color = &color
+	return b
+}
+
+// Build validates and returns the Task
+func (b *TaskBuilder) Build() (*Task, error) {
+	if len(b.errs) > 0 {
+		return nil, errors.Join(b.errs...)
+	}
+	if err := b.task.Validate(); err != nil {
+		return nil, err
+	}
+	return b.task, nil
+}
+
+// GroupOption configures a Group under construction, see EventOption
+type GroupOption func(*Group) error
+
+// GroupBuilder builds a Group with a fluent API, see EventBuilder
+type GroupBuilder struct {
+	group *Group
+	errs  []error
+}
+
+// NewGroupBuilder creates a GroupBuilder for an untitled, empty Group
+func NewGroupBuilder(uid string, opts ...GroupOption) *GroupBuilder {
+	b := &GroupBuilder{group: NewGroup(uid, "")}
+	b.group.Title = nil
+	return b.Apply(opts...)
+}
+
+// Apply applies options to the Group
+func (b *GroupBuilder) Apply(opts ...GroupOption) *GroupBuilder {
+	for _, opt := range opts {
+		if err := opt(b.group); err != nil {
+			b.errs = append(b.errs, err)
+		}
+	}
+	return b
+}
+
+// Title sets the title
+func (b *GroupBuilder) Title(title string) *GroupBuilder {
+	b.group.Title = &title
+	return b
+}
+
+// Description sets the description
+func (b *GroupBuilder) Description(description string) *GroupBuilder {
+	b.group.Description = &description
+	return b
+}
+
+// Source sets the URI the group's data is retrieved from
+func (b *GroupBuilder) Source(source string) *GroupBuilder {
+	b.group.Source = &source
+	return b
+}
+
+// Entry adds an Event or Task
+func (b *GroupBuilder) Entry(entry CalendarObject) *GroupBuilder {
+	if err := b.group.AddEntry(entry); err != nil {
+		b.errs = append(b.errs, err)
+	}
+	return b
+}
+
+// Link adds a link
+func (b *GroupBuilder) Link(id string, link *Link) *GroupBuilder {
+	b.group.AddLink(id, link)
+	return b
+}
+
+// Category adds a category
+func (b *GroupBuilder) Category(category string) *GroupBuilder {
+	b.group.AddCategory(category)
+	return b
+}
+
+// Keyword adds a keyword
+func (b *GroupBuilder) Keyword(keyword string) *GroupBuilder {
+	b.group.AddKeyword(keyword)
+	return b
+}
+
+// Color sets the CSS color
+func (b *GroupBuilder) Color(color string) *GroupBuilder {
+	b.group.Color = &color
+	return b
+}
+
+// Build validates and returns the Group
+func (b *GroupBuilder) Build() (*Group, error) {
+	if len(b.errs) > 0 {
+		return nil, errors.Join(b.errs...)
+	}
+	if err := b.group.Validate(); err != nil {
+		return nil, err
+	}
+	return b.group, nil
+}
+
+// localTimeIn returns the wall clock time of t in the time zone tz, or of t
+// as is if tz is empty
+func localTimeIn(t time.Time, tz string) (*LocalDateTime, error) {
+	if tz == "" {
+		return NewLocalDateTime(t), nil
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q", tz)
+	}
+	return NewLocalDateTime(wallClock(LocalDateTime(t.In(loc)))), nil
+}
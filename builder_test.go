@@ -0,0 +1,148 @@
+package jscal
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEventBuilder(t *testing.T) {
+	start := time.Date(2025, 3, 3, 8, 0, 0, 0, time.UTC)
+	inOffice := func(e *Event) error {
+		e.AddLocation("office", NewLocation("Office"))
+		return nil
+	}
+
+	event, err := NewEventBuilder("standup@example.com", inOffice).
+		Title("Standup").
+		StartsAt(start, "Europe/Vienna").
+		Lasts("PT15M").
+		Recurs(NewRecurrenceRule("weekly")).
+		Participant("jd", NewParticipant("Jane Doe", "jd@example.com")).
+		Category("Work").
+		Priority(1).
+		Build()
+	if err != nil {
+		if strings.Contains(err.Error(), "unknown time zone") {
+			t.Skip("time zone database not available")
+		}
+		t.Fatal(err)
+	}
+
+	if *event.Title != "Standup" || *event.Duration != "PT15M" || *event.Priority != 1 {
+		t.Errorf("Expected title, duration and priority to be set, got %+v", event)
+	}
+	if event.Start.String() != "2025-03-03T09:00:00" || *event.TimeZone != "Europe/Vienna" {
+		t.Errorf("Expected start 2025-03-03T09:00:00 in Europe/Vienna, got %s in %v", event.Start, event.TimeZone)
+	}
+	if len(event.RecurrenceRules) != 1 || event.Participants["jd"] == nil || event.Locations["office"] == nil {
+		t.Errorf("Expected a rule, a participant and a location, got %+v", event)
+	}
+	if !event.Categories["Work"] {
+		t.Errorf("Expected category Work, got %v", event.Categories)
+	}
+}
+
+func TestEventBuilderErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *EventBuilder
+		wantErr string
+	}{
+		{
+			name:    "invalid duration",
+			builder: NewEventBuilder("e1").Lasts("1 hour"),
+			wantErr: "duration",
+		},
+		{
+			name:    "unknown time zone",
+			builder: NewEventBuilder("e2").StartsAt(time.Now(), "Not/A_Zone"),
+			wantErr: `unknown time zone "Not/A_Zone"`,
+		},
+		{
+			name:    "failing option",
+			builder: NewEventBuilder("e3").Apply(func(*Event) error { return errTestOption }),
+			wantErr: errTestOption.Error(),
+		},
+		{
+			name:    "nil recurrence rule",
+			builder: NewEventBuilder("e5").Recurs(nil),
+			wantErr: "recurrence rule",
+		},
+		{
+			name:    "validation",
+			builder: NewEventBuilder("e4").Priority(12),
+			wantErr: "priority",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := tt.builder.Build()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+			if event != nil {
+				t.Errorf("Expected no event, got %+v", event)
+			}
+		})
+	}
+}
+
+var errTestOption = errors.New("option failed")
+
+func TestEventBuilderAllDay(t *testing.T) {
+	event, err := NewEventBuilder("holiday").Title("Holiday").AllDay(time.Date(2025, 12, 24, 15, 0, 0, 0, time.UTC), 2).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !event.IsAllDay() || event.Start.String() != "2025-12-24T00:00:00" || *event.Duration != "P2D" {
+		t.Errorf("Expected a two-day all-day event on 2025-12-24, got %s %v", event.Start, event.Duration)
+	}
+}
+
+func TestTaskBuilder(t *testing.T) {
+	due := time.Date(2025, 3, 7, 17, 0, 0, 0, time.UTC)
+	task, err := NewTaskBuilder("report").
+		Title("Report").
+		StartsAt(due.AddDate(0, 0, -4), "").
+		DueAt(due, "").
+		Estimate("PT4H").
+		Progress(ProgressInProcess, 50).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Due.String() != "2025-03-07T17:00:00" || task.TimeZone != nil || *task.EstimatedDuration != "PT4H" {
+		t.Errorf("Expected a floating due time and estimate, got %+v", task)
+	}
+	if *task.Progress != ProgressInProcess || *task.PercentComplete != 50 {
+		t.Errorf("Expected in-process at 50%%, got %v %v", *task.Progress, *task.PercentComplete)
+	}
+
+	if _, err := NewTaskBuilder("conflict").StartsAt(due, "UTC").DueAt(due, "").Build(); err == nil {
+		t.Error("Expected an error for conflicting time zones")
+	}
+	if _, err := NewTaskBuilder("nil-rule").Recurs(nil).Build(); err == nil {
+		t.Error("Expected an error for a nil recurrence rule")
+	}
+}
+
+func TestGroupBuilder(t *testing.T) {
+	event, err := NewEventBuilder("e1").Title("Event").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	group, err := NewGroupBuilder("g1").Title("Calendar").Source("https://example.com/cal").Entry(event).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if group.CountEvents() != 1 || *group.Source != "https://example.com/cal" {
+		t.Errorf("Expected one event and a source, got %+v", group)
+	}
+
+	if _, err := NewGroupBuilder("g2").Entry(nil).Build(); err == nil {
+		t.Error("Expected an error for a nil entry")
+	}
+}
@@ -0,0 +1,245 @@
+package jscal
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Limits of searches for working time: how many days in a row without
+// working hours, and how many days in all, before giving up
+const (
+	maxIdleDays   = 366
+	maxSearchDays = 10 * 366
+)
+
+// WorkingHours is a span of working time within a day, as offsets from
+// midnight, e.g. 9h to 17h
+type WorkingHours struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// BusinessCalendar describes when work happens: working hours per weekday,
+// exception dates such as holidays, and the time zone they are in
+type BusinessCalendar struct {
+	// Hours are the working hours of each weekday; days without any are off
+	Hours map[time.Weekday][]WorkingHours
+
+	// Exceptions replace the weekday's hours on a date ("2006-01-02"); no
+	// hours makes the date a day off
+	Exceptions map[string][]WorkingHours
+
+	// Location is the time zone of the working hours, UTC if nil
+	Location *time.Location
+}
+
+// NewBusinessCalendar creates a business calendar working Monday to Friday,
+// 9:00 to 17:00 in loc
+func NewBusinessCalendar(loc *time.Location) *BusinessCalendar {
+	nineToFive := []WorkingHours{{Start: 9 * time.Hour, End: 17 * time.Hour}}
+	c := &BusinessCalendar{Location: loc, Hours: make(map[time.Weekday][]WorkingHours)}
+	for day := time.Monday; day <= time.Friday; day++ {
+		c.Hours[day] = nineToFive
+	}
+	return c
+}
+
+// SetHours sets the working hours of a weekday; none makes it a day off
+func (c *BusinessCalendar) SetHours(day time.Weekday, hours ...WorkingHours) error {
+	if err := checkWorkingHours(hours); err != nil {
+		return err
+	}
+	if c.Hours == nil {
+		c.Hours = make(map[time.Weekday][]WorkingHours)
+	}
+	c.Hours[day] = hours
+	return nil
+}
+
+// SetException sets the working hours of a date ("2006-01-02"), replacing
+// the weekday's; none makes it a day off
+func (c *BusinessCalendar) SetException(date string, hours ...WorkingHours) error {
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return fmt.Errorf("invalid date %q: %w", date, err)
+	}
+	if err := checkWorkingHours(hours); err != nil {
+		return err
+	}
+	if c.Exceptions == nil {
+		c.Exceptions = make(map[string][]WorkingHours)
+	}
+	c.Exceptions[date] = hours
+	return nil
+}
+
+// AddHoliday makes a date ("2006-01-02") a day off
+func (c *BusinessCalendar) AddHoliday(date string) error {
+	return c.SetException(date)
+}
+
+// checkWorkingHours checks that spans fit in a day, don't end before they
+// start and don't overlap
+func checkWorkingHours(hours []WorkingHours) error {
+	sorted := append([]WorkingHours(nil), hours...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+	for i, h := range sorted {
+		if h.Start < 0 || h.End > 24*time.Hour || h.End <= h.Start {
+			return fmt.Errorf("invalid working hours %v-%v", h.Start, h.End)
+		}
+		if i > 0 && h.Start < sorted[i-1].End {
+			return fmt.Errorf("working hours %v-%v overlap %v-%v", h.Start, h.End, sorted[i-1].Start, sorted[i-1].End)
+		}
+	}
+	return nil
+}
+
+// location returns the calendar's time zone
+func (c *BusinessCalendar) location() *time.Location {
+	if c.Location != nil {
+		return c.Location
+	}
+	return time.UTC
+}
+
+// spans returns the working time of the day starting at midnight, sorted
+func (c *BusinessCalendar) spans(midnight time.Time) [][2]time.Time {
+	hours, ok := c.Exceptions[midnight.Format("2006-01-02")]
+	if !ok {
+		hours = c.Hours[midnight.Weekday()]
+	}
+
+	spans := make([][2]time.Time, 0, len(hours))
+	for _, h := range hours {
+		spans = append(spans, [2]time.Time{wallTime(midnight, h.Start), wallTime(midnight, h.End)})
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0].Before(spans[j][0]) })
+	return spans
+}
+
+// wallTime returns the time offset from midnight on the clock, so working
+// hours keep their wall clock time across DST transitions
+func wallTime(midnight time.Time, offset time.Duration) time.Time {
+	y, m, d := midnight.Date()
+	return time.Date(y, m, d, int(offset/time.Hour), int(offset%time.Hour/time.Minute),
+		int(offset%time.Minute/time.Second), int(offset%time.Second), midnight.Location())
+}
+
+// walk calls visit with the working spans ending after t, in order, until
+// visit returns true. It returns false if the calendar runs out of working
+// time or the search goes ten years ahead.
+func (c *BusinessCalendar) walk(t time.Time, visit func(start, end time.Time) bool) bool {
+	y, m, d := t.In(c.location()).Date()
+	first := time.Date(y, m, d, 0, 0, 0, 0, c.location())
+	for days, idle := 0, 0; idle <= maxIdleDays && days < maxSearchDays; days++ {
+		midnight := first.AddDate(0, 0, days)
+		spans := c.spans(midnight)
+		if len(spans) == 0 {
+			idle++
+			continue
+		}
+		idle = 0
+		for _, span := range spans {
+			if span[1].After(t) && visit(span[0], span[1]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsWorkingTime reports whether t falls within working hours
+func (c *BusinessCalendar) IsWorkingTime(t time.Time) bool {
+	y, m, d := t.In(c.location()).Date()
+	for _, span := range c.spans(time.Date(y, m, d, 0, 0, 0, 0, c.location())) {
+		if !t.Before(span[0]) && t.Before(span[1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextWorkingSlot returns the earliest start at or after after of an
+// uninterrupted stretch of working time lasting duration. ok is false if
+// there is none, e.g. if duration is longer than any working day.
+func (c *BusinessCalendar) NextWorkingSlot(after time.Time, duration time.Duration) (start time.Time, ok bool) {
+	ok = c.walk(after, func(spanStart, spanEnd time.Time) bool {
+		start = latest(spanStart, after)
+		return spanEnd.Sub(start) >= duration
+	})
+	return start, ok
+}
+
+// NextFreeSlot is NextWorkingSlot skipping the busy periods of fb, to find
+// a meeting time or a slot to work on a task
+func (c *BusinessCalendar) NextFreeSlot(fb *FreeBusy, after time.Time, duration time.Duration) (time.Time, bool) {
+	for {
+		start, ok := c.NextWorkingSlot(after, duration)
+		if !ok {
+			return time.Time{}, false
+		}
+		end := start.Add(duration)
+
+		blocked := false
+		if fb != nil {
+			for _, period := range fb.Busy {
+				if period.Start.Before(end) && period.End.After(start) {
+					after, blocked = period.End, true
+					break
+				}
+			}
+		}
+		if !blocked {
+			return start, true
+		}
+	}
+}
+
+// AddWorkingTime returns when work of the given duration started at start
+// is done, counting only working hours. ok is false if the calendar runs
+// out of working time.
+func (c *BusinessCalendar) AddWorkingTime(start time.Time, work time.Duration) (end time.Time, ok bool) {
+	if work <= 0 {
+		return start, true
+	}
+	ok = c.walk(start, func(spanStart, spanEnd time.Time) bool {
+		from := latest(spanStart, start)
+		if available := spanEnd.Sub(from); work > available {
+			work -= available
+			return false
+		}
+		end = from.Add(work)
+		return true
+	})
+	return end, ok
+}
+
+// WorkingTimeBetween returns the working time between from and to
+func (c *BusinessCalendar) WorkingTimeBetween(from, to time.Time) time.Duration {
+	var total time.Duration
+	if !from.Before(to) {
+		return 0
+	}
+	c.walk(from, func(spanStart, spanEnd time.Time) bool {
+		if !spanStart.Before(to) {
+			return true
+		}
+		total += earliest(spanEnd, to).Sub(latest(spanStart, from))
+		return false
+	})
+	return total
+}
+
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func earliest(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
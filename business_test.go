@@ -0,0 +1,140 @@
+package jscal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBusinessCalendarWorkingTime(t *testing.T) {
+	cal := NewBusinessCalendar(time.UTC)
+	if err := cal.AddHoliday("2025-03-05"); err != nil {
+		t.Fatal(err)
+	}
+	// Half day on Friday
+	if err := cal.SetException("2025-03-07", WorkingHours{Start: 9 * time.Hour, End: 13 * time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+
+	at := func(day, hour, minute int) time.Time { return time.Date(2025, 3, day, hour, minute, 0, 0, time.UTC) }
+	tests := []struct {
+		name    string
+		t       time.Time
+		working bool
+	}{
+		{"monday morning", at(3, 10, 0), true},
+		{"start of day", at(3, 9, 0), true},
+		{"end of day", at(3, 17, 0), false},
+		{"early", at(3, 8, 59), false},
+		{"holiday", at(5, 10, 0), false},
+		{"half day", at(7, 12, 0), true},
+		{"half day afternoon", at(7, 14, 0), false},
+		{"saturday", at(8, 10, 0), false},
+	}
+	for _, tt := range tests {
+		if got := cal.IsWorkingTime(tt.t); got != tt.working {
+			t.Errorf("%s: expected IsWorkingTime %v, got %v", tt.name, tt.working, got)
+		}
+	}
+
+	slots := []struct {
+		name     string
+		after    time.Time
+		duration time.Duration
+		want     time.Time
+	}{
+		{"fits now", at(3, 10, 0), time.Hour, at(3, 10, 0)},
+		{"next morning", at(3, 16, 30), time.Hour, at(4, 9, 0)},
+		{"skips holiday", at(4, 17, 0), time.Hour, at(6, 9, 0)},
+		{"too long for half day", at(7, 9, 0), 5 * time.Hour, at(10, 9, 0)},
+		{"weekend", at(8, 10, 0), 0, at(10, 9, 0)},
+	}
+	for _, tt := range slots {
+		got, ok := cal.NextWorkingSlot(tt.after, tt.duration)
+		if !ok || !got.Equal(tt.want) {
+			t.Errorf("%s: expected slot at %v, got %v (%v)", tt.name, tt.want, got, ok)
+		}
+	}
+	if _, ok := cal.NextWorkingSlot(at(3, 9, 0), 9*time.Hour); ok {
+		t.Errorf("Expected no slot longer than a working day")
+	}
+
+	// 12 hours from Tuesday 15:00: 2h Tuesday, holiday, 8h Thursday, 2h Friday
+	if end, ok := cal.AddWorkingTime(at(4, 15, 0), 12*time.Hour); !ok || !end.Equal(at(7, 11, 0)) {
+		t.Errorf("Expected work to end at %v, got %v (%v)", at(7, 11, 0), end, ok)
+	}
+	if got := cal.WorkingTimeBetween(at(3, 0, 0), at(10, 0, 0)); got != 28*time.Hour {
+		t.Errorf("Expected 28h of working time, got %v", got)
+	}
+	if got := cal.WorkingTimeBetween(at(10, 0, 0), at(3, 0, 0)); got != 0 {
+		t.Errorf("Expected no working time for a reversed range, got %v", got)
+	}
+}
+
+func TestBusinessCalendarNextFreeSlot(t *testing.T) {
+	cal := NewBusinessCalendar(time.UTC)
+	at := func(hour, minute int) time.Time { return time.Date(2025, 3, 3, hour, minute, 0, 0, time.UTC) }
+	fb := &FreeBusy{Busy: []BusyPeriod{
+		{Start: at(9, 0), End: at(10, 0), Status: FreeBusyBusy},
+		{Start: at(10, 30), End: at(12, 0), Status: FreeBusyBusy},
+		{Start: at(12, 30), End: at(17, 0), Status: FreeBusyTentative},
+	}}
+
+	if got, ok := cal.NextFreeSlot(fb, at(9, 0), 30*time.Minute); !ok || !got.Equal(at(10, 0)) {
+		t.Errorf("Expected a slot at 10:00, got %v (%v)", got, ok)
+	}
+	if got, ok := cal.NextFreeSlot(fb, at(9, 0), time.Hour); !ok || !got.Equal(time.Date(2025, 3, 4, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected a slot the next morning, got %v (%v)", got, ok)
+	}
+	if got, ok := cal.NextFreeSlot(nil, at(9, 0), time.Hour); !ok || !got.Equal(at(9, 0)) {
+		t.Errorf("Expected a slot at 9:00 without busy periods, got %v (%v)", got, ok)
+	}
+}
+
+func TestBusinessCalendarHours(t *testing.T) {
+	cal := &BusinessCalendar{}
+	if _, ok := cal.NextWorkingSlot(time.Now(), 0); ok {
+		t.Errorf("Expected no working time in an empty calendar")
+	}
+
+	invalid := [][]WorkingHours{
+		{{Start: 17 * time.Hour, End: 9 * time.Hour}},
+		{{Start: 9 * time.Hour, End: 25 * time.Hour}},
+		{{Start: 9 * time.Hour, End: 12 * time.Hour}, {Start: 11 * time.Hour, End: 14 * time.Hour}},
+	}
+	for _, hours := range invalid {
+		if err := cal.SetHours(time.Monday, hours...); err == nil {
+			t.Errorf("Expected error for hours %v", hours)
+		}
+	}
+	if err := cal.SetException("03/05/2025"); err == nil {
+		t.Errorf("Expected error for an invalid date")
+	}
+
+	// Split shift, given out of order
+	err := cal.SetHours(time.Monday, WorkingHours{Start: 13 * time.Hour, End: 17 * time.Hour}, WorkingHours{Start: 8 * time.Hour, End: 12 * time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	monday := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	if end, ok := cal.AddWorkingTime(monday, 6*time.Hour); !ok || !end.Equal(monday.Add(15*time.Hour)) {
+		t.Errorf("Expected work to end at 15:00, got %v (%v)", end, ok)
+	}
+}
+
+func TestBusinessCalendarDST(t *testing.T) {
+	if !hasTimeZoneDatabase() {
+		t.Skip("time zone database not available")
+	}
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	cal := NewBusinessCalendar(berlin)
+
+	// Clocks go forward on Sunday 2025-03-30; working hours stay 9:00-17:00
+	friday := time.Date(2025, 3, 28, 9, 0, 0, 0, berlin)
+	got, ok := cal.NextWorkingSlot(friday.Add(8*time.Hour), time.Hour)
+	if want := time.Date(2025, 3, 31, 9, 0, 0, 0, berlin); !ok || !got.Equal(want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if got.UTC().Hour() != 7 {
+		t.Errorf("Expected 9:00 CEST to be 7:00 UTC, got %v", got.UTC())
+	}
+}
@@ -0,0 +1,150 @@
+package jscal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+// canonicalDefaults lists, per @type, the properties whose RFC 8984 default
+// CanonicalJSON omits
+var canonicalDefaults = map[string]map[string]interface{}{
+	"Event": {
+		"title": "", "description": "", "descriptionContentType": "text/plain",
+		"showWithoutTime": false, "sequence": 0.0, "priority": 0.0, "freeBusyStatus": FreeBusyBusy,
+		"privacy": PrivacyPublic, "useDefaultAlerts": false, "excluded": false,
+		"duration": "PT0S", "status": StatusConfirmed,
+	},
+	"Task": {
+		"title": "", "description": "", "descriptionContentType": "text/plain",
+		"showWithoutTime": false, "sequence": 0.0, "priority": 0.0, "freeBusyStatus": FreeBusyBusy,
+		"privacy": PrivacyPublic, "useDefaultAlerts": false, "excluded": false,
+	},
+	"Group": {
+		"title": "", "description": "", "sequence": 0.0,
+	},
+	"Participant": {
+		"roles": map[string]interface{}{"attendee": true}, "participationStatus": ParticipationNeedsAction,
+		"expectReply": false, "scheduleAgent": "server", "scheduleForceSend": false, "scheduleSequence": 0.0,
+	},
+	"Alert":          {"action": AlertActionDisplay},
+	"OffsetTrigger":  {"relativeTo": RelativeToStart},
+	"RecurrenceRule": {"interval": 1.0, "rscale": "gregorian", "skip": "omit"},
+}
+
+// canonicalMembers maps properties holding sub-objects to the @type of
+// their members, for members that omit it
+var canonicalMembers = map[string]string{
+	"participants":            "Participant",
+	"locations":               "Location",
+	"virtualLocations":        "VirtualLocation",
+	"links":                   "Link",
+	"alerts":                  "Alert",
+	"recurrenceRules":         "RecurrenceRule",
+	"excludedRecurrenceRules": "RecurrenceRule",
+}
+
+// canonicalUTCDateTimes lists the UTCDateTime properties, which CanonicalJSON
+// writes in UTC without trailing zeros in the fraction
+var canonicalUTCDateTimes = map[string]bool{
+	"created": true, "updated": true, "acknowledged": true, "progressUpdated": true,
+	"scheduleUpdated": true, "validUntil": true, "when": true,
+}
+
+// CanonicalJSON returns a deterministic JSON encoding of the Event for
+// hashing and comparison: object keys are sorted at every level, there is
+// no insignificant whitespace, UTCDateTime values are written in UTC and
+// properties set to their RFC 8984 default are left out. Two events that
+// only differ in those respects encode the same.
+func (e *Event) CanonicalJSON() ([]byte, error) {
+	return canonicalJSON(e)
+}
+
+// Hash returns a fingerprint of the Event's content, the hex encoded
+// SHA-256 of its CanonicalJSON
+func (e *Event) Hash() (string, error) {
+	return canonicalHash(e)
+}
+
+// CanonicalJSON returns a deterministic JSON encoding of the Task, see
+// Event.CanonicalJSON
+func (t *Task) CanonicalJSON() ([]byte, error) {
+	return canonicalJSON(t)
+}
+
+// Hash returns a fingerprint of the Task's content, see Event.Hash
+func (t *Task) Hash() (string, error) {
+	return canonicalHash(t)
+}
+
+// CanonicalJSON returns a deterministic JSON encoding of the Group and its
+// entries, see Event.CanonicalJSON
+func (g *Group) CanonicalJSON() ([]byte, error) {
+	return canonicalJSON(g)
+}
+
+// Hash returns a fingerprint of the Group's content, see Event.Hash
+func (g *Group) Hash() (string, error) {
+	return canonicalHash(g)
+}
+
+func canonicalJSON(v interface{}) ([]byte, error) {
+	obj, err := toJSONObject(v)
+	if err != nil {
+		return nil, err
+	}
+	canonicalize(obj, "")
+	// encoding/json writes map keys sorted
+	return json.Marshal(obj)
+}
+
+func canonicalHash(v interface{}) (string, error) {
+	data, err := canonicalJSON(v)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// canonicalize rewrites a JSON object in place, using objType as its @type
+// if it has none
+func canonicalize(obj map[string]interface{}, objType string) {
+	if t, ok := obj["@type"].(string); ok {
+		objType = t
+	}
+	for key, value := range canonicalDefaults[objType] {
+		if current, ok := obj[key]; ok && reflect.DeepEqual(current, value) {
+			delete(obj, key)
+		}
+	}
+
+	for key, value := range obj {
+		switch v := value.(type) {
+		case string:
+			if canonicalUTCDateTimes[key] {
+				if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+					obj[key] = t.UTC().Format(time.RFC3339Nano)
+				}
+			}
+		case map[string]interface{}:
+			if memberType, ok := canonicalMembers[key]; ok {
+				for _, member := range v {
+					if m, ok := member.(map[string]interface{}); ok {
+						canonicalize(m, memberType)
+					}
+				}
+			} else {
+				canonicalize(v, "")
+			}
+		case []interface{}:
+			for _, item := range v {
+				if m, ok := item.(map[string]interface{}); ok {
+					canonicalize(m, canonicalMembers[key])
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package jscal
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCanonicalJSON(t *testing.T) {
+	created := time.Date(2025, 3, 1, 10, 0, 0, 0, time.FixedZone("CET", 3600))
+
+	event := &Event{Type: "Event", UID: "canonical", Created: NewUTCDateTime(created)}
+	event.Start = NewLocalDateTime(time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC))
+	event.Title = String("Review")
+	event.Duration = String("PT0S")
+	event.Status = String(StatusConfirmed)
+	event.Priority = Int(0)
+	event.AddCategory("b")
+	event.AddCategory("a")
+	event.AddParticipant("p1", NewParticipant("Jane", "jane@example.com"))
+	event.AddAlert("a1", &Alert{Type: "Alert", Trigger: NewOffsetTrigger("-PT5M"), Action: String(AlertActionDisplay)})
+
+	data, err := event.CanonicalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"@type":"Event",` +
+		`"alerts":{"a1":{"@type":"Alert","trigger":{"@type":"OffsetTrigger","offset":"-PT5M"}}},` +
+		`"categories":{"a":true,"b":true},"created":"2025-03-01T09:00:00Z",` +
+		`"participants":{"p1":{"email":"jane@example.com","name":"Jane"}},` +
+		`"start":"2025-03-03T09:00:00","title":"Review","uid":"canonical"}`
+	if string(data) != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, data)
+	}
+}
+
+func TestHash(t *testing.T) {
+	newEvent := func() *Event {
+		event := &Event{Type: "Event", UID: "hash"}
+		event.Start = NewLocalDateTime(time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC))
+		event.Title = String("Review")
+		return event
+	}
+
+	a, b := newEvent(), newEvent()
+	b.Privacy = String(PrivacyPublic) // The default
+	hashA, err := a.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashB, _ := b.Hash()
+	if hashA != hashB || len(hashA) != 64 {
+		t.Errorf("Expected equal SHA-256 hashes, got %s and %s", hashA, hashB)
+	}
+
+	b.Title = String("Other")
+	if hashB, _ = b.Hash(); hashA == hashB {
+		t.Error("Expected a different hash after changing the title")
+	}
+}
+
+func TestGroupCanonicalJSON(t *testing.T) {
+	group := &Group{Type: "Group", UID: "g", Entries: []CalendarObject{
+		&Task{Type: "Task", UID: "t", Title: String(""), Priority: Int(0)},
+	}}
+	data, err := group.CanonicalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "priority") || strings.Contains(string(data), "title") {
+		t.Errorf("Expected defaults in entries to be left out, got %s", data)
+	}
+}
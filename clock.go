@@ -0,0 +1,62 @@
+package jscal
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Clock supplies the current time wherever the package needs it: the
+// created and updated stamps of NewEvent, NewTask and Touch, NewUID,
+// snoozing alerts, IsOverdue and the DTSTAMP of converters
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts a function to a Clock
+type ClockFunc func() time.Time
+
+// Now returns f()
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// SystemClock is the default Clock, reading time.Now
+var SystemClock Clock = ClockFunc(time.Now)
+
+// FixedClock returns a Clock that always reports t, for tests and
+// reproducible output
+func FixedClock(t time.Time) Clock {
+	return ClockFunc(func() time.Time { return t })
+}
+
+// clockHolder wraps the current Clock so it can be swapped atomically
+type clockHolder struct {
+	clock Clock
+}
+
+var currentClock atomic.Pointer[clockHolder]
+
+// SetClock makes c the package's clock, SystemClock if nil, and returns a
+// function restoring the previous one:
+//
+//	defer jscal.SetClock(jscal.FixedClock(t))()
+//
+// The clock is shared by every goroutine, so tests setting it must not
+// run in parallel with others relying on it.
+func SetClock(c Clock) (restore func()) {
+	if c == nil {
+		c = SystemClock
+	}
+	previous := currentClock.Swap(&clockHolder{clock: c})
+	return func() {
+		currentClock.Store(previous)
+	}
+}
+
+// Now returns the current time of the package's clock
+func Now() time.Time {
+	if holder := currentClock.Load(); holder != nil {
+		return holder.clock.Now()
+	}
+	return time.Now()
+}
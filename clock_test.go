@@ -0,0 +1,48 @@
+package jscal
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSetClock(t *testing.T) {
+	now := time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+	restore := SetClock(FixedClock(now))
+
+	event := NewEvent("clock", "Planning")
+	if !event.Created.Time().Equal(now) || !event.Updated.Time().Equal(now) {
+		t.Errorf("Expected created and updated %v, got %v and %v", now, event.Created, event.Updated)
+	}
+	if event.Start.String() != "2025-03-01T09:30:00" {
+		t.Errorf("Expected start 2025-03-01T09:30:00, got %s", event.Start)
+	}
+
+	later := now.Add(time.Hour)
+	SetClock(ClockFunc(func() time.Time { return later }))
+	event.Touch()
+	if !event.Updated.Time().Equal(later) {
+		t.Errorf("Expected updated %v after Touch, got %v", later, event.Updated)
+	}
+
+	task := NewTask("clock-task", "Report")
+	task.Due = NewLocalDateTime(later.Add(-time.Minute))
+	if !task.IsOverdue() {
+		t.Error("Expected the task to be overdue on the injected clock")
+	}
+
+	// The UID timestamp comes from the clock too, 0x019551417840 ms
+	if uid := NewUID(); !strings.HasPrefix(uid, "01955141-7840-") {
+		t.Errorf("Expected a UID stamped with the clock, got %s", uid)
+	}
+
+	restore()
+	if Now().Sub(time.Now()).Abs() > time.Minute {
+		t.Errorf("Expected the system clock after restore, got %v", Now())
+	}
+
+	defer SetClock(nil)()
+	if Now().Sub(time.Now()).Abs() > time.Minute {
+		t.Errorf("Expected SetClock(nil) to use the system clock, got %v", Now())
+	}
+}
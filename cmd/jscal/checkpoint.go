@@ -0,0 +1,562 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/airtrafik/jscal"
+	"github.com/airtrafik/jscal/convert"
+)
+
+// checkpoint records the progress of a batch conversion so that an
+// interrupted run can be resumed without redoing or duplicating work.
+//
+// The checkpoint file is a journal of JSON lines: a header identifying the
+// conversion and its input, then one record per processed item, appended
+// as the item is done. Converted events are appended as JSON lines to the
+// partial output <output>.partial, which the output is written from once
+// every item is processed. Each record holds the size of the partial
+// output after its item, so events written after the last record are
+// dropped on resume instead of being duplicated.
+type checkpoint struct {
+	checkpointHeader
+
+	Processed []string          // Item keys in processing order
+	Failures  map[string]string // Errors by item key
+
+	path      string
+	done      map[string]bool // Keys of Processed
+	converted int             // Events in the partial output
+	offset    int64           // Size of the partial output after the last record
+	size      int64           // Size of the journal up to its last complete record
+	started   bool            // Whether the journal was written before
+	journal   *os.File
+	partial   *os.File
+}
+
+// checkpointHeader is the first line of a checkpoint file
+type checkpointHeader struct {
+	Input     string `json:"input"`
+	Output    string `json:"output"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	InputHash string `json:"inputHash"` // SHA-256 of the input data
+}
+
+// checkpointRecord is the journal line of one processed item
+type checkpointRecord struct {
+	Key    string `json:"key"`
+	Events int    `json:"events,omitempty"` // Events the item added to the partial output
+	Offset int64  `json:"offset,omitempty"` // Size of the partial output after the item
+	Error  string `json:"error,omitempty"`
+}
+
+// checkpointItem is a single unit of work in a batch conversion. Its key
+// is known before it is converted, so items a previous run handled are
+// skipped without converting them again.
+type checkpointItem struct {
+	key     string
+	convert func() ([]*jscal.Event, error)
+}
+
+// newCheckpoint creates an empty checkpoint for the given conversion of
+// inputData
+func newCheckpoint(path, input, output, from, to string, inputData []byte) *checkpoint {
+	return &checkpoint{
+		checkpointHeader: checkpointHeader{
+			Input:     input,
+			Output:    output,
+			From:      from,
+			To:        to,
+			InputHash: inputHash(inputData),
+		},
+		path: path,
+		done: make(map[string]bool),
+	}
+}
+
+// inputHash returns the hex-encoded SHA-256 of the input of a conversion
+func inputHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// loadCheckpoint reads a checkpoint file, returning nil if it does not
+// exist. A record cut short by an interruption is ignored.
+func loadCheckpoint(path string) (*checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	header, rest, ok := bytes.Cut(data, []byte("\n"))
+	cp := &checkpoint{path: path, done: make(map[string]bool), started: true}
+	if !ok || json.Unmarshal(header, &cp.checkpointHeader) != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint %s: missing header", path)
+	}
+	cp.size = int64(len(header) + 1)
+
+	for len(rest) > 0 {
+		line, next, ok := bytes.Cut(rest, []byte("\n"))
+		if !ok {
+			break
+		}
+		var record checkpointRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			return nil, fmt.Errorf("failed to parse checkpoint %s: %w", path, err)
+		}
+		cp.add(record)
+		cp.size += int64(len(line) + 1)
+		rest = next
+	}
+	return cp, nil
+}
+
+// matches reports whether the checkpoint was written for the same
+// conversion of the same input data
+func (cp *checkpoint) matches(input, output, from, to string, inputData []byte) bool {
+	return cp.Input == input && cp.Output == output &&
+		strings.EqualFold(cp.From, from) && strings.EqualFold(cp.To, to) &&
+		cp.InputHash == inputHash(inputData)
+}
+
+// partialPath returns the path of the partial output
+func (cp *checkpoint) partialPath() string {
+	return cp.Output + ".partial"
+}
+
+// open opens the journal and the partial output for appending. A new
+// checkpoint starts both afresh; a loaded one drops whatever was written
+// after its last complete record.
+func (cp *checkpoint) open() error {
+	if cp.journal != nil {
+		return nil
+	}
+
+	flags := os.O_RDWR | os.O_CREATE
+	if !cp.started {
+		flags |= os.O_TRUNC
+	}
+	journal, err := os.OpenFile(cp.path, flags, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open checkpoint: %w", err)
+	}
+	partial, err := os.OpenFile(cp.partialPath(), flags, 0644)
+	if err != nil {
+		journal.Close()
+		return fmt.Errorf("failed to open partial output: %w", err)
+	}
+	cp.journal, cp.partial = journal, partial
+
+	if cp.started {
+		if err := truncate(journal, cp.size); err != nil {
+			cp.close()
+			return fmt.Errorf("failed to resume checkpoint: %w", err)
+		}
+		if err := truncate(partial, cp.offset); err != nil {
+			cp.close()
+			return fmt.Errorf("failed to resume partial output: %w", err)
+		}
+		return nil
+	}
+
+	header, err := json.Marshal(cp.checkpointHeader)
+	if err != nil {
+		cp.close()
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+	if _, err := journal.Write(append(header, '\n')); err != nil {
+		cp.close()
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	cp.size = int64(len(header) + 1)
+	cp.started = true
+	return nil
+}
+
+// truncate cuts f to size and moves to its end for appending
+func truncate(f *os.File, size int64) error {
+	if err := f.Truncate(size); err != nil {
+		return err
+	}
+	_, err := f.Seek(size, io.SeekStart)
+	return err
+}
+
+// close closes the journal and the partial output
+func (cp *checkpoint) close() error {
+	var errs []error
+	for _, f := range []*os.File{cp.journal, cp.partial} {
+		if f != nil {
+			errs = append(errs, f.Close())
+		}
+	}
+	cp.journal, cp.partial = nil, nil
+	return errors.Join(errs...)
+}
+
+// remove deletes the checkpoint and the partial output
+func (cp *checkpoint) remove() error {
+	var errs []error
+	for _, path := range []string{cp.path, cp.partialPath()} {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// add applies a record to the checkpoint's state
+func (cp *checkpoint) add(record checkpointRecord) {
+	cp.Processed = append(cp.Processed, record.Key)
+	cp.done[record.Key] = true
+	if record.Error != "" {
+		if cp.Failures == nil {
+			cp.Failures = make(map[string]string)
+		}
+		cp.Failures[record.Key] = record.Error
+		return
+	}
+	cp.converted += record.Events
+	cp.offset = record.Offset
+}
+
+// isProcessed reports whether an item was handled by a previous run
+func (cp *checkpoint) isProcessed(key string) bool {
+	return cp.done[key]
+}
+
+// record appends the events of one item to the partial output and the
+// item's outcome to the journal
+func (cp *checkpoint) record(key string, events []*jscal.Event, err error) error {
+	if cp.isProcessed(key) {
+		return nil
+	}
+	if err := cp.open(); err != nil {
+		return err
+	}
+
+	record := checkpointRecord{Key: key, Offset: cp.offset}
+	if err == nil {
+		var lines []byte
+		for _, event := range events {
+			data, marshalErr := json.Marshal(event)
+			if marshalErr != nil {
+				err = fmt.Errorf("failed to encode event: %w", marshalErr)
+				break
+			}
+			lines = append(append(lines, data...), '\n')
+		}
+		if err == nil {
+			if _, err := cp.partial.Write(lines); err != nil {
+				return fmt.Errorf("failed to write partial output: %w", err)
+			}
+			record.Events = len(events)
+			record.Offset += int64(len(lines))
+		}
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+
+	data, marshalErr := json.Marshal(record)
+	if marshalErr != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", marshalErr)
+	}
+	if _, err := cp.journal.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	cp.size += int64(len(data) + 1)
+	cp.add(record)
+	return nil
+}
+
+// eachEvent calls fn with every line of the partial output, an event as
+// JSON, in processing order
+func (cp *checkpoint) eachEvent(fn func(line []byte) error) error {
+	f, err := os.Open(cp.partialPath())
+	if err != nil {
+		return fmt.Errorf("failed to read partial output: %w", err)
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(io.LimitReader(f, cp.offset))
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(line) > 0 {
+			if err := fn(bytes.TrimSuffix(line, []byte("\n"))); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read partial output: %w", err)
+		}
+	}
+}
+
+// events returns the successfully converted events in processing order
+func (cp *checkpoint) events() ([]*jscal.Event, error) {
+	events := make([]*jscal.Event, 0, cp.converted)
+	err := cp.eachEvent(func(line []byte) error {
+		var event jscal.Event
+		if err := json.Unmarshal(line, &event); err != nil {
+			return fmt.Errorf("failed to decode checkpointed event: %w", err)
+		}
+		events = append(events, &event)
+		return nil
+	})
+	return events, err
+}
+
+// writeOutput writes the output from the partial output. JSCalendar is
+// copied over one event at a time; other formats are formatted as a whole.
+func (cp *checkpoint) writeOutput(toFormat string) error {
+	if !isJSONFormat(toFormat) {
+		events, err := cp.events()
+		if err != nil {
+			return err
+		}
+		data, err := formatEvents(events, toFormat)
+		if err != nil {
+			return err
+		}
+		return writeFileAtomic(cp.Output, data)
+	}
+
+	// Indented as formatEvents does: a single event as an object, others
+	// as an array
+	return writeAtomic(cp.Output, func(w io.Writer) error {
+		if cp.converted == 0 {
+			_, err := io.WriteString(w, "[]")
+			return err
+		}
+		prefix, open, sep, end := "", "", "", ""
+		if cp.converted > 1 {
+			prefix, open, sep, end = "  ", "[\n  ", ",\n  ", "\n]"
+		}
+		var buf bytes.Buffer
+		first := true
+		err := cp.eachEvent(func(line []byte) error {
+			buf.Reset()
+			if first {
+				buf.WriteString(open)
+				first = false
+			} else {
+				buf.WriteString(sep)
+			}
+			if err := json.Indent(&buf, line, prefix, "  "); err != nil {
+				return fmt.Errorf("failed to decode checkpointed event: %w", err)
+			}
+			_, err := w.Write(buf.Bytes())
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, end)
+		return err
+	})
+}
+
+// checkpointItems splits the input into items converted one at a time, so
+// one bad item doesn't abort the batch. JSCalendar arrays are split into
+// their entries and formats whose converter is a convert.Splitter, such as
+// iCalendar, into its items; other formats are converted up front.
+func checkpointItems(inputData []byte, fromFormat string) ([]checkpointItem, error) {
+	if isJSONFormat(fromFormat) {
+		return jsonCheckpointItems(inputData)
+	}
+
+	converter, ok := convert.Lookup(fromFormat)
+	if !ok {
+		return nil, fmt.Errorf("unsupported input format: %s", fromFormat)
+	}
+	if splitter, ok := converter.(convert.Splitter); ok {
+		split, err := splitter.SplitItems(inputData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", fromFormat, err)
+		}
+		items := make([]checkpointItem, 0, len(split))
+		for _, item := range split {
+			items = append(items, checkpointItem{key: item.Key, convert: func() ([]*jscal.Event, error) {
+				return splitter.ParseItem(item)
+			}})
+		}
+		return items, nil
+	}
+
+	events, errs, err := convert.ParseAllLenient(converter, inputData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", fromFormat, err)
+	}
+	items := make([]checkpointItem, 0, len(events)+len(errs))
+	for _, event := range events {
+		items = append(items, checkpointItem{key: eventKey(event), convert: func() ([]*jscal.Event, error) {
+			return []*jscal.Event{event}, nil
+		}})
+	}
+	for _, itemErr := range errs {
+		items = append(items, checkpointItem{key: fmt.Sprintf("index:%d", itemErr.Index), convert: func() ([]*jscal.Event, error) {
+			return nil, itemErr
+		}})
+	}
+	return items, nil
+}
+
+// jsonCheckpointItems splits JSCalendar input into its array entries, or a
+// single item for an object. Entries are keyed by their uid and
+// recurrenceId, read without parsing the whole entry.
+func jsonCheckpointItems(inputData []byte) ([]checkpointItem, error) {
+	var raws []json.RawMessage
+	if !bytes.HasPrefix(bytes.TrimSpace(inputData), []byte("[")) {
+		raws = []json.RawMessage{inputData}
+	} else if err := json.Unmarshal(inputData, &raws); err != nil {
+		return nil, fmt.Errorf("failed to parse JSCalendar: %w", err)
+	}
+
+	items := make([]checkpointItem, 0, len(raws))
+	for i, raw := range raws {
+		items = append(items, checkpointItem{key: jsonItemKey(raw, i), convert: func() ([]*jscal.Event, error) {
+			event, err := jscal.ParseEvent(raw)
+			if err != nil {
+				return nil, err
+			}
+			return []*jscal.Event{event}, nil
+		}})
+	}
+	return items, nil
+}
+
+// jsonItemKey returns the key of a JSCalendar entry like eventKey, or
+// "index:<i>" if it has no uid
+func jsonItemKey(raw json.RawMessage, i int) string {
+	var ids struct {
+		UID          string `json:"uid"`
+		RecurrenceID string `json:"recurrenceId"`
+	}
+	if json.Unmarshal(raw, &ids) != nil || ids.UID == "" {
+		return fmt.Sprintf("index:%d", i)
+	}
+	if ids.RecurrenceID != "" {
+		return ids.UID + "#" + ids.RecurrenceID
+	}
+	return ids.UID
+}
+
+// eventKey identifies an event across runs. Recurrence instances share the
+// master's UID, so the recurrence ID is included when present.
+func eventKey(event *jscal.Event) string {
+	if event.RecurrenceId != nil {
+		return event.UID + "#" + event.RecurrenceId.String()
+	}
+	return event.UID
+}
+
+// convertWithCheckpoint converts the input item by item, skipping the items
+// recorded in cp and recording the others, and writes the output file from
+// all converted events
+func convertWithCheckpoint(inputData []byte, fromFormat, toFormat string, cp *checkpoint) (err error) {
+	items, err := checkpointItems(inputData, fromFormat)
+	if err != nil {
+		return err
+	}
+	if err := cp.open(); err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := cp.close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to close checkpoint: %w", closeErr)
+		}
+	}()
+
+	seen := make(map[string]bool, len(items))
+	for i, item := range items {
+		// Items repeating the key of an earlier one, such as an event
+		// listed twice in the input, fail under a key of their own, so a
+		// resumed run reports them again instead of dropping them
+		if seen[item.key] {
+			key := duplicateKey(item.key, i)
+			if cp.isProcessed(key) {
+				continue
+			}
+			if err := cp.record(key, nil, fmt.Errorf("duplicate of an earlier item with key %s", item.key)); err != nil {
+				return err
+			}
+			continue
+		}
+		seen[item.key] = true
+
+		if cp.isProcessed(item.key) {
+			continue
+		}
+		events, err := item.convert()
+		if err := cp.record(item.key, events, err); err != nil {
+			return err
+		}
+	}
+
+	if err := cp.partial.Sync(); err != nil {
+		return fmt.Errorf("failed to write partial output: %w", err)
+	}
+	return cp.writeOutput(toFormat)
+}
+
+// duplicateKey returns the key recording item i of the input, whose own key
+// an earlier item already has
+func duplicateKey(key string, i int) string {
+	return fmt.Sprintf("%s (item %d)", key, i+1)
+}
+
+// writeFileAtomic writes data to a temporary file and renames it into place,
+// so readers never observe a partially written file
+func writeFileAtomic(filename string, data []byte) error {
+	return writeAtomic(filename, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
+
+// writeAtomic is writeFileAtomic for output produced by write
+func writeAtomic(filename string, write func(w io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".tmp*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+
+	w := bufio.NewWriter(tmp)
+	if err := write(w); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	if err := os.Chmod(tmpName, 0644); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	return os.Rename(tmpName, filename)
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/airtrafik/jscal"
+)
+
+const checkpointCalendar = "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//Test//EN\r\n" +
+	"BEGIN:VEVENT\r\nUID:first\r\nDTSTART:20250303T090000Z\r\nSUMMARY:First\r\nEND:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\nDTSTART:20250303T090000Z\r\nSUMMARY:No UID\r\nEND:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\nUID:last\r\nDTSTART:20250305T090000Z\r\nSUMMARY:Last\r\nEND:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+const checkpointJSON = `[
+  {"@type": "Event", "uid": "first", "title": "First", "start": "2025-03-03T09:00:00"},
+  {"@type": "Event", "uid": "broken", "start": "not a date"},
+  {"@type": "Event", "uid": "last", "title": "Last", "start": "2025-03-05T09:00:00"}
+]`
+
+func TestConvertWithCheckpoint(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		from   string
+		broken string
+	}{
+		{"icalendar", checkpointCalendar, "ical", "index:1"},
+		{"jscalendar", checkpointJSON, "json", "broken"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cp := newTestCheckpoint(t, tt.from, []byte(tt.input))
+			output := runCheckpoint(t, []byte(tt.input), tt.from, cp)
+
+			// One bad item doesn't abort the batch
+			if strings.Join(cp.Processed, ",") != "first,"+tt.broken+",last" {
+				t.Errorf("Expected first, %s and last to be processed, got %v", tt.broken, cp.Processed)
+			}
+			if _, ok := cp.Failures[tt.broken]; !ok || len(cp.Failures) != 1 {
+				t.Errorf("Expected only %s to fail, got %v", tt.broken, cp.Failures)
+			}
+			events := decodeEvents(t, output)
+			if len(events) != 2 || events[0].UID != "first" || events[1].UID != "last" {
+				t.Errorf("Expected first and last in the output, got %s", output)
+			}
+		})
+	}
+}
+
+func TestConvertWithCheckpointResume(t *testing.T) {
+	cp := newTestCheckpoint(t, "ical", []byte(checkpointCalendar))
+
+	// A previous run recorded first, as it converted then, and the event
+	// without UID
+	earlier := jscal.NewEvent("first", "First, as converted before")
+	if err := cp.record("first", []*jscal.Event{earlier}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := cp.record("index:1", nil, errTest); err != nil {
+		t.Fatal(err)
+	}
+	if err := cp.close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Events written after the last record, by an interrupted run, are
+	// dropped; so is a record cut short
+	appendFile(t, cp.partialPath(), `{"@type":"Event","uid":"last","title":"Interrupted"}`+"\n")
+	appendFile(t, cp.path, `{"key":"la`)
+
+	loaded, err := loadCheckpoint(cp.path)
+	if err != nil || loaded == nil {
+		t.Fatalf("loadCheckpoint failed: %v", err)
+	}
+	if !loaded.matches("in", cp.Output, "ical", "json", []byte(checkpointCalendar)) {
+		t.Fatal("Expected the checkpoint to match its conversion")
+	}
+	output := runCheckpoint(t, []byte(checkpointCalendar), "ical", loaded)
+
+	// Recorded items aren't converted again
+	events := decodeEvents(t, output)
+	if len(events) != 2 || events[0].GetTitle() != "First, as converted before" || events[1].UID != "last" {
+		t.Errorf("Expected the recorded first and a converted last, got %s", output)
+	}
+	if loaded.Failures["index:1"] != errTest.Error() {
+		t.Errorf("Expected the recorded failure of index:1, got %q", loaded.Failures["index:1"])
+	}
+	if strings.Join(loaded.Processed, ",") != "first,index:1,last" {
+		t.Errorf("Expected last to be processed after the recorded items, got %v", loaded.Processed)
+	}
+}
+
+func TestCheckpointEventsOfItem(t *testing.T) {
+	cp := newTestCheckpoint(t, "ical", nil)
+	defer cp.close()
+	orphans := []*jscal.Event{jscal.NewEvent("series", "Monday"), jscal.NewEvent("series", "Tuesday")}
+	if err := cp.record("series", orphans, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := cp.events()
+	if err != nil {
+		t.Fatalf("events failed: %v", err)
+	}
+	if len(events) != 2 || events[0].GetTitle() != "Monday" || events[1].GetTitle() != "Tuesday" {
+		t.Errorf("Expected both events of the item, got %d", len(events))
+	}
+}
+
+func TestJSONItemKey(t *testing.T) {
+	tests := []struct {
+		raw      string
+		expected string
+	}{
+		{`{"uid": "a"}`, "a"},
+		{`{"uid": "a", "recurrenceId": "2025-03-03T09:00:00"}`, "a#2025-03-03T09:00:00"},
+		{`{"title": "No UID"}`, "index:4"},
+		{`not json`, "index:4"},
+	}
+	for _, tt := range tests {
+		if got := jsonItemKey(json.RawMessage(tt.raw), 4); got != tt.expected {
+			t.Errorf("jsonItemKey(%s): expected %q, got %q", tt.raw, tt.expected, got)
+		}
+	}
+}
+
+func TestCheckpointMatchesInput(t *testing.T) {
+	cp := newTestCheckpoint(t, "ical", []byte(checkpointCalendar))
+	if !cp.matches("in", cp.Output, "ical", "json", []byte(checkpointCalendar)) {
+		t.Error("Expected the checkpoint to match its conversion")
+	}
+	changed := strings.Replace(checkpointCalendar, "SUMMARY:Last", "SUMMARY:Changed", 1)
+	if cp.matches("in", cp.Output, "ical", "json", []byte(changed)) {
+		t.Error("Expected the checkpoint not to match changed input")
+	}
+	if cp.matches("in", cp.Output, "json", "json", []byte(checkpointCalendar)) {
+		t.Error("Expected the checkpoint not to match another input format")
+	}
+}
+
+// newTestCheckpoint returns a checkpoint for converting input in format
+// from to JSCalendar in a temporary directory
+func newTestCheckpoint(t *testing.T, from string, input []byte) *checkpoint {
+	t.Helper()
+	dir := t.TempDir()
+	return newCheckpoint(filepath.Join(dir, "out.checkpoint"), "in", filepath.Join(dir, "out.json"), from, "json", input)
+}
+
+// runCheckpoint converts input with cp and returns the output file
+func runCheckpoint(t *testing.T, input []byte, from string, cp *checkpoint) []byte {
+	t.Helper()
+	if err := convertWithCheckpoint(input, from, "json", cp); err != nil {
+		t.Fatalf("convertWithCheckpoint failed: %v", err)
+	}
+	output, err := os.ReadFile(cp.Output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return output
+}
+
+// appendFile appends data to a file
+func appendFile(t *testing.T, path, data string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// errTest is the failure recorded by tests
+var errTest = errors.New("invalid event")
+
+// decodeEvents decodes the JSCalendar output of a conversion
+func decodeEvents(t *testing.T, output []byte) []*jscal.Event {
+	t.Helper()
+	var events []*jscal.Event
+	if err := json.Unmarshal(output, &events); err != nil {
+		t.Fatalf("Output is not a JSCalendar array: %v\n%s", err, output)
+	}
+	return events
+}
+
+func TestConvertWithCheckpointDuplicates(t *testing.T) {
+	input := `[
+  {"@type": "Event", "uid": "first", "title": "First", "start": "2025-03-03T09:00:00"},
+  {"@type": "Event", "uid": "first", "title": "First again", "start": "2025-03-04T09:00:00"},
+  {"@type": "Event", "uid": "last", "title": "Last", "start": "2025-03-05T09:00:00"}
+]`
+	cp := newTestCheckpoint(t, "json", []byte(input))
+	output := runCheckpoint(t, []byte(input), "json", cp)
+
+	if strings.Join(cp.Processed, ",") != "first,first (item 2),last" {
+		t.Errorf("Expected the duplicate to be processed, got %v", cp.Processed)
+	}
+	if msg := cp.Failures["first (item 2)"]; !strings.Contains(msg, "duplicate") || len(cp.Failures) != 1 {
+		t.Errorf("Expected the duplicate to fail, got %v", cp.Failures)
+	}
+	if events := decodeEvents(t, output); len(events) != 2 || events[0].GetTitle() != "First" {
+		t.Errorf("Expected first and last in the output, got %s", output)
+	}
+
+	// A resumed run keeps the recorded failure
+	loaded, err := loadCheckpoint(cp.path)
+	if err != nil || loaded == nil {
+		t.Fatalf("loadCheckpoint failed: %v", err)
+	}
+	if events := decodeEvents(t, runCheckpoint(t, []byte(input), "json", loaded)); len(events) != 2 {
+		t.Errorf("Expected the resumed run to write first and last, got %d events", len(events))
+	}
+	if len(loaded.Processed) != 3 || len(loaded.Failures) != 1 {
+		t.Errorf("Expected the resumed run to keep the duplicate's failure, got %v and %v", loaded.Processed, loaded.Failures)
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/airtrafik/jscal"
+)
+
+// eventDiff is the difference for one UID between two files
+type eventDiff struct {
+	UID     string              `json:"uid"`
+	Status  string              `json:"status"` // added, removed or changed
+	Title   string              `json:"title"`
+	Changes []jscal.FieldChange `json:"changes,omitempty"`
+}
+
+func handleDiff(args []string) {
+	var files []string
+	format := "text"
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--format":
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: --format requires a value\n")
+				os.Exit(1)
+			}
+			i++
+			format = args[i]
+		default:
+			files = append(files, args[i])
+		}
+	}
+	if len(files) != 2 {
+		fmt.Fprintf(os.Stderr, "Error: two files are required\n")
+		os.Exit(1)
+	}
+	if format != "text" && format != "json" {
+		fmt.Fprintf(os.Stderr, "Error: unsupported format %s (use text or json)\n", format)
+		os.Exit(1)
+	}
+
+	diffs, err := diffFiles(files[0], files[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+	if err := printDiffs(diffs, format); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
+	// Like diff(1): 1 if the files differ
+	if len(diffs) > 0 {
+		os.Exit(1)
+	}
+}
+
+// diffFiles compares the events of two files by UID. Events only in the
+// first file are removed, those only in the second added; the rest are
+// compared field by field. Results follow the order of the second file,
+// followed by removed events.
+func diffFiles(oldFile, newFile string) ([]eventDiff, error) {
+	before, err := readEventsByUID(oldFile)
+	if err != nil {
+		return nil, err
+	}
+	after, err := readEventsByUID(newFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []eventDiff
+	seen := make(map[string]bool, len(after.order))
+	for _, uid := range after.order {
+		seen[uid] = true
+		event := after.events[uid]
+		old, ok := before.events[uid]
+		if !ok {
+			diffs = append(diffs, eventDiff{UID: uid, Status: "added", Title: event.EffectiveTitle("")})
+			continue
+		}
+		changes, err := old.Diff(event)
+		if err != nil {
+			return nil, fmt.Errorf("comparing %s: %w", uid, err)
+		}
+		if len(changes) > 0 {
+			diffs = append(diffs, eventDiff{UID: uid, Status: "changed", Title: event.EffectiveTitle(""), Changes: changes})
+		}
+	}
+	for _, uid := range before.order {
+		if !seen[uid] {
+			diffs = append(diffs, eventDiff{UID: uid, Status: "removed", Title: before.events[uid].EffectiveTitle("")})
+		}
+	}
+	return diffs, nil
+}
+
+// eventsByUID holds the events of a file keyed by UID, in file order
+type eventsByUID struct {
+	order  []string
+	events map[string]*jscal.Event
+}
+
+// readEventsByUID reads a file in any supported format, rejecting
+// duplicate UIDs, which couldn't be matched up
+func readEventsByUID(filename string) (*eventsByUID, error) {
+	data, err := readFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	events, err := parseEvents(data, detectFormat(data, filepath.Ext(filename)))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+
+	result := &eventsByUID{events: make(map[string]*jscal.Event, len(events))}
+	for _, event := range events {
+		if _, ok := result.events[event.UID]; ok {
+			return nil, fmt.Errorf("%s: duplicate UID '%s'", filename, event.UID)
+		}
+		result.order = append(result.order, event.UID)
+		result.events[event.UID] = event
+	}
+	return result, nil
+}
+
+// printDiffs writes the differences as JSON or as "+ added", "- removed" and
+// "~ changed" lines, each change indented below its event
+func printDiffs(diffs []eventDiff, format string) error {
+	if format == "json" {
+		if diffs == nil {
+			diffs = []eventDiff{}
+		}
+		data, err := json.MarshalIndent(diffs, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	marks := map[string]string{"added": "+", "removed": "-", "changed": "~"}
+	for _, d := range diffs {
+		fmt.Printf("%s %s %s\n", marks[d.Status], d.UID, d.Title)
+		for _, change := range d.Changes {
+			fmt.Printf("    %s\n", change)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/airtrafik/jscal"
+)
+
+// expandedOccurrence is an occurrence as printed by "jscal expand --format jsonl"
+type expandedOccurrence struct {
+	UID          string              `json:"uid"`
+	RecurrenceId jscal.LocalDateTime `json:"recurrenceId"`
+	Start        jscal.LocalDateTime `json:"start"`
+	End          jscal.LocalDateTime `json:"end"`
+	TimeZone     *string             `json:"timeZone,omitempty"`
+	Title        string              `json:"title"`
+	Overridden   []string            `json:"overridden,omitempty"`
+}
+
+func handleExpand(args []string) {
+	var filename, fromArg, toArg string
+	format := "table"
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--from", "--to", "--format":
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: %s requires a value\n", args[i])
+				os.Exit(1)
+			}
+			switch args[i] {
+			case "--from":
+				fromArg = args[i+1]
+			case "--to":
+				toArg = args[i+1]
+			default:
+				format = args[i+1]
+			}
+			i++
+		default:
+			filename = args[i]
+		}
+	}
+	if filename == "" {
+		filename = "-"
+	}
+	if format != "table" && format != "jsonl" {
+		fmt.Fprintf(os.Stderr, "Error: unsupported format %s (use table or jsonl)\n", format)
+		os.Exit(1)
+	}
+
+	if err := expand(filename, fromArg, toArg, format); err != nil {
+		fmt.Fprintf(os.Stderr, "Error expanding %s: %v\n", filename, err)
+		os.Exit(1)
+	}
+}
+
+// expand prints the occurrences of the events in filename between from
+// and to. from defaults to each event's start and to to a year after from;
+// a date without a time for to includes that whole day.
+func expand(filename, fromArg, toArg, format string) error {
+	data, err := readFile(filename)
+	if err != nil {
+		return err
+	}
+	events, err := parseEvents(data, detectFormat(data, filepath.Ext(filename)))
+	if err != nil {
+		return err
+	}
+
+	var from, to *jscal.LocalDateTime
+	if fromArg != "" {
+		if from, err = parseBound(fromArg, false); err != nil {
+			return fmt.Errorf("invalid --from: %w", err)
+		}
+	}
+	if toArg != "" {
+		if to, err = parseBound(toArg, true); err != nil {
+			return fmt.Errorf("invalid --to: %w", err)
+		}
+	}
+
+	var occurrences []expandedOccurrence
+	for _, event := range events {
+		lower, upper := from, to
+		if lower == nil {
+			lower = event.Start
+		}
+		if lower == nil {
+			return fmt.Errorf("event %s has no start", event.UID)
+		}
+		if upper == nil {
+			end := jscal.LocalDateTime(lower.Time().AddDate(1, 0, 0))
+			upper = &end
+		}
+
+		expanded, err := event.Occurrences(*lower, *upper)
+		if err != nil {
+			return err
+		}
+		for _, o := range expanded {
+			occurrences = append(occurrences, expandedOccurrence{
+				UID:          o.Event.UID,
+				RecurrenceId: o.RecurrenceId,
+				Start:        o.Start(),
+				End:          o.End(),
+				TimeZone:     o.Event.TimeZone,
+				Title:        o.Event.EffectiveTitle(""),
+				Overridden:   o.Overridden,
+			})
+		}
+	}
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		return occurrences[i].Start.String() < occurrences[j].Start.String()
+	})
+
+	if format == "jsonl" {
+		encoder := json.NewEncoder(os.Stdout)
+		for _, o := range occurrences {
+			if err := encoder.Encode(o); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "START\tEND\tTIME ZONE\tTITLE\tOVERRIDDEN")
+	for _, o := range occurrences {
+		timeZone := "-"
+		if o.TimeZone != nil {
+			timeZone = *o.TimeZone
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", o.Start, o.End, timeZone, o.Title, strings.Join(o.Overridden, ", "))
+	}
+	return w.Flush()
+}
+
+// parseBound parses a --from or --to value, either a date or a local
+// date-time. A date given as an upper bound means the end of that day.
+func parseBound(s string, upper bool) (*jscal.LocalDateTime, error) {
+	if date, err := time.Parse("2006-01-02", s); err == nil {
+		if upper {
+			date = date.AddDate(0, 0, 1)
+		}
+		return jscal.NewLocalDateTime(date), nil
+	}
+	return jscal.ParseLocalDateTime(s)
+}
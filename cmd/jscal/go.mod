@@ -8,3 +8,8 @@ require (
 )
 
 require github.com/arran4/golang-ical v0.3.2 // indirect
+
+replace (
+	github.com/airtrafik/jscal => ../..
+	github.com/airtrafik/jscal/convert/ical => ../../convert/ical
+)
@@ -2,15 +2,22 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/airtrafik/jscal"
-	"github.com/airtrafik/jscal/convert/ical"
+	"github.com/airtrafik/jscal/convert"
+	"github.com/airtrafik/jscal/feed"
+
+	// Converter modules register their formats with the convert package
+	_ "github.com/airtrafik/jscal/convert/ical"
 )
 
 const version = "0.2.0"
@@ -31,11 +38,28 @@ func main() {
 		handleValidate(args)
 	case "format":
 		handleFormat(args)
+	case "fix":
+		handleFix(args)
+	case "subscribe":
+		handleSubscribe(args)
+	case "expand":
+		handleExpand(args)
+	case "diff":
+		handleDiff(args)
+	case "merge":
+		handleMerge(args)
+	case "stats":
+		handleStats(args)
+	case "schema":
+		os.Stdout.Write(jscal.Schema())
 	case "version":
 		fmt.Printf("jscal version %s\n", version)
 	case "help", "-h", "--help":
 		printUsage()
 	default:
+		if plugin, ok := findPlugin(command); ok {
+			os.Exit(runPlugin(plugin, args))
+		}
 		fmt.Fprintf(os.Stderr, "Unknown command: %s\n", command)
 		printUsage()
 		os.Exit(1)
@@ -52,6 +76,13 @@ COMMANDS:
     convert     Convert between calendar formats
     validate    Validate JSCalendar files
     format      Pretty-print JSCalendar files
+    fix         Repair common issues in JSCalendar files
+    subscribe   Fetch an iCalendar feed and show what changed
+    expand      List the occurrences of recurring events
+    diff        Show field-level differences between two calendar files
+    merge       Combine calendar files, keeping the latest version of each UID
+    stats       Summarize the events and tasks of a calendar file
+    schema      Print the JSON Schema for JSCalendar objects
     version     Show version information
     help        Show this help message
 
@@ -59,25 +90,120 @@ CONVERT USAGE:
     jscal convert <input> <output>           Auto-detect format and convert
     jscal convert -f ical <input> <output>   Convert from iCalendar to JSCalendar
     jscal convert -t ical <input> <output>   Convert JSCalendar to iCalendar
+    jscal convert <dir|glob> <output>        Combine the files of a directory or "*.json" into one
+    jscal convert --split-by uid <input> -o <dir>
+                                             Write one file per UID into <dir> (format from -t, default json)
+
+CONVERT OPTIONS:
+    -o, --output <file>                      Output file, or directory with --split-by
+    --checkpoint <file>                      Record progress in <file> (default: <output>.checkpoint)
+    --resume                                 Continue an interrupted conversion from its checkpoint
+    --keep-going                             Skip events that fail to parse and report them on stderr
 
 VALIDATE USAGE:
     jscal validate <file>...                 Validate JSCalendar files
+    jscal validate --strict <file>...        Treat warnings as errors
 
 FORMAT USAGE:
     jscal format <file>...                   Pretty-print JSCalendar files
+    jscal format < event.json                Pretty-print stdin
+
+FORMAT OPTIONS:
+    -o <file>                                Write to <file> instead of stdout
+    -i, --in-place                           Rewrite each file in place
+    -t, --to <format>                        Output format, e.g. ical (default: json, or from -o)
+
+FIX USAGE:
+    jscal fix <file>...                      Print repaired events, report changes on stderr
+    jscal fix -w <file>...                   Rewrite files in place
+
+FIX OPTIONS:
+    --no-trim                                Keep over-long title and description values
+    --no-dedupe                              Keep participants that share an email address
+
+SUBSCRIBE USAGE:
+    jscal subscribe <url>                    Print the events of a webcal/https feed
+    jscal subscribe --state <file> <url>     Print changes since the fetch saved in <file>
+
+EXPAND USAGE:
+    jscal expand <file>                      List occurrences in the year after each event's start
+    jscal expand --from <date> --to <date> <file>
+                                             List occurrences between two dates, inclusive
+
+EXPAND OPTIONS:
+    --format table|jsonl                     Print a table (default) or one JSON object per line
+
+DIFF USAGE:
+    jscal diff <old> <new>                   List added, removed and changed events by UID
+    jscal diff --format json <old> <new>     Print the differences as JSON
+                                             Exits 1 if the files differ, 2 on error
+
+MERGE USAGE:
+    jscal merge <file>... -o <output>        Merge files of any format into <output> (default: stdout)
+    jscal merge --group <uid> <file>...      Output a Group with the given UID instead of an array
+                                             Duplicate UIDs keep the highest sequence, then the latest updated
+
+STATS USAGE:
+    jscal stats <file>                       Print counts, date span, busiest weekdays and hours,
+                                             top categories and participants, average duration
+
+STATS OPTIONS:
+    --from <date>, --to <date>               Expand recurring events in this range
+                                             (default: from the first start to a year after the last)
+    --tz <zone>                              Read floating times, days and hours in this zone (default: local)
+    --top <n>                                Number of entries in each ranking (default: 5)
 
 EXAMPLES:
     jscal convert calendar.ics calendar.json
     jscal convert -t ical event.json event.ics
+    jscal convert --resume export.ics export.json
+    jscal convert big.ics --split-by uid -o events/
+    jscal convert 'events/*.json' calendar.ics
     jscal validate events.json
     jscal format messy.json
-
+    curl -s https://example.com/event.json | jscal format --to ical
+    jscal fix -w export.json
+    jscal subscribe --state holidays.state webcal://example.com/holidays.ics
+    jscal expand standup.json --from 2025-01-01 --to 2025-03-31
+    jscal diff before.json after.json
+    jscal merge work.json personal.ics -o combined.json
+    jscal stats export.ics
+    jscal schema > jscalendar.schema.json
+
+PIPES:
+    A file argument of "-" reads stdin or writes stdout. validate, format,
+    fix, expand and stats read stdin when no file is given.
+
+PLUGINS:
+    Unknown commands run jscal-<command> from PATH, passing arguments,
+    stdin and stdout through, so "jscal foo" runs "jscal-foo".
 `, version)
+
+	if plugins := listPlugins(); len(plugins) > 0 {
+		fmt.Printf("    Installed: %s\n", strings.Join(plugins, ", "))
+	}
+	fmt.Println()
+	printFormats()
+}
+
+// printFormats lists JSCalendar and the formats of the registered
+// converters, with their aliases and file extensions
+func printFormats() {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FORMATS:")
+	fmt.Fprintln(w, "    json\tjscal, jscalendar\t.json\tJSCalendar (RFC 8984)")
+	for _, r := range convert.ListFormats() {
+		fmt.Fprintf(w, "    %s\t%s\t%s\t%s\n", r.Name, strings.Join(r.Aliases, ", "), strings.Join(r.Extensions, ", "), r.Description)
+	}
+	w.Flush()
+	fmt.Println()
 }
 
 func handleConvert(args []string) {
 	var fromFormat, toFormat string
 	var inputFile, outputFile string
+	var checkpointFile, splitBy string
+	var resume, keepGoing bool
 
 	// Parse flags
 	i := 0
@@ -98,6 +224,41 @@ func handleConvert(args []string) {
 			}
 			toFormat = args[i+1]
 			i += 2
+		case "--checkpoint":
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: %s requires a value\n", arg)
+				os.Exit(1)
+			}
+			checkpointFile = args[i+1]
+			i += 2
+		case "-o", "--output":
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: %s requires a value\n", arg)
+				os.Exit(1)
+			}
+			if outputFile != "" {
+				fmt.Fprintf(os.Stderr, "Error: unexpected argument %s\n", arg)
+				os.Exit(1)
+			}
+			outputFile = args[i+1]
+			i += 2
+		case "--split-by":
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: %s requires a value\n", arg)
+				os.Exit(1)
+			}
+			splitBy = args[i+1]
+			if splitBy != "uid" {
+				fmt.Fprintf(os.Stderr, "Error: unsupported --split-by %s, only uid is supported\n", splitBy)
+				os.Exit(1)
+			}
+			i += 2
+		case "--resume":
+			resume = true
+			i++
+		case "--keep-going":
+			keepGoing = true
+			i++
 		default:
 			if inputFile == "" {
 				inputFile = arg
@@ -121,6 +282,20 @@ func handleConvert(args []string) {
 		os.Exit(1)
 	}
 
+	files, err := inputFiles(inputFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		os.Exit(1)
+	}
+	if (files != nil || splitBy != "") && (resume || checkpointFile != "") {
+		fmt.Fprintf(os.Stderr, "Error: --checkpoint and --resume convert a single file to a single file\n")
+		os.Exit(1)
+	}
+	if files != nil || splitBy != "" {
+		handleMultiFileConvert(inputFile, files, outputFile, fromFormat, toFormat, splitBy, keepGoing)
+		return
+	}
+
 	// Read input file
 	inputData, err := readFile(inputFile)
 	if err != nil {
@@ -136,8 +311,18 @@ func handleConvert(args []string) {
 		toFormat = detectFormat(nil, filepath.Ext(outputFile))
 	}
 
+	if resume || checkpointFile != "" {
+		handleCheckpointedConvert(inputData, inputFile, outputFile, fromFormat, toFormat, checkpointFile, resume)
+		return
+	}
+
 	// Convert
-	outputData, err := convert(inputData, fromFormat, toFormat)
+	var outputData []byte
+	if keepGoing {
+		outputData, err = convertDataLenient(inputData, inputFile, fromFormat, toFormat)
+	} else {
+		outputData, err = convertData(inputData, fromFormat, toFormat)
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error converting: %v\n", err)
 		os.Exit(1)
@@ -152,18 +337,103 @@ func handleConvert(args []string) {
 	fmt.Printf("Successfully converted %s to %s\n", inputFile, outputFile)
 }
 
-func handleValidate(args []string) {
-	if len(args) == 0 {
-		fmt.Fprintf(os.Stderr, "Error: at least one file is required\n")
+func handleCheckpointedConvert(inputData []byte, inputFile, outputFile, fromFormat, toFormat, checkpointFile string, resume bool) {
+	if outputFile == "-" {
+		fmt.Fprintf(os.Stderr, "Error: --checkpoint and --resume require an output file\n")
+		os.Exit(1)
+	}
+	if checkpointFile == "" {
+		checkpointFile = outputFile + ".checkpoint"
+	}
+
+	var cp *checkpoint
+	if resume {
+		loaded, err := loadCheckpoint(checkpointFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if loaded != nil {
+			if !loaded.matches(inputFile, outputFile, fromFormat, toFormat, inputData) {
+				fmt.Fprintf(os.Stderr, "Error: checkpoint %s was created for a different conversion or input (%s -> %s)\n",
+					checkpointFile, loaded.Input, loaded.Output)
+				os.Exit(1)
+			}
+			fmt.Printf("Resuming from %s: %d items already processed\n", checkpointFile, len(loaded.Processed))
+			cp = loaded
+		}
+	}
+	if cp == nil {
+		cp = newCheckpoint(checkpointFile, inputFile, outputFile, fromFormat, toFormat, inputData)
+	}
+
+	if err := convertWithCheckpoint(inputData, fromFormat, toFormat, cp); err != nil {
+		fmt.Fprintf(os.Stderr, "Error converting: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Progress saved to %s, rerun with --resume to continue\n", checkpointFile)
+		os.Exit(1)
+	}
+
+	if len(cp.Failures) > 0 {
+		for _, key := range cp.Processed {
+			if msg, ok := cp.Failures[key]; ok {
+				fmt.Fprintf(os.Stderr, "❌ %s: %s\n", key, msg)
+			}
+		}
+		fmt.Fprintf(os.Stderr, "Converted %d items to %s, %d failed (details kept in %s)\n",
+			len(cp.Processed)-len(cp.Failures), outputFile, len(cp.Failures), checkpointFile)
 		os.Exit(1)
 	}
 
+	if err := cp.remove(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to remove checkpoint: %v\n", err)
+	}
+
+	fmt.Printf("Successfully converted %s to %s\n", inputFile, outputFile)
+}
+
+func handleValidate(args []string) {
+	var strict bool
+	var files []string
+	for _, arg := range args {
+		switch arg {
+		case "--strict":
+			strict = true
+		default:
+			files = append(files, arg)
+		}
+	}
+
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
 	var hasErrors bool
-	for _, filename := range args {
-		if err := validateFile(filename); err != nil {
+	for _, filename := range files {
+		reports, err := validateFile(filename)
+		if err != nil {
 			fmt.Fprintf(os.Stderr, "❌ %s: %v\n", filename, err)
 			hasErrors = true
-		} else {
+			continue
+		}
+
+		var issues, errorCount, warningCount int
+		for _, report := range reports {
+			issues += len(report.Issues)
+			errorCount += report.Count(jscal.SeverityError)
+			warningCount += report.Count(jscal.SeverityWarning)
+		}
+		if issues > 0 {
+			printReports(reports)
+		}
+
+		failed := errorCount > 0 || (strict && warningCount > 0)
+		switch {
+		case failed:
+			fmt.Fprintf(os.Stderr, "❌ %s: %d errors, %d warnings\n", filename, errorCount, warningCount)
+			hasErrors = true
+		case warningCount > 0:
+			fmt.Printf("✅ %s: valid (%d warnings)\n", filename, warningCount)
+		default:
 			fmt.Printf("✅ %s: valid\n", filename)
 		}
 	}
@@ -173,32 +443,234 @@ func handleValidate(args []string) {
 	}
 }
 
+// printReports renders validation reports as a table
+func printReports(reports []*jscal.ValidationReport) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "UID\tSEVERITY\tPOINTER\tSECTION\tMESSAGE")
+	for _, report := range reports {
+		for _, issue := range report.Issues {
+			pointer := issue.Pointer
+			if pointer == "" {
+				pointer = "/"
+			}
+			section := "-"
+			if issue.Section != "" {
+				section = "RFC 8984 §" + issue.Section
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", report.UID, issue.Severity, pointer, section, issue.Message)
+		}
+	}
+	w.Flush()
+}
+
 func handleFormat(args []string) {
-	if len(args) == 0 {
-		fmt.Fprintf(os.Stderr, "Error: at least one file is required\n")
+	var outputFile, toFormat string
+	var inPlace bool
+	var files []string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "-o", "--output", "-t", "--to":
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: %s requires a value\n", args[i])
+				os.Exit(1)
+			}
+			if args[i] == "-o" || args[i] == "--output" {
+				outputFile = args[i+1]
+			} else {
+				toFormat = args[i+1]
+			}
+			i++
+		case "-i", "--in-place":
+			inPlace = true
+		default:
+			files = append(files, args[i])
+		}
+	}
+
+	// Read stdin when no file is given, so format works in pipelines
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	switch {
+	case inPlace && outputFile != "":
+		fmt.Fprintf(os.Stderr, "Error: --in-place and -o cannot be combined\n")
+		os.Exit(1)
+	case outputFile != "" && len(files) > 1:
+		fmt.Fprintf(os.Stderr, "Error: -o requires a single input file\n")
 		os.Exit(1)
 	}
+	if toFormat == "" && outputFile != "" {
+		toFormat = detectFormat(nil, filepath.Ext(outputFile))
+	}
 
-	for _, filename := range args {
-		if err := formatFile(filename); err != nil {
+	for _, filename := range files {
+		if inPlace && filename == "-" {
+			fmt.Fprintf(os.Stderr, "Error: cannot rewrite stdin in place\n")
+			os.Exit(1)
+		}
+		if err := formatFile(filename, outputFile, toFormat, inPlace); err != nil {
 			fmt.Fprintf(os.Stderr, "Error formatting %s: %v\n", filename, err)
 			os.Exit(1)
 		}
 	}
 }
 
-func convert(inputData []byte, fromFormat, toFormat string) ([]byte, error) {
+func handleFix(args []string) {
+	var opts jscal.NormalizeOptions
+	var write bool
+	var files []string
+	for _, arg := range args {
+		switch arg {
+		case "-w", "--write":
+			write = true
+		case "--no-trim":
+			opts.SkipTrim = true
+		case "--no-dedupe":
+			opts.SkipDedupeParticipants = true
+		default:
+			files = append(files, arg)
+		}
+	}
+
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	if write {
+		for _, filename := range files {
+			if filename == "-" {
+				fmt.Fprintf(os.Stderr, "Error: cannot rewrite stdin in place\n")
+				os.Exit(1)
+			}
+		}
+	}
+
+	for _, filename := range files {
+		if err := fixFile(filename, opts, write); err != nil {
+			fmt.Fprintf(os.Stderr, "Error fixing %s: %v\n", filename, err)
+			os.Exit(1)
+		}
+	}
+}
+
+func handleSubscribe(args []string) {
+	var stateFile, url string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--state":
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: --state requires a file\n")
+				os.Exit(1)
+			}
+			i++
+			stateFile = args[i]
+		default:
+			url = args[i]
+		}
+	}
+	if url == "" {
+		fmt.Fprintf(os.Stderr, "Error: feed URL is required\n")
+		os.Exit(1)
+	}
+
+	if err := subscribe(url, stateFile); err != nil {
+		fmt.Fprintf(os.Stderr, "Error subscribing to %s: %v\n", url, err)
+		os.Exit(1)
+	}
+}
+
+// subscribe fetches a feed and prints the changes since the fetch recorded
+// in stateFile, one "+ added", "~ changed" or "- removed" line per event
+func subscribe(url, stateFile string) error {
+	f := feed.New(url)
+	if stateFile != "" {
+		data, err := os.ReadFile(stateFile)
+		switch {
+		case err == nil:
+			if err := json.Unmarshal(data, f); err != nil {
+				return fmt.Errorf("invalid state file %s: %w", stateFile, err)
+			}
+			if f.URL != url {
+				return fmt.Errorf("state file %s belongs to %s", stateFile, f.URL)
+			}
+		case !os.IsNotExist(err):
+			return err
+		}
+	}
+
+	changes, err := f.Fetch(context.Background())
+	if err != nil {
+		return err
+	}
+	for _, line := range []struct {
+		mark   string
+		events []*jscal.Event
+	}{{"+", changes.Added}, {"~", changes.Changed}, {"-", changes.Removed}} {
+		for _, event := range line.events {
+			fmt.Printf("%s %s %s\n", line.mark, event.UID, event.EffectiveTitle(""))
+		}
+	}
+	if changes.NotModified {
+		fmt.Fprintln(os.Stderr, "Feed not modified")
+	} else {
+		fmt.Fprintln(os.Stderr, changes)
+	}
+
+	if stateFile == "" {
+		return nil
+	}
+	data, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(stateFile, data, 0644)
+}
+
+func convertData(inputData []byte, fromFormat, toFormat string) ([]byte, error) {
 	// First, convert to JSCalendar if needed
+	events, err := parseEvents(inputData, fromFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to target format
+	return formatEvents(events, toFormat)
+}
+
+// convertDataLenient converts like convertData, skipping the events that
+// fail to parse and reporting them on stderr
+func convertDataLenient(inputData []byte, inputFile, fromFormat, toFormat string) ([]byte, error) {
+	events, err := parseEventsReporting(inputData, inputFile, fromFormat)
+	if err != nil {
+		return nil, err
+	}
+	return formatEvents(events, toFormat)
+}
+
+// parseEventsReporting parses like parseEventsLenient and reports the
+// skipped events on stderr
+func parseEventsReporting(inputData []byte, inputFile, fromFormat string) ([]*jscal.Event, error) {
+	events, errs, err := parseEventsLenient(inputData, fromFormat)
+	if err != nil {
+		return nil, err
+	}
+	for _, itemErr := range errs {
+		fmt.Fprintf(os.Stderr, "%s: skipped %v\n", inputFile, itemErr)
+	}
+	if len(errs) > 0 {
+		fmt.Fprintf(os.Stderr, "%s: skipped %d of %d events\n", inputFile, len(errs), len(events)+len(errs))
+	}
+	if len(events) == 0 && len(errs) > 0 {
+		return nil, fmt.Errorf("no events could be converted")
+	}
+	return events, nil
+}
+
+// parseEvents reads input data in the given format into JSCalendar events
+func parseEvents(inputData []byte, fromFormat string) ([]*jscal.Event, error) {
 	var events []*jscal.Event
 	var err error
 
 	switch strings.ToLower(fromFormat) {
-	case "ical", "icalendar", "ics":
-		converter := ical.New()
-		events, err = converter.ParseAll(inputData)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse iCalendar: %w", err)
-		}
 	case "json", "jscal", "jscalendar":
 		// Try to parse as single event first
 		if event, parseErr := jscal.ParseEvent(inputData); parseErr == nil {
@@ -211,14 +683,85 @@ func convert(inputData []byte, fromFormat, toFormat string) ([]byte, error) {
 			}
 		}
 	default:
-		return nil, fmt.Errorf("unsupported input format: %s", fromFormat)
+		converter, ok := convert.Lookup(fromFormat)
+		if !ok {
+			return nil, fmt.Errorf("unsupported input format: %s", fromFormat)
+		}
+		events, err = converter.ParseAll(inputData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", fromFormat, err)
+		}
 	}
 
-	// Convert to target format
+	return events, nil
+}
+
+// parseEventsLenient reads input data like parseEvents, but skips the
+// events that fail to parse and reports them instead
+func parseEventsLenient(inputData []byte, fromFormat string) ([]*jscal.Event, []*convert.ItemError, error) {
+	if !isJSONFormat(fromFormat) {
+		converter, ok := convert.Lookup(fromFormat)
+		if !ok {
+			return nil, nil, fmt.Errorf("unsupported input format: %s", fromFormat)
+		}
+		events, errs, err := convert.ParseAllLenient(converter, inputData)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse %s: %w", fromFormat, err)
+		}
+		return events, errs, nil
+	}
+
+	if !strings.HasPrefix(strings.TrimSpace(string(inputData)), "[") {
+		event, err := jscal.ParseEvent(inputData)
+		if err != nil {
+			return nil, nil, err
+		}
+		return []*jscal.Event{event}, nil, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(inputData))
+	if _, err := decoder.Token(); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse JSCalendar: %w", err)
+	}
+	var events []*jscal.Event
+	var errs []*convert.ItemError
+	for i := 0; decoder.More(); i++ {
+		offset := decoder.InputOffset()
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			// Without the end of this item the rest can't be found
+			return nil, nil, fmt.Errorf("failed to parse JSCalendar: %w", err)
+		}
+		event, err := jscal.ParseEvent(raw)
+		if err != nil {
+			itemErr := &convert.ItemError{Index: i, Line: jsonItemLine(inputData, offset), Err: err}
+			var uid struct {
+				UID string `json:"uid"`
+			}
+			if json.Unmarshal(raw, &uid) == nil {
+				itemErr.UID = uid.UID
+			}
+			errs = append(errs, itemErr)
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, errs, nil
+}
+
+// jsonItemLine returns the line of the array item following offset, past
+// separating whitespace and commas
+func jsonItemLine(data []byte, offset int64) int {
+	i := int(offset)
+	for i < len(data) && strings.ContainsRune(" \t\r\n,", rune(data[i])) {
+		i++
+	}
+	return bytes.Count(data[:i], []byte("\n")) + 1
+}
+
+// formatEvents serializes JSCalendar events into the given output format
+func formatEvents(events []*jscal.Event, toFormat string) ([]byte, error) {
 	switch strings.ToLower(toFormat) {
-	case "ical", "icalendar", "ics":
-		converter := ical.New()
-		return converter.FormatAll(events)
 	case "json", "jscal", "jscalendar":
 		if len(events) == 1 {
 			return events[0].PrettyJSON()
@@ -226,102 +769,229 @@ func convert(inputData []byte, fromFormat, toFormat string) ([]byte, error) {
 			return json.MarshalIndent(events, "", "  ")
 		}
 	default:
-		return nil, fmt.Errorf("unsupported output format: %s", toFormat)
+		converter, ok := convert.Lookup(toFormat)
+		if !ok {
+			return nil, fmt.Errorf("unsupported output format: %s", toFormat)
+		}
+		return converter.FormatAll(events)
 	}
 }
 
+// detectFormat returns the format of data, or of a file with extension
+// fileExt if data is nil, defaulting to JSCalendar
 func detectFormat(data []byte, fileExt string) string {
-	// Try file extension first
-	switch strings.ToLower(fileExt) {
-	case ".ics", ".ical":
-		return "ical"
-	case ".json":
-		return "json"
+	if format, _ := convert.Sniff(data, fileExt); format != "" {
+		return format
+	}
+	return "json"
+}
+
+func validateFile(filename string) ([]*jscal.ValidationReport, error) {
+	data, err := readFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
 
-	// Try content detection if we have data
-	if len(data) > 0 {
-		dataStr := string(data)
-		trimmed := strings.TrimSpace(dataStr)
+	// Decode without validating, so every issue ends up in the report
+	objects, err := decodeObjects(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JSCalendar: %w", err)
+	}
 
-		// Check for iCalendar
-		if strings.HasPrefix(trimmed, "BEGIN:VCALENDAR") || strings.Contains(dataStr, "BEGIN:VEVENT") {
-			return "ical"
+	reports := make([]*jscal.ValidationReport, 0, len(objects))
+	for _, obj := range objects {
+		reports = append(reports, obj.ValidateReport())
+	}
+	return reports, nil
+}
+
+// reportable is a JSCalendar object that can produce a validation report
+type reportable interface {
+	ValidateReport() *jscal.ValidationReport
+}
+
+// decodeObjects unmarshals a single JSCalendar object or an array of objects
+// of any @type without validating them
+func decodeObjects(data []byte) ([]reportable, error) {
+	var rawObjects []json.RawMessage
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "[") {
+		if err := json.Unmarshal(data, &rawObjects); err != nil {
+			return nil, err
 		}
+	} else {
+		rawObjects = []json.RawMessage{data}
+	}
 
-		// Check for JSON
-		if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
-			return "json"
+	objects := make([]reportable, 0, len(rawObjects))
+	for i, raw := range rawObjects {
+		var typeCheck struct {
+			Type string `json:"@type"`
 		}
+		if err := json.Unmarshal(raw, &typeCheck); err != nil {
+			return nil, fmt.Errorf("object at index %d: %w", i, err)
+		}
+
+		var obj reportable
+		switch typeCheck.Type {
+		case "Event":
+			obj = &jscal.Event{}
+		case "Task":
+			obj = &jscal.Task{}
+		case "Group":
+			obj = &jscal.Group{}
+		default:
+			return nil, fmt.Errorf("object at index %d: unknown @type: %q", i, typeCheck.Type)
+		}
+		if err := json.Unmarshal(raw, obj); err != nil {
+			return nil, fmt.Errorf("object at index %d: %w", i, err)
+		}
+		objects = append(objects, obj)
 	}
 
-	// Default to JSON
-	return "json"
+	return objects, nil
 }
 
-func validateFile(filename string) error {
+// formatFile pretty-prints the events of a file, or converts them to
+// toFormat if set. The result goes to outputFile, back to the file itself
+// with inPlace, or to stdout.
+func formatFile(filename, outputFile, toFormat string, inPlace bool) error {
 	data, err := readFile(filename)
 	if err != nil {
 		return fmt.Errorf("failed to read file: %w", err)
 	}
 
-	// Try to parse as single event first
-	if event, err := jscal.ParseEvent(data); err == nil {
-		return event.Validate()
+	fromFormat := detectFormat(data, filepath.Ext(filename))
+	if toFormat == "" {
+		toFormat = "json"
+		if inPlace {
+			toFormat = fromFormat
+		}
 	}
 
-	// Try as array of events
-	events, err := jscal.ParseAllEvents(data)
+	var formatted []byte
+	if isJSONFormat(fromFormat) && isJSONFormat(toFormat) {
+		formatted, err = formatJSON(data)
+	} else {
+		var events []*jscal.Event
+		if events, err = parseEvents(data, fromFormat); err == nil {
+			formatted, err = formatEvents(events, toFormat)
+		}
+	}
 	if err != nil {
-		return fmt.Errorf("failed to parse JSCalendar: %w", err)
+		return err
 	}
-
-	// Validate each event
-	for i, event := range events {
-		if err := event.Validate(); err != nil {
-			return fmt.Errorf("event %d is invalid: %w", i, err)
-		}
+	if !bytes.HasSuffix(formatted, []byte("\n")) {
+		formatted = append(formatted, '\n')
 	}
 
-	return nil
+	switch {
+	case inPlace:
+		return writeFileAtomic(filename, formatted)
+	case outputFile != "":
+		return writeFile(outputFile, formatted)
+	}
+	_, err = os.Stdout.Write(formatted)
+	return err
 }
 
-func formatFile(filename string) error {
-	data, err := readFile(filename)
-	if err != nil {
-		return fmt.Errorf("failed to read file: %w", err)
+// isJSONFormat reports whether a format name means JSCalendar
+func isJSONFormat(format string) bool {
+	switch strings.ToLower(format) {
+	case "json", "jscal", "jscalendar":
+		return true
 	}
+	return false
+}
 
+// formatJSON pretty-prints a JSCalendar event or array of events, keeping
+// the shape of the input
+func formatJSON(data []byte) ([]byte, error) {
 	// Try to parse as single event first
 	if event, err := jscal.ParseEvent(data); err == nil {
 		formatted, err := event.PrettyJSON()
 		if err != nil {
-			return fmt.Errorf("failed to format JSON: %w", err)
+			return nil, fmt.Errorf("failed to format JSON: %w", err)
 		}
-		fmt.Print(string(formatted))
-		return nil
+		return formatted, nil
 	}
 
 	// Try as array of events
 	events, err := jscal.ParseAllEvents(data)
 	if err != nil {
-		return fmt.Errorf("failed to parse JSCalendar: %w", err)
+		return nil, fmt.Errorf("failed to parse JSCalendar: %w", err)
 	}
 
 	formatted, err := json.MarshalIndent(events, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to format JSON: %w", err)
+	}
+	return formatted, nil
+}
+
+func fixFile(filename string, opts jscal.NormalizeOptions, write bool) error {
+	data, err := readFile(filename)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	// Decode without validating, invalid input is what fix is for
+	isArray := strings.HasPrefix(strings.TrimSpace(string(data)), "[")
+	var events []*jscal.Event
+	if isArray {
+		err = json.Unmarshal(data, &events)
+	} else {
+		var event jscal.Event
+		err = json.Unmarshal(data, &event)
+		events = []*jscal.Event{&event}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse JSCalendar: %w", err)
+	}
+
+	var changed int
+	for _, event := range events {
+		for _, change := range event.Normalize(opts) {
+			fmt.Fprintf(os.Stderr, "%s: %s: %s\n", filename, event.UID, change)
+			changed++
+		}
+	}
+
+	var output interface{} = events
+	if !isArray {
+		output = events[0]
+	}
+	formatted, err := json.MarshalIndent(output, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to format JSON: %w", err)
 	}
+	formatted = append(formatted, '\n')
 
-	fmt.Print(string(formatted))
+	if !write {
+		_, err := os.Stdout.Write(formatted)
+		return err
+	}
+	if changed == 0 {
+		fmt.Fprintf(os.Stderr, "%s: no changes\n", filename)
+		return nil
+	}
+	if err := writeFileAtomic(filename, formatted); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "%s: %d changes written\n", filename, changed)
 	return nil
 }
 
+// readFile reads a file, or stdin for "-", as UTF-8
 func readFile(filename string) ([]byte, error) {
+	var data []byte
+	var err error
 	if filename == "-" {
-		return io.ReadAll(os.Stdin)
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(filename)
 	}
-	return os.ReadFile(filename)
+	data, _ = convert.DecodeText(data)
+	return data, err
 }
 
 func writeFile(filename string, data []byte) error {
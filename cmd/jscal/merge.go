@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/airtrafik/jscal"
+)
+
+func handleMerge(args []string) {
+	var inputs []string
+	outputFile, groupUID := "-", ""
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "-o", "--output", "--group":
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: %s requires a value\n", args[i])
+				os.Exit(1)
+			}
+			if args[i] == "--group" {
+				groupUID = args[i+1]
+			} else {
+				outputFile = args[i+1]
+			}
+			i++
+		default:
+			inputs = append(inputs, args[i])
+		}
+	}
+	if len(inputs) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: at least one input file is required\n")
+		os.Exit(1)
+	}
+
+	if err := merge(inputs, outputFile, groupUID); err != nil {
+		fmt.Fprintf(os.Stderr, "Error merging: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// merge combines the objects of the input files, keeping one object per
+// UID, and writes them in the format of outputFile's extension: a JSON
+// array, a Group with groupUID if set, or e.g. iCalendar for events
+func merge(inputs []string, outputFile, groupUID string) error {
+	var merged []jscal.CalendarObject
+	index := make(map[string]int)
+	var total int
+	for _, filename := range inputs {
+		objects, err := readObjects(filename)
+		if err != nil {
+			return err
+		}
+		total += len(objects)
+		for _, obj := range objects {
+			uid := obj.GetUID()
+			i, ok := index[uid]
+			switch {
+			case !ok:
+				index[uid] = len(merged)
+				merged = append(merged, obj)
+			case newerRevision(obj, merged[i]):
+				merged[i] = obj
+			}
+		}
+	}
+
+	var data []byte
+	var err error
+	toFormat := detectFormat(nil, filepath.Ext(outputFile))
+	switch {
+	case toFormat != "json":
+		events := make([]*jscal.Event, 0, len(merged))
+		for _, obj := range merged {
+			event, ok := obj.(*jscal.Event)
+			if !ok {
+				return fmt.Errorf("%s %s cannot be written as %s", obj.GetType(), obj.GetUID(), toFormat)
+			}
+			events = append(events, event)
+		}
+		data, err = formatEvents(events, toFormat)
+	case groupUID != "":
+		group := jscal.NewGroup(groupUID, "Merged calendar")
+		group.Entries = merged
+		data, err = group.PrettyJSON()
+	default:
+		if merged == nil {
+			merged = []jscal.CalendarObject{}
+		}
+		data, err = json.MarshalIndent(merged, "", "  ")
+	}
+	if err != nil {
+		return err
+	}
+	if !bytes.HasSuffix(data, []byte("\n")) {
+		data = append(data, '\n')
+	}
+	if err := writeFile(outputFile, data); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Merged %d objects from %d files into %d (%d duplicates dropped)\n",
+		total, len(inputs), len(merged), total-len(merged))
+	return nil
+}
+
+// readObjects reads the events and tasks of a file in any supported format.
+// Groups are replaced by their events and tasks, including those of nested
+// groups.
+func readObjects(filename string) ([]jscal.CalendarObject, error) {
+	data, err := readFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	format := detectFormat(data, filepath.Ext(filename))
+	if format != "json" {
+		events, err := parseEvents(data, format)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filename, err)
+		}
+		objects := make([]jscal.CalendarObject, len(events))
+		for i, event := range events {
+			objects[i] = event
+		}
+		return objects, nil
+	}
+
+	var objects []jscal.CalendarObject
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "[") {
+		objects, err = jscal.ParseAll(data)
+	} else {
+		var obj jscal.CalendarObject
+		obj, err = jscal.Parse(data)
+		objects = []jscal.CalendarObject{obj}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+
+	var flattened []jscal.CalendarObject
+	for _, obj := range objects {
+		if group, ok := obj.(*jscal.Group); ok {
+			flattened = append(flattened, group.Flatten()...)
+		} else {
+			flattened = append(flattened, obj)
+		}
+	}
+	return flattened, nil
+}
+
+// newerRevision reports whether a is a later revision than b: a higher
+// sequence, or the same sequence and a later updated timestamp
+func newerRevision(a, b jscal.CalendarObject) bool {
+	seqA, updatedA := revision(a)
+	seqB, updatedB := revision(b)
+	if seqA != seqB {
+		return seqA > seqB
+	}
+	return updatedA.After(updatedB)
+}
+
+// revision returns the sequence and updated timestamp of an event or task
+func revision(obj jscal.CalendarObject) (int, time.Time) {
+	var sequence *int
+	var updated *jscal.UTCDateTime
+	switch o := obj.(type) {
+	case *jscal.Event:
+		sequence, updated = o.Sequence, o.Updated
+	case *jscal.Task:
+		sequence, updated = o.Sequence, o.Updated
+	}
+
+	var seq int
+	if sequence != nil {
+		seq = *sequence
+	}
+	return seq, updated.Time()
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const nestedGroupJSON = `{"@type": "Group", "uid": "calendar", "entries": [
+  {"@type": "Event", "uid": "top", "title": "Top", "start": "2025-03-03T09:00:00"},
+  {"@type": "Group", "uid": "team", "entries": [
+    {"@type": "Event", "uid": "nested", "title": "Nested", "start": "2025-03-04T09:00:00"},
+    {"@type": "Task", "uid": "task", "title": "Task"}
+  ]}
+]}`
+
+func TestReadObjectsNestedGroups(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "calendar.json")
+	if err := os.WriteFile(filename, []byte(nestedGroupJSON), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	objects, err := readObjects(filename)
+	if err != nil {
+		t.Fatalf("readObjects failed: %v", err)
+	}
+	var uids []string
+	for _, obj := range objects {
+		uids = append(uids, obj.GetUID())
+	}
+	if len(uids) != 3 || uids[0] != "top" || uids[1] != "nested" || uids[2] != "task" {
+		t.Errorf("Expected the entries of the nested group to be read, got %v", uids)
+	}
+}
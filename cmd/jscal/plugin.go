@@ -0,0 +1,80 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// pluginPrefix is prepended to unknown commands to find a plugin executable,
+// so `jscal foo` runs `jscal-foo` from PATH
+const pluginPrefix = "jscal-"
+
+// findPlugin returns the path of the plugin executable for a command
+func findPlugin(command string) (string, bool) {
+	// Only plain names, never paths
+	if command == "" || strings.ContainsAny(command, `/\`) || strings.HasPrefix(command, "-") {
+		return "", false
+	}
+	path, err := exec.LookPath(pluginPrefix + command)
+	if err != nil {
+		return "", false
+	}
+	return path, true
+}
+
+// runPlugin executes a plugin with stdin, stdout and stderr passed through
+// and returns its exit code. The plugin learns about the host through
+// JSCAL_VERSION and JSCAL_BIN.
+func runPlugin(path string, args []string) int {
+	cmd := exec.Command(path, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), "JSCAL_VERSION="+version)
+	if self, err := os.Executable(); err == nil {
+		cmd.Env = append(cmd.Env, "JSCAL_BIN="+self)
+	}
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode()
+		}
+		fmt.Fprintf(os.Stderr, "Error running plugin %s: %v\n", filepath.Base(path), err)
+		return 1
+	}
+	return 0
+}
+
+// listPlugins returns the command names of all plugins found on PATH
+func listPlugins() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if !strings.HasPrefix(name, pluginPrefix) || entry.IsDir() {
+				continue
+			}
+			command := strings.TrimSuffix(strings.TrimPrefix(name, pluginPrefix), ".exe")
+			if command == "" || seen[command] {
+				continue
+			}
+			if _, ok := findPlugin(command); ok {
+				seen[command] = true
+				names = append(names, command)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/airtrafik/jscal"
+	"github.com/airtrafik/jscal/convert"
+)
+
+// maxFileNameUID is the longest UID used as a file name as is; longer ones
+// are cut and made unique with a hash
+const maxFileNameUID = 100
+
+// splitByUID writes the events to one file per UID in dir, in toFormat, as
+// per-event storage such as a CalDAV collection keeps them. Events sharing
+// a UID, such as detached instances of a series, go in the same file. It
+// returns the number of files written.
+func splitByUID(events []*jscal.Event, dir, toFormat string) (int, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, err
+	}
+
+	var uids []string
+	byUID := make(map[string][]*jscal.Event)
+	for _, event := range events {
+		if _, ok := byUID[event.UID]; !ok {
+			uids = append(uids, event.UID)
+		}
+		byUID[event.UID] = append(byUID[event.UID], event)
+	}
+
+	ext := formatExtension(toFormat)
+	used := make(map[string]bool)
+	for _, uid := range uids {
+		name := uniqueFileName(uid, used)
+		data, err := formatEvents(byUID[uid], toFormat)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", uid, err)
+		}
+		if err := writeFile(filepath.Join(dir, name+ext), data); err != nil {
+			return 0, err
+		}
+	}
+	return len(uids), nil
+}
+
+// uidFileName returns a file name for a UID: the UID with characters other
+// than letters, digits and ".@+_-" replaced by "_", cut with a hash
+// appended if it is too long
+func uidFileName(uid string) string {
+	name := strings.Map(func(r rune) rune {
+		if r < 0x80 && (r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune(".@+_-", r)) {
+			return r
+		}
+		return '_'
+	}, uid)
+	if name == "" || strings.HasPrefix(name, ".") {
+		name = "event" + name
+	}
+	if len(name) > maxFileNameUID {
+		sum := sha256.Sum256([]byte(uid))
+		name = name[:maxFileNameUID-9] + "-" + hex.EncodeToString(sum[:4])
+	}
+	return name
+}
+
+// uniqueFileName returns the uidFileName of uid, numbered from "-2" on if
+// it is in used, and adds it to used. Names must also differ on
+// case-insensitive file systems, so used holds them lowercased.
+func uniqueFileName(uid string, used map[string]bool) string {
+	base := uidFileName(uid)
+	name := base
+	for i := 2; used[strings.ToLower(name)]; i++ {
+		name = fmt.Sprintf("%s-%d", base, i)
+	}
+	used[strings.ToLower(name)] = true
+	return name
+}
+
+// formatExtension returns the file extension of a format, ".json" for
+// JSCalendar and unknown formats
+func formatExtension(format string) string {
+	for _, r := range convert.ListFormats() {
+		names := append([]string{r.Name}, r.Aliases...)
+		for _, name := range names {
+			if strings.EqualFold(name, format) && len(r.Extensions) > 0 {
+				return r.Extensions[0]
+			}
+		}
+	}
+	return ".json"
+}
+
+// inputFiles returns the files an input argument names: the files of a
+// directory, sorted, or those matching a glob pattern such as
+// "events/*.json"; nil for a plain file or "-"
+func inputFiles(input string) ([]string, error) {
+	if input == "-" {
+		return nil, nil
+	}
+	if info, err := os.Stat(input); err == nil {
+		if !info.IsDir() {
+			return nil, nil
+		}
+		entries, err := os.ReadDir(input)
+		if err != nil {
+			return nil, err
+		}
+		var files []string
+		for _, entry := range entries {
+			if entry.Type().IsRegular() && !strings.HasPrefix(entry.Name(), ".") {
+				files = append(files, filepath.Join(input, entry.Name()))
+			}
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no files in %s", input)
+		}
+		return files, nil
+	}
+	if !strings.ContainsAny(input, "*?[") {
+		return nil, nil
+	}
+	files, err := filepath.Glob(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no files match %s", input)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// readEventsFromFiles parses the events of several files, see
+// readEventsFromFile
+func readEventsFromFiles(files []string, fromFormat string, keepGoing bool) ([]*jscal.Event, error) {
+	var events []*jscal.Event
+	for _, filename := range files {
+		parsed, err := readEventsFromFile(filename, fromFormat, keepGoing)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, parsed...)
+	}
+	return events, nil
+}
+
+// handleMultiFileConvert converts the files of a directory or glob pattern
+// into one output file, or an input into one file per UID with splitBy
+func handleMultiFileConvert(inputFile string, files []string, outputFile, fromFormat, toFormat, splitBy string, keepGoing bool) {
+	var events []*jscal.Event
+	var err error
+	if files != nil {
+		events, err = readEventsFromFiles(files, fromFormat, keepGoing)
+	} else {
+		events, err = readEventsFromFile(inputFile, fromFormat, keepGoing)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error converting: %v\n", err)
+		os.Exit(1)
+	}
+
+	if splitBy != "" {
+		if outputFile == "-" {
+			fmt.Fprintf(os.Stderr, "Error: --split-by requires an output directory\n")
+			os.Exit(1)
+		}
+		if toFormat == "" {
+			toFormat = "json"
+		}
+		n, err := splitByUID(events, outputFile, toFormat)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Successfully split %s into %d files in %s\n", inputFile, n, outputFile)
+		return
+	}
+
+	if toFormat == "" {
+		toFormat = detectFormat(nil, filepath.Ext(outputFile))
+	}
+	data, err := formatEvents(events, toFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error converting: %v\n", err)
+		os.Exit(1)
+	}
+	if err := writeFile(outputFile, data); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output file: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Successfully converted %d files to %s\n", len(files), outputFile)
+}
+
+// readEventsFromFile parses the events of a file, detecting its format
+// unless fromFormat is set. With keepGoing, events that fail to parse are
+// skipped and reported.
+func readEventsFromFile(filename, fromFormat string, keepGoing bool) ([]*jscal.Event, error) {
+	data, err := readFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	if fromFormat == "" {
+		fromFormat = detectFormat(data, filepath.Ext(filename))
+	}
+	if keepGoing {
+		return parseEventsReporting(data, filename, fromFormat)
+	}
+	events, err := parseEvents(data, fromFormat)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return events, nil
+}
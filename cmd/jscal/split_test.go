@@ -0,0 +1,157 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/airtrafik/jscal"
+)
+
+func TestUIDFileName(t *testing.T) {
+	long := strings.Repeat("x", 150)
+	tests := []struct {
+		name string
+		uid  string
+		want string
+	}{
+		{"plain", "abc-123@example.com", "abc-123@example.com"},
+		{"unsafe characters", "a/b\\c:d e?*", "a_b_c_d_e__"},
+		{"non-ASCII", "réunion", "r_union"},
+		{"empty", "", "event"},
+		{"hidden", ".hidden", "event.hidden"},
+		{"dot dot", "..", "event.."},
+		{"long", long, long[:91] + "-"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := uidFileName(tt.uid)
+			if tt.name == "long" {
+				if len(got) != maxFileNameUID || !strings.HasPrefix(got, tt.want) {
+					t.Errorf("Expected %d characters starting with %q, got %q", maxFileNameUID, tt.want, got)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	// Long UIDs differing only past the cut get different names
+	if a, b := uidFileName(long+"a"), uidFileName(long+"b"); a == b {
+		t.Errorf("Expected long UIDs to get different names, both got %q", a)
+	}
+}
+
+func TestUniqueFileName(t *testing.T) {
+	tests := []struct {
+		name string
+		uids []string
+		want []string
+	}{
+		{"distinct", []string{"a", "b"}, []string{"a", "b"}},
+		{"same after sanitizing", []string{"a/b", "a:b", "a b"}, []string{"a_b", "a_b-2", "a_b-3"}},
+		{"case only", []string{"Meeting", "meeting", "MEETING"}, []string{"Meeting", "meeting-2", "MEETING-3"}},
+		{"numbered name taken", []string{"a-2", "a", "a/"}, []string{"a-2", "a", "a_"}},
+		{"numbered collision", []string{"x-2", "x", "X"}, []string{"x-2", "x", "X-3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			used := make(map[string]bool)
+			var got []string
+			for _, uid := range tt.uids {
+				got = append(got, uniqueFileName(uid, used))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSplitByUID(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "events")
+	master := jscal.NewEvent("series@example.com", "Series")
+	instance := jscal.NewEvent("series@example.com", "Moved")
+	other := jscal.NewEvent("Series@example.com", "Other")
+	slashed := jscal.NewEvent("a/b", "Slashed")
+
+	n, err := splitByUID([]*jscal.Event{master, other, instance, slashed}, dir, "json")
+	if err != nil {
+		t.Fatalf("splitByUID failed: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("Expected 3 files, got %d", n)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	want := []string{"Series@example.com-2.json", "a_b.json", "series@example.com.json"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("Expected files %v, got %v", want, names)
+	}
+
+	// Events sharing a UID go in the same file
+	data, err := os.ReadFile(filepath.Join(dir, "series@example.com.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "Series") || !strings.Contains(string(data), "Moved") {
+		t.Errorf("Expected both events of the series in one file, got %s", data)
+	}
+}
+
+func TestInputFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"b.json", "a.json", "c.ics", ".hidden"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	empty := filepath.Join(dir, "sub")
+
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		{"stdin", "-", nil, false},
+		{"plain file", filepath.Join(dir, "a.json"), nil, false},
+		{"missing plain file", filepath.Join(dir, "missing.json"), nil, false},
+		{"directory", dir, []string{"a.json", "b.json", "c.ics"}, false},
+		{"glob", filepath.Join(dir, "*.json"), []string{"a.json", "b.json"}, false},
+		{"empty directory", empty, nil, true},
+		{"no match", filepath.Join(dir, "*.xml"), nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := inputFiles(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			var got []string
+			for _, file := range files {
+				got = append(got, filepath.Base(file))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
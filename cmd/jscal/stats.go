@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/airtrafik/jscal"
+)
+
+func handleStats(args []string) {
+	var filename, fromArg, toArg string
+	loc := time.Local
+	top := 5
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--from", "--to", "--top", "--tz":
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: %s requires a value\n", args[i])
+				os.Exit(1)
+			}
+			switch args[i] {
+			case "--from":
+				fromArg = args[i+1]
+			case "--to":
+				toArg = args[i+1]
+			case "--tz":
+				zone, err := time.LoadLocation(args[i+1])
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: unknown time zone %s\n", args[i+1])
+					os.Exit(1)
+				}
+				loc = zone
+			default:
+				n, err := strconv.Atoi(args[i+1])
+				if err != nil || n < 1 {
+					fmt.Fprintf(os.Stderr, "Error: --top requires a positive number\n")
+					os.Exit(1)
+				}
+				top = n
+			}
+			i++
+		default:
+			filename = args[i]
+		}
+	}
+	if filename == "" {
+		filename = "-"
+	}
+
+	if err := stats(os.Stdout, filename, fromArg, toArg, loc, top); err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", filename, err)
+		os.Exit(1)
+	}
+}
+
+// stats prints the Group.Stats of the events and tasks of a file. Recurring
+// events are expanded between from and to, by default from the first start
+// to a year after the last, so the busiest weekdays and hours count each
+// occurrence. Floating times, days and hours are read in loc.
+func stats(out io.Writer, filename, fromArg, toArg string, loc *time.Location, top int) error {
+	objects, err := readObjects(filename)
+	if err != nil {
+		return err
+	}
+	group := jscal.NewGroup("stats", filename)
+	group.Entries = objects
+
+	from, to, err := statsWindow(objects, fromArg, toArg, loc)
+	if err != nil {
+		return err
+	}
+	s, err := group.Stats(from, to)
+	if err != nil {
+		return err
+	}
+	printStats(out, s, top)
+	return nil
+}
+
+// statsWindow returns the window stats expands recurring events in: the
+// bounds given, or else a day before the first start of an event and a
+// year after the last, so that no time zone puts an instance outside it
+func statsWindow(objects []jscal.CalendarObject, fromArg, toArg string, loc *time.Location) (time.Time, time.Time, error) {
+	var first, last *jscal.LocalDateTime
+	for _, obj := range objects {
+		if event, ok := obj.(*jscal.Event); ok && event.Start != nil {
+			if first == nil || event.Start.Before(first) {
+				first = event.Start
+			}
+			if last == nil || event.Start.After(last) {
+				last = event.Start
+			}
+		}
+	}
+
+	var from, to time.Time
+	switch {
+	case fromArg != "":
+		bound, err := parseBound(fromArg, false)
+		if err != nil {
+			return from, to, fmt.Errorf("invalid --from: %w", err)
+		}
+		from = bound.In(loc)
+	case first != nil:
+		from = first.In(loc).AddDate(0, 0, -1)
+	default:
+		from = time.Now().In(loc)
+	}
+	switch {
+	case toArg != "":
+		bound, err := parseBound(toArg, true)
+		if err != nil {
+			return from, to, fmt.Errorf("invalid --to: %w", err)
+		}
+		to = bound.In(loc)
+	case last != nil:
+		to = last.In(loc).AddDate(1, 0, 0)
+	default:
+		to = from.AddDate(1, 0, 0)
+	}
+	return from, to, nil
+}
+
+// printStats prints the figures of s, with the top entries of each ranking
+func printStats(out io.Writer, s *jscal.GroupStats, top int) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Events:\t%d (%d recurring)\n", s.ByType["Event"], s.Recurring)
+	fmt.Fprintf(w, "Tasks:\t%d (%d overdue)\n", s.ByType["Task"], s.Overdue)
+	fmt.Fprintf(w, "Occurrences:\t%d\n", s.Instances)
+	if s.FirstDay != "" {
+		fmt.Fprintf(w, "Date span:\t%s to %s\n", s.FirstDay, s.LastDay)
+	}
+	if s.AverageDuration > 0 {
+		average := s.AverageDuration.Round(time.Minute)
+		fmt.Fprintf(w, "Average duration:\t%s\n", jscal.FormatDurationHuman(jscal.FormatDuration(average)))
+	}
+
+	hours := make(map[string]int, len(s.ByHour))
+	for hour, n := range s.ByHour {
+		hours[fmt.Sprintf("%02d:00", hour)] = n
+	}
+	printRanking(w, "Busiest weekdays", s.ByWeekday, top)
+	printRanking(w, "Busiest hours", hours, top)
+	printRanking(w, "Top categories", s.ByCategory, top)
+	printRanking(w, "Top participants", s.ByParticipant, top)
+	w.Flush()
+}
+
+// printRanking prints the top entries of counts, most frequent first and
+// ties in name order
+func printRanking(w *tabwriter.Writer, title string, counts map[string]int, top int) {
+	if len(counts) == 0 {
+		return
+	}
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	if len(names) > top {
+		names = names[:top]
+	}
+
+	fmt.Fprintf(w, "\n%s:\n", title)
+	for _, name := range names {
+		fmt.Fprintf(w, "    %s\t%d\n", name, counts[name])
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const statsCalendar = `{"@type": "Group", "uid": "calendar", "entries": [
+  {"@type": "Event", "uid": "standup", "title": "Standup", "start": "2025-03-03T09:00:00", "duration": "PT30M",
+   "recurrenceRules": [{"@type": "RecurrenceRule", "frequency": "daily", "count": 5}],
+   "recurrenceOverrides": {"2025-03-05T09:00:00": {"status": "cancelled"}},
+   "categories": {"work": true}, "keywords": {"daily": true},
+   "participants": {"lead": {"@type": "Participant", "email": "Lead@example.com"}}},
+  {"@type": "Group", "uid": "personal", "entries": [
+    {"@type": "Event", "uid": "dentist", "title": "Dentist", "start": "2025-03-05T14:00:00", "duration": "PT1H30M",
+     "participants": {"lead": {"@type": "Participant", "email": "lead@example.com"}}},
+    {"@type": "Task", "uid": "taxes", "title": "File taxes", "due": "2020-04-15T00:00:00"}
+  ]}
+]}`
+
+func TestStats(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "calendar.json")
+	if err := os.WriteFile(filename, []byte(statsCalendar), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := stats(&out, filename, "", "", time.UTC, 5); err != nil {
+		t.Fatalf("stats failed: %v", err)
+	}
+	// The cancelled standup isn't counted, the nested group's entries are,
+	// and keywords aren't categories
+	for _, want := range []string{
+		"Events:            2 (1 recurring)",
+		"Tasks:             1 (1 overdue)",
+		"Occurrences:       5",
+		"Date span:         2025-03-03 to 2025-03-07",
+		"Average duration:  42 minutes",
+		"    Wednesday  1\n",
+		"Busiest hours:\n    09:00  4\n    14:00  1\n",
+		"Top categories:\n    work  1\n",
+		"Top participants:\n    lead@example.com  2\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in output:\n%s", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "daily") {
+		t.Errorf("Expected keywords not to be counted as categories:\n%s", out.String())
+	}
+}
+
+func TestStatsWindow(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "calendar.json")
+	if err := os.WriteFile(filename, []byte(statsCalendar), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := stats(&out, filename, "2025-03-06", "2025-03-06", time.UTC, 1); err != nil {
+		t.Fatalf("stats failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "Occurrences:       1\n") || !strings.Contains(out.String(), "Busiest weekdays:\n    Thursday  1\n") {
+		t.Errorf("Expected only the standup of 2025-03-06, got:\n%s", out.String())
+	}
+}
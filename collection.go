@@ -0,0 +1,201 @@
+package jscal
+
+import (
+	"sort"
+	"time"
+)
+
+// Events is a list of events with chainable query methods:
+//
+//	today := jscal.Events(events).ByCategory("Work").Between(from, to).SortByStart()
+//
+// The methods return new lists and leave the receiver and its events as
+// they are. Floating times (events without a timeZone) and events in a zone
+// that isn't known are read in the location the caller passes, or UTC.
+type Events []*Event
+
+// Filter returns the events for which keep returns true
+func (es Events) Filter(keep func(*Event) bool) Events {
+	var result Events
+	for _, e := range es {
+		if e != nil && keep(e) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// Map returns the results of fn for each event, dropping nil results
+func (es Events) Map(fn func(*Event) *Event) Events {
+	var result Events
+	for _, e := range es {
+		if e == nil {
+			continue
+		}
+		if mapped := fn(e); mapped != nil {
+			result = append(result, mapped)
+		}
+	}
+	return result
+}
+
+// ByCategory returns the events in any of the given categories
+func (es Events) ByCategory(categories ...string) Events {
+	return es.Filter(func(e *Event) bool {
+		for _, category := range categories {
+			if e.Categories[category] {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// SortByStart returns the events sorted by start instant. Events without a
+// start come last; events with the same start keep their order.
+func (es Events) SortByStart() Events {
+	result := make(Events, len(es))
+	copy(result, es)
+	sort.SliceStable(result, func(i, j int) bool {
+		a, aok := startInstant(result[i], time.UTC)
+		b, bok := startInstant(result[j], time.UTC)
+		if aok != bok {
+			return aok
+		}
+		return a.Before(b)
+	})
+	return result
+}
+
+// Between returns the events overlapping the interval [from, to). An event
+// without duration overlaps if it starts in the interval. Only the first
+// instance of recurring events is considered; use Occurrences to expand
+// them. Floating times are read in the location of from.
+func (es Events) Between(from, to time.Time) Events {
+	return es.Filter(func(e *Event) bool {
+		start, ok := startInstant(e, from.Location())
+		if !ok || !start.Before(to) {
+			return false
+		}
+		duration, err := e.GetDuration()
+		if err != nil || duration <= 0 {
+			return !start.Before(from)
+		}
+		return start.Add(duration).After(from)
+	})
+}
+
+// GroupByDay groups the events by the date they start on in loc, keyed by
+// date ("2006-01-02"). Events without a start are left out.
+func (es Events) GroupByDay(loc *time.Location) map[string]Events {
+	if loc == nil {
+		loc = time.UTC
+	}
+	days := make(map[string]Events)
+	for _, e := range es {
+		start, ok := startInstant(e, loc)
+		if !ok {
+			continue
+		}
+		if !e.IsAllDay() {
+			start = start.In(loc)
+		}
+		day := start.Format("2006-01-02")
+		days[day] = append(days[day], e)
+	}
+	return days
+}
+
+// startInstant returns the start of an event in its time zone. Floating and
+// all-day starts, and starts in unknown zones, are read in loc.
+func startInstant(e *Event, loc *time.Location) (time.Time, bool) {
+	if e == nil || e.Start == nil {
+		return time.Time{}, false
+	}
+	return e.Start.In(timeZoneOf(e.TimeZone, e.IsAllDay(), loc)), true
+}
+
+// Objects is a list of events, tasks and groups with chainable query
+// methods, see Events
+type Objects []CalendarObject
+
+// Filter returns the objects for which keep returns true
+func (objs Objects) Filter(keep func(CalendarObject) bool) Objects {
+	var result Objects
+	for _, obj := range objs {
+		if obj != nil && keep(obj) {
+			result = append(result, obj)
+		}
+	}
+	return result
+}
+
+// Map returns the results of fn for each object, dropping nil results
+func (objs Objects) Map(fn func(CalendarObject) CalendarObject) Objects {
+	var result Objects
+	for _, obj := range objs {
+		if obj == nil {
+			continue
+		}
+		if mapped := fn(obj); mapped != nil {
+			result = append(result, mapped)
+		}
+	}
+	return result
+}
+
+// ByCategory returns the objects in any of the given categories
+func (objs Objects) ByCategory(categories ...string) Objects {
+	return objs.Filter(func(obj CalendarObject) bool {
+		var objCategories map[string]bool
+		switch o := obj.(type) {
+		case *Event:
+			objCategories = o.Categories
+		case *Task:
+			objCategories = o.Categories
+		case *Group:
+			objCategories = o.Categories
+		}
+		for _, category := range categories {
+			if objCategories[category] {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Events returns the events, including those in groups and nested groups
+func (objs Objects) Events() Events {
+	var events Events
+	for _, obj := range objs.flatten() {
+		if e, ok := obj.(*Event); ok {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+// Tasks returns the tasks, including those in groups and nested groups
+func (objs Objects) Tasks() []*Task {
+	var tasks []*Task
+	for _, obj := range objs.flatten() {
+		if t, ok := obj.(*Task); ok {
+			tasks = append(tasks, t)
+		}
+	}
+	return tasks
+}
+
+// flatten replaces groups by their flattened entries
+func (objs Objects) flatten() []CalendarObject {
+	var flat []CalendarObject
+	for _, obj := range objs {
+		if g, ok := obj.(*Group); ok {
+			flat = append(flat, g.Flatten()...)
+		} else {
+			flat = append(flat, obj)
+		}
+	}
+	return flat
+}
@@ -0,0 +1,124 @@
+package jscal
+
+import (
+	"testing"
+	"time"
+)
+
+func newCollectionTestEvents() Events {
+	standup := NewEvent("standup", "Standup")
+	standup.Start = NewLocalDateTime(time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC))
+	standup.Duration = String("PT15M")
+	standup.AddCategory("Work")
+
+	lunch := NewEvent("lunch", "Lunch")
+	lunch.Start = NewLocalDateTime(time.Date(2025, 3, 3, 12, 0, 0, 0, time.UTC))
+	lunch.Duration = String("PT1H")
+
+	review := NewEvent("review", "Review")
+	review.Start = NewLocalDateTime(time.Date(2025, 3, 2, 23, 30, 0, 0, time.UTC))
+	review.Duration = String("PT1H")
+	review.AddCategory("Work")
+
+	unscheduled := NewEvent("unscheduled", "Unscheduled")
+	unscheduled.Start = nil
+
+	return Events{standup, unscheduled, lunch, review}
+}
+
+func uids(events Events) []string {
+	var result []string
+	for _, e := range events {
+		result = append(result, e.UID)
+	}
+	return result
+}
+
+func TestEventsQueries(t *testing.T) {
+	events := newCollectionTestEvents()
+
+	tests := []struct {
+		name     string
+		result   Events
+		expected []string
+	}{
+		{"sort by start", events.SortByStart(), []string{"review", "standup", "lunch", "unscheduled"}},
+		{"by category", events.ByCategory("Work", "Home"), []string{"standup", "review"}},
+		{
+			name:     "between overlaps",
+			result:   events.Between(time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 3, 12, 0, 0, 0, time.UTC)),
+			expected: []string{"standup", "review"},
+		},
+		{
+			name:     "chained",
+			result:   events.ByCategory("Work").SortByStart().Filter(func(e *Event) bool { return e.GetTitle() != "Review" }),
+			expected: []string{"standup"},
+		},
+		{
+			name: "map",
+			result: events.Map(func(e *Event) *Event {
+				if e.Start == nil {
+					return nil
+				}
+				return e
+			}),
+			expected: []string{"standup", "lunch", "review"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := uids(tt.result)
+			if len(got) != len(tt.expected) {
+				t.Fatalf("Expected %v, got %v", tt.expected, got)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Errorf("Expected %v, got %v", tt.expected, got)
+					break
+				}
+			}
+		})
+	}
+
+	if events[0].UID != "standup" || events[1].UID != "unscheduled" {
+		t.Errorf("Expected the receiver to keep its order, got %v", uids(events))
+	}
+}
+
+func TestEventsGroupByDay(t *testing.T) {
+	events := newCollectionTestEvents()
+	events[3].TimeZone = String("UTC")
+
+	days := events.GroupByDay(time.FixedZone("UTC+1", 3600))
+	if len(days) != 1 || len(days["2025-03-03"]) != 3 {
+		t.Errorf("Expected three events on 2025-03-03, got %v", days)
+	}
+
+	days = events.GroupByDay(nil)
+	if len(days["2025-03-02"]) != 1 || len(days["2025-03-03"]) != 2 {
+		t.Errorf("Expected one event on 2025-03-02 and two on 2025-03-03, got %v", days)
+	}
+}
+
+func TestObjects(t *testing.T) {
+	group := NewGroup("group", "Group")
+	event := NewEvent("grouped", "Grouped event")
+	event.AddCategory("Work")
+	_ = group.AddEntry(event)
+	task := NewTask("task", "Task")
+
+	objects := Objects{group, task, NewEvent("event", "Event")}
+	if events := objects.Events(); len(events) != 2 || events[0].UID != "grouped" {
+		t.Errorf("Expected the grouped event first, got %v", uids(events))
+	}
+	if tasks := objects.Tasks(); len(tasks) != 1 || tasks[0].UID != "task" {
+		t.Errorf("Expected one task, got %v", tasks)
+	}
+	if work := objects.ByCategory("Work"); len(work) != 0 {
+		t.Errorf("Expected no top-level objects in Work, got %v", work)
+	}
+	if work := objects.Events().ByCategory("Work"); len(work) != 1 {
+		t.Errorf("Expected one event in Work, got %v", uids(work))
+	}
+}
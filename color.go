@@ -0,0 +1,418 @@
+package jscal
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Color is a CSS color as 8-bit RGB and alpha channels
+type Color struct {
+	R, G, B, A uint8
+}
+
+// ParseColor parses a CSS color: a named color, transparent, #rgb, #rgba,
+// #rrggbb, #rrggbbaa, rgb(), rgba(), hsl() or hsla(), in the comma or the
+// space-separated syntax. Out of range values are rejected rather than
+// clamped.
+func ParseColor(s string) (Color, error) {
+	value := strings.ToLower(strings.TrimSpace(s))
+	if value == "" {
+		return Color{}, fmt.Errorf("empty color")
+	}
+	if value == "transparent" {
+		return Color{}, nil
+	}
+	if rgb, ok := cssColors[value]; ok {
+		return Color{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 255}, nil
+	}
+	if hex, ok := strings.CutPrefix(value, "#"); ok {
+		return parseHexColor(hex, s)
+	}
+
+	name, args, ok := strings.Cut(value, "(")
+	if !ok || !strings.HasSuffix(args, ")") {
+		return Color{}, fmt.Errorf("invalid color %q", s)
+	}
+	parts, err := colorArguments(strings.TrimSuffix(args, ")"))
+	if err != nil {
+		return Color{}, fmt.Errorf("invalid color %q: %w", s, err)
+	}
+	switch name {
+	case "rgb", "rgba":
+		return parseRGBColor(parts, s)
+	case "hsl", "hsla":
+		return parseHSLColor(parts, s)
+	}
+	return Color{}, fmt.Errorf("invalid color %q", s)
+}
+
+// NormalizeColor returns a CSS color as lowercase hex, see Color.Hex
+func NormalizeColor(s string) (string, error) {
+	c, err := ParseColor(s)
+	if err != nil {
+		return "", err
+	}
+	return c.Hex(), nil
+}
+
+// Hex returns the color as #rrggbb, or #rrggbbaa if it isn't opaque
+func (c Color) Hex() string {
+	if c.A == 255 {
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)
+}
+
+// String returns the color as hex
+func (c Color) String() string {
+	return c.Hex()
+}
+
+// Name returns the CSS name of an opaque color, if it has one. Of the
+// names sharing a value, such as gray and grey, the first in alphabetical
+// order is returned.
+func (c Color) Name() (string, bool) {
+	if c.A != 255 {
+		return "", false
+	}
+	for _, name := range cssColorNames {
+		if cssColors[name] == c.rgb() {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// NearestName returns the CSS name of the color closest to this one in RGB
+// space, ignoring alpha
+func (c Color) NearestName() string {
+	nearest, best := "", math.MaxInt
+	for _, name := range cssColorNames {
+		rgb := cssColors[name]
+		dr := int(c.R) - int(uint8(rgb>>16))
+		dg := int(c.G) - int(uint8(rgb>>8))
+		db := int(c.B) - int(uint8(rgb))
+		if d := dr*dr + dg*dg + db*db; d < best {
+			nearest, best = name, d
+		}
+	}
+	return nearest
+}
+
+// Luminance returns the relative luminance of the color as defined by
+// WCAG 2, from 0 for black to 1 for white
+func (c Color) Luminance() float64 {
+	channel := func(v uint8) float64 {
+		f := float64(v) / 255
+		if f <= 0.03928 {
+			return f / 12.92
+		}
+		return math.Pow((f+0.055)/1.055, 2.4)
+	}
+	return 0.2126*channel(c.R) + 0.7152*channel(c.G) + 0.0722*channel(c.B)
+}
+
+// ContrastRatio returns the WCAG 2 contrast ratio of two colors, from 1 to 21
+func ContrastRatio(a, b Color) float64 {
+	la, lb := a.Luminance(), b.Luminance()
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+// ContrastingTextColor returns black or white, whichever is more readable
+// on a background of this color
+func (c Color) ContrastingTextColor() Color {
+	black := Color{A: 255}
+	white := Color{R: 255, G: 255, B: 255, A: 255}
+	if ContrastRatio(c, black) >= ContrastRatio(c, white) {
+		return black
+	}
+	return white
+}
+
+// rgb returns the color as 0xRRGGBB
+func (c Color) rgb() uint32 {
+	return uint32(c.R)<<16 | uint32(c.G)<<8 | uint32(c.B)
+}
+
+// parseHexColor parses the digits of a hex color
+func parseHexColor(hex, s string) (Color, error) {
+	if len(hex) == 3 || len(hex) == 4 {
+		var long strings.Builder
+		for _, r := range hex {
+			long.WriteRune(r)
+			long.WriteRune(r)
+		}
+		hex = long.String()
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	if len(hex) != 8 {
+		return Color{}, fmt.Errorf("invalid hex color %q", s)
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return Color{}, fmt.Errorf("invalid hex color %q", s)
+	}
+	return Color{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+}
+
+// colorArguments splits the arguments of a color function, "1, 2, 3, 0.5"
+// or "1 2 3 / 50%", into three or four values
+func colorArguments(args string) ([]string, error) {
+	var parts []string
+	if strings.Contains(args, ",") {
+		for _, part := range strings.Split(args, ",") {
+			parts = append(parts, strings.TrimSpace(part))
+		}
+	} else {
+		channels, alpha, hasAlpha := strings.Cut(args, "/")
+		parts = strings.Fields(channels)
+		if hasAlpha {
+			parts = append(parts, strings.TrimSpace(alpha))
+		}
+	}
+	if len(parts) != 3 && len(parts) != 4 {
+		return nil, fmt.Errorf("expected 3 or 4 values, got %d", len(parts))
+	}
+	return parts, nil
+}
+
+// colorNumber parses a number, or a percentage scaled to max, within 0 and
+// max
+func colorNumber(s string, max float64) (float64, error) {
+	scale := 1.0
+	if number, ok := strings.CutSuffix(s, "%"); ok {
+		s, scale = number, max/100
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	v *= scale
+	if v < 0 || v > max {
+		return 0, fmt.Errorf("value %q out of range", s)
+	}
+	return v, nil
+}
+
+// colorAlpha parses the optional alpha value of a color function
+func colorAlpha(parts []string) (uint8, error) {
+	if len(parts) < 4 {
+		return 255, nil
+	}
+	a, err := colorNumber(parts[3], 1)
+	if err != nil {
+		return 0, err
+	}
+	return uint8(math.Round(a * 255)), nil
+}
+
+// parseRGBColor parses the arguments of rgb() and rgba()
+func parseRGBColor(parts []string, s string) (Color, error) {
+	var channels [3]uint8
+	for i := range channels {
+		v, err := colorNumber(parts[i], 255)
+		if err != nil {
+			return Color{}, fmt.Errorf("invalid color %q: %w", s, err)
+		}
+		channels[i] = uint8(math.Round(v))
+	}
+	a, err := colorAlpha(parts)
+	if err != nil {
+		return Color{}, fmt.Errorf("invalid color %q: %w", s, err)
+	}
+	return Color{R: channels[0], G: channels[1], B: channels[2], A: a}, nil
+}
+
+// parseHSLColor parses the arguments of hsl() and hsla()
+func parseHSLColor(parts []string, s string) (Color, error) {
+	h, err := strconv.ParseFloat(strings.TrimSuffix(parts[0], "deg"), 64)
+	if err != nil {
+		return Color{}, fmt.Errorf("invalid color %q: invalid hue %q", s, parts[0])
+	}
+	if !strings.HasSuffix(parts[1], "%") || !strings.HasSuffix(parts[2], "%") {
+		return Color{}, fmt.Errorf("invalid color %q: saturation and lightness must be percentages", s)
+	}
+	sat, err := colorNumber(parts[1], 1)
+	if err != nil {
+		return Color{}, fmt.Errorf("invalid color %q: %w", s, err)
+	}
+	light, err := colorNumber(parts[2], 1)
+	if err != nil {
+		return Color{}, fmt.Errorf("invalid color %q: %w", s, err)
+	}
+	a, err := colorAlpha(parts)
+	if err != nil {
+		return Color{}, fmt.Errorf("invalid color %q: %w", s, err)
+	}
+
+	// CSS Color 4, Section 7.1
+	h = math.Mod(math.Mod(h, 360)+360, 360)
+	channel := func(n float64) uint8 {
+		k := math.Mod(n+h/30, 12)
+		v := light - sat*math.Min(light, 1-light)*math.Max(-1, math.Min(math.Min(k-3, 9-k), 1))
+		return uint8(math.Round(v * 255))
+	}
+	return Color{R: channel(0), G: channel(8), B: channel(4), A: a}, nil
+}
+
+// cssColorNames holds the keys of cssColors in alphabetical order
+var cssColorNames = sortedKeys(cssColors)
+
+// cssColors maps the CSS named colors to their RGB values
+var cssColors = map[string]uint32{
+	"aliceblue":            0xf0f8ff,
+	"antiquewhite":         0xfaebd7,
+	"aqua":                 0x00ffff,
+	"aquamarine":           0x7fffd4,
+	"azure":                0xf0ffff,
+	"beige":                0xf5f5dc,
+	"bisque":               0xffe4c4,
+	"black":                0x000000,
+	"blanchedalmond":       0xffebcd,
+	"blue":                 0x0000ff,
+	"blueviolet":           0x8a2be2,
+	"brown":                0xa52a2a,
+	"burlywood":            0xdeb887,
+	"cadetblue":            0x5f9ea0,
+	"chartreuse":           0x7fff00,
+	"chocolate":            0xd2691e,
+	"coral":                0xff7f50,
+	"cornflowerblue":       0x6495ed,
+	"cornsilk":             0xfff8dc,
+	"crimson":              0xdc143c,
+	"cyan":                 0x00ffff,
+	"darkblue":             0x00008b,
+	"darkcyan":             0x008b8b,
+	"darkgoldenrod":        0xb8860b,
+	"darkgray":             0xa9a9a9,
+	"darkgreen":            0x006400,
+	"darkgrey":             0xa9a9a9,
+	"darkkhaki":            0xbdb76b,
+	"darkmagenta":          0x8b008b,
+	"darkolivegreen":       0x556b2f,
+	"darkorange":           0xff8c00,
+	"darkorchid":           0x9932cc,
+	"darkred":              0x8b0000,
+	"darksalmon":           0xe9967a,
+	"darkseagreen":         0x8fbc8f,
+	"darkslateblue":        0x483d8b,
+	"darkslategray":        0x2f4f4f,
+	"darkslategrey":        0x2f4f4f,
+	"darkturquoise":        0x00ced1,
+	"darkviolet":           0x9400d3,
+	"deeppink":             0xff1493,
+	"deepskyblue":          0x00bfff,
+	"dimgray":              0x696969,
+	"dimgrey":              0x696969,
+	"dodgerblue":           0x1e90ff,
+	"firebrick":            0xb22222,
+	"floralwhite":          0xfffaf0,
+	"forestgreen":          0x228b22,
+	"fuchsia":              0xff00ff,
+	"gainsboro":            0xdcdcdc,
+	"ghostwhite":           0xf8f8ff,
+	"gold":                 0xffd700,
+	"goldenrod":            0xdaa520,
+	"gray":                 0x808080,
+	"green":                0x008000,
+	"greenyellow":          0xadff2f,
+	"grey":                 0x808080,
+	"honeydew":             0xf0fff0,
+	"hotpink":              0xff69b4,
+	"indianred":            0xcd5c5c,
+	"indigo":               0x4b0082,
+	"ivory":                0xfffff0,
+	"khaki":                0xf0e68c,
+	"lavender":             0xe6e6fa,
+	"lavenderblush":        0xfff0f5,
+	"lawngreen":            0x7cfc00,
+	"lemonchiffon":         0xfffacd,
+	"lightblue":            0xadd8e6,
+	"lightcoral":           0xf08080,
+	"lightcyan":            0xe0ffff,
+	"lightgoldenrodyellow": 0xfafad2,
+	"lightgray":            0xd3d3d3,
+	"lightgreen":           0x90ee90,
+	"lightgrey":            0xd3d3d3,
+	"lightpink":            0xffb6c1,
+	"lightsalmon":          0xffa07a,
+	"lightseagreen":        0x20b2aa,
+	"lightskyblue":         0x87cefa,
+	"lightslategray":       0x778899,
+	"lightslategrey":       0x778899,
+	"lightsteelblue":       0xb0c4de,
+	"lightyellow":          0xffffe0,
+	"lime":                 0x00ff00,
+	"limegreen":            0x32cd32,
+	"linen":                0xfaf0e6,
+	"magenta":              0xff00ff,
+	"maroon":               0x800000,
+	"mediumaquamarine":     0x66cdaa,
+	"mediumblue":           0x0000cd,
+	"mediumorchid":         0xba55d3,
+	"mediumpurple":         0x9370db,
+	"mediumseagreen":       0x3cb371,
+	"mediumslateblue":      0x7b68ee,
+	"mediumspringgreen":    0x00fa9a,
+	"mediumturquoise":      0x48d1cc,
+	"mediumvioletred":      0xc71585,
+	"midnightblue":         0x191970,
+	"mintcream":            0xf5fffa,
+	"mistyrose":            0xffe4e1,
+	"moccasin":             0xffe4b5,
+	"navajowhite":          0xffdead,
+	"navy":                 0x000080,
+	"oldlace":              0xfdf5e6,
+	"olive":                0x808000,
+	"olivedrab":            0x6b8e23,
+	"orange":               0xffa500,
+	"orangered":            0xff4500,
+	"orchid":               0xda70d6,
+	"palegoldenrod":        0xeee8aa,
+	"palegreen":            0x98fb98,
+	"paleturquoise":        0xafeeee,
+	"palevioletred":        0xdb7093,
+	"papayawhip":           0xffefd5,
+	"peachpuff":            0xffdab9,
+	"peru":                 0xcd853f,
+	"pink":                 0xffc0cb,
+	"plum":                 0xdda0dd,
+	"powderblue":           0xb0e0e6,
+	"purple":               0x800080,
+	"rebeccapurple":        0x663399,
+	"red":                  0xff0000,
+	"rosybrown":            0xbc8f8f,
+	"royalblue":            0x4169e1,
+	"saddlebrown":          0x8b4513,
+	"salmon":               0xfa8072,
+	"sandybrown":           0xf4a460,
+	"seagreen":             0x2e8b57,
+	"seashell":             0xfff5ee,
+	"sienna":               0xa0522d,
+	"silver":               0xc0c0c0,
+	"skyblue":              0x87ceeb,
+	"slateblue":            0x6a5acd,
+	"slategray":            0x708090,
+	"slategrey":            0x708090,
+	"snow":                 0xfffafa,
+	"springgreen":          0x00ff7f,
+	"steelblue":            0x4682b4,
+	"tan":                  0xd2b48c,
+	"teal":                 0x008080,
+	"thistle":              0xd8bfd8,
+	"tomato":               0xff6347,
+	"turquoise":            0x40e0d0,
+	"violet":               0xee82ee,
+	"wheat":                0xf5deb3,
+	"white":                0xffffff,
+	"whitesmoke":           0xf5f5f5,
+	"yellow":               0xffff00,
+	"yellowgreen":          0x9acd32,
+}
@@ -0,0 +1,117 @@
+package jscal
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"red", "#ff0000", false},
+		{" RebeccaPurple ", "#663399", false},
+		{"transparent", "#00000000", false},
+		{"#F00", "#ff0000", false},
+		{"#f008", "#ff000088", false},
+		{"#FF5733", "#ff5733", false},
+		{"#FF2968FF", "#ff2968", false},
+		{"rgb(255, 87, 51)", "#ff5733", false},
+		{"rgba(255, 87, 51, 0.5)", "#ff573380", false},
+		{"rgb(100%, 0%, 0%)", "#ff0000", false},
+		{"rgb(255 87 51 / 50%)", "#ff573380", false},
+		{"hsl(120, 100%, 25%)", "#008000", false},
+		{"hsla(240deg, 100%, 50%, 1)", "#0000ff", false},
+		{"hsl(-120 100% 50%)", "#0000ff", false},
+		{"", "", true},
+		{"notacolor", "", true},
+		{"#ff00", "#ffff0000", false},
+		{"#ff000", "", true},
+		{"#gg0000", "", true},
+		{"rgb(256, 0, 0)", "", true},
+		{"rgb(255, 0)", "", true},
+		{"rgb(255, 0, 0", "", true},
+		{"rgba(0, 0, 0, 2)", "", true},
+		{"hsl(0, 100, 50)", "", true},
+		{"cmyk(0, 0, 0, 0)", "", true},
+	}
+
+	for _, tt := range tests {
+		c, err := ParseColor(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseColor(%q): expected error %v, got %v", tt.input, tt.wantErr, err)
+			continue
+		}
+		if err == nil && c.Hex() != tt.want {
+			t.Errorf("ParseColor(%q): expected %s, got %s", tt.input, tt.want, c.Hex())
+		}
+	}
+}
+
+func TestNormalizeColor(t *testing.T) {
+	if got, err := NormalizeColor("Teal"); err != nil || got != "#008080" {
+		t.Errorf("Expected #008080, got %s (%v)", got, err)
+	}
+	if _, err := NormalizeColor("teal-ish"); err == nil {
+		t.Error("Expected error for invalid color")
+	}
+}
+
+func TestColorNames(t *testing.T) {
+	tests := []struct {
+		color   Color
+		name    string
+		named   bool
+		nearest string
+	}{
+		{Color{R: 255, A: 255}, "red", true, "red"},
+		{Color{R: 128, G: 128, B: 128, A: 255}, "gray", true, "gray"},
+		{Color{G: 255, B: 255, A: 255}, "aqua", true, "aqua"},
+		{Color{R: 250, G: 2, B: 3, A: 255}, "", false, "red"},
+		{Color{R: 255, A: 128}, "", false, "red"},
+	}
+
+	for _, tt := range tests {
+		name, ok := tt.color.Name()
+		if name != tt.name || ok != tt.named {
+			t.Errorf("Name(%s): expected %q %v, got %q %v", tt.color, tt.name, tt.named, name, ok)
+		}
+		if got := tt.color.NearestName(); got != tt.nearest {
+			t.Errorf("NearestName(%s): expected %s, got %s", tt.color, tt.nearest, got)
+		}
+	}
+}
+
+func TestContrast(t *testing.T) {
+	black := Color{A: 255}
+	white := Color{R: 255, G: 255, B: 255, A: 255}
+	if ratio := ContrastRatio(black, white); math.Abs(ratio-21) > 0.001 {
+		t.Errorf("Expected contrast ratio 21, got %f", ratio)
+	}
+	if ratio := ContrastRatio(white, white); ratio != 1 {
+		t.Errorf("Expected contrast ratio 1, got %f", ratio)
+	}
+
+	tests := []struct {
+		background string
+		want       Color
+	}{
+		{"white", black},
+		{"yellow", black},
+		{"#FF5733", black},
+		{"navy", white},
+		{"black", white},
+		{"#663399", white},
+	}
+	for _, tt := range tests {
+		c, err := ParseColor(tt.background)
+		if err != nil {
+			t.Fatalf("ParseColor(%q) failed: %v", tt.background, err)
+		}
+		if got := c.ContrastingTextColor(); got != tt.want {
+			t.Errorf("ContrastingTextColor(%s): expected %s, got %s", tt.background, tt.want, got)
+		}
+	}
+}
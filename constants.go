@@ -57,6 +57,13 @@ const (
 	ScheduleAgentNone   = "none"
 )
 
+// Methods of replyTo and sendTo, mapped to URIs (RFC 8984 Section 4.4.4)
+const (
+	ReplyMethodImip  = "imip"  // iMIP, a mailto: URI
+	ReplyMethodWeb   = "web"   // A web page to reply on, an https: URI
+	ReplyMethodOther = "other" // Any URI, with no defined way to reply
+)
+
 // Method values (RFC 8984 Section 4.1.8)
 const (
 	MethodPublish        = "publish"
@@ -92,6 +99,14 @@ const (
 	PriorityDefault = 0
 )
 
+// Priorities of the three-level scale in RFC 5545 Section 3.8.1.9, see
+// PriorityLevel
+const (
+	PriorityHigh   = 1
+	PriorityNormal = 5
+	PriorityLow    = 9
+)
+
 // Alert trigger relationships
 const (
 	AlertTriggerStart = "start"
@@ -0,0 +1,117 @@
+package convert
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Charsets reported by DecodeText and accepted by DecodeCharset
+const (
+	CharsetUTF8        = "utf-8"
+	CharsetUTF16LE     = "utf-16le"
+	CharsetUTF16BE     = "utf-16be"
+	CharsetLatin1      = "iso-8859-1"
+	CharsetWindows1252 = "windows-1252"
+)
+
+// windows1252 maps the bytes 0x80-0x9F of Windows-1252 to runes. The other
+// bytes are the same as in Latin-1; the five undefined ones map to the C1
+// control with their value, as browsers decode them.
+var windows1252 = [32]rune{
+	'€', 0x81, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0x8D, 'Ž', 0x8F,
+	0x90, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0x9D, 'ž', 'Ÿ',
+}
+
+// DecodeText returns data as UTF-8 without a byte order mark, and the
+// charset it was in. UTF-16 is recognized by its byte order mark or, without
+// one, by the zero bytes of ASCII characters; data that isn't valid UTF-8 is
+// read as Windows-1252, the superset of Latin-1 that files labeled Latin-1
+// are usually in. UTF-8 data is returned without copying.
+func DecodeText(data []byte) ([]byte, string) {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}):
+		return data[3:], CharsetUTF8
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}):
+		return decodeUTF16(data[2:], false), CharsetUTF16LE
+	case bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		return decodeUTF16(data[2:], true), CharsetUTF16BE
+	}
+
+	if len(data) >= 4 && len(data)%2 == 0 {
+		switch {
+		case data[0] == 0 && data[1] != 0 && data[2] == 0 && data[3] != 0:
+			return decodeUTF16(data, true), CharsetUTF16BE
+		case data[0] != 0 && data[1] == 0 && data[2] != 0 && data[3] == 0:
+			return decodeUTF16(data, false), CharsetUTF16LE
+		}
+	}
+
+	if utf8.Valid(data) {
+		return data, CharsetUTF8
+	}
+	return decodeSingleByte(data, true), CharsetWindows1252
+}
+
+// DecodeCharset converts data in the named charset to UTF-8, e.g. from the
+// charset parameter of a Content-Type header. Names are case-insensitive
+// and include common aliases such as "latin1" and "cp1252"; "utf-16"
+// reads the byte order mark, defaulting to big endian. An empty name
+// detects the charset with DecodeText. A leading byte order mark is
+// dropped; invalid UTF-8 is kept as it is.
+func DecodeCharset(data []byte, charset string) ([]byte, error) {
+	switch strings.ToLower(strings.TrimSpace(charset)) {
+	case "":
+		text, _ := DecodeText(data)
+		return text, nil
+	case "utf-8", "utf8", "us-ascii", "ascii":
+		return bytes.TrimPrefix(data, []byte{0xEF, 0xBB, 0xBF}), nil
+	case "utf-16le":
+		return decodeUTF16(bytes.TrimPrefix(data, []byte{0xFF, 0xFE}), false), nil
+	case "utf-16be":
+		return decodeUTF16(bytes.TrimPrefix(data, []byte{0xFE, 0xFF}), true), nil
+	case "utf-16":
+		if bytes.HasPrefix(data, []byte{0xFF, 0xFE}) {
+			return decodeUTF16(data[2:], false), nil
+		}
+		return decodeUTF16(bytes.TrimPrefix(data, []byte{0xFE, 0xFF}), true), nil
+	case "iso-8859-1", "iso8859-1", "latin1", "latin-1", "l1":
+		return decodeSingleByte(data, false), nil
+	case "windows-1252", "cp1252", "x-cp1252":
+		return decodeSingleByte(data, true), nil
+	}
+	return nil, fmt.Errorf("unsupported charset %q", charset)
+}
+
+// decodeSingleByte converts Latin-1, or Windows-1252 if cp1252 is set, to
+// UTF-8
+func decodeSingleByte(data []byte, cp1252 bool) []byte {
+	var b strings.Builder
+	b.Grow(len(data) + len(data)/4)
+	for _, c := range data {
+		switch {
+		case c < utf8.RuneSelf:
+			b.WriteByte(c)
+		case cp1252 && c < 0xA0:
+			b.WriteRune(windows1252[c-0x80])
+		default:
+			b.WriteRune(rune(c))
+		}
+	}
+	return []byte(b.String())
+}
+
+// decodeUTF16 converts UTF-16 to UTF-8, dropping a trailing odd byte
+func decodeUTF16(data []byte, bigEndian bool) []byte {
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		if bigEndian {
+			units[i] = uint16(data[2*i])<<8 | uint16(data[2*i+1])
+		} else {
+			units[i] = uint16(data[2*i+1])<<8 | uint16(data[2*i])
+		}
+	}
+	return []byte(string(utf16.Decode(units)))
+}
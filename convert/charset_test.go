@@ -0,0 +1,58 @@
+package convert
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDecodeText(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    string
+		charset string
+	}{
+		{"utf-8", []byte("Café"), "Café", CharsetUTF8},
+		{"utf-8 bom", []byte("\xEF\xBB\xBFCafé"), "Café", CharsetUTF8},
+		{"utf-16le bom", []byte{0xFF, 0xFE, 'C', 0, 'a', 0, 'f', 0, 0xE9, 0}, "Café", CharsetUTF16LE},
+		{"utf-16be bom", []byte{0xFE, 0xFF, 0, 'C', 0, 'a', 0, 'f', 0, 0xE9}, "Café", CharsetUTF16BE},
+		{"utf-16be", []byte{0, 'C', 0, 'a', 0, 'f', 0, 0xE9}, "Café", CharsetUTF16BE},
+		{"windows-1252", []byte("Caf\xE9 \x93quoted\x94 \x80"), "Café “quoted” €", CharsetWindows1252},
+	}
+
+	for _, tt := range tests {
+		got, charset := DecodeText(tt.data)
+		if !bytes.Equal(got, []byte(tt.want)) || charset != tt.charset {
+			t.Errorf("%s: expected %q in %s, got %q in %s", tt.name, tt.want, tt.charset, got, charset)
+		}
+	}
+}
+
+func TestDecodeCharset(t *testing.T) {
+	tests := []struct {
+		charset string
+		data    []byte
+		want    string
+		wantErr bool
+	}{
+		{"", []byte("Caf\xE9"), "Café", false},
+		{"UTF-8", []byte("\xEF\xBB\xBFCafé"), "Café", false},
+		{"latin1", []byte("Caf\xE9 \x80"), "Café \u0080", false},
+		{"cp1252", []byte("Caf\xE9 \x80"), "Café €", false},
+		{"utf-16", []byte{0xFF, 0xFE, 'O', 0, 'K', 0}, "OK", false},
+		{"utf-16", []byte{0, 'O', 0, 'K'}, "OK", false},
+		{"utf-16le", []byte{'O', 0, 'K', 0}, "OK", false},
+		{"koi8-r", []byte("x"), "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := DecodeCharset(tt.data, tt.charset)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("DecodeCharset(%q): expected error %v, got %v", tt.charset, tt.wantErr, err)
+			continue
+		}
+		if err == nil && string(got) != tt.want {
+			t.Errorf("DecodeCharset(%q): expected %q, got %q", tt.charset, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package convert
+
+import (
+	"context"
+
+	"github.com/airtrafik/jscal"
+)
+
+// ContextConverter is implemented by converters that can stop a batch
+// conversion once a context is done
+type ContextConverter interface {
+	// ParseAllContext is ParseAll returning the context's error once ctx
+	// is done
+	ParseAllContext(ctx context.Context, data []byte) ([]*jscal.Event, error)
+
+	// FormatAllContext is FormatAll returning the context's error once ctx
+	// is done
+	FormatAllContext(ctx context.Context, events []*jscal.Event) ([]byte, error)
+}
+
+// ContextLenientParser is implemented by lenient converters that can stop a
+// batch conversion once a context is done
+type ContextLenientParser interface {
+	ParseAllLenientContext(ctx context.Context, data []byte) ([]*jscal.Event, []*ItemError, error)
+}
+
+// ParseAllContext parses data with c, stopping once ctx is done if c
+// implements ContextConverter. Other converters run to completion, but
+// aren't started if ctx is already done.
+func ParseAllContext(ctx context.Context, c Converter, data []byte) ([]*jscal.Event, error) {
+	if cc, ok := c.(ContextConverter); ok {
+		return cc.ParseAllContext(ctx, data)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.ParseAll(data)
+}
+
+// FormatAllContext formats events with c, stopping once ctx is done if c
+// implements ContextConverter, see ParseAllContext
+func FormatAllContext(ctx context.Context, c Converter, events []*jscal.Event) ([]byte, error) {
+	if cc, ok := c.(ContextConverter); ok {
+		return cc.FormatAllContext(ctx, events)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.FormatAll(events)
+}
+
+// ParseAllLenientContext is ParseAllLenient stopping once ctx is done if c
+// implements ContextLenientParser, see ParseAllContext
+func ParseAllLenientContext(ctx context.Context, c Converter, data []byte) ([]*jscal.Event, []*ItemError, error) {
+	if lenient, ok := c.(ContextLenientParser); ok {
+		return lenient.ParseAllLenientContext(ctx, data)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	return ParseAllLenient(c, data)
+}
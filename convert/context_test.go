@@ -0,0 +1,57 @@
+package convert
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/airtrafik/jscal"
+)
+
+// contextLines is a lineConverter that checks its context before each line
+type contextLines struct{ lineConverter }
+
+func (contextLines) ParseAllContext(ctx context.Context, data []byte) ([]*jscal.Event, error) {
+	var events []*jscal.Event
+	for _, line := range strings.Split(string(data), "\n")[1:] {
+		if err := ctx.Err(); err != nil {
+			return events, err
+		}
+		events = append(events, newSourceEvent(strings.TrimSpace(line), "From lines"))
+	}
+	return events, nil
+}
+
+func (contextLines) FormatAllContext(ctx context.Context, events []*jscal.Event) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return []byte("LINES"), nil
+}
+
+func TestParseAllContext(t *testing.T) {
+	data := []byte("LINES\na\nb")
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, c := range []Converter{lineConverter{}, contextLines{}} {
+		events, err := ParseAllContext(context.Background(), c, data)
+		if err != nil || len(events) != 2 {
+			t.Errorf("%T: Expected 2 events, got %d and %v", c, len(events), err)
+		}
+		if _, err := ParseAllContext(canceled, c, data); !errors.Is(err, context.Canceled) {
+			t.Errorf("%T: Expected context.Canceled, got %v", c, err)
+		}
+		if _, _, err := ParseAllLenientContext(canceled, c, data); !errors.Is(err, context.Canceled) {
+			t.Errorf("%T: Expected context.Canceled from lenient parsing, got %v", c, err)
+		}
+		if _, err := FormatAllContext(canceled, c, events); !errors.Is(err, context.Canceled) {
+			t.Errorf("%T: Expected context.Canceled from formatting, got %v", c, err)
+		}
+	}
+
+	if data, err := FormatAllContext(context.Background(), contextLines{}, nil); err != nil || string(data) != "LINES" {
+		t.Errorf("Expected the converter's FormatAllContext, got %q and %v", data, err)
+	}
+}
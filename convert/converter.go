@@ -15,3 +15,13 @@ type Converter interface {
 	// Detection
 	Detect(data []byte) bool
 }
+
+// GroupConverter is implemented by converters whose format has a calendar
+// level, such as an iCalendar VCALENDAR, and can keep it as a Group
+type GroupConverter interface {
+	// ParseCalendar returns the calendar as a Group holding its entries
+	ParseCalendar(data []byte) (*jscal.Group, error)
+
+	// FormatGroup writes the Group as a calendar
+	FormatGroup(group *jscal.Group) ([]byte, error)
+}
@@ -0,0 +1,29 @@
+// Package convert defines the Converter interface shared by all calendar
+// format converters and a registry to look them up by format name.
+//
+// The core jscal module has no external dependencies, and this package keeps
+// it that way: it only holds the interface, the registry and format-neutral
+// helpers such as RefreshFromSource. Each converter that needs a third-party
+// library (iCalendar parsing, Google Calendar, Microsoft Graph, CalDAV, SQLite
+// and so on) lives in its own nested module with its own go.mod, for example
+// github.com/airtrafik/jscal/convert/ical. Users who only need the JSCalendar
+// types never download those dependencies.
+//
+// A converter module registers itself from an init function:
+//
+//	func init() {
+//		convert.Register(convert.Registration{
+//			Name:       "ical",
+//			Aliases:    []string{"icalendar", "ics"},
+//			Extensions: []string{".ics", ".ical"},
+//			New:        func() convert.Converter { return New() },
+//		})
+//	}
+//
+// and applications enable it with a blank import, as with database/sql drivers:
+//
+//	import _ "github.com/airtrafik/jscal/convert/ical"
+//
+//	c, ok := convert.Lookup("ics")
+//	events, err := c.ParseAll(data)
+package convert
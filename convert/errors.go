@@ -0,0 +1,33 @@
+package convert
+
+import "fmt"
+
+// ConversionError reports the item a conversion failed on. Converters
+// return it, possibly wrapped, so callers can find it with errors.As and
+// point users at the source, while errors.Is still sees the cause.
+type ConversionError struct {
+	Item string // Kind of item, e.g. "event" or "journal entry"
+	UID  string // UID of the item, if known
+	Line int    // Line the item starts on in the source, from 1; 0 if unknown
+	Err  error
+}
+
+// Error formats the error as "failed to convert event x (line 42): ..."
+func (e *ConversionError) Error() string {
+	item := e.Item
+	if item == "" {
+		item = "item"
+	}
+	if e.UID != "" {
+		item += " " + e.UID
+	}
+	if e.Line > 0 {
+		item += fmt.Sprintf(" (line %d)", e.Line)
+	}
+	return fmt.Sprintf("failed to convert %s: %v", item, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *ConversionError) Unwrap() error {
+	return e.Err
+}
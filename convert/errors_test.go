@@ -0,0 +1,27 @@
+package convert
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestConversionError(t *testing.T) {
+	cause := errors.New("invalid DTSTART")
+	tests := []struct {
+		err      *ConversionError
+		expected string
+	}{
+		{&ConversionError{Item: "event", UID: "e1", Line: 42, Err: cause}, "failed to convert event e1 (line 42): invalid DTSTART"},
+		{&ConversionError{Item: "event", UID: "e1", Err: cause}, "failed to convert event e1: invalid DTSTART"},
+		{&ConversionError{Line: 7, Err: cause}, "failed to convert item (line 7): invalid DTSTART"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.expected {
+			t.Errorf("Expected %q, got %q", tt.expected, got)
+		}
+		if !errors.Is(tt.err, cause) {
+			t.Errorf("Expected %v to wrap its cause", tt.err)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package ical
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/airtrafik/jscal"
+	ics "github.com/arran4/golang-ical"
+)
+
+// CalScaleGregorian is the only calendar scale defined by RFC 5545 and the
+// default when a VCALENDAR has no CALSCALE property
+const CalScaleGregorian = "GREGORIAN"
+
+// DefaultProductID is the PRODID written when FormatOptions don't set one
+const DefaultProductID = "-//AirTrafik//JSCal Go Library//EN"
+
+// CalendarMetadata holds calendar-level (VCALENDAR) properties retained while parsing
+type CalendarMetadata struct {
+	// CalScale is the calendar scale of the source data, GREGORIAN when absent
+	CalScale string
+
+	UID             string // UID (RFC 7986)
+	ProductID       string // PRODID
+	Method          string // METHOD (RFC 5546), e.g. PUBLISH or REQUEST
+	Name            string // NAME (RFC 7986) or X-WR-CALNAME
+	Description     string // DESCRIPTION (RFC 7986) or X-WR-CALDESC
+	TimeZone        string // X-WR-TIMEZONE
+	RefreshInterval string // REFRESH-INTERVAL (RFC 7986) or X-PUBLISHED-TTL, a duration such as PT1H
+	Color           string // COLOR (RFC 7986) or X-APPLE-CALENDAR-COLOR
+	Source          string // SOURCE (RFC 7986), the URI the calendar is refreshed from
+}
+
+// propertyAppleCalendarColor is the calendar color written by Apple
+// Calendar, a hex color such as #FF2968FF
+const propertyAppleCalendarColor = "X-APPLE-CALENDAR-COLOR"
+
+// FormatOptions controls the calendar-level properties written by
+// FormatAllWithOptions, and how some event properties are written
+type FormatOptions struct {
+	// ProductID is written as PRODID, DefaultProductID when empty
+	ProductID string
+
+	// Calendar holds the other calendar-level properties to write, for
+	// example the metadata returned by ParseAllWithMetadata. Its ProductID
+	// is ignored, as PRODID names the product writing the data. Empty fields
+	// are omitted.
+	Calendar *CalendarMetadata
+
+	// Locations selects how events with several locations are written
+	Locations LocationMode
+
+	// LocationAddresses appends the address of each location to its
+	// LOCATION on one line, as in "HQ, Stephansplatz 1, 1010 Wien, AT".
+	// A location with an address but no name is written as its address
+	// either way.
+	LocationAddresses bool
+}
+
+// LocationMode selects how the locations of an event are written. RFC 5545
+// allows a single LOCATION, so each mode trades compliance for compatibility.
+type LocationMode int
+
+const (
+	// LocationsMultiple writes one LOCATION per location. Most clients read
+	// them all, though some only show the first.
+	LocationsMultiple LocationMode = iota
+
+	// LocationsAppleStructured writes the name of the first location as the
+	// only LOCATION and an X-APPLE-STRUCTURED-LOCATION for each location with
+	// coordinates, as Apple Calendar does. Other locations without
+	// coordinates are dropped.
+	LocationsAppleStructured
+)
+
+// colorName returns the CSS name for the RFC 7986 COLOR property, which
+// doesn't take other color values: the color's own name, or the name of
+// the nearest color. Transparent colors have none.
+func colorName(value string) (string, bool) {
+	c, err := jscal.ParseColor(value)
+	if err != nil || c.A == 0 {
+		return "", false
+	}
+	if name, ok := c.Name(); ok {
+		return name, true
+	}
+	return c.NearestName(), true
+}
+
+// nearestColorName returns the CSS name colorName writes for a color, or ""
+func nearestColorName(value string) string {
+	name, _ := colorName(value)
+	return name
+}
+
+// UnsupportedCalScaleError is returned when the source declares a CALSCALE
+// other than GREGORIAN. Date-time values in such a calendar cannot be read as
+// Gregorian dates, so the data is rejected rather than silently misinterpreted.
+type UnsupportedCalScaleError struct {
+	CalScale string
+}
+
+func (e *UnsupportedCalScaleError) Error() string {
+	return fmt.Sprintf("unsupported calendar scale %q: only %s is supported", e.CalScale, CalScaleGregorian)
+}
+
+// parseCalendarMetadata extracts calendar-level properties from a parsed VCALENDAR.
+// The RFC 7986 properties take precedence over their X-WR- counterparts.
+func parseCalendarMetadata(cal *ics.Calendar) *CalendarMetadata {
+	metadata := &CalendarMetadata{
+		CalScale: CalScaleGregorian,
+	}
+
+	var xName, xDescription, xTTL, xColor string
+	for _, prop := range cal.CalendarProperties {
+		value := strings.TrimSpace(prop.Value)
+		switch strings.ToUpper(prop.IANAToken) {
+		case string(ics.PropertyCalscale):
+			if value != "" {
+				metadata.CalScale = strings.ToUpper(value)
+			}
+		case string(ics.PropertyProductId):
+			metadata.ProductID = value
+		case string(ics.PropertyMethod):
+			metadata.Method = strings.ToUpper(value)
+		case string(ics.PropertyName):
+			metadata.Name = value
+		case string(ics.PropertyXWRCalName):
+			xName = value
+		case string(ics.PropertyDescription):
+			metadata.Description = value
+		case string(ics.PropertyXWRCalDesc):
+			xDescription = value
+		case string(ics.PropertyXWRTimezone):
+			metadata.TimeZone = value
+		case "REFRESH-INTERVAL":
+			metadata.RefreshInterval = value
+		case string(ics.PropertyXPublishedTTL):
+			xTTL = value
+		case string(ics.PropertyColor):
+			metadata.Color = value
+		case propertyAppleCalendarColor:
+			xColor = value
+		case string(ics.PropertyUid):
+			metadata.UID = value
+		case "SOURCE":
+			metadata.Source = value
+		}
+	}
+
+	if metadata.Name == "" {
+		metadata.Name = xName
+	}
+	if metadata.Description == "" {
+		metadata.Description = xDescription
+	}
+	if metadata.RefreshInterval == "" {
+		metadata.RefreshInterval = xTTL
+	}
+	// X-APPLE-CALENDAR-COLOR is the exact color when COLOR names the
+	// nearest one, as setCalendarProperties writes them
+	if metadata.Color == "" || nearestColorName(xColor) == strings.ToLower(metadata.Color) {
+		metadata.Color = xColor
+	}
+	return metadata
+}
+
+// setCalendarProperties writes calendar-level properties. Name, description
+// and refresh interval are written both as RFC 7986 properties and as their
+// X-WR- counterparts, which many clients only understand.
+func setCalendarProperties(cal *ics.Calendar, opts FormatOptions) {
+	productID := opts.ProductID
+	if productID == "" {
+		productID = DefaultProductID
+	}
+	cal.SetProductId(productID)
+	cal.SetVersion("2.0")
+
+	m := opts.Calendar
+	if m == nil {
+		return
+	}
+	if m.CalScale != "" {
+		cal.SetCalscale(m.CalScale)
+	}
+	if m.Method != "" {
+		cal.SetMethod(ics.Method(m.Method))
+	}
+	if m.Name != "" {
+		cal.SetName(m.Name) // Also sets X-WR-CALNAME
+	}
+	if m.Description != "" {
+		cal.SetDescription(m.Description)
+		cal.SetXWRCalDesc(m.Description)
+	}
+	if m.TimeZone != "" {
+		cal.SetXWRTimezone(m.TimeZone)
+	}
+	if m.RefreshInterval != "" {
+		cal.SetRefreshInterval(m.RefreshInterval)
+		cal.SetXPublishedTTL(m.RefreshInterval)
+	}
+	if m.Color != "" {
+		if name, ok := colorName(m.Color); ok {
+			cal.SetColor(name)
+		} else {
+			cal.SetColor(m.Color)
+		}
+		if strings.HasPrefix(m.Color, "#") {
+			addCalendarProperty(cal, propertyAppleCalendarColor, m.Color)
+		}
+	}
+	if m.UID != "" {
+		addCalendarProperty(cal, string(ics.PropertyUid), m.UID)
+	}
+	if m.Source != "" {
+		addCalendarProperty(cal, "SOURCE", m.Source, ics.WithValue("URI"))
+	}
+}
+
+// addCalendarProperty adds a calendar-level property golang-ical has no setter for
+func addCalendarProperty(cal *ics.Calendar, name, value string, params ...ics.PropertyParameter) {
+	prop := ics.CalendarProperty{BaseProperty: ics.BaseProperty{IANAToken: name, Value: value, ICalParameters: map[string][]string{}}}
+	for _, param := range params {
+		key, values := param.KeyValue()
+		prop.ICalParameters[key] = values
+	}
+	cal.CalendarProperties = append(cal.CalendarProperties, prop)
+}
+
+// checkCalScale returns an *UnsupportedCalScaleError for non-Gregorian calendars
+func checkCalScale(metadata *CalendarMetadata) error {
+	if metadata.CalScale != CalScaleGregorian {
+		return &UnsupportedCalScaleError{CalScale: metadata.CalScale}
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+package ical
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/airtrafik/jscal"
+)
+
+func TestParseCalScale(t *testing.T) {
+	tests := []struct {
+		name         string
+		calscale     string
+		wantCalScale string
+		wantErr      bool
+	}{
+		{
+			name:         "absent defaults to gregorian",
+			calscale:     "",
+			wantCalScale: CalScaleGregorian,
+		},
+		{
+			name:         "explicit gregorian",
+			calscale:     "CALSCALE:GREGORIAN\n",
+			wantCalScale: CalScaleGregorian,
+		},
+		{
+			name:         "lowercase gregorian",
+			calscale:     "CALSCALE:gregorian\n",
+			wantCalScale: CalScaleGregorian,
+		},
+		{
+			name:         "hebrew rejected",
+			calscale:     "CALSCALE:HEBREW\n",
+			wantCalScale: "HEBREW",
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			icalData := `BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//Test//Test//EN
+` + tt.calscale + `BEGIN:VEVENT
+UID:calscale@example.com
+SUMMARY:Calendar Scale
+DTSTART:20250301T140000Z
+END:VEVENT
+END:VCALENDAR`
+
+			events, metadata, err := New().ParseAllWithMetadata([]byte(icalData))
+			if metadata == nil {
+				t.Fatalf("Expected metadata, got nil (err: %v)", err)
+			}
+			if metadata.CalScale != tt.wantCalScale {
+				t.Errorf("Expected CalScale %s, got %s", tt.wantCalScale, metadata.CalScale)
+			}
+
+			if tt.wantErr {
+				var calScaleErr *UnsupportedCalScaleError
+				if !errors.As(err, &calScaleErr) {
+					t.Fatalf("Expected *UnsupportedCalScaleError, got %v", err)
+				}
+				if calScaleErr.CalScale != tt.wantCalScale {
+					t.Errorf("Expected error for %s, got %s", tt.wantCalScale, calScaleErr.CalScale)
+				}
+				if events != nil {
+					t.Errorf("Expected no events, got %d", len(events))
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(events) != 1 {
+				t.Errorf("Expected 1 event, got %d", len(events))
+			}
+		})
+	}
+}
+
+func TestParseAllRejectsNonGregorian(t *testing.T) {
+	icalData := `BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//Test//Test//EN
+CALSCALE:CHINESE
+BEGIN:VEVENT
+UID:chinese@example.com
+DTSTART:20250301T140000Z
+END:VEVENT
+END:VCALENDAR`
+
+	_, err := New().ParseAll([]byte(icalData))
+	var calScaleErr *UnsupportedCalScaleError
+	if !errors.As(err, &calScaleErr) {
+		t.Fatalf("Expected *UnsupportedCalScaleError from ParseAll, got %v", err)
+	}
+}
+
+func TestCalendarMetadataRoundTrip(t *testing.T) {
+	icalData := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Example//Holidays//EN",
+		"METHOD:publish",
+		"X-WR-CALNAME:Public Holidays",
+		"X-WR-CALDESC:Days off",
+		"X-WR-TIMEZONE:Europe/Berlin",
+		"X-PUBLISHED-TTL:P1D",
+		"COLOR:crimson",
+		"BEGIN:VEVENT",
+		"UID:xmas@example.com",
+		"SUMMARY:Christmas",
+		"DTSTART;VALUE=DATE:20251225",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	converter := New()
+	events, metadata, err := converter.ParseAllWithMetadata([]byte(icalData))
+	if err != nil {
+		t.Fatalf("ParseAllWithMetadata failed: %v", err)
+	}
+	want := CalendarMetadata{
+		CalScale:        CalScaleGregorian,
+		ProductID:       "-//Example//Holidays//EN",
+		Method:          "PUBLISH",
+		Name:            "Public Holidays",
+		Description:     "Days off",
+		TimeZone:        "Europe/Berlin",
+		RefreshInterval: "P1D",
+		Color:           "crimson",
+	}
+	if *metadata != want {
+		t.Errorf("Expected metadata %+v, got %+v", want, *metadata)
+	}
+
+	output, err := converter.FormatAllWithOptions(events, FormatOptions{Calendar: metadata})
+	if err != nil {
+		t.Fatalf("FormatAllWithOptions failed: %v", err)
+	}
+	for _, line := range []string{
+		"PRODID:" + DefaultProductID,
+		"METHOD:PUBLISH",
+		"NAME:Public Holidays",
+		"X-WR-CALNAME:Public Holidays",
+		"DESCRIPTION:Days off",
+		"X-WR-CALDESC:Days off",
+		"X-WR-TIMEZONE:Europe/Berlin",
+		"REFRESH-INTERVAL;VALUE=DURATION:P1D",
+		"X-PUBLISHED-TTL:P1D",
+		"COLOR:crimson",
+	} {
+		if !strings.Contains(string(output), line+"\r\n") {
+			t.Errorf("Expected %s in output:\n%s", line, output)
+		}
+	}
+
+	_, reparsed, err := converter.ParseAllWithMetadata(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reparsed.ProductID = metadata.ProductID
+	if *reparsed != *metadata {
+		t.Errorf("Expected metadata to survive a round trip, got %+v", *reparsed)
+	}
+
+	output, err = converter.FormatAllWithOptions(events, FormatOptions{ProductID: "-//Acme//Sync//EN"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(output), "PRODID:-//Acme//Sync//EN") || strings.Contains(string(output), "METHOD") {
+		t.Errorf("Expected custom PRODID and no METHOD, got:\n%s", output)
+	}
+}
+
+func TestCalendarColor(t *testing.T) {
+	tests := []struct {
+		name      string
+		color     string
+		lines     []string
+		reparsed  string
+		withApple bool
+	}{
+		{"named", "Crimson", []string{"COLOR:crimson"}, "crimson", false},
+		{"hex with a name", "#DC143C", []string{"COLOR:crimson", "X-APPLE-CALENDAR-COLOR:#DC143C"}, "#DC143C", true},
+		{"hex without a name", "#FF2968FF", []string{"COLOR:deeppink", "X-APPLE-CALENDAR-COLOR:#FF2968FF"}, "#FF2968FF", true},
+		{"rgb", "rgb(0, 128, 128)", []string{"COLOR:teal"}, "teal", false},
+		{"invalid", "not-a-color", []string{"COLOR:not-a-color"}, "not-a-color", false},
+	}
+
+	event := newCompatEvents()[1]
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := New().FormatAllWithOptions([]*jscal.Event{event}, FormatOptions{Calendar: &CalendarMetadata{Color: tt.color}})
+			if err != nil {
+				t.Fatalf("FormatAllWithOptions failed: %v", err)
+			}
+			for _, line := range tt.lines {
+				if !strings.Contains(string(output), line+"\r\n") {
+					t.Errorf("Expected %s in output:\n%s", line, output)
+				}
+			}
+			if strings.Contains(string(output), "X-APPLE-CALENDAR-COLOR") != tt.withApple {
+				t.Errorf("Expected X-APPLE-CALENDAR-COLOR %v:\n%s", tt.withApple, output)
+			}
+
+			_, metadata, err := New().ParseAllWithMetadata(output)
+			if err != nil {
+				t.Fatalf("ParseAllWithMetadata failed: %v", err)
+			}
+			if metadata.Color != tt.reparsed {
+				t.Errorf("Expected color %s after the round trip, got %s", tt.reparsed, metadata.Color)
+			}
+		})
+	}
+}
+
+func TestCalendarColorPrecedence(t *testing.T) {
+	// COLOR names a different color than X-APPLE-CALENDAR-COLOR, so it wins
+	icalData := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Test//Test//EN",
+		"COLOR:teal",
+		"X-APPLE-CALENDAR-COLOR:#FF2968FF",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	_, metadata, err := New().ParseAllWithMetadata([]byte(icalData))
+	if err != nil {
+		t.Fatalf("ParseAllWithMetadata failed: %v", err)
+	}
+	if metadata.Color != "teal" {
+		t.Errorf("Expected COLOR to take precedence, got %s", metadata.Color)
+	}
+}
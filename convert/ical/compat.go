@@ -0,0 +1,172 @@
+package ical
+
+import (
+	"strings"
+	"time"
+
+	"github.com/airtrafik/jscal"
+	ics "github.com/arran4/golang-ical"
+)
+
+// CompatibilityMode adjusts the iCalendar written by the converter to the
+// quirks of a calendar client. Parsing is the same in every mode.
+type CompatibilityMode int
+
+const (
+	// CompatibilityStandard writes plain RFC 5545, the default. Event
+	// lengths are written as DURATION, which round-trips exactly.
+	CompatibilityStandard CompatibilityMode = iota
+
+	// CompatibilityOutlook writes DTEND instead of DURATION, the
+	// X-MICROSOFT-CDO-BUSYSTATUS and X-MICROSOFT-CDO-ALLDAYEVENT properties
+	// Outlook reads the free/busy state and all-day flag from, and METHOD
+	// right after PRODID and VERSION. Folding never splits a backslash escape.
+	CompatibilityOutlook
+
+	// CompatibilityApple writes DTEND instead of DURATION and locations as
+	// LocationsAppleStructured, whatever the FormatOptions say
+	CompatibilityApple
+
+	// CompatibilityGoogle writes DTEND instead of DURATION, the addresses of
+	// locations in LOCATION as Google Calendar has no other place for them
+	// and, unless the calendar metadata has one, an X-WR-TIMEZONE when all
+	// zoned events share a time zone, as Google Calendar uses it as the
+	// calendar's zone
+	CompatibilityGoogle
+)
+
+// Outlook-specific properties
+const (
+	propertyMSBusyStatus  = "X-MICROSOFT-CDO-BUSYSTATUS"
+	propertyMSAllDayEvent = "X-MICROSOFT-CDO-ALLDAYEVENT"
+)
+
+// formatOptions returns the options as adjusted for the mode
+func (m CompatibilityMode) formatOptions(opts FormatOptions) FormatOptions {
+	switch m {
+	case CompatibilityApple:
+		opts.Locations = LocationsAppleStructured
+	case CompatibilityGoogle:
+		opts.LocationAddresses = true
+	}
+	return opts
+}
+
+// adjustEvent adjusts a converted event for the mode. Journal entries are
+// left alone, as a VJOURNAL has no length or free/busy state.
+func (m CompatibilityMode) adjustEvent(event *jscal.Event, vevent *ics.VEvent) {
+	if m == CompatibilityStandard || isJournal(event) {
+		return
+	}
+	setDTEnd(event, vevent)
+
+	if m == CompatibilityOutlook {
+		vevent.SetProperty(ics.ComponentProperty(propertyMSBusyStatus), outlookBusyStatus(event))
+		allDay := "FALSE"
+		if event.IsAllDay() {
+			allDay = "TRUE"
+		}
+		vevent.SetProperty(ics.ComponentProperty(propertyMSAllDayEvent), allDay)
+	}
+}
+
+// adjustCalendar adjusts the calendar-level properties for the mode
+func (m CompatibilityMode) adjustCalendar(cal *ics.Calendar, events []*jscal.Event, opts FormatOptions) {
+	switch m {
+	case CompatibilityOutlook:
+		moveMethod(cal)
+	case CompatibilityGoogle:
+		if opts.Calendar != nil && opts.Calendar.TimeZone != "" {
+			return
+		}
+		if tz := sharedTimeZone(events); tz != "" {
+			cal.SetXWRTimezone(tz)
+		}
+	}
+}
+
+// setDTEnd replaces the DURATION of a converted event by the DTEND it
+// gives, in the form of DTSTART. All-day events end on the exclusive next
+// day, as RFC 5545 intends and clients showing the inclusive last day
+// expect; one without a duration lasts a day.
+func setDTEnd(event *jscal.Event, vevent *ics.VEvent) {
+	if event.Start == nil {
+		return
+	}
+	duration, err := event.GetDuration()
+	if err != nil {
+		duration = 0
+	}
+	start := event.Start.Time()
+
+	if event.IsAllDay() {
+		days := int(duration / (24 * time.Hour))
+		if days < 1 {
+			days = 1
+		}
+		vevent.RemoveProperty(ics.ComponentPropertyDuration)
+		vevent.SetProperty(ics.ComponentPropertyDtEnd, start.AddDate(0, 0, days).Format("20060102"), ics.WithValue(string(ics.ValueDataTypeDate)))
+		return
+	}
+	if event.Duration == nil || err != nil {
+		return
+	}
+
+	vevent.RemoveProperty(ics.ComponentPropertyDuration)
+	end := start.Add(duration)
+	if eventLocation(event) != nil {
+		vevent.SetProperty(ics.ComponentPropertyDtEnd, end.Format("20060102T150405"), ics.WithTZID(*event.TimeZone))
+		return
+	}
+	vevent.SetProperty(ics.ComponentPropertyDtEnd, end.UTC().Format("20060102T150405Z"))
+}
+
+// outlookBusyStatus returns the X-MICROSOFT-CDO-BUSYSTATUS for an event
+func outlookBusyStatus(event *jscal.Event) string {
+	switch event.GetFreeBusyStatus() {
+	case jscal.FreeBusyFree:
+		return "FREE"
+	case jscal.FreeBusyTentative:
+		return "TENTATIVE"
+	case jscal.FreeBusyUnavailable:
+		return "OOF"
+	}
+	if event.GetStatus() == jscal.StatusTentative {
+		return "TENTATIVE"
+	}
+	return "BUSY"
+}
+
+// moveMethod moves METHOD right after PRODID and VERSION, where Outlook
+// looks for it
+func moveMethod(cal *ics.Calendar) {
+	var method []ics.CalendarProperty
+	var head, rest []ics.CalendarProperty
+	for _, prop := range cal.CalendarProperties {
+		switch strings.ToUpper(prop.IANAToken) {
+		case string(ics.PropertyMethod):
+			method = append(method, prop)
+		case string(ics.PropertyProductId), string(ics.PropertyVersion):
+			head = append(head, prop)
+		default:
+			rest = append(rest, prop)
+		}
+	}
+	cal.CalendarProperties = append(append(head, method...), rest...)
+}
+
+// sharedTimeZone returns the time zone of the zoned events, or "" if they
+// have different ones or there are none
+func sharedTimeZone(events []*jscal.Event) string {
+	tz := ""
+	for _, event := range events {
+		if event.IsAllDay() || eventLocation(event) == nil {
+			continue
+		}
+		if tz != "" && tz != *event.TimeZone {
+			return ""
+		}
+		tz = *event.TimeZone
+	}
+	return tz
+}
@@ -0,0 +1,202 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/airtrafik/jscal"
+)
+
+func newCompatEvents() []*jscal.Event {
+	meeting := jscal.NewEvent("meeting@example.com", "Planning")
+	meeting.Start = jscal.NewLocalDateTime(time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC))
+	meeting.TimeZone = jscal.String("Europe/Berlin")
+	meeting.Duration = jscal.String("PT1H30M")
+	meeting.FreeBusyStatus = jscal.String(jscal.FreeBusyUnavailable)
+	hotel := jscal.NewLocation("Hotel")
+	hotel.Coordinates = jscal.String("geo:52.5200,13.4050")
+	meeting.AddLocation("a", jscal.NewLocation("Main office"))
+	meeting.AddLocation("b", hotel)
+
+	holiday := jscal.NewEvent("holiday@example.com", "Holiday")
+	holiday.Start = jscal.NewLocalDateTime(time.Date(2025, 3, 28, 0, 0, 0, 0, time.UTC))
+	holiday.ShowWithoutTime = jscal.Bool(true)
+	holiday.Duration = jscal.String("P3D")
+	holiday.FreeBusyStatus = jscal.String(jscal.FreeBusyFree)
+	return []*jscal.Event{meeting, holiday}
+}
+
+func TestCompatibilityModes(t *testing.T) {
+	if _, err := time.LoadLocation("Europe/Berlin"); err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		mode       CompatibilityMode
+		expected   []string
+		unexpected []string
+	}{
+		{
+			name: "standard",
+			mode: CompatibilityStandard,
+			expected: []string{
+				"DURATION:PT1H30M",
+				"DURATION:P3D",
+				"LOCATION:Hotel",
+			},
+			unexpected: []string{"DTEND", "X-MICROSOFT-CDO-", "X-WR-TIMEZONE"},
+		},
+		{
+			name: "outlook",
+			mode: CompatibilityOutlook,
+			expected: []string{
+				"DTEND;TZID=Europe/Berlin:20250310T103000",
+				"DTEND;VALUE=DATE:20250331",
+				"X-MICROSOFT-CDO-BUSYSTATUS:OOF",
+				"X-MICROSOFT-CDO-BUSYSTATUS:FREE",
+				"X-MICROSOFT-CDO-ALLDAYEVENT:FALSE",
+				"X-MICROSOFT-CDO-ALLDAYEVENT:TRUE",
+				"Library//EN\r\nMETHOD:PUBLISH\r\nCALSCALE:GREGORIAN",
+			},
+			unexpected: []string{"DURATION"},
+		},
+		{
+			name: "apple",
+			mode: CompatibilityApple,
+			expected: []string{
+				"DTEND;TZID=Europe/Berlin:20250310T103000",
+				"DTEND;VALUE=DATE:20250331",
+				"X-APPLE-STRUCTURED-LOCATION",
+			},
+			unexpected: []string{"DURATION", "X-MICROSOFT-CDO-", "LOCATION:Hotel"},
+		},
+		{
+			name: "google",
+			mode: CompatibilityGoogle,
+			expected: []string{
+				"DTEND;TZID=Europe/Berlin:20250310T103000",
+				"DTEND;VALUE=DATE:20250331",
+				"X-WR-TIMEZONE:Europe/Berlin",
+			},
+			unexpected: []string{"DURATION", "X-MICROSOFT-CDO-"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Converter{Compatibility: tt.mode}
+			data, err := c.FormatAllWithOptions(newCompatEvents(), FormatOptions{
+				Calendar: &CalendarMetadata{CalScale: CalScaleGregorian, Method: "PUBLISH"},
+			})
+			if err != nil {
+				t.Fatalf("FormatAllWithOptions failed: %v", err)
+			}
+			output := string(data)
+			for _, want := range tt.expected {
+				if !strings.Contains(output, want) {
+					t.Errorf("Expected output to contain %q:\n%s", want, output)
+				}
+			}
+			for _, unwanted := range tt.unexpected {
+				if strings.Contains(output, unwanted) {
+					t.Errorf("Expected output not to contain %q:\n%s", unwanted, output)
+				}
+			}
+
+			// Every mode parses back to the same start and duration
+			events, err := c.ParseAll(data)
+			if err != nil {
+				t.Fatalf("ParseAll failed: %v", err)
+			}
+			if len(events) != 2 {
+				t.Fatalf("Expected 2 events, got %d", len(events))
+			}
+			for i, want := range []string{"PT1H30M", "P3D"} {
+				if got := deref(events[i].Duration); got != want {
+					t.Errorf("Expected duration %s to round-trip, got %s", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestCompatibilityGoogleTimeZone(t *testing.T) {
+	events := newCompatEvents()
+	other := jscal.NewEvent("other@example.com", "Call")
+	other.Start = jscal.NewLocalDateTime(time.Date(2025, 3, 11, 9, 0, 0, 0, time.UTC))
+	other.TimeZone = jscal.String("America/New_York")
+
+	c := &Converter{Compatibility: CompatibilityGoogle}
+	data, err := c.FormatAll(append(events, other))
+	if err != nil {
+		t.Fatalf("FormatAll failed: %v", err)
+	}
+	if strings.Contains(string(data), "X-WR-TIMEZONE") {
+		t.Errorf("Expected no X-WR-TIMEZONE for events in several zones:\n%s", data)
+	}
+
+	data, err = c.FormatAllWithOptions(events, FormatOptions{Calendar: &CalendarMetadata{TimeZone: "Europe/Vienna"}})
+	if err != nil {
+		t.Fatalf("FormatAllWithOptions failed: %v", err)
+	}
+	if !strings.Contains(string(data), "X-WR-TIMEZONE:Europe/Vienna") || strings.Contains(string(data), "X-WR-TIMEZONE:Europe/Berlin") {
+		t.Errorf("Expected the metadata time zone to be kept:\n%s", data)
+	}
+}
+
+func TestOutlookBusyStatus(t *testing.T) {
+	tests := []struct {
+		freeBusy string
+		status   string
+		want     string
+	}{
+		{"", "", "BUSY"},
+		{jscal.FreeBusyBusy, "", "BUSY"},
+		{jscal.FreeBusyFree, "", "FREE"},
+		{jscal.FreeBusyTentative, "", "TENTATIVE"},
+		{jscal.FreeBusyUnavailable, "", "OOF"},
+		{"", jscal.StatusTentative, "TENTATIVE"},
+		{jscal.FreeBusyFree, jscal.StatusTentative, "FREE"},
+	}
+
+	for _, tt := range tests {
+		event := jscal.NewEvent("busy@example.com", "Busy")
+		if tt.freeBusy != "" {
+			event.FreeBusyStatus = jscal.String(tt.freeBusy)
+		}
+		if tt.status != "" {
+			event.Status = jscal.String(tt.status)
+		}
+		if got := outlookBusyStatus(event); got != tt.want {
+			t.Errorf("freeBusyStatus %q, status %q: expected %s, got %s", tt.freeBusy, tt.status, tt.want, got)
+		}
+	}
+}
+
+func TestAllDayDTEndWithoutDuration(t *testing.T) {
+	event := jscal.NewEvent("day@example.com", "Day off")
+	event.Start = jscal.NewLocalDateTime(time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC))
+	event.ShowWithoutTime = jscal.Bool(true)
+
+	data, err := (&Converter{Compatibility: CompatibilityOutlook}).Format(event)
+	if err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	if !strings.Contains(string(data), "DTEND;VALUE=DATE:20260101") {
+		t.Errorf("Expected an exclusive DTEND on the next day:\n%s", data)
+	}
+}
+
+func TestFoldLinesKeepingEscapes(t *testing.T) {
+	// The escape's backslash would be the 75th octet
+	line := "DESCRIPTION:" + strings.Repeat("a", 62) + `\nnext`
+	output := string(foldLinesKeepingEscapes([]byte(line + "\r\n")))
+	if !strings.Contains(output, "a\r\n \\nnext") {
+		t.Errorf("Expected the fold before the escape, got %q", output)
+	}
+	if output := string(foldLines([]byte(line + "\r\n"))); !strings.Contains(output, "\\\r\n n") {
+		t.Errorf("Expected foldLines to fold at 75 octets, got %q", output)
+	}
+}
@@ -2,27 +2,86 @@
 package ical
 
 import (
+	"context"
+	"encoding/base64"
 	"fmt"
+	"log/slog"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/airtrafik/jscal"
 	"github.com/airtrafik/jscal/convert"
+	"github.com/airtrafik/jscal/observe"
 	ics "github.com/arran4/golang-ical"
 )
 
 // Converter handles iCalendar <-> JSCalendar conversions using golang-ical library
-type Converter struct{}
+type Converter struct {
+	// Journals decides what happens to VJOURNAL components, skipped by default
+	Journals JournalPolicy
+
+	// Compatibility adjusts the written iCalendar to a calendar client,
+	// CompatibilityStandard by default
+	Compatibility CompatibilityMode
+
+	// MaxAttachmentSize drops inline ATTACH values decoding to more than
+	// this many bytes while parsing; 0 means no limit
+	MaxAttachmentSize int
+
+	// Charset is the character set of parsed data, e.g. from a
+	// Content-Type header. When empty it is detected: byte order marks
+	// are dropped, UTF-16 is recognized and data that isn't valid UTF-8
+	// is read as Windows-1252. See convert.DecodeCharset for the names.
+	Charset string
+
+	// Lenient repairs legacy input before parsing: bare LF or CR line
+	// endings, QUOTED-PRINTABLE values of vCalendar 1.0 exporters, and
+	// lines continuing a value without the leading space of a fold
+	Lenient bool
+
+	// Strict rejects input the parser would lose data of, with errors
+	// wrapping ErrUnmapped: events and journal entries with properties or
+	// parameters that have no JSCalendar mapping fail to convert, and so
+	// do calendars with components that are skipped, including VJOURNALs
+	// under JournalSkip. ParseAllWithReport lists such data instead.
+	Strict bool
+
+	// Hooks receives a span for each batch conversion, and the counts of
+	// unsupported properties and dropped components; nil reports nothing
+	Hooks *observe.Hooks
+}
+
+// Ensure Converter implements the convert.Converter, convert.LenientParser,
+// convert.GroupConverter, convert.Splitter and context interfaces
+var (
+	_ convert.Converter      = (*Converter)(nil)
+	_ convert.LenientParser  = (*Converter)(nil)
+	_ convert.GroupConverter = (*Converter)(nil)
+	_ convert.Splitter       = (*Converter)(nil)
 
-// Ensure Converter implements the convert.Converter interface
-var _ convert.Converter = (*Converter)(nil)
+	_ convert.ContextConverter     = (*Converter)(nil)
+	_ convert.ContextLenientParser = (*Converter)(nil)
+)
 
 // New creates a new iCalendar converter
 func New() *Converter {
 	return &Converter{}
 }
 
+// Register the converter, so importing this package enables convert.Lookup("ical")
+func init() {
+	convert.Register(convert.Registration{
+		Name:       "ical",
+		Aliases:    []string{"icalendar", "ics"},
+		Extensions: []string{".ics", ".ical"},
+		New:        func() convert.Converter { return New() },
+
+		Description: "iCalendar (RFC 5545)",
+	})
+}
+
 // Parse converts iCalendar data to a single JSCalendar event
 func (c *Converter) Parse(data []byte) (*jscal.Event, error) {
 	events, err := c.ParseAll(data)
@@ -48,72 +107,246 @@ func (c *Converter) Format(event *jscal.Event) ([]byte, error) {
 
 // ParseAll converts iCalendar data to JSCalendar events
 func (c *Converter) ParseAll(data []byte) ([]*jscal.Event, error) {
+	return c.ParseAllContext(context.Background(), data)
+}
+
+// ParseAllContext is ParseAll returning the context's error once ctx is done
+func (c *Converter) ParseAllContext(ctx context.Context, data []byte) ([]*jscal.Event, error) {
+	events, _, _, err := c.parseAllTraced(ctx, data)
+	return events, err
+}
+
+// ParseAllLenient converts iCalendar data to JSCalendar events, skipping
+// VEVENTs that fail to convert instead of aborting. Each skipped VEVENT is
+// reported with its index and the line its BEGIN:VEVENT is on; VJOURNALs
+// that fail, e.g. under JournalReject, with their index among the VJOURNALs.
+// The error is only set if the calendar as a whole can't be parsed.
+func (c *Converter) ParseAllLenient(data []byte) ([]*jscal.Event, []*convert.ItemError, error) {
+	return c.ParseAllLenientContext(context.Background(), data)
+}
+
+// ParseAllLenientContext is ParseAllLenient returning the context's error
+// once ctx is done
+func (c *Converter) ParseAllLenientContext(ctx context.Context, data []byte) ([]*jscal.Event, []*convert.ItemError, error) {
+	ctx, span := c.Hooks.Start(ctx, "jscal.ical.parse", slog.Int("bytes", len(data)), slog.Bool("lenient", true))
+	events, errs, err := c.parseAllLenient(ctx, data, &ConversionReport{})
+	for _, itemErr := range errs {
+		c.Hooks.Add(ctx, observe.DroppedItems, 1, slog.String("item", "invalid"))
+		c.Hooks.Warn(ctx, "skipped iCalendar component", slog.String("error", itemErr.Error()))
+	}
+	span.SetAttributes(slog.Int("events", len(events)), slog.Int("skipped", len(errs)))
+	span.End(err)
+	return events, errs, err
+}
+
+func (c *Converter) parseAllLenient(ctx context.Context, data []byte, report *ConversionReport) ([]*jscal.Event, []*convert.ItemError, error) {
+	var errs []*convert.ItemError
+	events, _, err := c.parseAll(ctx, data, report, func(_ string, index, line int, uid string, err error) error {
+		errs = append(errs, &convert.ItemError{Index: index, Line: line, UID: uid, Err: err})
+		return nil
+	})
+	return events, errs, err
+}
+
+// itemErrorFunc handles a component that failed to convert, the item of
+// the given kind at index among its kind, starting on the given line of
+// the input. Returning an error aborts the parse; nil skips the component.
+type itemErrorFunc func(item string, index, line int, uid string, err error) error
+
+// failItem is the itemErrorFunc of a strict parse, failing with a
+// *convert.ConversionError
+func failItem(item string, _, line int, uid string, err error) error {
+	return &convert.ConversionError{Item: item, UID: uid, Line: line, Err: err}
+}
+
+// lineAt returns the i-th line of componentLines, or 0 if there is none
+func lineAt(lines []int, i int) int {
+	if i < len(lines) {
+		return lines[i]
+	}
+	return 0
+}
+
+// componentLines returns the line numbers of the BEGIN lines of a
+// component, such as VEVENT, in data
+func componentLines(data []byte, component string) []int {
+	var lines []int
+	for i, line := range strings.Split(string(data), "\n") {
+		if strings.EqualFold(strings.TrimSpace(line), "BEGIN:"+component) {
+			lines = append(lines, i+1)
+		}
+	}
+	return lines
+}
+
+// ParseAllWithMetadata converts iCalendar data to JSCalendar events and also
+// returns the calendar-level metadata of the source. Calendars using a
+// CALSCALE other than GREGORIAN are rejected with an *UnsupportedCalScaleError;
+// the metadata is still returned in that case.
+func (c *Converter) ParseAllWithMetadata(data []byte) ([]*jscal.Event, *CalendarMetadata, error) {
+	events, metadata, _, err := c.parseAllTraced(context.Background(), data)
+	return events, metadata, err
+}
+
+// ParseAllWithReport converts iCalendar data to JSCalendar events and also
+// returns a report of the data that couldn't be mapped and was dropped,
+// so it can be reviewed before a migration relies on the events
+func (c *Converter) ParseAllWithReport(data []byte) ([]*jscal.Event, *ConversionReport, error) {
+	events, _, report, err := c.parseAllTraced(context.Background(), data)
+	return events, report, err
+}
+
+func (c *Converter) parseAllTraced(ctx context.Context, data []byte) ([]*jscal.Event, *CalendarMetadata, *ConversionReport, error) {
+	ctx, span := c.Hooks.Start(ctx, "jscal.ical.parse", slog.Int("bytes", len(data)))
+	report := &ConversionReport{}
+	events, metadata, err := c.parseAll(ctx, data, report, failItem)
+	span.SetAttributes(slog.Int("events", len(events)))
+	span.End(err)
+	return events, metadata, report, err
+}
+
+// parseAll converts the events and journal entries of iCalendar data,
+// passing those that fail to convert to itemFailed
+func (c *Converter) parseAll(ctx context.Context, data []byte, report *ConversionReport, itemFailed itemErrorFunc) ([]*jscal.Event, *CalendarMetadata, error) {
+	data, err := c.prepare(data)
+	if err != nil {
+		return nil, nil, err
+	}
 	cal, err := ics.ParseCalendar(strings.NewReader(string(data)))
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse iCalendar: %w", err)
+		return nil, nil, fmt.Errorf("failed to parse iCalendar: %w", err)
+	}
+
+	metadata := parseCalendarMetadata(cal)
+	if err := checkCalScale(metadata); err != nil {
+		return nil, metadata, err
+	}
+	if err := c.accountComponents(ctx, report, cal); err != nil {
+		return nil, metadata, err
 	}
 
 	var events []*jscal.Event
 
-	for _, vevent := range cal.Events() {
+	lines := componentLines(data, "VEVENT")
+	for i, vevent := range cal.Events() {
+		if err := ctx.Err(); err != nil {
+			return nil, metadata, err
+		}
 		event, err := convertICalEventToJSCal(vevent)
+		if err == nil {
+			err = c.account(ctx, report, event.UID, vevent.Properties)
+		}
 		if err != nil {
-			return nil, fmt.Errorf("failed to convert event: %w", err)
+			if err := itemFailed("event", i, lineAt(lines, i), vevent.Id(), err); err != nil {
+				return nil, metadata, err
+			}
+			continue
 		}
+		c.dropLargeAttachments(ctx, event)
 		events = append(events, event)
 	}
 
-	return events, nil
+	journals, err := c.parseJournals(ctx, cal, data, report, itemFailed)
+	if err != nil {
+		return nil, metadata, err
+	}
+	events = append(events, journals...)
+
+	events, err = mergeDetachedInstances(events)
+	if err != nil {
+		return nil, metadata, err
+	}
+	return events, metadata, nil
 }
 
 // FormatAll converts JSCalendar events to iCalendar format
 func (c *Converter) FormatAll(events []*jscal.Event) ([]byte, error) {
+	return c.FormatAllWithOptions(events, FormatOptions{})
+}
+
+// FormatAllContext is FormatAll returning the context's error once ctx is done
+func (c *Converter) FormatAllContext(ctx context.Context, events []*jscal.Event) ([]byte, error) {
+	return c.formatAll(ctx, events, FormatOptions{})
+}
+
+// FormatAllWithOptions converts JSCalendar events to iCalendar format with
+// the calendar-level properties set in opts. Pass the metadata returned by
+// ParseAllWithMetadata to keep a feed's name, method and refresh interval
+// across a round trip.
+func (c *Converter) FormatAllWithOptions(events []*jscal.Event, opts FormatOptions) ([]byte, error) {
+	return c.formatAll(context.Background(), events, opts)
+}
+
+func (c *Converter) formatAll(ctx context.Context, events []*jscal.Event, opts FormatOptions) ([]byte, error) {
 	if len(events) == 0 {
 		return nil, fmt.Errorf("no events to convert")
 	}
+	return c.formatCalendar(ctx, events, opts)
+}
 
+// formatCalendar writes the events as a VCALENDAR, which may be empty
+func (c *Converter) formatCalendar(ctx context.Context, events []*jscal.Event, opts FormatOptions) ([]byte, error) {
+	ctx, span := c.Hooks.Start(ctx, "jscal.ical.format", slog.Int("events", len(events)))
+	data, err := c.writeCalendar(ctx, events, opts)
+	span.SetAttributes(slog.Int("bytes", len(data)))
+	span.End(err)
+	return data, err
+}
+
+func (c *Converter) writeCalendar(ctx context.Context, events []*jscal.Event, opts FormatOptions) ([]byte, error) {
+	opts = c.Compatibility.formatOptions(opts)
 	cal := ics.NewCalendar()
-	cal.SetProductId("-//AirTrafik//JSCal Go Library//EN")
-	cal.SetVersion("2.0")
+	setCalendarProperties(cal, opts)
+	c.Compatibility.adjustCalendar(cal, events, opts)
+	addVTimezones(cal, events)
 
 	for _, event := range events {
-		vevent, err := convertJSCalEventToICal(event)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		vevent, err := convertJSCalEventToICal(event, opts)
 		if err != nil {
-			return nil, fmt.Errorf("failed to convert event %s: %w", event.UID, err)
+			return nil, &convert.ConversionError{Item: "event", UID: event.UID, Err: err}
 		}
-		cal.AddVEvent(vevent)
-	}
-
-	return []byte(cal.Serialize()), nil
-}
-
-// Detect returns true if the data appears to be iCalendar format
-func (c *Converter) Detect(data []byte) bool {
-	dataStr := strings.TrimSpace(string(data))
+		c.Compatibility.adjustEvent(event, vevent)
+		addEvent(cal, event, vevent)
 
-	// Check for iCalendar header
-	if strings.HasPrefix(dataStr, "BEGIN:VCALENDAR") {
-		return true
+		// Overrides patching properties follow as detached instances
+		instances, err := detachedInstances(event)
+		if err != nil {
+			return nil, &convert.ConversionError{Item: "event", UID: event.UID, Err: err}
+		}
+		for _, instance := range instances {
+			vevent, err := convertJSCalEventToICal(instance, opts)
+			if err != nil {
+				return nil, &convert.ConversionError{Item: "event", UID: event.UID, Err: err}
+			}
+			c.Compatibility.adjustEvent(instance, vevent)
+			addEvent(cal, instance, vevent)
+		}
 	}
 
-	// Check for common iCalendar patterns
-	patterns := []string{
-		"BEGIN:VEVENT",
-		"DTSTART:",
-		"DTEND:",
-		"SUMMARY:",
-		"UID:",
+	data := serialize(cal)
+	if c.Compatibility == CompatibilityOutlook {
+		return foldLinesKeepingEscapes(data), nil
 	}
+	return foldLines(data), nil
+}
 
-	found := 0
-	for _, pattern := range patterns {
-		if strings.Contains(dataStr, pattern) {
-			found++
-		}
+// addEvent adds a converted event to the calendar, as a VJOURNAL if it is
+// a journal entry
+func addEvent(cal *ics.Calendar, event *jscal.Event, vevent *ics.VEvent) {
+	if isJournal(event) {
+		cal.Components = append(cal.Components, formatJournal(vevent))
+		return
 	}
+	cal.AddVEvent(vevent)
+}
 
-	// If we found at least 3 patterns, it's likely iCalendar
-	return found >= 3
+// Detect returns true if the data appears to be iCalendar format
+func (c *Converter) Detect(data []byte) bool {
+	format, _ := convert.SniffContent(data)
+	return format == "ical"
 }
 
 // convertICalEventToJSCal converts an iCalendar event to JSCalendar
@@ -138,11 +371,6 @@ func convertICalEventToJSCal(vevent *ics.VEvent) (*jscal.Event, error) {
 	// Description
 	if prop := vevent.GetProperty(ics.ComponentPropertyDescription); prop != nil {
 		desc := prop.Value
-		// Handle escaped characters
-		desc = strings.ReplaceAll(desc, "\\n", "\n")
-		desc = strings.ReplaceAll(desc, "\\,", ",")
-		desc = strings.ReplaceAll(desc, "\\;", ";")
-		desc = strings.ReplaceAll(desc, "\\\\", "\\")
 		event.Description = &desc
 	}
 
@@ -167,13 +395,16 @@ func convertICalEventToJSCal(vevent *ics.VEvent) (*jscal.Event, error) {
 			// Convert LocalDateTime to time.Time for duration calculation
 			startTime := event.Start.Time()
 			duration := endTime.Sub(startTime)
-			durationStr := formatISO8601Duration(duration)
+			durationStr := jscal.FormatDuration(duration)
 			event.Duration = &durationStr
 		}
 	} else if dur := vevent.GetProperty(ics.ComponentPropertyDuration); dur != nil {
 		// Parse and convert duration
-		duration := parseICalDuration(dur.Value)
-		durationStr := formatISO8601Duration(duration)
+		duration, err := jscal.ParseDuration(dur.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DURATION: %w", err)
+		}
+		durationStr := jscal.FormatDuration(duration)
 		event.Duration = &durationStr
 	}
 
@@ -181,7 +412,7 @@ func convertICalEventToJSCal(vevent *ics.VEvent) (*jscal.Event, error) {
 	if created := vevent.GetProperty(ics.ComponentPropertyCreated); created != nil {
 		createdTime, _, _ := parseICalDateTime(created)
 		if !createdTime.IsZero() {
-			event.Created = &createdTime
+			event.Created = jscal.NewUTCDateTime(createdTime)
 		}
 	}
 
@@ -189,7 +420,7 @@ func convertICalEventToJSCal(vevent *ics.VEvent) (*jscal.Event, error) {
 	if modified := vevent.GetProperty(ics.ComponentPropertyLastModified); modified != nil {
 		modifiedTime, _, _ := parseICalDateTime(modified)
 		if !modifiedTime.IsZero() {
-			event.Updated = &modifiedTime
+			event.Updated = jscal.NewUTCDateTime(modifiedTime)
 		}
 	}
 
@@ -200,30 +431,55 @@ func convertICalEventToJSCal(vevent *ics.VEvent) (*jscal.Event, error) {
 		}
 	}
 
+	// Priority, 0 (undefined) to 9 in both formats
+	if priority := vevent.GetProperty(ics.ComponentPropertyPriority); priority != nil {
+		if n, err := strconv.Atoi(strings.TrimSpace(priority.Value)); err == nil && n > jscal.PriorityMin && n <= jscal.PriorityMax {
+			event.Priority = &n
+		}
+	}
+
+	// Recurrence id of a detached instance, see mergeDetachedInstances
+	if rid := vevent.GetProperty(ics.ComponentPropertyRecurrenceId); rid != nil {
+		ridTime, _, timezone := parseICalDateTime(rid)
+		if !ridTime.IsZero() {
+			event.RecurrenceId = jscal.NewLocalDateTime(ridTime)
+			if timezone != "" && timezone != "UTC" {
+				event.RecurrenceIdTimeZone = &timezone
+			}
+		}
+	}
+
 	// Status
 	if status := vevent.GetProperty(ics.ComponentPropertyStatus); status != nil {
 		statusLower := strings.ToLower(status.Value)
 		event.Status = &statusLower
 	}
 
-	// Categories
-	if categories := vevent.GetProperty(ics.ComponentPropertyCategories); categories != nil {
-		cats := strings.Split(categories.Value, ",")
-		event.Categories = make(map[string]bool)
-		for _, cat := range cats {
-			event.Categories[strings.TrimSpace(cat)] = true
+	// REQUEST-STATUS -> RequestStatus
+	processRequestStatus(vevent, event)
+
+	// X-JMAP-MAY-INVITE-SELF, X-JMAP-MAY-INVITE-OTHERS, X-JMAP-HIDE-ATTENDEES
+	processInvitePermissions(vevent, event)
+
+	// Categories, from every CATEGORIES property
+	for i := range vevent.Properties {
+		if prop := &vevent.Properties[i]; prop.IANAToken == string(ics.ComponentPropertyCategories) {
+			for _, cat := range splitTextList(prop.Value) {
+				event.AddCategory(cat)
+			}
 		}
 	}
 
-	// Location
-	if location := vevent.GetProperty(ics.ComponentPropertyLocation); location != nil {
-		if event.Locations == nil {
-			event.Locations = make(map[string]*jscal.Location)
+	// COLOR (RFC 7986), a CSS color name
+	if color := vevent.GetProperty(ics.ComponentPropertyColor); color != nil {
+		if _, err := jscal.ParseColor(color.Value); err == nil {
+			event.Color = jscal.String(strings.ToLower(strings.TrimSpace(color.Value)))
 		}
-		loc := jscal.NewLocation(location.Value)
-		event.Locations["1"] = loc
 	}
 
+	// LOCATION, GEO and X-APPLE-STRUCTURED-LOCATION -> Locations
+	processLocations(vevent, event)
+
 	// Transparency -> FreeBusyStatus
 	if transp := vevent.GetProperty(ics.ComponentPropertyTransp); transp != nil {
 		var freeBusy string
@@ -245,29 +501,38 @@ func convertICalEventToJSCal(vevent *ics.VEvent) (*jscal.Event, error) {
 	}
 
 	// URL -> Links
-	if url := vevent.GetProperty(ics.ComponentPropertyUrl); url != nil {
-		if event.Links == nil {
-			event.Links = make(map[string]*jscal.Link)
+	for i := range vevent.Properties {
+		if prop := &vevent.Properties[i]; prop.IANAToken == string(ics.ComponentPropertyUrl) && prop.Value != "" {
+			event.AddLink(strconv.Itoa(len(event.Links)+1), jscal.NewLink(prop.Value))
 		}
-		link := jscal.NewLink(url.Value)
-		event.Links["1"] = link
 	}
 
+	// Process attachments and conferences
+	processAttachments(vevent, event)
+	processConferences(vevent, event)
+
 	// Process Attendees and Organizer
 	processParticipants(vevent, event)
 
 	// Process Recurrence Rules
 	processRecurrenceRules(vevent, event)
+	processRecurrenceDates(vevent, event)
+
+	// Process Alarms
+	processAlarms(vevent, event)
+
+	// Process related objects
+	processRelations(vevent, event)
 
 	return event, nil
 }
 
 // convertJSCalEventToICal converts a JSCalendar event to iCalendar
-func convertJSCalEventToICal(event *jscal.Event) (*ics.VEvent, error) {
+func convertJSCalEventToICal(event *jscal.Event, opts FormatOptions) (*ics.VEvent, error) {
 	vevent := ics.NewEvent(event.UID)
 
 	// Set timestamp
-	vevent.SetProperty(ics.ComponentPropertyDtstamp, time.Now().Format("20060102T150405Z"))
+	vevent.SetProperty(ics.ComponentPropertyDtstamp, jscal.Now().UTC().Format(utcLayout))
 
 	// Title/Summary
 	if event.Title != nil {
@@ -276,13 +541,7 @@ func convertJSCalEventToICal(event *jscal.Event) (*ics.VEvent, error) {
 
 	// Description
 	if event.Description != nil {
-		desc := *event.Description
-		// Escape special characters for iCalendar
-		desc = strings.ReplaceAll(desc, "\\", "\\\\")
-		desc = strings.ReplaceAll(desc, ";", "\\;")
-		desc = strings.ReplaceAll(desc, ",", "\\,")
-		desc = strings.ReplaceAll(desc, "\n", "\\n")
-		vevent.SetDescription(desc)
+		vevent.SetDescription(*event.Description)
 	}
 
 	// Start time
@@ -293,6 +552,9 @@ func convertJSCalEventToICal(event *jscal.Event) (*ics.VEvent, error) {
 		if event.IsAllDay() {
 			// All-day event - use date format
 			vevent.SetAllDayStartAt(startTime)
+		} else if loc := eventLocation(event); loc != nil {
+			// Wall clock time in the event's time zone, see addVTimezones
+			vevent.SetProperty(ics.ComponentPropertyDtStart, startTime.Format("20060102T150405"), ics.WithTZID(*event.TimeZone))
 		} else {
 			// Timed event
 			vevent.SetStartAt(startTime)
@@ -319,35 +581,40 @@ func convertJSCalEventToICal(event *jscal.Event) (*ics.VEvent, error) {
 		vevent.SetSequence(*event.Sequence)
 	}
 
+	// Priority, omitted when undefined
+	if event.Priority != nil && *event.Priority > jscal.PriorityMin && *event.Priority <= jscal.PriorityMax {
+		vevent.SetProperty(ics.ComponentPropertyPriority, strconv.Itoa(*event.Priority))
+	}
+
+	// Recurrence id
+	if event.RecurrenceId != nil {
+		value, params := formatRecurrenceTime(event, *event.RecurrenceId)
+		vevent.SetProperty(ics.ComponentPropertyRecurrenceId, value, params...)
+	}
+
 	// Status
 	if event.Status != nil {
 		vevent.SetStatus(ics.ObjectStatus(strings.ToUpper(*event.Status)))
 	}
 
-	// Categories (sorted for consistency)
-	if len(event.Categories) > 0 {
-		var cats []string
-		for cat := range event.Categories {
-			cats = append(cats, cat)
-		}
-		sort.Strings(cats)
-		prop := ics.IANAProperty{
-			BaseProperty: ics.BaseProperty{
-				IANAToken: string(ics.ComponentPropertyCategories),
-				Value:     strings.Join(cats, ","),
-			},
+	// Categories (sorted for consistency), one CATEGORIES property each, as
+	// the comma separating list items would be escaped with the text
+	for _, cat := range sortedKeys(event.Categories) {
+		if event.Categories[cat] {
+			vevent.AddProperty(ics.ComponentPropertyCategories, cat)
 		}
-		vevent.Properties = append(vevent.Properties, prop)
 	}
 
-	// Location
-	for _, location := range event.Locations {
-		if location.Name != nil {
-			vevent.SetLocation(*location.Name)
-			break // Only use first location
+	// Color -> COLOR, which only takes CSS color names
+	if event.Color != nil {
+		if name, ok := colorName(*event.Color); ok {
+			vevent.SetProperty(ics.ComponentPropertyColor, name)
 		}
 	}
 
+	// Locations
+	convertLocations(event, vevent, opts)
+
 	// FreeBusyStatus -> Transparency
 	if event.FreeBusyStatus != nil {
 		if *event.FreeBusyStatus == "free" {
@@ -366,17 +633,41 @@ func convertJSCalEventToICal(event *jscal.Event) (*ics.VEvent, error) {
 		vevent.AddProperty(ics.ComponentPropertyClass, class)
 	}
 
-	// URL
-	for _, link := range event.Links {
-		vevent.SetURL(link.Href)
-		break // Only use first link
+	// RequestStatus -> REQUEST-STATUS, if it's valid
+	if event.RequestStatus != nil {
+		if rs, err := jscal.ParseRequestStatus(*event.RequestStatus); err == nil {
+			vevent.AddProperty(ics.ComponentPropertyRequestStatus, requestStatusValue(rs))
+		}
 	}
 
+	// Invitation permissions -> X-JMAP-* properties
+	convertInvitePermissions(event, vevent)
+
+	// URL, one per link that isn't an attachment
+	for _, id := range sortedKeys(event.Links) {
+		if link := event.Links[id]; link != nil && !isAttachment(link) {
+			vevent.AddProperty(ics.ComponentPropertyUrl, link.Href)
+		}
+	}
+
+	// Convert attachments and conferences
+	convertAttachments(event, vevent)
+	convertConferences(event, vevent)
+
 	// Convert participants
 	convertParticipants(event, vevent)
 
 	// Convert recurrence rules
 	convertRecurrenceRules(event, vevent)
+	if err := convertRecurrenceDates(event, vevent); err != nil {
+		return nil, err
+	}
+
+	// Convert alerts
+	convertAlerts(event, vevent)
+
+	// Convert related objects
+	convertRelations(event, vevent)
 
 	return vevent, nil
 }
@@ -417,10 +708,11 @@ func parseICalDateTime(prop *ics.IANAProperty) (time.Time, bool, string) {
 
 	for _, format := range formats {
 		if t, err := time.Parse(format, value); err == nil {
-			// Apply timezone if specified
+			// A TZID value is a wall clock time in that zone
 			if timezone != "" && timezone != "UTC" {
-				if loc, err := time.LoadLocation(timezone); err == nil {
-					t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+				if loc := loadTimeZone(timezone); loc != nil {
+					t = wallTime(t, loc)
+					timezone = loc.String()
 				}
 			}
 			return t, isAllDay, timezone
@@ -430,99 +722,6 @@ func parseICalDateTime(prop *ics.IANAProperty) (time.Time, bool, string) {
 	return time.Time{}, isAllDay, timezone
 }
 
-func formatISO8601Duration(d time.Duration) string {
-	if d == 0 {
-		return "PT0S"
-	}
-
-	result := "P"
-
-	days := int(d.Hours() / 24)
-	if days > 0 {
-		result += fmt.Sprintf("%dD", days)
-		d -= time.Duration(days) * 24 * time.Hour
-	}
-
-	if d > 0 {
-		result += "T"
-
-		hours := int(d.Hours())
-		if hours > 0 {
-			result += fmt.Sprintf("%dH", hours)
-			d -= time.Duration(hours) * time.Hour
-		}
-
-		minutes := int(d.Minutes())
-		if minutes > 0 {
-			result += fmt.Sprintf("%dM", minutes)
-			d -= time.Duration(minutes) * time.Minute
-		}
-
-		seconds := d.Seconds()
-		if seconds > 0 {
-			result += fmt.Sprintf("%.0fS", seconds)
-		}
-	}
-
-	return result
-}
-
-func parseISO8601Duration(duration string) time.Duration {
-	// Simple parser for ISO 8601 durations
-	var result time.Duration
-
-	if !strings.HasPrefix(duration, "P") {
-		return 0
-	}
-
-	duration = duration[1:] // Remove P
-
-	// Split on T
-	parts := strings.Split(duration, "T")
-
-	// Parse date part if present
-	if len(parts) > 0 && parts[0] != "" {
-		datePart := parts[0]
-		// Parse days
-		if idx := strings.Index(datePart, "D"); idx > 0 {
-			days := parseInt(datePart[:idx])
-			result += time.Duration(days) * 24 * time.Hour
-		}
-	}
-
-	// Parse time part if present
-	if len(parts) > 1 && parts[1] != "" {
-		timePart := parts[1]
-
-		// Parse hours
-		if idx := strings.Index(timePart, "H"); idx > 0 {
-			hours := parseInt(timePart[:idx])
-			result += time.Duration(hours) * time.Hour
-			timePart = timePart[idx+1:]
-		}
-
-		// Parse minutes
-		if idx := strings.Index(timePart, "M"); idx > 0 {
-			minutes := parseInt(timePart[:idx])
-			result += time.Duration(minutes) * time.Minute
-			timePart = timePart[idx+1:]
-		}
-
-		// Parse seconds
-		if idx := strings.Index(timePart, "S"); idx > 0 {
-			seconds := parseInt(timePart[:idx])
-			result += time.Duration(seconds) * time.Second
-		}
-	}
-
-	return result
-}
-
-func parseICalDuration(value string) time.Duration {
-	// Handle iCalendar duration format (e.g., "P1DT2H30M" or "PT1H30M")
-	return parseISO8601Duration(value)
-}
-
 func parseInt(s string) int {
 	var i int
 	fmt.Sscanf(s, "%d", &i)
@@ -539,9 +738,10 @@ func processParticipants(vevent *ics.VEvent, event *jscal.Event) {
 		email := strings.TrimPrefix(organizer.Value, "mailto:")
 		participant := jscal.NewParticipant("", email)
 		participant.Roles = map[string]bool{"owner": true, "attendee": true}
+		addIMIP(participant, organizer.Value)
 
-		if cn := organizer.ICalParameters["CN"]; len(cn) > 0 {
-			participant.Name = &cn[0]
+		if cn, ok := textParam(organizer, "CN"); ok {
+			participant.Name = &cn
 		}
 
 		event.Participants[email] = participant
@@ -565,10 +765,11 @@ func processParticipants(vevent *ics.VEvent, event *jscal.Event) {
 		if !exists {
 			participant = jscal.NewParticipant("", email)
 		}
+		addIMIP(participant, attendee.Value)
 
 		// Common Name
-		if cn := attendee.ICalParameters["CN"]; len(cn) > 0 {
-			participant.Name = &cn[0]
+		if cn, ok := textParam(attendee, "CN"); ok {
+			participant.Name = &cn
 		}
 
 		// Participation Status
@@ -602,9 +803,21 @@ func processParticipants(vevent *ics.VEvent, event *jscal.Event) {
 	}
 }
 
+// addIMIP adds the mailto: calendar address of an ORGANIZER or ATTENDEE as
+// the participant's imip sendTo method
+func addIMIP(participant *jscal.Participant, address string) {
+	if strings.HasPrefix(strings.ToLower(address), "mailto:") {
+		_ = participant.AddSendTo(jscal.ReplyMethodImip, address)
+	}
+}
+
 func convertParticipants(event *jscal.Event, vevent *ics.VEvent) {
 	for email, participant := range event.Participants {
-		mailto := email
+		// The imip sendTo method is the calendar address, the id otherwise
+		mailto := participant.SendTo[jscal.ReplyMethodImip]
+		if mailto == "" {
+			mailto = email
+		}
 		if !strings.HasPrefix(mailto, "mailto:") {
 			mailto = "mailto:" + mailto
 		}
@@ -613,7 +826,7 @@ func convertParticipants(event *jscal.Event, vevent *ics.VEvent) {
 		if participant.Roles != nil && participant.Roles["owner"] {
 			organizerParams := make(map[string][]string)
 			if participant.Name != nil {
-				organizerParams["CN"] = []string{*participant.Name}
+				organizerParams["CN"] = []string{encodeParamValue(*participant.Name)}
 			}
 
 			prop := ics.IANAProperty{
@@ -630,7 +843,7 @@ func convertParticipants(event *jscal.Event, vevent *ics.VEvent) {
 		params := make(map[string][]string)
 
 		if participant.Name != nil {
-			params["CN"] = []string{*participant.Name}
+			params["CN"] = []string{encodeParamValue(*participant.Name)}
 		}
 
 		if participant.ParticipationStatus != nil {
@@ -662,24 +875,576 @@ func convertParticipants(event *jscal.Event, vevent *ics.VEvent) {
 }
 
 func processRecurrenceRules(vevent *ics.VEvent, event *jscal.Event) {
-	// Process RRULE
-	if rrule := vevent.GetProperty(ics.ComponentPropertyRrule); rrule != nil {
-		rule := parseRRule(rrule.Value)
-		if rule != nil {
-			event.RecurrenceRules = append(event.RecurrenceRules, *rule)
+	// An event may have several RRULEs, and EXRULEs (deprecated by RFC 5545
+	// but still produced) map to excludedRecurrenceRules. UNTIL is UTC in
+	// iCalendar but in the event's time zone in JSCalendar.
+	loc := eventLocation(event)
+	for _, prop := range vevent.Properties {
+		switch strings.ToUpper(prop.IANAToken) {
+		case string(ics.ComponentPropertyRrule):
+			if rule := parseRRule(prop.Value); rule != nil {
+				if loc != nil && rule.Until != nil {
+					rule.Until = jscal.NewLocalDateTime(rule.Until.Time().In(loc))
+				}
+				event.RecurrenceRules = append(event.RecurrenceRules, *rule)
+			}
+		case string(ics.ComponentPropertyExrule):
+			if rule := parseRRule(prop.Value); rule != nil {
+				if loc != nil && rule.Until != nil {
+					rule.Until = jscal.NewLocalDateTime(rule.Until.Time().In(loc))
+				}
+				event.ExcludedRecurrenceRules = append(event.ExcludedRecurrenceRules, *rule)
+			}
 		}
 	}
 }
 
 func convertRecurrenceRules(event *jscal.Event, vevent *ics.VEvent) {
+	loc := eventLocation(event)
 	for _, rule := range event.RecurrenceRules {
-		rrule := formatRRule(&rule)
-		if rrule != "" {
+		if rrule := formatRRule(utcUntil(rule, loc), event.IsAllDay()); rrule != "" {
 			vevent.AddProperty(ics.ComponentPropertyRrule, rrule)
 		}
 	}
+	for _, rule := range event.ExcludedRecurrenceRules {
+		if exrule := formatRRule(utcUntil(rule, loc), event.IsAllDay()); exrule != "" {
+			// golang-ical has no value type for EXRULE and would escape it as TEXT
+			vevent.AddProperty(ics.ComponentPropertyExrule, exrule, ics.WithValue(string(ics.ValueDataTypeRecur)))
+		}
+	}
+}
+
+// utcUntil returns rule with its UNTIL, a wall clock time in loc, in UTC.
+// The rule is returned as is if loc is nil.
+func utcUntil(rule jscal.RecurrenceRule, loc *time.Location) *jscal.RecurrenceRule {
+	if loc != nil && rule.Until != nil {
+		rule.Until = jscal.NewLocalDateTime(wallTime(rule.Until.Time(), loc).UTC())
+	}
+	return &rule
+}
+
+// processAlarms converts VALARM components to alerts. A TRIGGER with
+// VALUE=DATE-TIME becomes an AbsoluteTrigger, any other an OffsetTrigger.
+func processAlarms(vevent *ics.VEvent, event *jscal.Event) {
+	for i, valarm := range vevent.Alarms() {
+		prop := valarm.GetProperty(ics.ComponentPropertyTrigger)
+		if prop == nil {
+			continue
+		}
+
+		alert := &jscal.Alert{Type: "Alert"}
+		if value := prop.ICalParameters[string(ics.ParameterValue)]; len(value) > 0 && strings.EqualFold(value[0], "DATE-TIME") {
+			when, err := time.Parse("20060102T150405Z", prop.Value)
+			if err != nil {
+				continue
+			}
+			alert.Trigger = jscal.NewAbsoluteTrigger(when)
+		} else {
+			trigger := jscal.NewOffsetTrigger(strings.TrimPrefix(prop.Value, "+"))
+			if related := prop.ICalParameters[string(ics.ParameterRelated)]; len(related) > 0 && strings.EqualFold(related[0], "END") {
+				trigger.RelativeTo = jscal.String(jscal.RelativeToEnd)
+			}
+			alert.Trigger = trigger
+		}
+
+		if action := valarm.GetProperty(ics.ComponentPropertyAction); action != nil {
+			switch strings.ToUpper(action.Value) {
+			case "DISPLAY", "EMAIL":
+				alert.Action = jscal.String(strings.ToLower(action.Value))
+			}
+		}
+
+		id := fmt.Sprintf("alert%d", i+1)
+		if uid := valarm.GetProperty(ics.ComponentPropertyUniqueId); uid != nil && uid.Value != "" {
+			id = uid.Value
+		}
+		event.AddAlert(id, alert)
+	}
+}
+
+// convertAlerts converts alerts to VALARM components, sorted by id for
+// stable output. Unknown triggers have no iCalendar equivalent and are
+// dropped.
+func convertAlerts(event *jscal.Event, vevent *ics.VEvent) {
+	ids := make([]string, 0, len(event.Alerts))
+	for id := range event.Alerts {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		alert := event.Alerts[id]
+		if alert == nil {
+			continue
+		}
+
+		var value string
+		var params []ics.PropertyParameter
+		switch t := alert.Trigger.(type) {
+		case *jscal.AbsoluteTrigger:
+			value = t.When.Format("20060102T150405Z")
+			params = append(params, ics.WithValue("DATE-TIME"))
+		case *jscal.OffsetTrigger:
+			value = t.Offset
+			if t.RelativeTo != nil && *t.RelativeTo == jscal.RelativeToEnd {
+				params = append(params, &ics.KeyValues{Key: string(ics.ParameterRelated), Value: []string{"END"}})
+			}
+		default:
+			continue
+		}
+
+		action := "DISPLAY"
+		if alert.Action != nil && strings.EqualFold(*alert.Action, "email") {
+			action = "EMAIL"
+		}
+
+		valarm := vevent.AddAlarm()
+		valarm.SetProperty(ics.ComponentPropertyAction, action)
+		valarm.SetProperty(ics.ComponentPropertyTrigger, value, params...)
+		if event.Title != nil {
+			valarm.SetProperty(ics.ComponentPropertyDescription, *event.Title)
+		}
+	}
+}
+
+// processRequestStatus converts the first valid REQUEST-STATUS property to
+// requestStatus. JSCalendar keeps a single status where iTIP replies may
+// carry one per request.
+func processRequestStatus(vevent *ics.VEvent, event *jscal.Event) {
+	for i := range vevent.Properties {
+		prop := &vevent.Properties[i]
+		if prop.IANAToken != string(ics.ComponentPropertyRequestStatus) {
+			continue
+		}
+		if rs, err := jscal.ParseRequestStatus(prop.Value); err == nil {
+			event.SetRequestStatus(rs.String())
+			return
+		}
+	}
+}
+
+// Invitation permission properties, as JMAP servers write them
+const (
+	propertyMayInviteSelf   = "X-JMAP-MAY-INVITE-SELF"
+	propertyMayInviteOthers = "X-JMAP-MAY-INVITE-OTHERS"
+	propertyHideAttendees   = "X-JMAP-HIDE-ATTENDEES"
+)
+
+// invitePermission is an invitation permission property and the field of
+// an event it maps to
+type invitePermission struct {
+	name  string
+	field **bool
+}
+
+// invitePermissions returns the invitation permission properties of an event
+func invitePermissions(event *jscal.Event) []invitePermission {
+	return []invitePermission{
+		{propertyMayInviteSelf, &event.MayInviteSelf},
+		{propertyMayInviteOthers, &event.MayInviteOthers},
+		{propertyHideAttendees, &event.HideAttendees},
+	}
+}
+
+// processInvitePermissions converts the BOOLEAN X-JMAP-* invitation
+// permission properties to mayInviteSelf, mayInviteOthers and
+// hideAttendees. Values other than TRUE and FALSE are ignored.
+func processInvitePermissions(vevent *ics.VEvent, event *jscal.Event) {
+	for _, permission := range invitePermissions(event) {
+		prop := vevent.GetProperty(ics.ComponentProperty(permission.name))
+		if prop == nil {
+			continue
+		}
+		switch strings.ToUpper(strings.TrimSpace(prop.Value)) {
+		case "TRUE":
+			*permission.field = jscal.Bool(true)
+		case "FALSE":
+			*permission.field = jscal.Bool(false)
+		}
+	}
+}
+
+// convertInvitePermissions writes the invitation permissions that are set
+// as X-JMAP-* properties
+func convertInvitePermissions(event *jscal.Event, vevent *ics.VEvent) {
+	for _, permission := range invitePermissions(event) {
+		if value := *permission.field; value != nil {
+			ical := "FALSE"
+			if *value {
+				ical = "TRUE"
+			}
+			vevent.SetProperty(ics.ComponentProperty(permission.name), ical)
+		}
+	}
+}
+
+// processRelations converts RELATED-TO properties to relatedTo. RELTYPE
+// defaults to PARENT as in RFC 5545.
+func processRelations(vevent *ics.VEvent, event *jscal.Event) {
+	for i := range vevent.Properties {
+		prop := &vevent.Properties[i]
+		if prop.IANAToken != string(ics.ComponentPropertyRelatedTo) || prop.Value == "" {
+			continue
+		}
+
+		relType := jscal.RelationTypeParent
+		if reltype := prop.ICalParameters[string(ics.ParameterReltype)]; len(reltype) > 0 && reltype[0] != "" {
+			relType = strings.ToLower(reltype[0])
+		}
+		event.AddRelation(prop.Value, relType)
+	}
+}
+
+// convertRelations converts relatedTo to RELATED-TO properties, one per
+// relation type. A relation without types is written without RELTYPE, which
+// iCalendar reads as a parent.
+func convertRelations(event *jscal.Event, vevent *ics.VEvent) {
+	uids := make([]string, 0, len(event.RelatedTo))
+	for uid := range event.RelatedTo {
+		uids = append(uids, uid)
+	}
+	sort.Strings(uids)
+
+	for _, uid := range uids {
+		var relTypes []string
+		if relation := event.RelatedTo[uid]; relation != nil {
+			for relType, ok := range relation.Relation {
+				if ok {
+					relTypes = append(relTypes, relType)
+				}
+			}
+		}
+		sort.Strings(relTypes)
+
+		if len(relTypes) == 0 {
+			vevent.AddProperty(ics.ComponentPropertyRelatedTo, uid)
+			continue
+		}
+		for _, relType := range relTypes {
+			vevent.AddProperty(ics.ComponentPropertyRelatedTo, uid,
+				&ics.KeyValues{Key: string(ics.ParameterReltype), Value: []string{strings.ToUpper(relType)}})
+		}
+	}
+}
+
+// propertyAppleStructuredLocation is the property Apple Calendar uses for
+// locations with coordinates
+const propertyAppleStructuredLocation = "X-APPLE-STRUCTURED-LOCATION"
+
+// processLocations converts each LOCATION to a location. GEO sets the
+// coordinates of the first one, and X-APPLE-STRUCTURED-LOCATION those of the
+// location with the same name, adding a location if there is none. Its
+// X-ADDRESS becomes the full text of the location's address, as Apple
+// Calendar doesn't split it into parts.
+func processLocations(vevent *ics.VEvent, event *jscal.Event) {
+	for i := range vevent.Properties {
+		if prop := &vevent.Properties[i]; prop.IANAToken == string(ics.ComponentPropertyLocation) {
+			event.AddLocation(strconv.Itoa(len(event.Locations)+1), jscal.NewLocation(prop.Value))
+		}
+	}
+
+	if geo := vevent.GetProperty(ics.ComponentPropertyGeo); geo != nil {
+		if coordinates := parseGeo(geo.Value); coordinates != "" {
+			loc, ok := event.Locations["1"]
+			if !ok {
+				loc = &jscal.Location{Type: jscal.String("Location")}
+				event.AddLocation("1", loc)
+			}
+			loc.Coordinates = &coordinates
+		}
+	}
+
+	for i := range vevent.Properties {
+		prop := &vevent.Properties[i]
+		if prop.IANAToken != propertyAppleStructuredLocation || !strings.HasPrefix(strings.ToLower(prop.Value), "geo:") {
+			continue
+		}
+		coordinates := prop.Value
+		title, _ := textParam(prop, "X-TITLE")
+		address, _ := textParam(prop, "X-ADDRESS")
+
+		var loc *jscal.Location
+		for _, id := range sortedKeys(event.Locations) {
+			if l := event.Locations[id]; l != nil && l.Name != nil && *l.Name == title {
+				loc = l
+				break
+			}
+		}
+		if loc == nil {
+			loc = &jscal.Location{Type: jscal.String("Location")}
+			if title != "" {
+				loc.Name = &title
+			}
+			event.AddLocation(strconv.Itoa(len(event.Locations)+1), loc)
+		}
+		loc.Coordinates = &coordinates
+		if address != "" {
+			// Apple Calendar separates the lines with an escaped newline
+			address = strings.NewReplacer(`\\n`, "\n", `\n`, "\n").Replace(address)
+			loc.Address = &jscal.Address{Type: "Address", Full: &address}
+		}
+	}
+}
+
+// convertLocations writes the locations of an event as the options say.
+// Without structured locations, GEO holds the coordinates of the first
+// location that has them, as iCalendar allows only one; that location is
+// written first, since GEO applies to the first LOCATION when parsing.
+func convertLocations(event *jscal.Event, vevent *ics.VEvent, opts FormatOptions) {
+	mode := opts.Locations
+	ids := sortedKeys(event.Locations)
+	if mode != LocationsAppleStructured {
+		for i, id := range ids {
+			if location := event.Locations[id]; location != nil && location.Coordinates != nil {
+				if geo := formatGeo(*location.Coordinates); geo != "" {
+					vevent.SetProperty(ics.ComponentPropertyGeo, geo)
+					ids = append(append([]string{id}, ids[:i]...), ids[i+1:]...)
+					break
+				}
+			}
+		}
+	}
+
+	var names []string
+	for _, id := range ids {
+		location := event.Locations[id]
+		if location == nil {
+			continue
+		}
+		if mode == LocationsAppleStructured && location.Coordinates != nil {
+			params := []ics.PropertyParameter{ics.WithValue("URI")}
+			if location.Name != nil {
+				params = append(params, textParamValue("X-TITLE", *location.Name))
+			}
+			if lines := location.Address.Lines(); len(lines) > 0 {
+				params = append(params, textParamValue("X-ADDRESS", strings.Join(lines, "\n")))
+			}
+			vevent.AddProperty(propertyAppleStructuredLocation, *location.Coordinates, params...)
+		}
+		if name := locationText(location, opts.LocationAddresses); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	switch {
+	case len(names) == 0:
+	case mode == LocationsAppleStructured:
+		vevent.SetLocation(names[0])
+	default:
+		for _, name := range names {
+			vevent.AddProperty(ics.ComponentPropertyLocation, name)
+		}
+	}
+}
+
+// locationText returns the LOCATION text of a location: its name, followed
+// by its address if withAddress, or its address if it has no name
+func locationText(location *jscal.Location, withAddress bool) string {
+	address := location.Address.String()
+	switch {
+	case location.Name == nil:
+		return address
+	case withAddress && address != "":
+		return *location.Name + ", " + address
+	}
+	return *location.Name
+}
+
+// parseGeo converts a GEO value ("lat;lon") to a geo: URI, or returns ""
+// if it isn't a valid pair of coordinates
+func parseGeo(value string) string {
+	lat, lon, ok := strings.Cut(value, ";")
+	if !ok {
+		return ""
+	}
+	lat, lon = strings.TrimSpace(lat), strings.TrimSpace(lon)
+	if _, err := strconv.ParseFloat(lat, 64); err != nil {
+		return ""
+	}
+	if _, err := strconv.ParseFloat(lon, 64); err != nil {
+		return ""
+	}
+	return "geo:" + lat + "," + lon
+}
+
+// formatGeo converts a geo: URI to a GEO value. Altitude and URI
+// parameters such as the uncertainty are dropped, GEO has no room for them.
+func formatGeo(coordinates string) string {
+	if !strings.HasPrefix(strings.ToLower(coordinates), "geo:") {
+		return ""
+	}
+	coords, _, _ := strings.Cut(coordinates[len("geo:"):], ";")
+	parts := strings.Split(coords, ",")
+	if len(parts) < 2 {
+		return ""
+	}
+	return strings.TrimSpace(parts[0]) + ";" + strings.TrimSpace(parts[1])
+}
+
+// isAttachment reports whether a link is an attachment, written as ATTACH
+// rather than URL
+func isAttachment(link *jscal.Link) bool {
+	return link.Rel != nil && *link.Rel == "enclosure"
+}
+
+// processAttachments converts ATTACH properties to links with rel
+// "enclosure". Inline attachments (ENCODING=BASE64) become data: URIs.
+func processAttachments(vevent *ics.VEvent, event *jscal.Event) {
+	for i := range vevent.Properties {
+		prop := &vevent.Properties[i]
+		if prop.IANAToken != string(ics.ComponentPropertyAttach) || prop.Value == "" {
+			continue
+		}
+
+		link := jscal.NewLink(prop.Value)
+		link.Rel = jscal.String("enclosure")
+		if fmttype := prop.ICalParameters[string(ics.ParameterFmttype)]; len(fmttype) > 0 && fmttype[0] != "" {
+			link.ContentType = &fmttype[0]
+		}
+		if filename, ok := textParam(prop, "FILENAME"); ok {
+			link.Title = &filename
+		}
+		if size := prop.ICalParameters["SIZE"]; len(size) > 0 {
+			if n, err := strconv.Atoi(size[0]); err == nil && n >= 0 {
+				link.Size = &n
+			}
+		}
+
+		if encoding := prop.ICalParameters[string(ics.ParameterEncoding)]; len(encoding) > 0 && strings.EqualFold(encoding[0], "BASE64") {
+			data, err := base64.StdEncoding.DecodeString(prop.Value)
+			if err != nil {
+				continue
+			}
+			contentType := "application/octet-stream"
+			if link.ContentType != nil {
+				contentType = *link.ContentType
+			}
+			link.Href = "data:" + contentType + ";base64," + prop.Value
+			size := len(data)
+			link.Size = &size
+		}
+
+		event.AddLink(strconv.Itoa(len(event.Links)+1), link)
+	}
+}
+
+// dropLargeAttachments removes inline attachments over MaxAttachmentSize
+func (c *Converter) dropLargeAttachments(ctx context.Context, event *jscal.Event) {
+	if c.MaxAttachmentSize <= 0 {
+		return
+	}
+	for id, link := range event.Attachments() {
+		if !link.IsInline() {
+			continue
+		}
+		if data, _, err := link.Data(); err != nil || len(data) > c.MaxAttachmentSize {
+			delete(event.Links, id)
+			c.Hooks.Add(ctx, observe.DroppedItems, 1, slog.String("item", "ATTACH"))
+			c.Hooks.Warn(ctx, "dropped inline attachment over MaxAttachmentSize", slog.String("uid", event.UID), slog.Int("size", len(data)))
+		}
+	}
+	if len(event.Links) == 0 {
+		event.Links = nil
+	}
+}
+
+// convertAttachments writes links with rel "enclosure" as ATTACH
+// properties. Base64 data: URIs are written inline.
+func convertAttachments(event *jscal.Event, vevent *ics.VEvent) {
+	for _, id := range sortedKeys(event.Links) {
+		link := event.Links[id]
+		if link == nil || !isAttachment(link) {
+			continue
+		}
+
+		var params []ics.PropertyParameter
+		value := link.Href
+		if rest, ok := strings.CutPrefix(link.Href, "data:"); ok && strings.Contains(rest, ";base64,") {
+			mediaType, data, _ := strings.Cut(rest, ";base64,")
+			value = data
+			params = append(params,
+				&ics.KeyValues{Key: string(ics.ParameterEncoding), Value: []string{"BASE64"}},
+				ics.WithValue("BINARY"))
+			if mediaType != "" {
+				params = append(params, &ics.KeyValues{Key: string(ics.ParameterFmttype), Value: []string{mediaType}})
+			}
+		} else {
+			if link.ContentType != nil {
+				params = append(params, &ics.KeyValues{Key: string(ics.ParameterFmttype), Value: []string{*link.ContentType}})
+			}
+			if link.Size != nil {
+				params = append(params, &ics.KeyValues{Key: "SIZE", Value: []string{strconv.Itoa(*link.Size)}})
+			}
+		}
+		if link.Title != nil {
+			params = append(params, textParamValue("FILENAME", *link.Title))
+		}
+		vevent.AddProperty(ics.ComponentPropertyAttach, value, params...)
+	}
+}
+
+// propertyConference is the RFC 7986 CONFERENCE property
+const propertyConference = "CONFERENCE"
+
+// processConferences converts CONFERENCE properties to virtual locations.
+// LABEL becomes the name and FEATURE the features.
+func processConferences(vevent *ics.VEvent, event *jscal.Event) {
+	for i := range vevent.Properties {
+		prop := &vevent.Properties[i]
+		if prop.IANAToken != propertyConference || prop.Value == "" {
+			continue
+		}
+
+		virtualLocation := &jscal.VirtualLocation{Type: "VirtualLocation", URI: prop.Value}
+		if label, ok := textParam(prop, "LABEL"); ok {
+			virtualLocation.Name = &label
+		}
+		for _, features := range prop.ICalParameters["FEATURE"] {
+			for _, feature := range strings.Split(features, ",") {
+				if feature = strings.ToLower(strings.TrimSpace(feature)); feature != "" {
+					if virtualLocation.Features == nil {
+						virtualLocation.Features = make(map[string]bool)
+					}
+					virtualLocation.Features[feature] = true
+				}
+			}
+		}
+
+		event.AddVirtualLocation(strconv.Itoa(len(event.VirtualLocations)+1), virtualLocation)
+	}
 }
 
+// convertConferences writes virtual locations as CONFERENCE properties
+func convertConferences(event *jscal.Event, vevent *ics.VEvent) {
+	for _, id := range sortedKeys(event.VirtualLocations) {
+		virtualLocation := event.VirtualLocations[id]
+		if virtualLocation == nil || virtualLocation.URI == "" {
+			continue
+		}
+
+		params := []ics.PropertyParameter{ics.WithValue("URI")}
+		var features []string
+		for _, feature := range sortedKeys(virtualLocation.Features) {
+			if virtualLocation.Features[feature] {
+				features = append(features, strings.ToUpper(feature))
+			}
+		}
+		if len(features) > 0 {
+			params = append(params, &ics.KeyValues{Key: "FEATURE", Value: features})
+		}
+		if virtualLocation.Name != nil && *virtualLocation.Name != "" {
+			params = append(params, textParamValue("LABEL", *virtualLocation.Name))
+		}
+		vevent.AddProperty(propertyConference, virtualLocation.URI, params...)
+	}
+}
+
+// weekStartDays maps WKST values to firstDayOfWeek, where 0 is Monday
+var weekStartDays = []string{"MO", "TU", "WE", "TH", "FR", "SA", "SU"}
+
+// parseRRule converts an RRULE value, including the RSCALE and SKIP parts of
+// RFC 7529, to a recurrence rule. Unknown parts and invalid values are
+// ignored.
 func parseRRule(rruleValue string) *jscal.RecurrenceRule {
 	rule := &jscal.RecurrenceRule{
 		Type: "RecurrenceRule",
@@ -720,15 +1485,67 @@ func parseRRule(rruleValue string) *jscal.RecurrenceRule {
 					rule.ByDay = append(rule.ByDay, *nday)
 				}
 			}
+		case "BYMONTH":
+			for _, month := range strings.Split(value, ",") {
+				if month = strings.TrimSpace(month); month != "" {
+					rule.ByMonth = append(rule.ByMonth, strings.ToUpper(month))
+				}
+			}
+		case "BYMONTHDAY":
+			rule.ByMonthDay = parseIntList(value)
+		case "BYYEARDAY":
+			rule.ByYearDay = parseIntList(value)
+		case "BYWEEKNO":
+			rule.ByWeekNo = parseIntList(value)
+		case "BYHOUR":
+			rule.ByHour = parseIntList(value)
+		case "BYMINUTE":
+			rule.ByMinute = parseIntList(value)
+		case "BYSECOND":
+			rule.BySecond = parseIntList(value)
+		case "BYSETPOS":
+			rule.BySetPos = parseIntList(value)
+		case "WKST":
+			for i, day := range weekStartDays {
+				if strings.EqualFold(value, day) {
+					rule.FirstDayOfWeek = jscal.Int(i)
+				}
+			}
+		case "RSCALE":
+			rule.RScale = jscal.String(strings.ToLower(value))
+		case "SKIP":
+			rule.Skip = jscal.String(strings.ToLower(value))
 		}
 	}
 
 	return rule
 }
 
-func formatRRule(rule *jscal.RecurrenceRule) string {
+// parseIntList parses a comma-separated list of integers, such as
+// BYMONTHDAY=1,-1, skipping invalid entries
+func parseIntList(value string) []int {
+	var values []int
+	for _, part := range strings.Split(value, ",") {
+		if n, err := strconv.Atoi(strings.TrimSpace(part)); err == nil {
+			values = append(values, n)
+		}
+	}
+	return values
+}
+
+// formatRRule converts a recurrence rule to an RRULE value. UNTIL is a date
+// for all-day events, as it must match the value type of DTSTART.
+func formatRRule(rule *jscal.RecurrenceRule, allDay bool) string {
 	var parts []string
 
+	// RFC 7529 puts RSCALE first; SKIP is only allowed with it
+	if rule.RScale != nil && *rule.RScale != "" {
+		parts = append(parts, fmt.Sprintf("RSCALE=%s", strings.ToUpper(*rule.RScale)))
+		if rule.Skip != nil && *rule.Skip != "" {
+			parts = append(parts, fmt.Sprintf("SKIP=%s", strings.ToUpper(*rule.Skip)))
+		}
+	}
+
 	// FREQ is required
 	if rule.Frequency != "" {
 		parts = append(parts, fmt.Sprintf("FREQ=%s", strings.ToUpper(rule.Frequency)))
@@ -743,10 +1560,23 @@ func formatRRule(rule *jscal.RecurrenceRule) string {
 	if rule.Count != nil {
 		parts = append(parts, fmt.Sprintf("COUNT=%d", *rule.Count))
 	} else if rule.Until != nil {
-		parts = append(parts, fmt.Sprintf("UNTIL=%s", rule.Until.Time().Format("20060102T150405Z")))
+		if allDay {
+			parts = append(parts, fmt.Sprintf("UNTIL=%s", rule.Until.Time().Format("20060102")))
+		} else {
+			parts = append(parts, fmt.Sprintf("UNTIL=%s", rule.Until.Time().Format("20060102T150405Z")))
+		}
 	}
 
-	// BYDAY
+	// BYxxx parts, in the order RFC 5545 lists them
+	if len(rule.BySecond) > 0 {
+		parts = append(parts, "BYSECOND="+formatIntList(rule.BySecond))
+	}
+	if len(rule.ByMinute) > 0 {
+		parts = append(parts, "BYMINUTE="+formatIntList(rule.ByMinute))
+	}
+	if len(rule.ByHour) > 0 {
+		parts = append(parts, "BYHOUR="+formatIntList(rule.ByHour))
+	}
 	if len(rule.ByDay) > 0 {
 		var dayStrings []string
 		for _, nday := range rule.ByDay {
@@ -758,6 +1588,33 @@ func formatRRule(rule *jscal.RecurrenceRule) string {
 		}
 		parts = append(parts, fmt.Sprintf("BYDAY=%s", strings.Join(dayStrings, ",")))
 	}
+	if len(rule.ByMonthDay) > 0 {
+		parts = append(parts, "BYMONTHDAY="+formatIntList(rule.ByMonthDay))
+	}
+	if len(rule.ByYearDay) > 0 {
+		parts = append(parts, "BYYEARDAY="+formatIntList(rule.ByYearDay))
+	}
+	if len(rule.ByWeekNo) > 0 {
+		parts = append(parts, "BYWEEKNO="+formatIntList(rule.ByWeekNo))
+	}
+	if len(rule.ByMonth) > 0 {
+		parts = append(parts, "BYMONTH="+strings.ToUpper(strings.Join(rule.ByMonth, ",")))
+	}
+	if len(rule.BySetPos) > 0 {
+		parts = append(parts, "BYSETPOS="+formatIntList(rule.BySetPos))
+	}
+	if rule.FirstDayOfWeek != nil && *rule.FirstDayOfWeek >= 0 && *rule.FirstDayOfWeek < len(weekStartDays) {
+		parts = append(parts, "WKST="+weekStartDays[*rule.FirstDayOfWeek])
+	}
 
 	return strings.Join(parts, ";")
 }
+
+// formatIntList formats integers as a comma-separated list
+func formatIntList(values []int) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = strconv.Itoa(v)
+	}
+	return strings.Join(parts, ",")
+}
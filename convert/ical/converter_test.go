@@ -1,13 +1,33 @@
 package ical
 
 import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/airtrafik/jscal"
+	"github.com/airtrafik/jscal/convert"
 )
 
+func TestConverterRegistered(t *testing.T) {
+	for _, name := range []string{"ical", "icalendar", "ics"} {
+		c, ok := convert.Lookup(name)
+		if !ok {
+			t.Fatalf("Expected %s to be registered", name)
+		}
+		if _, isICal := c.(*Converter); !isICal {
+			t.Errorf("Expected *Converter for %s, got %T", name, c)
+		}
+	}
+	if format, ok := convert.FormatForExtension(".ics"); !ok || format != "ical" {
+		t.Errorf("Expected ical for .ics, got %q", format)
+	}
+}
+
 func TestConverterDetect(t *testing.T) {
 	converter := New()
 
@@ -227,6 +247,84 @@ END:VCALENDAR`
 	if optional.ParticipationStatus == nil || *optional.ParticipationStatus != "needs-action" {
 		t.Errorf("Expected participation status 'needs-action', got %v", optional.ParticipationStatus)
 	}
+
+	// Calendar addresses become the imip sendTo method
+	if imip := optional.SendTo[jscal.ReplyMethodImip]; imip != "mailto:bob.johnson@example.com" {
+		t.Errorf("Expected sendTo imip 'mailto:bob.johnson@example.com', got %q", imip)
+	}
+	if imip := organizer.SendTo[jscal.ReplyMethodImip]; imip != "mailto:john.doe@example.com" {
+		t.Errorf("Expected organizer sendTo imip 'mailto:john.doe@example.com', got %q", imip)
+	}
+}
+
+func TestInvitePermissions(t *testing.T) {
+	converter := New()
+
+	icalData := `BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//Test//Test//EN
+BEGIN:VEVENT
+UID:open-house@example.com
+SUMMARY:Open house
+DTSTART:20250301T140000Z
+ORGANIZER;CN=John Doe:mailto:john.doe@example.com
+X-JMAP-MAY-INVITE-SELF:TRUE
+X-JMAP-MAY-INVITE-OTHERS:false
+X-JMAP-HIDE-ATTENDEES:maybe
+END:VEVENT
+END:VCALENDAR`
+
+	event, err := converter.Parse([]byte(icalData))
+	if err != nil {
+		t.Fatalf("Failed to convert event: %v", err)
+	}
+	if event.MayInviteSelf == nil || !*event.MayInviteSelf {
+		t.Errorf("Expected mayInviteSelf true, got %v", event.MayInviteSelf)
+	}
+	if event.MayInviteOthers == nil || *event.MayInviteOthers {
+		t.Errorf("Expected mayInviteOthers false, got %v", event.MayInviteOthers)
+	}
+	if event.HideAttendees != nil {
+		t.Errorf("Expected an invalid hideAttendees to be ignored, got %v", *event.HideAttendees)
+	}
+
+	event.SetHideAttendees(true)
+	data, err := converter.Format(event)
+	if err != nil {
+		t.Fatalf("Failed to format event: %v", err)
+	}
+	for _, want := range []string{"X-JMAP-MAY-INVITE-SELF:TRUE", "X-JMAP-MAY-INVITE-OTHERS:FALSE", "X-JMAP-HIDE-ATTENDEES:TRUE"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Expected %s in:\n%s", want, data)
+		}
+	}
+
+	parsed, err := converter.Parse(data)
+	if err != nil {
+		t.Fatalf("Failed to parse formatted event: %v", err)
+	}
+	if !parsed.GetMayInviteSelf() || parsed.GetMayInviteOthers() || !parsed.HasMayInviteOthers() || !parsed.GetHideAttendees() {
+		t.Errorf("Expected the permissions to round-trip, got %v %v %v", parsed.MayInviteSelf, parsed.MayInviteOthers, parsed.HideAttendees)
+	}
+}
+
+func TestParticipantSendToFormat(t *testing.T) {
+	event := jscal.NewEvent("sendto@example.com", "Review")
+	event.Start = jscal.NewLocalDateTime(time.Date(2025, 3, 1, 14, 0, 0, 0, time.UTC))
+	participant := jscal.NewParticipant("Tom Tool", "tom@foobar.example.com")
+	if err := participant.AddSendTo(jscal.ReplyMethodImip, "mailto:tom@calendar.example.com"); err != nil {
+		t.Fatal(err)
+	}
+	event.AddParticipant("dG9tQGZvb2Jhci5xlLmNvbQ", participant)
+
+	output, err := New().Format(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unfolded := strings.ReplaceAll(string(output), "\r\n ", "")
+	if !strings.Contains(unfolded, ":mailto:tom@calendar.example.com\r\n") {
+		t.Errorf("Expected the sendTo address as calendar address, got:\n%s", output)
+	}
 }
 
 func TestRecurringEventConversion(t *testing.T) {
@@ -295,6 +393,703 @@ END:VCALENDAR`
 	}
 }
 
+func TestRRuleRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		rrule string
+	}{
+		{name: "last working day", rrule: "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1"},
+		{name: "month days", rrule: "FREQ=MONTHLY;INTERVAL=2;BYMONTHDAY=1,15,-1"},
+		{name: "thanksgiving", rrule: "FREQ=YEARLY;BYDAY=4TH;BYMONTH=11"},
+		{name: "year days", rrule: "FREQ=YEARLY;COUNT=10;BYYEARDAY=1,100,-1"},
+		{name: "week numbers", rrule: "FREQ=YEARLY;BYDAY=MO;BYWEEKNO=20;WKST=SU"},
+		{name: "times of day", rrule: "FREQ=DAILY;BYSECOND=0,30;BYMINUTE=0,15;BYHOUR=9,17"},
+		{name: "rscale", rrule: "RSCALE=GREGORIAN;SKIP=FORWARD;FREQ=YEARLY;BYMONTHDAY=29;BYMONTH=2"},
+		{name: "leap month", rrule: "RSCALE=CHINESE;FREQ=YEARLY;BYMONTH=5L"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := parseRRule(tt.rrule)
+			if got := formatRRule(rule, false); got != tt.rrule {
+				t.Errorf("Expected %s, got %s", tt.rrule, got)
+			}
+		})
+	}
+
+	rule := parseRRule("FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1;WKST=SU;BYMONTH=1,7")
+	if len(rule.BySetPos) != 1 || rule.BySetPos[0] != -1 {
+		t.Errorf("Expected bySetPos [-1], got %v", rule.BySetPos)
+	}
+	if rule.FirstDayOfWeek == nil || *rule.FirstDayOfWeek != 6 {
+		t.Errorf("Expected firstDayOfWeek 6 (Sunday), got %v", rule.FirstDayOfWeek)
+	}
+	if strings.Join(rule.ByMonth, ",") != "1,7" {
+		t.Errorf("Expected byMonth [1 7], got %v", rule.ByMonth)
+	}
+
+	until := &jscal.RecurrenceRule{Frequency: "daily", Until: jscal.NewLocalDateTime(time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC))}
+	if got := formatRRule(until, true); got != "FREQ=DAILY;UNTIL=20250331" {
+		t.Errorf("Expected date UNTIL for all-day events, got %s", got)
+	}
+}
+
+func TestMultipleRRulesAndExRule(t *testing.T) {
+	icalData := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Test//Test//EN",
+		"BEGIN:VEVENT",
+		"UID:gym@example.com",
+		"SUMMARY:Gym",
+		"DTSTART:20250303T070000Z",
+		"RRULE:FREQ=WEEKLY;BYDAY=MO",
+		"RRULE:FREQ=WEEKLY;BYDAY=TH",
+		"EXRULE:FREQ=MONTHLY;BYDAY=1MO",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	converter := New()
+	events, err := converter.ParseAll([]byte(icalData))
+	if err != nil {
+		t.Fatalf("ParseAll failed: %v", err)
+	}
+	event := events[0]
+	if len(event.RecurrenceRules) != 2 || len(event.ExcludedRecurrenceRules) != 1 {
+		t.Fatalf("Expected 2 rules and 1 excluded rule, got %d and %d",
+			len(event.RecurrenceRules), len(event.ExcludedRecurrenceRules))
+	}
+
+	output, err := converter.Format(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"RRULE:FREQ=WEEKLY;BYDAY=MO", "RRULE:FREQ=WEEKLY;BYDAY=TH", "EXRULE;VALUE=RECUR:FREQ=MONTHLY;BYDAY=1MO"} {
+		if !strings.Contains(string(output), line+"\r\n") {
+			t.Errorf("Expected %s in output:\n%s", line, output)
+		}
+	}
+
+	reparsed, err := converter.Parse(output)
+	if err != nil {
+		t.Fatalf("Parse of output failed: %v", err)
+	}
+	if len(reparsed.ExcludedRecurrenceRules) != 1 || reparsed.ExcludedRecurrenceRules[0].Frequency != "monthly" ||
+		len(reparsed.ExcludedRecurrenceRules[0].ByDay) != 1 {
+		t.Errorf("Expected the excluded rule to round trip, got %+v", reparsed.ExcludedRecurrenceRules)
+	}
+}
+
+func TestRecurrenceDatesAndDetachedInstances(t *testing.T) {
+	icalData := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Test//Test//EN",
+		"BEGIN:VEVENT",
+		"UID:standup@example.com",
+		"SUMMARY:Standup",
+		"DTSTART:20250303T090000Z",
+		"DURATION:PT15M",
+		"RRULE:FREQ=DAILY;COUNT=5",
+		"EXDATE:20250304T090000Z,20250305T090000Z",
+		"RDATE;VALUE=PERIOD:20250308T100000Z/PT30M",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:standup@example.com",
+		"RECURRENCE-ID:20250306T090000Z",
+		"SUMMARY:Standup (moved)",
+		"DTSTART:20250306T110000Z",
+		"DURATION:PT15M",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:standup@example.com",
+		"RECURRENCE-ID:20250305T090000Z",
+		"SUMMARY:Cancelled anyway",
+		"DTSTART:20250305T090000Z",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	converter := New()
+	events, err := converter.ParseAll([]byte(icalData))
+	if err != nil {
+		t.Fatalf("ParseAll failed: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("Expected detached instances to merge into 1 event, got %d", len(events))
+	}
+	event := events[0]
+
+	tests := []struct {
+		key   string
+		patch map[string]interface{}
+	}{
+		{"2025-03-04T09:00:00", map[string]interface{}{"excluded": true}},
+		{"2025-03-05T09:00:00", map[string]interface{}{"excluded": true}},
+		{"2025-03-06T09:00:00", map[string]interface{}{"start": "2025-03-06T11:00:00", "title": "Standup (moved)"}},
+		{"2025-03-08T10:00:00", map[string]interface{}{"duration": "PT30M"}},
+	}
+	if len(event.RecurrenceOverrides) != len(tests) {
+		t.Errorf("Expected %d overrides, got %v", len(tests), event.RecurrenceOverrides)
+	}
+	for _, tt := range tests {
+		if got := event.RecurrenceOverrides[tt.key]; !reflect.DeepEqual(got, tt.patch) {
+			t.Errorf("Expected override %s to be %v, got %v", tt.key, tt.patch, got)
+		}
+	}
+
+	output, err := converter.Format(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		"EXDATE:20250304T090000Z",
+		"EXDATE:20250305T090000Z",
+		"RECURRENCE-ID:20250306T090000Z",
+		"SUMMARY:Standup (moved)",
+		"RDATE:20250308T100000Z",
+		"RECURRENCE-ID:20250308T100000Z",
+	} {
+		if !strings.Contains(string(output), line+"\r\n") {
+			t.Errorf("Expected %s in output:\n%s", line, output)
+		}
+	}
+
+	roundTrip, err := converter.ParseAll(output)
+	if err != nil {
+		t.Fatalf("ParseAll of output failed: %v", err)
+	}
+	if len(roundTrip) != 1 {
+		t.Fatalf("Expected 1 event after round trip, got %d", len(roundTrip))
+	}
+	moved := roundTrip[0].RecurrenceOverrides["2025-03-06T09:00:00"]
+	if moved["title"] != "Standup (moved)" || moved["start"] != "2025-03-06T11:00:00" {
+		t.Errorf("Expected moved instance to survive the round trip, got %v", moved)
+	}
+	// The added instance keeps its RDATE and its duration
+	if strings.Contains(string(output), "RDATE:20250306") {
+		t.Errorf("Expected no RDATE for an instance the rule produces:\n%s", output)
+	}
+	if added := roundTrip[0].RecurrenceOverrides["2025-03-08T10:00:00"]; !reflect.DeepEqual(added, map[string]interface{}{"duration": "PT30M"}) {
+		t.Errorf("Expected the RDATE period to survive the round trip, got %v", added)
+	}
+}
+
+func TestGeoAttachAndConference(t *testing.T) {
+	icalData := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Test//Test//EN",
+		"BEGIN:VEVENT",
+		"UID:review@example.com",
+		"SUMMARY:Design review",
+		"DTSTART:20250310T140000Z",
+		"LOCATION:Room 4",
+		"GEO:48.198634;16.371648",
+		"URL:https://example.com/review",
+		"ATTACH;FMTTYPE=application/pdf;SIZE=2048:https://example.com/agenda.pdf",
+		"ATTACH;ENCODING=BASE64;VALUE=BINARY;FMTTYPE=text/plain:aGVsbG8=",
+		"CONFERENCE;VALUE=URI;FEATURE=AUDIO,VIDEO;LABEL=Join the call:https://meet.example.com/abc",
+		"CONFERENCE;VALUE=URI;FEATURE=PHONE:tel:+1-555-0100",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	converter := New()
+	events, err := converter.ParseAll([]byte(icalData))
+	if err != nil {
+		t.Fatalf("ParseAll failed: %v", err)
+	}
+	event := events[0]
+
+	if loc := event.Locations["1"]; loc == nil || loc.Coordinates == nil || *loc.Coordinates != "geo:48.198634,16.371648" {
+		t.Errorf("Expected coordinates geo:48.198634,16.371648, got %+v", loc)
+	}
+
+	if len(event.Links) != 3 {
+		t.Fatalf("Expected 3 links, got %d", len(event.Links))
+	}
+	pdf := event.Links["2"]
+	if pdf.Href != "https://example.com/agenda.pdf" || pdf.Rel == nil || *pdf.Rel != "enclosure" ||
+		pdf.ContentType == nil || *pdf.ContentType != "application/pdf" || pdf.Size == nil || *pdf.Size != 2048 {
+		t.Errorf("Unexpected attachment link %+v", pdf)
+	}
+	inline := event.Links["3"]
+	if inline.Href != "data:text/plain;base64,aGVsbG8=" || inline.Size == nil || *inline.Size != 5 {
+		t.Errorf("Unexpected inline attachment link %+v", inline)
+	}
+
+	call := event.VirtualLocations["1"]
+	if call == nil || call.URI != "https://meet.example.com/abc" || call.Name == nil || *call.Name != "Join the call" {
+		t.Fatalf("Unexpected virtual location %+v", call)
+	}
+	if !reflect.DeepEqual(call.Features, map[string]bool{"audio": true, "video": true}) {
+		t.Errorf("Expected audio and video features, got %v", call.Features)
+	}
+	if phone := event.VirtualLocations["2"]; phone == nil || !phone.Features["phone"] {
+		t.Errorf("Expected phone virtual location, got %+v", phone)
+	}
+
+	output, err := converter.Format(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		"GEO:48.198634;16.371648",
+		"URL:https://example.com/review",
+		"ATTACH;FMTTYPE=application/pdf;SIZE=2048:https://example.com/agenda.pdf",
+		"ATTACH;ENCODING=BASE64;FMTTYPE=text/plain;VALUE=BINARY:aGVsbG8=",
+		"CONFERENCE;FEATURE=PHONE;VALUE=URI:tel:+1-555-0100",
+	} {
+		if !strings.Contains(string(output), line+"\r\n") {
+			t.Errorf("Expected %s in output:\n%s", line, output)
+		}
+	}
+
+	roundTrip, err := converter.Parse(output)
+	if err != nil {
+		t.Fatalf("Parse of output failed: %v", err)
+	}
+	if !reflect.DeepEqual(roundTrip.VirtualLocations, event.VirtualLocations) {
+		t.Errorf("Expected virtual locations to survive the round trip, got %+v", roundTrip.VirtualLocations)
+	}
+	if !reflect.DeepEqual(roundTrip.Links, event.Links) {
+		t.Errorf("Expected links to survive the round trip, got %+v", roundTrip.Links)
+	}
+}
+
+func TestInlineAttachments(t *testing.T) {
+	event := jscal.NewEvent("files@example.com", "Files")
+	event.Start = jscal.NewLocalDateTime(time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC))
+	if err := event.AttachFile("notes", "notes.txt", "text/plain", []byte("hello"), jscal.AttachOptions{}); err != nil {
+		t.Fatalf("AttachFile failed: %v", err)
+	}
+	if err := event.AttachFile("slides", "slides.pdf", "application/pdf", make([]byte, 2048), jscal.AttachOptions{}); err != nil {
+		t.Fatalf("AttachFile failed: %v", err)
+	}
+
+	output, err := New().Format(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(output), "ATTACH;ENCODING=BASE64;FILENAME=notes.txt;FMTTYPE=text/plain;VALUE=BINARY:") {
+		t.Errorf("Expected an inline ATTACH in output:\n%s", output)
+	}
+
+	roundTrip, err := New().Parse(output)
+	if err != nil {
+		t.Fatalf("Parse of output failed: %v", err)
+	}
+	if len(roundTrip.Attachments()) != 2 || roundTrip.InlineAttachmentSize() != 2053 {
+		t.Errorf("Expected both attachments to survive the round trip, got %+v", roundTrip.Links)
+	}
+
+	// Attachments over the limit are dropped while parsing
+	limited, err := (&Converter{MaxAttachmentSize: 1024}).Parse(output)
+	if err != nil {
+		t.Fatalf("Parse of output failed: %v", err)
+	}
+	attachments := limited.Attachments()
+	if len(attachments) != 1 {
+		t.Fatalf("Expected only the small attachment, got %+v", attachments)
+	}
+	for _, link := range attachments {
+		if data, contentType, err := link.Data(); err != nil || string(data) != "hello" || contentType != "text/plain" {
+			t.Errorf("Expected hello as text/plain, got %q as %s (%v)", data, contentType, err)
+		}
+	}
+}
+
+func TestEventColor(t *testing.T) {
+	tests := []struct {
+		color    string
+		line     string
+		reparsed string
+	}{
+		{"Teal", "COLOR:teal", "teal"},
+		{"#008080", "COLOR:teal", "teal"},
+		{"#007f7e", "COLOR:teal", "teal"},
+		{"hsl(0, 100%, 50%)", "COLOR:red", "red"},
+		{"transparent", "", ""},
+	}
+
+	for _, tt := range tests {
+		event := jscal.NewEvent("color@example.com", "Colored")
+		event.Start = jscal.NewLocalDateTime(time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC))
+		event.Color = jscal.String(tt.color)
+
+		output, err := New().Format(event)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.line != "" && !strings.Contains(string(output), tt.line+"\r\n") {
+			t.Errorf("Color %s: expected %s in output:\n%s", tt.color, tt.line, output)
+		}
+		roundTrip, err := New().Parse(output)
+		if err != nil {
+			t.Fatalf("Parse of output failed: %v", err)
+		}
+		if got := roundTrip.GetColor(); got != tt.reparsed {
+			t.Errorf("Color %s: expected %q after the round trip, got %q", tt.color, tt.reparsed, got)
+		}
+	}
+}
+
+func TestPriority(t *testing.T) {
+	tests := []struct {
+		value    string
+		priority int
+	}{
+		{"1", 1},
+		{"5", 5},
+		{" 9", 9},
+		{"0", 0},
+		{"10", 0},
+		{"high", 0},
+	}
+
+	for _, tt := range tests {
+		icalData := strings.Join([]string{
+			"BEGIN:VCALENDAR",
+			"VERSION:2.0",
+			"PRODID:-//Test//Test//EN",
+			"BEGIN:VEVENT",
+			"UID:priority@example.com",
+			"DTSTART:20250310T090000Z",
+			"PRIORITY:" + tt.value,
+			"END:VEVENT",
+			"END:VCALENDAR",
+		}, "\r\n")
+
+		event, err := New().Parse([]byte(icalData))
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		if event.GetPriority() != tt.priority || event.HasPriority() != (tt.priority > 0) {
+			t.Errorf("PRIORITY:%s: expected priority %d, got %v", tt.value, tt.priority, event.Priority)
+		}
+
+		output, err := New().Format(event)
+		if err != nil {
+			t.Fatal(err)
+		}
+		line := "PRIORITY:" + strconv.Itoa(tt.priority) + "\r\n"
+		if strings.Contains(string(output), line) != (tt.priority > 0) {
+			t.Errorf("PRIORITY:%s: expected %s in output only for a defined priority:\n%s", tt.value, strings.TrimSpace(line), output)
+		}
+	}
+}
+
+func TestMultipleLocationsAndLinks(t *testing.T) {
+	event := jscal.NewEvent("offsite@example.com", "Offsite")
+	event.Start = jscal.NewLocalDateTime(time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC))
+	event.AddLocation("a", jscal.NewLocation("Main office"))
+	hotel := jscal.NewLocation("Hotel")
+	hotel.Coordinates = jscal.String("geo:52.5200,13.4050")
+	event.AddLocation("b", hotel)
+	event.AddLink("1", jscal.NewLink("https://example.com/agenda"))
+	event.AddLink("2", jscal.NewLink("https://example.com/directions"))
+	event.AddVirtualLocation("1", jscal.NewVirtualLocation("Video", "https://meet.example.com/a"))
+	event.AddVirtualLocation("2", jscal.NewVirtualLocation("Backup", "https://meet.example.com/b"))
+
+	tests := []struct {
+		name      string
+		mode      LocationMode
+		expected  []string
+		locations int
+	}{
+		{
+			name: "multiple",
+			mode: LocationsMultiple,
+			expected: []string{
+				"LOCATION:Main office",
+				"LOCATION:Hotel",
+				"GEO:52.5200;13.4050",
+			},
+			locations: 2,
+		},
+		{
+			name: "apple structured",
+			mode: LocationsAppleStructured,
+			expected: []string{
+				"LOCATION:Main office",
+				"X-APPLE-STRUCTURED-LOCATION;VALUE=URI;X-TITLE=Hotel:geo:52.5200,13.4050",
+			},
+			locations: 2,
+		},
+	}
+
+	converter := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := converter.FormatAllWithOptions([]*jscal.Event{event}, FormatOptions{Locations: tt.mode})
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := append(tt.expected,
+				"URL:https://example.com/agenda",
+				"URL:https://example.com/directions",
+				"CONFERENCE;LABEL=Video;VALUE=URI:https://meet.example.com/a",
+				"CONFERENCE;LABEL=Backup;VALUE=URI:https://meet.example.com/b",
+			)
+			for _, line := range expected {
+				if !strings.Contains(string(output), line+"\r\n") {
+					t.Errorf("Expected %s in output:\n%s", line, output)
+				}
+			}
+
+			parsed, err := converter.Parse(output)
+			if err != nil {
+				t.Fatalf("Parse of output failed: %v", err)
+			}
+			if len(parsed.Locations) != tt.locations {
+				t.Errorf("Expected %d locations after round trip, got %d", tt.locations, len(parsed.Locations))
+			}
+			var coordinates []string
+			for _, loc := range parsed.Locations {
+				if loc.Coordinates != nil {
+					coordinates = append(coordinates, *loc.Name+" "+*loc.Coordinates)
+				}
+			}
+			if len(coordinates) != 1 || coordinates[0] != "Hotel geo:52.5200,13.4050" {
+				t.Errorf("Expected Hotel to keep its coordinates, got %v", coordinates)
+			}
+			if len(parsed.Links) != 2 || len(parsed.VirtualLocations) != 2 {
+				t.Errorf("Expected 2 links and 2 virtual locations, got %d and %d",
+					len(parsed.Links), len(parsed.VirtualLocations))
+			}
+		})
+	}
+}
+
+func TestLocationAddresses(t *testing.T) {
+	event := jscal.NewEvent("visit@example.com", "Visit")
+	event.Start = jscal.NewLocalDateTime(time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC))
+	cathedral := jscal.NewLocation("Cathedral")
+	cathedral.Coordinates = jscal.String("geo:48.2085,16.3731")
+	cathedral.Address = jscal.NewAddress("Stephansplatz 3", "Wien", "1010", "AT")
+	event.AddLocation("a", cathedral)
+
+	apple, err := (&Converter{Compatibility: CompatibilityApple}).Format(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(apple), "X-ADDRESS=Stephansplatz 3^n1010 Wien") {
+		t.Errorf("Expected X-ADDRESS in output:\n%s", apple)
+	}
+	parsed, err := New().Parse(apple)
+	if err != nil {
+		t.Fatalf("Parse of output failed: %v", err)
+	}
+	if address := parsed.Locations["1"].Address; address.GetFull() != "Stephansplatz 3\n1010 Wien\nAT" {
+		t.Errorf("Expected the address as full text, got %+v", address)
+	}
+
+	google, err := (&Converter{Compatibility: CompatibilityGoogle}).Format(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(google), "LOCATION:Cathedral\\, Stephansplatz 3\\, 1010 Wien\\, AT") {
+		t.Errorf("Expected the address in LOCATION:\n%s", google)
+	}
+
+	// Without a name the address is the LOCATION in every mode
+	cathedral.Name = nil
+	standard, err := New().Format(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(standard), "LOCATION:Stephansplatz 3\\, 1010 Wien\\, AT") {
+		t.Errorf("Expected the address in LOCATION:\n%s", standard)
+	}
+
+	// Apple Calendar escapes the newlines in X-ADDRESS
+	icalData := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//Test//Test//EN\r\nBEGIN:VEVENT\r\n" +
+		"UID:apple@example.com\r\nDTSTART:20250310T090000Z\r\nLOCATION:Cathedral\r\n" +
+		"X-APPLE-STRUCTURED-LOCATION;VALUE=URI;X-ADDRESS=Stephansplatz 3\\\\n1010 Wien;X-TITLE=Cathedral:geo:48.2085,16.3731\r\n" +
+		"END:VEVENT\r\nEND:VCALENDAR\r\n"
+	fromApple, err := New().Parse([]byte(icalData))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if address := fromApple.Locations["1"].Address; address.GetFull() != "Stephansplatz 3\n1010 Wien" {
+		t.Errorf("Expected the Apple address lines, got %+v", address)
+	}
+}
+
+func TestDurationParsing(t *testing.T) {
+	tests := []struct {
+		duration string
+		expected string
+		wantErr  bool
+	}{
+		{"PT1H30M", "PT1H30M", false},
+		{"P2W", "P14D", false},
+		{"PT1.5H", "PT1H30M", false},
+		{"PT90M", "PT1H30M", false},
+		{"PT1X", "", true},
+	}
+
+	converter := New()
+	for _, tt := range tests {
+		icalData := strings.Join([]string{
+			"BEGIN:VCALENDAR",
+			"VERSION:2.0",
+			"PRODID:-//Test//Test//EN",
+			"BEGIN:VEVENT",
+			"UID:duration@example.com",
+			"DTSTART:20250310T090000Z",
+			"DURATION:" + tt.duration,
+			"END:VEVENT",
+			"END:VCALENDAR",
+		}, "\r\n")
+
+		event, err := converter.Parse([]byte(icalData))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("DURATION:%s: expected error %v, got %v", tt.duration, tt.wantErr, err)
+			continue
+		}
+		if !tt.wantErr && (event.Duration == nil || *event.Duration != tt.expected) {
+			t.Errorf("DURATION:%s: expected %s, got %v", tt.duration, tt.expected, event.Duration)
+		}
+	}
+}
+
+func TestAlarmConversion(t *testing.T) {
+	converter := New()
+
+	icalData := `BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//Test//Test//EN
+BEGIN:VEVENT
+UID:alarm-test@example.com
+SUMMARY:Flight
+DTSTART:20250310T090000Z
+DURATION:PT2H
+BEGIN:VALARM
+ACTION:DISPLAY
+TRIGGER:-PT15M
+END:VALARM
+BEGIN:VALARM
+ACTION:EMAIL
+TRIGGER;RELATED=END:PT5M
+END:VALARM
+BEGIN:VALARM
+ACTION:DISPLAY
+TRIGGER;VALUE=DATE-TIME:20250309T180000Z
+END:VALARM
+END:VEVENT
+END:VCALENDAR`
+
+	events, err := converter.ParseAll([]byte(icalData))
+	if err != nil {
+		t.Fatalf("Failed to convert event with alarms: %v", err)
+	}
+	event := events[0]
+	if len(event.Alerts) != 3 {
+		t.Fatalf("Expected 3 alerts, got %d", len(event.Alerts))
+	}
+
+	offset, ok := event.Alerts["alert1"].Trigger.(*jscal.OffsetTrigger)
+	if !ok || offset.Offset != "-PT15M" || offset.RelativeTo != nil {
+		t.Errorf("Expected -PT15M offset trigger, got %+v", event.Alerts["alert1"].Trigger)
+	}
+	end, ok := event.Alerts["alert2"].Trigger.(*jscal.OffsetTrigger)
+	if !ok || end.Offset != "PT5M" || end.RelativeTo == nil || *end.RelativeTo != jscal.RelativeToEnd {
+		t.Errorf("Expected PT5M trigger relative to end, got %+v", event.Alerts["alert2"].Trigger)
+	}
+	if action := event.Alerts["alert2"].Action; action == nil || *action != "email" {
+		t.Errorf("Expected email action, got %v", action)
+	}
+	when := time.Date(2025, 3, 9, 18, 0, 0, 0, time.UTC)
+	absolute, ok := event.Alerts["alert3"].Trigger.(*jscal.AbsoluteTrigger)
+	if !ok || !absolute.When.Time().Equal(when) {
+		t.Errorf("Expected absolute trigger at %s, got %+v", when, event.Alerts["alert3"].Trigger)
+	}
+
+	// Round trip
+	data, err := converter.Format(event)
+	if err != nil {
+		t.Fatalf("Failed to format event: %v", err)
+	}
+	output := string(data)
+	for _, want := range []string{"TRIGGER:-PT15M", "TRIGGER;RELATED=END:PT5M", "ACTION:EMAIL", "TRIGGER;VALUE=DATE-TIME:20250309T180000Z"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q:\n%s", want, output)
+		}
+	}
+
+	reparsed, err := converter.Parse(data)
+	if err != nil {
+		t.Fatalf("Failed to parse formatted event: %v", err)
+	}
+	if len(reparsed.Alerts) != 3 {
+		t.Errorf("Expected 3 alerts after round trip, got %d", len(reparsed.Alerts))
+	}
+}
+
+func TestRelatedToConversion(t *testing.T) {
+	converter := New()
+
+	icalData := `BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//Test//Test//EN
+BEGIN:VEVENT
+UID:occurrence@example.com
+SUMMARY:Planning (moved)
+DTSTART:20250310T090000Z
+RELATED-TO:series@example.com
+RELATED-TO;RELTYPE=CHILD:notes@example.com
+RELATED-TO;RELTYPE=SIBLING:review@example.com
+END:VEVENT
+END:VCALENDAR`
+
+	event, err := converter.Parse([]byte(icalData))
+	if err != nil {
+		t.Fatalf("Failed to convert event with relations: %v", err)
+	}
+
+	expected := map[string]string{
+		"series@example.com": jscal.RelationTypeParent,
+		"notes@example.com":  jscal.RelationTypeChild,
+		"review@example.com": jscal.RelationTypeSibling,
+	}
+	if len(event.RelatedTo) != len(expected) {
+		t.Fatalf("Expected %d relations, got %d", len(expected), len(event.RelatedTo))
+	}
+	for uid, relType := range expected {
+		relation := event.RelatedTo[uid]
+		if relation == nil || !relation.Relation[relType] {
+			t.Errorf("Expected %s relation to %s, got %+v", relType, uid, relation)
+		}
+	}
+
+	// Round trip
+	data, err := converter.Format(event)
+	if err != nil {
+		t.Fatalf("Failed to format event: %v", err)
+	}
+	output := string(data)
+	for _, want := range []string{
+		"RELATED-TO;RELTYPE=PARENT:series@example.com",
+		"RELATED-TO;RELTYPE=CHILD:notes@example.com",
+		"RELATED-TO;RELTYPE=SIBLING:review@example.com",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q:\n%s", want, output)
+		}
+	}
+
+	reparsed, err := converter.Parse(data)
+	if err != nil {
+		t.Fatalf("Failed to parse formatted event: %v", err)
+	}
+	if len(reparsed.RelatedTo) != len(expected) {
+		t.Errorf("Expected %d relations after round trip, got %d", len(expected), len(reparsed.RelatedTo))
+	}
+}
+
 func TestRoundTripConversion(t *testing.T) {
 	converter := New()
 
@@ -327,7 +1122,7 @@ func TestRoundTripConversion(t *testing.T) {
 		"SUMMARY:Round Trip Test",
 		"DTSTART:20250301T140000Z",
 		"DURATION:PT1H",
-		"CATEGORIES:Round Trip\\,Test", // Categories should be sorted (comma escaped in iCal)
+		"CATEGORIES:Round Trip\r\nCATEGORIES:Test", // Categories should be sorted, one property each
 		"ATTENDEE",
 		"test@example.com",
 		"END:VEVENT",
@@ -381,3 +1176,147 @@ func TestRoundTripConversion(t *testing.T) {
 		}
 	}
 }
+
+func TestParseAllLenient(t *testing.T) {
+	icalData := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Test//Test//EN",
+		"BEGIN:VEVENT",
+		"UID:good-1@example.com",
+		"SUMMARY:Good",
+		"DTSTART:20250301T140000Z",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"SUMMARY:No UID",
+		"DTSTART:20250302T140000Z",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:good-2@example.com",
+		"SUMMARY:Also good",
+		"DTSTART:20250303T140000Z",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	converter := New()
+	if _, err := converter.ParseAll([]byte(icalData)); err == nil {
+		t.Error("Expected ParseAll to fail on the event without UID")
+	}
+
+	events, errs, err := convert.ParseAllLenient(converter, []byte(icalData))
+	if err != nil {
+		t.Fatalf("ParseAllLenient failed: %v", err)
+	}
+	if len(events) != 2 || events[0].UID != "good-1@example.com" || events[1].UID != "good-2@example.com" {
+		t.Errorf("Expected the two good events, got %d", len(events))
+	}
+	if len(errs) != 1 || errs[0].Index != 1 || errs[0].Line != 9 {
+		t.Fatalf("Expected an error for item 1 on line 9, got %v", errs)
+	}
+	if !strings.Contains(errs[0].Error(), "missing UID") {
+		t.Errorf("Unexpected error %v", errs[0])
+	}
+
+	if _, _, err := converter.ParseAllLenient([]byte("not a calendar")); err == nil {
+		t.Error("Expected error for unparseable input")
+	}
+}
+
+func TestConverterContext(t *testing.T) {
+	data := []byte("BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//Test//EN\r\n" +
+		"BEGIN:VEVENT\r\nUID:ctx-1\r\nDTSTART:20250303T090000Z\r\nSUMMARY:Review\r\nEND:VEVENT\r\n" +
+		"END:VCALENDAR\r\n")
+	c := New()
+
+	events, err := c.ParseAllContext(context.Background(), data)
+	if err != nil || len(events) != 1 {
+		t.Fatalf("Expected 1 event, got %d and %v", len(events), err)
+	}
+	if _, err := c.FormatAllContext(context.Background(), events); err != nil {
+		t.Errorf("FormatAllContext failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.ParseAllContext(ctx, data); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if _, _, err := c.ParseAllLenientContext(ctx, data); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled from lenient parsing, got %v", err)
+	}
+	if _, err := c.FormatAllContext(ctx, events); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled from formatting, got %v", err)
+	}
+}
+
+func TestDtstampClock(t *testing.T) {
+	defer jscal.SetClock(jscal.FixedClock(time.Date(2025, 3, 1, 10, 0, 0, 0, time.FixedZone("CET", 3600))))()
+
+	event := jscal.NewEvent("stamp-1", "Review")
+	data, err := New().Format(event)
+	if err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	if !strings.Contains(string(data), "DTSTAMP:20250301T090000Z") {
+		t.Errorf("Expected the DTSTAMP of the injected clock in UTC, got %s", data)
+	}
+}
+
+func TestConversionError(t *testing.T) {
+	data := []byte("BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//Test//EN\r\n" +
+		"BEGIN:VEVENT\r\nUID:good\r\nDTSTART:20250303T090000Z\r\nSUMMARY:Review\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nUID:bad\r\nDTSTART:20250303T090000Z\r\nDURATION:soon\r\nEND:VEVENT\r\n" +
+		"END:VCALENDAR\r\n")
+
+	_, err := New().ParseAll(data)
+	var convErr *convert.ConversionError
+	if !errors.As(err, &convErr) {
+		t.Fatalf("Expected a ConversionError, got %v", err)
+	}
+	if convErr.UID != "bad" || convErr.Line != 9 || convErr.Item != "event" {
+		t.Errorf("Expected event bad on line 9, got %+v", convErr)
+	}
+
+	event := jscal.NewEvent("overrides", "Standup")
+	event.RecurrenceOverrides = map[string]jscal.PatchObject{"tomorrow": {"title": "Later"}}
+	if _, err := New().Format(event); !errors.As(err, &convErr) || convErr.UID != "overrides" {
+		t.Errorf("Expected a ConversionError for overrides when formatting, got %v", err)
+	}
+}
+
+func TestCharset(t *testing.T) {
+	ics := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//Test//EN\r\nBEGIN:VEVENT\r\nUID:cafe@example.com\r\n" +
+		"DTSTART:20250301T090000Z\r\nSUMMARY:Caf\xE9 \x93Zum L\xF6wen\x94\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+	utf16 := []byte{0xFF, 0xFE}
+	for _, r := range "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VEVENT\r\nUID:u\r\nDTSTART:20250301T090000Z\r\nSUMMARY:Café\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n" {
+		utf16 = append(utf16, byte(r), byte(r>>8))
+	}
+
+	tests := []struct {
+		name    string
+		charset string
+		data    []byte
+		title   string
+		wantErr bool
+	}{
+		{"detected windows-1252", "", []byte(ics), "Café “Zum Löwen”", false},
+		{"explicit latin1", "ISO-8859-1", []byte(ics), "Café \u0093Zum Löwen\u0094", false},
+		{"bom", "", append([]byte("\xEF\xBB\xBF"), strings.ReplaceAll(ics, "Caf\xE9 \x93Zum L\xF6wen\x94", "Café")...), "Café", false},
+		{"utf-16", "", utf16, "Café", false},
+		{"unknown charset", "ebcdic", []byte(ics), "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converter := &Converter{Charset: tt.charset}
+			events, err := converter.ParseAll(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && (len(events) != 1 || events[0].GetTitle() != tt.title) {
+				t.Errorf("Expected title %q, got %+v", tt.title, events)
+			}
+		})
+	}
+}
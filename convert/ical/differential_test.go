@@ -0,0 +1,225 @@
+package ical
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/airtrafik/jscal"
+)
+
+// differentialDir holds iCalendar inputs paired with golden files recording
+// how a reference implementation interprets them (see README.md there)
+const differentialDir = "testdata/differential"
+
+// goldenFile is the reference implementation's view of one .ics input
+type goldenFile struct {
+	Oracle string        `json:"oracle"` // Reference implementation and version
+	Events []goldenEvent `json:"events"`
+}
+
+// goldenEvent holds the semantic fields of one event. Fields jscal is known
+// to interpret differently are listed in KnownDifferences with a reason.
+type goldenEvent struct {
+	UID              string            `json:"uid"`
+	Fields           map[string]string `json:"fields"`
+	KnownDifferences map[string]string `json:"knownDifferences,omitempty"`
+}
+
+// semanticFields flattens the parts of an event whose interpretation varies
+// between implementations into comparable strings
+func semanticFields(event *jscal.Event) map[string]string {
+	fields := make(map[string]string)
+	if event.Title != nil {
+		fields["title"] = *event.Title
+	}
+	if event.Start != nil {
+		fields["start"] = event.Start.String()
+	}
+	if event.TimeZone != nil {
+		fields["timeZone"] = *event.TimeZone
+	}
+	if event.Duration != nil {
+		fields["duration"] = *event.Duration
+	}
+	if event.ShowWithoutTime != nil && *event.ShowWithoutTime {
+		fields["showWithoutTime"] = "true"
+	}
+
+	for i, rule := range event.RecurrenceRules {
+		prefix := fmt.Sprintf("recurrenceRules[%d].", i)
+		fields[prefix+"frequency"] = rule.Frequency
+		if rule.Interval != nil {
+			fields[prefix+"interval"] = strconv.Itoa(*rule.Interval)
+		}
+		if rule.Count != nil {
+			fields[prefix+"count"] = strconv.Itoa(*rule.Count)
+		}
+		if rule.Until != nil {
+			fields[prefix+"until"] = rule.Until.String()
+		}
+		if len(rule.ByDay) > 0 {
+			days := make([]string, 0, len(rule.ByDay))
+			for _, nday := range rule.ByDay {
+				day := nday.Day
+				if nday.NthOfPeriod != nil {
+					day = strconv.Itoa(*nday.NthOfPeriod) + day
+				}
+				days = append(days, day)
+			}
+			fields[prefix+"byDay"] = strings.Join(days, ",")
+		}
+		if len(rule.ByMonthDay) > 0 {
+			days := make([]string, 0, len(rule.ByMonthDay))
+			for _, day := range rule.ByMonthDay {
+				days = append(days, strconv.Itoa(day))
+			}
+			fields[prefix+"byMonthDay"] = strings.Join(days, ",")
+		}
+	}
+
+	return fields
+}
+
+// diffFields compares jscal's interpretation against the reference. Known
+// differences are returned separately so they don't fail the test, and any
+// known difference that no longer occurs is reported as fixed.
+func diffFields(got map[string]string, want goldenEvent) (mismatches, known, fixed []string) {
+	keys := make(map[string]bool)
+	for k := range got {
+		keys[k] = true
+	}
+	for k := range want.Fields {
+		keys[k] = true
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	for _, k := range sorted {
+		reason, isKnown := want.KnownDifferences[k]
+		if got[k] == want.Fields[k] {
+			if isKnown {
+				fixed = append(fixed, k)
+			}
+			continue
+		}
+		diff := fmt.Sprintf("%s: jscal %q, reference %q", k, got[k], want.Fields[k])
+		if isKnown {
+			known = append(known, diff+" ("+reason+")")
+		} else {
+			mismatches = append(mismatches, diff)
+		}
+	}
+	return mismatches, known, fixed
+}
+
+func TestDifferentialAgainstReference(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join(differentialDir, "*.ics"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inputs) == 0 {
+		t.Fatal("No differential test inputs found")
+	}
+
+	for _, input := range inputs {
+		name := strings.TrimSuffix(filepath.Base(input), ".ics")
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			goldenData, err := os.ReadFile(strings.TrimSuffix(input, ".ics") + ".golden.json")
+			if err != nil {
+				t.Fatalf("Missing golden file: %v", err)
+			}
+			var golden goldenFile
+			if err := json.Unmarshal(goldenData, &golden); err != nil {
+				t.Fatalf("Invalid golden file: %v", err)
+			}
+
+			events, err := New().ParseAll(data)
+			if err != nil {
+				t.Fatalf("Failed to parse: %v", err)
+			}
+
+			byUID := make(map[string]*jscal.Event, len(events))
+			for _, event := range events {
+				byUID[event.UID] = event
+			}
+			if len(events) != len(golden.Events) {
+				t.Errorf("Expected %d events (per %s), got %d", len(golden.Events), golden.Oracle, len(events))
+			}
+
+			for _, want := range golden.Events {
+				event, ok := byUID[want.UID]
+				if !ok {
+					t.Errorf("%s: event missing from jscal output", want.UID)
+					continue
+				}
+
+				mismatches, known, fixed := diffFields(semanticFields(event), want)
+				for _, m := range mismatches {
+					t.Errorf("%s: semantic mismatch with %s: %s", want.UID, golden.Oracle, m)
+				}
+				for _, k := range known {
+					t.Logf("%s: known difference: %s", want.UID, k)
+				}
+				for _, f := range fixed {
+					t.Errorf("%s: known difference in %s no longer occurs, remove it from the golden file", want.UID, f)
+				}
+			}
+		})
+	}
+}
+
+// FuzzParseAll feeds arbitrary input through the parser and checks that any
+// events it produces survive a Format/Parse round trip. The seed corpus is
+// every .ics file under testdata.
+func FuzzParseAll(f *testing.F) {
+	for _, pattern := range []string{
+		filepath.Join(differentialDir, "*.ics"),
+		filepath.Join("..", "..", "testdata", "ical", "*.ics"),
+	} {
+		files, _ := filepath.Glob(pattern)
+		for _, file := range files {
+			if data, err := os.ReadFile(file); err == nil {
+				f.Add(data)
+			}
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		converter := New()
+		events, err := converter.ParseAll(data)
+		if err != nil || len(events) == 0 {
+			return
+		}
+
+		formatted, err := converter.FormatAll(events)
+		if err != nil {
+			return
+		}
+		reparsed, err := converter.ParseAll(formatted)
+		if err != nil {
+			t.Fatalf("Failed to reparse formatted output: %v\n%s", err, formatted)
+		}
+		if len(reparsed) != len(events) {
+			t.Fatalf("Expected %d events after round trip, got %d", len(events), len(reparsed))
+		}
+		for i := range events {
+			if reparsed[i].UID != events[i].UID {
+				t.Errorf("UID changed in round trip: %q -> %q", events[i].UID, reparsed[i].UID)
+			}
+		}
+	})
+}
@@ -0,0 +1,184 @@
+package ical
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/airtrafik/jscal"
+	"github.com/airtrafik/jscal/convert"
+	ics "github.com/arran4/golang-ical"
+)
+
+// utcLayout is the format of UTC date-times, the only form FREEBUSY
+// periods may use (RFC 5545 Section 3.8.2.6)
+const utcLayout = "20060102T150405Z"
+
+// fbTypes maps FBTYPE parameter values to free/busy statuses. FREE periods
+// have no status, as only busy time is kept.
+var fbTypes = map[string]string{
+	"BUSY":             jscal.FreeBusyBusy,
+	"BUSY-TENTATIVE":   jscal.FreeBusyTentative,
+	"BUSY-UNAVAILABLE": jscal.FreeBusyUnavailable,
+}
+
+// ParseFreeBusy converts the VFREEBUSY components of iCalendar data, such
+// as the availability published by a CalDAV or Exchange server or a reply
+// to a free/busy request (RFC 5546 Section 3.3), to free/busy results.
+// Unknown FBTYPEs are read as BUSY, as RFC 5545 asks.
+func (c *Converter) ParseFreeBusy(data []byte) ([]*jscal.FreeBusy, error) {
+	data, err := c.prepare(data)
+	if err != nil {
+		return nil, err
+	}
+	cal, err := ics.ParseCalendar(strings.NewReader(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse iCalendar: %w", err)
+	}
+
+	var results []*jscal.FreeBusy
+	for _, component := range cal.Components {
+		vbusy, ok := component.(*ics.VBusy)
+		if !ok {
+			continue
+		}
+		fb, err := convertFreeBusy(vbusy)
+		if err != nil {
+			return nil, &convert.ConversionError{Item: "free/busy", UID: vbusy.Id(), Err: err}
+		}
+		results = append(results, fb)
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no free/busy components found in iCalendar data")
+	}
+	return results, nil
+}
+
+// convertFreeBusy converts a VFREEBUSY component
+func convertFreeBusy(vbusy *ics.VBusy) (*jscal.FreeBusy, error) {
+	fb := &jscal.FreeBusy{UID: vbusy.Id()}
+	if prop := vbusy.GetProperty(ics.ComponentPropertyDtStart); prop != nil {
+		fb.Start, _, _ = parseICalDateTime(prop)
+	}
+	if prop := vbusy.GetProperty(ics.ComponentPropertyDtEnd); prop != nil {
+		fb.End, _, _ = parseICalDateTime(prop)
+	}
+	if prop := vbusy.GetProperty(ics.ComponentPropertyOrganizer); prop != nil {
+		fb.Organizer = prop.Value
+	}
+	if prop := vbusy.GetProperty(ics.ComponentPropertyAttendee); prop != nil {
+		fb.Attendee = prop.Value
+	}
+
+	for i := range vbusy.Properties {
+		prop := &vbusy.Properties[i]
+		if prop.IANAToken != string(ics.ComponentPropertyFreebusy) {
+			continue
+		}
+		status := jscal.FreeBusyBusy
+		if fbType := prop.ICalParameters[string(ics.ParameterFbtype)]; len(fbType) > 0 {
+			if strings.EqualFold(fbType[0], "FREE") {
+				continue
+			}
+			if mapped, ok := fbTypes[strings.ToUpper(fbType[0])]; ok {
+				status = mapped
+			}
+		}
+		for _, value := range strings.Split(prop.Value, ",") {
+			period, err := parsePeriod(strings.TrimSpace(value))
+			if err != nil {
+				return nil, err
+			}
+			period.Status = status
+			fb.Busy = append(fb.Busy, period)
+		}
+	}
+
+	sort.SliceStable(fb.Busy, func(i, j int) bool { return fb.Busy[i].Start.Before(fb.Busy[j].Start) })
+	return fb, nil
+}
+
+// parsePeriod parses a period of time, "start/end" or "start/duration"
+func parsePeriod(value string) (jscal.BusyPeriod, error) {
+	startValue, endValue, ok := strings.Cut(value, "/")
+	if !ok {
+		return jscal.BusyPeriod{}, fmt.Errorf("invalid FREEBUSY period %q", value)
+	}
+	start, err := time.Parse(utcLayout, startValue)
+	if err != nil {
+		return jscal.BusyPeriod{}, fmt.Errorf("invalid FREEBUSY period %q: start must be a UTC date-time", value)
+	}
+
+	var end time.Time
+	if strings.HasPrefix(endValue, "P") || strings.HasPrefix(endValue, "+P") {
+		duration, err := jscal.ParseDuration(strings.TrimPrefix(endValue, "+"))
+		if err != nil {
+			return jscal.BusyPeriod{}, fmt.Errorf("invalid FREEBUSY period %q: %w", value, err)
+		}
+		end = start.Add(duration)
+	} else if end, err = time.Parse(utcLayout, endValue); err != nil {
+		return jscal.BusyPeriod{}, fmt.Errorf("invalid FREEBUSY period %q: end must be a UTC date-time", value)
+	}
+	if !end.After(start) {
+		return jscal.BusyPeriod{}, fmt.Errorf("invalid FREEBUSY period %q: end must be after start", value)
+	}
+	return jscal.BusyPeriod{Start: start, End: end}, nil
+}
+
+// FormatFreeBusy converts free/busy results to VFREEBUSY components, one
+// FREEBUSY property per busy period. To answer a free/busy request, set
+// opts.Calendar.Method to "REPLY" and the results' Organizer and Attendee.
+// Results without a UID get a random one.
+func (c *Converter) FormatFreeBusy(results []*jscal.FreeBusy, opts FormatOptions) ([]byte, error) {
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no free/busy results to convert")
+	}
+
+	cal := ics.NewCalendar()
+	setCalendarProperties(cal, opts)
+	for _, fb := range results {
+		if fb == nil {
+			continue
+		}
+		cal.Components = append(cal.Components, formatFreeBusy(fb))
+	}
+	return foldLines(serialize(cal)), nil
+}
+
+// formatFreeBusy converts a free/busy result to a VFREEBUSY component
+func formatFreeBusy(fb *jscal.FreeBusy) *ics.VBusy {
+	uid := fb.UID
+	if uid == "" {
+		uid = jscal.NewUID()
+	}
+
+	vbusy := &ics.VBusy{}
+	vbusy.SetProperty(ics.ComponentPropertyUniqueId, uid)
+	vbusy.SetProperty(ics.ComponentPropertyDtstamp, jscal.Now().UTC().Format(utcLayout))
+	if !fb.Start.IsZero() {
+		vbusy.SetProperty(ics.ComponentPropertyDtStart, fb.Start.UTC().Format(utcLayout))
+	}
+	if !fb.End.IsZero() {
+		vbusy.SetProperty(ics.ComponentPropertyDtEnd, fb.End.UTC().Format(utcLayout))
+	}
+	if fb.Organizer != "" {
+		vbusy.SetProperty(ics.ComponentPropertyOrganizer, fb.Organizer)
+	}
+	if fb.Attendee != "" {
+		vbusy.SetProperty(ics.ComponentPropertyAttendee, fb.Attendee)
+	}
+
+	for _, period := range fb.Busy {
+		fbType := "BUSY"
+		for name, status := range fbTypes {
+			if status == period.Status {
+				fbType = name
+			}
+		}
+		value := period.Start.UTC().Format(utcLayout) + "/" + period.End.UTC().Format(utcLayout)
+		vbusy.AddProperty(ics.ComponentPropertyFreebusy, value,
+			&ics.KeyValues{Key: string(ics.ParameterFbtype), Value: []string{fbType}})
+	}
+	return vbusy
+}
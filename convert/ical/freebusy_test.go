@@ -0,0 +1,132 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/airtrafik/jscal"
+)
+
+func TestParseFreeBusy(t *testing.T) {
+	data := `BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//Example Corp.//CalDAV Server//EN
+METHOD:REPLY
+BEGIN:VFREEBUSY
+UID:fb-1@example.com
+DTSTAMP:20250301T080000Z
+ORGANIZER:mailto:jane@example.com
+ATTENDEE:mailto:john@example.com
+DTSTART:20250303T000000Z
+DTEND:20250304T000000Z
+FREEBUSY:20250303T140000Z/PT1H,20250303T090000Z/20250303T100000Z
+FREEBUSY;FBTYPE=BUSY-TENTATIVE:20250303T160000Z/20250303T163000Z
+FREEBUSY;FBTYPE=FREE:20250303T170000Z/20250303T180000Z
+FREEBUSY;FBTYPE=X-OUT-OF-OFFICE:20250303T200000Z/PT2H
+END:VFREEBUSY
+END:VCALENDAR`
+
+	results, err := New().ParseFreeBusy([]byte(data))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+	fb := results[0]
+	if fb.UID != "fb-1@example.com" || fb.Organizer != "mailto:jane@example.com" || fb.Attendee != "mailto:john@example.com" {
+		t.Errorf("Unexpected identity: %+v", fb)
+	}
+	if want := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC); !fb.Start.Equal(want) {
+		t.Errorf("Expected start %v, got %v", want, fb.Start)
+	}
+
+	utc := func(hour, minute int) time.Time { return time.Date(2025, 3, 3, hour, minute, 0, 0, time.UTC) }
+	want := []jscal.BusyPeriod{
+		{Start: utc(9, 0), End: utc(10, 0), Status: jscal.FreeBusyBusy},
+		{Start: utc(14, 0), End: utc(15, 0), Status: jscal.FreeBusyBusy},
+		{Start: utc(16, 0), End: utc(16, 30), Status: jscal.FreeBusyTentative},
+		{Start: utc(20, 0), End: utc(22, 0), Status: jscal.FreeBusyBusy},
+	}
+	if len(fb.Busy) != len(want) {
+		t.Fatalf("Expected %d busy periods, got %+v", len(want), fb.Busy)
+	}
+	for i, period := range fb.Busy {
+		if !period.Start.Equal(want[i].Start) || !period.End.Equal(want[i].End) || period.Status != want[i].Status {
+			t.Errorf("Expected period %d to be %+v, got %+v", i, want[i], period)
+		}
+	}
+}
+
+func TestParseFreeBusyErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"missing end", "FREEBUSY:20250303T140000Z"},
+		{"local time", "FREEBUSY:20250303T140000/20250303T150000Z"},
+		{"end before start", "FREEBUSY:20250303T140000Z/20250303T130000Z"},
+		{"bad duration", "FREEBUSY:20250303T140000Z/PXH"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:test\r\nBEGIN:VFREEBUSY\r\nUID:fb@example.com\r\n" +
+				tt.value + "\r\nEND:VFREEBUSY\r\nEND:VCALENDAR\r\n"
+			if _, err := New().ParseFreeBusy([]byte(data)); err == nil {
+				t.Errorf("Expected error for %s", tt.value)
+			}
+		})
+	}
+
+	if _, err := New().ParseFreeBusy([]byte(journalCalendar)); err == nil {
+		t.Errorf("Expected error for a calendar without VFREEBUSY")
+	}
+}
+
+func TestFormatFreeBusy(t *testing.T) {
+	utc := func(hour int) time.Time { return time.Date(2025, 3, 3, hour, 0, 0, 0, time.UTC) }
+	meeting := jscal.NewEvent("meeting@example.com", "Planning")
+	meeting.Start = jscal.NewLocalDateTime(utc(9))
+	meeting.TimeZone = jscal.String("UTC")
+	meeting.Duration = jscal.String("PT1H")
+
+	fb, err := jscal.Events{meeting}.FreeBusy(utc(0), utc(24), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fb.UID = "fb-reply@example.com"
+	fb.Organizer = "mailto:jane@example.com"
+	fb.Attendee = "mailto:john@example.com"
+	fb.Busy = append(fb.Busy, jscal.BusyPeriod{Start: utc(13), End: utc(14), Status: jscal.FreeBusyUnavailable})
+
+	converter := New()
+	output, err := converter.FormatFreeBusy([]*jscal.FreeBusy{fb}, FormatOptions{Calendar: &CalendarMetadata{Method: "REPLY"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		"METHOD:REPLY",
+		"BEGIN:VFREEBUSY",
+		"DTSTART:20250303T000000Z",
+		"FREEBUSY;FBTYPE=BUSY:20250303T090000Z/20250303T100000Z",
+		"FREEBUSY;FBTYPE=BUSY-UNAVAILABLE:20250303T130000Z/20250303T140000Z",
+	} {
+		if !strings.Contains(string(output), line+"\r\n") {
+			t.Errorf("Expected %s in output:\n%s", line, output)
+		}
+	}
+
+	parsed, err := converter.ParseFreeBusy(output)
+	if err != nil {
+		t.Fatalf("Parse of output failed: %v", err)
+	}
+	if len(parsed) != 1 || len(parsed[0].Busy) != 2 || parsed[0].UID != fb.UID || parsed[0].Attendee != fb.Attendee {
+		t.Errorf("Expected the result to round trip, got %+v", parsed)
+	}
+
+	if _, err := converter.FormatFreeBusy(nil, FormatOptions{}); err == nil {
+		t.Errorf("Expected error for no results")
+	}
+}
@@ -6,3 +6,5 @@ require (
 	github.com/airtrafik/jscal v0.2.1
 	github.com/arran4/golang-ical v0.3.2
 )
+
+replace github.com/airtrafik/jscal => ../..
@@ -0,0 +1,88 @@
+package ical
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/airtrafik/jscal"
+)
+
+// ParseCalendar converts iCalendar data to a Group holding its events, so
+// the calendar they came from is kept. The Group takes its identity from
+// the calendar: uid from UID, title from NAME or X-WR-CALNAME, color from
+// COLOR or X-APPLE-CALENDAR-COLOR, prodId from PRODID and source from
+// SOURCE. Calendars without a UID get one derived from their PRODID and
+// name, which stays the same across refreshes of the same calendar.
+func (c *Converter) ParseCalendar(data []byte) (*jscal.Group, error) {
+	events, metadata, err := c.ParseAllWithMetadata(data)
+	if err != nil {
+		return nil, err
+	}
+
+	group := jscal.NewGroup(calendarUID(metadata), "")
+	group.Title = optional(metadata.Name)
+	group.Description = optional(metadata.Description)
+	group.Color = optional(metadata.Color)
+	group.ProdId = optional(metadata.ProductID)
+	group.Source = optional(metadata.Source)
+	if metadata.Method != "" {
+		group.Method = jscal.String(strings.ToLower(metadata.Method))
+	}
+	for _, event := range events {
+		if err := group.AddEntry(event); err != nil {
+			return nil, err
+		}
+	}
+	return group, nil
+}
+
+// FormatGroup converts the events of a Group to iCalendar format, writing
+// the group's identity as calendar-level properties, the reverse of
+// ParseCalendar. The group's prodId is written as PRODID if set. Groups
+// holding tasks are rejected, as they can't be written as VEVENTs.
+func (c *Converter) FormatGroup(group *jscal.Group) ([]byte, error) {
+	if group == nil {
+		return nil, fmt.Errorf("group is nil")
+	}
+	if tasks := group.CountTasks(); tasks > 0 {
+		return nil, fmt.Errorf("group %s holds %d tasks, only events are supported", group.UID, tasks)
+	}
+
+	metadata := &CalendarMetadata{
+		UID:         group.UID,
+		Name:        deref(group.Title),
+		Description: deref(group.Description),
+		Color:       deref(group.Color),
+		Source:      deref(group.Source),
+		Method:      strings.ToUpper(deref(group.Method)),
+	}
+	return c.FormatAllWithOptions(group.GetEvents(), FormatOptions{
+		ProductID: deref(group.ProdId),
+		Calendar:  metadata,
+	})
+}
+
+// calendarUID returns the UID of a calendar, deriving one from its PRODID
+// and name if it has none
+func calendarUID(metadata *CalendarMetadata) string {
+	if metadata.UID != "" {
+		return metadata.UID
+	}
+	return jscal.HashUID(metadata.ProductID, metadata.Name)
+}
+
+// optional returns a pointer to s, or nil if s is empty
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// deref returns the string s points to, or "" if s is nil
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,96 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/airtrafik/jscal"
+)
+
+const groupTestCalendar = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//Apple Inc.//macOS 14.0//EN\r\n" +
+	"X-WR-CALNAME:Team\r\n" +
+	"X-APPLE-CALENDAR-COLOR:#FF2968FF\r\n" +
+	"METHOD:PUBLISH\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:one@example.com\r\n" +
+	"DTSTAMP:20250301T000000Z\r\n" +
+	"DTSTART:20250303T090000Z\r\n" +
+	"SUMMARY:Standup\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:two@example.com\r\n" +
+	"DTSTAMP:20250301T000000Z\r\n" +
+	"DTSTART:20250304T090000Z\r\n" +
+	"SUMMARY:Review\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseCalendar(t *testing.T) {
+	converter := New()
+	group, err := converter.ParseCalendar([]byte(groupTestCalendar))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if group.GetTitle() != "Team" || group.GetColor() != "#FF2968FF" {
+		t.Errorf("Expected title Team and color #FF2968FF, got %q and %q", group.GetTitle(), group.GetColor())
+	}
+	if group.GetProdId() != "-//Apple Inc.//macOS 14.0//EN" || group.GetMethod() != "publish" {
+		t.Errorf("Expected Apple prodId and method publish, got %q and %q", group.GetProdId(), group.GetMethod())
+	}
+	if group.CountEvents() != 2 || group.GetEntry("two@example.com") == nil {
+		t.Errorf("Expected two events, got %d", group.CountEvents())
+	}
+	if err := group.Validate(); err != nil {
+		t.Errorf("Expected a valid group, got %v", err)
+	}
+
+	again, err := converter.ParseCalendar([]byte(groupTestCalendar))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if group.UID == "" || again.UID != group.UID {
+		t.Errorf("Expected a stable derived uid, got %q and %q", group.UID, again.UID)
+	}
+}
+
+func TestFormatGroup(t *testing.T) {
+	converter := New()
+	group, err := converter.ParseCalendar([]byte(groupTestCalendar))
+	if err != nil {
+		t.Fatal(err)
+	}
+	group.SetSource("https://example.com/team.ics")
+
+	output, err := converter.FormatGroup(group)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		"PRODID:-//Apple Inc.//macOS 14.0//EN",
+		"X-WR-CALNAME:Team",
+		"X-APPLE-CALENDAR-COLOR:#FF2968FF",
+		"METHOD:PUBLISH",
+		"UID:" + group.UID,
+		"SOURCE;VALUE=URI:https://example.com/team.ics",
+	} {
+		if !strings.Contains(string(output), line+"\r\n") {
+			t.Errorf("Expected %s in output:\n%s", line, output)
+		}
+	}
+
+	reparsed, err := converter.ParseCalendar(output)
+	if err != nil {
+		t.Fatalf("ParseCalendar of output failed: %v", err)
+	}
+	if reparsed.UID != group.UID || reparsed.GetSource() != group.GetSource() || reparsed.CountEvents() != 2 {
+		t.Errorf("Expected the group to round trip, got %+v", reparsed)
+	}
+
+	_ = group.AddEntry(jscal.NewTask("task@example.com", "Task"))
+	if _, err := converter.FormatGroup(group); err == nil {
+		t.Error("Expected an error for a group holding tasks")
+	}
+}
@@ -0,0 +1,398 @@
+// Package icaltest measures how much of an iCalendar file survives a round
+// trip through JSCalendar, so integrations can check their exports (Outlook,
+// Apple Calendar, Google) and the converter can be tested for loss
+// systematically.
+//
+// Basic usage:
+//
+//	report, err := icaltest.RoundTrip(ical.New(), data)
+//	fmt.Printf("%.1f%% kept\n", 100*report.Fidelity())
+//	for _, loss := range report.Losses {
+//		fmt.Println(loss)
+//	}
+//
+// Every VEVENT (and VJOURNAL, if the converter converts them) is parsed to
+// an event, formatted back and compared property by property with the
+// original, by UID and RECURRENCE-ID. Values are compared, not parameters:
+//
+//   - date-times are compared as the instant they give, so a TZID time and
+//     its UTC equivalent match
+//   - DTEND and DURATION are compared as the end they give, under DTEND
+//   - list values (CATEGORIES, EXDATE, RDATE) are compared item by item
+//   - calendar addresses are compared ignoring case, recurrence rules
+//     ignoring the order of their parts
+//   - alarm properties are compared as "VALARM.<name>"
+//   - DTSTAMP, which is set when formatting, is ignored
+package icaltest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/airtrafik/jscal"
+	"github.com/airtrafik/jscal/convert/ical"
+	ics "github.com/arran4/golang-ical"
+)
+
+// Loss is a property value of the original that the round trip lost or
+// changed
+type Loss struct {
+	UID          string // UID of the component
+	RecurrenceID string // Normalized RECURRENCE-ID of overrides, "" otherwise
+	Property     string // Property name, e.g. "ATTENDEE" or "VALARM.TRIGGER"
+	Original     string // The value that was lost
+	RoundTrip    string // A different value of the property after the round trip, "" if none is left
+}
+
+// String describes the loss, e.g. `uid-1: X-MICROSOFT-CDO-BUSYSTATUS "BUSY" lost`
+func (l Loss) String() string {
+	id := l.UID
+	if l.RecurrenceID != "" {
+		id += " (" + l.RecurrenceID + ")"
+	}
+	if l.RoundTrip != "" {
+		return fmt.Sprintf("%s: %s %q changed to %q", id, l.Property, l.Original, l.RoundTrip)
+	}
+	return fmt.Sprintf("%s: %s %q lost", id, l.Property, l.Original)
+}
+
+// Report is the result of a round trip
+type Report struct {
+	Name       string // File name, set by RoundTripDir
+	Components int    // Components compared
+	Properties int    // Property values compared
+	Losses     []Loss
+}
+
+// Fidelity returns the share of property values kept, from 0 to 1. A
+// report without properties has a fidelity of 1.
+func (r *Report) Fidelity() float64 {
+	if r.Properties == 0 {
+		return 1
+	}
+	return 1 - float64(len(r.Losses))/float64(r.Properties)
+}
+
+// LossesByProperty counts the losses of each property
+func (r *Report) LossesByProperty() map[string]int {
+	counts := make(map[string]int)
+	for _, loss := range r.Losses {
+		counts[loss.Property]++
+	}
+	return counts
+}
+
+// String summarizes the report, e.g. "outlook.ics: 3 components, 42
+// properties, 97.6% kept (X-ALT-DESC 1)"
+func (r *Report) String() string {
+	var b strings.Builder
+	if r.Name != "" {
+		b.WriteString(r.Name + ": ")
+	}
+	fmt.Fprintf(&b, "%d components, %d properties, %.1f%% kept", r.Components, r.Properties, 100*r.Fidelity())
+	if counts := r.LossesByProperty(); len(counts) > 0 {
+		names := make([]string, 0, len(counts))
+		for name := range counts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for i, name := range names {
+			names[i] = fmt.Sprintf("%s %d", name, counts[name])
+		}
+		fmt.Fprintf(&b, " (%s)", strings.Join(names, ", "))
+	}
+	return b.String()
+}
+
+// Merge combines reports, e.g. of a whole corpus, into one
+func Merge(reports []*Report) *Report {
+	merged := &Report{}
+	for _, r := range reports {
+		merged.Components += r.Components
+		merged.Properties += r.Properties
+		merged.Losses = append(merged.Losses, r.Losses...)
+	}
+	return merged
+}
+
+// RoundTrip converts iCalendar data to JSCalendar with c and back, and
+// reports the property values lost on the way
+func RoundTrip(c *ical.Converter, data []byte) (*Report, error) {
+	original, err := ics.ParseCalendar(strings.NewReader(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse iCalendar: %w", err)
+	}
+	events, err := c.ParseAll(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert to JSCalendar: %w", err)
+	}
+	formatted, err := c.FormatAll(events)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert back to iCalendar: %w", err)
+	}
+	roundTrip, err := ics.ParseCalendar(strings.NewReader(string(formatted)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse round trip output: %w", err)
+	}
+
+	after := make(map[string]properties)
+	for _, comp := range components(roundTrip, c.Journals == ical.JournalAsEvent) {
+		after[comp.key()] = comp.properties
+	}
+
+	report := &Report{}
+	for _, comp := range components(original, c.Journals == ical.JournalAsEvent) {
+		report.Components++
+		kept := after[comp.key()]
+		for _, name := range sortedNames(comp.properties) {
+			remaining := append([]string(nil), kept[name]...)
+			var unmatched []string
+			for _, value := range comp.properties[name] {
+				report.Properties++
+				if i := indexOf(remaining, value); i >= 0 {
+					remaining = append(remaining[:i], remaining[i+1:]...)
+				} else {
+					unmatched = append(unmatched, value)
+				}
+			}
+			for i, value := range unmatched {
+				loss := Loss{UID: comp.uid, RecurrenceID: comp.recurrenceID, Property: name, Original: value}
+				if i < len(remaining) {
+					loss.RoundTrip = remaining[i]
+				}
+				report.Losses = append(report.Losses, loss)
+			}
+		}
+	}
+	return report, nil
+}
+
+// RoundTripDir runs RoundTrip on every .ics file under dir. Files that
+// fail to convert are returned as errors by file name, and left out of the
+// reports.
+func RoundTripDir(c *ical.Converter, dir string) ([]*Report, map[string]error, error) {
+	var reports []*Report
+	failures := make(map[string]error)
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".ics") {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		name, _ := filepath.Rel(dir, path)
+		report, err := RoundTrip(c, data)
+		if err != nil {
+			failures[name] = err
+			return nil
+		}
+		report.Name = name
+		reports = append(reports, report)
+		return nil
+	})
+	return reports, failures, err
+}
+
+// Check fails the test if the round trip of data loses any property other
+// than the allowed ones, e.g. Check(t, ical.New(), data, "X-ALT-DESC")
+func Check(t testing.TB, c *ical.Converter, data []byte, allowed ...string) {
+	t.Helper()
+	report, err := RoundTrip(c, data)
+	if err != nil {
+		t.Fatalf("Round trip failed: %v", err)
+	}
+	for _, loss := range report.Losses {
+		if indexOf(allowed, loss.Property) < 0 {
+			t.Errorf("Round trip loss: %s", loss)
+		}
+	}
+}
+
+// properties are the normalized values of a component by property name
+type properties map[string][]string
+
+// component is a VEVENT or VJOURNAL with its normalized properties
+type component struct {
+	uid          string
+	recurrenceID string
+	properties   properties
+}
+
+// key identifies the component in a calendar
+func (c component) key() string {
+	return c.uid + "\x00" + c.recurrenceID
+}
+
+// components returns the VEVENTs of a calendar, and its VJOURNALs if
+// journals is set
+func components(cal *ics.Calendar, journals bool) []component {
+	var result []component
+	for _, comp := range cal.Components {
+		var base *ics.ComponentBase
+		switch comp := comp.(type) {
+		case *ics.VEvent:
+			base = &comp.ComponentBase
+		case *ics.VJournal:
+			if !journals {
+				continue
+			}
+			base = &comp.ComponentBase
+		default:
+			continue
+		}
+
+		c := component{uid: base.Id(), properties: normalize(base.Properties, "")}
+		if ids := c.properties["RECURRENCE-ID"]; len(ids) > 0 {
+			c.recurrenceID = ids[0]
+		}
+		for _, sub := range base.Components {
+			if alarm, ok := sub.(*ics.VAlarm); ok {
+				for name, values := range normalize(alarm.Properties, "VALARM.") {
+					c.properties[name] = append(c.properties[name], values...)
+				}
+			}
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+// listProperties hold comma separated lists of values
+var listProperties = map[string]bool{"CATEGORIES": true, "EXDATE": true, "RDATE": true, "RESOURCES": true}
+
+// dateTimeProperties hold a date or date-time
+var dateTimeProperties = map[string]bool{
+	"DTSTART": true, "DTEND": true, "DUE": true, "RECURRENCE-ID": true, "EXDATE": true, "RDATE": true,
+	"CREATED": true, "LAST-MODIFIED": true,
+}
+
+// normalize returns the comparable values of properties, see the package
+// documentation
+func normalize(props []ics.IANAProperty, prefix string) properties {
+	result := make(properties)
+	var start, end time.Time
+	var duration string
+	for _, prop := range props {
+		name := strings.ToUpper(prop.IANAToken)
+		switch name {
+		case "DTSTAMP":
+			continue
+		case "DURATION":
+			if prefix == "" {
+				duration = prop.Value
+				continue
+			}
+		}
+
+		values := []string{prop.Value}
+		if listProperties[name] {
+			values = strings.Split(prop.Value, ",")
+		}
+		for _, value := range values {
+			value = strings.TrimSpace(value)
+			switch {
+			case dateTimeProperties[name]:
+				t, normalized := dateTime(prop, value)
+				if name == "DTSTART" {
+					start = t
+				}
+				if name == "DTEND" && prefix == "" {
+					end = t
+					continue
+				}
+				value = normalized
+			case name == "ORGANIZER" || name == "ATTENDEE":
+				value = strings.ToLower(value)
+			case name == "RRULE" || name == "EXRULE":
+				parts := strings.Split(strings.ToUpper(value), ";")
+				sort.Strings(parts)
+				value = strings.Join(parts, ";")
+			}
+			result[prefix+name] = append(result[prefix+name], value)
+		}
+	}
+
+	// The end an event's DTEND or DURATION gives
+	if prefix == "" && !start.IsZero() {
+		if end.IsZero() && duration != "" {
+			if d, err := jscal.ParseDuration(strings.TrimPrefix(duration, "+")); err == nil {
+				end = start.Add(d)
+			}
+		}
+		if !end.IsZero() {
+			result["DTEND"] = []string{end.UTC().Format("20060102T150405Z")}
+		}
+	}
+	return result
+}
+
+// dateTime parses a date or date-time value with its TZID and VALUE
+// parameters. It returns the time, zero if it can't be read, and the
+// normalized value: UTC for date-times in a known zone, the value itself
+// for dates, floating times and unknown zones.
+func dateTime(prop ics.IANAProperty, value string) (time.Time, string) {
+	if isDate(prop, value) {
+		t, _ := time.Parse("20060102", value)
+		return t, value
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		if err != nil {
+			return time.Time{}, value
+		}
+		return t, t.Format("20060102T150405Z")
+	}
+	loc := time.UTC
+	if tzid := prop.ICalParameters["TZID"]; len(tzid) > 0 {
+		zone, err := time.LoadLocation(strings.Trim(tzid[0], `"`))
+		if err != nil {
+			return time.Time{}, tzid[0] + ":" + value
+		}
+		loc = zone
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	if err != nil {
+		return time.Time{}, value
+	}
+	if len(prop.ICalParameters["TZID"]) == 0 {
+		return t, value
+	}
+	return t, t.UTC().Format("20060102T150405Z")
+}
+
+// isDate reports whether a value is a DATE rather than a DATE-TIME
+func isDate(prop ics.IANAProperty, value string) bool {
+	if kind := prop.ICalParameters["VALUE"]; len(kind) > 0 {
+		return strings.EqualFold(kind[0], "DATE")
+	}
+	return len(value) == 8
+}
+
+// sortedNames returns the property names in order
+func sortedNames(props properties) []string {
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// indexOf returns the index of value in values, or -1
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,107 @@
+package icaltest
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/airtrafik/jscal/convert/ical"
+)
+
+const lossyCalendar = `BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//Test//EN
+BEGIN:VEVENT
+UID:lossy-1
+DTSTAMP:20250101T000000Z
+DTSTART;TZID=Europe/Berlin:20250310T090000
+DTEND;TZID=Europe/Berlin:20250310T100000
+SUMMARY:Planning
+CATEGORIES:work,planning
+ORGANIZER;CN=Olga:mailto:olga@example.com
+X-MICROSOFT-CDO-BUSYSTATUS:BUSY
+BEGIN:VALARM
+ACTION:DISPLAY
+TRIGGER:-PT15M
+DESCRIPTION:Reminder
+END:VALARM
+END:VEVENT
+END:VCALENDAR
+`
+
+func TestRoundTrip(t *testing.T) {
+	report, err := RoundTrip(ical.New(), []byte(strings.ReplaceAll(lossyCalendar, "\n", "\r\n")))
+	if err != nil {
+		t.Fatalf("RoundTrip failed: %v", err)
+	}
+	if report.Components != 1 {
+		t.Errorf("Expected 1 component, got %d", report.Components)
+	}
+	if report.Properties != 11 {
+		t.Errorf("Expected 11 property values, got %d", report.Properties)
+	}
+
+	counts := report.LossesByProperty()
+	if len(report.Losses) != 2 || counts["X-MICROSOFT-CDO-BUSYSTATUS"] != 1 || counts["VALARM.DESCRIPTION"] != 1 {
+		t.Fatalf("Expected the X- property and the alarm description to be lost, got %v", report.Losses)
+	}
+	for _, loss := range report.Losses {
+		switch loss.Property {
+		case "X-MICROSOFT-CDO-BUSYSTATUS":
+			if loss.Original != "BUSY" || loss.RoundTrip != "" {
+				t.Errorf("Expected BUSY to be lost, got %+v", loss)
+			}
+			if loss.String() != `lossy-1: X-MICROSOFT-CDO-BUSYSTATUS "BUSY" lost` {
+				t.Errorf("Unexpected description %q", loss.String())
+			}
+		case "VALARM.DESCRIPTION":
+			if loss.Original != "Reminder" || loss.RoundTrip != "Planning" {
+				t.Errorf("Expected Reminder to change to Planning, got %+v", loss)
+			}
+		}
+	}
+
+	if fidelity := report.Fidelity(); fidelity < 0.81 || fidelity > 0.82 {
+		t.Errorf("Expected fidelity 9/11, got %f", fidelity)
+	}
+	if want := "1 components, 11 properties, 81.8% kept (VALARM.DESCRIPTION 1, X-MICROSOFT-CDO-BUSYSTATUS 1)"; report.String() != want {
+		t.Errorf("Expected %q, got %q", want, report.String())
+	}
+}
+
+func TestRoundTripErrors(t *testing.T) {
+	if _, err := RoundTrip(ical.New(), []byte("not a calendar")); err == nil {
+		t.Error("Expected error for invalid iCalendar data")
+	}
+}
+
+func TestRoundTripDir(t *testing.T) {
+	reports, failures, err := RoundTripDir(ical.New(), filepath.Join("..", "..", "..", "testdata", "ical"))
+	if err != nil {
+		t.Fatalf("RoundTripDir failed: %v", err)
+	}
+	if len(failures) > 0 {
+		t.Errorf("Expected every file to convert, got %v", failures)
+	}
+	if len(reports) != 3 {
+		t.Fatalf("Expected 3 reports, got %d", len(reports))
+	}
+	for _, report := range reports {
+		if report.Name == "" || report.Components == 0 {
+			t.Errorf("Expected a named report with components, got %s", report)
+		}
+	}
+
+	merged := Merge(reports)
+	if merged.Components < len(reports) {
+		t.Errorf("Expected at least %d components in total, got %d", len(reports), merged.Components)
+	}
+	for _, loss := range merged.Losses {
+		t.Errorf("Round trip loss: %s", loss)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	data := []byte(strings.ReplaceAll(lossyCalendar, "\n", "\r\n"))
+	Check(t, ical.New(), data, "X-MICROSOFT-CDO-BUSYSTATUS", "VALARM.DESCRIPTION")
+}
@@ -0,0 +1,233 @@
+// Command refgolden generates the golden files of the differential test
+// corpus from a reference implementation: github.com/arran4/golang-ical
+// parses each input and resolves its date-times, with time zones from the
+// Go time package. The mapping of the resulting values to JSCalendar
+// fields is kept minimal and free of jscal code, so the corpus checks the
+// converter against an interpretation it doesn't share.
+//
+// Run from convert/ical:
+//
+//	go run ./internal/refgolden testdata/differential
+//
+// knownDifferences recorded in existing golden files are kept.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	ics "github.com/arran4/golang-ical"
+)
+
+const referenceModule = "github.com/arran4/golang-ical"
+
+// localLayout is the layout of JSCalendar LocalDateTime values
+const localLayout = "2006-01-02T15:04:05"
+
+// goldenFile mirrors the golden file format of differential_test.go
+type goldenFile struct {
+	Oracle string        `json:"oracle"`
+	Events []goldenEvent `json:"events"`
+}
+
+type goldenEvent struct {
+	UID              string            `json:"uid"`
+	Fields           map[string]string `json:"fields"`
+	KnownDifferences map[string]string `json:"knownDifferences,omitempty"`
+}
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: refgolden <corpus directory>")
+		os.Exit(2)
+	}
+	oracle, err := oracleName()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	inputs, err := filepath.Glob(filepath.Join(os.Args[1], "*.ics"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	for _, input := range inputs {
+		if err := generate(input, oracle); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", input, err)
+			os.Exit(1)
+		}
+	}
+}
+
+// oracleName returns the reference implementation and version this binary
+// was built with
+func oracleName() (string, error) {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "", fmt.Errorf("no build information")
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == referenceModule {
+			return fmt.Sprintf("%s %s (time zones: %s)", dep.Path, dep.Version, runtime.Version()), nil
+		}
+	}
+	return "", fmt.Errorf("%s is not a dependency", referenceModule)
+}
+
+// generate writes the golden file of one input
+func generate(input, oracle string) error {
+	data, err := os.ReadFile(input)
+	if err != nil {
+		return err
+	}
+	cal, err := ics.ParseCalendar(strings.NewReader(string(data)))
+	if err != nil {
+		return err
+	}
+
+	path := strings.TrimSuffix(input, ".ics") + ".golden.json"
+	known := make(map[string]map[string]string)
+	if existing, err := os.ReadFile(path); err == nil {
+		var previous goldenFile
+		if err := json.Unmarshal(existing, &previous); err != nil {
+			return fmt.Errorf("invalid golden file: %w", err)
+		}
+		for _, event := range previous.Events {
+			known[event.UID] = event.KnownDifferences
+		}
+	}
+
+	golden := goldenFile{Oracle: oracle}
+	for _, event := range cal.Events() {
+		fields, err := eventFields(event)
+		if err != nil {
+			return err
+		}
+		uid := value(&event.ComponentBase, ics.ComponentPropertyUniqueId)
+		golden.Events = append(golden.Events, goldenEvent{UID: uid, Fields: fields, KnownDifferences: known[uid]})
+	}
+
+	out, err := json.MarshalIndent(golden, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(out, '\n'), 0o644)
+}
+
+// eventFields returns the compared fields of an event
+func eventFields(event *ics.VEvent) (map[string]string, error) {
+	fields := make(map[string]string)
+	if summary := value(&event.ComponentBase, ics.ComponentPropertySummary); summary != "" {
+		fields["title"] = summary
+	}
+
+	dtstart := event.GetProperty(ics.ComponentPropertyDtStart)
+	if dtstart == nil {
+		return nil, fmt.Errorf("event without DTSTART")
+	}
+	allDay := len(dtstart.ICalParameters[string(ics.ParameterValue)]) > 0 &&
+		dtstart.ICalParameters[string(ics.ParameterValue)][0] == string(ics.ValueDataTypeDate)
+
+	var start, end time.Time
+	var err error
+	if allDay {
+		if start, err = event.GetAllDayStartAt(); err != nil {
+			return nil, err
+		}
+		fields["showWithoutTime"] = "true"
+	} else {
+		if start, err = event.GetStartAt(); err != nil {
+			return nil, err
+		}
+		switch {
+		case len(dtstart.ICalParameters[string(ics.ParameterTzid)]) > 0:
+			fields["timeZone"] = dtstart.ICalParameters[string(ics.ParameterTzid)][0]
+		case strings.HasSuffix(dtstart.Value, "Z"):
+			// RFC 8984 section 4.7.1: UTC is Etc/UTC
+			fields["timeZone"] = "Etc/UTC"
+		}
+	}
+	fields["start"] = start.Format(localLayout)
+
+	if duration := value(&event.ComponentBase, ics.ComponentPropertyDuration); duration != "" {
+		fields["duration"] = duration
+	} else if event.GetProperty(ics.ComponentPropertyDtEnd) != nil {
+		if allDay {
+			end, err = event.GetAllDayEndAt()
+		} else {
+			end, err = event.GetEndAt()
+		}
+		if err != nil {
+			return nil, err
+		}
+		fields["duration"] = formatDuration(end.Sub(start), allDay)
+	}
+
+	for i, rule := range event.GetProperties(ics.ComponentPropertyRrule) {
+		if err := ruleFields(fields, fmt.Sprintf("recurrenceRules[%d].", i), rule.Value, start.Location()); err != nil {
+			return nil, err
+		}
+	}
+	return fields, nil
+}
+
+// ruleFields adds the compared fields of an RRULE value, with a UTC UNTIL
+// in the time zone of the start
+func ruleFields(fields map[string]string, prefix, rule string, loc *time.Location) error {
+	for _, part := range strings.Split(rule, ";") {
+		name, val, _ := strings.Cut(part, "=")
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			fields[prefix+"frequency"] = strings.ToLower(val)
+		case "INTERVAL":
+			fields[prefix+"interval"] = val
+		case "COUNT":
+			fields[prefix+"count"] = val
+		case "BYDAY":
+			fields[prefix+"byDay"] = strings.ToLower(val)
+		case "BYMONTHDAY":
+			fields[prefix+"byMonthDay"] = val
+		case "UNTIL":
+			until, err := time.Parse("20060102T150405Z", val)
+			if err != nil {
+				return fmt.Errorf("unsupported UNTIL %q", val)
+			}
+			fields[prefix+"until"] = until.In(loc).Format(localLayout)
+		}
+	}
+	return nil
+}
+
+// formatDuration formats the exact time between start and end as an
+// ISO 8601 duration, in days for all-day events
+func formatDuration(d time.Duration, allDay bool) string {
+	if allDay && d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("P%dD", d/(24*time.Hour))
+	}
+	s := "PT"
+	if h := d / time.Hour; h > 0 {
+		s += fmt.Sprintf("%dH", h)
+	}
+	if m := d % time.Hour / time.Minute; m > 0 {
+		s += fmt.Sprintf("%dM", m)
+	}
+	if sec := d % time.Minute / time.Second; sec > 0 || s == "PT" {
+		s += fmt.Sprintf("%dS", sec)
+	}
+	return s
+}
+
+// value returns the value of a property, or ""
+func value(cb *ics.ComponentBase, property ics.ComponentProperty) string {
+	if prop := cb.GetProperty(property); prop != nil {
+		return prop.Value
+	}
+	return ""
+}
@@ -0,0 +1,132 @@
+package ical
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/airtrafik/jscal"
+	"github.com/airtrafik/jscal/convert"
+)
+
+// SplitItems splits iCalendar data into one item per UID: the VEVENTs and
+// VJOURNALs sharing it, such as a series and its detached instances, in a
+// calendar with the properties, time zones and other components of the
+// input. Components without a UID are items of their own, keyed
+// "index:<n>" by their position. Items are decoded and, with Lenient,
+// repaired.
+func (c *Converter) SplitItems(data []byte) ([]convert.Item, error) {
+	data, err := c.decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	type item struct {
+		key   string
+		line  int
+		lines []string
+	}
+	var items []*item
+	byKey := make(map[string]*item)
+	var header, footer, component []string
+	start, depth, n := 0, 0, 0
+
+	lines := strings.SplitAfter(string(data), "\n")
+	for i, line := range lines {
+		name := strings.ToUpper(strings.TrimSpace(line))
+		if component != nil {
+			component = append(component, line)
+			if strings.HasPrefix(name, "BEGIN:") {
+				depth++
+			} else if strings.HasPrefix(name, "END:") {
+				depth--
+			}
+			if depth > 0 {
+				continue
+			}
+
+			key := componentUID(component)
+			if key == "" {
+				key = fmt.Sprintf("index:%d", n)
+			}
+			n++
+			if byKey[key] == nil {
+				byKey[key] = &item{key: key, line: start}
+				items = append(items, byKey[key])
+			}
+			byKey[key].lines = append(byKey[key].lines, component...)
+			component = nil
+			continue
+		}
+
+		switch name {
+		case "BEGIN:VEVENT", "BEGIN:VJOURNAL":
+			component, start, depth = []string{line}, i+1, 1
+		case "END:VCALENDAR":
+			footer = lines[i:]
+		default:
+			header = append(header, line)
+		}
+		if footer != nil {
+			break
+		}
+	}
+	if component != nil {
+		return nil, fmt.Errorf("component starting on line %d is not terminated", start)
+	}
+
+	result := make([]convert.Item, len(items))
+	for i, it := range items {
+		var b strings.Builder
+		for _, part := range [][]string{header, it.lines, footer} {
+			for _, line := range part {
+				b.WriteString(line)
+			}
+		}
+		result[i] = convert.Item{Key: it.key, Line: it.line, Data: []byte(b.String())}
+	}
+	return result, nil
+}
+
+// ParseItem converts an item returned by SplitItems. Conversion errors
+// report the line the item starts on in the input.
+func (c *Converter) ParseItem(item convert.Item) ([]*jscal.Event, error) {
+	// The item was decoded and repaired by SplitItems
+	parser := *c
+	parser.Charset, parser.Lenient = "utf-8", false
+	events, err := parser.ParseAll(item.Data)
+
+	var convErr *convert.ConversionError
+	if errors.As(err, &convErr) {
+		convErr.Line = item.Line
+	}
+	return events, err
+}
+
+// componentUID returns the UID of a component given as its content lines,
+// or "" if it has none. UIDs of nested components such as VALARM are
+// ignored.
+func componentUID(lines []string) string {
+	depth := 0
+	for i, line := range lines {
+		line = strings.TrimRight(line, "\r\n")
+		upper := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(upper, "BEGIN:"):
+			depth++
+		case strings.HasPrefix(upper, "END:"):
+			depth--
+		case depth == 1 && (strings.HasPrefix(upper, "UID:") || strings.HasPrefix(upper, "UID;")):
+			// Unfold the value
+			for _, next := range lines[i+1:] {
+				if !strings.HasPrefix(next, " ") && !strings.HasPrefix(next, "\t") {
+					break
+				}
+				line += strings.TrimRight(next[1:], "\r\n")
+			}
+			_, uid, _ := strings.Cut(line, ":")
+			return strings.TrimSpace(uid)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,72 @@
+package ical
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/airtrafik/jscal/convert"
+)
+
+const itemsCalendar = "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//Test//EN\r\n" +
+	"BEGIN:VEVENT\r\nUID:series\r\nDTSTART:20250303T090000Z\r\nRRULE:FREQ=DAILY;COUNT=3\r\nSUMMARY:Standup\r\n" +
+	"BEGIN:VALARM\r\nUID:alarm-1\r\nACTION:DISPLAY\r\nTRIGGER:-PT5M\r\nEND:VALARM\r\nEND:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\nUID:broken\r\nDTSTART:20250303T090000Z\r\nDURATION:soon\r\nEND:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\nUID:ser\r\n ies\r\nRECURRENCE-ID:20250304T090000Z\r\nDTSTART:20250304T100000Z\r\nSUMMARY:Moved\r\nEND:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\nDTSTART:20250305T090000Z\r\nEND:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestSplitItems(t *testing.T) {
+	converter := New()
+	items, err := converter.SplitItems([]byte(itemsCalendar))
+	if err != nil {
+		t.Fatalf("SplitItems failed: %v", err)
+	}
+
+	expected := []struct {
+		key  string
+		line int
+	}{
+		{"series", 4},
+		{"broken", 15},
+		{"index:3", 27},
+	}
+	if len(items) != len(expected) {
+		t.Fatalf("Expected %d items, got %d", len(expected), len(items))
+	}
+	for i, tt := range expected {
+		if items[i].Key != tt.key || items[i].Line != tt.line {
+			t.Errorf("Item %d: expected %s on line %d, got %s on line %d", i, tt.key, tt.line, items[i].Key, items[i].Line)
+		}
+		if data := string(items[i].Data); !strings.HasPrefix(data, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n") || !strings.HasSuffix(data, "END:VEVENT\r\nEND:VCALENDAR\r\n") {
+			t.Errorf("Item %d: expected a calendar, got:\n%s", i, data)
+		}
+	}
+
+	// The detached instance is merged into its series
+	events, err := converter.ParseItem(items[0])
+	if err != nil || len(events) != 1 {
+		t.Fatalf("Expected 1 event, got %d and %v", len(events), err)
+	}
+	if len(events[0].RecurrenceOverrides) != 1 {
+		t.Errorf("Expected the detached instance as an override, got %v", events[0].RecurrenceOverrides)
+	}
+
+	// Errors point at the item in the input
+	_, err = converter.ParseItem(items[1])
+	var convErr *convert.ConversionError
+	if !errors.As(err, &convErr) || convErr.UID != "broken" || convErr.Line != 15 {
+		t.Errorf("Expected a ConversionError for broken on line 15, got %v", err)
+	}
+	_, err = converter.ParseItem(items[2])
+	if err == nil {
+		t.Error("Expected an error for an event without UID")
+	}
+}
+
+func TestSplitItemsUnterminated(t *testing.T) {
+	data := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VEVENT\r\nUID:open\r\n"
+	if _, err := New().SplitItems([]byte(data)); err == nil {
+		t.Error("Expected an error for an unterminated VEVENT")
+	}
+}
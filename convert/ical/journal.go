@@ -0,0 +1,154 @@
+package ical
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/airtrafik/jscal"
+	ics "github.com/arran4/golang-ical"
+)
+
+// JournalPolicy decides what the converter does with VJOURNAL components.
+// JSCalendar has no journal object, so entries can only be skipped,
+// converted to events or rejected.
+type JournalPolicy int
+
+const (
+	// JournalSkip ignores journal entries, the default
+	JournalSkip JournalPolicy = iota
+
+	// JournalAsEvent converts journal entries to free events marked with
+	// the JournalProperty extension. Format writes such events back as
+	// VJOURNALs.
+	JournalAsEvent
+
+	// JournalReject fails the conversion of calendars with journal entries;
+	// lenient parsing reports each entry as an item error instead
+	JournalReject
+)
+
+// JournalProperty is the extension property, set to true, marking events
+// converted from VJOURNAL components
+const JournalProperty = "airtrafik.com:journal"
+
+// ErrJournal is returned for VJOURNAL components under JournalReject
+var ErrJournal = errors.New("VJOURNAL components are not supported")
+
+// journalStatuses maps VJOURNAL status values to event statuses
+var journalStatuses = map[string]string{
+	"DRAFT":     jscal.StatusTentative,
+	"FINAL":     jscal.StatusConfirmed,
+	"CANCELLED": jscal.StatusCancelled,
+}
+
+// eventOnlyProperties are VEVENT properties a VJOURNAL can't have (RFC 5545
+// Section 3.6.3)
+var eventOnlyProperties = map[string]bool{
+	string(ics.ComponentPropertyDtEnd):     true,
+	string(ics.ComponentPropertyDuration):  true,
+	string(ics.ComponentPropertyTransp):    true,
+	string(ics.ComponentPropertyLocation):  true,
+	string(ics.ComponentPropertyGeo):       true,
+	string(ics.ComponentPropertyPriority):  true,
+	string(ics.ComponentPropertyResources): true,
+	propertyAppleStructuredLocation:        true,
+	"CONFERENCE":                           true,
+}
+
+// calendarJournals returns the VJOURNAL components of a calendar
+func calendarJournals(cal *ics.Calendar) []*ics.VJournal {
+	var journals []*ics.VJournal
+	for _, component := range cal.Components {
+		if journal, ok := component.(*ics.VJournal); ok {
+			journals = append(journals, journal)
+		}
+	}
+	return journals
+}
+
+// parseJournals converts the VJOURNALs of a calendar following the
+// converter's JournalPolicy, passing entries that fail to convert to
+// itemFailed. Their index counts VJOURNALs only.
+func (c *Converter) parseJournals(ctx context.Context, cal *ics.Calendar, data []byte, report *ConversionReport, itemFailed itemErrorFunc) ([]*jscal.Event, error) {
+	if c.Journals == JournalSkip {
+		return nil, nil
+	}
+
+	lines := componentLines(data, "VJOURNAL")
+	var events []*jscal.Event
+	for i, vjournal := range calendarJournals(cal) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		event, err := c.convertJournal(ctx, vjournal)
+		if err == nil {
+			err = c.account(ctx, report, event.UID, vjournal.Properties)
+		}
+		if err != nil {
+			if err := itemFailed("journal entry", i, lineAt(lines, i), vjournal.Id(), err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// convertJournal converts a VJOURNAL to an event, or rejects it
+func (c *Converter) convertJournal(ctx context.Context, vjournal *ics.VJournal) (*jscal.Event, error) {
+	if c.Journals == JournalReject {
+		return nil, ErrJournal
+	}
+
+	event, err := convertICalEventToJSCal(&ics.VEvent{ComponentBase: vjournal.ComponentBase})
+	if err != nil {
+		return nil, err
+	}
+	c.dropLargeAttachments(ctx, event)
+	event.SetExtension(JournalProperty, true)
+
+	// Entries note something about a day or time, they don't block it
+	event.FreeBusyStatus = jscal.String(jscal.FreeBusyFree)
+
+	event.Status = nil
+	if status := vjournal.GetProperty(ics.ComponentPropertyStatus); status != nil {
+		if mapped, ok := journalStatuses[strings.ToUpper(status.Value)]; ok {
+			event.Status = jscal.String(mapped)
+		}
+	}
+	return event, nil
+}
+
+// isJournal reports whether an event is a converted journal entry
+func isJournal(event *jscal.Event) bool {
+	journal, _ := event.Extensions[JournalProperty].(bool)
+	return journal
+}
+
+// formatJournal turns a converted event into a VJOURNAL, dropping what RFC
+// 5545 doesn't allow in one: alarms and the VEVENT-only properties
+func formatJournal(vevent *ics.VEvent) *ics.VJournal {
+	vjournal := &ics.VJournal{}
+	for _, prop := range vevent.Properties {
+		if eventOnlyProperties[prop.IANAToken] {
+			continue
+		}
+		if prop.IANAToken == string(ics.ComponentPropertyStatus) {
+			prop.Value = journalStatus(prop.Value)
+		}
+		vjournal.Properties = append(vjournal.Properties, prop)
+	}
+	return vjournal
+}
+
+// journalStatus maps an event status back to a VJOURNAL status
+func journalStatus(status string) string {
+	for journal, event := range journalStatuses {
+		if strings.EqualFold(event, status) {
+			return journal
+		}
+	}
+	return status
+}
@@ -0,0 +1,136 @@
+package ical
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/airtrafik/jscal"
+)
+
+const journalCalendar = `BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//Test//Test//EN
+BEGIN:VEVENT
+UID:meeting@example.com
+SUMMARY:Planning
+DTSTART:20250301T140000Z
+DURATION:PT1H
+END:VEVENT
+BEGIN:VJOURNAL
+UID:journal@example.com
+DTSTAMP:20250301T180000Z
+DTSTART;VALUE=DATE:20250301
+SUMMARY:Planning notes
+DESCRIPTION:Agreed on the Q2 roadmap
+STATUS:FINAL
+CATEGORIES:Notes
+END:VJOURNAL
+END:VCALENDAR`
+
+func TestJournalPolicies(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   JournalPolicy
+		events   int
+		wantErr  bool
+		itemErrs int
+	}{
+		{name: "skip", policy: JournalSkip, events: 1},
+		{name: "as event", policy: JournalAsEvent, events: 2},
+		{name: "reject", policy: JournalReject, wantErr: true, events: 1, itemErrs: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converter := &Converter{Journals: tt.policy}
+			events, err := converter.ParseAll([]byte(journalCalendar))
+			if tt.wantErr {
+				if !errors.Is(err, ErrJournal) {
+					t.Errorf("Expected ErrJournal, got %v", err)
+				}
+			} else if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			} else if len(events) != tt.events {
+				t.Errorf("Expected %d events, got %d", tt.events, len(events))
+			}
+
+			// Lenient parsing never aborts on journal entries
+			events, itemErrs, err := converter.ParseAllLenient([]byte(journalCalendar))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(events) != tt.events || len(itemErrs) != tt.itemErrs {
+				t.Errorf("Expected %d events and %d item errors, got %d and %v", tt.events, tt.itemErrs, len(events), itemErrs)
+			}
+			for _, itemErr := range itemErrs {
+				if itemErr.Line != 10 || itemErr.UID != "journal@example.com" || !errors.Is(itemErr, ErrJournal) {
+					t.Errorf("Expected ErrJournal for journal@example.com on line 10, got %v", itemErr)
+				}
+			}
+		})
+	}
+}
+
+func TestJournalAsEvent(t *testing.T) {
+	converter := &Converter{Journals: JournalAsEvent}
+	events, err := converter.ParseAll([]byte(journalCalendar))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var journal *jscal.Event
+	for _, event := range events {
+		if event.UID == "journal@example.com" {
+			journal = event
+		}
+	}
+	if journal == nil {
+		t.Fatal("Journal entry not converted")
+	}
+	if journal.Extensions[JournalProperty] != true {
+		t.Errorf("Expected %s to be true, got %v", JournalProperty, journal.Extensions[JournalProperty])
+	}
+	if journal.GetTitle() != "Planning notes" || journal.GetDescription() != "Agreed on the Q2 roadmap" {
+		t.Errorf("Expected title and description to be kept, got %q and %q", journal.GetTitle(), journal.GetDescription())
+	}
+	if journal.GetStatus() != jscal.StatusConfirmed {
+		t.Errorf("Expected status %q, got %q", jscal.StatusConfirmed, journal.GetStatus())
+	}
+	if journal.GetFreeBusyStatus() != jscal.FreeBusyFree {
+		t.Errorf("Expected freeBusyStatus %q, got %q", jscal.FreeBusyFree, journal.GetFreeBusyStatus())
+	}
+	if !journal.IsAllDay() {
+		t.Errorf("Expected a date-only journal entry to be all-day")
+	}
+	if err := journal.Validate(); err != nil {
+		t.Errorf("Expected a valid event, got %v", err)
+	}
+
+	// Journal entries are written back as VJOURNALs
+	output, err := converter.FormatAll(events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ics := string(output)
+	if strings.Count(ics, "BEGIN:VJOURNAL") != 1 || strings.Count(ics, "BEGIN:VEVENT") != 1 {
+		t.Errorf("Expected one VEVENT and one VJOURNAL, got:\n%s", ics)
+	}
+	journalPart := ics[strings.Index(ics, "BEGIN:VJOURNAL"):strings.Index(ics, "END:VJOURNAL")]
+	if !strings.Contains(journalPart, "STATUS:FINAL") {
+		t.Errorf("Expected STATUS:FINAL in the VJOURNAL, got:\n%s", journalPart)
+	}
+	for _, property := range []string{"TRANSP", "DURATION", "DTEND"} {
+		if strings.Contains(journalPart, "\r\n"+property) {
+			t.Errorf("Expected no %s in the VJOURNAL, got:\n%s", property, journalPart)
+		}
+	}
+
+	reparsed, err := converter.ParseAll(output)
+	if err != nil {
+		t.Fatalf("Parse of output failed: %v", err)
+	}
+	if len(reparsed) != 2 {
+		t.Errorf("Expected 2 events after a round trip, got %d", len(reparsed))
+	}
+}
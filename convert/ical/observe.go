@@ -0,0 +1,54 @@
+package ical
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/airtrafik/jscal/observe"
+	ics "github.com/arran4/golang-ical"
+)
+
+// reportUnsupported counts and logs the properties and parameters
+// dropped from a converted component
+func (c *Converter) reportUnsupported(ctx context.Context, uid string, properties, parameters map[string]int) {
+	if c.Hooks == nil {
+		return
+	}
+	for _, name := range sortedKeys(properties) {
+		c.Hooks.Add(ctx, observe.UnsupportedProperties, int64(properties[name]), slog.String("property", name))
+		c.Hooks.Warn(ctx, "dropped unsupported iCalendar property", slog.String("uid", uid), slog.String("property", name))
+	}
+	for _, name := range sortedKeys(parameters) {
+		c.Hooks.Add(ctx, observe.UnsupportedParameters, int64(parameters[name]), slog.String("parameter", name))
+		c.Hooks.Warn(ctx, "dropped unsupported iCalendar parameter", slog.String("uid", uid), slog.String("parameter", name))
+	}
+}
+
+// reportDroppedComponents counts and logs the components of a calendar the
+// parser skips
+func (c *Converter) reportDroppedComponents(ctx context.Context, components map[string]int) {
+	for _, name := range sortedKeys(components) {
+		c.Hooks.Add(ctx, observe.DroppedItems, int64(components[name]), slog.String("item", name))
+		c.Hooks.Warn(ctx, "dropped iCalendar components", slog.String("component", name), slog.Int("count", components[name]))
+	}
+}
+
+// componentName returns the name of a component, e.g. "VTODO"
+func componentName(component ics.Component) string {
+	switch c := component.(type) {
+	case *ics.VEvent:
+		return string(ics.ComponentVEvent)
+	case *ics.VTodo:
+		return string(ics.ComponentVTodo)
+	case *ics.VJournal:
+		return string(ics.ComponentVJournal)
+	case *ics.VBusy:
+		return string(ics.ComponentVFreeBusy)
+	case *ics.VTimezone:
+		return string(ics.ComponentVTimezone)
+	case *ics.GeneralComponent:
+		return strings.ToUpper(c.Token)
+	}
+	return "UNKNOWN"
+}
@@ -0,0 +1,59 @@
+package ical
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/airtrafik/jscal/observe"
+)
+
+func TestHooks(t *testing.T) {
+	data := []byte("BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//Test//EN\r\n" +
+		"BEGIN:VEVENT\r\nUID:hooks-1\r\nDTSTAMP:20250301T000000Z\r\nDTSTART:20250303T090000Z\r\nSUMMARY:Review\r\n" +
+		"X-ALT-DESC;FMTTYPE=text/html:<b>Review</b>\r\nX-ALT-DESC;FMTTYPE=text/html:<i>Again</i>\r\n" +
+		"RESOURCES:Projector\r\nATTENDEE;RSVP=TRUE:mailto:ann@example.com\r\nATTACH;ENCODING=BASE64;VALUE=BINARY:SGVsbG8sIHdvcmxkIQ==\r\nEND:VEVENT\r\n" +
+		"BEGIN:VTODO\r\nUID:todo-1\r\nSUMMARY:Chores\r\nEND:VTODO\r\n" +
+		"BEGIN:VJOURNAL\r\nUID:journal-1\r\nSUMMARY:Notes\r\nEND:VJOURNAL\r\n" +
+		"END:VCALENDAR\r\n")
+
+	recorder := &observe.Recorder{}
+	c := &Converter{MaxAttachmentSize: 4, Hooks: &observe.Hooks{Tracer: recorder, Counter: recorder}}
+	events, err := c.ParseAll(data)
+	if err != nil || len(events) != 1 {
+		t.Fatalf("Expected 1 event, got %d and %v", len(events), err)
+	}
+
+	counts := []struct {
+		name     string
+		attr     slog.Attr
+		expected int64
+	}{
+		{observe.UnsupportedProperties, slog.String("property", "X-ALT-DESC"), 2},
+		{observe.UnsupportedProperties, slog.String("property", "RESOURCES"), 1},
+		{observe.UnsupportedProperties, slog.String("property", "SUMMARY"), 0},
+		{observe.UnsupportedParameters, slog.String("parameter", "ATTENDEE;RSVP"), 1},
+		{observe.DroppedItems, slog.String("item", "VTODO"), 1},
+		{observe.DroppedItems, slog.String("item", "VJOURNAL"), 1},
+		{observe.DroppedItems, slog.String("item", "ATTACH"), 1},
+	}
+	for _, tt := range counts {
+		if got := recorder.Count(tt.name, tt.attr); got != tt.expected {
+			t.Errorf("Expected %s %s = %d, got %d", tt.name, tt.attr, tt.expected, got)
+		}
+	}
+
+	if _, err := c.FormatAllContext(context.Background(), events); err != nil {
+		t.Fatalf("FormatAllContext failed: %v", err)
+	}
+	if len(recorder.Spans) != 2 {
+		t.Fatalf("Expected a parse and a format span, got %d", len(recorder.Spans))
+	}
+	parse, format := recorder.Spans[0], recorder.Spans[1]
+	if parse.Name != "jscal.ical.parse" || parse.Attr("events").Int64() != 1 || !parse.Ended {
+		t.Errorf("Unexpected parse span %+v", parse)
+	}
+	if format.Name != "jscal.ical.format" || format.Attr("bytes").Int64() == 0 || !format.Ended {
+		t.Errorf("Unexpected format span %+v", format)
+	}
+}
@@ -0,0 +1,154 @@
+package ical
+
+import (
+	"context"
+	"time"
+
+	"github.com/airtrafik/jscal"
+	"github.com/airtrafik/jscal/convert"
+)
+
+// ExpandOptions controls how FormatRange writes recurring events
+type ExpandOptions struct {
+	// Expand writes each instance in the range as its own VEVENT with a
+	// RECURRENCE-ID, without recurrence rules and with times in UTC, as
+	// CALDAV:expand asks (RFC 4791 Section 9.6.5). Otherwise recurring
+	// events are written whole, keeping only the overrides in the range,
+	// as CALDAV:limit-recurrence-set does.
+	Expand bool
+
+	// Format sets the calendar-level properties, see FormatAllWithOptions
+	Format FormatOptions
+}
+
+// FormatRange converts the events overlapping the interval [from, to),
+// e.g. to answer a CalDAV time-range query. A recurring event overlaps if
+// any of its instances does, and an instance without duration overlaps if
+// it starts in the interval. Floating times are read in the location of
+// from. Unlike FormatAll, a range without events gives an empty VCALENDAR.
+func (c *Converter) FormatRange(events []*jscal.Event, from, to time.Time, opts ExpandOptions) ([]byte, error) {
+	ctx := context.Background()
+	var selected []*jscal.Event
+	for _, event := range events {
+		if event == nil || event.Start == nil {
+			continue
+		}
+		occurrences, err := rangeOccurrences(ctx, event, from, to)
+		if err != nil {
+			return nil, &convert.ConversionError{Item: "event", UID: event.UID, Err: err}
+		}
+		if len(occurrences) == 0 {
+			continue
+		}
+
+		switch {
+		case opts.Expand:
+			for _, occurrence := range occurrences {
+				selected = append(selected, expandedInstance(event, occurrence, from.Location()))
+			}
+		case isRecurringEvent(event):
+			selected = append(selected, limitOverrides(event, occurrences))
+		default:
+			selected = append(selected, event)
+		}
+	}
+	return c.formatCalendar(ctx, selected, opts.Format)
+}
+
+// isRecurringEvent reports whether an event has instances besides its
+// start, from rules or from overrides
+func isRecurringEvent(event *jscal.Event) bool {
+	return len(event.RecurrenceRules) > 0 || len(event.RecurrenceOverrides) > 0
+}
+
+// rangeLocation returns the location the event's times are read in:
+// its time zone, or loc for floating and all-day events
+func rangeLocation(event *jscal.Event, loc *time.Location) *time.Location {
+	if event.TimeZone != nil && !event.IsAllDay() {
+		if zone, err := time.LoadLocation(*event.TimeZone); err == nil {
+			return zone
+		}
+	}
+	return loc
+}
+
+// rangeOccurrences returns the instances of an event overlapping [from, to)
+func rangeOccurrences(ctx context.Context, event *jscal.Event, from, to time.Time) ([]*jscal.Occurrence, error) {
+	loc := rangeLocation(event, from.Location())
+
+	// Instances starting before from may still overlap it. Overrides can
+	// change the duration, so allow a day more than the master's.
+	duration, _ := event.GetDuration()
+	lower := jscal.NewLocalDateTime(from.In(loc).Add(-duration - 24*time.Hour))
+	upper := jscal.NewLocalDateTime(to.In(loc).Add(24 * time.Hour))
+	candidates, err := event.OccurrencesContext(ctx, *lower, *upper)
+	if err != nil {
+		return nil, err
+	}
+
+	var occurrences []*jscal.Occurrence
+	for _, occurrence := range candidates {
+		instanceStart := occurrence.Start()
+		start := instanceStart.In(loc)
+		if !start.Before(to) {
+			continue
+		}
+		duration, err := occurrence.Event.GetDuration()
+		if err != nil || duration <= 0 {
+			if start.Before(from) {
+				continue
+			}
+		} else if !start.Add(duration).After(from) {
+			continue
+		}
+		occurrences = append(occurrences, occurrence)
+	}
+	return occurrences, nil
+}
+
+// expandedInstance returns an occurrence as a standalone event for
+// CALDAV:expand: timed instances move to UTC, and instances of recurring
+// events keep their recurrence id
+func expandedInstance(event *jscal.Event, occurrence *jscal.Occurrence, floating *time.Location) *jscal.Event {
+	instance := occurrence.Event
+	if !isRecurringEvent(event) {
+		instance = event.Clone()
+		instance.RecurrenceId = nil
+	}
+	if instance.IsAllDay() {
+		return instance
+	}
+
+	loc := rangeLocation(event, floating)
+	if instance.Start != nil {
+		instance.Start = jscal.NewLocalDateTime(instance.Start.In(loc).UTC())
+	}
+	if instance.RecurrenceId != nil {
+		instance.RecurrenceId = jscal.NewLocalDateTime(instance.RecurrenceId.In(loc).UTC())
+	}
+	instance.TimeZone = nil
+	instance.RecurrenceIdTimeZone = nil
+	return instance
+}
+
+// limitOverrides returns a copy of a recurring event keeping only the
+// overrides of the given instances. Exclusions and added instances
+// without a patch are part of the master and stay.
+func limitOverrides(event *jscal.Event, occurrences []*jscal.Occurrence) *jscal.Event {
+	inRange := make(map[string]bool, len(occurrences))
+	for _, occurrence := range occurrences {
+		inRange[occurrence.RecurrenceId.String()] = true
+	}
+
+	limited := event.Clone()
+	for key, patch := range limited.RecurrenceOverrides {
+		if excluded, _ := patch["excluded"].(bool); excluded || len(patch) == 0 {
+			continue
+		}
+		id, err := jscal.ParseLocalDateTime(key)
+		if err != nil || !inRange[id.String()] {
+			delete(limited.RecurrenceOverrides, key)
+		}
+	}
+	return limited
+}
@@ -0,0 +1,119 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/airtrafik/jscal"
+)
+
+func newRangeEvents() []*jscal.Event {
+	standup := jscal.NewEvent("standup@example.com", "Standup")
+	standup.Start = jscal.NewLocalDateTime(time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC))
+	standup.TimeZone = jscal.String("Europe/Berlin")
+	standup.Duration = jscal.String("PT1H")
+	standup.RecurrenceRules = []jscal.RecurrenceRule{*jscal.NewRecurrenceRule("weekly")}
+	standup.RecurrenceOverrides = map[string]map[string]interface{}{
+		"2025-03-17T09:00:00": {"title": "Standup (moved)", "start": "2025-03-17T11:00:00"},
+		"2025-03-24T09:00:00": {"excluded": true},
+		"2025-04-07T09:00:00": {"title": "Standup (later)"},
+	}
+
+	// Starts before the range and ends in it
+	offsite := jscal.NewEvent("offsite@example.com", "Offsite")
+	offsite.Start = jscal.NewLocalDateTime(time.Date(2025, 3, 8, 0, 0, 0, 0, time.UTC))
+	offsite.ShowWithoutTime = jscal.Bool(true)
+	offsite.Duration = jscal.String("P3D")
+
+	review := jscal.NewEvent("review@example.com", "Review")
+	review.Start = jscal.NewLocalDateTime(time.Date(2025, 4, 1, 14, 0, 0, 0, time.UTC))
+	review.Duration = jscal.String("PT1H")
+	return []*jscal.Event{standup, offsite, review}
+}
+
+func TestFormatRange(t *testing.T) {
+	if _, err := time.LoadLocation("Europe/Berlin"); err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	from := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 25, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		expand     bool
+		expected   []string
+		unexpected []string
+	}{
+		{
+			name:   "limit",
+			expand: false,
+			expected: []string{
+				"UID:standup@example.com",
+				"RRULE:FREQ=WEEKLY",
+				"DTSTART;TZID=Europe/Berlin:20250303T090000",
+				"RECURRENCE-ID;TZID=Europe/Berlin:20250317T090000",
+				"SUMMARY:Standup (moved)",
+				"UID:offsite@example.com",
+			},
+			unexpected: []string{"Standup (later)", "UID:review@example.com"},
+		},
+		{
+			name:   "expand",
+			expand: true,
+			expected: []string{
+				"DTSTART:20250310T080000Z",
+				"RECURRENCE-ID:20250310T080000Z",
+				"DTSTART:20250317T100000Z",
+				"RECURRENCE-ID:20250317T080000Z",
+				"SUMMARY:Standup (moved)",
+				"DTSTART;VALUE=DATE:20250308",
+			},
+			unexpected: []string{
+				"RRULE", "TZID", "VTIMEZONE", "EXDATE",
+				"20250303T", "20250324T", "Standup (later)",
+				"UID:review@example.com",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := New().FormatRange(newRangeEvents(), from, to, ExpandOptions{Expand: tt.expand})
+			if err != nil {
+				t.Fatalf("FormatRange failed: %v", err)
+			}
+			icalStr := string(output)
+			for _, want := range tt.expected {
+				if !strings.Contains(icalStr, want) {
+					t.Errorf("Expected %s in output:\n%s", want, icalStr)
+				}
+			}
+			for _, unwanted := range tt.unexpected {
+				if strings.Contains(icalStr, unwanted) {
+					t.Errorf("Unexpected %s in output:\n%s", unwanted, icalStr)
+				}
+			}
+		})
+	}
+
+	// The expanded offsite is not an instance of a recurring event
+	output, err := New().FormatRange(newRangeEvents(), from, to, ExpandOptions{Expand: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count := strings.Count(string(output), "RECURRENCE-ID"); count != 2 {
+		t.Errorf("Expected 2 RECURRENCE-IDs, got %d", count)
+	}
+}
+
+func TestFormatRangeEmpty(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	output, err := New().FormatRange(newRangeEvents()[1:], from, from.AddDate(0, 1, 0), ExpandOptions{})
+	if err != nil {
+		t.Fatalf("FormatRange failed: %v", err)
+	}
+	if !strings.Contains(string(output), "BEGIN:VCALENDAR") || strings.Contains(string(output), "BEGIN:VEVENT") {
+		t.Errorf("Expected an empty calendar, got:\n%s", output)
+	}
+}
@@ -0,0 +1,246 @@
+package ical
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/airtrafik/jscal"
+	ics "github.com/arran4/golang-ical"
+)
+
+// recurrenceIdFormat is the format of recurrenceOverrides keys
+const recurrenceIdFormat = "2006-01-02T15:04:05"
+
+// processRecurrenceDates maps EXDATE to recurrenceOverrides excluding the
+// instance and RDATE to overrides adding one. An RDATE period sets the
+// instance's duration.
+func processRecurrenceDates(vevent *ics.VEvent, event *jscal.Event) {
+	if event.Start == nil {
+		return
+	}
+	for i := range vevent.Properties {
+		prop := &vevent.Properties[i]
+		token := strings.ToUpper(prop.IANAToken)
+		if token != string(ics.ComponentPropertyExdate) && token != string(ics.ComponentPropertyRdate) {
+			continue
+		}
+
+		for _, value := range strings.Split(prop.Value, ",") {
+			value, period, _ := strings.Cut(strings.TrimSpace(value), "/")
+			t, _, _ := parseICalDateTime(&ics.IANAProperty{BaseProperty: ics.BaseProperty{Value: value, ICalParameters: prop.ICalParameters}})
+			if t.IsZero() {
+				continue
+			}
+
+			patch := map[string]interface{}{}
+			if token == string(ics.ComponentPropertyExdate) {
+				patch["excluded"] = true
+			} else if period != "" {
+				if d, err := jscal.ParseDuration(period); err == nil {
+					patch["duration"] = jscal.FormatDuration(d)
+				} else if end, _, _ := parseICalDateTime(&ics.IANAProperty{BaseProperty: ics.BaseProperty{Value: period}}); !end.IsZero() {
+					patch["duration"] = jscal.FormatDuration(end.Sub(t))
+				}
+			}
+
+			if event.RecurrenceOverrides == nil {
+				event.RecurrenceOverrides = make(map[string]map[string]interface{})
+			}
+			event.RecurrenceOverrides[recurrenceKey(event, t)] = patch
+		}
+	}
+}
+
+// recurrenceKey returns the recurrenceOverrides key for t: its wall clock
+// time in the location of the event's start
+func recurrenceKey(event *jscal.Event, t time.Time) string {
+	return t.In(event.Start.Time().Location()).Format(recurrenceIdFormat)
+}
+
+// recurrenceInstant returns the time of a recurrence id in the location of
+// the event's start, so it's written in the same form as DTSTART
+func recurrenceInstant(event *jscal.Event, id jscal.LocalDateTime) time.Time {
+	loc := time.UTC
+	if event.Start != nil {
+		loc = event.Start.Time().Location()
+	}
+	return time.Date(id.Year(), id.Month(), id.Day(), id.Hour(), id.Minute(), id.Second(), id.Nanosecond(), loc)
+}
+
+// formatRecurrenceTime formats a recurrence id like DTSTART: a date for
+// all-day events, a wall clock time with a TZID for events in a time zone,
+// otherwise a UTC date-time
+func formatRecurrenceTime(event *jscal.Event, id jscal.LocalDateTime) (string, []ics.PropertyParameter) {
+	t := recurrenceInstant(event, id)
+	if event.IsAllDay() {
+		return t.Format("20060102"), []ics.PropertyParameter{ics.WithValue("DATE")}
+	}
+	if eventLocation(event) != nil {
+		return t.Format("20060102T150405"), []ics.PropertyParameter{ics.WithTZID(*event.TimeZone)}
+	}
+	return t.UTC().Format("20060102T150405Z"), nil
+}
+
+// convertRecurrenceDates writes excluded overrides as EXDATE, and the
+// other overrides for instances the rules don't produce as RDATE. Overrides
+// that patch properties also become detached VEVENTs, see
+// detachedInstances, which some clients ignore without a matching RDATE.
+func convertRecurrenceDates(event *jscal.Event, vevent *ics.VEvent) error {
+	ruled, err := ruleInstances(event)
+	if err != nil {
+		return err
+	}
+	for _, key := range sortedKeys(event.RecurrenceOverrides) {
+		id, err := jscal.ParseLocalDateTime(key)
+		if err != nil {
+			continue
+		}
+		patch := event.RecurrenceOverrides[key]
+		value, params := formatRecurrenceTime(event, *id)
+		if excluded, _ := patch["excluded"].(bool); excluded {
+			vevent.AddProperty(ics.ComponentPropertyExdate, value, params...)
+		} else if len(patch) == 0 || !ruled[id.String()] {
+			vevent.AddProperty(ics.ComponentPropertyRdate, value, params...)
+		}
+	}
+	return nil
+}
+
+// ruleInstances returns the recurrence ids the start and rules of an event
+// produce up to its last override, ignoring the overrides and
+// excludedRecurrenceRules
+func ruleInstances(event *jscal.Event) (map[string]bool, error) {
+	if len(event.RecurrenceOverrides) == 0 || event.Start == nil {
+		return nil, nil
+	}
+	last := *event.Start
+	for key := range event.RecurrenceOverrides {
+		if id, err := jscal.ParseLocalDateTime(key); err == nil && id.After(&last) {
+			last = *id
+		}
+	}
+
+	rules := event.Clone()
+	rules.RecurrenceOverrides = nil
+	rules.ExcludedRecurrenceRules = nil
+	occurrences, err := rules.Occurrences(*event.Start, last.Add(time.Nanosecond))
+	if err != nil {
+		return nil, err
+	}
+	ruled := make(map[string]bool, len(occurrences))
+	for _, occurrence := range occurrences {
+		ruled[occurrence.RecurrenceId.String()] = true
+	}
+	return ruled, nil
+}
+
+// detachedInstances builds the instances of an event whose overrides patch
+// properties, which iCalendar represents as VEVENTs with a RECURRENCE-ID
+func detachedInstances(event *jscal.Event) ([]*jscal.Event, error) {
+	var instances []*jscal.Event
+	for _, key := range sortedKeys(event.RecurrenceOverrides) {
+		patch := event.RecurrenceOverrides[key]
+		if excluded, _ := patch["excluded"].(bool); excluded || len(patch) == 0 {
+			continue
+		}
+		id, err := jscal.ParseLocalDateTime(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recurrenceOverrides key %s", key)
+		}
+		instance, err := event.Instance(*id)
+		if err != nil {
+			return nil, err
+		}
+
+		// Keep the master's location, so both are written in the same form
+		instance.RecurrenceId = jscal.NewLocalDateTime(recurrenceInstant(event, *id))
+		if instance.Start != nil {
+			instance.Start = jscal.NewLocalDateTime(recurrenceInstant(event, *instance.Start))
+		}
+		instances = append(instances, instance)
+	}
+	return instances, nil
+}
+
+// sortedKeys returns the keys of m, sorted
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// mergeDetachedInstances folds VEVENTs with a RECURRENCE-ID into the
+// recurrenceOverrides of their master event, the VEVENT with the same UID
+// and no RECURRENCE-ID. The override patches the properties that differ
+// from the master. Instances without a master are kept as they are, and an
+// EXDATE for the same recurrence id takes precedence.
+func mergeDetachedInstances(events []*jscal.Event) ([]*jscal.Event, error) {
+	masters := make(map[string]*jscal.Event)
+	for _, event := range events {
+		if event.RecurrenceId == nil {
+			masters[event.UID] = event
+		}
+	}
+
+	merged := make([]*jscal.Event, 0, len(events))
+	for _, event := range events {
+		master, ok := masters[event.UID]
+		if event.RecurrenceId == nil || !ok || master.Start == nil {
+			merged = append(merged, event)
+			continue
+		}
+
+		key := recurrenceKey(master, event.RecurrenceId.Time())
+		if patch, exists := master.RecurrenceOverrides[key]; exists && patch["excluded"] == true {
+			continue
+		}
+		patch, err := overridePatch(master, event, key)
+		if err != nil {
+			return nil, fmt.Errorf("event %s: recurrence %s: %w", event.UID, key, err)
+		}
+		if master.RecurrenceOverrides == nil {
+			master.RecurrenceOverrides = make(map[string]map[string]interface{})
+		}
+		master.RecurrenceOverrides[key] = patch
+	}
+	return merged, nil
+}
+
+// overridePatch returns the PatchObject turning the master's instance at key
+// into the detached instance
+func overridePatch(master, instance *jscal.Event, key string) (map[string]interface{}, error) {
+	id, err := jscal.ParseLocalDateTime(key)
+	if err != nil {
+		return nil, err
+	}
+	base := master.Clone()
+	base.RecurrenceRules = nil
+	base.ExcludedRecurrenceRules = nil
+	base.RecurrenceOverrides = nil
+	base.Start = id
+
+	detached := instance.Clone()
+	detached.RecurrenceId = nil
+	detached.RecurrenceIdTimeZone = nil
+	if instance.Start != nil {
+		// Cloning drops the location, so convert the original
+		if detached.Start, err = jscal.ParseLocalDateTime(recurrenceKey(master, instance.Start.Time())); err != nil {
+			return nil, err
+		}
+	}
+
+	changes, err := base.Diff(detached)
+	if err != nil {
+		return nil, err
+	}
+	patch := make(map[string]interface{}, len(changes))
+	for _, change := range changes {
+		patch[change.Path] = change.New
+	}
+	return patch, nil
+}
@@ -0,0 +1,175 @@
+package ical
+
+import (
+	"bytes"
+	"io"
+	"mime/quotedprintable"
+	"regexp"
+	"strings"
+
+	"github.com/airtrafik/jscal/convert"
+)
+
+// propertyLinePattern matches the start of a content line: a property name
+// followed by its parameters or value. Names are case-insensitive, but only
+// uppercase ones are taken for properties when repairing, so that a line
+// such as "Note: ..." in a broken value isn't.
+var propertyLinePattern = regexp.MustCompile(`^[A-Z0-9-]+[;:]`)
+
+// prepare decodes data and marks the escaped commas of CATEGORIES values,
+// before it is handed to golang-ical
+func (c *Converter) prepare(data []byte) ([]byte, error) {
+	data, err := c.decode(data)
+	if err != nil {
+		return nil, err
+	}
+	return markEscapedCommas(data), nil
+}
+
+// decode decodes data to UTF-8 and, for a lenient converter, repairs it
+func (c *Converter) decode(data []byte) ([]byte, error) {
+	data, err := convert.DecodeCharset(data, c.Charset)
+	if err != nil {
+		return nil, err
+	}
+	if c.Lenient {
+		data = repairInput(data)
+	}
+	return data, nil
+}
+
+// repairInput rewrites the habits of legacy exporters into RFC 5545 content
+// lines: bare LF or CR line endings become CRLF, QUOTED-PRINTABLE values
+// (vCalendar 1.0) are decoded, and lines that continue a value without the
+// leading space of a fold are joined to it. A continuation of a line of
+// full length is taken to be a fold missing its space, otherwise a raw
+// newline in the value, which is escaped. Blank lines are dropped.
+func repairInput(data []byte) []byte {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+
+	var lines []string
+	quoted := false // the last line is a quoted-printable value
+	for _, line := range strings.Split(text, "\n") {
+		switch {
+		case line == "":
+			continue
+		case len(lines) == 0:
+			lines = append(lines, line)
+		case line[0] == ' ' || line[0] == '\t':
+			// A proper fold
+			lines[len(lines)-1] += line[1:]
+			continue
+		case quoted && strings.HasSuffix(lines[len(lines)-1], "="):
+			// A quoted-printable soft line break
+			lines[len(lines)-1] += "\n" + line
+			continue
+		case !propertyLinePattern.MatchString(line):
+			last := lines[len(lines)-1]
+			if len(last) >= maxLineOctets {
+				lines[len(lines)-1] = last + line
+			} else {
+				lines[len(lines)-1] = last + `\n` + line
+			}
+			continue
+		default:
+			lines = append(lines, line)
+		}
+		quoted = isQuotedPrintable(lines[len(lines)-1])
+	}
+
+	var out bytes.Buffer
+	for _, line := range lines {
+		if isQuotedPrintable(line) {
+			line = decodeQuotedPrintableLine(line)
+		}
+		out.WriteString(line)
+		out.WriteString("\r\n")
+	}
+	return out.Bytes()
+}
+
+// splitContentLine splits a content line into its name, its parameters and
+// its value, at the first colon outside a quoted parameter value
+func splitContentLine(line string) (string, []string, string, bool) {
+	inQuotes := false
+	for i, r := range line {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+		case r == ':' && !inQuotes:
+			parts := splitParams(line[:i])
+			return parts[0], parts[1:], line[i+1:], true
+		}
+	}
+	return "", nil, "", false
+}
+
+// splitParams splits a property name and its parameters on semicolons
+// outside quotes
+func splitParams(s string) []string {
+	var parts []string
+	inQuotes := false
+	start := 0
+	for i, r := range s {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+		case r == ';' && !inQuotes:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// isQuotedPrintable reports whether a content line has the ENCODING
+// parameter, or the bare parameter of vCalendar 1.0, for QUOTED-PRINTABLE
+func isQuotedPrintable(line string) bool {
+	_, params, _, ok := splitContentLine(line)
+	if !ok {
+		return false
+	}
+	for _, param := range params {
+		if strings.EqualFold(param, "ENCODING=QUOTED-PRINTABLE") || strings.EqualFold(param, "QUOTED-PRINTABLE") {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeQuotedPrintableLine decodes the value of a QUOTED-PRINTABLE line in
+// its CHARSET, UTF-8 by default, and writes it as an escaped TEXT value
+// without the ENCODING and CHARSET parameters. Values that fail to decode
+// are kept as they are.
+func decodeQuotedPrintableLine(line string) string {
+	name, params, value, _ := splitContentLine(line)
+
+	charset := ""
+	kept := []string{name}
+	for _, param := range params {
+		key, paramValue, _ := strings.Cut(param, "=")
+		switch {
+		case strings.EqualFold(key, "ENCODING"), strings.EqualFold(param, "QUOTED-PRINTABLE"):
+		case strings.EqualFold(key, "CHARSET"):
+			charset = strings.Trim(paramValue, `"`)
+		default:
+			kept = append(kept, param)
+		}
+	}
+
+	// Soft line breaks were kept as "=\n", which the decoder removes
+	decoded, err := io.ReadAll(quotedprintable.NewReader(strings.NewReader(value)))
+	if err != nil {
+		return line
+	}
+	if charset != "" {
+		if decoded, err = convert.DecodeCharset(decoded, charset); err != nil {
+			return line
+		}
+	}
+
+	text := strings.ReplaceAll(string(decoded), "\r\n", "\n")
+	text = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`, "\r", `\n`).Replace(text)
+	return strings.Join(kept, ";") + ":" + text
+}
@@ -0,0 +1,73 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRepairInput(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "bare line endings",
+			input: "BEGIN:VEVENT\nUID:1\rEND:VEVENT\n\n",
+			want:  "BEGIN:VEVENT\r\nUID:1\r\nEND:VEVENT\r\n",
+		},
+		{
+			name:  "proper fold",
+			input: "SUMMARY:Long\r\n  title\r\n",
+			want:  "SUMMARY:Long title\r\n",
+		},
+		{
+			name:  "quoted-printable",
+			input: "DESCRIPTION;ENCODING=QUOTED-PRINTABLE;CHARSET=UTF-8:Caf=C3=A9=0D=0ASecond line, =\nend\r\n",
+			want:  "DESCRIPTION:Café\\nSecond line\\, end\r\n",
+		},
+		{
+			name:  "vcalendar 1.0 quoted-printable",
+			input: "SUMMARY;LANGUAGE=de;QUOTED-PRINTABLE;CHARSET=ISO-8859-1:Gr=FC=DFe\n",
+			want:  "SUMMARY;LANGUAGE=de:Grüße\r\n",
+		},
+		{
+			name:  "raw newline in value",
+			input: "DESCRIPTION:First line\nNote: second line\nUID:1\n",
+			want:  "DESCRIPTION:First line\\nNote: second line\r\nUID:1\r\n",
+		},
+		{
+			name:  "fold without space",
+			input: "DESCRIPTION:" + strings.Repeat("x", 63) + "\nyz\n",
+			want:  "DESCRIPTION:" + strings.Repeat("x", 63) + "yz\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(repairInput([]byte(tt.input))); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLenientParse(t *testing.T) {
+	legacy := "BEGIN:VCALENDAR\nVERSION:2.0\nPRODID:-//Legacy//EN\nBEGIN:VEVENT\nUID:legacy@example.com\n" +
+		"DTSTART:20250301T090000Z\nSUMMARY;ENCODING=QUOTED-PRINTABLE:Caf=C3=A9 meeting\n" +
+		"DESCRIPTION:Agenda\n- budget\nEND:VEVENT\nEND:VCALENDAR\n"
+
+	events, err := (&Converter{Lenient: true}).ParseAll([]byte(legacy))
+	if err != nil {
+		t.Fatalf("ParseAll failed: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(events))
+	}
+	if events[0].GetTitle() != "Café meeting" {
+		t.Errorf("Expected decoded title, got %q", events[0].GetTitle())
+	}
+	if events[0].GetDescription() != "Agenda\n- budget" {
+		t.Errorf("Expected description with its newline, got %q", events[0].GetDescription())
+	}
+}
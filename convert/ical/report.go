@@ -0,0 +1,155 @@
+package ical
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	ics "github.com/arran4/golang-ical"
+)
+
+// ErrUnmapped is wrapped by the errors of a Strict converter for input it
+// would lose data of
+var ErrUnmapped = errors.New("unmapped iCalendar data")
+
+// ConversionReport accounts for the iCalendar data a parse couldn't map to
+// JSCalendar and dropped. Keys are upper case.
+type ConversionReport struct {
+	Properties map[string]int // Properties of events and journal entries by name, e.g. "RESOURCES"
+	Parameters map[string]int // Parameters of mapped properties by property and name, e.g. "ATTENDEE;RSVP"
+	Components map[string]int // Skipped components by name, e.g. "VTODO"
+}
+
+// Empty reports whether nothing was dropped
+func (r *ConversionReport) Empty() bool {
+	return r == nil || len(r.Properties)+len(r.Parameters)+len(r.Components) == 0
+}
+
+// String lists the dropped data with counts, one "NAME: count" per line,
+// components first
+func (r *ConversionReport) String() string {
+	if r == nil {
+		return ""
+	}
+	var b strings.Builder
+	for _, counts := range []map[string]int{r.Components, r.Properties, r.Parameters} {
+		for _, name := range sortedKeys(counts) {
+			fmt.Fprintf(&b, "%s: %d\n", name, counts[name])
+		}
+	}
+	return b.String()
+}
+
+// addCounts adds counts to one of the maps of a report
+func addCounts(counts *map[string]int, add map[string]int) {
+	if len(add) == 0 {
+		return
+	}
+	if *counts == nil {
+		*counts = make(map[string]int)
+	}
+	for name, n := range add {
+		(*counts)[name] += n
+	}
+}
+
+// parsedProperties lists the VEVENT and VJOURNAL properties the parser
+// maps to JSCalendar. DTSTAMP is regenerated when formatting, so it isn't
+// lost. Other properties are dropped and reported.
+var parsedProperties = map[string]bool{
+	"UID": true, "DTSTAMP": true, "DTSTART": true, "DTEND": true, "DURATION": true,
+	"SUMMARY": true, "DESCRIPTION": true, "CREATED": true, "LAST-MODIFIED": true,
+	"SEQUENCE": true, "PRIORITY": true, "RECURRENCE-ID": true, "STATUS": true,
+	"REQUEST-STATUS": true, "CATEGORIES": true, "COLOR": true, "LOCATION": true,
+	"GEO": true, "TRANSP": true, "CLASS": true, "URL": true, "ATTACH": true,
+	"ATTENDEE": true, "ORGANIZER": true, "RRULE": true, "EXRULE": true,
+	"RDATE": true, "EXDATE": true, "RELATED-TO": true,
+	propertyConference:              true,
+	propertyAppleStructuredLocation: true,
+	propertyMayInviteSelf:           true,
+	propertyMayInviteOthers:         true,
+	propertyHideAttendees:           true,
+}
+
+// parsedComponents lists the calendar components the parser reads; others,
+// such as VTODO, are dropped and reported
+var parsedComponents = map[string]bool{
+	string(ics.ComponentVEvent):    true,
+	string(ics.ComponentVTimezone): true,
+	string(ics.ComponentVJournal):  true,
+}
+
+// dateParameters are the parameters read from date and date-time properties
+var dateParameters = map[string]bool{"VALUE": true, "TZID": true}
+
+// parsedParameters lists the parameters the parser reads, by property.
+// Parameters of other properties are reported with the property.
+var parsedParameters = map[string]map[string]bool{
+	"DTSTART": dateParameters, "DTEND": dateParameters, "RECURRENCE-ID": dateParameters,
+	"RDATE": dateParameters, "EXDATE": dateParameters,
+	"RRULE":                         {"VALUE": true},
+	"EXRULE":                        {"VALUE": true},
+	"ORGANIZER":                     {"CN": true},
+	"ATTENDEE":                      {"CN": true, "PARTSTAT": true, "ROLE": true},
+	"ATTACH":                        {"FMTTYPE": true, "FILENAME": true, "SIZE": true, "ENCODING": true, "VALUE": true},
+	"RELATED-TO":                    {"RELTYPE": true},
+	propertyConference:              {"LABEL": true, "FEATURE": true, "VALUE": true},
+	propertyAppleStructuredLocation: {"X-TITLE": true, "X-ADDRESS": true, "VALUE": true},
+}
+
+// unmapped returns the properties, and the parameters of mapped
+// properties, that the parser drops from a VEVENT or VJOURNAL
+func unmapped(props []ics.IANAProperty) (properties, parameters map[string]int) {
+	for _, prop := range props {
+		name := strings.ToUpper(prop.IANAToken)
+		if !parsedProperties[name] {
+			if properties == nil {
+				properties = make(map[string]int)
+			}
+			properties[name]++
+			continue
+		}
+		for param := range prop.ICalParameters {
+			if param = strings.ToUpper(param); !parsedParameters[name][param] {
+				if parameters == nil {
+					parameters = make(map[string]int)
+				}
+				parameters[name+";"+param]++
+			}
+		}
+	}
+	return properties, parameters
+}
+
+// account records the data dropped from a converted VEVENT or VJOURNAL in
+// the report and the hooks, or rejects it in strict mode
+func (c *Converter) account(ctx context.Context, report *ConversionReport, uid string, props []ics.IANAProperty) error {
+	properties, parameters := unmapped(props)
+	if c.Strict && len(properties)+len(parameters) > 0 {
+		names := append(sortedKeys(properties), sortedKeys(parameters)...)
+		return fmt.Errorf("%w: %s", ErrUnmapped, strings.Join(names, ", "))
+	}
+	addCounts(&report.Properties, properties)
+	addCounts(&report.Parameters, parameters)
+	c.reportUnsupported(ctx, uid, properties, parameters)
+	return nil
+}
+
+// accountComponents records the components of a calendar the parser
+// skips, or rejects the calendar in strict mode
+func (c *Converter) accountComponents(ctx context.Context, report *ConversionReport, cal *ics.Calendar) error {
+	components := make(map[string]int)
+	for _, component := range cal.Components {
+		name := componentName(component)
+		if !parsedComponents[name] || (name == string(ics.ComponentVJournal) && c.Journals == JournalSkip) {
+			components[name]++
+		}
+	}
+	if c.Strict && len(components) > 0 {
+		return fmt.Errorf("%w: skipped components %s", ErrUnmapped, strings.Join(sortedKeys(components), ", "))
+	}
+	addCounts(&report.Components, components)
+	c.reportDroppedComponents(ctx, components)
+	return nil
+}
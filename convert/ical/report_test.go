@@ -0,0 +1,132 @@
+package ical
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/airtrafik/jscal/convert"
+)
+
+const reportCalendar = "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//Test//EN\r\n" +
+	"BEGIN:VEVENT\r\nUID:report-1\r\nDTSTAMP:20250301T000000Z\r\nDTSTART;TZID=Europe/Berlin:20250303T090000\r\n" +
+	"SUMMARY;LANGUAGE=de:Besprechung\r\nRESOURCES:Projector\r\nRESOURCES:Whiteboard\r\n" +
+	"ATTENDEE;CN=Ann;RSVP=TRUE;X-NUM-GUESTS=1:mailto:ann@example.com\r\n" +
+	"ATTENDEE;CN=Bob;RSVP=TRUE:mailto:bob@example.com\r\nEND:VEVENT\r\n" +
+	"BEGIN:VTODO\r\nUID:todo-1\r\nSUMMARY:Chores\r\nEND:VTODO\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseAllWithReport(t *testing.T) {
+	events, report, err := New().ParseAllWithReport([]byte(reportCalendar))
+	if err != nil || len(events) != 1 {
+		t.Fatalf("Expected 1 event, got %d and %v", len(events), err)
+	}
+
+	expected := &ConversionReport{
+		Properties: map[string]int{"RESOURCES": 2},
+		Parameters: map[string]int{"SUMMARY;LANGUAGE": 1, "ATTENDEE;RSVP": 2, "ATTENDEE;X-NUM-GUESTS": 1},
+		Components: map[string]int{"VTODO": 1},
+	}
+	if !reflect.DeepEqual(report, expected) {
+		t.Errorf("Expected report %+v, got %+v", expected, report)
+	}
+	if report.Empty() {
+		t.Error("Expected a non-empty report")
+	}
+
+	lines := "VTODO: 1\nRESOURCES: 2\nATTENDEE;RSVP: 2\nATTENDEE;X-NUM-GUESTS: 1\nSUMMARY;LANGUAGE: 1\n"
+	if got := report.String(); got != lines {
+		t.Errorf("Expected report %q, got %q", lines, got)
+	}
+}
+
+func TestParseAllWithReportEmpty(t *testing.T) {
+	data := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//Test//EN\r\n" +
+		"BEGIN:VEVENT\r\nUID:clean-1\r\nDTSTAMP:20250301T000000Z\r\nDTSTART;VALUE=DATE:20250303\r\n" +
+		"SUMMARY:Holiday\r\nATTENDEE;CN=Ann;PARTSTAT=ACCEPTED:mailto:ann@example.com\r\nEND:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	_, report, err := New().ParseAllWithReport([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseAllWithReport failed: %v", err)
+	}
+	if !report.Empty() || report.String() != "" {
+		t.Errorf("Expected an empty report, got %q", report.String())
+	}
+}
+
+func TestStrict(t *testing.T) {
+	event := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//Test//EN\r\n" +
+		"BEGIN:VEVENT\r\nUID:strict-1\r\nDTSTAMP:20250301T000000Z\r\nDTSTART:20250303T090000Z\r\nSUMMARY:Clean\r\nEND:VEVENT\r\n"
+
+	tests := []struct {
+		name    string
+		data    string
+		uid     string
+		message string
+	}{
+		{
+			name:    "clean",
+			data:    event + "END:VCALENDAR\r\n",
+			message: "",
+		},
+		{
+			name: "unmapped property and parameter",
+			data: event + "BEGIN:VEVENT\r\nUID:strict-2\r\nDTSTAMP:20250301T000000Z\r\nDTSTART:20250304T090000Z\r\n" +
+				"RESOURCES:Projector\r\nATTENDEE;RSVP=TRUE:mailto:ann@example.com\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n",
+			uid:     "strict-2",
+			message: "failed to convert event strict-2 (line 10): unmapped iCalendar data: RESOURCES, ATTENDEE;RSVP",
+		},
+		{
+			name:    "skipped component",
+			data:    event + "BEGIN:VTODO\r\nUID:todo-1\r\nEND:VTODO\r\nEND:VCALENDAR\r\n",
+			message: "unmapped iCalendar data: skipped components VTODO",
+		},
+		{
+			name:    "skipped journal",
+			data:    event + "BEGIN:VJOURNAL\r\nUID:journal-1\r\nEND:VJOURNAL\r\nEND:VCALENDAR\r\n",
+			message: "unmapped iCalendar data: skipped components VJOURNAL",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Converter{Strict: true}
+			events, err := c.ParseAll([]byte(tt.data))
+			if tt.message == "" {
+				if err != nil || len(events) != 1 {
+					t.Fatalf("Expected 1 event, got %d and %v", len(events), err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrUnmapped) {
+				t.Fatalf("Expected ErrUnmapped, got %v", err)
+			}
+			if err.Error() != tt.message {
+				t.Errorf("Expected error %q, got %q", tt.message, err.Error())
+			}
+			var convErr *convert.ConversionError
+			if errors.As(err, &convErr) != (tt.uid != "") || (convErr != nil && convErr.UID != tt.uid) {
+				t.Errorf("Expected a ConversionError for %q, got %v", tt.uid, err)
+			}
+		})
+	}
+}
+
+func TestStrictLenient(t *testing.T) {
+	data := strings.Replace(reportCalendar, "BEGIN:VTODO\r\nUID:todo-1\r\nSUMMARY:Chores\r\nEND:VTODO\r\n",
+		"BEGIN:VEVENT\r\nUID:report-2\r\nDTSTAMP:20250301T000000Z\r\nDTSTART:20250304T090000Z\r\nEND:VEVENT\r\n", 1)
+
+	c := &Converter{Strict: true}
+	events, errs, err := c.ParseAllLenient([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseAllLenient failed: %v", err)
+	}
+	if len(events) != 1 || events[0].UID != "report-2" {
+		t.Fatalf("Expected only report-2 to convert, got %d events", len(events))
+	}
+	if len(errs) != 1 || errs[0].UID != "report-1" || !errors.Is(errs[0], ErrUnmapped) {
+		t.Errorf("Expected report-1 to be skipped as unmapped, got %v", errs)
+	}
+}
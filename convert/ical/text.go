@@ -0,0 +1,289 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/airtrafik/jscal"
+	ics "github.com/arran4/golang-ical"
+)
+
+// Text handling. golang-ical escapes TEXT property values (RFC 5545
+// section 3.3.11) when serializing and unescapes them when parsing, so
+// property values are passed to it unescaped. What it doesn't cover is
+// handled here: list values, parameter values and folding.
+
+// maxLineOctets is the maximum length of a content line, excluding the
+// line break (RFC 5545 section 3.1)
+const maxLineOctets = 75
+
+// escapedCommaMarker stands in for the escaped commas of CATEGORIES values.
+// golang-ical unescapes the whole value, after which an escaped comma in a
+// category can't be told from one separating two categories;
+// markEscapedCommas replaces them before parsing and splitTextList turns
+// them back into commas in the items.
+const escapedCommaMarker = "\x1d"
+
+// splitTextList splits a CATEGORIES value unescaped by golang-ical on the
+// commas that weren't escaped, dropping empty items. Escaped commas must
+// have been marked by markEscapedCommas.
+func splitTextList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.ReplaceAll(item, `\`+escapedCommaMarker, ",")
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// markEscapedCommas replaces the comma of every escaped comma in the values
+// of CATEGORIES properties with escapedCommaMarker, keeping the backslash,
+// which golang-ical then leaves in place. Markers already in those values
+// are removed first, so that only the marked commas turn into commas.
+// Lines may end in CRLF or a bare LF; the line endings are kept.
+func markEscapedCommas(data []byte) []byte {
+	if !bytes.Contains(data, []byte(`\`)) && !bytes.Contains(data, []byte(escapedCommaMarker)) {
+		return data
+	}
+	lines := strings.Split(string(data), "\n")
+	categories, inValue, inQuotes, escaped := false, false, false, false
+	for i, line := range lines {
+		start := 0
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			// Continuation lines belong to the property before them
+			start = 1
+		} else {
+			categories = isCategoriesLine(line)
+			inValue, inQuotes, escaped = false, false, false
+		}
+		if !categories {
+			continue
+		}
+		b := []byte(line[:start])
+		for j := start; j < len(line); j++ {
+			c := line[j]
+			switch {
+			case c == '\r' && j == len(line)-1:
+				// The CR of a CRLF line ending
+			case !inValue:
+				if c == '"' {
+					inQuotes = !inQuotes
+				} else if c == ':' && !inQuotes {
+					inValue = true
+				}
+			case c == escapedCommaMarker[0]:
+				continue
+			case escaped:
+				if c == ',' {
+					c = escapedCommaMarker[0]
+				}
+				escaped = false
+			case c == '\\':
+				escaped = true
+			}
+			b = append(b, c)
+		}
+		lines[i] = string(b)
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+// isCategoriesLine reports whether a content line starts a CATEGORIES
+// property
+func isCategoriesLine(line string) bool {
+	const name = "CATEGORIES"
+	return len(line) > len(name) && strings.EqualFold(line[:len(name)], name) &&
+		(line[len(name)] == ':' || line[len(name)] == ';')
+}
+
+// textParam returns the first value of a text parameter such as CN,
+// decoded per RFC 6868
+func textParam(prop *ics.IANAProperty, name string) (string, bool) {
+	values := prop.ICalParameters[name]
+	if len(values) == 0 || values[0] == "" {
+		return "", false
+	}
+	return decodeParamValue(values[0]), true
+}
+
+// textParamValue returns a text parameter such as CN for writing, its value
+// encoded per RFC 6868
+func textParamValue(name, value string) *ics.KeyValues {
+	return &ics.KeyValues{Key: name, Value: []string{encodeParamValue(value)}}
+}
+
+// serialize writes a calendar as content lines with CRLF line endings. The
+// lines are written here rather than by golang-ical, which escapes
+// parameter values with backslashes where RFC 5545 quotes them, and would
+// escape the semicolons separating the parts of REQUEST-STATUS values. The
+// result isn't folded; see foldLines.
+func serialize(cal *ics.Calendar) []byte {
+	var b bytes.Buffer
+	b.WriteString("BEGIN:VCALENDAR\r\n")
+	for _, prop := range cal.CalendarProperties {
+		writeProperty(&b, &prop.BaseProperty)
+	}
+	for _, component := range cal.Components {
+		writeComponent(&b, component)
+	}
+	b.WriteString("END:VCALENDAR\r\n")
+	return b.Bytes()
+}
+
+// writeComponent writes a component, its properties and its subcomponents
+func writeComponent(b *bytes.Buffer, component ics.Component) {
+	var name ics.ComponentType
+	switch c := component.(type) {
+	case *ics.VEvent:
+		name = ics.ComponentVEvent
+	case *ics.VTodo:
+		name = ics.ComponentVTodo
+	case *ics.VJournal:
+		name = ics.ComponentVJournal
+	case *ics.VBusy:
+		name = ics.ComponentVFreeBusy
+	case *ics.VTimezone:
+		name = ics.ComponentVTimezone
+	case *ics.VAlarm:
+		name = ics.ComponentVAlarm
+	case *ics.Standard:
+		name = ics.ComponentStandard
+	case *ics.Daylight:
+		name = ics.ComponentDaylight
+	case *ics.GeneralComponent:
+		name = ics.ComponentType(c.Token)
+	default:
+		return
+	}
+
+	b.WriteString("BEGIN:" + string(name) + "\r\n")
+	properties := component.UnknownPropertiesIANAProperties()
+	for i := range properties {
+		writeProperty(b, &properties[i].BaseProperty)
+	}
+	for _, sub := range component.SubComponents() {
+		writeComponent(b, sub)
+	}
+	b.WriteString("END:" + string(name) + "\r\n")
+}
+
+// writeProperty writes a content line. Parameter values holding characters
+// that end an unquoted value, or an apostrophe, are quoted; text parameters
+// must have been encoded with encodeParamValue, so they hold no DQUOTEs.
+// TEXT values are escaped, except for REQUEST-STATUS, whose value
+// requestStatusValue escapes part by part.
+func writeProperty(b *bytes.Buffer, prop *ics.BaseProperty) {
+	b.WriteString(prop.IANAToken)
+	for _, key := range sortedKeys(prop.ICalParameters) {
+		b.WriteString(";" + key + "=")
+		for i, value := range prop.ICalParameters[key] {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			if strings.ContainsAny(value, ",;:'") || ics.Parameter(key).IsQuoted() {
+				value = `"` + strings.ReplaceAll(value, `"`, "") + `"`
+			}
+			b.WriteString(value)
+		}
+	}
+	b.WriteByte(':')
+
+	value := prop.Value
+	if prop.GetValueType() == ics.ValueDataTypeText && prop.IANAToken != string(ics.ComponentPropertyRequestStatus) {
+		value = ics.ToText(value)
+	}
+	b.WriteString(value)
+	b.WriteString("\r\n")
+}
+
+// encodeParamValue encodes the characters a parameter value can't hold,
+// even quoted, with the caret escapes of RFC 6868: ^^ for a caret, ^n for a
+// newline and ^' for a double quote
+func encodeParamValue(s string) string {
+	if !strings.ContainsAny(s, "^\n\r\"") {
+		return s
+	}
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.NewReplacer("^", "^^", "\n", "^n", "\r", "^n", `"`, "^'").Replace(s)
+}
+
+// decodeParamValue reverses encodeParamValue. A caret followed by any other
+// character is kept as is.
+func decodeParamValue(s string) string {
+	if !strings.Contains(s, "^") {
+		return s
+	}
+	return strings.NewReplacer("^^", "^", "^n", "\n", "^N", "\n", "^'", `"`).Replace(s)
+}
+
+// requestStatusValue returns the REQUEST-STATUS value of a request status,
+// its parts escaped as TEXT and separated by semicolons
+func requestStatusValue(rs *jscal.RequestStatus) string {
+	parts := []string{ics.ToText(rs.Code), ics.ToText(rs.Description)}
+	if rs.Extra != "" {
+		parts = append(parts, ics.ToText(rs.Extra))
+	}
+	return strings.Join(parts, ";")
+}
+
+// foldLines folds the content lines of serialized iCalendar data to at most
+// 75 octets, never splitting a UTF-8 sequence. Lines that are already folded
+// are unfolded first, so the result doesn't depend on how the data was
+// folded before. Lines may end in CRLF or a bare LF; the result uses CRLF.
+func foldLines(data []byte) []byte {
+	return fold(data, false)
+}
+
+// foldLinesKeepingEscapes folds like foldLines, but never between a
+// backslash and the character it escapes, which some clients don't unfold
+// correctly
+func foldLinesKeepingEscapes(data []byte) []byte {
+	return fold(data, true)
+}
+
+// fold implements foldLines and foldLinesKeepingEscapes
+func fold(data []byte, keepEscapes bool) []byte {
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+
+	var unfolded []string
+	for _, line := range lines {
+		line = strings.TrimSuffix(line, "\r")
+		if len(unfolded) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			unfolded[len(unfolded)-1] += line[1:]
+			continue
+		}
+		unfolded = append(unfolded, line)
+	}
+
+	var b strings.Builder
+	for _, line := range unfolded {
+		limit := maxLineOctets
+		for len(line) > limit {
+			cut := limit
+			for cut > 0 && !utf8.RuneStart(line[cut]) {
+				cut--
+			}
+			if keepEscapes {
+				escaped := cut
+				for escaped > 0 && line[escaped-1] == '\\' {
+					escaped--
+				}
+				// An odd run of backslashes ends in an escape's backslash
+				if (cut-escaped)%2 == 1 {
+					cut--
+				}
+			}
+			b.WriteString(line[:cut])
+			b.WriteString("\r\n ")
+			line = line[cut:]
+			// Continuation lines start with a space, which counts
+			limit = maxLineOctets - 1
+		}
+		b.WriteString(line)
+		b.WriteString("\r\n")
+	}
+	return []byte(b.String())
+}
@@ -0,0 +1,307 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/airtrafik/jscal"
+)
+
+func TestParamValueEncoding(t *testing.T) {
+	tests := []struct {
+		value   string
+		encoded string
+	}{
+		{"Jane Doe", "Jane Doe"},
+		{`Jane "JD" Doe`, "Jane ^'JD^' Doe"},
+		{"Line 1\nLine 2", "Line 1^nLine 2"},
+		{"x^2", "x^^2"},
+		{"Doe, Jane: Room 1; East", "Doe, Jane: Room 1; East"},
+	}
+
+	for _, tt := range tests {
+		if got := encodeParamValue(tt.value); got != tt.encoded {
+			t.Errorf("encodeParamValue(%q): expected %q, got %q", tt.value, tt.encoded, got)
+		}
+		if got := decodeParamValue(tt.encoded); got != tt.value {
+			t.Errorf("decodeParamValue(%q): expected %q, got %q", tt.encoded, tt.value, got)
+		}
+	}
+}
+
+func TestFoldLines(t *testing.T) {
+	long := "DESCRIPTION:" + strings.Repeat("äöü", 40)
+	input := "BEGIN:VEVENT\r\nSUMMARY:Short\r\nX-FOLDED:abc\r\n def\r\n" + long + "\r\nEND:VEVENT\r\n"
+
+	output := string(foldLines([]byte(input)))
+	if !strings.Contains(output, "X-FOLDED:abcdef\r\n") {
+		t.Errorf("Expected short folded line to be unfolded:\n%s", output)
+	}
+
+	// Serializers writing bare LF line endings get CRLF
+	lf := string(foldLines([]byte(strings.ReplaceAll(input, "\r\n", "\n"))))
+	if lf != output {
+		t.Errorf("Expected LF input to fold like CRLF input, got:\n%q", lf)
+	}
+
+	var unfolded strings.Builder
+	for i, line := range strings.Split(strings.TrimSuffix(output, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("Line %d is %d octets long: %q", i, len(line), line)
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("Line %d splits a UTF-8 sequence: %q", i, line)
+		}
+		if strings.HasPrefix(line, " ") {
+			unfolded.WriteString(line[1:])
+		} else {
+			unfolded.WriteString("\n" + line)
+		}
+	}
+	if !strings.Contains(unfolded.String(), "\n"+long+"\n") {
+		t.Errorf("Expected the long line to unfold to the original")
+	}
+}
+
+func TestQuotedParamValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"Jane Doe", "ATTENDEE;CN=Jane Doe;"},
+		{"Smith, John: Boss", `ATTENDEE;CN="Smith, John: Boss";`},
+		{"Team; East", `ATTENDEE;CN="Team; East";`},
+		{"O'Brien", `ATTENDEE;CN="O'Brien";`},
+		{`Doe, Jane "JD"`, `ATTENDEE;CN="Doe, Jane ^'JD^'";`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := jscal.NewEvent("quoted@example.com", "Quoted")
+			event.AddParticipant("p1@example.com", jscal.NewParticipant(tt.name, "p1@example.com"))
+
+			converter := New()
+			output, err := converter.Format(event)
+			if err != nil {
+				t.Fatal(err)
+			}
+			unfolded := strings.ReplaceAll(string(output), "\r\n ", "")
+			if !strings.Contains(unfolded, "\r\n"+tt.expected) {
+				t.Errorf("Expected %s in output:\n%s", tt.expected, output)
+			}
+
+			parsed, err := converter.Parse(output)
+			if err != nil {
+				t.Fatalf("Parse of output failed: %v", err)
+			}
+			if p := parsed.Participants["p1@example.com"]; p == nil || p.Name == nil || *p.Name != tt.name {
+				t.Errorf("Expected name %q, got %+v", tt.name, p)
+			}
+		})
+	}
+}
+
+func TestFormatLineEndings(t *testing.T) {
+	event := jscal.NewEvent("lines@example.com", "Line endings")
+	event.SetDescription(strings.Repeat("A long description. ", 10))
+
+	output, err := New().Format(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := string(output)
+	if strings.Count(data, "\n") != strings.Count(data, "\r\n") {
+		t.Errorf("Expected only CRLF line endings, got:\n%q", data)
+	}
+	for _, line := range []string{"BEGIN:VCALENDAR\r\n", "\r\nBEGIN:VEVENT\r\n", "\r\nSUMMARY:Line endings\r\n", "\r\nEND:VCALENDAR\r\n"} {
+		if !strings.Contains(data, line) {
+			t.Errorf("Expected %q in output:\n%s", line, data)
+		}
+	}
+}
+
+func TestTextRoundTrip(t *testing.T) {
+	event := jscal.NewEvent("text@example.com", "Lunch, then review; maybe")
+	event.Description = jscal.String("Agenda:\n1. Budget, Q3\n2. Path C:\\temp\\new")
+	event.AddLocation("1", jscal.NewLocation("Café, 2nd floor"))
+	event.AddCategory("Work")
+	event.AddCategory("Team; East")
+	event.AddParticipant("jd@example.com", jscal.NewParticipant(`Doe, Jane "JD"`, "jd@example.com"))
+
+	converter := New()
+	output, err := converter.Format(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		`SUMMARY:Lunch\, then review\; maybe`,
+		`DESCRIPTION:Agenda:\n1. Budget\, Q3\n2. Path C:\\temp\\new`,
+		`LOCATION:Café\, 2nd floor`,
+		`CATEGORIES:Team\; East`,
+		`CATEGORIES:Work`,
+	} {
+		if !strings.Contains(string(output), line+"\r\n") {
+			t.Errorf("Expected %s in output:\n%s", line, output)
+		}
+	}
+
+	parsed, err := converter.Parse(output)
+	if err != nil {
+		t.Fatalf("Parse of output failed: %v", err)
+	}
+	if *parsed.Title != *event.Title {
+		t.Errorf("Expected title %q, got %q", *event.Title, *parsed.Title)
+	}
+	if *parsed.Description != *event.Description {
+		t.Errorf("Expected description %q, got %q", *event.Description, *parsed.Description)
+	}
+	if name := *parsed.Locations["1"].Name; name != "Café, 2nd floor" {
+		t.Errorf("Expected location %q, got %q", "Café, 2nd floor", name)
+	}
+	if len(parsed.Categories) != 2 || !parsed.Categories["Team; East"] || !parsed.Categories["Work"] {
+		t.Errorf("Expected categories Team; East and Work, got %v", parsed.Categories)
+	}
+	if p := parsed.Participants["jd@example.com"]; p == nil || p.Name == nil || *p.Name != `Doe, Jane "JD"` {
+		t.Errorf("Expected participant name %q, got %+v", `Doe, Jane "JD"`, p)
+	}
+}
+
+func TestCategoriesEscapedCommas(t *testing.T) {
+	input := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:test\r\nBEGIN:VEVENT\r\nUID:categories@example.com\r\n" +
+		"DTSTART:20250101T100000Z\r\nCATEGORIES:Smith\\, John\r\nCATEGORIES:a\\,b,c\r\n" +
+		"CATEGORIES:Path C:\\\\,Folded\\\r\n , item\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+
+	converter := New()
+	event, err := converter.Parse([]byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"Smith, John", "a,b", "c", `Path C:\`, "Folded, item"}
+	if len(event.Categories) != len(expected) {
+		t.Errorf("Expected categories %q, got %v", expected, event.Categories)
+	}
+	for _, cat := range expected {
+		if !event.Categories[cat] {
+			t.Errorf("Expected category %q, got %v", cat, event.Categories)
+		}
+	}
+
+	// Split items keep the input's escapes
+	items, err := converter.SplitItems([]byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || !strings.Contains(string(items[0].Data), `CATEGORIES:Smith\, John`) {
+		t.Errorf("Expected the escaped comma in the item, got %q", items)
+	}
+
+	output, err := converter.Format(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{`CATEGORIES:Smith\, John`, `CATEGORIES:a\,b`, `CATEGORIES:c`} {
+		if !strings.Contains(string(output), line+"\r\n") {
+			t.Errorf("Expected %s in output:\n%s", line, output)
+		}
+	}
+	again, err := converter.Parse(output)
+	if err != nil {
+		t.Fatalf("Parse of output failed: %v", err)
+	}
+	if len(again.Categories) != len(event.Categories) {
+		t.Errorf("Expected categories %v after round trip, got %v", event.Categories, again.Categories)
+	}
+	for cat := range event.Categories {
+		if !again.Categories[cat] {
+			t.Errorf("Expected category %q after round trip, got %v", cat, again.Categories)
+		}
+	}
+}
+
+func TestControlBytesInValues(t *testing.T) {
+	// The bytes once used as markers are data like any other
+	event := jscal.NewEvent("control@example.com", "a\x1eb\x1fc")
+	event.SetDescription("Line\x1d, and more")
+	event.AddParticipant("p1@example.com", jscal.NewParticipant("Smith, J", "p1@example.com"))
+	event.SetRequestStatus("2.0;Success\x1e")
+
+	converter := New()
+	output, err := converter.Format(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(output), "CN=\"Smith, J\"") {
+		t.Errorf("Expected the quoted name in output:\n%s", output)
+	}
+	parsed, err := converter.Parse(output)
+	if err != nil {
+		t.Fatalf("Parse of output failed: %v", err)
+	}
+	if parsed.GetTitle() != event.GetTitle() || parsed.GetDescription() != event.GetDescription() {
+		t.Errorf("Expected title %q and description %q, got %q and %q",
+			event.GetTitle(), event.GetDescription(), parsed.GetTitle(), parsed.GetDescription())
+	}
+	if p := parsed.Participants["p1@example.com"]; p == nil || p.Name == nil || *p.Name != "Smith, J" {
+		t.Errorf("Expected the participant name to round-trip, got %+v", p)
+	}
+	if parsed.GetRequestStatus() != event.GetRequestStatus() {
+		t.Errorf("Expected requestStatus %q, got %q", event.GetRequestStatus(), parsed.GetRequestStatus())
+	}
+
+	// A marker byte in a CATEGORIES value doesn't turn into a comma
+	input := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:test\r\nBEGIN:VEVENT\r\nUID:categories@example.com\r\n" +
+		"DTSTART:20250101T100000Z\r\nCATEGORIES:a\\\x1db\\,c\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+	parsed, err = converter.Parse([]byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed.Categories) != 1 || !parsed.Categories[`a\b,c`] {
+		t.Errorf("Expected the single category %q, got %v", `a\b,c`, parsed.Categories)
+	}
+}
+
+func TestRequestStatusRoundTrip(t *testing.T) {
+	event := jscal.NewEvent("status@example.com", "Reply")
+	event.SetRequestStatus("3.1;Invalid property value, see below;DTSTART:96-Apr-01")
+
+	converter := New()
+	output, err := converter.Format(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	line := `REQUEST-STATUS:3.1;Invalid property value\, see below;DTSTART:96-Apr-01`
+	if !strings.Contains(string(output), line+"\r\n") {
+		t.Errorf("Expected %s in output:\n%s", line, output)
+	}
+
+	parsed, err := converter.Parse(output)
+	if err != nil {
+		t.Fatalf("Parse of output failed: %v", err)
+	}
+	if parsed.GetRequestStatus() != event.GetRequestStatus() {
+		t.Errorf("Expected requestStatus %q, got %q", event.GetRequestStatus(), parsed.GetRequestStatus())
+	}
+
+	// The first valid status of an iTIP reply is kept
+	input := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:test\r\nBEGIN:VEVENT\r\nUID:reply@example.com\r\n" +
+		"DTSTART:20250101T100000Z\r\nREQUEST-STATUS:bogus\r\nREQUEST-STATUS:2.0;Success\r\n" +
+		"REQUEST-STATUS:3.7;Invalid calendar user;ATTENDEE:mailto:x@example.com\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+	parsed, err = converter.Parse([]byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.GetRequestStatus() != "2.0;Success" {
+		t.Errorf("Expected requestStatus %q, got %q", "2.0;Success", parsed.GetRequestStatus())
+	}
+
+	// Invalid statuses aren't written
+	event.SetRequestStatus("Success")
+	output, err = converter.Format(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(output), "REQUEST-STATUS") {
+		t.Errorf("Expected no REQUEST-STATUS for an invalid status, got:\n%s", output)
+	}
+}
@@ -0,0 +1,182 @@
+package ical
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/airtrafik/jscal"
+	ics "github.com/arran4/golang-ical"
+)
+
+// loadTimeZone resolves a TZID to a location. Besides IANA names it accepts
+// the prefixed ids some producers write, such as
+// "/mozilla.org/20050126_1/America/New_York". It returns nil if the zone is
+// unknown.
+func loadTimeZone(tzid string) *time.Location {
+	tzid = strings.TrimSpace(tzid)
+	if tzid == "" {
+		return nil
+	}
+	if loc, err := time.LoadLocation(tzid); err == nil {
+		return loc
+	}
+
+	// Drop leading path segments until a known zone remains
+	parts := strings.Split(strings.Trim(tzid, "/"), "/")
+	for i := range parts {
+		if loc, err := time.LoadLocation(strings.Join(parts[i:], "/")); err == nil {
+			return loc
+		}
+	}
+	return nil
+}
+
+// eventLocation returns the location of an event's time zone, or nil if its
+// times are written in UTC: all-day and floating events, events in UTC and
+// events in a zone that isn't known
+func eventLocation(event *jscal.Event) *time.Location {
+	if event.TimeZone == nil || event.IsAllDay() {
+		return nil
+	}
+	switch *event.TimeZone {
+	case "", "UTC", "Etc/UTC":
+		return nil
+	}
+	if loc, err := time.LoadLocation(*event.TimeZone); err == nil {
+		return loc
+	}
+	return nil
+}
+
+// wallTime returns the wall clock time of t in loc
+func wallTime(t time.Time, loc *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+}
+
+// addVTimezones adds a VTIMEZONE for each time zone the events are written
+// in, covering the year of the earliest start in that zone
+func addVTimezones(cal *ics.Calendar, events []*jscal.Event) {
+	years := make(map[string]int)
+	locations := make(map[string]*time.Location)
+	for _, event := range events {
+		loc := eventLocation(event)
+		if loc == nil || event.Start == nil {
+			continue
+		}
+		year := event.Start.Time().Year()
+		if first, ok := years[*event.TimeZone]; !ok || year < first {
+			years[*event.TimeZone] = year
+		}
+		locations[*event.TimeZone] = loc
+	}
+
+	for _, tzid := range sortedKeys(years) {
+		cal.AddVTimezone(newVTimezone(tzid, locations[tzid], years[tzid]))
+	}
+}
+
+// newVTimezone builds the VTIMEZONE of a zone from its transitions in year.
+// The transitions repeat yearly with an RRULE if the next year has the same
+// ones, which holds for zones with stable daylight saving rules.
+func newVTimezone(tzid string, loc *time.Location, year int) *ics.VTimezone {
+	vtimezone := &ics.VTimezone{}
+	vtimezone.AddProperty(ics.ComponentPropertyTzid, tzid)
+
+	transitions := zoneTransitions(loc, year)
+	if len(transitions) == 0 {
+		name, offset := time.Date(year, 1, 1, 0, 0, 0, 0, loc).Zone()
+		standard := &ics.Standard{}
+		setObservance(&standard.ComponentBase, "19700101T000000", name, offset, offset, "")
+		vtimezone.Components = append(vtimezone.Components, standard)
+		return vtimezone
+	}
+
+	next := zoneTransitions(loc, year+1)
+	repeats := len(next) == len(transitions)
+	for i := range transitions {
+		if repeats && transitionRule(transitions[i]) != transitionRule(next[i]) {
+			repeats = false
+		}
+	}
+
+	for _, at := range transitions {
+		_, from := at.Add(-time.Second).Zone()
+		name, to := at.Zone()
+		start := at.In(time.FixedZone("", from)).Format("20060102T150405")
+		rrule := ""
+		if repeats {
+			rrule = transitionRule(at)
+		}
+
+		if at.IsDST() {
+			daylight := &ics.Daylight{}
+			setObservance(&daylight.ComponentBase, start, name, from, to, rrule)
+			vtimezone.Components = append(vtimezone.Components, daylight)
+		} else {
+			standard := &ics.Standard{}
+			setObservance(&standard.ComponentBase, start, name, from, to, rrule)
+			vtimezone.Components = append(vtimezone.Components, standard)
+		}
+	}
+	return vtimezone
+}
+
+// zoneTransitions returns the instants in year at which loc changes offset
+func zoneTransitions(loc *time.Location, year int) []time.Time {
+	var transitions []time.Time
+	t := time.Date(year, 1, 1, 0, 0, 0, 0, loc)
+	end := time.Date(year+1, 1, 1, 0, 0, 0, 0, loc)
+	for {
+		_, next := t.ZoneBounds()
+		if next.IsZero() || !next.Before(end) {
+			return transitions
+		}
+		transitions = append(transitions, next)
+		t = next
+	}
+}
+
+// transitionRule returns the yearly RRULE of a transition, e.g.
+// "FREQ=YEARLY;BYMONTH=3;BYDAY=2SU", using -1 for the last weekday of
+// the month
+func transitionRule(at time.Time) string {
+	_, from := at.Add(-time.Second).Zone()
+	local := at.In(time.FixedZone("", from))
+	daysInMonth := time.Date(local.Year(), local.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+
+	nth := (local.Day()-1)/7 + 1
+	if local.Day()+7 > daysInMonth {
+		nth = -1
+	}
+	weekday := strings.ToUpper(local.Weekday().String()[:2])
+	return fmt.Sprintf("FREQ=YEARLY;BYMONTH=%d;BYDAY=%d%s", int(local.Month()), nth, weekday)
+}
+
+// setObservance sets the properties of a STANDARD or DAYLIGHT component
+func setObservance(cb *ics.ComponentBase, start, name string, from, to int, rrule string) {
+	cb.AddProperty(ics.ComponentPropertyDtStart, start)
+	cb.AddProperty(ics.ComponentProperty(ics.PropertyTzoffsetfrom), formatUTCOffset(from))
+	cb.AddProperty(ics.ComponentProperty(ics.PropertyTzoffsetto), formatUTCOffset(to))
+	if name != "" {
+		cb.AddProperty(ics.ComponentProperty(ics.PropertyTzname), name)
+	}
+	if rrule != "" {
+		cb.AddProperty(ics.ComponentPropertyRrule, rrule)
+	}
+}
+
+// formatUTCOffset formats an offset in seconds as a UTC-OFFSET value, such
+// as -0500 or +053328
+func formatUTCOffset(offset int) string {
+	sign := "+"
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+	s := fmt.Sprintf("%s%02d%02d", sign, offset/3600, offset%3600/60)
+	if seconds := offset % 60; seconds != 0 {
+		s += fmt.Sprintf("%02d", seconds)
+	}
+	return s
+}
@@ -0,0 +1,151 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/airtrafik/jscal"
+	ics "github.com/arran4/golang-ical"
+)
+
+func TestLoadTimeZone(t *testing.T) {
+	tests := []struct {
+		tzid     string
+		expected string
+	}{
+		{"America/New_York", "America/New_York"},
+		{"/mozilla.org/20050126_1/America/New_York", "America/New_York"},
+		{"/Europe/Berlin", "Europe/Berlin"},
+		{"Not/A_Zone", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		loc := loadTimeZone(tt.tzid)
+		got := ""
+		if loc != nil {
+			got = loc.String()
+		}
+		if got != tt.expected {
+			t.Errorf("loadTimeZone(%q): expected %q, got %q", tt.tzid, tt.expected, got)
+		}
+	}
+}
+
+func TestNewVTimezone(t *testing.T) {
+	tests := []struct {
+		tzid     string
+		expected []string
+	}{
+		{
+			tzid: "America/New_York",
+			expected: []string{
+				"BEGIN:DAYLIGHT\r\nDTSTART:20250309T020000\r\nTZOFFSETFROM:-0500\r\nTZOFFSETTO:-0400\r\nTZNAME:EDT\r\nRRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=2SU\r\nEND:DAYLIGHT",
+				"BEGIN:STANDARD\r\nDTSTART:20251102T020000\r\nTZOFFSETFROM:-0400\r\nTZOFFSETTO:-0500\r\nTZNAME:EST\r\nRRULE:FREQ=YEARLY;BYMONTH=11;BYDAY=1SU\r\nEND:STANDARD",
+			},
+		},
+		{
+			tzid: "Europe/Berlin",
+			expected: []string{
+				"DTSTART:20250330T020000\r\nTZOFFSETFROM:+0100\r\nTZOFFSETTO:+0200\r\nTZNAME:CEST\r\nRRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=-1SU",
+				"DTSTART:20251026T030000\r\nTZOFFSETFROM:+0200\r\nTZOFFSETTO:+0100\r\nTZNAME:CET\r\nRRULE:FREQ=YEARLY;BYMONTH=10;BYDAY=-1SU",
+			},
+		},
+		{
+			tzid: "Asia/Tokyo",
+			expected: []string{
+				"BEGIN:STANDARD\r\nDTSTART:19700101T000000\r\nTZOFFSETFROM:+0900\r\nTZOFFSETTO:+0900\r\nTZNAME:JST\r\nEND:STANDARD",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tzid, func(t *testing.T) {
+			loc, err := time.LoadLocation(tt.tzid)
+			if err != nil {
+				t.Skipf("time zone database lacks %s: %v", tt.tzid, err)
+			}
+			cal := ics.NewCalendar()
+			cal.Components = append(cal.Components, newVTimezone(tt.tzid, loc, 2025))
+			output := cal.Serialize(ics.WithNewLineWindows)
+			if !strings.Contains(output, "TZID:"+tt.tzid+"\r\n") {
+				t.Errorf("Expected TZID:%s in output:\n%s", tt.tzid, output)
+			}
+			for _, expected := range tt.expected {
+				if !strings.Contains(output, expected) {
+					t.Errorf("Expected %q in output:\n%s", expected, output)
+				}
+			}
+		})
+	}
+}
+
+func TestTimeZoneRoundTrip(t *testing.T) {
+	// As parsed from JSON: the wall clock time in the event's time zone
+	event := jscal.NewEvent("sync@example.com", "Team Sync")
+	event.Start = jscal.NewLocalDateTime(time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC))
+	event.TimeZone = jscal.String("America/New_York")
+	event.Duration = jscal.String("PT30M")
+	rule := jscal.NewRecurrenceRule("weekly")
+	rule.Until = jscal.NewLocalDateTime(time.Date(2025, 3, 31, 23, 59, 59, 0, time.UTC))
+	event.RecurrenceRules = []jscal.RecurrenceRule{*rule}
+	event.RecurrenceOverrides = map[string]map[string]interface{}{
+		"2025-03-10T09:00:00": {"excluded": true},
+	}
+
+	converter := New()
+	output, err := converter.Format(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		"BEGIN:VTIMEZONE",
+		"TZID:America/New_York",
+		"DTSTART;TZID=America/New_York:20250303T090000",
+		"RRULE:FREQ=WEEKLY;UNTIL=20250401T035959Z",
+		"EXDATE;TZID=America/New_York:20250310T090000",
+	} {
+		if !strings.Contains(string(output), line+"\r\n") {
+			t.Errorf("Expected %s in output:\n%s", line, output)
+		}
+	}
+	if strings.Index(string(output), "BEGIN:VTIMEZONE") > strings.Index(string(output), "BEGIN:VEVENT") {
+		t.Errorf("Expected VTIMEZONE before VEVENT:\n%s", output)
+	}
+
+	parsed, err := converter.Parse(output)
+	if err != nil {
+		t.Fatalf("Parse of output failed: %v", err)
+	}
+	if parsed.Start.String() != "2025-03-03T09:00:00" {
+		t.Errorf("Expected start 2025-03-03T09:00:00, got %s", parsed.Start)
+	}
+	if parsed.TimeZone == nil || *parsed.TimeZone != "America/New_York" {
+		t.Errorf("Expected time zone America/New_York, got %v", parsed.TimeZone)
+	}
+	if until := parsed.RecurrenceRules[0].Until; until == nil || until.String() != "2025-03-31T23:59:59" {
+		t.Errorf("Expected until 2025-03-31T23:59:59, got %v", until)
+	}
+	if patch := parsed.RecurrenceOverrides["2025-03-10T09:00:00"]; patch["excluded"] != true {
+		t.Errorf("Expected 2025-03-10T09:00:00 to be excluded, got %v", parsed.RecurrenceOverrides)
+	}
+}
+
+func TestFormatUTCOffset(t *testing.T) {
+	tests := []struct {
+		offset   int
+		expected string
+	}{
+		{0, "+0000"},
+		{-5 * 3600, "-0500"},
+		{5*3600 + 30*60, "+0530"},
+		{3600 + 33*60 + 28, "+013328"},
+	}
+
+	for _, tt := range tests {
+		if got := formatUTCOffset(tt.offset); got != tt.expected {
+			t.Errorf("formatUTCOffset(%d): expected %s, got %s", tt.offset, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package convert
+
+import "github.com/airtrafik/jscal"
+
+// Item is a part of a batch that converts on its own, such as the VEVENTs
+// of an iCalendar calendar sharing a UID
+type Item struct {
+	Key  string // Identifies the item across runs, e.g. its UID
+	Line int    // Line the item starts on in the input, from 1; 0 if unknown
+	Data []byte // The item, for ParseItem
+}
+
+// Splitter is implemented by converters that can split a batch into items
+// without converting them, so callers can convert the items one at a time:
+// skipping those a previous run converted, or carrying on past one that
+// fails
+type Splitter interface {
+	// SplitItems returns the items of data in input order
+	SplitItems(data []byte) ([]Item, error)
+
+	// ParseItem converts an item returned by SplitItems
+	ParseItem(item Item) ([]*jscal.Event, error)
+}
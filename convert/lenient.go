@@ -0,0 +1,58 @@
+package convert
+
+import (
+	"fmt"
+
+	"github.com/airtrafik/jscal"
+)
+
+// ItemError reports an item of a batch that could not be converted
+type ItemError struct {
+	Index int    // Position of the item in the input, e.g. the VEVENT index, from 0
+	Line  int    // Line the item starts on, from 1; 0 if unknown
+	UID   string // UID of the item, if known
+	Err   error
+}
+
+// Error formats the error as "item 3 (line 42, UID 'x'): ..."
+func (e *ItemError) Error() string {
+	location := ""
+	if e.Line > 0 {
+		location = fmt.Sprintf("line %d", e.Line)
+	}
+	if e.UID != "" {
+		if location != "" {
+			location += ", "
+		}
+		location += fmt.Sprintf("UID '%s'", e.UID)
+	}
+	if location != "" {
+		return fmt.Sprintf("item %d (%s): %v", e.Index, location, e.Err)
+	}
+	return fmt.Sprintf("item %d: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *ItemError) Unwrap() error {
+	return e.Err
+}
+
+// LenientParser is implemented by converters that can skip the items of a
+// batch they fail to convert instead of aborting
+type LenientParser interface {
+	// ParseAllLenient returns the events that converted and an error for
+	// each item that didn't. The error is only set if the input as a whole
+	// can't be parsed.
+	ParseAllLenient(data []byte) ([]*jscal.Event, []*ItemError, error)
+}
+
+// ParseAllLenient parses data with c, skipping bad items if c implements
+// LenientParser. Other converters are all or nothing: a failure is
+// returned as the error.
+func ParseAllLenient(c Converter, data []byte) ([]*jscal.Event, []*ItemError, error) {
+	if lenient, ok := c.(LenientParser); ok {
+		return lenient.ParseAllLenient(data)
+	}
+	events, err := c.ParseAll(data)
+	return events, nil, err
+}
@@ -0,0 +1,66 @@
+package convert
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/airtrafik/jscal"
+)
+
+// lenientLines is a lineConverter that skips blank lines when lenient
+type lenientLines struct{ lineConverter }
+
+func (lenientLines) ParseAllLenient(data []byte) ([]*jscal.Event, []*ItemError, error) {
+	var events []*jscal.Event
+	var errs []*ItemError
+	for i, line := range strings.Split(string(data), "\n")[1:] {
+		if strings.TrimSpace(line) == "" {
+			errs = append(errs, &ItemError{Index: i, Line: i + 2, Err: errors.New("empty line")})
+			continue
+		}
+		events = append(events, newSourceEvent(strings.TrimSpace(line), "From lines"))
+	}
+	return events, errs, nil
+}
+
+func TestParseAllLenient(t *testing.T) {
+	data := []byte("LINES\na\n\nb")
+
+	events, errs, err := ParseAllLenient(lenientLines{}, data)
+	if err != nil {
+		t.Fatalf("ParseAllLenient failed: %v", err)
+	}
+	if len(events) != 2 || len(errs) != 1 {
+		t.Fatalf("Expected 2 events and 1 error, got %d and %d", len(events), len(errs))
+	}
+	if errs[0].Error() != "item 1 (line 3): empty line" {
+		t.Errorf("Unexpected error message %q", errs[0].Error())
+	}
+
+	// Converters without lenient support parse all or nothing
+	events, errs, err = ParseAllLenient(lineConverter{}, data)
+	if err != nil || len(events) != 3 || errs != nil {
+		t.Errorf("Expected fallback to ParseAll, got %d events, %v, %v", len(events), errs, err)
+	}
+}
+
+func TestItemError(t *testing.T) {
+	cause := errors.New("event missing UID")
+	tests := []struct {
+		err  *ItemError
+		want string
+	}{
+		{&ItemError{Index: 0, Err: cause}, "item 0: event missing UID"},
+		{&ItemError{Index: 2, UID: "x", Err: cause}, "item 2 (UID 'x'): event missing UID"},
+		{&ItemError{Index: 3, Line: 42, UID: "x", Err: cause}, "item 3 (line 42, UID 'x'): event missing UID"},
+	}
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("Expected %q, got %q", tt.want, got)
+		}
+		if !errors.Is(tt.err, cause) {
+			t.Errorf("Expected %v to wrap the cause", tt.err)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package convert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/airtrafik/jscal"
+)
+
+// Fetcher downloads the contents of a Group source URI
+type Fetcher interface {
+	Fetch(ctx context.Context, uri string) ([]byte, error)
+}
+
+// FetcherFunc adapts a function to the Fetcher interface
+type FetcherFunc func(ctx context.Context, uri string) ([]byte, error)
+
+// Fetch calls f(ctx, uri)
+func (f FetcherFunc) Fetch(ctx context.Context, uri string) ([]byte, error) {
+	return f(ctx, uri)
+}
+
+// HTTPFetcher fetches http, https and webcal sources
+type HTTPFetcher struct {
+	Client *http.Client // Defaults to http.DefaultClient
+	Accept string       // Accept header; defaults to JSCalendar, then iCalendar
+}
+
+// Validators identify the version of a source a server sent, for
+// conditional requests
+type Validators struct {
+	ETag         string
+	LastModified string
+}
+
+// Fetch downloads the source with a GET request. webcal URIs are fetched over https.
+func (f HTTPFetcher) Fetch(ctx context.Context, uri string) ([]byte, error) {
+	data, _, err := f.FetchIfModified(ctx, uri, Validators{})
+	return data, err
+}
+
+// FetchIfModified is like Fetch, but only downloads the source if it no
+// longer matches v. The data is nil if the server answered 304 Not
+// Modified. The validators of the response are returned for the next call.
+func (f HTTPFetcher) FetchIfModified(ctx context.Context, uri string, v Validators) ([]byte, Validators, error) {
+	if strings.HasPrefix(uri, "webcal://") {
+		uri = "https://" + strings.TrimPrefix(uri, "webcal://")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, v, fmt.Errorf("invalid source URI: %w", err)
+	}
+	accept := f.Accept
+	if accept == "" {
+		accept = "application/jscalendar+json, text/calendar;q=0.9, */*;q=0.1"
+	}
+	req.Header.Set("Accept", accept)
+	if v.ETag != "" {
+		req.Header.Set("If-None-Match", v.ETag)
+	}
+	if v.LastModified != "" {
+		req.Header.Set("If-Modified-Since", v.LastModified)
+	}
+
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, v, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, v, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, v, fmt.Errorf("fetching %s: unexpected status %s", uri, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, v, err
+	}
+	return data, Validators{ETag: resp.Header.Get("ETag"), LastModified: resp.Header.Get("Last-Modified")}, nil
+}
+
+// ChangeSummary reports how RefreshFromSource changed a Group's entries
+type ChangeSummary struct {
+	Added     []string `json:"added,omitempty"`   // UIDs of new entries
+	Updated   []string `json:"updated,omitempty"` // UIDs of entries whose content changed
+	Removed   []string `json:"removed,omitempty"` // UIDs of entries no longer in the source
+	Unchanged int      `json:"unchanged"`
+}
+
+// HasChanges returns true if any entry was added, updated or removed
+func (s *ChangeSummary) HasChanges() bool {
+	return len(s.Added)+len(s.Updated)+len(s.Removed) > 0
+}
+
+// String formats the summary as "N added, N updated, N removed, N unchanged"
+func (s *ChangeSummary) String() string {
+	return fmt.Sprintf("%d added, %d updated, %d removed, %d unchanged",
+		len(s.Added), len(s.Updated), len(s.Removed), s.Unchanged)
+}
+
+// RefreshFromSource re-downloads group.Source and reconciles the entries by
+// UID: new entries are added, changed entries replaced and entries missing
+// from the source removed. The source may be JSCalendar (a Group, a single
+// object or an array) or any format one of the converters detects, such as
+// iCalendar. If anything changed the group is touched, updating Updated and
+// incrementing its sequence. On error the group is left unmodified.
+func RefreshFromSource(ctx context.Context, group *jscal.Group, fetcher Fetcher, converters ...Converter) (*ChangeSummary, error) {
+	if group == nil {
+		return nil, fmt.Errorf("group is nil")
+	}
+	if group.Source == nil || *group.Source == "" {
+		return nil, fmt.Errorf("group %s has no source", group.UID)
+	}
+	if fetcher == nil {
+		fetcher = HTTPFetcher{}
+	}
+
+	data, err := fetcher.Fetch(ctx, *group.Source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch source %s: %w", *group.Source, err)
+	}
+	fetched, err := decodeSource(ctx, data, converters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse source %s: %w", *group.Source, err)
+	}
+
+	existing := make(map[string]jscal.CalendarObject, len(group.Entries))
+	for _, entry := range group.Entries {
+		if entry != nil {
+			existing[entry.GetUID()] = entry
+		}
+	}
+
+	summary := &ChangeSummary{}
+	entries := make([]jscal.CalendarObject, 0, len(fetched))
+	seen := make(map[string]bool, len(fetched))
+	for i, entry := range fetched {
+		if t := entry.GetType(); t != "Event" && t != "Task" {
+			return nil, fmt.Errorf("source entry %d has type %s, must be Event or Task", i, t)
+		}
+		uid := entry.GetUID()
+		if uid == "" {
+			return nil, fmt.Errorf("source entry %d has no uid", i)
+		}
+		if seen[uid] {
+			return nil, fmt.Errorf("duplicate UID '%s' in source", uid)
+		}
+		seen[uid] = true
+
+		current, ok := existing[uid]
+		switch {
+		case !ok:
+			summary.Added = append(summary.Added, uid)
+		case SameContent(current, entry):
+			// Keep the existing object so callers' references stay valid
+			entry = current
+			summary.Unchanged++
+		default:
+			summary.Updated = append(summary.Updated, uid)
+		}
+		entries = append(entries, entry)
+	}
+
+	for _, entry := range group.Entries {
+		if entry != nil && !seen[entry.GetUID()] {
+			summary.Removed = append(summary.Removed, entry.GetUID())
+		}
+	}
+
+	if summary.HasChanges() {
+		group.Entries = entries
+		group.Touch()
+	}
+	return summary, nil
+}
+
+// decodeSource parses downloaded source data into Event and Task entries
+func decodeSource(ctx context.Context, data []byte, converters []Converter) ([]jscal.CalendarObject, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, fmt.Errorf("source is empty")
+	}
+
+	switch trimmed[0] {
+	case '{':
+		obj, err := jscal.Parse(trimmed)
+		if err != nil {
+			return nil, err
+		}
+		if g, ok := obj.(*jscal.Group); ok {
+			return g.Entries, nil
+		}
+		return []jscal.CalendarObject{obj}, nil
+	case '[':
+		return jscal.ParseAll(trimmed)
+	}
+
+	for _, c := range converters {
+		if !c.Detect(trimmed) {
+			continue
+		}
+		events, err := ParseAllContext(ctx, c, trimmed)
+		if err != nil {
+			return nil, err
+		}
+		objects := make([]jscal.CalendarObject, 0, len(events))
+		for _, e := range events {
+			objects = append(objects, e)
+		}
+		return objects, nil
+	}
+	return nil, fmt.Errorf("unrecognized source format")
+}
+
+// SameContent reports whether two objects have the same JSON encoding, so
+// a refreshed copy that only differs in representation, such as an unset
+// instead of an empty map, is unchanged
+func SameContent(a, b jscal.CalendarObject) bool {
+	aData, errA := json.Marshal(a)
+	bData, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(aData, bData)
+}
@@ -0,0 +1,206 @@
+package convert
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/airtrafik/jscal"
+)
+
+// lineConverter is a test converter for "LINES" sources with one event UID per line
+type lineConverter struct{}
+
+func (lineConverter) Parse(data []byte) (*jscal.Event, error) { return nil, errors.New("unused") }
+func (lineConverter) Format(*jscal.Event) ([]byte, error)     { return nil, errors.New("unused") }
+func (lineConverter) FormatAll([]*jscal.Event) ([]byte, error) {
+	return nil, errors.New("unused")
+}
+func (lineConverter) Detect(data []byte) bool { return strings.HasPrefix(string(data), "LINES") }
+func (lineConverter) ParseAll(data []byte) ([]*jscal.Event, error) {
+	var events []*jscal.Event
+	for _, line := range strings.Split(string(data), "\n")[1:] {
+		events = append(events, newSourceEvent(strings.TrimSpace(line), "From lines"))
+	}
+	return events, nil
+}
+
+func newSourceEvent(uid, title string) *jscal.Event {
+	return &jscal.Event{
+		Type:  "Event",
+		UID:   uid,
+		Title: jscal.String(title),
+		Start: jscal.NewLocalDateTime(time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)),
+	}
+}
+
+func staticFetcher(body string) Fetcher {
+	return FetcherFunc(func(ctx context.Context, uri string) ([]byte, error) {
+		return []byte(body), nil
+	})
+}
+
+func TestRefreshFromSource(t *testing.T) {
+	group := jscal.NewGroup("feed", "Feed")
+	group.Source = jscal.String("https://example.com/feed.json")
+	for _, e := range []*jscal.Event{
+		newSourceEvent("keep", "Keep"),
+		newSourceEvent("change", "Old title"),
+		newSourceEvent("drop", "Drop"),
+	} {
+		if err := group.AddEntry(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	kept := group.GetEntry("keep")
+	sequence := *group.Sequence
+
+	source := `{"@type":"Group","uid":"feed","entries":[
+		{"@type":"Event","uid":"keep","title":"Keep","start":"2025-01-01T09:00:00"},
+		{"@type":"Event","uid":"change","title":"New title","start":"2025-01-01T09:00:00"},
+		{"@type":"Task","uid":"new-task","title":"New"}
+	]}`
+
+	summary, err := RefreshFromSource(context.Background(), group, staticFetcher(source))
+	if err != nil {
+		t.Fatalf("RefreshFromSource failed: %v", err)
+	}
+
+	if got := summary.String(); got != "1 added, 1 updated, 1 removed, 1 unchanged" {
+		t.Errorf("Unexpected summary %q", got)
+	}
+	if summary.Added[0] != "new-task" || summary.Updated[0] != "change" || summary.Removed[0] != "drop" {
+		t.Errorf("Unexpected summary UIDs %+v", summary)
+	}
+	if group.GetEntry("drop") != nil || group.GetEntry("new-task") == nil {
+		t.Errorf("Expected entries to be reconciled, got %d entries", len(group.Entries))
+	}
+	if title := group.GetEntry("change").(*jscal.Event).Title; *title != "New title" {
+		t.Errorf("Expected updated title, got %s", *title)
+	}
+	if group.GetEntry("keep") != kept {
+		t.Error("Expected unchanged entry to keep its identity")
+	}
+	if *group.Sequence != sequence+1 {
+		t.Errorf("Expected sequence %d, got %d", sequence+1, *group.Sequence)
+	}
+
+	// A second refresh with the same content changes nothing
+	summary, err = RefreshFromSource(context.Background(), group, staticFetcher(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.HasChanges() || *group.Sequence != sequence+1 {
+		t.Errorf("Expected no changes, got %s (sequence %d)", summary, *group.Sequence)
+	}
+}
+
+func TestRefreshFromSourceConverter(t *testing.T) {
+	group := jscal.NewGroup("lines", "Lines")
+	group.Source = jscal.String("https://example.com/feed.lines")
+
+	summary, err := RefreshFromSource(context.Background(), group, staticFetcher("LINES\na\nb"), lineConverter{})
+	if err != nil {
+		t.Fatalf("RefreshFromSource failed: %v", err)
+	}
+	if len(summary.Added) != 2 || group.CountEvents() != 2 {
+		t.Errorf("Expected 2 added events, got %s", summary)
+	}
+
+	if _, err := RefreshFromSource(context.Background(), group, staticFetcher("LINES\na")); err == nil {
+		t.Error("Expected error without a matching converter")
+	}
+}
+
+func TestRefreshFromSourceErrors(t *testing.T) {
+	ctx := context.Background()
+	group := jscal.NewGroup("errors", "Errors")
+	if _, err := RefreshFromSource(ctx, group, staticFetcher("[]")); err == nil || !strings.Contains(err.Error(), "no source") {
+		t.Errorf("Expected missing source error, got %v", err)
+	}
+
+	group.Source = jscal.String("https://example.com/feed.json")
+	if err := group.AddEntry(newSourceEvent("existing", "Existing")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		fetcher Fetcher
+		wantErr string
+	}{
+		{
+			name: "fetch failure",
+			fetcher: FetcherFunc(func(ctx context.Context, uri string) ([]byte, error) {
+				return nil, errors.New("connection refused")
+			}),
+			wantErr: "connection refused",
+		},
+		{name: "duplicate uid", fetcher: staticFetcher(`[{"@type":"Event","uid":"a","start":"2025-01-01T09:00:00"},{"@type":"Event","uid":"a","start":"2025-01-01T09:00:00"}]`), wantErr: "duplicate UID"},
+		{name: "nested group", fetcher: staticFetcher(`[{"@type":"Group","uid":"g","entries":[]}]`), wantErr: "must be Event or Task"},
+		{name: "empty", fetcher: staticFetcher("  "), wantErr: "source is empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := RefreshFromSource(ctx, group, tt.fetcher)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+			if group.GetEntry("existing") == nil {
+				t.Error("Expected group to be left unmodified")
+			}
+		})
+	}
+}
+
+func TestHTTPFetcher(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/feed.json" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	fetcher := HTTPFetcher{Client: server.Client()}
+	data, err := fetcher.Fetch(context.Background(), server.URL+"/feed.json")
+	if err != nil || string(data) != "[]" {
+		t.Errorf("Expected [], got %q (%v)", data, err)
+	}
+	if _, err := fetcher.Fetch(context.Background(), server.URL+"/missing"); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("Expected 404 error, got %v", err)
+	}
+}
+
+func TestHTTPFetcherIfModified(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "text/calendar" {
+			http.Error(w, "not acceptable", http.StatusNotAcceptable)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	fetcher := HTTPFetcher{Client: server.Client(), Accept: "text/calendar"}
+	data, v, err := fetcher.FetchIfModified(context.Background(), server.URL, Validators{})
+	if err != nil || string(data) != "[]" || v.ETag != `"v1"` {
+		t.Fatalf("Expected [] with ETag \"v1\", got %q %+v (%v)", data, v, err)
+	}
+
+	data, v, err = fetcher.FetchIfModified(context.Background(), server.URL, v)
+	if err != nil || data != nil || v.ETag != `"v1"` {
+		t.Errorf("Expected not modified, got %q %+v (%v)", data, v, err)
+	}
+}
@@ -0,0 +1,131 @@
+package convert
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Registration describes a converter made available by Register
+type Registration struct {
+	Name       string           // Canonical format name, e.g. "ical"
+	Aliases    []string         // Other accepted format names, e.g. "ics"
+	Extensions []string         // File extensions including the dot, e.g. ".ics"
+	New        func() Converter // Creates a converter instance
+
+	// Description is a one-line summary for help output, e.g.
+	// "iCalendar (RFC 5545)"
+	Description string
+}
+
+var (
+	registryMu    sync.RWMutex
+	registrations = make(map[string]*Registration) // canonical name -> registration
+	formatNames   = make(map[string]string)        // name or alias -> canonical name
+	extensions    = make(map[string]string)        // file extension -> canonical name
+)
+
+// Register makes a converter available by format name. Converter modules call
+// it from an init function, so importing the module is enough to enable it:
+//
+//	import _ "github.com/airtrafik/jscal/convert/ical"
+//
+// Names, aliases and extensions are case-insensitive. Register panics if the
+// registration has no name or constructor, or if a name or alias is taken.
+func Register(r Registration) {
+	if r.Name == "" || r.New == nil {
+		panic("convert: Register requires a name and a constructor")
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	names := append([]string{r.Name}, r.Aliases...)
+	for _, name := range names {
+		if _, dup := formatNames[strings.ToLower(name)]; dup {
+			panic("convert: Register called twice for format " + name)
+		}
+	}
+
+	canonical := strings.ToLower(r.Name)
+	registrations[canonical] = &r
+	for _, name := range names {
+		formatNames[strings.ToLower(name)] = canonical
+	}
+	for _, ext := range r.Extensions {
+		extensions[strings.ToLower(ext)] = canonical
+	}
+}
+
+// Lookup returns a new converter for a registered format name or alias
+func Lookup(format string) (Converter, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	canonical, ok := formatNames[strings.ToLower(format)]
+	if !ok {
+		return nil, false
+	}
+	return registrations[canonical].New(), true
+}
+
+// FormatForExtension returns the registered format name for a file extension
+func FormatForExtension(ext string) (string, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	format, ok := extensions[strings.ToLower(ext)]
+	return format, ok
+}
+
+// Detect returns the name of the first registered format, in name order,
+// whose converter recognizes the data
+func Detect(data []byte) (string, bool) {
+	for _, format := range Formats() {
+		if c, ok := Lookup(format); ok && c.Detect(data) {
+			return format, true
+		}
+	}
+	return "", false
+}
+
+// DetectAll returns the names of all registered formats, in name order,
+// whose converters recognize the data. More than one name means the data
+// is ambiguous, e.g. a format that is a dialect of another.
+func DetectAll(data []byte) []string {
+	var formats []string
+	for _, format := range Formats() {
+		if c, ok := Lookup(format); ok && c.Detect(data) {
+			formats = append(formats, format)
+		}
+	}
+	return formats
+}
+
+// ListFormats returns the registrations of all formats, sorted by name, for
+// help output and format pickers. The slices are copies.
+func ListFormats() []Registration {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	list := make([]Registration, 0, len(registrations))
+	for _, r := range registrations {
+		r := *r
+		r.Aliases = append([]string(nil), r.Aliases...)
+		r.Extensions = append([]string(nil), r.Extensions...)
+		list = append(list, r)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return strings.ToLower(list[i].Name) < strings.ToLower(list[j].Name)
+	})
+	return list
+}
+
+// Formats returns the canonical names of all registered formats, sorted
+func Formats() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registrations))
+	for name := range registrations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,120 @@
+package convert
+
+import (
+	"strings"
+	"testing"
+)
+
+// registerLines registers lineConverter for the duration of a test
+func registerLines(t *testing.T) {
+	t.Helper()
+	Register(Registration{
+		Name:       "lines",
+		Aliases:    []string{"txt-lines"},
+		Extensions: []string{".lines"},
+		New:        func() Converter { return lineConverter{} },
+
+		Description: "One event per line",
+	})
+	t.Cleanup(func() {
+		registryMu.Lock()
+		defer registryMu.Unlock()
+		delete(registrations, "lines")
+		delete(formatNames, "lines")
+		delete(formatNames, "txt-lines")
+		delete(extensions, ".lines")
+	})
+}
+
+func TestRegistry(t *testing.T) {
+	registerLines(t)
+
+	for _, name := range []string{"lines", "LINES", "txt-lines"} {
+		if c, ok := Lookup(name); !ok {
+			t.Errorf("Expected %s to be registered", name)
+		} else if _, isLines := c.(lineConverter); !isLines {
+			t.Errorf("Expected lineConverter for %s, got %T", name, c)
+		}
+	}
+	if _, ok := Lookup("unknown"); ok {
+		t.Error("Expected unknown format not to be found")
+	}
+
+	if format, ok := FormatForExtension(".LINES"); !ok || format != "lines" {
+		t.Errorf("Expected lines for .LINES, got %q", format)
+	}
+	if format, ok := Detect([]byte("LINES\na")); !ok || format != "lines" {
+		t.Errorf("Expected content to be detected as lines, got %q", format)
+	}
+	if _, ok := Detect([]byte("something else")); ok {
+		t.Error("Expected unrecognized content not to be detected")
+	}
+
+	if formats := DetectAll([]byte("LINES\na")); len(formats) != 1 || formats[0] != "lines" {
+		t.Errorf("Expected [lines] from DetectAll, got %v", formats)
+	}
+	if formats := DetectAll([]byte("something else")); len(formats) != 0 {
+		t.Errorf("Expected no formats from DetectAll, got %v", formats)
+	}
+
+	var listed *Registration
+	for _, r := range ListFormats() {
+		if r.Name == "lines" {
+			listed = &r
+		}
+	}
+	if listed == nil || listed.Description != "One event per line" || len(listed.Aliases) != 1 {
+		t.Fatalf("Expected lines in ListFormats, got %+v", listed)
+	}
+	listed.Aliases[0] = "changed"
+	for _, r := range ListFormats() {
+		if r.Name == "lines" && r.Aliases[0] != "txt-lines" {
+			t.Errorf("Expected ListFormats to return copies, got %v", r.Aliases)
+		}
+	}
+
+	found := false
+	for _, format := range Formats() {
+		found = found || format == "lines"
+	}
+	if !found {
+		t.Errorf("Expected lines in %v", Formats())
+	}
+}
+
+func TestRegisterPanics(t *testing.T) {
+	registerLines(t)
+
+	tests := []struct {
+		name         string
+		registration Registration
+		wantPanic    string
+	}{
+		{
+			name:         "duplicate alias",
+			registration: Registration{Name: "other", Aliases: []string{"Txt-Lines"}, New: func() Converter { return lineConverter{} }},
+			wantPanic:    "twice",
+		},
+		{
+			name:         "missing constructor",
+			registration: Registration{Name: "broken"},
+			wantPanic:    "requires",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				r := recover()
+				if msg, _ := r.(string); !strings.Contains(msg, tt.wantPanic) {
+					t.Errorf("Expected panic containing %q, got %v", tt.wantPanic, r)
+				}
+			}()
+			Register(tt.registration)
+		})
+	}
+
+	if _, ok := Lookup("other"); ok {
+		t.Error("Expected failed registration to leave the registry unchanged")
+	}
+}
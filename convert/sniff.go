@@ -0,0 +1,202 @@
+package convert
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+)
+
+// Confidence is how sure Sniff is about a format
+type Confidence int
+
+// Confidence levels, from no match to an unambiguous signature
+const (
+	ConfidenceNone   Confidence = iota // Nothing matched
+	ConfidenceLow                      // Only the file extension or a weak hint matched
+	ConfidenceMedium                   // The content is shaped like the format
+	ConfidenceHigh                     // The content starts with the format's signature
+)
+
+// String returns the level's name, e.g. "high"
+func (c Confidence) String() string {
+	switch c {
+	case ConfidenceLow:
+		return "low"
+	case ConfidenceMedium:
+		return "medium"
+	case ConfidenceHigh:
+		return "high"
+	}
+	return "none"
+}
+
+// sniffExtensions maps file extensions to the formats Sniff knows without
+// a registered converter
+var sniffExtensions = map[string]string{
+	".json": "json",
+	".jcal": "jcal",
+	".ics":  "ical",
+	".ical": "ical",
+	".ifb":  "ical",
+	".xcs":  "xcal",
+	".xml":  "xml",
+	".csv":  "csv",
+	".tsv":  "csv",
+}
+
+// icalProperties are property names whose lines mark iCalendar content
+// without a VCALENDAR, e.g. a pasted VEVENT
+var icalProperties = []string{"BEGIN", "END", "UID", "DTSTART", "DTEND", "DTSTAMP", "SUMMARY", "RRULE"}
+
+// Sniff guesses the format of data, optionally named filename, and how sure
+// it is. The content decides; the extension, registered ones included,
+// raises the confidence when it agrees and is used alone when the content
+// is inconclusive. Data is decoded with DecodeText first, so byte order
+// marks, UTF-16 and Windows-1252 don't get in the way.
+//
+// Besides registered format names, Sniff returns "json" (JSCalendar),
+// "jcal" (RFC 7265), "xcal" (RFC 6321), "xml" and "csv", or "" if nothing
+// matched.
+func Sniff(data []byte, filename string) (string, Confidence) {
+	format, confidence := SniffContent(data)
+	if confidence < ConfidenceMedium {
+		// Ask the registered converters about other formats
+		text, _ := DecodeText(data)
+		if formats := DetectAll(text); len(formats) > 0 {
+			format, confidence = formats[0], ConfidenceMedium
+		}
+	}
+
+	if filename == "" {
+		return format, confidence
+	}
+	ext := strings.ToLower(filepath.Ext(filename))
+	byExt, ok := FormatForExtension(ext)
+	if !ok {
+		byExt, ok = sniffExtensions[ext]
+	}
+	switch {
+	case !ok:
+		return format, confidence
+	case format == "":
+		return byExt, ConfidenceLow
+	case format == byExt:
+		return format, ConfidenceHigh
+	}
+	return format, confidence
+}
+
+// SniffContent is Sniff without a file name and without asking the
+// registered converters, for use in Converter.Detect implementations
+func SniffContent(data []byte) (string, Confidence) {
+	text, _ := DecodeText(data)
+	text = bytes.TrimSpace(text)
+	if len(text) == 0 {
+		return "", ConfidenceNone
+	}
+
+	switch text[0] {
+	case '{', '[':
+		return sniffJSON(text)
+	case '<':
+		return sniffXML(text)
+	}
+	if hasPrefixFold(text, "BEGIN:VCALENDAR") {
+		return "ical", ConfidenceHigh
+	}
+	if icalLines(text) >= 3 {
+		return "ical", ConfidenceMedium
+	}
+	if looksLikeCSV(text) {
+		return "csv", ConfidenceMedium
+	}
+	return "", ConfidenceNone
+}
+
+// sniffJSON tells jCal, a ["vcalendar", ...] array, from JSCalendar
+func sniffJSON(text []byte) (string, Confidence) {
+	confidence := ConfidenceHigh
+	if !json.Valid(text) {
+		confidence = ConfidenceMedium
+	}
+	if text[0] == '[' {
+		rest := bytes.TrimLeft(text[1:], " \t\r\n")
+		if hasPrefixFold(rest, `"vcalendar"`) || hasPrefixFold(rest, `"vevent"`) {
+			return "jcal", confidence
+		}
+	}
+	return "json", confidence
+}
+
+// sniffXML tells xCal, XML in the iCalendar namespace, from other XML
+func sniffXML(text []byte) (string, Confidence) {
+	head := text
+	if len(head) > 4096 {
+		head = head[:4096]
+	}
+	if bytes.Contains(head, []byte("urn:ietf:params:xml:ns:icalendar-2.0")) {
+		return "xcal", ConfidenceHigh
+	}
+	if hasPrefixFold(text, "<?xml") {
+		return "xml", ConfidenceMedium
+	}
+	return "xml", ConfidenceLow
+}
+
+// icalLines counts the lines of the first 50 that are iCalendar content
+// lines with a well-known property name
+func icalLines(text []byte) int {
+	count := 0
+	for i, line := range strings.SplitN(string(text), "\n", 51) {
+		if i == 50 {
+			break
+		}
+		name, _, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		name, _, _ = strings.Cut(name, ";")
+		for _, property := range icalProperties {
+			if strings.EqualFold(strings.TrimSpace(name), property) {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
+
+// looksLikeCSV reports whether the first lines have the same number of
+// commas, semicolons or tabs, and at least one
+func looksLikeCSV(text []byte) bool {
+	lines := strings.Split(strings.ReplaceAll(string(text), "\r\n", "\n"), "\n")
+	if len(lines) > 10 {
+		lines = lines[:10]
+	}
+	if len(lines) < 2 {
+		return false
+	}
+	for _, sep := range []string{",", ";", "\t"} {
+		fields := strings.Count(lines[0], sep)
+		if fields == 0 {
+			continue
+		}
+		consistent := true
+		for _, line := range lines[1:] {
+			if line != "" && strings.Count(line, sep) != fields {
+				consistent = false
+				break
+			}
+		}
+		if consistent {
+			return true
+		}
+	}
+	return false
+}
+
+// hasPrefixFold reports whether text starts with prefix, ignoring case
+func hasPrefixFold(text []byte, prefix string) bool {
+	return len(text) >= len(prefix) && strings.EqualFold(string(text[:len(prefix)]), prefix)
+}
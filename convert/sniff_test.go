@@ -0,0 +1,58 @@
+package convert
+
+import (
+	"testing"
+)
+
+func TestSniff(t *testing.T) {
+	ics := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nEND:VCALENDAR\r\n"
+	utf16le := []byte{0xFF, 0xFE}
+	for _, r := range ics {
+		utf16le = append(utf16le, byte(r), 0)
+	}
+
+	tests := []struct {
+		name       string
+		data       []byte
+		filename   string
+		format     string
+		confidence Confidence
+	}{
+		{"jscalendar", []byte(`{"@type": "Event"}`), "", "json", ConfidenceHigh},
+		{"jscalendar array", []byte(` [{"@type": "Event"}]`), "", "json", ConfidenceHigh},
+		{"broken json", []byte(`{"@type": `), "", "json", ConfidenceMedium},
+		{"jcal", []byte(`["vcalendar", [], []]`), "", "jcal", ConfidenceHigh},
+		{"ical", []byte(ics), "", "ical", ConfidenceHigh},
+		{"ical lowercase", []byte("begin:vcalendar\nend:vcalendar"), "", "ical", ConfidenceHigh},
+		{"ical with bom", append([]byte{0xEF, 0xBB, 0xBF}, ics...), "", "ical", ConfidenceHigh},
+		{"ical utf-16", utf16le, "", "ical", ConfidenceHigh},
+		{"pasted vevent", []byte("BEGIN:VEVENT\nUID:1\nDTSTART;TZID=Europe/Berlin:20250301T090000\nEND:VEVENT"), "", "ical", ConfidenceMedium},
+		{"xcal", []byte(`<?xml version="1.0"?><icalendar xmlns="urn:ietf:params:xml:ns:icalendar-2.0"/>`), "", "xcal", ConfidenceHigh},
+		{"xml", []byte(`<?xml version="1.0"?><feed/>`), "", "xml", ConfidenceMedium},
+		{"csv", []byte("Subject,Start Date,Start Time\nStandup,2025-03-01,09:00\n"), "", "csv", ConfidenceMedium},
+		{"extension agrees", []byte(ics), "cal.ics", "ical", ConfidenceHigh},
+		{"content wins", []byte(ics), "cal.json", "ical", ConfidenceHigh},
+		{"extension only", nil, "out.ICS", "ical", ConfidenceLow},
+		{"unknown", []byte("hello"), "notes.txt", "", ConfidenceNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, confidence := Sniff(tt.data, tt.filename)
+			if format != tt.format || confidence != tt.confidence {
+				t.Errorf("Expected %q with %s confidence, got %q with %s", tt.format, tt.confidence, format, confidence)
+			}
+		})
+	}
+}
+
+func TestSniffRegistered(t *testing.T) {
+	registerLines(t)
+
+	if format, confidence := Sniff([]byte("LINES\na"), ""); format != "lines" || confidence != ConfidenceMedium {
+		t.Errorf("Expected lines with medium confidence, got %q with %s", format, confidence)
+	}
+	if format, confidence := Sniff(nil, "x.lines"); format != "lines" || confidence != ConfidenceLow {
+		t.Errorf("Expected lines from the extension, got %q with %s", format, confidence)
+	}
+}
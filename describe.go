@@ -0,0 +1,256 @@
+package jscal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RecurrenceLocale holds the words and patterns Describe writes recurrence
+// rules with. Patterns are fmt formats.
+type RecurrenceLocale struct {
+	Every        string                  // Interval of one: "Every %s" with the unit
+	EveryN       string                  // Other intervals: "Every %d %s" with the interval and unit
+	EveryWeekday string                  // Weekly on Monday to Friday
+	Units        map[string][2]string    // Singular and plural unit of each frequency
+	Weekdays     map[time.Weekday]string // Names of the days
+	Months       [12]string              // Names of the months, January first
+	Ordinal      func(n int) string      // 1 is "1st", -1 is "last"
+	And          string                  // Joins the last two items of a list
+	Comma        string                  // Joins the other items of a list
+	NthWeekday   string                  // "the %s %s" with the ordinal and day
+	OnDays       string                  // " on %s" with the days
+	OnMonthDays  string                  // " on the %s" with the days of the month
+	OnYearDays   string                  // " on day %s of the year"
+	InMonths     string                  // " in %s" with the months
+	InWeeks      string                  // " in week %s" with the week numbers
+	AtTimes      string                  // " at %s" with the times of day
+	TimeLayout   string                  // Layout of times of day
+	SetPositions string                  // ", only the %s" with the ordinals of bySetPos
+	Until        string                  // " until %s" with the date
+	DateLayout   string                  // Layout of the until date
+	Count        string                  // ", %d times"
+	Once         string                  // A count of one
+}
+
+// EnglishRecurrence is the English recurrence locale, used for locales
+// without one
+var EnglishRecurrence = &RecurrenceLocale{
+	Every:        "Every %s",
+	EveryN:       "Every %d %s",
+	EveryWeekday: "Every weekday",
+	Units: map[string][2]string{
+		FrequencyYearly:   {"year", "years"},
+		FrequencyMonthly:  {"month", "months"},
+		FrequencyWeekly:   {"week", "weeks"},
+		FrequencyDaily:    {"day", "days"},
+		FrequencyHourly:   {"hour", "hours"},
+		FrequencyMinutely: {"minute", "minutes"},
+		FrequencySecondly: {"second", "seconds"},
+	},
+	Weekdays: map[time.Weekday]string{
+		time.Monday: "Monday", time.Tuesday: "Tuesday", time.Wednesday: "Wednesday",
+		time.Thursday: "Thursday", time.Friday: "Friday", time.Saturday: "Saturday", time.Sunday: "Sunday",
+	},
+	Months: [12]string{"January", "February", "March", "April", "May", "June",
+		"July", "August", "September", "October", "November", "December"},
+	Ordinal:      englishOrdinal,
+	And:          " and ",
+	Comma:        ", ",
+	NthWeekday:   "the %s %s",
+	OnDays:       " on %s",
+	OnMonthDays:  " on the %s",
+	OnYearDays:   " on day %s of the year",
+	InMonths:     " in %s",
+	InWeeks:      " in week %s",
+	AtTimes:      " at %s",
+	TimeLayout:   "15:04",
+	SetPositions: ", only the %s",
+	Until:        " until %s",
+	DateLayout:   "Jan 2, 2006",
+	Count:        ", %d times",
+	Once:         ", once",
+}
+
+// RecurrenceLocales are the recurrence locales by language tag, such as
+// "en" or "de-AT". Add to it to describe rules in other languages.
+var RecurrenceLocales = map[string]*RecurrenceLocale{
+	"en": EnglishRecurrence,
+}
+
+// englishOrdinal returns "1st", "2nd", "last", "2nd to last", ...
+func englishOrdinal(n int) string {
+	if n == -1 {
+		return "last"
+	}
+	if n < 0 {
+		return englishOrdinal(-n) + " to last"
+	}
+	suffix := "th"
+	if n%100 < 11 || n%100 > 13 {
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return strconv.Itoa(n) + suffix
+}
+
+// recurrenceLocale returns the recurrence locale for a language tag,
+// matching the exact tag first and then the primary language subtag (e.g.
+// "de" for "de-AT"), and English if there is none
+func recurrenceLocale(locale string) *RecurrenceLocale {
+	candidates := []string{locale}
+	if idx := strings.Index(locale, "-"); idx > 0 {
+		candidates = append(candidates, locale[:idx])
+	}
+	for _, candidate := range candidates {
+		for tag, l := range RecurrenceLocales {
+			if strings.EqualFold(tag, candidate) && l != nil {
+				return l
+			}
+		}
+	}
+	return EnglishRecurrence
+}
+
+// Describe returns a human-readable summary of the rule in the given
+// locale, e.g. "Every 2 weeks on Monday and Wednesday until Mar 31, 2025".
+// Locales without a RecurrenceLocale are described in English.
+func (r *RecurrenceRule) Describe(locale string) string {
+	l := recurrenceLocale(locale)
+
+	var b strings.Builder
+	units := l.Units[r.Frequency]
+	if units[0] == "" {
+		units = [2]string{r.Frequency, r.Frequency}
+	}
+	interval := r.GetInterval()
+	if interval < 1 {
+		interval = 1
+	}
+	workWeek := r.Frequency == FrequencyWeekly && interval == 1 && r.isWorkWeek() && len(r.BySetPos) == 0
+	switch {
+	case workWeek:
+		b.WriteString(l.EveryWeekday)
+	case interval == 1:
+		fmt.Fprintf(&b, l.Every, units[0])
+	default:
+		fmt.Fprintf(&b, l.EveryN, interval, units[1])
+	}
+
+	if len(r.ByDay) > 0 && !workWeek {
+		days := make([]string, len(r.ByDay))
+		for i, nday := range r.ByDay {
+			days[i] = l.Weekdays[weekdays[nday.Day]]
+			if days[i] == "" {
+				days[i] = nday.Day
+			}
+			if nday.NthOfPeriod != nil {
+				days[i] = fmt.Sprintf(l.NthWeekday, l.Ordinal(*nday.NthOfPeriod), days[i])
+			}
+		}
+		fmt.Fprintf(&b, l.OnDays, l.list(days))
+	}
+	if len(r.ByMonthDay) > 0 {
+		fmt.Fprintf(&b, l.OnMonthDays, l.list(l.ordinals(r.ByMonthDay)))
+	}
+	if len(r.ByYearDay) > 0 {
+		fmt.Fprintf(&b, l.OnYearDays, l.list(numbers(r.ByYearDay)))
+	}
+	if len(r.ByMonth) > 0 {
+		months := make([]string, len(r.ByMonth))
+		for i, value := range r.ByMonth {
+			months[i] = value
+			if n, err := strconv.Atoi(strings.TrimSuffix(value, "L")); err == nil && n >= 1 && n <= 12 {
+				months[i] = l.Months[n-1]
+			}
+		}
+		fmt.Fprintf(&b, l.InMonths, l.list(months))
+	}
+	if len(r.ByWeekNo) > 0 {
+		fmt.Fprintf(&b, l.InWeeks, l.list(numbers(r.ByWeekNo)))
+	}
+	if times := r.timesOfDay(l.TimeLayout); len(times) > 0 {
+		fmt.Fprintf(&b, l.AtTimes, l.list(times))
+	}
+	if len(r.BySetPos) > 0 {
+		fmt.Fprintf(&b, l.SetPositions, l.list(l.ordinals(r.BySetPos)))
+	}
+
+	if r.Until != nil {
+		fmt.Fprintf(&b, l.Until, r.Until.Time().Format(l.DateLayout))
+	} else if r.Count != nil {
+		if *r.Count == 1 {
+			b.WriteString(l.Once)
+		} else {
+			fmt.Fprintf(&b, l.Count, *r.Count)
+		}
+	}
+	return b.String()
+}
+
+// isWorkWeek reports whether the rule's days are Monday to Friday
+func (r *RecurrenceRule) isWorkWeek() bool {
+	if len(r.ByDay) != 5 {
+		return false
+	}
+	seen := make(map[string]bool)
+	for _, nday := range r.ByDay {
+		if nday.NthOfPeriod != nil {
+			return false
+		}
+		seen[nday.Day] = true
+	}
+	return seen[DayMonday] && seen[DayTuesday] && seen[DayWednesday] && seen[DayThursday] && seen[DayFriday]
+}
+
+// timesOfDay returns the times of day of byHour and byMinute, or nothing if
+// the rule has no byHour
+func (r *RecurrenceRule) timesOfDay(layout string) []string {
+	if len(r.ByHour) == 0 {
+		return nil
+	}
+	minutes := r.ByMinute
+	if len(minutes) == 0 {
+		minutes = []int{0}
+	}
+	var times []string
+	for _, hour := range sortedInts(r.ByHour) {
+		for _, minute := range sortedInts(minutes) {
+			times = append(times, time.Date(2000, 1, 1, hour, minute, 0, 0, time.UTC).Format(layout))
+		}
+	}
+	return times
+}
+
+// ordinals returns the ordinals of numbers
+func (l *RecurrenceLocale) ordinals(numbers []int) []string {
+	ordinals := make([]string, len(numbers))
+	for i, n := range numbers {
+		ordinals[i] = l.Ordinal(n)
+	}
+	return ordinals
+}
+
+// numbers formats numbers
+func numbers(values []int) []string {
+	formatted := make([]string, len(values))
+	for i, v := range values {
+		formatted[i] = strconv.Itoa(v)
+	}
+	return formatted
+}
+
+// list joins items as "a, b and c"
+func (l *RecurrenceLocale) list(items []string) string {
+	if len(items) < 2 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], l.Comma) + l.And + items[len(items)-1]
+}
@@ -0,0 +1,85 @@
+package jscal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDescribe(t *testing.T) {
+	until := NewLocalDateTime(time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC))
+	weekday := func(days ...string) []NDay {
+		ndays := make([]NDay, len(days))
+		for i, day := range days {
+			ndays[i] = NDay{Day: day}
+		}
+		return ndays
+	}
+
+	tests := []struct {
+		name     string
+		rule     RecurrenceRule
+		expected string
+	}{
+		{"daily", RecurrenceRule{Frequency: FrequencyDaily}, "Every day"},
+		{"interval", RecurrenceRule{Frequency: FrequencyDaily, Interval: Int(3)}, "Every 3 days"},
+		{
+			"weekly until",
+			RecurrenceRule{Frequency: FrequencyWeekly, Interval: Int(2), ByDay: weekday(DayMonday, DayWednesday), Until: until},
+			"Every 2 weeks on Monday and Wednesday until Mar 31, 2025",
+		},
+		{
+			"weekdays",
+			RecurrenceRule{Frequency: FrequencyWeekly, ByDay: weekday(DayMonday, DayTuesday, DayWednesday, DayThursday, DayFriday)},
+			"Every weekday",
+		},
+		{
+			"weekdays every other week",
+			RecurrenceRule{Frequency: FrequencyWeekly, Interval: Int(2), ByDay: weekday(DayMonday, DayTuesday, DayWednesday, DayThursday, DayFriday)},
+			"Every 2 weeks on Monday, Tuesday, Wednesday, Thursday and Friday",
+		},
+		{
+			"nth weekday",
+			RecurrenceRule{Frequency: FrequencyMonthly, ByDay: []NDay{nthDay(DayTuesday, 2), nthDay(DayFriday, -1)}},
+			"Every month on the 2nd Tuesday and the last Friday",
+		},
+		{"month days", RecurrenceRule{Frequency: FrequencyMonthly, ByMonthDay: []int{1, 15, -2}}, "Every month on the 1st, 15th and 2nd to last"},
+		{"months", RecurrenceRule{Frequency: FrequencyYearly, ByMonth: []string{"1", "7"}, ByMonthDay: []int{4}}, "Every year on the 4th in January and July"},
+		{"year days", RecurrenceRule{Frequency: FrequencyYearly, ByYearDay: []int{100}}, "Every year on day 100 of the year"},
+		{"week numbers", RecurrenceRule{Frequency: FrequencyYearly, ByWeekNo: []int{20}}, "Every year in week 20"},
+		{"times", RecurrenceRule{Frequency: FrequencyDaily, ByHour: []int{17, 9}, ByMinute: []int{30}}, "Every day at 09:30 and 17:30"},
+		{
+			"set positions",
+			RecurrenceRule{Frequency: FrequencyMonthly, ByDay: weekday(DayMonday, DayTuesday, DayWednesday, DayThursday, DayFriday), BySetPos: []int{-1}},
+			"Every month on Monday, Tuesday, Wednesday, Thursday and Friday, only the last",
+		},
+		{"count", RecurrenceRule{Frequency: FrequencyWeekly, Count: Int(10)}, "Every week, 10 times"},
+		{"once", RecurrenceRule{Frequency: FrequencyYearly, Count: Int(1)}, "Every year, once"},
+		{"ordinals", RecurrenceRule{Frequency: FrequencyMonthly, ByMonthDay: []int{11, 12, 13, 21, 22, 23}}, "Every month on the 11th, 12th, 13th, 21st, 22nd and 23rd"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Describe("en"); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDescribeLocale(t *testing.T) {
+	german := *EnglishRecurrence
+	german.Every = "Jede %s"
+	german.Units = map[string][2]string{FrequencyWeekly: {"Woche", "Wochen"}}
+	german.Weekdays = map[time.Weekday]string{time.Monday: "Montag"}
+	german.OnDays = " am %s"
+	RecurrenceLocales["de"] = &german
+	defer delete(RecurrenceLocales, "de")
+
+	rule := RecurrenceRule{Frequency: FrequencyWeekly, ByDay: []NDay{{Day: DayMonday}}}
+	if got := rule.Describe("de-AT"); got != "Jede Woche am Montag" {
+		t.Errorf("Expected German description, got %q", got)
+	}
+	if got := rule.Describe("fr"); got != "Every week on Monday" {
+		t.Errorf("Expected English fallback, got %q", got)
+	}
+}
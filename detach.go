@@ -0,0 +1,70 @@
+package jscal
+
+import "fmt"
+
+// unpatchableProperties are the properties recurrenceOverrides must not
+// patch (RFC 8984 Section 4.3.5)
+var unpatchableProperties = map[string]bool{
+	"@type": true, "excludedRecurrenceRules": true, "method": true, "privacy": true,
+	"prodId": true, "recurrenceId": true, "recurrenceIdTimeZone": true, "recurrenceOverrides": true,
+	"recurrenceRules": true, "relatedTo": true, "replyTo": true, "sentBy": true,
+	"timeZones": true, "uid": true,
+}
+
+// DetachOccurrence returns the instance of the recurring event with the
+// given recurrence id as a standalone Event, like the overridden instances
+// CalDAV stores as separate VEVENTs: it has the master's uid, recurrenceId
+// and recurrenceIdTimeZone set, and the override patch applied. The event
+// itself is unchanged; see ReattachOccurrence for the way back.
+func (e *Event) DetachOccurrence(recurrenceId LocalDateTime) (*Event, error) {
+	instance, err := e.Instance(recurrenceId)
+	if err != nil {
+		return nil, err
+	}
+	if e.TimeZone != nil {
+		instance.RecurrenceIdTimeZone = String(*e.TimeZone)
+	}
+	return instance, nil
+}
+
+// ReattachOccurrence folds a detached instance of the event back into its
+// recurrenceOverrides: the patch for the instance's recurrenceId becomes
+// the difference between the instance and the one the rules produce.
+// Properties RFC 8984 doesn't allow overrides to patch, such as uid and
+// relatedTo, are ignored. An instance without differences removes the
+// override.
+func (e *Event) ReattachOccurrence(instance *Event) error {
+	if instance == nil || instance.RecurrenceId == nil {
+		return fmt.Errorf("instance has no recurrenceId")
+	}
+	if instance.UID != e.UID {
+		return fmt.Errorf("instance %s does not belong to event %s", instance.UID, e.UID)
+	}
+
+	id := LocalDateTime(wallClock(*instance.RecurrenceId))
+	base, err := instanceBase(e)
+	if err != nil {
+		return err
+	}
+	generated, err := newOccurrence(base, id, nil)
+	if err != nil {
+		return fmt.Errorf("event %s: %w", e.UID, err)
+	}
+	changes, err := generated.Event.Diff(instance)
+	if err != nil {
+		return err
+	}
+
+	patch := make(map[string]interface{})
+	for _, change := range changes {
+		if !unpatchableProperties[splitPointer(change.Path)[0]] {
+			patch[change.Path] = change.New
+		}
+	}
+
+	if len(patch) == 0 {
+		e.DeleteOverride(id)
+		return nil
+	}
+	return e.SetOverride(id, patch)
+}
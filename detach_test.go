@@ -0,0 +1,80 @@
+package jscal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDetachOccurrence(t *testing.T) {
+	event := newDailyStandup()
+	id, _ := ParseLocalDateTime("2025-03-04T09:00:00")
+
+	instance, err := event.DetachOccurrence(*id)
+	if err != nil {
+		t.Fatalf("DetachOccurrence failed: %v", err)
+	}
+	if instance.UID != event.UID || instance.RecurrenceId.String() != "2025-03-04T09:00:00" {
+		t.Errorf("Expected instance %s of %s, got %s of %s", id, event.UID, instance.RecurrenceId, instance.UID)
+	}
+	if instance.GetRecurrenceIdTimeZone() != "Europe/Berlin" {
+		t.Errorf("Expected recurrenceIdTimeZone Europe/Berlin, got %q", instance.GetRecurrenceIdTimeZone())
+	}
+	if instance.GetTitle() != "Standup (short)" || len(instance.RecurrenceRules) != 0 {
+		t.Errorf("Expected the override applied and no rules, got %q with %d rules", instance.GetTitle(), len(instance.RecurrenceRules))
+	}
+
+	excluded, _ := ParseLocalDateTime("2025-03-08T09:00:00")
+	if _, err := event.DetachOccurrence(*excluded); err == nil {
+		t.Error("Expected an error detaching an excluded instance")
+	}
+}
+
+func TestReattachOccurrence(t *testing.T) {
+	event := newDailyStandup()
+	id, _ := ParseLocalDateTime("2025-03-05T09:00:00")
+
+	instance, err := event.DetachOccurrence(*id)
+	if err != nil {
+		t.Fatalf("DetachOccurrence failed: %v", err)
+	}
+	instance.Title = String("Standup with demo")
+	instance.Start = NewLocalDateTime(time.Date(2025, 3, 5, 11, 0, 0, 0, time.UTC))
+	instance.AddLocation("room", &Location{Type: String("Location"), Name: String("Room 2")})
+
+	if err := event.ReattachOccurrence(instance); err != nil {
+		t.Fatalf("ReattachOccurrence failed: %v", err)
+	}
+	patch := event.RecurrenceOverrides["2025-03-05T09:00:00"]
+	if patch["title"] != "Standup with demo" || patch["start"] != "2025-03-05T11:00:00" || patch["locations"] == nil {
+		t.Errorf("Expected title, start and locations patched, got %v", patch)
+	}
+	if _, ok := patch["recurrenceIdTimeZone"]; ok {
+		t.Errorf("Expected recurrenceIdTimeZone not to be patched, got %v", patch)
+	}
+
+	// The round trip gives back the edited instance
+	again, err := event.DetachOccurrence(*id)
+	if err != nil {
+		t.Fatalf("DetachOccurrence failed: %v", err)
+	}
+	if changes, _ := instance.Diff(again); len(changes) != 0 {
+		t.Errorf("Expected the reattached instance unchanged, got %v", changes)
+	}
+
+	// An unmodified instance removes the override
+	unchanged, _ := ParseLocalDateTime("2025-03-04T09:00:00")
+	plain, _ := event.DetachOccurrence(*unchanged)
+	plain.Title = event.Title
+	if err := event.ReattachOccurrence(plain); err != nil {
+		t.Fatalf("ReattachOccurrence failed: %v", err)
+	}
+	if _, ok := event.RecurrenceOverrides["2025-03-04T09:00:00"]; ok {
+		t.Errorf("Expected the override removed, got %v", event.RecurrenceOverrides)
+	}
+
+	other := NewEvent("other", "Other")
+	other.RecurrenceId = id
+	if err := event.ReattachOccurrence(other); err == nil {
+		t.Error("Expected an error reattaching an instance of another event")
+	}
+}
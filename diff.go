@@ -0,0 +1,90 @@
+package jscal
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FieldChange is a difference in one property between two versions of an
+// object
+type FieldChange struct {
+	Path string      `json:"path"`          // Property path as in a PatchObject, e.g. "locations/room/name"
+	Old  interface{} `json:"old,omitempty"` // JSON value before, nil if the property was added
+	New  interface{} `json:"new,omitempty"` // JSON value after, nil if the property was removed
+}
+
+// String formats the change as "path: old -> new", using "(none)" for a
+// missing side
+func (c FieldChange) String() string {
+	return fmt.Sprintf("%s: %s -> %s", c.Path, formatDiffValue(c.Old), formatDiffValue(c.New))
+}
+
+// formatDiffValue renders a JSON value for FieldChange.String
+func formatDiffValue(v interface{}) string {
+	if v == nil {
+		return "(none)"
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// Diff compares e with other property by property, descending into nested
+// objects such as locations and participants. Arrays are compared as a
+// whole. The changes are sorted by path, which is escaped like a
+// PatchObject key, so applying {path: New} for each change to e yields
+// other.
+func (e *Event) Diff(other *Event) ([]FieldChange, error) {
+	before, err := toJSONObject(e)
+	if err != nil {
+		return nil, err
+	}
+	after, err := toJSONObject(other)
+	if err != nil {
+		return nil, err
+	}
+	return diffObjects("", before, after), nil
+}
+
+// toJSONObject returns the JSON representation of v as a map
+func toJSONObject(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// diffObjects compares two JSON objects below prefix
+func diffObjects(prefix string, before, after map[string]interface{}) []FieldChange {
+	keys := make(map[string]bool, len(before)+len(after))
+	for k := range before {
+		keys[k] = true
+	}
+	for k := range after {
+		keys[k] = true
+	}
+
+	var changes []FieldChange
+	for _, k := range sortedKeys(keys) {
+		path := prefix + strings.NewReplacer("~", "~0", "/", "~1").Replace(k)
+		oldValue, newValue := before[k], after[k]
+		oldObj, oldIsObj := oldValue.(map[string]interface{})
+		newObj, newIsObj := newValue.(map[string]interface{})
+		switch {
+		case oldIsObj && newIsObj:
+			changes = append(changes, diffObjects(path+"/", oldObj, newObj)...)
+		case !reflect.DeepEqual(oldValue, newValue):
+			changes = append(changes, FieldChange{Path: path, Old: oldValue, New: newValue})
+		}
+	}
+	return changes
+}
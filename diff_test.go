@@ -0,0 +1,60 @@
+package jscal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEventDiff(t *testing.T) {
+	before := NewEvent("meeting", "Planning")
+	before.Locations = map[string]*Location{
+		"room": {Type: String("Location"), Name: String("Room 1")},
+		"a/b":  {Type: String("Location"), Name: String("Annex")},
+	}
+	before.Keywords = map[string]bool{"planning": true}
+
+	after := before.Clone()
+	after.Title = String("Quarterly planning")
+	after.Description = String("Bring numbers")
+	after.Locations["room"].Name = String("Room 2")
+	after.Locations["a/b"].Name = nil
+	after.Keywords = nil
+
+	changes, err := before.Diff(after)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+
+	var got []string
+	for _, c := range changes {
+		got = append(got, c.String())
+	}
+	want := []string{
+		`description: (none) -> "Bring numbers"`,
+		`keywords: {"planning":true} -> (none)`,
+		`locations/a~1b/name: "Annex" -> (none)`,
+		`locations/room/name: "Room 1" -> "Room 2"`,
+		`title: "Planning" -> "Quarterly planning"`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Expected changes:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+
+	// The changes, as a patch, turn one version into the other
+	obj, _ := toJSONObject(before)
+	patch := make(map[string]interface{}, len(changes))
+	for _, c := range changes {
+		patch[c.Path] = c.New
+	}
+	if err := applyPatch(obj, patch); err != nil {
+		t.Fatal(err)
+	}
+	patched, _ := toJSONObject(after)
+	if rest := diffObjects("", obj, patched); len(rest) != 0 {
+		t.Errorf("Expected patched event to equal the new version, got %v", rest)
+	}
+
+	if changes, _ := before.Diff(before.Clone()); len(changes) != 0 {
+		t.Errorf("Expected no changes for identical events, got %v", changes)
+	}
+}
@@ -1,126 +1,216 @@
 package jscal
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
 	"time"
 )
 
-// parseISO8601Duration parses an ISO 8601 duration string to time.Duration
-func parseISO8601Duration(duration string) (time.Duration, error) {
-	// Parser for ISO 8601 durations like PT1H, P1D, PT30M, P1Y2M3DT4H5M6S
-	// Supports: Years (Y), Months (M), Weeks (W), Days (D), Hours (H), Minutes (M), Seconds (S)
-	// Supports fractional values for all units
-	// Supports negative durations (e.g., -PT15M)
+// ParseDuration parses an ISO 8601 duration such as "PT1H" or a signed
+// duration such as "-PT15M" into a time.Duration
+func ParseDuration(duration string) (time.Duration, error) {
+	return parseISO8601Duration(duration)
+}
 
-	if duration == "" {
-		return 0, fmt.Errorf("invalid ISO 8601 duration: empty string")
-	}
+// SignedDuration is a length of time that may be negative, written as an
+// RFC 8984 SignedDuration such as "-PT15M". Its zero value is "PT0S".
+type SignedDuration time.Duration
 
-	// Check for negative duration
-	negative := false
-	if strings.HasPrefix(duration, "-") {
-		negative = true
-		duration = duration[1:]
+// String returns the duration in ISO 8601 form, see FormatDuration
+func (d SignedDuration) String() string {
+	return FormatDuration(time.Duration(d))
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d SignedDuration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *SignedDuration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
 	}
+	parsed, err := ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = SignedDuration(parsed)
+	return nil
+}
 
-	if !strings.HasPrefix(duration, "P") {
-		return 0, fmt.Errorf("invalid ISO 8601 duration: must start with P")
+// FormatDuration formats a time.Duration as an ISO 8601 signed duration
+// using days, hours, minutes and seconds, e.g. "-PT15M", "P1DT2H" or
+// "PT0.5S"
+func FormatDuration(d time.Duration) string {
+	if d == 0 {
+		return "PT0S"
 	}
 
-	duration = duration[1:] // Remove P
-	if duration == "" {
-		return 0, nil // "P" alone is valid (0 duration)
+	var b strings.Builder
+	if d < 0 {
+		b.WriteByte('-')
+		d = -d
 	}
+	b.WriteByte('P')
 
-	var result time.Duration
+	if days := d / (24 * time.Hour); days > 0 {
+		fmt.Fprintf(&b, "%dD", days)
+		d -= days * 24 * time.Hour
+	}
+	if d > 0 {
+		b.WriteByte('T')
+		if hours := d / time.Hour; hours > 0 {
+			fmt.Fprintf(&b, "%dH", hours)
+			d -= hours * time.Hour
+		}
+		if minutes := d / time.Minute; minutes > 0 {
+			fmt.Fprintf(&b, "%dM", minutes)
+			d -= minutes * time.Minute
+		}
+		if d > 0 {
+			seconds, fraction := d/time.Second, d%time.Second
+			fmt.Fprintf(&b, "%d", seconds)
+			if fraction > 0 {
+				fmt.Fprintf(&b, ".%s", strings.TrimRight(fmt.Sprintf("%09d", fraction), "0"))
+			}
+			b.WriteByte('S')
+		}
+	}
+	return b.String()
+}
 
-	// Check for time portion
-	timeIndex := strings.Index(duration, "T")
-	var datePart, timePart string
+// durationUnits are the designators of an ISO 8601 duration in the order
+// they must appear, with their length. Years and months have no fixed
+// length and are approximated as 365 and 30 days.
+var durationUnits = []struct {
+	designator byte
+	time       bool // whether the designator follows the T
+	length     time.Duration
+}{
+	{'Y', false, 365 * 24 * time.Hour},
+	{'M', false, 30 * 24 * time.Hour},
+	{'W', false, 7 * 24 * time.Hour},
+	{'D', false, 24 * time.Hour},
+	{'H', true, time.Hour},
+	{'M', true, time.Minute},
+	{'S', true, time.Second},
+}
 
-	if timeIndex >= 0 {
-		datePart = duration[:timeIndex]
-		timePart = duration[timeIndex+1:]
-	} else {
-		datePart = duration
+// parseISO8601Duration parses an ISO 8601 duration such as "PT1H",
+// "P2W", "PT1.5H" or "-P1DT12H" to a time.Duration. Components must appear
+// in order and at most once, and only the last may have a fraction. There
+// must be at least one component, and one after a T. Any other input is an
+// error.
+func parseISO8601Duration(duration string) (time.Duration, error) {
+	invalid := func(reason string) (time.Duration, error) {
+		return 0, fmt.Errorf("invalid ISO 8601 duration %q: %s", duration, reason)
+	}
+	if duration == "" {
+		return invalid("empty string")
 	}
 
-	// Parse date part (PYMWD)
-	if datePart != "" {
-		remaining := datePart
+	s := duration
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+	if !strings.HasPrefix(s, "P") {
+		return invalid("must start with P")
+	}
+	s = s[1:]
 
-		// Parse Years (approximate: 365 days)
-		if idx := strings.Index(remaining, "Y"); idx >= 0 {
-			years := 0.0
-			if n, err := fmt.Sscanf(remaining[:idx], "%f", &years); n == 1 && err == nil {
-				result += time.Duration(years * 365 * 24 * float64(time.Hour))
+	var result time.Duration
+	inTime, fractional := false, false
+	components, timeComponents := 0, 0
+	next := 0 // index in durationUnits of the first designator still allowed
+	for s != "" {
+		if s[0] == 'T' {
+			if inTime {
+				return invalid("repeated T")
 			}
-			remaining = remaining[idx+1:]
+			inTime = true
+			s = s[1:]
+			continue
 		}
 
-		// Parse Months (approximate: 30 days)
-		if idx := strings.Index(remaining, "M"); idx >= 0 {
-			months := 0.0
-			if n, err := fmt.Sscanf(remaining[:idx], "%f", &months); n == 1 && err == nil {
-				result += time.Duration(months * 30 * 24 * float64(time.Hour))
-			}
-			remaining = remaining[idx+1:]
+		i := 0
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
 		}
-
-		// Parse Weeks
-		if idx := strings.Index(remaining, "W"); idx >= 0 {
-			weeks := 0.0
-			if n, err := fmt.Sscanf(remaining[:idx], "%f", &weeks); n == 1 && err == nil {
-				result += time.Duration(weeks * 7 * 24 * float64(time.Hour))
-			}
-			remaining = remaining[idx+1:]
+		if i == 0 {
+			return invalid(fmt.Sprintf("expected a number at %q", s))
 		}
-
-		// Parse Days
-		if idx := strings.Index(remaining, "D"); idx >= 0 {
-			days := 0.0
-			if n, err := fmt.Sscanf(remaining[:idx], "%f", &days); n == 1 && err == nil {
-				result += time.Duration(days * 24 * float64(time.Hour))
+		whole := s[:i]
+		var fraction string
+		if i < len(s) && (s[i] == '.' || s[i] == ',') {
+			j := i + 1
+			for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+				j++
 			}
-		}
-	}
-
-	// Parse time part (HMS)
-	if timePart != "" {
-		remaining := timePart
-
-		// Parse Hours
-		if idx := strings.Index(remaining, "H"); idx >= 0 {
-			hours := 0.0
-			if n, err := fmt.Sscanf(remaining[:idx], "%f", &hours); n == 1 && err == nil {
-				result += time.Duration(hours * float64(time.Hour))
+			if j == i+1 {
+				return invalid("missing digits after the decimal mark")
 			}
-			remaining = remaining[idx+1:]
+			fraction = s[i+1 : j]
+			i = j
 		}
-
-		// Parse Minutes
-		if idx := strings.Index(remaining, "M"); idx >= 0 {
-			minutes := 0.0
-			if n, err := fmt.Sscanf(remaining[:idx], "%f", &minutes); n == 1 && err == nil {
-				result += time.Duration(minutes * float64(time.Minute))
+		if i == len(s) {
+			return invalid(fmt.Sprintf("missing designator after %s", s))
+		}
+		designator := s[i]
+		s = s[i+1:]
+
+		unit := -1
+		for k := next; k < len(durationUnits); k++ {
+			if durationUnits[k].designator == designator && durationUnits[k].time == inTime {
+				unit = k
+				break
 			}
-			remaining = remaining[idx+1:]
+		}
+		if unit < 0 {
+			return invalid(fmt.Sprintf("unexpected %c", designator))
+		}
+		if fractional {
+			return invalid("only the last component may have a fraction")
+		}
+		next, fractional = unit+1, fraction != ""
+		components++
+		if inTime {
+			timeComponents++
 		}
 
-		// Parse Seconds
-		if idx := strings.Index(remaining, "S"); idx >= 0 {
-			seconds := 0.0
-			if n, err := fmt.Sscanf(remaining[:idx], "%f", &seconds); n == 1 && err == nil {
-				result += time.Duration(seconds * float64(time.Second))
-			}
+		length := durationUnits[unit].length
+		n, err := strconv.ParseInt(whole, 10, 64)
+		if err != nil || n > math.MaxInt64/int64(length) {
+			return invalid("out of range")
+		}
+		value := time.Duration(n) * length
+		if fraction != "" {
+			f, _ := strconv.ParseFloat("0."+fraction, 64)
+			value += time.Duration(math.Round(f * float64(length)))
+		}
+		if result > math.MaxInt64-value {
+			return invalid("out of range")
 		}
+		result += value
+	}
+	if components == 0 {
+		return invalid("no components")
+	}
+	if inTime && timeComponents == 0 {
+		return invalid("no component after T")
 	}
 
-	// Apply negative flag if set
 	if negative {
 		result = -result
 	}
-
 	return result, nil
 }
@@ -1,6 +1,7 @@
 package jscal
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 )
@@ -38,12 +39,31 @@ func TestParseISO8601Duration(t *testing.T) {
 
 		// Edge cases
 		{"0 duration", "PT0S", 0, false},
-		{"only P", "P", 0, false},
-		{"only PT", "PT", 0, false},
+		{"only P", "P", 0, true},
+		{"only PT", "PT", 0, true},
+		{"only -P", "-P", 0, true},
+		{"T without time component", "P1DT", 0, true},
 		{"negative duration", "-PT1H", -time.Hour, false},
 		{"invalid format", "1H30M", 0, true},
 		{"missing P", "T1H", 0, true},
 		{"text", "one hour", 0, true},
+
+		// Strictness
+		{"explicit plus", "+PT1H", time.Hour, false},
+		{"decimal comma", "PT0,5H", 30 * time.Minute, false},
+		{"fractional seconds", "PT0.25S", 250 * time.Millisecond, false},
+		{"negative weeks", "-P2W", -14 * 24 * time.Hour, false},
+		{"unknown designator", "PT1X", 0, true},
+		{"hours before T", "P1H", 0, true},
+		{"out of order", "PT1M1H", 0, true},
+		{"repeated component", "P1D2D", 0, true},
+		{"repeated T", "PT1HT2M", 0, true},
+		{"fraction not last", "PT1.5H30M", 0, true},
+		{"missing number", "PTH", 0, true},
+		{"missing designator", "PT15", 0, true},
+		{"missing fraction digits", "PT1.H", 0, true},
+		{"trailing garbage", "PT1H ", 0, true},
+		{"overflow", "P999999999999D", 0, true},
 	}
 
 	for _, tt := range tests {
@@ -58,3 +78,62 @@ func TestParseISO8601Duration(t *testing.T) {
 		})
 	}
 }
+
+func TestParseDuration(t *testing.T) {
+	if got, err := ParseDuration("-PT15M"); err != nil || got != -15*time.Minute {
+		t.Errorf("ParseDuration(-PT15M) = %v, %v, want -15m", got, err)
+	}
+	if _, err := ParseDuration("15 minutes"); err == nil {
+		t.Error("Expected error for invalid duration")
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "PT0S"},
+		{15 * time.Minute, "PT15M"},
+		{-15 * time.Minute, "-PT15M"},
+		{24 * time.Hour, "P1D"},
+		{26*time.Hour + 30*time.Second, "P1DT2H30S"},
+		{-(49 * time.Hour), "-P2DT1H"},
+		{1500 * time.Millisecond, "PT1.5S"},
+		{500 * time.Millisecond, "PT0.5S"},
+	}
+
+	for _, tt := range tests {
+		got := FormatDuration(tt.d)
+		if got != tt.want {
+			t.Errorf("FormatDuration(%v) = %s, want %s", tt.d, got, tt.want)
+		}
+		if back, err := ParseDuration(got); err != nil || back != tt.d {
+			t.Errorf("ParseDuration(%s) = %v, %v, want %v", got, back, err, tt.d)
+		}
+	}
+}
+
+func TestSignedDurationJSON(t *testing.T) {
+	var value struct {
+		Offset SignedDuration `json:"offset"`
+	}
+	if err := json.Unmarshal([]byte(`{"offset":"-PT15M"}`), &value); err != nil {
+		t.Fatal(err)
+	}
+	if time.Duration(value.Offset) != -15*time.Minute {
+		t.Errorf("Expected -15m, got %v", time.Duration(value.Offset))
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"offset":"-PT15M"}` {
+		t.Errorf("Expected {\"offset\":\"-PT15M\"}, got %s", data)
+	}
+
+	if err := json.Unmarshal([]byte(`{"offset":"PT15"}`), &value); err == nil {
+		t.Error("Expected error for invalid duration")
+	}
+}
@@ -0,0 +1,278 @@
+package jscal
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Length limits of RFC 5321 Section 4.5.3.1, in octets
+const (
+	maxEmailLength      = 254
+	maxEmailLocalLength = 64
+	maxDomainLength     = 255
+	maxLabelLength      = 63
+)
+
+// emailSpecials are the ASCII characters allowed in an unquoted local part
+// besides letters and digits (atext, RFC 5322 Section 3.2.3)
+const emailSpecials = "!#$%&'*+-/=?^_`{|}~"
+
+// EmailOptions configures NormalizeEmail
+type EmailOptions struct {
+	// ASCIIDomain converts an internationalized domain to its ASCII form,
+	// e.g. "bücher.example" to "xn--bcher-kva.example", for systems
+	// without SMTPUTF8 (RFC 6531)
+	ASCIIDomain bool
+}
+
+// ValidateEmail checks that address is a mailbox as used in SMTP (RFC 5321
+// Section 4.1.2): a dot-atom or quoted local part, an "@" and a domain name
+// or address literal such as "[192.0.2.1]". Internationalized addresses
+// (RFC 6531) may use UTF-8 in the local part and domain labels.
+func ValidateEmail(address string) error {
+	if !utf8.ValidString(address) {
+		return fmt.Errorf("not valid UTF-8")
+	}
+	if len(address) > maxEmailLength {
+		return fmt.Errorf("longer than %d octets", maxEmailLength)
+	}
+	idx := strings.LastIndex(address, "@")
+	if idx < 0 {
+		return fmt.Errorf("missing @")
+	}
+	if err := validateLocalPart(address[:idx]); err != nil {
+		return err
+	}
+	return validateDomain(address[idx+1:])
+}
+
+// validateLocalPart checks the part of an address before the "@"
+func validateLocalPart(local string) error {
+	switch {
+	case local == "":
+		return fmt.Errorf("empty local part")
+	case len(local) > maxEmailLocalLength:
+		return fmt.Errorf("local part longer than %d octets", maxEmailLocalLength)
+	}
+
+	if len(local) >= 2 && local[0] == '"' && local[len(local)-1] == '"' {
+		quoted := local[1 : len(local)-1]
+		for i := 0; i < len(quoted); i++ {
+			switch c := quoted[i]; {
+			case c == '\\':
+				if i++; i == len(quoted) || quoted[i] < ' ' || quoted[i] > '~' {
+					return fmt.Errorf("invalid escape in quoted local part")
+				}
+			case c == '"' || (c < ' ' && c != '\t') || c == 0x7F:
+				return fmt.Errorf("invalid character %q in quoted local part", c)
+			}
+		}
+		return nil
+	}
+
+	for _, atom := range strings.Split(local, ".") {
+		if atom == "" {
+			return fmt.Errorf("empty atom in local part %q", local)
+		}
+		for _, r := range atom {
+			if r < utf8.RuneSelf && !isAlphaNumeric(r) && !strings.ContainsRune(emailSpecials, r) {
+				return fmt.Errorf("invalid character %q in local part", r)
+			}
+			if r >= utf8.RuneSelf && !unicode.IsGraphic(r) {
+				return fmt.Errorf("invalid character %U in local part", r)
+			}
+		}
+	}
+	return nil
+}
+
+// validateDomain checks the part of an address after the "@": a domain
+// name whose labels may be internationalized, or an address literal
+func validateDomain(domain string) error {
+	if domain == "" {
+		return fmt.Errorf("empty domain")
+	}
+	if strings.HasPrefix(domain, "[") && strings.HasSuffix(domain, "]") {
+		literal := domain[1 : len(domain)-1]
+		if ipv6, ok := strings.CutPrefix(literal, "IPv6:"); ok {
+			if ip := net.ParseIP(ipv6); ip != nil && strings.Contains(ipv6, ":") {
+				return nil
+			}
+		} else if ip := net.ParseIP(literal); ip != nil && ip.To4() != nil && !strings.Contains(literal, ":") {
+			return nil
+		}
+		return fmt.Errorf("invalid address literal %s", domain)
+	}
+
+	ascii := domainToASCII(domain)
+	if len(ascii) > maxDomainLength {
+		return fmt.Errorf("domain longer than %d octets", maxDomainLength)
+	}
+	for i, label := range strings.Split(domain, ".") {
+		switch {
+		case label == "":
+			return fmt.Errorf("empty label in domain %q", domain)
+		case strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-"):
+			return fmt.Errorf("label %q starts or ends with a hyphen", label)
+		case len(strings.Split(ascii, ".")[i]) > maxLabelLength:
+			return fmt.Errorf("label %q longer than %d octets", label, maxLabelLength)
+		}
+		for _, r := range label {
+			if r < utf8.RuneSelf && !isAlphaNumeric(r) && r != '-' {
+				return fmt.Errorf("invalid character %q in domain", r)
+			}
+			if r >= utf8.RuneSelf && !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.Is(unicode.Mn, r) && !unicode.Is(unicode.Mc, r) {
+				return fmt.Errorf("invalid character %U in domain", r)
+			}
+		}
+	}
+	return nil
+}
+
+// NormalizeEmail validates an address and returns it with its domain
+// lowercased and, with ASCIIDomain, converted to ASCII. The local part is
+// kept as it is, since RFC 5321 leaves its case to the receiving host.
+func NormalizeEmail(address string, opts EmailOptions) (string, error) {
+	address = strings.TrimSpace(address)
+	if err := ValidateEmail(address); err != nil {
+		return "", fmt.Errorf("invalid email address %q: %w", address, err)
+	}
+
+	idx := strings.LastIndex(address, "@")
+	domain := address[idx+1:]
+	if !strings.HasPrefix(domain, "[") {
+		domain = strings.ToLower(domain)
+		if opts.ASCIIDomain {
+			domain = domainToASCII(domain)
+		}
+	}
+	return address[:idx+1] + domain, nil
+}
+
+// mailtoAddresses returns the addresses of a mailto: URI (RFC 6068), which
+// may list several separated by commas
+func mailtoAddresses(uri string) ([]string, error) {
+	u, err := url.Parse(uri)
+	if err != nil || !strings.EqualFold(u.Scheme, "mailto") {
+		return nil, fmt.Errorf("not a mailto: URI")
+	}
+	to, err := url.PathUnescape(u.Opaque)
+	if err != nil {
+		return nil, err
+	}
+	if to == "" {
+		return nil, fmt.Errorf("no address")
+	}
+	return strings.Split(to, ","), nil
+}
+
+// domainToASCII converts the internationalized labels of a domain to
+// their punycode form with the "xn--" prefix (RFC 5891)
+func domainToASCII(domain string) string {
+	labels := strings.Split(domain, ".")
+	for i, label := range labels {
+		for _, r := range label {
+			if r >= utf8.RuneSelf {
+				labels[i] = "xn--" + punycodeEncode(strings.ToLower(label))
+				break
+			}
+		}
+	}
+	return strings.Join(labels, ".")
+}
+
+// Punycode parameters (RFC 3492 Section 5)
+const (
+	punyBase        = 36
+	punyTMin        = 1
+	punyTMax        = 26
+	punySkew        = 38
+	punyDamp        = 700
+	punyInitialBias = 72
+	punyInitialN    = 128
+)
+
+// punycodeEncode encodes a label with the Punycode algorithm of RFC 3492
+func punycodeEncode(label string) string {
+	runes := []rune(label)
+	var out []byte
+	for _, r := range runes {
+		if r < utf8.RuneSelf {
+			out = append(out, byte(r))
+		}
+	}
+	basic := len(out)
+	if basic > 0 {
+		out = append(out, '-')
+	}
+
+	n, delta, bias := punyInitialN, 0, punyInitialBias
+	for handled := basic; handled < len(runes); {
+		next := int(unicode.MaxRune) + 1
+		for _, r := range runes {
+			if int(r) >= n && int(r) < next {
+				next = int(r)
+			}
+		}
+		delta += (next - n) * (handled + 1)
+		n = next
+
+		for _, r := range runes {
+			if int(r) < n {
+				delta++
+			}
+			if int(r) != n {
+				continue
+			}
+			q := delta
+			for k := punyBase; ; k += punyBase {
+				t := min(max(k-bias, punyTMin), punyTMax)
+				if q < t {
+					break
+				}
+				out = append(out, punycodeDigit(t+(q-t)%(punyBase-t)))
+				q = (q - t) / (punyBase - t)
+			}
+			out = append(out, punycodeDigit(q))
+			bias = punycodeAdapt(delta, handled+1, handled == basic)
+			delta = 0
+			handled++
+		}
+		delta++
+		n++
+	}
+	return string(out)
+}
+
+// punycodeAdapt is the bias adaptation function of RFC 3492 Section 6.1
+func punycodeAdapt(delta, points int, first bool) int {
+	if first {
+		delta /= punyDamp
+	} else {
+		delta /= 2
+	}
+	delta += delta / points
+	k := 0
+	for delta > ((punyBase-punyTMin)*punyTMax)/2 {
+		delta /= punyBase - punyTMin
+		k += punyBase
+	}
+	return k + (punyBase-punyTMin+1)*delta/(delta+punySkew)
+}
+
+// punycodeDigit returns the basic code point for a digit value
+func punycodeDigit(d int) byte {
+	if d < 26 {
+		return byte('a' + d)
+	}
+	return byte('0' + d - 26)
+}
+
+// isAlphaNumeric reports whether r is an ASCII letter or digit
+func isAlphaNumeric(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'
+}
@@ -0,0 +1,116 @@
+package jscal
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateEmail(t *testing.T) {
+	tests := []struct {
+		address string
+		valid   bool
+	}{
+		{"alice@example.com", true},
+		{"first.last+tag@sub.example.co.uk", true},
+		{"user@localhost", true},
+		{`"john doe"@example.com`, true},
+		{`"a\"b"@example.com`, true},
+		{"user@[192.0.2.1]", true},
+		{"user@[IPv6:2001:db8::1]", true},
+		{"josé@example.com", true},
+		{"用户@例子.广告", true},
+		{"user@bücher.example", true},
+		{"user@xn--bcher-kva.example", true},
+		{"", false},
+		{"alice", false},
+		{"@example.com", false},
+		{"alice@", false},
+		{"alice..smith@example.com", false},
+		{".alice@example.com", false},
+		{"alice smith@example.com", false},
+		{"alice@exa mple.com", false},
+		{"alice@-example.com", false},
+		{"alice@example..com", false},
+		{"alice@[300.1.1.1]", false},
+		{"alice@[2001:db8::1]", false},
+		{strings.Repeat("a", 65) + "@example.com", false},
+		{"alice@" + strings.Repeat("a", 64) + ".com", false},
+		{"alice@example.com\xff", false},
+	}
+
+	for _, tt := range tests {
+		err := ValidateEmail(tt.address)
+		if (err == nil) != tt.valid {
+			t.Errorf("ValidateEmail(%q): expected valid %v, got %v", tt.address, tt.valid, err)
+		}
+	}
+}
+
+func TestNormalizeEmail(t *testing.T) {
+	tests := []struct {
+		address string
+		opts    EmailOptions
+		want    string
+		wantErr bool
+	}{
+		{"Alice@Example.COM", EmailOptions{}, "Alice@example.com", false},
+		{" bob@EXAMPLE.org ", EmailOptions{}, "bob@example.org", false},
+		{"kim@Bücher.example", EmailOptions{}, "kim@bücher.example", false},
+		{"kim@Bücher.example", EmailOptions{ASCIIDomain: true}, "kim@xn--bcher-kva.example", false},
+		{"max@münchen.de", EmailOptions{ASCIIDomain: true}, "max@xn--mnchen-3ya.de", false},
+		{"user@[192.0.2.1]", EmailOptions{ASCIIDomain: true}, "user@[192.0.2.1]", false},
+		{"not an address", EmailOptions{}, "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := NormalizeEmail(tt.address, tt.opts)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("NormalizeEmail(%q): expected error %v, got %v", tt.address, tt.wantErr, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("NormalizeEmail(%q): expected %q, got %q", tt.address, tt.want, got)
+		}
+	}
+}
+
+func TestPunycodeEncode(t *testing.T) {
+	// Samples from RFC 3492 Section 7.1 and common IDNs
+	tests := map[string]string{
+		"bücher":            "bcher-kva",
+		"münchen":           "mnchen-3ya",
+		"例子":                "fsqu00a",
+		"ليهمابتكلموشعربي؟": "egbpdaj6bu4bxfgehfvwxn",
+	}
+	for label, want := range tests {
+		if got := punycodeEncode(label); got != want {
+			t.Errorf("punycodeEncode(%q): expected %s, got %s", label, want, got)
+		}
+	}
+}
+
+func TestValidateEmailFields(t *testing.T) {
+	event := NewEvent("email-test", "Meeting")
+	event.Start = NewLocalDateTime(time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC))
+	event.SentBy = String("assistant@@example.com")
+	event.ReplyTo = map[string]string{ReplyMethodImip: "mailto:organizer@exa mple.com"}
+	event.AddParticipant("p1", &Participant{Email: String("josé@bücher.example"), SentBy: String("nobody")})
+
+	err := event.Validate()
+	if err == nil {
+		t.Fatal("Expected validation errors")
+	}
+	fields := make(map[string]bool)
+	for _, valErr := range err.(ValidationErrors) {
+		fields[valErr.Field] = true
+	}
+	for _, field := range []string{"sentBy", "replyTo[imip]", "participants[p1].sentBy"} {
+		if !fields[field] {
+			t.Errorf("Expected an error at %s, got %v", field, err)
+		}
+	}
+	if fields["participants[p1].email"] {
+		t.Errorf("Expected the internationalized email to be valid, got %v", err)
+	}
+}
@@ -0,0 +1,154 @@
+package jscal
+
+import (
+	"reflect"
+	"time"
+)
+
+// volatileProperties change whenever an object is saved or passes through
+// another product, without changing what it describes
+var volatileProperties = []string{"updated", "sequence", "prodId"}
+
+// Equal reports whether the Event and other have the same fields. Times
+// are equal if they denote the same instant, and unset and empty maps
+// differ. Use EquivalentTo to ignore bookkeeping fields.
+func (e *Event) Equal(other *Event) bool {
+	return equalObjects(e, other)
+}
+
+// EquivalentTo reports whether the Event and other describe the same event.
+// Unlike Equal it ignores updated, sequence and prodId, treats unset and
+// empty maps and lists as the same and treats a property set to its RFC 8984
+// default like an unset one.
+func (e *Event) EquivalentTo(other *Event) bool {
+	return equivalentObjects(e, other)
+}
+
+// Equal reports whether the Task and other have the same fields, see
+// Event.Equal
+func (t *Task) Equal(other *Task) bool {
+	return equalObjects(t, other)
+}
+
+// EquivalentTo reports whether the Task and other describe the same task,
+// see Event.EquivalentTo
+func (t *Task) EquivalentTo(other *Task) bool {
+	return equivalentObjects(t, other)
+}
+
+// Equal reports whether the Group and other have the same fields and
+// entries, see Event.Equal
+func (g *Group) Equal(other *Group) bool {
+	return equalObjects(g, other)
+}
+
+// EquivalentTo reports whether the Group and other hold the same entries,
+// see Event.EquivalentTo. Volatile fields of the entries are ignored too.
+func (g *Group) EquivalentTo(other *Group) bool {
+	return equivalentObjects(g, other)
+}
+
+func equalObjects(a, b interface{}) bool {
+	return equalValues(reflect.ValueOf(a), reflect.ValueOf(b))
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	localDateTimeType = reflect.TypeOf(LocalDateTime{})
+	utcDateTimeType   = reflect.TypeOf(UTCDateTime{})
+)
+
+// equalValues compares two values of the same type like reflect.DeepEqual,
+// except that times are compared as instants and local date-times by value
+func equalValues(a, b reflect.Value) bool {
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+	if a.Type() != b.Type() {
+		return false
+	}
+
+	switch a.Type() {
+	case timeType:
+		return a.Interface().(time.Time).Equal(b.Interface().(time.Time))
+	case localDateTimeType:
+		return a.Interface().(LocalDateTime).String() == b.Interface().(LocalDateTime).String()
+	case utcDateTimeType:
+		return time.Time(a.Interface().(UTCDateTime)).Equal(time.Time(b.Interface().(UTCDateTime)))
+	}
+
+	switch a.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return equalValues(a.Elem(), b.Elem())
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if !equalValues(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Slice:
+		if a.IsNil() != b.IsNil() || a.Len() != b.Len() {
+			return false
+		}
+		for i := 0; i < a.Len(); i++ {
+			if !equalValues(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		if a.IsNil() != b.IsNil() || a.Len() != b.Len() {
+			return false
+		}
+		for _, key := range a.MapKeys() {
+			value := b.MapIndex(key)
+			if !value.IsValid() || !equalValues(a.MapIndex(key), value) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(a.Interface(), b.Interface())
+	}
+}
+
+func equivalentObjects(a, b interface{}) bool {
+	before, err := equivalenceForm(a)
+	if err != nil {
+		return false
+	}
+	after, err := equivalenceForm(b)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(before, after)
+}
+
+// equivalenceForm returns the canonical JSON object of v without volatile
+// properties, see CanonicalJSON
+func equivalenceForm(v interface{}) (map[string]interface{}, error) {
+	obj, err := toJSONObject(v)
+	if err != nil {
+		return nil, err
+	}
+	canonicalize(obj, "")
+	dropVolatile(obj)
+	return obj, nil
+}
+
+// dropVolatile removes the volatile properties of an object and its entries
+func dropVolatile(obj map[string]interface{}) {
+	for _, key := range volatileProperties {
+		delete(obj, key)
+	}
+	entries, _ := obj["entries"].([]interface{})
+	for _, entry := range entries {
+		if m, ok := entry.(map[string]interface{}); ok {
+			dropVolatile(m)
+		}
+	}
+}
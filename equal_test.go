@@ -0,0 +1,94 @@
+package jscal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEventEqual(t *testing.T) {
+	updated := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	base := NewEvent("equal", "Review")
+	base.Created, base.Updated = NewUTCDateTime(updated), NewUTCDateTime(updated)
+	base.Start = NewLocalDateTime(time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC))
+	base.AddCategory("work")
+	base.AddParticipant("p1", NewParticipant("Jane", "jane@example.com"))
+
+	tests := []struct {
+		name       string
+		modify     func(*Event)
+		equal      bool
+		equivalent bool
+	}{
+		{"identical", func(e *Event) {}, true, true},
+		{"same instant in another zone", func(e *Event) {
+			updated := UTCDateTime(e.Updated.Time().In(time.FixedZone("CET", 3600)))
+			e.Updated = &updated
+		}, true, true},
+		{"updated", func(e *Event) {
+			updated := e.Updated.Add(time.Hour)
+			e.Updated = &updated
+		}, false, true},
+		{"sequence", func(e *Event) { e.Sequence = Int(3) }, false, true},
+		{"prodId", func(e *Event) { e.ProdId = String("-//Other//EN") }, false, true},
+		{"empty map", func(e *Event) { e.Keywords = map[string]bool{} }, false, true},
+		{"default value", func(e *Event) { e.Privacy = String(PrivacyPublic) }, false, true},
+		{"title", func(e *Event) { e.Title = String("Other") }, false, false},
+		{"start", func(e *Event) {
+			e.Start = NewLocalDateTime(time.Date(2025, 3, 3, 10, 0, 0, 0, time.UTC))
+		}, false, false},
+		{"participant", func(e *Event) { e.Participants["p1"].Name = String("John") }, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := base.Clone(), base.Clone()
+			tt.modify(b)
+			if got := a.Equal(b); got != tt.equal {
+				t.Errorf("Expected Equal %v, got %v", tt.equal, got)
+			}
+			if got := a.EquivalentTo(b); got != tt.equivalent {
+				t.Errorf("Expected EquivalentTo %v, got %v", tt.equivalent, got)
+			}
+		})
+	}
+}
+
+func TestTaskEqual(t *testing.T) {
+	a, b := NewTask("task", "Write"), NewTask("task", "Write")
+	b.Created, b.Updated = a.Created, a.Updated
+	if !a.Equal(b) || !a.EquivalentTo(b) {
+		t.Error("Expected identical tasks to be equal")
+	}
+	b.Sequence = Int(1)
+	if a.Equal(b) || !a.EquivalentTo(b) {
+		t.Error("Expected tasks differing in sequence to be equivalent only")
+	}
+}
+
+func TestGroupEqual(t *testing.T) {
+	created := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	newGroup := func() *Group {
+		group := NewGroup("group", "Calendar")
+		event := NewEvent("equal", "Review")
+		event.Created, event.Updated = NewUTCDateTime(created), NewUTCDateTime(created)
+		event.Start = NewLocalDateTime(created)
+		if err := group.AddEntry(event); err != nil {
+			t.Fatal(err)
+		}
+		group.Created, group.Updated = NewUTCDateTime(created), NewUTCDateTime(created)
+		return group
+	}
+
+	a, b := newGroup(), newGroup()
+	if !a.Equal(b) {
+		t.Error("Expected identical groups to be equal")
+	}
+	b.Entries[0].(*Event).Sequence = Int(2)
+	if a.Equal(b) || !a.EquivalentTo(b) {
+		t.Error("Expected groups whose entries differ in sequence to be equivalent only")
+	}
+	b.Entries[0].(*Event).Title = String("Other")
+	if a.EquivalentTo(b) {
+		t.Error("Expected groups with different entries not to be equivalent")
+	}
+}
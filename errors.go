@@ -0,0 +1,56 @@
+package jscal
+
+import "errors"
+
+// Sentinel errors that parse failures wrap, so callers can tell them apart
+// with errors.Is:
+//
+//	obj, err := jscal.Parse(data)
+//	switch {
+//	case errors.Is(err, jscal.ErrInvalidJSON):
+//		// Malformed input, or a property of the wrong type
+//	case errors.Is(err, jscal.ErrValidation):
+//		var errs jscal.ValidationErrors
+//		errors.As(err, &errs)
+//	}
+var (
+	// ErrInvalidJSON is wrapped by errors for input that isn't JSON, or
+	// whose properties don't decode into their RFC 8984 types
+	ErrInvalidJSON = errors.New("invalid JSON")
+
+	// ErrUnknownType is wrapped by errors for objects whose @type is
+	// missing or not one the function accepts
+	ErrUnknownType = errors.New("unknown @type")
+
+	// ErrUnknownProperty is wrapped by errors for properties RFC 8984
+	// doesn't define, with ParseOptions.DisallowUnknownFields
+	ErrUnknownProperty = errors.New("unknown property")
+
+	// ErrValidation is matched by ValidationError and ValidationErrors, the
+	// errors of objects that decode but break RFC 8984
+	ErrValidation = errors.New("validation failed")
+)
+
+// kindError marks err as one of the sentinel errors for errors.Is while
+// keeping err's message
+type kindError struct {
+	kind error
+	err  error
+}
+
+func (e *kindError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the sentinel and the underlying error
+func (e *kindError) Unwrap() []error {
+	return []error{e.kind, e.err}
+}
+
+// withKind returns err marked as kind, or nil for a nil err
+func withKind(kind, err error) error {
+	if err == nil || errors.Is(err, kind) {
+		return err
+	}
+	return &kindError{kind: kind, err: err}
+}
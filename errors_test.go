@@ -0,0 +1,53 @@
+package jscal
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseErrorKinds(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		opts     ParseOptions
+		expected error
+	}{
+		{"not JSON", `{"@type": "Event",`, ParseOptions{}, ErrInvalidJSON},
+		{"wrong property type", `{"@type": "Event", "uid": "e", "title": 3}`, ParseOptions{}, ErrInvalidJSON},
+		{"unknown @type", `{"@type": "Meeting", "uid": "e"}`, ParseOptions{}, ErrUnknownType},
+		{"missing @type", `{"uid": "e"}`, ParseOptions{}, ErrUnknownType},
+		{"unknown group entry", `{"@type": "Group", "uid": "g", "entries": [{"@type": "Note", "uid": "n"}]}`, ParseOptions{}, ErrUnknownType},
+		{"unknown property", `{"@type": "Event", "uid": "e", "start": "2025-03-01T09:00:00", "colour": "red"}`,
+			ParseOptions{DisallowUnknownFields: true}, ErrUnknownProperty},
+		{"invalid event", `{"@type": "Event", "uid": "", "start": "2025-03-01T09:00:00"}`, ParseOptions{}, ErrValidation},
+		{"too large", `{"@type": "Event"}`, ParseOptions{MaxBytes: 4}, ErrTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseWithOptions([]byte(tt.data), tt.opts)
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+			if tt.expected != ErrValidation && errors.Is(err, ErrValidation) {
+				t.Errorf("Expected %v not to be a validation error", err)
+			}
+		})
+	}
+}
+
+func TestValidationErrorKind(t *testing.T) {
+	err := (&Event{Type: "Event"}).Validate()
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("Expected Validate to return ErrValidation, got %v", err)
+	}
+
+	_, err = ParseEvent([]byte(`{"@type": "Event", "uid": "e", "start": "2025-03-01T09:00:00", "priority": 12}`))
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs) == 0 || errs[0].Field != "priority" {
+		t.Errorf("Expected ValidationErrors for priority, got %v", err)
+	}
+	if err.Error() != "parsed JSCalendar Event is invalid: "+errs.Error() {
+		t.Errorf("Expected the message to be kept, got %q", err)
+	}
+}
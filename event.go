@@ -3,6 +3,7 @@ package jscal
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"time"
 )
 
@@ -14,13 +15,13 @@ var _ CalendarObject = &Event{}
 // or reminder with all associated metadata.
 type Event struct {
 	// Core metadata (Section 4.1)
-	Type     string     `json:"@type"`              // Always "Event"
-	UID      string     `json:"uid"`                // Unique identifier
-	Created  *time.Time `json:"created,omitempty"`  // Creation timestamp
-	Updated  *time.Time `json:"updated,omitempty"`  // Last modification timestamp
-	Sequence *int       `json:"sequence,omitempty"` // Revision sequence number
-	Method   *string    `json:"method,omitempty"`   // publish, request, reply, etc.
-	ProdId   *string    `json:"prodId,omitempty"`   // Product identifier that created this
+	Type     string       `json:"@type"`              // Always "Event"
+	UID      string       `json:"uid"`                // Unique identifier
+	Created  *UTCDateTime `json:"created,omitempty"`  // Creation timestamp
+	Updated  *UTCDateTime `json:"updated,omitempty"`  // Last modification timestamp
+	Sequence *int         `json:"sequence,omitempty"` // Revision sequence number
+	Method   *string      `json:"method,omitempty"`   // publish, request, reply, etc.
+	ProdId   *string      `json:"prodId,omitempty"`   // Product identifier that created this
 
 	// What and Where Properties (Section 4.2)
 	Title                  *string                           `json:"title,omitempty"`                  // Event summary/title
@@ -56,6 +57,11 @@ type Event struct {
 	Participants   map[string]*Participant `json:"participants,omitempty"`   // Event participants
 	RequestStatus  *string                 `json:"requestStatus,omitempty"`  // Scheduling request status
 
+	// Invitation permissions (JMAP Calendars)
+	MayInviteSelf   *bool `json:"mayInviteSelf,omitempty"`   // Anyone may add themselves as an attendee
+	MayInviteOthers *bool `json:"mayInviteOthers,omitempty"` // Attendees may invite others
+	HideAttendees   *bool `json:"hideAttendees,omitempty"`   // Only owners see every participant
+
 	// Alerts Properties (Section 4.5)
 	UseDefaultAlerts *bool             `json:"useDefaultAlerts,omitempty"` // Use default alert settings
 	Alerts           map[string]*Alert `json:"alerts,omitempty"`           // Custom alerts
@@ -80,14 +86,14 @@ type Event struct {
 
 // NewEvent creates a new JSCalendar Event with required fields
 func NewEvent(uid, title string) *Event {
-	now := time.Now().UTC()
+	now := Now().UTC()
 	return &Event{
 		Type:     "Event",
 		UID:      uid,
 		Title:    &title,
 		Start:    NewLocalDateTime(now),
-		Created:  &now,
-		Updated:  &now,
+		Created:  NewUTCDateTime(now),
+		Updated:  NewUTCDateTime(now),
 		Sequence: Int(0),
 	}
 }
@@ -102,6 +108,55 @@ func (e *Event) PrettyJSON() ([]byte, error) {
 	return json.MarshalIndent(e, "", "  ")
 }
 
+// MarshalPartial returns the Event as JSON with only the named top-level
+// properties, e.g. "title", "start" and "duration" for sparse listings.
+// Extensions are named like any other property and unknown names are
+// ignored. The properties are written as MarshalJSON writes them, without
+// building an intermediate map.
+func (e *Event) MarshalPartial(fields []string) ([]byte, error) {
+	if fields == nil {
+		fields = []string{}
+	}
+	return e.appendFields(make([]byte, 0, 256), fields)
+}
+
+// MarshalJSON implements json.Marshaler, writing Extensions as top-level properties
+func (e Event) MarshalJSON() ([]byte, error) {
+	if data, err := e.appendJSON(make([]byte, 0, 1024)); err == nil {
+		return data, nil
+	}
+
+	// Let encoding/json report the error
+	type Alias Event
+	data, err := json.Marshal(Alias(e))
+	if err != nil {
+		return nil, err
+	}
+	return appendExtensions(data, e.Extensions, reflect.TypeOf(Alias{}))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, capturing unrecognized top-level
+// properties such as "example.com:custom" into Extensions
+func (e *Event) UnmarshalJSON(data []byte) error {
+	saved := *e
+	if err := e.decodeJSON(data); err == nil {
+		return nil
+	}
+
+	// Decode again with encoding/json, which reports errors with context
+	*e = saved
+	type Alias Event
+	if err := json.Unmarshal(data, (*Alias)(e)); err != nil {
+		return err
+	}
+	extensions, err := extractExtensions(data, reflect.TypeOf(Alias{}))
+	if err != nil {
+		return err
+	}
+	e.Extensions = extensions
+	return nil
+}
+
 // Clone creates a deep copy of the Event
 func (e *Event) Clone() *Event {
 	data, _ := json.Marshal(e)
@@ -125,21 +180,24 @@ func (e *Event) GetDuration() (time.Duration, error) {
 	return parseISO8601Duration(*e.Duration)
 }
 
-// GetEndTime calculates the end time based on start and duration
+// GetEndTime calculates the end time based on start and duration. The
+// duration is added in the event's time zone, see AddDurationInZone, and
+// the end is returned in it; floating and all-day ends are in UTC.
 func (e *Event) GetEndTime() (*time.Time, error) {
 	if e.Start == nil {
 		return nil, fmt.Errorf("no start time specified")
 	}
 
-	// Convert LocalDateTime to time.Time
-	startTime := e.Start.Time()
-
-	duration, err := e.GetDuration()
+	if _, err := e.GetDuration(); err != nil {
+		return nil, fmt.Errorf("failed to parse duration: %w", err)
+	}
+	zone := eventTimeZone(e)
+	end, err := e.Start.AddDurationInZone(*e.Duration, zone)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse duration: %w", err)
 	}
 
-	endTime := startTime.Add(duration)
+	endTime := e.ZonedTime(end)
 	return &endTime, nil
 }
 
@@ -175,6 +233,14 @@ func (e *Event) AddAlert(id string, alert *Alert) {
 	e.Alerts[id] = alert
 }
 
+// EffectiveAlerts returns the alerts that apply to the event: its own, or the
+// provider's defaults if useDefaultAlerts is true, in which case RFC 8984
+// says to ignore alerts. Without a provider there are then no alerts. The
+// returned map is not a copy.
+func (e *Event) EffectiveAlerts(provider DefaultAlertsProvider) map[string]*Alert {
+	return effectiveAlerts(e, e.Alerts, e.UseDefaultAlerts, provider)
+}
+
 // AddCategory adds a category to the event
 func (e *Event) AddCategory(category string) {
 	if e.Categories == nil {
@@ -199,6 +265,13 @@ func (e *Event) AddLink(id string, link *Link) {
 	e.Links[id] = link
 }
 
+// AddRelation relates the event to the object with the given UID. relType is
+// one of the RelationType constants, e.g. RelationTypeParent when uid is
+// the parent of this event; an empty relType adds an unspecified relation.
+func (e *Event) AddRelation(uid, relType string) {
+	addRelation(&e.RelatedTo, uid, relType)
+}
+
 // SetRecurrence sets the recurrence rules for the event
 func (e *Event) SetRecurrence(rules []RecurrenceRule) {
 	e.RecurrenceRules = rules
@@ -211,8 +284,8 @@ func (e *Event) IsRecurring() bool {
 
 // Touch updates the Updated timestamp to now
 func (e *Event) Touch() {
-	now := time.Now().UTC()
-	e.Updated = &now
+	now := Now().UTC()
+	e.Updated = NewUTCDateTime(now)
 	if e.Sequence != nil {
 		*e.Sequence++
 	} else {
@@ -220,6 +293,21 @@ func (e *Event) Touch() {
 	}
 }
 
+// GetExtension returns the extension property with the given name. Values of
+// registered extensions are decoded into a pointer to the registered Go type,
+// others are returned as parsed from JSON.
+func (e *Event) GetExtension(name string) (interface{}, error) {
+	return getExtension(e.Extensions, name)
+}
+
+// SetExtension sets an extension property
+func (e *Event) SetExtension(name string, value interface{}) {
+	if e.Extensions == nil {
+		e.Extensions = make(map[string]interface{})
+	}
+	e.Extensions[name] = value
+}
+
 // GetUID returns the event's UID (implements CalendarObject)
 func (e *Event) GetUID() string {
 	return e.UID
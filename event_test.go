@@ -325,7 +325,7 @@ func TestEventTouch(t *testing.T) {
 
 	event.Touch()
 
-	if event.Updated.Equal(*originalUpdated) {
+	if event.Updated.Equal(originalUpdated) {
 		t.Error("Expected Updated timestamp to change")
 	}
 
@@ -429,7 +429,7 @@ func TestAddAlert(t *testing.T) {
 	if event.Alerts["15min"] == nil {
 		t.Error("Alert should be accessible by ID")
 	}
-	if event.Alerts["15min"].Trigger.Offset != "-PT15M" {
+	if trigger, ok := event.Alerts["15min"].Trigger.(*OffsetTrigger); !ok || trigger.Offset != "-PT15M" {
 		t.Error("Alert details should be preserved")
 	}
 
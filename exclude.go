@@ -0,0 +1,75 @@
+package jscal
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ExcludeDates removes instances from the recurring event. Single dates
+// can't be expressed as excludedRecurrenceRules, which repeat from the
+// start, so an instance the rules produce gets an override with
+// "excluded": true, replacing any patch it had. An extra instance that only
+// exists as an override, like an iCalendar RDATE, or one excludedRecurrenceRules
+// match but an override brings back, has its override removed.
+//
+// A date that isn't an instance of the event is an error and leaves the
+// event unchanged.
+func (e *Event) ExcludeDates(dates ...LocalDateTime) error {
+	if len(e.RecurrenceRules) == 0 && len(e.RecurrenceOverrides) == 0 {
+		return fmt.Errorf("event %s is not recurring", e.UID)
+	}
+	if e.Start == nil {
+		return fmt.Errorf("event %s has no start", e.UID)
+	}
+	if len(dates) == 0 {
+		return nil
+	}
+
+	last := wallClock(dates[0])
+	for _, date := range dates[1:] {
+		if wallClock(date).After(last) {
+			last = wallClock(date)
+		}
+	}
+	to := LocalDateTime(last.Add(time.Nanosecond))
+	ruled, err := instanceSet(*e.Start, e.RecurrenceRules, nil, to)
+	if err != nil {
+		return fmt.Errorf("event %s: %w", e.UID, err)
+	}
+	remaining, err := instanceSet(*e.Start, e.RecurrenceRules, e.ExcludedRecurrenceRules, to)
+	if err != nil {
+		return fmt.Errorf("event %s: %w", e.UID, err)
+	}
+
+	for _, date := range dates {
+		if _, ok := e.GetOverride(date); !ok && !ruled[wallClock(date)] {
+			return fmt.Errorf("event %s: %s is not an instance", e.UID, date)
+		}
+	}
+	for _, date := range dates {
+		if remaining[wallClock(date)] {
+			if err := e.SetOverride(date, PatchObject{"excluded": true}); err != nil {
+				return err
+			}
+		} else {
+			e.DeleteOverride(date)
+		}
+	}
+	e.Touch()
+	return nil
+}
+
+// instanceSet returns the date-times rules produce up to (but not
+// including) to, less those matched by excludedRules, ignoring overrides
+func instanceSet(start LocalDateTime, rules, excludedRules []RecurrenceRule, to LocalDateTime) (map[time.Time]bool, error) {
+	ids, _, err := recurrenceSet(context.Background(), start, rules, excludedRules, nil, to)
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[time.Time]bool, len(ids))
+	for _, id := range ids {
+		set[time.Time(id)] = true
+	}
+	return set, nil
+}
@@ -0,0 +1,73 @@
+package jscal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExcludeDates(t *testing.T) {
+	event := newDailyStandup()
+	// No standups on Sundays, but the override brings 2025-03-09 back
+	event.ExcludedRecurrenceRules = []RecurrenceRule{
+		{Type: "RecurrenceRule", Frequency: FrequencyWeekly, ByDay: []NDay{{Day: DaySunday}}},
+	}
+	event.RecurrenceOverrides["2025-03-09T09:00:00"] = map[string]interface{}{"title": "Sunday standup"}
+	event.RecurrenceOverrides["2025-03-20T09:00:00"] = map[string]interface{}{"title": "Extra standup"}
+
+	at := func(day int) LocalDateTime {
+		return LocalDateTime(time.Date(2025, 3, day, 9, 0, 0, 0, time.UTC))
+	}
+	if err := event.ExcludeDates(at(4), at(9), at(20), at(5)); err != nil {
+		t.Fatalf("ExcludeDates failed: %v", err)
+	}
+
+	tests := []struct {
+		day      int
+		want     bool
+		excluded bool
+	}{
+		{day: 4, want: true, excluded: true}, // patch replaced
+		{day: 5, want: true, excluded: true},
+		{day: 9},  // back to being excluded by the rule
+		{day: 20}, // extra instance removed
+	}
+	for _, tt := range tests {
+		patch, ok := event.GetOverride(at(tt.day))
+		if ok != tt.want {
+			t.Errorf("Expected override for March %d: %v, got %v", tt.day, tt.want, ok)
+			continue
+		}
+		if excluded, _ := patch["excluded"].(bool); excluded != tt.excluded || (ok && len(patch) != 1) {
+			t.Errorf("Expected March %d to be excluded, got %v", tt.day, patch)
+		}
+	}
+
+	occurrences, err := event.Occurrences(at(1), at(31))
+	if err != nil {
+		t.Fatalf("Occurrences failed: %v", err)
+	}
+	for _, occurrence := range occurrences {
+		switch time.Time(occurrence.RecurrenceId).Day() {
+		case 4, 5, 9, 20:
+			t.Errorf("Expected %s to be excluded", occurrence.RecurrenceId)
+		}
+	}
+}
+
+func TestExcludeDatesInvalid(t *testing.T) {
+	event := newDailyStandup()
+	before := len(event.RecurrenceOverrides)
+	notAnInstance := LocalDateTime(time.Date(2025, 3, 5, 10, 0, 0, 0, time.UTC))
+	if err := event.ExcludeDates(*event.Start, notAnInstance); err == nil {
+		t.Error("Expected an error for a date that isn't an instance")
+	}
+	if len(event.RecurrenceOverrides) != before {
+		t.Errorf("Expected the event to be unchanged, got %v", event.RecurrenceOverrides)
+	}
+
+	single := NewEvent("single", "Lunch")
+	single.Start = NewLocalDateTime(time.Date(2025, 3, 5, 12, 0, 0, 0, time.UTC))
+	if err := single.ExcludeDates(*single.Start); err == nil {
+		t.Error("Expected an error for an event that isn't recurring")
+	}
+}
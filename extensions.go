@@ -0,0 +1,246 @@
+package jscal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// knownPropertyCache maps a struct type to the set of its JSON property names
+var knownPropertyCache sync.Map // reflect.Type -> map[string]bool
+
+// knownProperties returns the JSON property names declared by a struct type
+func knownProperties(t reflect.Type) map[string]bool {
+	if known, ok := knownPropertyCache.Load(t); ok {
+		return known.(map[string]bool)
+	}
+
+	known := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			known[name] = true
+		}
+	}
+	knownPropertyCache.Store(t, known)
+	return known
+}
+
+// isKnownProperty reports whether t declares the property name, matched
+// case-insensitively as encoding/json matches it
+func isKnownProperty(t reflect.Type, name string) bool {
+	if knownProperties(t)[name] {
+		return true
+	}
+	_, ok := foldedProperty(t, name)
+	return ok
+}
+
+// extractExtensions returns the top-level properties of a JSON object that
+// are not declared by the struct type t, or nil if there are none
+func extractExtensions(data []byte, t reflect.Type) (map[string]interface{}, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	var extensions map[string]interface{}
+	for name, value := range raw {
+		if isKnownProperty(t, name) {
+			continue
+		}
+		var v interface{}
+		if err := json.Unmarshal(value, &v); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal extension property %s: %w", name, err)
+		}
+		if extensions == nil {
+			extensions = make(map[string]interface{})
+		}
+		extensions[name] = v
+	}
+	return extensions, nil
+}
+
+// appendExtensions appends extension properties to an encoded JSON object.
+// Extensions are written in sorted order after the declared properties and
+// never replace a declared property of the struct type t.
+func appendExtensions(data []byte, extensions map[string]interface{}, t reflect.Type) ([]byte, error) {
+	if len(extensions) == 0 {
+		return data, nil
+	}
+
+	var buf bytes.Buffer
+	buf.Write(bytes.TrimSuffix(bytes.TrimSpace(data), []byte("}")))
+	empty := bytes.HasSuffix(bytes.TrimSpace(buf.Bytes()), []byte("{"))
+
+	for _, name := range sortedKeys(extensions) {
+		if isKnownProperty(t, name) {
+			continue
+		}
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(extensions[name])
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal extension property %s: %w", name, err)
+		}
+		if !empty {
+			buf.WriteByte(',')
+		}
+		empty = false
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// ExtensionValidator validates a decoded extension value. Returned
+// ValidationErrors use field names relative to the extension property.
+type ExtensionValidator func(value interface{}) error
+
+// registeredExtension is the Go type and validator of an extension property
+type registeredExtension struct {
+	typ      reflect.Type
+	validate ExtensionValidator
+}
+
+var (
+	extensionMu       sync.RWMutex
+	extensionRegistry = make(map[string]registeredExtension)
+)
+
+// RegisterExtension registers a Go type and an optional validator for vendor
+// extension properties (RFC 8984 §3.3). The name is either a full property
+// name such as "acme.com:room" or a vendor prefix such as "acme.com", which
+// covers every "acme.com:..." property. prototype is a value of the Go type,
+// e.g. AcmeRoom{}. It panics if the name is empty or already registered.
+func RegisterExtension(name string, prototype interface{}, validate ExtensionValidator) {
+	if name == "" || prototype == nil {
+		panic("jscal: RegisterExtension requires a name and a prototype")
+	}
+	typ := reflect.TypeOf(prototype)
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	extensionMu.Lock()
+	defer extensionMu.Unlock()
+	if _, dup := extensionRegistry[name]; dup {
+		panic("jscal: RegisterExtension called twice for " + name)
+	}
+	extensionRegistry[name] = registeredExtension{typ: typ, validate: validate}
+}
+
+// UnregisterExtension removes the registration for a property name or vendor prefix
+func UnregisterExtension(name string) {
+	extensionMu.Lock()
+	defer extensionMu.Unlock()
+	delete(extensionRegistry, name)
+}
+
+// lookupExtension returns the registration for a property, preferring an
+// exact name over its vendor prefix
+func lookupExtension(property string) (registeredExtension, bool) {
+	extensionMu.RLock()
+	defer extensionMu.RUnlock()
+	if ext, ok := extensionRegistry[property]; ok {
+		return ext, true
+	}
+	if prefix, _, found := strings.Cut(property, ":"); found {
+		ext, ok := extensionRegistry[prefix]
+		return ext, ok
+	}
+	return registeredExtension{}, false
+}
+
+// decode converts a raw extension value into a pointer to the registered type
+func (r registeredExtension) decode(value interface{}) (interface{}, error) {
+	// Values set from Go code may already have the registered type
+	if v := reflect.ValueOf(value); v.IsValid() {
+		if v.Type() == reflect.PointerTo(r.typ) {
+			return value, nil
+		}
+		if v.Type() == r.typ {
+			ptr := reflect.New(r.typ)
+			ptr.Elem().Set(v)
+			return ptr.Interface(), nil
+		}
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	ptr := reflect.New(r.typ)
+	if err := json.Unmarshal(data, ptr.Interface()); err != nil {
+		return nil, err
+	}
+	return ptr.Interface(), nil
+}
+
+// getExtension returns an extension value, decoded into its registered Go
+// type if there is one
+func getExtension(extensions map[string]interface{}, name string) (interface{}, error) {
+	value, ok := extensions[name]
+	if !ok {
+		return nil, fmt.Errorf("extension %s not set", name)
+	}
+	ext, registered := lookupExtension(name)
+	if !registered {
+		return value, nil
+	}
+	decoded, err := ext.decode(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode extension %s as %s: %w", name, ext.typ, err)
+	}
+	return decoded, nil
+}
+
+// validateExtensions runs the registered validators over extension properties
+func validateExtensions(extensions map[string]interface{}) ValidationErrors {
+	var errors ValidationErrors
+	for _, name := range sortedKeys(extensions) {
+		ext, registered := lookupExtension(name)
+		if !registered {
+			continue
+		}
+
+		decoded, err := ext.decode(extensions[name])
+		if err != nil {
+			errors = append(errors, ValidationError{
+				Field:   name,
+				Value:   extensions[name],
+				Message: fmt.Sprintf("invalid %s extension: %v", name, err),
+			})
+			continue
+		}
+		if ext.validate == nil {
+			continue
+		}
+
+		switch err := ext.validate(decoded).(type) {
+		case nil:
+		case ValidationErrors:
+			for _, valErr := range err {
+				valErr.Field = joinPath(name, valErr.Field)
+				errors = append(errors, valErr)
+			}
+		case ValidationError:
+			err.Field = joinPath(name, err.Field)
+			errors = append(errors, err)
+		default:
+			errors = append(errors, ValidationError{
+				Field:   name,
+				Value:   extensions[name],
+				Message: fmt.Sprintf("invalid %s extension: %v", name, err),
+			})
+		}
+	}
+	return errors
+}
@@ -0,0 +1,260 @@
+package jscal
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestEventExtensionsRoundTrip(t *testing.T) {
+	input := `{
+		"@type": "Event",
+		"uid": "ext-event",
+		"title": "Vendor data",
+		"start": "2025-01-01T09:00:00",
+		"example.com:custom": {"room": "4B", "capacity": 12},
+		"example.com:flag": true
+	}`
+
+	var event Event
+	if err := json.Unmarshal([]byte(input), &event); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	if len(event.Extensions) != 2 {
+		t.Fatalf("Expected 2 extensions, got %v", event.Extensions)
+	}
+	custom, ok := event.Extensions["example.com:custom"].(map[string]interface{})
+	if !ok || custom["room"] != "4B" {
+		t.Errorf("Expected example.com:custom to be preserved, got %v", event.Extensions["example.com:custom"])
+	}
+	if _, ok := event.Extensions["title"]; ok {
+		t.Error("Expected declared properties not to be captured as extensions")
+	}
+
+	data, err := event.JSON()
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	if !strings.Contains(string(data), `"example.com:flag":true`) {
+		t.Errorf("Expected extension in output, got %s", data)
+	}
+
+	var roundTrip Event
+	if err := json.Unmarshal(data, &roundTrip); err != nil {
+		t.Fatalf("Failed to unmarshal round trip: %v", err)
+	}
+	if roundTrip.Title == nil || *roundTrip.Title != "Vendor data" || len(roundTrip.Extensions) != 2 {
+		t.Errorf("Expected event and extensions to survive the round trip, got %+v", roundTrip)
+	}
+
+	if clone := event.Clone(); clone.Extensions["example.com:flag"] != true {
+		t.Errorf("Expected Clone to copy extensions, got %v", clone.Extensions)
+	}
+}
+
+func TestEventExtensionsDoNotOverrideProperties(t *testing.T) {
+	event := NewEvent("ext-override", "Real title")
+	event.Extensions = map[string]interface{}{
+		"title":          "Shadow title",
+		"vendor.example": 1,
+	}
+
+	data, err := event.JSON()
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Output is not valid JSON: %v (%s)", err, data)
+	}
+	if decoded["title"] != "Real title" {
+		t.Errorf("Expected declared title to win, got %v", decoded["title"])
+	}
+	if decoded["vendor.example"] != float64(1) {
+		t.Errorf("Expected vendor.example extension, got %v", decoded["vendor.example"])
+	}
+}
+
+func TestExtensionsMatchPropertiesCaseInsensitively(t *testing.T) {
+	data := []byte(`{"@type": "Event", "uid": "e1", "Title": "Folded", "vendor:x": 1}`)
+	var event Event
+	if err := json.Unmarshal(data, &event); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	if event.GetTitle() != "Folded" {
+		t.Errorf("Expected the title from Title, got %q", event.GetTitle())
+	}
+	if _, ok := event.Extensions["Title"]; ok || len(event.Extensions) != 1 {
+		t.Errorf("Expected only vendor:x as an extension, got %v", event.Extensions)
+	}
+
+	var group Group
+	if err := json.Unmarshal([]byte(`{"@type": "Group", "uid": "g1", "ENTRIES": []}`), &group); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	if group.Extensions != nil {
+		t.Errorf("Expected no extensions, got %v", group.Extensions)
+	}
+
+	// An extension differing from a declared property only in case isn't
+	// written, since parsing it back would override the property
+	event.Extensions["TITLE"] = "Shadow"
+	out, err := event.JSON()
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	if strings.Contains(string(out), "TITLE") {
+		t.Errorf("Expected TITLE to be left out, got %s", out)
+	}
+}
+
+func TestEventWithoutExtensions(t *testing.T) {
+	var event Event
+	if err := json.Unmarshal([]byte(`{"@type":"Event","uid":"plain"}`), &event); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	if event.Extensions != nil {
+		t.Errorf("Expected nil extensions, got %v", event.Extensions)
+	}
+
+	data, err := json.Marshal(&Task{Type: "Task", UID: "t", Extensions: map[string]interface{}{}})
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	if string(data) != `{"@type":"Task","uid":"t"}` {
+		t.Errorf("Unexpected output %s", data)
+	}
+}
+
+func TestGroupExtensionsRoundTrip(t *testing.T) {
+	input := `{
+		"@type": "Group",
+		"uid": "ext-group",
+		"example.com:source-id": "abc",
+		"entries": [
+			{"@type": "Event", "uid": "e1", "example.com:color": "teal"},
+			{"@type": "Task", "uid": "t1", "example.com:points": 3}
+		]
+	}`
+
+	var group Group
+	if err := json.Unmarshal([]byte(input), &group); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	if group.Extensions["example.com:source-id"] != "abc" {
+		t.Errorf("Expected group extension, got %v", group.Extensions)
+	}
+	if _, ok := group.Extensions["entries"]; ok {
+		t.Error("Expected entries not to be captured as an extension")
+	}
+	if e := group.Entries[0].(*Event); e.Extensions["example.com:color"] != "teal" {
+		t.Errorf("Expected event extension, got %v", e.Extensions)
+	}
+	if task := group.Entries[1].(*Task); task.Extensions["example.com:points"] != float64(3) {
+		t.Errorf("Expected task extension, got %v", task.Extensions)
+	}
+
+	data, err := group.JSON()
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	for _, want := range []string{`"example.com:source-id":"abc"`, `"example.com:color":"teal"`, `"example.com:points":3`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Expected %s in output, got %s", want, data)
+		}
+	}
+}
+
+type testRoom struct {
+	Building string `json:"building"`
+	Capacity int    `json:"capacity"`
+}
+
+func registerTestRoom(t *testing.T) {
+	t.Helper()
+	RegisterExtension("acme.com", testRoom{}, func(value interface{}) error {
+		room := value.(*testRoom)
+		if room.Capacity <= 0 {
+			return ValidationErrors{{Field: "capacity", Value: room.Capacity, Message: "must be positive"}}
+		}
+		return nil
+	})
+	t.Cleanup(func() { UnregisterExtension("acme.com") })
+}
+
+func TestGetExtension(t *testing.T) {
+	registerTestRoom(t)
+
+	var event Event
+	input := `{"@type":"Event","uid":"room","start":"2025-01-01T09:00:00",
+		"acme.com:room":{"building":"HQ","capacity":8},"other.org:note":"hi"}`
+	if err := json.Unmarshal([]byte(input), &event); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+
+	value, err := event.GetExtension("acme.com:room")
+	if err != nil {
+		t.Fatalf("GetExtension failed: %v", err)
+	}
+	room, ok := value.(*testRoom)
+	if !ok || room.Building != "HQ" || room.Capacity != 8 {
+		t.Errorf("Expected *testRoom{HQ 8}, got %#v", value)
+	}
+
+	if note, err := event.GetExtension("other.org:note"); err != nil || note != "hi" {
+		t.Errorf("Expected raw value for unregistered extension, got %v (%v)", note, err)
+	}
+	if _, err := event.GetExtension("acme.com:missing"); err == nil {
+		t.Error("Expected error for missing extension")
+	}
+
+	task := NewTask("typed", "Typed value")
+	task.SetExtension("acme.com:room", testRoom{Building: "Annex", Capacity: 2})
+	if value, err := task.GetExtension("acme.com:room"); err != nil || value.(*testRoom).Building != "Annex" {
+		t.Errorf("Expected typed value set from Go, got %v (%v)", value, err)
+	}
+}
+
+func TestValidateRegisteredExtensions(t *testing.T) {
+	registerTestRoom(t)
+
+	event := NewEvent("room-validate", "Meeting")
+	event.SetExtension("acme.com:room", map[string]interface{}{"building": "HQ", "capacity": 0})
+	event.SetExtension("other.org:anything", []int{1, 2})
+
+	err := event.Validate()
+	errs, ok := err.(ValidationErrors)
+	if !ok || len(errs) != 1 || errs[0].Field != "acme.com:room.capacity" {
+		t.Fatalf("Expected acme.com:room.capacity error, got %v", err)
+	}
+
+	event.SetExtension("acme.com:room", map[string]interface{}{"capacity": "many"})
+	if err := event.Validate(); err == nil || !strings.Contains(err.Error(), "invalid acme.com:room extension") {
+		t.Errorf("Expected decode error, got %v", err)
+	}
+
+	event.SetExtension("acme.com:room", &testRoom{Building: "HQ", Capacity: 4})
+	if err := event.Validate(); err != nil {
+		t.Errorf("Expected valid event, got %v", err)
+	}
+
+	group := NewGroup("room-group", "Rooms")
+	group.SetExtension("acme.com:room", testRoom{})
+	if err := group.AddEntry(event); err != nil {
+		t.Fatalf("Failed to add entry: %v", err)
+	}
+	if err := group.Validate(); err == nil || !strings.Contains(err.Error(), "capacity") {
+		t.Errorf("Expected group extension error, got %v", err)
+	}
+}
+
+func TestRegisterExtensionTwicePanics(t *testing.T) {
+	registerTestRoom(t)
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic on duplicate registration")
+		}
+	}()
+	RegisterExtension("acme.com", testRoom{}, nil)
+}
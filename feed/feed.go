@@ -0,0 +1,156 @@
+// Package feed subscribes to published calendar feeds, such as public
+// holiday or sports calendars served as iCalendar over webcal or https, and
+// reports which events changed since the previous fetch.
+//
+// Feeds are converted with a registered converter, so the iCalendar module
+// must be imported for its side effect:
+//
+//	import _ "github.com/airtrafik/jscal/convert/ical"
+//
+//	f := feed.New("webcal://example.com/holidays.ics")
+//	changes, err := f.Fetch(ctx)
+//	for _, e := range changes.Added {
+//		fmt.Println(*e.Title)
+//	}
+//
+// A Feed remembers the ETag, Last-Modified date and events of the last
+// fetch, and sends conditional requests so unchanged feeds aren't downloaded
+// again. It marshals to JSON, so the state can be saved between runs.
+package feed
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/airtrafik/jscal"
+	"github.com/airtrafik/jscal/convert"
+	"github.com/airtrafik/jscal/observe"
+)
+
+// Feed is a subscription to a calendar feed
+type Feed struct {
+	URL          string         `json:"url"`
+	ETag         string         `json:"etag,omitempty"`         // ETag of the last response
+	LastModified string         `json:"lastModified,omitempty"` // Last-Modified of the last response
+	Events       []*jscal.Event `json:"events,omitempty"`       // Events of the last fetch
+
+	Client    *http.Client      `json:"-"` // Defaults to http.DefaultClient
+	Converter convert.Converter `json:"-"` // Defaults to the registered "ical" converter
+	Hooks     *observe.Hooks    `json:"-"` // Receives a span for each fetch
+}
+
+// Changes reports how a feed changed since the previous fetch
+type Changes struct {
+	Added       []*jscal.Event // Events new to the feed
+	Changed     []*jscal.Event // Events whose content changed, as now published
+	Removed     []*jscal.Event // Events no longer in the feed, as last seen
+	NotModified bool           // The server reported the feed unchanged
+}
+
+// HasChanges returns true if any event was added, changed or removed
+func (c *Changes) HasChanges() bool {
+	return len(c.Added)+len(c.Changed)+len(c.Removed) > 0
+}
+
+// String formats the changes as "N added, N changed, N removed"
+func (c *Changes) String() string {
+	return fmt.Sprintf("%d added, %d changed, %d removed", len(c.Added), len(c.Changed), len(c.Removed))
+}
+
+// New creates a subscription to the feed at url. webcal URLs are fetched
+// over https.
+func New(url string) *Feed {
+	return &Feed{URL: url}
+}
+
+// Fetch downloads the feed and compares its events with those of the
+// previous fetch by UID (and recurrenceId for overridden occurrences). The
+// first fetch reports every event as added. On error the feed is left
+// unmodified.
+func (f *Feed) Fetch(ctx context.Context) (*Changes, error) {
+	ctx, span := f.Hooks.Start(ctx, "jscal.feed.fetch", slog.String("url", f.URL))
+	changes, err := f.fetch(ctx)
+	if changes != nil {
+		span.SetAttributes(slog.Bool("not_modified", changes.NotModified), slog.Int("added", len(changes.Added)),
+			slog.Int("changed", len(changes.Changed)), slog.Int("removed", len(changes.Removed)))
+	}
+	span.End(err)
+	return changes, err
+}
+
+func (f *Feed) fetch(ctx context.Context) (*Changes, error) {
+	converter := f.Converter
+	if converter == nil {
+		c, ok := convert.Lookup("ical")
+		if !ok {
+			return nil, fmt.Errorf("no ical converter registered: import github.com/airtrafik/jscal/convert/ical")
+		}
+		converter = c
+	}
+
+	fetcher := convert.HTTPFetcher{Client: f.Client, Accept: "text/calendar, */*;q=0.1"}
+	data, validators, err := fetcher.FetchIfModified(ctx, f.URL, convert.Validators{ETag: f.ETag, LastModified: f.LastModified})
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return &Changes{NotModified: true}, nil
+	}
+
+	events, err := convert.ParseAllContext(ctx, converter, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse feed %s: %w", f.URL, err)
+	}
+	changes, err := diff(f.Events, events)
+	if err != nil {
+		return nil, fmt.Errorf("feed %s: %w", f.URL, err)
+	}
+
+	f.Events = events
+	f.ETag, f.LastModified = validators.ETag, validators.LastModified
+	return changes, nil
+}
+
+// eventKey identifies an event in a feed: its UID, plus the recurrenceId
+// for overrides of a single occurrence
+func eventKey(e *jscal.Event) string {
+	if e.RecurrenceId != nil {
+		return e.UID + "#" + e.RecurrenceId.String()
+	}
+	return e.UID
+}
+
+// diff compares two fetches of a feed, keeping the feed's order
+func diff(previous, current []*jscal.Event) (*Changes, error) {
+	old := make(map[string]*jscal.Event, len(previous))
+	for _, e := range previous {
+		old[eventKey(e)] = e
+	}
+
+	changes := &Changes{}
+	seen := make(map[string]bool, len(current))
+	for _, e := range current {
+		key := eventKey(e)
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate event %s", key)
+		}
+		seen[key] = true
+
+		before, ok := old[key]
+		switch {
+		case !ok:
+			changes.Added = append(changes.Added, e)
+		case !convert.SameContent(before, e):
+			changes.Changed = append(changes.Changed, e)
+		}
+	}
+
+	for _, e := range previous {
+		if !seen[eventKey(e)] {
+			changes.Removed = append(changes.Removed, e)
+		}
+	}
+	return changes, nil
+}
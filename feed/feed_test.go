@@ -0,0 +1,206 @@
+package feed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/airtrafik/jscal"
+	"github.com/airtrafik/jscal/observe"
+)
+
+// lineConverter is a test converter for feeds with one "uid title" event per line
+type lineConverter struct{}
+
+func (lineConverter) Parse(data []byte) (*jscal.Event, error) { return nil, errors.New("unused") }
+func (lineConverter) Format(*jscal.Event) ([]byte, error)     { return nil, errors.New("unused") }
+func (lineConverter) FormatAll([]*jscal.Event) ([]byte, error) {
+	return nil, errors.New("unused")
+}
+func (lineConverter) Detect(data []byte) bool { return true }
+func (lineConverter) ParseAll(data []byte) ([]*jscal.Event, error) {
+	var events []*jscal.Event
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		uid, title, _ := strings.Cut(line, " ")
+		events = append(events, &jscal.Event{
+			Type:  "Event",
+			UID:   uid,
+			Title: jscal.String(title),
+			Start: jscal.NewLocalDateTime(time.Date(2025, 12, 25, 0, 0, 0, 0, time.UTC)),
+		})
+	}
+	return events, nil
+}
+
+// feedServer serves body with an ETag derived from it and honors If-None-Match
+type feedServer struct {
+	body     string
+	requests int
+}
+
+func (s *feedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.requests++
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(s.body))
+	etag := fmt.Sprintf(`"%x"`, h.Sum64())
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Content-Type", "text/calendar")
+	_, _ = w.Write([]byte(s.body))
+}
+
+func uids(events []*jscal.Event) string {
+	names := make([]string, len(events))
+	for i, e := range events {
+		names[i] = e.UID
+	}
+	return strings.Join(names, ",")
+}
+
+func TestFetch(t *testing.T) {
+	source := &feedServer{body: "xmas Christmas\nnye New Year's Eve\neaster Easter"}
+	server := httptest.NewServer(source)
+	defer server.Close()
+
+	f := New(server.URL + "/holidays.ics")
+	f.Converter = lineConverter{}
+
+	changes, err := f.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if uids(changes.Added) != "xmas,nye,easter" || changes.NotModified {
+		t.Errorf("Expected all events added on first fetch, got %s", changes)
+	}
+	if f.ETag == "" {
+		t.Error("Expected ETag to be recorded")
+	}
+
+	// Unchanged feed: the server answers 304
+	changes, err = f.Fetch(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changes.NotModified || changes.HasChanges() {
+		t.Errorf("Expected not modified, got %s (notModified=%v)", changes, changes.NotModified)
+	}
+
+	source.body = "xmas Christmas Day\nnye New Year's Eve\nboxing Boxing Day"
+	changes, err = f.Fetch(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uids(changes.Added) != "boxing" || uids(changes.Changed) != "xmas" || uids(changes.Removed) != "easter" {
+		t.Errorf("Unexpected changes: added %s, changed %s, removed %s",
+			uids(changes.Added), uids(changes.Changed), uids(changes.Removed))
+	}
+	if changes.String() != "1 added, 1 changed, 1 removed" {
+		t.Errorf("Unexpected summary %q", changes.String())
+	}
+	if source.requests != 3 {
+		t.Errorf("Expected 3 requests, got %d", source.requests)
+	}
+}
+
+func TestFetchHooks(t *testing.T) {
+	source := &feedServer{body: "xmas Christmas\nnye New Year's Eve"}
+	server := httptest.NewServer(source)
+	defer server.Close()
+
+	recorder := &observe.Recorder{}
+	f := New(server.URL)
+	f.Converter = lineConverter{}
+	f.Hooks = &observe.Hooks{Tracer: recorder}
+	for i := 0; i < 2; i++ {
+		if _, err := f.Fetch(context.Background()); err != nil {
+			t.Fatalf("Fetch failed: %v", err)
+		}
+	}
+
+	if len(recorder.Spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(recorder.Spans))
+	}
+	first, second := recorder.Spans[0], recorder.Spans[1]
+	if first.Name != "jscal.feed.fetch" || first.Attr("url").String() != server.URL || first.Attr("added").Int64() != 2 {
+		t.Errorf("Unexpected span %+v", first)
+	}
+	if !second.Attr("not_modified").Bool() {
+		t.Errorf("Expected the second fetch to be not modified, got %+v", second)
+	}
+}
+
+func TestFeedStateRoundTrip(t *testing.T) {
+	source := &feedServer{body: "xmas Christmas"}
+	server := httptest.NewServer(source)
+	defer server.Close()
+
+	f := New(server.URL)
+	f.Converter = lineConverter{}
+	if _, err := f.Fetch(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var restored Feed
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatal(err)
+	}
+	restored.Converter = lineConverter{}
+
+	changes, err := restored.Fetch(context.Background())
+	if err != nil || !changes.NotModified {
+		t.Errorf("Expected restored feed to send a conditional request, got %v (%v)", changes, err)
+	}
+}
+
+func TestFetchErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/dup.ics" {
+			_, _ = w.Write([]byte("a One\na Again"))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		url     string
+		wantErr string
+	}{
+		{name: "not found", url: server.URL + "/missing.ics", wantErr: "404"},
+		{name: "duplicate event", url: server.URL + "/dup.ics", wantErr: "duplicate event"},
+		{name: "invalid url", url: "://nowhere", wantErr: "invalid source URI"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := New(tt.url)
+			f.Converter = lineConverter{}
+			_, err := f.Fetch(context.Background())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+			if f.Events != nil || f.ETag != "" {
+				t.Error("Expected feed to be left unmodified")
+			}
+		})
+	}
+
+	if _, err := New(server.URL).Fetch(context.Background()); err == nil || !strings.Contains(err.Error(), "no ical converter") {
+		t.Errorf("Expected missing converter error, got %v", err)
+	}
+}
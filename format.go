@@ -0,0 +1,84 @@
+package jscal
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// FormatOptions configures Format and MarshalAll. The zero value writes
+// compact JSON like json.Marshal.
+type FormatOptions struct {
+	// Indent indents nested values by this string, e.g. "  "
+	Indent string
+
+	// EnforceType rejects objects, including group entries, whose @type
+	// doesn't match their Go type, e.g. an Event without "@type": "Event"
+	EnforceType bool
+}
+
+// Format marshals any JSCalendar object to JSON, the counterpart of Parse
+func Format(obj CalendarObject, opts FormatOptions) ([]byte, error) {
+	if err := checkFormatType(obj, opts); err != nil {
+		return nil, err
+	}
+	return marshalFormatted(obj, opts)
+}
+
+// MarshalAll marshals JSCalendar objects of any type to a JSON array, the
+// counterpart of ParseAll
+func MarshalAll(objs []CalendarObject, opts FormatOptions) ([]byte, error) {
+	for i, obj := range objs {
+		if err := checkFormatType(obj, opts); err != nil {
+			return nil, fmt.Errorf("object at index %d: %w", i, err)
+		}
+	}
+	if objs == nil {
+		objs = []CalendarObject{}
+	}
+	return marshalFormatted(objs, opts)
+}
+
+func marshalFormatted(v interface{}, opts FormatOptions) ([]byte, error) {
+	if opts.Indent != "" {
+		return json.MarshalIndent(v, "", opts.Indent)
+	}
+	return json.Marshal(v)
+}
+
+// checkFormatType rejects nil objects and, with EnforceType, objects whose
+// @type doesn't match their Go type
+func checkFormatType(obj CalendarObject, opts FormatOptions) error {
+	if obj == nil {
+		return fmt.Errorf("cannot format nil object")
+	}
+	if !opts.EnforceType {
+		return nil
+	}
+
+	if err := checkObjectType(obj); err != nil {
+		return err
+	}
+	if g, ok := obj.(*Group); ok {
+		return g.Walk(checkObjectType)
+	}
+	return nil
+}
+
+// checkObjectType checks the @type of a single object against its Go type
+func checkObjectType(obj CalendarObject) error {
+	var expected string
+	switch obj.(type) {
+	case *Event:
+		expected = "Event"
+	case *Task:
+		expected = "Task"
+	case *Group:
+		expected = "Group"
+	default:
+		return nil
+	}
+	if obj.GetType() != expected {
+		return fmt.Errorf("object '%s' has @type '%s', expected '%s'", obj.GetUID(), obj.GetType(), expected)
+	}
+	return nil
+}
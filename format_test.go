@@ -0,0 +1,74 @@
+package jscal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	event := &Event{Type: "Event", UID: "event-1", Title: String("Review")}
+	untyped := &Event{UID: "event-2"}
+	group := &Group{Type: "Group", UID: "group-1", Entries: []CalendarObject{untyped}}
+
+	tests := []struct {
+		name     string
+		obj      CalendarObject
+		opts     FormatOptions
+		expected string
+		wantErr  bool
+	}{
+		{"compact", event, FormatOptions{}, `{"@type":"Event","uid":"event-1","title":"Review"}`, false},
+		{"indented", &Task{Type: "Task", UID: "task-1"}, FormatOptions{Indent: " "},
+			"{\n \"@type\": \"Task\",\n \"uid\": \"task-1\"\n}", false},
+		{"missing type", untyped, FormatOptions{}, `{"@type":"","uid":"event-2"}`, false},
+		{"missing type enforced", untyped, FormatOptions{EnforceType: true}, "", true},
+		{"wrong entry type enforced", group, FormatOptions{EnforceType: true}, "", true},
+		{"nil", nil, FormatOptions{}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := Format(tt.obj, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && string(data) != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, data)
+			}
+		})
+	}
+}
+
+func TestMarshalAll(t *testing.T) {
+	objects := []CalendarObject{
+		NewEvent("event-1", "Meeting"),
+		NewTask("task-1", "Write"),
+		NewGroup("group-1", "Calendar"),
+	}
+
+	data, err := MarshalAll(objects, FormatOptions{EnforceType: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseAll(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed) != len(objects) {
+		t.Fatalf("Expected %d objects, got %d", len(objects), len(parsed))
+	}
+	for i, obj := range parsed {
+		if obj.GetUID() != objects[i].GetUID() || obj.GetType() != objects[i].GetType() {
+			t.Errorf("Expected %s %s, got %s %s", objects[i].GetType(), objects[i].GetUID(), obj.GetType(), obj.GetUID())
+		}
+	}
+
+	if data, _ := MarshalAll(nil, FormatOptions{}); string(data) != "[]" {
+		t.Errorf("Expected [], got %s", data)
+	}
+
+	_, err = MarshalAll([]CalendarObject{objects[0], &Task{UID: "task-2"}}, FormatOptions{EnforceType: true})
+	if err == nil || !strings.Contains(err.Error(), "index 1") {
+		t.Errorf("Expected an error for the object at index 1, got %v", err)
+	}
+}
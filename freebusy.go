@@ -0,0 +1,141 @@
+package jscal
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// BusyPeriod is a span of time that isn't free
+type BusyPeriod struct {
+	Start  time.Time
+	End    time.Time
+	Status string // FreeBusyBusy, FreeBusyTentative or FreeBusyUnavailable
+}
+
+// FreeBusy is the availability of a calendar over a time range, the
+// information published by iCalendar VFREEBUSY components
+type FreeBusy struct {
+	UID       string       // Identifies the request or the published availability
+	Organizer string       // Calendar address of the requester, e.g. "mailto:..."
+	Attendee  string       // Calendar address of the calendar's owner
+	Start     time.Time    // Start of the time range
+	End       time.Time    // End of the time range
+	Busy      []BusyPeriod // Sorted by start
+}
+
+// IsFree reports whether no busy period overlaps [start, end)
+func (fb *FreeBusy) IsFree(start, end time.Time) bool {
+	if fb == nil {
+		return true
+	}
+	for _, period := range fb.Busy {
+		if period.Start.Before(end) && period.End.After(start) {
+			return false
+		}
+	}
+	return true
+}
+
+// FreeBusy computes the busy periods of the events between from and to,
+// expanding recurrences. Events or occurrences with freeBusyStatus "free" or
+// status "cancelled" are left out; tentative events are tentatively busy.
+// Floating and all-day times are read in loc, UTC if nil. Overlapping
+// periods of the same status are merged.
+func (es Events) FreeBusy(from, to time.Time, loc *time.Location) (*FreeBusy, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	fb := &FreeBusy{Start: from, End: to}
+	for _, e := range es {
+		if e == nil || e.Start == nil {
+			continue
+		}
+
+		// Occurrences starting before from may still overlap it
+		zone := timeZoneOf(e.TimeZone, e.IsAllDay(), loc)
+		lower := from.In(zone)
+		if duration, err := e.GetDuration(); err == nil && duration > 0 {
+			lower = lower.Add(-duration)
+		}
+		occurrences, err := e.Occurrences(LocalDateTime(lower), LocalDateTime(to.In(zone)))
+		if err != nil {
+			return nil, fmt.Errorf("event %s: %w", e.UID, err)
+		}
+
+		for _, o := range occurrences {
+			status := busyStatus(o.Event)
+			if status == "" {
+				continue
+			}
+			zone := timeZoneOf(o.Event.TimeZone, o.Event.IsAllDay(), loc)
+			start, end := o.Start(), o.End()
+			period := BusyPeriod{Start: start.In(zone), End: end.In(zone), Status: status}
+			if period.Start.Before(from) {
+				period.Start = from
+			}
+			if period.End.After(to) {
+				period.End = to
+			}
+			if period.Start.Before(period.End) {
+				fb.Busy = append(fb.Busy, period)
+			}
+		}
+	}
+	fb.Busy = mergeBusyPeriods(fb.Busy)
+	return fb, nil
+}
+
+// timeZoneOf returns the location of an object's times in the time zone
+// timeZone, or loc for floating and all-day (or date-only) times and zones
+// time.LoadLocation doesn't know
+func timeZoneOf(timeZone *string, allDay bool, loc *time.Location) *time.Location {
+	if timeZone != nil && !allDay {
+		if zone, err := time.LoadLocation(*timeZone); err == nil {
+			return zone
+		}
+	}
+	return loc
+}
+
+// busyStatus returns how an event occupies its time, or "" if it doesn't
+func busyStatus(e *Event) string {
+	if e.GetStatus() == StatusCancelled {
+		return ""
+	}
+	switch e.GetFreeBusyStatus() {
+	case FreeBusyFree:
+		return ""
+	case FreeBusyTentative, FreeBusyUnavailable:
+		return e.GetFreeBusyStatus()
+	}
+	if e.GetStatus() == StatusTentative {
+		return FreeBusyTentative
+	}
+	return FreeBusyBusy
+}
+
+// mergeBusyPeriods sorts periods by start and merges the overlapping or
+// adjacent periods of the same status
+func mergeBusyPeriods(periods []BusyPeriod) []BusyPeriod {
+	sort.SliceStable(periods, func(i, j int) bool {
+		if !periods[i].Start.Equal(periods[j].Start) {
+			return periods[i].Start.Before(periods[j].Start)
+		}
+		return periods[i].Status < periods[j].Status
+	})
+
+	var merged []BusyPeriod
+	last := make(map[string]int) // Index in merged of the latest period of each status
+	for _, period := range periods {
+		if i, ok := last[period.Status]; ok && !period.Start.After(merged[i].End) {
+			if period.End.After(merged[i].End) {
+				merged[i].End = period.End
+			}
+			continue
+		}
+		last[period.Status] = len(merged)
+		merged = append(merged, period)
+	}
+	return merged
+}
@@ -0,0 +1,87 @@
+package jscal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEventsFreeBusy(t *testing.T) {
+	utc := func(day, hour, minute int) time.Time { return time.Date(2025, 3, day, hour, minute, 0, 0, time.UTC) }
+	event := func(uid string, start time.Time, duration string) *Event {
+		e := NewEvent(uid, uid)
+		e.Start = NewLocalDateTime(start)
+		e.TimeZone = String("UTC")
+		e.Duration = String(duration)
+		return e
+	}
+
+	meeting := event("meeting", utc(3, 9, 0), "PT1H")
+	overlap := event("overlap", utc(3, 9, 30), "PT1H")
+	tentative := event("tentative", utc(3, 14, 0), "PT30M")
+	tentative.Status = String(StatusTentative)
+	free := event("free", utc(3, 11, 0), "PT1H")
+	free.FreeBusyStatus = String(FreeBusyFree)
+	cancelled := event("cancelled", utc(3, 12, 0), "PT1H")
+	cancelled.Status = String(StatusCancelled)
+	daily := event("daily", utc(1, 8, 0), "PT30M")
+	daily.RecurrenceRules = []RecurrenceRule{{Type: "RecurrenceRule", Frequency: "daily"}}
+	early := event("early", utc(2, 23, 0), "PT2H") // Starts before the range
+
+	fb, err := Events{meeting, overlap, tentative, free, cancelled, daily, early, nil}.FreeBusy(utc(3, 0, 0), utc(4, 0, 0), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []BusyPeriod{
+		{Start: utc(3, 0, 0), End: utc(3, 1, 0), Status: FreeBusyBusy},
+		{Start: utc(3, 8, 0), End: utc(3, 8, 30), Status: FreeBusyBusy},
+		{Start: utc(3, 9, 0), End: utc(3, 10, 30), Status: FreeBusyBusy},
+		{Start: utc(3, 14, 0), End: utc(3, 14, 30), Status: FreeBusyTentative},
+	}
+	if len(fb.Busy) != len(want) {
+		t.Fatalf("Expected %d busy periods, got %+v", len(want), fb.Busy)
+	}
+	for i, period := range fb.Busy {
+		if !period.Start.Equal(want[i].Start) || !period.End.Equal(want[i].End) || period.Status != want[i].Status {
+			t.Errorf("Expected period %d to be %+v, got %+v", i, want[i], period)
+		}
+	}
+
+	if fb.IsFree(utc(3, 9, 45), utc(3, 10, 0)) {
+		t.Errorf("Expected 09:45 to be busy")
+	}
+	if !fb.IsFree(utc(3, 11, 0), utc(3, 12, 0)) {
+		t.Errorf("Expected 11:00 to be free")
+	}
+}
+
+func TestEventsFreeBusyTimeZones(t *testing.T) {
+	if !hasTimeZoneDatabase() {
+		t.Skip("time zone database not available")
+	}
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+
+	zoned := NewEvent("zoned", "Zoned")
+	zoned.Start = NewLocalDateTime(time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC))
+	zoned.TimeZone = String("Europe/Berlin")
+	zoned.Duration = String("PT1H")
+
+	floating := NewEvent("floating", "Floating")
+	floating.Start = NewLocalDateTime(time.Date(2025, 3, 3, 12, 0, 0, 0, time.UTC))
+	floating.Duration = String("PT1H")
+
+	from := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	fb, err := Events{zoned, floating}.FreeBusy(from, from.Add(24*time.Hour), berlin)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(fb.Busy) != 2 {
+		t.Fatalf("Expected 2 busy periods, got %+v", fb.Busy)
+	}
+	if want := time.Date(2025, 3, 3, 8, 0, 0, 0, time.UTC); !fb.Busy[0].Start.Equal(want) {
+		t.Errorf("Expected zoned event at %v, got %v", want, fb.Busy[0].Start)
+	}
+	if want := time.Date(2025, 3, 3, 11, 0, 0, 0, time.UTC); !fb.Busy[1].Start.Equal(want) {
+		t.Errorf("Expected floating event at %v, got %v", want, fb.Busy[1].Start)
+	}
+}
@@ -0,0 +1,176 @@
+package jscal
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// earthRadius is the mean radius of the earth in meters, used by DistanceTo
+const earthRadius = 6371008.8
+
+// Geo is a position on the WGS-84 ellipsoid, as written in a geo: URI
+// (RFC 5870) such as "geo:48.2010,16.3695,183;u=40"
+type Geo struct {
+	Latitude  float64 // Degrees, -90 to 90
+	Longitude float64 // Degrees, -180 to 180
+
+	// Altitude above the WGS-84 reference ellipsoid in meters
+	Altitude *float64
+
+	// Uncertainty of the position in meters, the "u" parameter
+	Uncertainty *float64
+}
+
+// NewGeo creates a Geo for the given latitude and longitude
+func NewGeo(lat, lon float64) *Geo {
+	return &Geo{Latitude: lat, Longitude: lon}
+}
+
+// ParseGeo parses a geo: URI. Only the wgs84 coordinate reference system is
+// supported; URI parameters other than crs and u are ignored.
+func ParseGeo(uri string) (*Geo, error) {
+	if len(uri) < len("geo:") || !strings.EqualFold(uri[:len("geo:")], "geo:") {
+		return nil, fmt.Errorf("not a geo: URI: %q", uri)
+	}
+	coords, params, _ := strings.Cut(uri[len("geo:"):], ";")
+
+	parts := strings.Split(coords, ",")
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, fmt.Errorf("geo: URI must have 2 or 3 coordinates: %q", uri)
+	}
+	values := make([]float64, len(parts))
+	for i, part := range parts {
+		v, err := parseGeoNumber(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid coordinate %q in geo: URI", part)
+		}
+		values[i] = v
+	}
+
+	g := &Geo{Latitude: values[0], Longitude: values[1]}
+	if len(values) == 3 {
+		g.Altitude = &values[2]
+	}
+
+	if params != "" {
+		for i, param := range strings.Split(params, ";") {
+			name, value, _ := strings.Cut(param, "=")
+			switch strings.ToLower(name) {
+			case "crs":
+				// RFC 5870 section 3.3: crs must come first
+				if i != 0 || !strings.EqualFold(value, "wgs84") {
+					return nil, fmt.Errorf("unsupported coordinate reference system %q", value)
+				}
+			case "u":
+				u, err := parseGeoNumber(value)
+				if err != nil {
+					return nil, fmt.Errorf("invalid uncertainty %q in geo: URI", value)
+				}
+				g.Uncertainty = &u
+			}
+		}
+	}
+
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// parseGeoNumber parses a decimal number as allowed by RFC 5870, which has
+// no exponents, infinities or NaN
+func parseGeoNumber(s string) (float64, error) {
+	if s == "" || strings.ContainsAny(s, "eEiInN") {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// Validate checks that the coordinates are in range and the uncertainty is
+// not negative
+func (g *Geo) Validate() error {
+	if g.Latitude < -90 || g.Latitude > 90 {
+		return fmt.Errorf("latitude %v out of range [-90, 90]", g.Latitude)
+	}
+	if g.Longitude < -180 || g.Longitude > 180 {
+		return fmt.Errorf("longitude %v out of range [-180, 180]", g.Longitude)
+	}
+	if g.Uncertainty != nil && *g.Uncertainty < 0 {
+		return fmt.Errorf("uncertainty %v must not be negative", *g.Uncertainty)
+	}
+	return nil
+}
+
+// String formats the Geo as a geo: URI
+func (g *Geo) String() string {
+	var b strings.Builder
+	b.WriteString("geo:")
+	b.WriteString(formatGeoNumber(g.Latitude))
+	b.WriteString(",")
+	b.WriteString(formatGeoNumber(g.Longitude))
+	if g.Altitude != nil {
+		b.WriteString(",")
+		b.WriteString(formatGeoNumber(*g.Altitude))
+	}
+	if g.Uncertainty != nil {
+		b.WriteString(";u=")
+		b.WriteString(formatGeoNumber(*g.Uncertainty))
+	}
+	return b.String()
+}
+
+func formatGeoNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// DistanceTo returns the great-circle distance to other in meters, using
+// the haversine formula on a spherical earth. Altitudes are ignored.
+func (g *Geo) DistanceTo(other *Geo) float64 {
+	lat1 := g.Latitude * math.Pi / 180
+	lat2 := other.Latitude * math.Pi / 180
+	dLat := lat2 - lat1
+	dLon := (other.Longitude - g.Longitude) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// Geo parses the location's coordinates. It returns nil and no error if the
+// location has none.
+func (l *Location) Geo() (*Geo, error) {
+	if l.Coordinates == nil {
+		return nil, nil
+	}
+	return ParseGeo(*l.Coordinates)
+}
+
+// SetCoordinates sets the location's coordinates to a geo: URI for the given
+// latitude and longitude
+func (l *Location) SetCoordinates(lat, lon float64) error {
+	g := NewGeo(lat, lon)
+	if err := g.Validate(); err != nil {
+		return err
+	}
+	coordinates := g.String()
+	l.Coordinates = &coordinates
+	return nil
+}
+
+// DistanceTo returns the distance between two locations in meters. Both
+// need valid coordinates.
+func (l *Location) DistanceTo(other *Location) (float64, error) {
+	from, err := l.Geo()
+	if err != nil {
+		return 0, err
+	}
+	to, err := other.Geo()
+	if err != nil {
+		return 0, err
+	}
+	if from == nil || to == nil {
+		return 0, fmt.Errorf("location has no coordinates")
+	}
+	return from.DistanceTo(to), nil
+}
@@ -0,0 +1,84 @@
+package jscal
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseGeo(t *testing.T) {
+	tests := []struct {
+		uri      string
+		expected string
+		wantErr  bool
+	}{
+		{uri: "geo:37.786971,-122.399677", expected: "geo:37.786971,-122.399677"},
+		{uri: "GEO:48.2010,16.3695,183", expected: "geo:48.201,16.3695,183"},
+		{uri: "geo:48.198634,16.371648;crs=wgs84;u=40", expected: "geo:48.198634,16.371648;u=40"},
+		{uri: "geo:-90,0;foo=bar", expected: "geo:-90,0"},
+		{uri: "geo:90.5,0", wantErr: true},
+		{uri: "geo:0,-180.1", wantErr: true},
+		{uri: "geo:0,0;u=-1", wantErr: true},
+		{uri: "geo:0,0;u=1;crs=wgs84", wantErr: true},
+		{uri: "geo:0,0;crs=nad27", wantErr: true},
+		{uri: "geo:1e2,0", wantErr: true},
+		{uri: "geo:NaN,0", wantErr: true},
+		{uri: "geo:1,2,3,4", wantErr: true},
+		{uri: "geo:1", wantErr: true},
+		{uri: "https://example.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		g, err := ParseGeo(tt.uri)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseGeo(%q): expected error, got %v", tt.uri, g)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseGeo(%q): unexpected error: %v", tt.uri, err)
+			continue
+		}
+		if got := g.String(); got != tt.expected {
+			t.Errorf("ParseGeo(%q): expected %s, got %s", tt.uri, tt.expected, got)
+		}
+	}
+}
+
+func TestGeoDistanceTo(t *testing.T) {
+	// Berlin to Paris is about 878 km
+	berlin := NewGeo(52.5200, 13.4050)
+	paris := NewGeo(48.8566, 2.3522)
+	if d := berlin.DistanceTo(paris); math.Abs(d-877_500) > 2_000 {
+		t.Errorf("Expected about 877.5 km, got %.0f m", d)
+	}
+	if d := berlin.DistanceTo(berlin); d != 0 {
+		t.Errorf("Expected 0, got %v", d)
+	}
+}
+
+func TestLocationCoordinates(t *testing.T) {
+	office := NewLocation("Office")
+	if err := office.SetCoordinates(52.52, 13.405); err != nil {
+		t.Fatal(err)
+	}
+	if *office.Coordinates != "geo:52.52,13.405" {
+		t.Errorf("Expected geo:52.52,13.405, got %s", *office.Coordinates)
+	}
+	if err := office.SetCoordinates(100, 0); err == nil {
+		t.Error("Expected an error for latitude 100")
+	}
+
+	venue := NewLocation("Venue")
+	if _, err := office.DistanceTo(venue); err == nil {
+		t.Error("Expected an error for a location without coordinates")
+	}
+	venue.Coordinates = String("geo:48.8566,2.3522;u=50")
+	if d, err := office.DistanceTo(venue); err != nil || d < 870_000 || d > 885_000 {
+		t.Errorf("Expected about 878 km, got %v, %v", d, err)
+	}
+
+	if g, err := NewLocation("Nowhere").Geo(); g != nil || err != nil {
+		t.Errorf("Expected no coordinates, got %v, %v", g, err)
+	}
+}
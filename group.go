@@ -3,7 +3,7 @@ package jscal
 import (
 	"encoding/json"
 	"fmt"
-	"time"
+	"reflect"
 )
 
 // Group represents a JSCalendar Group object according to RFC 8984.
@@ -11,13 +11,13 @@ import (
 // Typically, objects are grouped by topic (e.g., by keywords) or calendar membership.
 type Group struct {
 	// Core metadata (Section 4.1)
-	Type     string     `json:"@type"`              // Always "Group"
-	UID      string     `json:"uid"`                // Unique identifier
-	Created  *time.Time `json:"created,omitempty"`  // Creation timestamp
-	Updated  *time.Time `json:"updated,omitempty"`  // Last modification timestamp
-	Sequence *int       `json:"sequence,omitempty"` // Revision sequence number
-	Method   *string    `json:"method,omitempty"`   // iTIP method
-	ProdId   *string    `json:"prodId,omitempty"`   // Product identifier that created this
+	Type     string       `json:"@type"`              // Always "Group"
+	UID      string       `json:"uid"`                // Unique identifier
+	Created  *UTCDateTime `json:"created,omitempty"`  // Creation timestamp
+	Updated  *UTCDateTime `json:"updated,omitempty"`  // Last modification timestamp
+	Sequence *int         `json:"sequence,omitempty"` // Revision sequence number
+	Method   *string      `json:"method,omitempty"`   // iTIP method
+	ProdId   *string      `json:"prodId,omitempty"`   // Product identifier that created this
 
 	// Group properties
 	Title       *string          `json:"title,omitempty"`       // Group title
@@ -45,13 +45,13 @@ type CalendarObject interface {
 
 // NewGroup creates a new JSCalendar Group with required fields
 func NewGroup(uid, title string) *Group {
-	now := time.Now().UTC()
+	now := Now().UTC()
 	return &Group{
 		Type:     "Group",
 		UID:      uid,
 		Title:    &title,
-		Created:  &now,
-		Updated:  &now,
+		Created:  NewUTCDateTime(now),
+		Updated:  NewUTCDateTime(now),
 		Sequence: Int(0),
 		Entries:  []CalendarObject{},
 	}
@@ -75,7 +75,8 @@ func (g *Group) Clone() *Group {
 	return &clone
 }
 
-// AddEntry adds an Event or Task to the group
+// AddEntry adds an Event, Task or nested Group to the group. A Group that
+// contains this group, directly or further down, is rejected.
 func (g *Group) AddEntry(entry CalendarObject) error {
 	if entry == nil {
 		return fmt.Errorf("cannot add nil entry to group")
@@ -83,8 +84,11 @@ func (g *Group) AddEntry(entry CalendarObject) error {
 
 	// Validate the entry type
 	entryType := entry.GetType()
-	if entryType != "Event" && entryType != "Task" {
-		return fmt.Errorf("invalid entry type '%s': must be Event or Task", entryType)
+	if entryType != "Event" && entryType != "Task" && entryType != "Group" {
+		return fmt.Errorf("invalid entry type '%s': must be Event, Task or Group", entryType)
+	}
+	if sub, ok := entry.(*Group); ok && (sub == g || sub.UID == g.UID || sub.FindByUID(g.UID) != nil) {
+		return fmt.Errorf("group '%s' contains group '%s', adding it would create a cycle", sub.UID, g.UID)
 	}
 
 	// Check for duplicate UIDs
@@ -145,6 +149,72 @@ func (g *Group) GetTasks() []*Task {
 	return tasks
 }
 
+// Walk calls fn for each entry of the group and its nested groups, depth
+// first, visiting a nested group before its entries. It stops at and
+// returns the first error from fn. A group reached again through a cycle is
+// not entered again.
+func (g *Group) Walk(fn func(entry CalendarObject) error) error {
+	return g.walk(fn, map[*Group]bool{g: true})
+}
+
+func (g *Group) walk(fn func(entry CalendarObject) error, visited map[*Group]bool) error {
+	for _, entry := range g.Entries {
+		if entry == nil {
+			continue
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+		if sub, ok := entry.(*Group); ok && !visited[sub] {
+			visited[sub] = true
+			if err := sub.walk(fn, visited); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Flatten returns the events and tasks of the group and its nested groups,
+// depth first
+func (g *Group) Flatten() []CalendarObject {
+	var entries []CalendarObject
+	_ = g.Walk(func(entry CalendarObject) error {
+		if _, ok := entry.(*Group); !ok {
+			entries = append(entries, entry)
+		}
+		return nil
+	})
+	return entries
+}
+
+// errFound stops Walk once FindByUID has found its entry
+var errFound = fmt.Errorf("found")
+
+// FindByUID returns the entry with the given UID anywhere in the group's
+// tree, including nested groups themselves, or nil if there is none
+func (g *Group) FindByUID(uid string) CalendarObject {
+	var found CalendarObject
+	_ = g.Walk(func(entry CalendarObject) error {
+		if entry.GetUID() == uid {
+			found = entry
+			return errFound
+		}
+		return nil
+	})
+	return found
+}
+
+// isAncestor reports whether group is one of ancestors, by identity or UID
+func isAncestor(group *Group, ancestors []*Group) bool {
+	for _, ancestor := range ancestors {
+		if ancestor == group || ancestor.UID == group.UID {
+			return true
+		}
+	}
+	return false
+}
+
 // CountEntries returns the total number of entries
 func (g *Group) CountEntries() int {
 	return len(g.Entries)
@@ -174,8 +244,8 @@ func (g *Group) CountTasks() int {
 
 // Touch updates the Updated timestamp and increments sequence
 func (g *Group) Touch() {
-	now := time.Now().UTC()
-	g.Updated = &now
+	now := Now().UTC()
+	g.Updated = NewUTCDateTime(now)
 	if g.Sequence != nil {
 		*g.Sequence++
 	} else {
@@ -207,6 +277,21 @@ func (g *Group) AddLink(id string, link *Link) {
 	g.Links[id] = link
 }
 
+// GetExtension returns the extension property with the given name. Values of
+// registered extensions are decoded into a pointer to the registered Go type,
+// others are returned as parsed from JSON.
+func (g *Group) GetExtension(name string) (interface{}, error) {
+	return getExtension(g.Extensions, name)
+}
+
+// SetExtension sets an extension property
+func (g *Group) SetExtension(name string, value interface{}) {
+	if g.Extensions == nil {
+		g.Extensions = make(map[string]interface{})
+	}
+	g.Extensions[name] = value
+}
+
 // GetUID returns the group's UID (implements CalendarObject)
 func (g *Group) GetUID() string {
 	return g.UID
@@ -217,7 +302,18 @@ func (g *Group) GetType() string {
 	return g.Type
 }
 
-// UnmarshalJSON implements custom JSON unmarshaling for Group to handle polymorphic entries
+// MarshalJSON implements json.Marshaler, writing Extensions as top-level properties
+func (g Group) MarshalJSON() ([]byte, error) {
+	type Alias Group
+	data, err := json.Marshal(Alias(g))
+	if err != nil {
+		return nil, err
+	}
+	return appendExtensions(data, g.Extensions, reflect.TypeOf(Alias{}))
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling for Group to handle polymorphic
+// entries and capture unrecognized top-level properties into Extensions
 func (g *Group) UnmarshalJSON(data []byte) error {
 	// Create an alias to avoid infinite recursion
 	type Alias Group
@@ -235,6 +331,12 @@ func (g *Group) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
+	extensions, err := extractExtensions(data, reflect.TypeOf(Alias{}))
+	if err != nil {
+		return err
+	}
+	g.Extensions = extensions
+
 	// If no entries, we're done
 	if len(aux.Entries) == 0 {
 		g.Entries = []CalendarObject{}
@@ -274,7 +376,7 @@ func (g *Group) UnmarshalJSON(data []byte) error {
 			}
 			entry = &subGroup
 		default:
-			return fmt.Errorf("unknown entry type at index %d: %s", i, typeCheck.Type)
+			return withKind(ErrUnknownType, fmt.Errorf("unknown entry type at index %d: %s", i, typeCheck.Type))
 		}
 
 		g.Entries = append(g.Entries, entry)
@@ -285,6 +387,18 @@ func (g *Group) UnmarshalJSON(data []byte) error {
 
 // Validate validates the Group according to RFC 8984
 func (g *Group) Validate() error {
+	return g.validate(nil)
+}
+
+// validate validates the Group with the given options (nil for defaults)
+func (g *Group) validate(opts *ValidationOptions) error {
+	return g.validateNested(opts, nil)
+}
+
+// validateNested validates a Group nested in ancestors, the groups above it
+// from the outermost down. Nested groups that are also an ancestor form a
+// cycle, which is reported instead of followed.
+func (g *Group) validateNested(opts *ValidationOptions, ancestors []*Group) error {
 	if g == nil {
 		return ValidationError{
 			Field:   "group",
@@ -313,7 +427,7 @@ func (g *Group) Validate() error {
 	}
 
 	// Validate UID length (RFC 8984: max 255 octets)
-	if len(g.UID) > MaxUIDLength {
+	if opts.enforceLengths() && len(g.UID) > MaxUIDLength {
 		errors = append(errors, ValidationError{
 			Field:   "uid",
 			Value:   g.UID,
@@ -321,8 +435,12 @@ func (g *Group) Validate() error {
 		})
 	}
 
+	// Validate timestamps
+	errors = append(errors, validateUTCDateTime("created", g.Created)...)
+	errors = append(errors, validateUTCDateTime("updated", g.Updated)...)
+
 	// Validate title length if present
-	if g.Title != nil && len(*g.Title) > MaxTitleLength {
+	if opts.enforceLengths() && g.Title != nil && len(*g.Title) > MaxTitleLength {
 		errors = append(errors, ValidationError{
 			Field:   "title",
 			Value:   *g.Title,
@@ -331,7 +449,7 @@ func (g *Group) Validate() error {
 	}
 
 	// Validate description length if present
-	if g.Description != nil && len(*g.Description) > MaxDescriptionLength {
+	if opts.enforceLengths() && g.Description != nil && len(*g.Description) > MaxDescriptionLength {
 		errors = append(errors, ValidationError{
 			Field:   "description",
 			Value:   *g.Description,
@@ -350,7 +468,7 @@ func (g *Group) Validate() error {
 
 	// Validate color format if present
 	if g.Color != nil {
-		if !colorPattern.MatchString(*g.Color) {
+		if _, err := ParseColor(*g.Color); err != nil {
 			errors = append(errors, ValidationError{
 				Field:   "color",
 				Value:   *g.Color,
@@ -366,6 +484,9 @@ func (g *Group) Validate() error {
 		}
 	}
 
+	// Validate registered extension properties
+	errors = append(errors, validateExtensions(g.Extensions)...)
+
 	// Validate entries
 	if g.Entries == nil {
 		g.Entries = []CalendarObject{}
@@ -395,16 +516,33 @@ func (g *Group) Validate() error {
 
 		// Validate entry type
 		entryType := entry.GetType()
-		if entryType != "Event" && entryType != "Task" {
+		if entryType != "Event" && entryType != "Task" && entryType != "Group" {
 			errors = append(errors, ValidationError{
 				Field:   fmt.Sprintf("entries[%d].@type", i),
 				Value:   entryType,
-				Message: "must be 'Event' or 'Task'",
+				Message: "must be 'Event', 'Task' or 'Group'",
 			})
 		}
 
 		// Validate the entry itself
-		if err := entry.Validate(); err != nil {
+		var err error
+		if sub, ok := entry.(*Group); ok {
+			if sub.UID == g.UID {
+				continue // Reported below
+			}
+			if isAncestor(sub, ancestors) {
+				errors = append(errors, ValidationError{
+					Field:   fmt.Sprintf("entries[%d]", i),
+					Value:   sub.UID,
+					Message: fmt.Sprintf("circular reference to group '%s'", sub.UID),
+				})
+				continue
+			}
+			err = sub.validateNested(opts, append(ancestors, g))
+		} else {
+			err = validateEntry(entry, opts)
+		}
+		if err != nil {
 			if valErrors, ok := err.(ValidationErrors); ok {
 				for _, valErr := range valErrors {
 					// Prefix the field with entries[i]
@@ -423,7 +561,7 @@ func (g *Group) Validate() error {
 
 	// Check for circular references (a group cannot contain itself)
 	for i, entry := range g.Entries {
-		if entry.GetUID() == g.UID {
+		if entry != nil && entry.GetUID() == g.UID {
 			errors = append(errors, ValidationError{
 				Field:   fmt.Sprintf("entries[%d]", i),
 				Value:   entry.GetUID(),
@@ -480,7 +480,7 @@ func TestGroupTouch(t *testing.T) {
 	event := NewEvent("event-1", "Event 1")
 	_ = group.AddEntry(event)
 
-	if group.Updated.Equal(*originalUpdated) {
+	if group.Updated.Equal(originalUpdated) {
 		t.Error("Expected Updated timestamp to change")
 	}
 
@@ -543,3 +543,82 @@ func TestGroupCalendarObjectInterface(t *testing.T) {
 		t.Errorf("Validate() error = %v", err)
 	}
 }
+
+func newNestedTestGroup(t *testing.T) (*Group, *Group) {
+	t.Helper()
+	inner := NewGroup("inner", "Inner")
+	if err := inner.AddEntry(NewTask("task-1", "Task 1")); err != nil {
+		t.Fatal(err)
+	}
+	outer := NewGroup("outer", "Outer")
+	for _, entry := range []CalendarObject{NewEvent("event-1", "Event 1"), inner, NewEvent("event-2", "Event 2")} {
+		if err := outer.AddEntry(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return outer, inner
+}
+
+func TestNestedGroups(t *testing.T) {
+	outer, inner := newNestedTestGroup(t)
+	if err := outer.Validate(); err != nil {
+		t.Errorf("Expected nested groups to be valid, got %v", err)
+	}
+
+	var visited []string
+	_ = outer.Walk(func(entry CalendarObject) error {
+		visited = append(visited, entry.GetUID())
+		return nil
+	})
+	if strings.Join(visited, ",") != "event-1,inner,task-1,event-2" {
+		t.Errorf("Expected depth-first order, got %v", visited)
+	}
+
+	var flat []string
+	for _, entry := range outer.Flatten() {
+		flat = append(flat, entry.GetUID())
+	}
+	if strings.Join(flat, ",") != "event-1,task-1,event-2" {
+		t.Errorf("Expected events and tasks only, got %v", flat)
+	}
+
+	if found := outer.FindByUID("task-1"); found == nil || found.GetType() != "Task" {
+		t.Errorf("Expected to find task-1 in the nested group, got %v", found)
+	}
+	if found := outer.FindByUID("inner"); found != inner {
+		t.Errorf("Expected to find the nested group, got %v", found)
+	}
+	if found := outer.FindByUID("missing"); found != nil {
+		t.Errorf("Expected nil, got %v", found)
+	}
+}
+
+func TestNestedGroupCycles(t *testing.T) {
+	outer, inner := newNestedTestGroup(t)
+
+	if err := inner.AddEntry(outer); err == nil {
+		t.Error("Expected AddEntry to reject a group containing the target")
+	}
+	if err := inner.AddEntry(inner); err == nil {
+		t.Error("Expected AddEntry to reject adding a group to itself")
+	}
+
+	// Build the cycle directly, bypassing AddEntry
+	deepest := NewGroup("deepest", "Deepest")
+	deepest.Entries = append(deepest.Entries, outer)
+	inner.Entries = append(inner.Entries, deepest)
+
+	errs, ok := outer.Validate().(ValidationErrors)
+	if !ok || len(errs) != 1 || errs[0].Field != "entries[1].entries[1].entries[0]" || errs[0].Message != "circular reference to group 'outer'" {
+		t.Errorf("Expected a circular reference error, got %v", errs)
+	}
+
+	count := 0
+	_ = outer.Walk(func(CalendarObject) error {
+		count++
+		return nil
+	})
+	if count != 6 {
+		t.Errorf("Expected Walk to visit each entry once, visited %d", count)
+	}
+}
@@ -0,0 +1,275 @@
+// Package http negotiates between the calendar media types servers
+// embedding jscal commonly offer, and marshals or parses calendar objects
+// accordingly:
+//
+//   - application/jscalendar+json (RFC 8984), with its type parameter
+//   - text/calendar (RFC 5545), via the registered "ical" converter
+//   - application/calendar+json (RFC 7265), via a registered "jcal" converter
+//
+// Formats other than JSCalendar are only offered when their converter is
+// registered, so enable them with a blank import:
+//
+//	import (
+//		_ "github.com/airtrafik/jscal/convert/ical"
+//		jscalhttp "github.com/airtrafik/jscal/http"
+//	)
+//
+//	func serveEvent(w http.ResponseWriter, r *http.Request) {
+//		jscalhttp.Write(w, r, event)
+//	}
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/airtrafik/jscal"
+	"github.com/airtrafik/jscal/convert"
+)
+
+// Media types
+const (
+	MediaTypeJSCalendar = "application/jscalendar+json"
+	MediaTypeICalendar  = "text/calendar"
+	MediaTypeJCal       = "application/calendar+json"
+)
+
+// MaxBodySize limits the request bodies ReadRequest accepts
+const MaxBodySize = 10 << 20
+
+var (
+	// ErrNotAcceptable means none of the acceptable media types can
+	// represent the object (HTTP 406)
+	ErrNotAcceptable = errors.New("no acceptable media type")
+
+	// ErrUnsupportedMediaType means a request body's media type can't be
+	// parsed (HTTP 415)
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+)
+
+// converterFormats maps media types other than JSCalendar to the registry
+// format that converts them
+var converterFormats = map[string]string{
+	MediaTypeICalendar: "ical",
+	MediaTypeJCal:      "jcal",
+}
+
+// Offers returns the media types obj can be marshaled to, in order of
+// preference. Converters only handle events, so objects containing tasks
+// are only offered as JSCalendar.
+func Offers(obj jscal.CalendarObject) []string {
+	offers := []string{MediaTypeJSCalendar}
+	if !eventsOnly(obj) {
+		return offers
+	}
+	for _, mediaType := range []string{MediaTypeICalendar, MediaTypeJCal} {
+		if _, ok := convert.Lookup(converterFormats[mediaType]); ok {
+			offers = append(offers, mediaType)
+		}
+	}
+	return offers
+}
+
+// eventsOnly reports whether obj is an Event or a Group of only events
+func eventsOnly(obj jscal.CalendarObject) bool {
+	switch o := obj.(type) {
+	case *jscal.Event:
+		return true
+	case *jscal.Group:
+		return len(o.GetEvents()) == len(o.Entries)
+	}
+	return false
+}
+
+// acceptRange is one media range of an Accept header
+type acceptRange struct {
+	mediaType string
+	params    map[string]string
+	q         float64
+}
+
+// parseAccept parses an Accept header, ignoring malformed ranges. The
+// ranges are sorted by decreasing quality, keeping header order for ties.
+func parseAccept(accept string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(accept, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		mediaType, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+			delete(params, "q")
+		}
+		ranges = append(ranges, acceptRange{mediaType: mediaType, params: params, q: q})
+	}
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+	return ranges
+}
+
+// matches reports whether the range accepts mediaType for an object of the
+// given JSCalendar type
+func (r acceptRange) matches(mediaType, objType string) bool {
+	if r.q <= 0 {
+		return false
+	}
+	switch {
+	case r.mediaType == "*/*":
+	case strings.HasSuffix(r.mediaType, "/*"):
+		if !strings.HasPrefix(mediaType, strings.TrimSuffix(r.mediaType, "*")) {
+			return false
+		}
+	case r.mediaType != mediaType:
+		return false
+	}
+	if t, ok := r.params["type"]; ok && mediaType == MediaTypeJSCalendar && !strings.EqualFold(t, objType) {
+		return false
+	}
+	return true
+}
+
+// Negotiate picks the media type for obj from an Accept header. Ranges with
+// a higher quality win; among equals the order of Offers decides. An empty
+// header accepts anything.
+func Negotiate(accept string, obj jscal.CalendarObject) (string, error) {
+	offers := Offers(obj)
+	if strings.TrimSpace(accept) == "" {
+		return offers[0], nil
+	}
+
+	for _, r := range parseAccept(accept) {
+		for _, offer := range offers {
+			if r.matches(offer, obj.GetType()) {
+				return offer, nil
+			}
+		}
+	}
+	return "", ErrNotAcceptable
+}
+
+// Marshal encodes obj as mediaType and returns the body with the full
+// Content-Type header value, e.g. "application/jscalendar+json; type=event"
+func Marshal(obj jscal.CalendarObject, mediaType string) ([]byte, string, error) {
+	if mediaType == MediaTypeJSCalendar {
+		data, err := json.Marshal(obj)
+		if err != nil {
+			return nil, "", err
+		}
+		contentType := mime.FormatMediaType(mediaType, map[string]string{"type": strings.ToLower(obj.GetType())})
+		return data, contentType, nil
+	}
+
+	format, ok := converterFormats[mediaType]
+	if !ok {
+		return nil, "", fmt.Errorf("%w: %s", ErrNotAcceptable, mediaType)
+	}
+	converter, ok := convert.Lookup(format)
+	if !ok || !eventsOnly(obj) {
+		return nil, "", fmt.Errorf("%w: cannot represent %s as %s", ErrNotAcceptable, obj.GetType(), mediaType)
+	}
+
+	var events []*jscal.Event
+	switch o := obj.(type) {
+	case *jscal.Event:
+		events = []*jscal.Event{o}
+	case *jscal.Group:
+		events = o.GetEvents()
+	}
+	data, err := converter.FormatAll(events)
+	if err != nil {
+		return nil, "", err
+	}
+	params := map[string]string{}
+	if mediaType == MediaTypeICalendar {
+		params["charset"] = "utf-8"
+	}
+	return data, mime.FormatMediaType(mediaType, params), nil
+}
+
+// Write negotiates the media type from the request's Accept header and
+// writes obj with a 200 status. If no acceptable media type can represent
+// obj, Write replies 406 Not Acceptable and returns ErrNotAcceptable.
+func Write(w http.ResponseWriter, r *http.Request, obj jscal.CalendarObject) error {
+	mediaType, err := Negotiate(r.Header.Get("Accept"), obj)
+	if err != nil {
+		http.Error(w, "Not Acceptable: available types are "+strings.Join(Offers(obj), ", "), http.StatusNotAcceptable)
+		return err
+	}
+	data, contentType, err := Marshal(obj, mediaType)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return err
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Add("Vary", "Accept")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(data)
+	return err
+}
+
+// Parse decodes a body of the given Content-Type. JSCalendar bodies may
+// hold a single object or an array; iCalendar and jCal bodies yield their
+// events. Unknown or unregistered media types return
+// ErrUnsupportedMediaType.
+func Parse(data []byte, contentType string) ([]jscal.CalendarObject, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedMediaType, contentType)
+	}
+
+	switch mediaType {
+	case MediaTypeJSCalendar, "application/json":
+		trimmed := bytes.TrimSpace(data)
+		if len(trimmed) > 0 && trimmed[0] == '[' {
+			return jscal.ParseAll(trimmed)
+		}
+		obj, err := jscal.Parse(trimmed)
+		if err != nil {
+			return nil, err
+		}
+		return []jscal.CalendarObject{obj}, nil
+	}
+
+	format, ok := converterFormats[mediaType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedMediaType, mediaType)
+	}
+	converter, ok := convert.Lookup(format)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s (no %s converter registered)", ErrUnsupportedMediaType, mediaType, format)
+	}
+	events, err := converter.ParseAll(data)
+	if err != nil {
+		return nil, err
+	}
+	objects := make([]jscal.CalendarObject, 0, len(events))
+	for _, e := range events {
+		objects = append(objects, e)
+	}
+	return objects, nil
+}
+
+// ReadRequest parses the request body according to its Content-Type, reading
+// at most MaxBodySize bytes
+func ReadRequest(r *http.Request) ([]jscal.CalendarObject, error) {
+	data, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, MaxBodySize))
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data, r.Header.Get("Content-Type"))
+}
@@ -0,0 +1,177 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/airtrafik/jscal"
+	"github.com/airtrafik/jscal/convert"
+)
+
+// fakeICal stands in for the iCalendar converter module, which the core
+// module can't import
+type fakeICal struct{}
+
+func (fakeICal) Parse(data []byte) (*jscal.Event, error) { return nil, errors.New("unused") }
+func (fakeICal) Format(e *jscal.Event) ([]byte, error) {
+	return fakeICal{}.FormatAll([]*jscal.Event{e})
+}
+func (fakeICal) Detect(data []byte) bool { return strings.HasPrefix(string(data), "BEGIN:VCALENDAR") }
+func (fakeICal) FormatAll(events []*jscal.Event) ([]byte, error) {
+	var b strings.Builder
+	b.WriteString("BEGIN:VCALENDAR\r\n")
+	for _, e := range events {
+		b.WriteString("BEGIN:VEVENT\r\nUID:" + e.UID + "\r\nEND:VEVENT\r\n")
+	}
+	b.WriteString("END:VCALENDAR\r\n")
+	return []byte(b.String()), nil
+}
+func (fakeICal) ParseAll(data []byte) ([]*jscal.Event, error) {
+	var events []*jscal.Event
+	for _, line := range strings.Split(string(data), "\r\n") {
+		if uid, ok := strings.CutPrefix(line, "UID:"); ok {
+			event := jscal.NewEvent(uid, uid)
+			event.Start = jscal.NewLocalDateTime(time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC))
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func init() {
+	convert.Register(convert.Registration{Name: "ical", New: func() convert.Converter { return fakeICal{} }})
+}
+
+func newEvent() *jscal.Event {
+	event := jscal.NewEvent("event-1", "Launch")
+	event.Start = jscal.NewLocalDateTime(time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC))
+	return event
+}
+
+func TestNegotiate(t *testing.T) {
+	event := newEvent()
+	task := jscal.NewTask("task-1", "Ship")
+
+	tests := []struct {
+		name   string
+		accept string
+		obj    jscal.CalendarObject
+		want   string
+	}{
+		{name: "empty", accept: "", obj: event, want: MediaTypeJSCalendar},
+		{name: "wildcard", accept: "*/*", obj: event, want: MediaTypeJSCalendar},
+		{name: "icalendar", accept: "text/calendar", obj: event, want: MediaTypeICalendar},
+		{name: "quality", accept: "application/jscalendar+json;q=0.5, text/calendar", obj: event, want: MediaTypeICalendar},
+		{name: "type parameter", accept: "application/jscalendar+json;type=event", obj: event, want: MediaTypeJSCalendar},
+		{name: "text wildcard", accept: "text/*, application/json;q=0.1", obj: event, want: MediaTypeICalendar},
+		{name: "task falls back to jscalendar", accept: "text/calendar, */*;q=0.1", obj: task, want: MediaTypeJSCalendar},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Negotiate(tt.accept, tt.obj)
+			if err != nil || got != tt.want {
+				t.Errorf("Expected %s, got %q (%v)", tt.want, got, err)
+			}
+		})
+	}
+
+	for _, accept := range []string{
+		"application/calendar+json",             // no jcal converter registered
+		"application/jscalendar+json;type=task", // wrong type
+		"text/calendar;q=0",
+		"image/png",
+	} {
+		if got, err := Negotiate(accept, event); !errors.Is(err, ErrNotAcceptable) {
+			t.Errorf("Expected ErrNotAcceptable for %q, got %q (%v)", accept, got, err)
+		}
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	data, contentType, err := Marshal(newEvent(), MediaTypeJSCalendar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if contentType != "application/jscalendar+json; type=event" || !strings.Contains(string(data), `"uid":"event-1"`) {
+		t.Errorf("Unexpected JSCalendar representation %s: %s", contentType, data)
+	}
+
+	group := jscal.NewGroup("group-1", "Calendar")
+	if err := group.AddEntry(newEvent()); err != nil {
+		t.Fatal(err)
+	}
+	data, contentType, err = Marshal(group, MediaTypeICalendar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if contentType != "text/calendar; charset=utf-8" || !strings.Contains(string(data), "UID:event-1") {
+		t.Errorf("Unexpected iCalendar representation %s: %s", contentType, data)
+	}
+
+	if _, _, err := Marshal(jscal.NewTask("task-1", "Ship"), MediaTypeICalendar); !errors.Is(err, ErrNotAcceptable) {
+		t.Errorf("Expected ErrNotAcceptable for task as iCalendar, got %v", err)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/events/1", nil)
+	req.Header.Set("Accept", "text/calendar")
+	rec := httptest.NewRecorder()
+	if err := Write(rec, req, newEvent()); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "text/calendar; charset=utf-8" {
+		t.Errorf("Unexpected response %d %s", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if rec.Header().Get("Vary") != "Accept" {
+		t.Error("Expected Vary: Accept")
+	}
+
+	req.Header.Set("Accept", "image/png")
+	rec = httptest.NewRecorder()
+	if err := Write(rec, req, newEvent()); !errors.Is(err, ErrNotAcceptable) || rec.Code != http.StatusNotAcceptable {
+		t.Errorf("Expected 406, got %d (%v)", rec.Code, err)
+	}
+}
+
+func TestReadRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantUIDs    string
+	}{
+		{name: "jscalendar object", contentType: "application/jscalendar+json;type=event", body: `{"@type":"Event","uid":"a","start":"2025-01-01T09:00:00"}`, wantUIDs: "a"},
+		{name: "jscalendar array", contentType: "application/jscalendar+json", body: `[{"@type":"Event","uid":"a","start":"2025-01-01T09:00:00"},{"@type":"Task","uid":"b"}]`, wantUIDs: "a,b"},
+		{name: "icalendar", contentType: "text/calendar; charset=utf-8", body: "BEGIN:VCALENDAR\r\nUID:x\r\nUID:y\r\nEND:VCALENDAR\r\n", wantUIDs: "x,y"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			objects, err := ReadRequest(req)
+			if err != nil {
+				t.Fatalf("ReadRequest failed: %v", err)
+			}
+			var uids []string
+			for _, obj := range objects {
+				uids = append(uids, obj.GetUID())
+			}
+			if got := strings.Join(uids, ","); got != tt.wantUIDs {
+				t.Errorf("Expected %s, got %s", tt.wantUIDs, got)
+			}
+		})
+	}
+
+	for _, contentType := range []string{"", "application/calendar+json", "text/plain"} {
+		if _, err := Parse([]byte("{}"), contentType); !errors.Is(err, ErrUnsupportedMediaType) {
+			t.Errorf("Expected ErrUnsupportedMediaType for %q, got %v", contentType, err)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package jscal
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// humanUnits are the units of human-readable durations, largest first
+var humanUnits = []struct {
+	length           time.Duration
+	singular, plural string
+}{
+	{24 * time.Hour, "day", "days"},
+	{time.Hour, "hour", "hours"},
+	{time.Minute, "minute", "minutes"},
+	{time.Second, "second", "seconds"},
+}
+
+// FormatDurationHuman formats an ISO 8601 duration for display, e.g.
+// "PT1H30M" as "1 hour 30 minutes" and "-PT15M" as "-15 minutes". Invalid
+// durations are returned unchanged.
+func FormatDurationHuman(duration string) string {
+	d, err := ParseDuration(duration)
+	if err != nil {
+		return duration
+	}
+	return humanDuration(d)
+}
+
+// humanDuration formats a duration in days, hours, minutes and seconds,
+// leaving out zero parts and fractions of a second
+func humanDuration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	var parts []string
+	for _, unit := range humanUnits {
+		if n := int64(d / unit.length); n > 0 {
+			parts = append(parts, countUnit(n, unit.singular, unit.plural))
+			d -= time.Duration(n) * unit.length
+		}
+	}
+	if len(parts) == 0 {
+		return "0 seconds"
+	}
+	return sign + strings.Join(parts, " ")
+}
+
+// countUnit returns "1 day" or "n days"
+func countUnit(n int64, singular, plural string) string {
+	if n == 1 {
+		return "1 " + singular
+	}
+	return fmt.Sprintf("%d %s", n, plural)
+}
+
+// TimeRangeString returns when the event takes place for display, e.g.
+// "Mar 1, 2:00–3:00 PM CET", or "Mar 1 – Mar 3" for all-day events. Times
+// are shown in tz, the event's own time zone if nil; floating times are
+// shown without a zone. English locales use the 12-hour clock, except
+// "en-GB", and other locales the 24-hour clock. It returns "" for events
+// without a start.
+func (e *Event) TimeRangeString(locale string, tz *time.Location) string {
+	loc := tz
+	if loc == nil {
+		loc = time.UTC
+	}
+	start, ok := startInstant(e, loc)
+	if !ok {
+		return ""
+	}
+	duration, err := e.GetDuration()
+	if err != nil || duration < 0 {
+		duration = 0
+	}
+
+	if e.IsAllDay() {
+		days := int(duration / (24 * time.Hour))
+		if days <= 1 {
+			return humanDate(start, start)
+		}
+		last := start.AddDate(0, 0, days-1)
+		return humanDate(start, last) + " – " + humanDate(last, start)
+	}
+
+	floating := e.TimeZone == nil && tz == nil
+	if tz != nil {
+		start = start.In(tz)
+	}
+	end := start.Add(duration)
+
+	clock, meridiem := "15:04", ""
+	if twelveHourClock(locale) {
+		clock, meridiem = "3:04", " PM"
+	}
+	zone := ""
+	if !floating {
+		zone = " " + start.Format("MST")
+	}
+
+	switch {
+	case duration == 0:
+		return humanDate(start, start) + ", " + start.Format(clock+meridiem) + zone
+	case start.Format("2006-01-02") != end.Format("2006-01-02"):
+		return humanDate(start, end) + ", " + start.Format(clock+meridiem) + " – " +
+			humanDate(end, start) + ", " + end.Format(clock+meridiem) + zone
+	case start.Format(meridiem) != end.Format(meridiem):
+		return humanDate(start, start) + ", " + start.Format(clock+meridiem) + "–" + end.Format(clock+meridiem) + zone
+	}
+	return humanDate(start, start) + ", " + start.Format(clock) + "–" + end.Format(clock+meridiem) + zone
+}
+
+// humanDate formats a day as "Mar 1", with the year if other is in a
+// different year
+func humanDate(t, other time.Time) string {
+	if t.Year() != other.Year() {
+		return t.Format("Jan 2, 2006")
+	}
+	return t.Format("Jan 2")
+}
+
+// twelveHourClock reports whether a locale shows times on the 12-hour clock
+func twelveHourClock(locale string) bool {
+	if strings.EqualFold(locale, "en-GB") {
+		return false
+	}
+	return locale == "" || strings.EqualFold(locale, "en") || strings.HasPrefix(strings.ToLower(locale), "en-")
+}
+
+// DueIn describes when the task is due relative to now for display, e.g.
+// "due in 3 days", "due in 1 hour" or "overdue by 2 days", counting whole
+// units of the largest unit that fits. Due times are read in the task's time
+// zone, or now's location if it has none. It returns "" for tasks without a
+// due time.
+func (t *Task) DueIn(now time.Time) string {
+	if t.Due == nil {
+		return ""
+	}
+	loc := timeZoneOf(t.TimeZone, t.GetShowWithoutTime(), now.Location())
+
+	diff := t.Due.In(loc).Sub(now)
+	format := "due in %s"
+	if diff < 0 {
+		format, diff = "overdue by %s", -diff
+	}
+	for _, unit := range humanUnits[:3] {
+		if diff >= unit.length {
+			return fmt.Sprintf(format, countUnit(int64(diff/unit.length), unit.singular, unit.plural))
+		}
+	}
+	return "due now"
+}
@@ -0,0 +1,123 @@
+package jscal
+
+import (
+	"testing"
+	"time"
+)
+
+// localPtr parses a LocalDateTime or fails the test
+func localPtr(t *testing.T, s string) *LocalDateTime {
+	t.Helper()
+	ldt := mustLocal(t, s)
+	return &ldt
+}
+
+func TestFormatDurationHuman(t *testing.T) {
+	tests := []struct {
+		duration string
+		want     string
+	}{
+		{"PT1H30M", "1 hour 30 minutes"},
+		{"PT1M", "1 minute"},
+		{"P2DT3H", "2 days 3 hours"},
+		{"P1W", "7 days"},
+		{"PT45S", "45 seconds"},
+		{"-PT15M", "-15 minutes"},
+		{"PT0S", "0 seconds"},
+		{"invalid", "invalid"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatDurationHuman(tt.duration); got != tt.want {
+			t.Errorf("FormatDurationHuman(%q): expected %q, got %q", tt.duration, tt.want, got)
+		}
+	}
+}
+
+func TestTimeRangeString(t *testing.T) {
+	cet := time.FixedZone("CET", 60*60)
+	timed := func(start, duration string) *Event {
+		event := NewEvent("range-test", "Meeting")
+		event.Start = localPtr(t, start)
+		event.Duration = String(duration)
+		event.TimeZone = String("UTC")
+		return event
+	}
+	floating := timed("2025-03-01T09:00:00", "PT1H")
+	floating.TimeZone = nil
+	allDay := &Event{Start: localPtr(t, "2025-03-01T00:00:00"), ShowWithoutTime: Bool(true), Duration: String("P1D")}
+
+	tests := []struct {
+		name   string
+		event  *Event
+		locale string
+		tz     *time.Location
+		want   string
+	}{
+		{"afternoon", timed("2025-03-01T13:00:00", "PT1H"), "en", cet, "Mar 1, 2:00–3:00 PM CET"},
+		{"across noon", timed("2025-03-01T10:00:00", "PT2H"), "en-US", cet, "Mar 1, 11:00 AM–1:00 PM CET"},
+		{"24-hour clock", timed("2025-03-01T13:00:00", "PT1H"), "de", cet, "Mar 1, 14:00–15:00 CET"},
+		{"en-GB", timed("2025-03-01T13:00:00", "PT1H"), "en-GB", cet, "Mar 1, 14:00–15:00 CET"},
+		{"own zone", timed("2025-03-01T13:00:00", "PT30M"), "en", nil, "Mar 1, 1:00–1:30 PM UTC"},
+		{"no duration", timed("2025-03-01T13:00:00", "PT0S"), "en", cet, "Mar 1, 2:00 PM CET"},
+		{"overnight", timed("2025-03-01T22:00:00", "PT3H"), "en", cet, "Mar 1, 11:00 PM – Mar 2, 2:00 AM CET"},
+		{"new year", timed("2025-12-31T22:00:00", "PT3H"), "de", cet, "Dec 31, 2025, 23:00 – Jan 1, 2026, 02:00 CET"},
+		{"floating", floating, "en", nil, "Mar 1, 9:00–10:00 AM"},
+		{"all day", allDay, "en", cet, "Mar 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.event.TimeRangeString(tt.locale, tt.tz); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	allDay.Duration = String("P3D")
+	if got, want := allDay.TimeRangeString("en", nil), "Mar 1 – Mar 3"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	if got := (&Event{}).TimeRangeString("en", nil); got != "" {
+		t.Errorf("Expected empty string without start, got %q", got)
+	}
+}
+
+func TestDueIn(t *testing.T) {
+	now := time.Date(2025, 3, 3, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		due  string
+		want string
+	}{
+		{"2025-03-06T12:00:00", "due in 3 days"},
+		{"2025-03-04T18:00:00", "due in 1 day"},
+		{"2025-03-03T14:30:00", "due in 2 hours"},
+		{"2025-03-03T12:05:00", "due in 5 minutes"},
+		{"2025-03-03T12:00:20", "due now"},
+		{"2025-03-01T09:00:00", "overdue by 2 days"},
+		{"2025-03-03T11:59:00", "overdue by 1 minute"},
+	}
+
+	for _, tt := range tests {
+		task := NewTask("due-test", "Report")
+		task.Due = localPtr(t, tt.due)
+		if got := task.DueIn(now); got != tt.want {
+			t.Errorf("DueIn with due %s: expected %q, got %q", tt.due, tt.want, got)
+		}
+	}
+
+	if got := NewTask("no-due", "Report").DueIn(now); got != "" {
+		t.Errorf("Expected empty string without due, got %q", got)
+	}
+
+	// The due time is in the task's zone, an hour ahead of UTC
+	task := NewTask("zoned", "Report")
+	task.Due = localPtr(t, "2025-03-03T15:00:00")
+	task.TimeZone = String("Europe/Berlin")
+	if _, err := time.LoadLocation("Europe/Berlin"); err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	if got, want := task.DueIn(now), "due in 2 hours"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,90 @@
+package jscal
+
+import "fmt"
+
+// methodRule lists what a scheduling message with a given method must
+// contain, after the component tables of RFC 5546 Section 3.2. The
+// organizer is a participant with the owner role; attendees are the other
+// participants expected to attend.
+type methodRule struct {
+	owner           bool // at least one owner
+	attendees       bool // at least one attendee
+	singleAttendee  bool // exactly one attendee, the sender
+	replyTo         bool // replyTo is set, so attendees can answer
+	attendeeReplied bool // the attendee has a participationStatus other than needs-action
+}
+
+// methodRules maps each method to its rule. Publish has none: it is
+// informational and may come without participants.
+var methodRules = map[string]methodRule{
+	MethodPublish:        {},
+	MethodRequest:        {owner: true, attendees: true, replyTo: true},
+	MethodAdd:            {owner: true, attendees: true, replyTo: true},
+	MethodCancel:         {owner: true, attendees: true},
+	MethodReply:          {owner: true, singleAttendee: true, attendeeReplied: true},
+	MethodRefresh:        {owner: true, singleAttendee: true},
+	MethodCounter:        {owner: true, attendees: true},
+	MethodDeclineCounter: {owner: true, attendees: true},
+}
+
+// checkMethod validates an object against the iTIP rules of its method.
+// Unknown methods are left to the method enum check.
+func checkMethod(method *string, participants map[string]*Participant, replyTo map[string]string) ValidationErrors {
+	if method == nil {
+		return nil
+	}
+	rule, ok := methodRules[*method]
+	if !ok {
+		return nil
+	}
+
+	var owners, attendees []string
+	for _, id := range sortedKeys(participants) {
+		p := participants[id]
+		switch {
+		case p == nil:
+		case p.Roles[RoleOwner]:
+			owners = append(owners, id)
+		case isAttendee(p):
+			attendees = append(attendees, id)
+		}
+	}
+
+	var errors ValidationErrors
+	if rule.owner && len(owners) == 0 {
+		errors = append(errors, ValidationError{
+			Field:   "participants",
+			Message: fmt.Sprintf("must include an owner for method %s", *method),
+		})
+	}
+	if rule.attendees && len(attendees) == 0 {
+		errors = append(errors, ValidationError{
+			Field:   "participants",
+			Message: fmt.Sprintf("must include an attendee for method %s", *method),
+		})
+	}
+	if rule.singleAttendee && len(attendees) != 1 {
+		errors = append(errors, ValidationError{
+			Field:   "participants",
+			Value:   attendees,
+			Message: fmt.Sprintf("must include exactly one attendee for method %s, got %d", *method, len(attendees)),
+		})
+	}
+	if rule.attendeeReplied && len(attendees) == 1 {
+		id := attendees[0]
+		if status := participationStatus(participants[id]); status == ParticipationNeedsAction {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("participants[%s].participationStatus", id),
+				Value:   status,
+				Message: fmt.Sprintf("must be a reply status for method %s", *method),
+			})
+		}
+	}
+	if rule.replyTo && len(replyTo) == 0 {
+		errors = append(errors, ValidationError{
+			Field:   "replyTo",
+			Message: fmt.Sprintf("must be set for method %s", *method),
+		})
+	}
+	return errors
+}
@@ -0,0 +1,113 @@
+package jscal
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckMethod(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		modify  func(e *Event)
+		wantErr string
+	}{
+		{name: "valid request", method: MethodRequest},
+		{
+			name:    "request without owner",
+			method:  MethodRequest,
+			modify:  func(e *Event) { delete(e.Participants, "owner") },
+			wantErr: "must include an owner for method request",
+		},
+		{
+			name:    "request without attendees",
+			method:  MethodRequest,
+			modify:  func(e *Event) { delete(e.Participants, "alice") },
+			wantErr: "must include an attendee for method request",
+		},
+		{
+			name:    "request without replyTo",
+			method:  MethodRequest,
+			modify:  func(e *Event) { e.ReplyTo = nil },
+			wantErr: "replyTo must be set for method request",
+		},
+		{
+			name:   "valid reply",
+			method: MethodReply,
+			modify: func(e *Event) {
+				e.ReplyTo = nil
+				e.Participants["alice"].ParticipationStatus = String(ParticipationAccepted)
+			},
+		},
+		{
+			name:   "reply from two attendees",
+			method: MethodReply,
+			modify: func(e *Event) {
+				e.Participants["alice"].ParticipationStatus = String(ParticipationAccepted)
+				e.AddParticipant("bob", &Participant{ParticipationStatus: String(ParticipationDeclined)})
+			},
+			wantErr: "exactly one attendee for method reply, got 2",
+		},
+		{
+			name:    "reply without an answer",
+			method:  MethodReply,
+			wantErr: "must be a reply status for method reply",
+		},
+		{
+			name:    "cancel without owner",
+			method:  MethodCancel,
+			modify:  func(e *Event) { delete(e.Participants, "owner") },
+			wantErr: "must include an owner for method cancel",
+		},
+		{
+			name:   "publish without participants",
+			method: MethodPublish,
+			modify: func(e *Event) { e.Participants = nil },
+		},
+	}
+
+	validator := NewValidator(ValidationOptions{CheckMethod: true})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := NewEvent("itip-test", "Planning")
+			event.Start = NewLocalDateTime(time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC))
+			event.Method = String(tt.method)
+			event.ReplyTo = map[string]string{ReplyMethodImip: "mailto:owner@example.com"}
+			event.AddParticipant("owner", &Participant{Email: String("owner@example.com"), Roles: map[string]bool{RoleOwner: true}})
+			event.AddParticipant("alice", &Participant{Email: String("alice@example.com"), Roles: map[string]bool{RoleAttendee: true}})
+			if tt.modify != nil {
+				tt.modify(event)
+			}
+			err := validator.Validate(event)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("Expected no error, got %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCheckMethodReply(t *testing.T) {
+	event := NewEvent("itip-test", "Planning")
+	event.Start = NewLocalDateTime(time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC))
+	event.Method = String(MethodRequest)
+	event.ReplyTo = map[string]string{ReplyMethodImip: "mailto:owner@example.com"}
+	event.AddParticipant("owner", &Participant{Email: String("owner@example.com"), Roles: map[string]bool{RoleOwner: true}})
+	event.AddParticipant("alice", &Participant{Email: String("alice@example.com"), Roles: map[string]bool{RoleAttendee: true}})
+	reply, err := event.Reply("alice", ParticipationTentative, nil)
+	if err != nil {
+		t.Fatalf("Reply failed: %v", err)
+	}
+	if err := NewValidator(ValidationOptions{CheckMethod: true}).Validate(reply); err != nil {
+		t.Errorf("Expected the generated reply to be valid, got %v", err)
+	}
+
+	// Without the option the rules don't apply
+	event.Participants = nil
+	if err := event.Validate(); err != nil {
+		t.Errorf("Expected Validate to ignore method rules, got %v", err)
+	}
+}
@@ -1,9 +1,25 @@
 package jscal
 
 import (
+	"os"
+	"strings"
 	"testing"
 )
 
+// The core module must stay dependency-free; integrations that need
+// third-party libraries belong in nested modules (see package convert)
+func TestCoreModuleHasNoDependencies(t *testing.T) {
+	data, err := os.ReadFile("go.mod")
+	if err != nil {
+		t.Fatalf("Failed to read go.mod: %v", err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "require") {
+			t.Errorf("Expected no dependencies in the core module, found %q", line)
+		}
+	}
+}
+
 func TestParseAllEvents(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -0,0 +1,164 @@
+package jscal
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// languageTagPattern matches the langtag and privateuse productions of
+// RFC 5646 Section 2.1: language with extlangs, script, region, variants,
+// extensions and a private use suffix
+var languageTagPattern = regexp.MustCompile(`(?i)^(?:` +
+	`(?:[a-z]{2,3}(?:-[a-z]{3}){0,3}|[a-z]{4}|[a-z]{5,8})` +
+	`(?:-[a-z]{4})?` +
+	`(?:-(?:[a-z]{2}|[0-9]{3}))?` +
+	`(?:-(?:[a-z0-9]{5,8}|[0-9][a-z0-9]{3}))*` +
+	`(?:-[0-9a-wyz](?:-[a-z0-9]{2,8})+)*` +
+	`(?:-x(?:-[a-z0-9]{1,8})+)?` +
+	`|x(?:-[a-z0-9]{1,8})+)$`)
+
+// irregularLanguageTags lists the grandfathered tags of RFC 5646 that don't
+// follow the langtag syntax
+var irregularLanguageTags = map[string]bool{
+	"en-gb-oed": true, "i-ami": true, "i-bnn": true, "i-default": true, "i-enochian": true,
+	"i-hak": true, "i-klingon": true, "i-lux": true, "i-mingo": true, "i-navajo": true,
+	"i-pwn": true, "i-tao": true, "i-tay": true, "i-tsu": true,
+	"sgn-be-fr": true, "sgn-be-nl": true, "sgn-ch-de": true,
+}
+
+// IsLanguageTag reports whether tag is a well-formed RFC 5646 language tag,
+// such as "de", "zh-Hant-TW" or "sl-rozaj-biske". Subtags aren't checked
+// against the IANA registry.
+func IsLanguageTag(tag string) bool {
+	return languageTagPattern.MatchString(tag) || irregularLanguageTags[strings.ToLower(tag)]
+}
+
+// LanguageTag is a BCP 47 language tag split into its subtags, each in its
+// canonical case
+type LanguageTag struct {
+	Language   string   // primary language and extlangs, e.g. "de" or "zh-yue"
+	Script     string   // e.g. "Hant"
+	Region     string   // e.g. "AT" or "419"
+	Variants   []string // e.g. "rozaj"
+	Extensions string   // extension and private use subtags, e.g. "u-co-phonebk-x-foo"
+}
+
+// ParseLanguageTag parses a BCP 47 language tag. Subtags may be separated by
+// underscores as in POSIX locales and are matched case-insensitively, so
+// "en_us" parses as "en-US". Grandfathered irregular tags are kept whole in
+// Language.
+func ParseLanguageTag(s string) (LanguageTag, error) {
+	tag := strings.ReplaceAll(strings.TrimSpace(s), "_", "-")
+	if !IsLanguageTag(tag) {
+		return LanguageTag{}, fmt.Errorf("invalid language tag %q", s)
+	}
+
+	lower := strings.ToLower(tag)
+	if irregularLanguageTags[lower] {
+		return LanguageTag{Language: lower}, nil
+	}
+	if strings.HasPrefix(lower, "x-") {
+		return LanguageTag{Extensions: lower}, nil
+	}
+
+	subtags := strings.Split(lower, "-")
+	parsed := LanguageTag{Language: subtags[0]}
+	i := 1
+	for ; i < len(subtags) && i < 4 && len(subtags[i]) == 3 && isAlpha(subtags[i]); i++ {
+		parsed.Language += "-" + subtags[i]
+	}
+	if i < len(subtags) && len(subtags[i]) == 4 && isAlpha(subtags[i]) {
+		parsed.Script = strings.ToUpper(subtags[i][:1]) + subtags[i][1:]
+		i++
+	}
+	if i < len(subtags) && (len(subtags[i]) == 2 || len(subtags[i]) == 3) {
+		parsed.Region = strings.ToUpper(subtags[i])
+		i++
+	}
+	for ; i < len(subtags) && len(subtags[i]) > 1; i++ {
+		parsed.Variants = append(parsed.Variants, subtags[i])
+	}
+	if i < len(subtags) {
+		parsed.Extensions = strings.Join(subtags[i:], "-")
+	}
+	return parsed, nil
+}
+
+// String returns the tag in its canonical form
+func (t LanguageTag) String() string {
+	var subtags []string
+	for _, subtag := range []string{t.Language, t.Script, t.Region} {
+		if subtag != "" {
+			subtags = append(subtags, subtag)
+		}
+	}
+	subtags = append(subtags, t.Variants...)
+	if t.Extensions != "" {
+		subtags = append(subtags, t.Extensions)
+	}
+	return strings.Join(subtags, "-")
+}
+
+// CanonicalLanguageTag returns the canonical form of a language tag, e.g.
+// "en-US" for "en_us" and "zh-Hant-TW" for "ZH-hant-tw"
+func CanonicalLanguageTag(s string) (string, error) {
+	tag, err := ParseLanguageTag(s)
+	if err != nil {
+		return "", err
+	}
+	return tag.String(), nil
+}
+
+// languageFallbacks returns the lookup chain of a language tag (RFC 4647
+// Section 3.4): the tag itself and then ever shorter prefixes, dropping a
+// trailing single-letter subtag with its last subtag, e.g. "zh-Hant-TW",
+// "zh-Hant" and "zh". Tags that don't parse fall back to their first
+// subtag.
+func languageFallbacks(language string) []string {
+	tag, err := CanonicalLanguageTag(language)
+	if err != nil {
+		tag = language
+	}
+
+	chain := []string{tag}
+	for {
+		idx := strings.LastIndexAny(tag, "-_")
+		if idx <= 0 {
+			return chain
+		}
+		tag = tag[:idx]
+		if idx = strings.LastIndexAny(tag, "-_"); idx > 0 && len(tag)-idx == 2 {
+			tag = tag[:idx]
+		}
+		chain = append(chain, tag)
+	}
+}
+
+// matchLanguage returns the keys of a map that match a language tag, in the
+// order of its lookup chain. Keys are compared in their canonical form.
+func matchLanguage[V any](m map[string]V, language string) []string {
+	var matches []string
+	for _, candidate := range languageFallbacks(language) {
+		for _, key := range sortedKeys(m) {
+			canonical, err := CanonicalLanguageTag(key)
+			if err != nil {
+				canonical = key
+			}
+			if strings.EqualFold(canonical, candidate) {
+				matches = append(matches, key)
+			}
+		}
+	}
+	return matches
+}
+
+// isAlpha reports whether s only contains ASCII letters
+func isAlpha(s string) bool {
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,132 @@
+package jscal
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIsLanguageTag(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want bool
+	}{
+		{"de", true},
+		{"en-US", true},
+		{"zh-Hant-TW", true},
+		{"sl-rozaj-biske", true},
+		{"es-419", true},
+		{"zh-yue-HK", true},
+		{"de-CH-x-phonebk", true},
+		{"en-a-bbb-x-a-ccc", true},
+		{"x-whatever", true},
+		{"i-klingon", true},
+		{"", false},
+		{"german", true}, // 5-8 letter languages are reserved but well-formed
+		{"en_US", false},
+		{"e", false},
+		{"en-", false},
+		{"toolonglanguage", false},
+		{"de-x", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsLanguageTag(tt.tag); got != tt.want {
+			t.Errorf("IsLanguageTag(%q): expected %v, got %v", tt.tag, tt.want, got)
+		}
+	}
+}
+
+func TestParseLanguageTag(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    LanguageTag
+		wantErr bool
+	}{
+		{"de", LanguageTag{Language: "de"}, false},
+		{"en_us", LanguageTag{Language: "en", Region: "US"}, false},
+		{"ZH-hant-tw", LanguageTag{Language: "zh", Script: "Hant", Region: "TW"}, false},
+		{"zh-yue-HK", LanguageTag{Language: "zh-yue", Region: "HK"}, false},
+		{"es-419", LanguageTag{Language: "es", Region: "419"}, false},
+		{"sl-rozaj-biske", LanguageTag{Language: "sl", Variants: []string{"rozaj", "biske"}}, false},
+		{"de-CH-1996", LanguageTag{Language: "de", Region: "CH", Variants: []string{"1996"}}, false},
+		{"de-DE-u-co-phonebk", LanguageTag{Language: "de", Region: "DE", Extensions: "u-co-phonebk"}, false},
+		{"x-Whatever", LanguageTag{Extensions: "x-whatever"}, false},
+		{"i-Klingon", LanguageTag{Language: "i-klingon"}, false},
+		{"en--us", LanguageTag{}, true},
+		{"", LanguageTag{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseLanguageTag(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseLanguageTag(%q): expected error %v, got %v", tt.input, tt.wantErr, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseLanguageTag(%q): expected %+v, got %+v", tt.input, tt.want, got)
+		}
+	}
+}
+
+func TestCanonicalLanguageTag(t *testing.T) {
+	tests := map[string]string{
+		"en_us":              "en-US",
+		"ZH-hant-tw":         "zh-Hant-TW",
+		"DE-ch-1996":         "de-CH-1996",
+		"en-US-X-Private":    "en-US-x-private",
+		"sr-latn-rs-u-nu-ar": "sr-Latn-RS-u-nu-ar",
+	}
+	for input, want := range tests {
+		if got, err := CanonicalLanguageTag(input); err != nil || got != want {
+			t.Errorf("CanonicalLanguageTag(%q): expected %s, got %s (%v)", input, want, got, err)
+		}
+	}
+}
+
+func TestLanguageFallbacks(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"de", []string{"de"}},
+		{"zh_hant_tw", []string{"zh-Hant-TW", "zh-Hant", "zh"}},
+		{"de-DE-u-co-phonebk", []string{"de-DE-u-co-phonebk", "de-DE-u-co", "de-DE", "de"}},
+		{"not a tag", []string{"not a tag"}},
+	}
+	for _, tt := range tests {
+		if got := languageFallbacks(tt.input); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("languageFallbacks(%q): expected %v, got %v", tt.input, tt.want, got)
+		}
+	}
+}
+
+func TestValidateLanguageTags(t *testing.T) {
+	event := NewEvent("lang-test", "Meeting")
+	event.Start = NewLocalDateTime(time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC))
+	event.Locale = String("en_US")
+	event.AddParticipant("p1", &Participant{Language: String("deutsch!")})
+
+	err := event.Validate()
+	if err == nil {
+		t.Fatal("Expected validation errors for the locale and the participant language")
+	}
+	fields := make(map[string]bool)
+	for _, valErr := range err.(ValidationErrors) {
+		fields[valErr.Field] = true
+	}
+	if !fields["locale"] || !fields["participants[p1].language"] {
+		t.Errorf("Expected errors for locale and participants[p1].language, got %v", err)
+	}
+
+	relaxed := NewValidator(ValidationOptions{AllowInvalidLanguageTags: true})
+	if err := relaxed.Validate(event); err != nil && strings.Contains(err.Error(), "language tag") {
+		t.Errorf("Expected language tags to be accepted, got %v", err)
+	}
+
+	event.Normalize(NormalizeOptions{})
+	if event.GetLocale() != "en-US" {
+		t.Errorf("Expected Normalize to canonicalize the locale to en-US, got %s", event.GetLocale())
+	}
+}
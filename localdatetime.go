@@ -99,6 +99,13 @@ func ParseLocalDateTime(s string) (*LocalDateTime, error) {
 	return NewLocalDateTime(t), nil
 }
 
+// In returns the instant at which the LocalDateTime's wall clock time occurs
+// in loc. Times skipped by a DST transition are normalized as by time.Date.
+func (ldt *LocalDateTime) In(loc *time.Location) time.Time {
+	t := ldt.Time()
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+}
+
 // Equal returns true if the two LocalDateTime values represent the same moment.
 // This comparison ignores timezone differences.
 func (ldt *LocalDateTime) Equal(other *LocalDateTime) bool {
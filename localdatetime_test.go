@@ -444,3 +444,19 @@ func TestLocalDateTimeStructInJSON(t *testing.T) {
 		t.Error("Unmarshaled struct doesn't match original")
 	}
 }
+
+func TestLocalDateTimeIn(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+
+	ldt := NewLocalDateTime(time.Date(2025, 7, 1, 9, 30, 0, 0, time.UTC))
+	got := ldt.In(berlin)
+	if want := time.Date(2025, 7, 1, 7, 30, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("In(Europe/Berlin) = %s, want %s", got.UTC(), want)
+	}
+	if got.Location() != berlin || got.Hour() != 9 {
+		t.Errorf("Expected wall clock 09:30 in Europe/Berlin, got %s", got)
+	}
+}
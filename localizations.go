@@ -0,0 +1,90 @@
+package jscal
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// validateLocalizations checks the localizations of an Event or Task: each
+// key must be a language tag, and each path of a patch must name a
+// declared or vendor-specific (prefixed) property, have its parents in the
+// object and leave it decodable, so that "title" can't be patched to a
+// number (RFC 8984 Section 4.6.1)
+func validateLocalizations(object interface{}, localizations map[string]map[string]interface{},
+	opts *ValidationOptions) ValidationErrors {
+	if len(localizations) == 0 {
+		return nil
+	}
+	var obj map[string]interface{}
+	if opts.enforceLocalizationPatches() {
+		var err error
+		if obj, err = toJSONObject(object); err != nil {
+			return ValidationErrors{{Field: "localizations", Message: err.Error()}}
+		}
+	}
+
+	var errors ValidationErrors
+	t := reflect.TypeOf(object).Elem()
+	known := knownProperties(t)
+	for _, tag := range sortedKeys(localizations) {
+		field := fmt.Sprintf("localizations[%s]", tag)
+		if opts.enforceLanguageTags() && !IsLanguageTag(tag) {
+			errors = append(errors, ValidationError{
+				Field:   field,
+				Value:   tag,
+				Message: "invalid language tag",
+			})
+		}
+		if !opts.enforceLocalizationPatches() {
+			continue
+		}
+
+		patch := localizations[tag]
+		for _, path := range sortedKeys(patch) {
+			pathField := fmt.Sprintf("%s[%s]", field, path)
+			segments := splitPointer(path)
+			if len(segments) == 0 {
+				errors = append(errors, ValidationError{Field: pathField, Value: path, Message: "invalid patch path"})
+				continue
+			}
+			if !known[segments[0]] && !strings.Contains(segments[0], ":") {
+				errors = append(errors, ValidationError{
+					Field:   pathField,
+					Value:   path,
+					Message: fmt.Sprintf("unknown property %s", segments[0]),
+				})
+				continue
+			}
+			if err := checkPatchPath(obj, t, path, patch[path]); err != nil {
+				errors = append(errors, ValidationError{Field: pathField, Value: patch[path], Message: err.Error()})
+			}
+		}
+	}
+	return errors
+}
+
+// checkPatchPath applies a single path of a patch to a copy of obj and
+// decodes the result as t
+func checkPatchPath(obj map[string]interface{}, t reflect.Type, path string, value interface{}) error {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	var patched map[string]interface{}
+	if err := json.Unmarshal(data, &patched); err != nil {
+		return err
+	}
+	if err := applyPatch(patched, map[string]interface{}{path: value}); err != nil {
+		return err
+	}
+
+	if data, err = json.Marshal(patched); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, reflect.New(t).Interface()); err != nil {
+		return fmt.Errorf("invalid value: %v", err)
+	}
+	return nil
+}
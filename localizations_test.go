@@ -0,0 +1,106 @@
+package jscal
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateLocalizations(t *testing.T) {
+	tests := []struct {
+		name    string
+		tag     string
+		patch   map[string]interface{}
+		opts    *ValidationOptions
+		wantErr string
+	}{
+		{
+			name:  "valid title",
+			tag:   "de",
+			patch: map[string]interface{}{"title": "Besprechung"},
+		},
+		{
+			name:  "valid nested path",
+			tag:   "fr-CA",
+			patch: map[string]interface{}{"locations/loc1/name": "Salle A"},
+		},
+		{
+			name:  "vendor property",
+			tag:   "de",
+			patch: map[string]interface{}{"example.com:note": "Notiz"},
+		},
+		{
+			name:    "invalid language tag",
+			tag:     "german_DE",
+			patch:   map[string]interface{}{"title": "Besprechung"},
+			wantErr: "invalid language tag",
+		},
+		{
+			name:  "invalid language tag allowed",
+			tag:   "german_DE",
+			patch: map[string]interface{}{"title": "Besprechung"},
+			opts:  &ValidationOptions{AllowInvalidLanguageTags: true},
+		},
+		{
+			name:    "title not a string",
+			tag:     "de",
+			patch:   map[string]interface{}{"title": 42},
+			wantErr: "invalid value",
+		},
+		{
+			name:    "unknown property",
+			tag:     "de",
+			patch:   map[string]interface{}{"titel": "Besprechung"},
+			wantErr: "unknown property titel",
+		},
+		{
+			name:    "missing parent",
+			tag:     "de",
+			patch:   map[string]interface{}{"locations/nowhere/name": "Nirgendwo"},
+			wantErr: "does not exist",
+		},
+		{
+			name:  "patches not checked",
+			tag:   "de",
+			patch: map[string]interface{}{"title": 42},
+			opts:  &ValidationOptions{SkipLocalizationPatches: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := NewEvent("loc-test", "Meeting")
+			event.Start = NewLocalDateTime(time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC))
+			event.AddLocation("loc1", &Location{Name: String("Room A")})
+			event.Localizations = map[string]map[string]interface{}{tt.tag: tt.patch}
+
+			var err error
+			if tt.opts != nil {
+				err = NewValidator(*tt.opts).Validate(event)
+			} else {
+				err = event.Validate()
+			}
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("Expected no error, got %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateTaskLocalizations(t *testing.T) {
+	task := NewTask("loc-task", "Report")
+	task.Localizations = map[string]map[string]interface{}{"de": {"percentComplete": "half"}}
+
+	err := task.Validate()
+	if err == nil || !strings.Contains(err.Error(), "invalid value") {
+		t.Errorf("Expected an invalid value error, got %v", err)
+	}
+
+	errs, _ := err.(ValidationErrors)
+	if len(errs) != 1 || errs[0].Field != "localizations[de][percentComplete]" {
+		t.Errorf("Expected the error at localizations[de][percentComplete], got %v", errs)
+	}
+}
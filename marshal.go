@@ -0,0 +1,1682 @@
+package jscal
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Events, tasks and the objects they commonly contain are encoded and
+// decoded by hand: walking their many optional fields with encoding/json's
+// reflection dominated the cost of bulk conversions. The output is byte for byte what encoding/json
+// produces, and decoding matches it too, including case-insensitive property
+// names. Whenever the fast path meets something unusual, such as a type
+// error, the object is decoded again the reflection way so errors stay the
+// same. Nested objects without a fast path still go through encoding/json.
+
+var (
+	eventType           = reflect.TypeOf(Event{})
+	taskType            = reflect.TypeOf(Task{})
+	participantType     = reflect.TypeOf(Participant{})
+	locationType        = reflect.TypeOf(Location{})
+	virtualLocationType = reflect.TypeOf(VirtualLocation{})
+	linkType            = reflect.TypeOf(Link{})
+	relationType        = reflect.TypeOf(Relation{})
+	recurrenceRuleType  = reflect.TypeOf(RecurrenceRule{})
+	nDayType            = reflect.TypeOf(NDay{})
+	alertType           = reflect.TypeOf(Alert{})
+	offsetTriggerType   = reflect.TypeOf(OffsetTrigger{})
+)
+
+// errNotSimple makes a decoder fall back to encoding/json
+var errNotSimple = errors.New("jscal: value needs encoding/json")
+
+// objectEncoder appends the members of a JSON object, leaving out unset
+// members like the omitempty option
+type objectEncoder struct {
+	buf     []byte
+	members int
+	err     error
+	fields  []string // if not nil, the only members to write
+}
+
+func (o *objectEncoder) begin() {
+	o.buf = append(o.buf, '{')
+}
+
+func (o *objectEncoder) end() ([]byte, error) {
+	if o.err != nil {
+		return nil, o.err
+	}
+	return append(o.buf, '}'), nil
+}
+
+// wants reports whether the member should be written
+func (o *objectEncoder) wants(name string) bool {
+	if o.fields == nil {
+		return true
+	}
+	for _, field := range o.fields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *objectEncoder) key(name string) {
+	if o.members > 0 {
+		o.buf = append(o.buf, ',')
+	}
+	o.members++
+	o.buf = appendJSONString(o.buf, name)
+	o.buf = append(o.buf, ':')
+}
+
+func (o *objectEncoder) str(name, v string) {
+	if !o.wants(name) {
+		return
+	}
+	o.key(name)
+	o.buf = appendJSONString(o.buf, v)
+}
+
+func (o *objectEncoder) stringPtr(name string, v *string) {
+	if v != nil {
+		o.str(name, *v)
+	}
+}
+
+func (o *objectEncoder) intPtr(name string, v *int) {
+	if v != nil && o.wants(name) {
+		o.key(name)
+		o.buf = strconv.AppendInt(o.buf, int64(*v), 10)
+	}
+}
+
+func (o *objectEncoder) boolPtr(name string, v *bool) {
+	if v != nil && o.wants(name) {
+		o.key(name)
+		o.buf = strconv.AppendBool(o.buf, *v)
+	}
+}
+
+func (o *objectEncoder) utcDateTimePtr(name string, v *UTCDateTime) {
+	if v == nil || !o.wants(name) {
+		return
+	}
+	// String formats four-digit years like this layout
+	if t := time.Time(*v).UTC(); t.Year() >= 0 && t.Year() <= 9999 {
+		o.key(name)
+		o.buf = append(t.AppendFormat(append(o.buf, '"'), time.RFC3339Nano), '"')
+		return
+	}
+	data, err := v.MarshalJSON()
+	if err != nil {
+		o.err = err
+		return
+	}
+	o.key(name)
+	o.buf = append(o.buf, data...)
+}
+
+func (o *objectEncoder) localDateTimePtr(name string, v *LocalDateTime) {
+	if v == nil || !o.wants(name) {
+		return
+	}
+	// String formats four-digit years like this layout, without the zone
+	if t := time.Time(*v); t.Year() >= 0 && t.Year() <= 9999 {
+		o.key(name)
+		o.buf = append(t.AppendFormat(append(o.buf, '"'), "2006-01-02T15:04:05.999999999"), '"')
+		return
+	}
+	o.str(name, v.String())
+}
+
+func (o *objectEncoder) boolMap(name string, m map[string]bool) {
+	if len(m) == 0 || !o.wants(name) {
+		return
+	}
+	o.key(name)
+	o.buf = append(o.buf, '{')
+	for i, k := range sortedKeys(m) {
+		if i > 0 {
+			o.buf = append(o.buf, ',')
+		}
+		o.buf = appendJSONString(o.buf, k)
+		o.buf = append(o.buf, ':')
+		o.buf = strconv.AppendBool(o.buf, m[k])
+	}
+	o.buf = append(o.buf, '}')
+}
+
+func (o *objectEncoder) stringMap(name string, m map[string]string) {
+	if len(m) == 0 || !o.wants(name) {
+		return
+	}
+	o.key(name)
+	o.buf = append(o.buf, '{')
+	for i, k := range sortedKeys(m) {
+		if i > 0 {
+			o.buf = append(o.buf, ',')
+		}
+		o.buf = appendJSONString(o.buf, k)
+		o.buf = append(o.buf, ':')
+		o.buf = appendJSONString(o.buf, m[k])
+	}
+	o.buf = append(o.buf, '}')
+}
+
+func (o *objectEncoder) strings(name string, s []string) {
+	if len(s) == 0 || !o.wants(name) {
+		return
+	}
+	o.key(name)
+	o.buf = append(o.buf, '[')
+	for i, v := range s {
+		if i > 0 {
+			o.buf = append(o.buf, ',')
+		}
+		o.buf = appendJSONString(o.buf, v)
+	}
+	o.buf = append(o.buf, ']')
+}
+
+func (o *objectEncoder) ints(name string, s []int) {
+	if len(s) == 0 || !o.wants(name) {
+		return
+	}
+	o.key(name)
+	o.buf = append(o.buf, '[')
+	for i, v := range s {
+		if i > 0 {
+			o.buf = append(o.buf, ',')
+		}
+		o.buf = strconv.AppendInt(o.buf, int64(v), 10)
+	}
+	o.buf = append(o.buf, ']')
+}
+
+// appendObjects encodes a map of objects with their appendJSON method, in
+// key order
+func appendObjects[V any](o *objectEncoder, name string, m map[string]*V, appendJSON func(*V, []byte) ([]byte, error)) {
+	if len(m) == 0 || o.err != nil || !o.wants(name) {
+		return
+	}
+	o.key(name)
+	o.buf = append(o.buf, '{')
+	for i, k := range sortedKeys(m) {
+		if i > 0 {
+			o.buf = append(o.buf, ',')
+		}
+		o.buf = appendJSONString(o.buf, k)
+		o.buf = append(o.buf, ':')
+		if m[k] == nil {
+			o.buf = append(o.buf, "null"...)
+			continue
+		}
+		buf, err := appendJSON(m[k], o.buf)
+		if err != nil {
+			o.err = err
+			return
+		}
+		o.buf = buf
+	}
+	o.buf = append(o.buf, '}')
+}
+
+// appendArray encodes a slice of objects with their appendJSON method
+func appendArray[V any](o *objectEncoder, name string, s []V, appendJSON func(*V, []byte) ([]byte, error)) {
+	if len(s) == 0 || o.err != nil || !o.wants(name) {
+		return
+	}
+	o.key(name)
+	o.buf = append(o.buf, '[')
+	for i := range s {
+		if i > 0 {
+			o.buf = append(o.buf, ',')
+		}
+		buf, err := appendJSON(&s[i], o.buf)
+		if err != nil {
+			o.err = err
+			return
+		}
+		o.buf = buf
+	}
+	o.buf = append(o.buf, ']')
+}
+
+// value encodes a member with appendValue
+func (o *objectEncoder) value(name string, v interface{}) {
+	if o.err != nil || !o.wants(name) {
+		return
+	}
+	buf := append(o.buf, ',')
+	if o.members == 0 {
+		buf = buf[:len(buf)-1]
+	}
+	buf = appendJSONString(buf, name)
+	buf, err := appendValue(append(buf, ':'), v)
+	if err != nil {
+		o.err = err
+		return
+	}
+	o.buf = buf
+	o.members++
+}
+
+// appendValue appends v as JSON. Values as encoding/json decodes them into
+// interface{}, which is what patches and extensions hold, are encoded
+// directly; anything else goes through encoding/json.
+func appendValue(buf []byte, v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case nil:
+		return append(buf, "null"...), nil
+	case string:
+		return appendJSONString(buf, v), nil
+	case bool:
+		return strconv.AppendBool(buf, v), nil
+	case float64:
+		if v == float64(int64(v)) && v > -1e15 && v < 1e15 && (v != 0 || !math.Signbit(v)) {
+			return strconv.AppendInt(buf, int64(v), 10), nil
+		}
+	case map[string]interface{}:
+		if v == nil {
+			return append(buf, "null"...), nil
+		}
+		buf = append(buf, '{')
+		for i, k := range sortedKeys(v) {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = append(appendJSONString(buf, k), ':')
+			var err error
+			if buf, err = appendValue(buf, v[k]); err != nil {
+				return nil, err
+			}
+		}
+		return append(buf, '}'), nil
+	case []interface{}:
+		if v == nil {
+			return append(buf, "null"...), nil
+		}
+		buf = append(buf, '[')
+		for i, e := range v {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			var err error
+			if buf, err = appendValue(buf, e); err != nil {
+				return nil, err
+			}
+		}
+		return append(buf, ']'), nil
+	case map[string]map[string]interface{}:
+		if v == nil {
+			return append(buf, "null"...), nil
+		}
+		buf = append(buf, '{')
+		for i, k := range sortedKeys(v) {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = append(appendJSONString(buf, k), ':')
+			var err error
+			if buf, err = appendValue(buf, v[k]); err != nil {
+				return nil, err
+			}
+		}
+		return append(buf, '}'), nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return append(buf, data...), nil
+}
+
+// extensions encodes the extension properties not declared by t, see
+// appendExtensions
+func (o *objectEncoder) extensions(extensions map[string]interface{}, t reflect.Type) {
+	if len(extensions) == 0 {
+		return
+	}
+	for _, name := range sortedKeys(extensions) {
+		if !isKnownProperty(t, name) {
+			o.value(name, extensions[name])
+		}
+	}
+}
+
+// appendJSONString appends s as a JSON string, escaped like encoding/json.
+// Control characters, invalid UTF-8 and the JavaScript line separators are
+// left to encoding/json.
+func appendJSONString(buf []byte, s string) []byte {
+	start := len(buf)
+	buf = append(buf, '"')
+	plain := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c >= utf8.RuneSelf {
+			r, size := utf8.DecodeRuneInString(s[i:])
+			if (r == utf8.RuneError && size == 1) || r == '\u2028' || r == '\u2029' {
+				data, _ := json.Marshal(s)
+				return append(buf[:start], data...)
+			}
+			i += size
+			continue
+		}
+		if safeASCII[c] {
+			i++
+			continue
+		}
+		if c < 0x20 {
+			data, _ := json.Marshal(s)
+			return append(buf[:start], data...)
+		}
+		buf = append(buf, s[plain:i]...)
+		if c == '"' || c == '\\' {
+			buf = append(buf, '\\', c)
+		} else {
+			buf = append(buf, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xf])
+		}
+		i++
+		plain = i
+	}
+	buf = append(buf, s[plain:]...)
+	return append(buf, '"')
+}
+
+const hexDigits = "0123456789abcdef"
+
+// safeASCII reports the ASCII characters encoding/json writes unescaped
+var safeASCII = func() (safe [utf8.RuneSelf]bool) {
+	for c := 0x20; c < utf8.RuneSelf; c++ {
+		safe[c] = c != '"' && c != '\\' && c != '<' && c != '>' && c != '&'
+	}
+	return safe
+}()
+
+// scanObject calls fn for each member of a JSON object, in order. data must
+// be valid JSON; errNotSimple is returned if it isn't an object.
+func scanObject(data []byte, fn func(key string, value []byte) error) error {
+	i := skipSpace(data, 0)
+	if i >= len(data) || data[i] != '{' {
+		return errNotSimple
+	}
+	i = skipSpace(data, i+1)
+	if data[i] == '}' {
+		return nil
+	}
+	for {
+		end := stringEnd(data, i)
+		key, err := decodeKey(data[i:end])
+		if err != nil {
+			return err
+		}
+		i = skipSpace(data, end)
+		i = skipSpace(data, i+1) // ':'
+		end = valueEnd(data, i)
+		if err := fn(key, data[i:end]); err != nil {
+			return err
+		}
+		i = skipSpace(data, end)
+		if data[i] == '}' {
+			return nil
+		}
+		i = skipSpace(data, i+1) // ','
+	}
+}
+
+// decodeKey decodes an object key
+func decodeKey(raw []byte) (string, error) {
+	if s, ok := simpleString(raw); ok {
+		return s, nil
+	}
+	var s string
+	err := json.Unmarshal(raw, &s)
+	return s, err
+}
+
+func skipSpace(data []byte, i int) int {
+	for i < len(data) && (data[i] == ' ' || data[i] == '\t' || data[i] == '\n' || data[i] == '\r') {
+		i++
+	}
+	return i
+}
+
+// stringEnd returns the index after the string starting at i
+func stringEnd(data []byte, i int) int {
+	for i++; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return i
+}
+
+// valueEnd returns the index after the value starting at i
+func valueEnd(data []byte, i int) int {
+	switch data[i] {
+	case '"':
+		return stringEnd(data, i)
+	case '{', '[':
+		depth := 0
+		for ; i < len(data); i++ {
+			switch data[i] {
+			case '"':
+				i = stringEnd(data, i) - 1
+			case '{', '[':
+				depth++
+			case '}', ']':
+				if depth--; depth == 0 {
+					return i + 1
+				}
+			}
+		}
+		return i
+	default:
+		for i < len(data) && data[i] != ',' && data[i] != '}' && data[i] != ']' &&
+			data[i] != ' ' && data[i] != '\t' && data[i] != '\n' && data[i] != '\r' {
+			i++
+		}
+		return i
+	}
+}
+
+// simpleString returns the contents of a JSON string without escapes, which
+// decode to themselves
+func simpleString(raw []byte) (string, bool) {
+	if len(raw) < 2 || raw[0] != '"' {
+		return "", false
+	}
+	content := raw[1 : len(raw)-1]
+	ascii := true
+	for _, c := range content {
+		if c == '\\' {
+			return "", false
+		}
+		if c >= utf8.RuneSelf {
+			ascii = false
+		}
+	}
+	if !ascii && !utf8.Valid(content) {
+		return "", false
+	}
+	return string(content), true
+}
+
+func isNull(raw []byte) bool {
+	return string(raw) == "null"
+}
+
+func decodeString(raw []byte, dst *string) error {
+	if s, ok := simpleString(raw); ok {
+		*dst = s
+		return nil
+	}
+	return json.Unmarshal(raw, dst)
+}
+
+func decodeStringPtr(raw []byte, dst **string) error {
+	if isNull(raw) {
+		*dst = nil
+		return nil
+	}
+	if s, ok := simpleString(raw); ok {
+		*dst = &s
+		return nil
+	}
+	return json.Unmarshal(raw, dst)
+}
+
+func decodeIntPtr(raw []byte, dst **int) error {
+	if isNull(raw) {
+		*dst = nil
+		return nil
+	}
+	digits := raw
+	if len(digits) > 0 && digits[0] == '-' {
+		digits = digits[1:]
+	}
+	if len(digits) == 0 || len(digits) > 18 {
+		return json.Unmarshal(raw, dst)
+	}
+	n := 0
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return json.Unmarshal(raw, dst)
+		}
+		n = n*10 + int(c-'0')
+	}
+	if raw[0] == '-' {
+		n = -n
+	}
+	*dst = &n
+	return nil
+}
+
+func decodeBoolPtr(raw []byte, dst **bool) error {
+	switch string(raw) {
+	case "null":
+		*dst = nil
+	case "true":
+		v := true
+		*dst = &v
+	case "false":
+		v := false
+		*dst = &v
+	default:
+		return json.Unmarshal(raw, dst)
+	}
+	return nil
+}
+
+func decodeUTCDateTimePtr(raw []byte, dst **UTCDateTime) error {
+	if isNull(raw) {
+		*dst = nil
+		return nil
+	}
+	s, ok := simpleString(raw)
+	if !ok {
+		return json.Unmarshal(raw, dst)
+	}
+	u, err := ParseUTCDateTime(s)
+	if err != nil {
+		return err
+	}
+	*dst = u
+	return nil
+}
+
+func decodeLocalDateTimePtr(raw []byte, dst **LocalDateTime) error {
+	if isNull(raw) {
+		*dst = nil
+		return nil
+	}
+	s, ok := simpleString(raw)
+	if !ok {
+		return json.Unmarshal(raw, dst)
+	}
+	ldt, err := ParseLocalDateTime(s)
+	if err != nil {
+		return err
+	}
+	*dst = ldt
+	return nil
+}
+
+func decodeBoolMap(raw []byte, dst *map[string]bool) error {
+	if isNull(raw) {
+		*dst = nil
+		return nil
+	}
+	if *dst == nil {
+		*dst = make(map[string]bool)
+	}
+	m := *dst
+	err := scanObject(raw, func(key string, value []byte) error {
+		switch string(value) {
+		case "true":
+			m[key] = true
+		case "false":
+			m[key] = false
+		default:
+			return errNotSimple
+		}
+		return nil
+	})
+	if err == errNotSimple {
+		return json.Unmarshal(raw, dst)
+	}
+	return err
+}
+
+func decodeStringMap(raw []byte, dst *map[string]string) error {
+	if isNull(raw) {
+		*dst = nil
+		return nil
+	}
+	if *dst == nil {
+		*dst = make(map[string]string)
+	}
+	m := *dst
+	err := scanObject(raw, func(key string, value []byte) error {
+		s, ok := simpleString(value)
+		if !ok {
+			return errNotSimple
+		}
+		m[key] = s
+		return nil
+	})
+	if err == errNotSimple {
+		return json.Unmarshal(raw, dst)
+	}
+	return err
+}
+
+func decodeStrings(raw []byte, dst *[]string) error {
+	return decodeArray(raw, dst, func(s *string, value []byte) error {
+		return decodeString(value, s)
+	})
+}
+
+func decodeInts(raw []byte, dst *[]int) error {
+	return decodeArray(raw, dst, func(n *int, value []byte) error {
+		var v *int
+		if err := decodeIntPtr(value, &v); err != nil {
+			return err
+		}
+		if v != nil {
+			*n = *v
+		}
+		return nil
+	})
+}
+
+// scanArray calls fn for each element of a JSON array, in order. data must
+// be valid JSON; errNotSimple is returned if it isn't an array.
+func scanArray(data []byte, fn func(value []byte) error) error {
+	i := skipSpace(data, 0)
+	if i >= len(data) || data[i] != '[' {
+		return errNotSimple
+	}
+	i = skipSpace(data, i+1)
+	if data[i] == ']' {
+		return nil
+	}
+	for {
+		end := valueEnd(data, i)
+		if err := fn(data[i:end]); err != nil {
+			return err
+		}
+		i = skipSpace(data, end)
+		if data[i] == ']' {
+			return nil
+		}
+		i = skipSpace(data, i+1) // ','
+	}
+}
+
+// decodeArray decodes a JSON array with an element decoder, falling back to
+// encoding/json for anything the decoder doesn't handle
+func decodeArray[V any](raw []byte, dst *[]V, decode func(*V, []byte) error) error {
+	if isNull(raw) {
+		*dst = nil
+		return nil
+	}
+	s := (*dst)[:0]
+	err := scanArray(raw, func(value []byte) error {
+		var v V
+		if err := decode(&v, value); err != nil {
+			return err
+		}
+		s = append(s, v)
+		return nil
+	})
+	if err == errNotSimple {
+		return json.Unmarshal(raw, dst)
+	}
+	if err == nil && s == nil {
+		s = []V{}
+	}
+	*dst = s
+	return err
+}
+
+// decodeObjects decodes a map of objects with their decodeJSON method
+func decodeObjects[V any](raw []byte, dst *map[string]*V, decodeJSON func(*V, []byte) error) error {
+	if isNull(raw) {
+		*dst = nil
+		return nil
+	}
+	if *dst == nil {
+		*dst = make(map[string]*V)
+	}
+	m := *dst
+	err := scanObject(raw, func(key string, value []byte) error {
+		if isNull(value) {
+			m[key] = nil
+			return nil
+		}
+		v := new(V)
+		if err := decodeJSON(v, value); err != nil {
+			return err
+		}
+		m[key] = v
+		return nil
+	})
+	if err == errNotSimple {
+		return json.Unmarshal(raw, dst)
+	}
+	return err
+}
+
+// decodeValue decodes JSON into interface{} like encoding/json
+func decodeValue(raw []byte) (interface{}, error) {
+	switch raw[0] {
+	case 'n':
+		return nil, nil
+	case 't':
+		return true, nil
+	case 'f':
+		return false, nil
+	case '"':
+		var s string
+		err := decodeString(raw, &s)
+		return s, err
+	case '{':
+		m := make(map[string]interface{})
+		err := scanObject(raw, func(key string, value []byte) error {
+			v, err := decodeValue(value)
+			m[key] = v
+			return err
+		})
+		return m, err
+	case '[':
+		a := []interface{}{}
+		err := scanArray(raw, func(value []byte) error {
+			v, err := decodeValue(value)
+			a = append(a, v)
+			return err
+		})
+		return a, err
+	}
+	f, err := strconv.ParseFloat(string(raw), 64)
+	if err != nil {
+		return nil, errNotSimple
+	}
+	return f, nil
+}
+
+// decodePatches decodes a map of patch objects, such as recurrenceOverrides
+func decodePatches(raw []byte, dst *map[string]map[string]interface{}) error {
+	if isNull(raw) {
+		*dst = nil
+		return nil
+	}
+	if *dst == nil {
+		*dst = make(map[string]map[string]interface{})
+	}
+	m := *dst
+	err := scanObject(raw, func(key string, value []byte) error {
+		if isNull(value) {
+			m[key] = nil
+			return nil
+		}
+		if value[0] != '{' {
+			return errNotSimple
+		}
+		v, err := decodeValue(value)
+		if err != nil {
+			return err
+		}
+		m[key] = v.(map[string]interface{})
+		return nil
+	})
+	if err == errNotSimple {
+		return json.Unmarshal(raw, dst)
+	}
+	return err
+}
+
+// foldedProperty returns the declared property of t matching name
+// case-insensitively, as encoding/json matches it
+func foldedProperty(t reflect.Type, name string) (string, bool) {
+	for property := range knownProperties(t) {
+		if strings.EqualFold(property, name) {
+			return property, true
+		}
+	}
+	return "", false
+}
+
+// decodeObject decodes a JSON object with a member decoder, matching names
+// case-insensitively and collecting undeclared properties of t if
+// extensions isn't nil
+func decodeObject(data []byte, t reflect.Type, member func(key string, value []byte) (bool, error),
+	extensions *map[string]interface{}) error {
+	return scanObject(data, func(key string, value []byte) error {
+		known, err := member(key, value)
+		if err != nil || known {
+			return err
+		}
+		if name, ok := foldedProperty(t, key); ok {
+			_, err := member(name, value)
+			return err
+		}
+		if extensions == nil {
+			return nil
+		}
+		v, err := decodeValue(value)
+		if err == errNotSimple {
+			err = json.Unmarshal(value, &v)
+		}
+		if err != nil {
+			return err
+		}
+		if *extensions == nil {
+			*extensions = make(map[string]interface{})
+		}
+		(*extensions)[key] = v
+		return nil
+	})
+}
+
+// appendJSON appends the Event as JSON
+func (e *Event) appendJSON(buf []byte) ([]byte, error) {
+	return e.appendFields(buf, nil)
+}
+
+// appendFields appends the Event as JSON with only the given top-level
+// properties, or all of them if fields is nil
+func (e *Event) appendFields(buf []byte, fields []string) ([]byte, error) {
+	o := objectEncoder{buf: buf, fields: fields}
+	o.begin()
+	o.str("@type", e.Type)
+	o.str("uid", e.UID)
+	o.utcDateTimePtr("created", e.Created)
+	o.utcDateTimePtr("updated", e.Updated)
+	o.intPtr("sequence", e.Sequence)
+	o.stringPtr("method", e.Method)
+	o.stringPtr("prodId", e.ProdId)
+	o.stringPtr("title", e.Title)
+	o.stringPtr("description", e.Description)
+	o.stringPtr("descriptionContentType", e.DescriptionContentType)
+	o.boolPtr("showWithoutTime", e.ShowWithoutTime)
+	o.stringPtr("locale", e.Locale)
+	if len(e.Localizations) > 0 {
+		o.value("localizations", e.Localizations)
+	}
+	o.boolMap("keywords", e.Keywords)
+	o.boolMap("categories", e.Categories)
+	o.stringPtr("color", e.Color)
+	o.localDateTimePtr("start", e.Start)
+	o.stringPtr("duration", e.Duration)
+	o.stringPtr("timeZone", e.TimeZone)
+	if len(e.TimeZones) > 0 {
+		o.value("timeZones", e.TimeZones)
+	}
+	o.localDateTimePtr("recurrenceId", e.RecurrenceId)
+	o.stringPtr("recurrenceIdTimeZone", e.RecurrenceIdTimeZone)
+	appendArray(&o, "recurrenceRules", e.RecurrenceRules, (*RecurrenceRule).appendJSON)
+	if len(e.RecurrenceOverrides) > 0 {
+		o.value("recurrenceOverrides", e.RecurrenceOverrides)
+	}
+	appendArray(&o, "excludedRecurrenceRules", e.ExcludedRecurrenceRules, (*RecurrenceRule).appendJSON)
+	o.boolPtr("excluded", e.Excluded)
+	o.intPtr("priority", e.Priority)
+	o.stringPtr("freeBusyStatus", e.FreeBusyStatus)
+	o.stringPtr("privacy", e.Privacy)
+	o.stringMap("replyTo", e.ReplyTo)
+	o.stringPtr("sentBy", e.SentBy)
+	appendObjects(&o, "participants", e.Participants, (*Participant).appendJSON)
+	o.stringPtr("requestStatus", e.RequestStatus)
+	o.boolPtr("mayInviteSelf", e.MayInviteSelf)
+	o.boolPtr("mayInviteOthers", e.MayInviteOthers)
+	o.boolPtr("hideAttendees", e.HideAttendees)
+	o.boolPtr("useDefaultAlerts", e.UseDefaultAlerts)
+	appendObjects(&o, "alerts", e.Alerts, (*Alert).appendJSON)
+	appendObjects(&o, "links", e.Links, (*Link).appendJSON)
+	appendObjects(&o, "locations", e.Locations, (*Location).appendJSON)
+	appendObjects(&o, "virtualLocations", e.VirtualLocations, (*VirtualLocation).appendJSON)
+	appendObjects(&o, "relatedTo", e.RelatedTo, (*Relation).appendJSON)
+	o.stringPtr("status", e.Status)
+	if len(e.LocalizedStrings) > 0 {
+		o.value("localizedStrings", e.LocalizedStrings)
+	}
+	o.extensions(e.Extensions, eventType)
+	return o.end()
+}
+
+// decodeJSON decodes the Event from JSON without reflection where possible
+func (e *Event) decodeJSON(data []byte) error {
+	if !json.Valid(data) {
+		return errNotSimple
+	}
+	var extensions map[string]interface{}
+	if err := decodeObject(data, eventType, e.decodeMember, &extensions); err != nil {
+		return err
+	}
+	e.Extensions = extensions
+	return nil
+}
+
+// decodeMember decodes a declared property of the Event
+func (e *Event) decodeMember(key string, value []byte) (bool, error) {
+	switch key {
+	case "@type":
+		return true, decodeString(value, &e.Type)
+	case "uid":
+		return true, decodeString(value, &e.UID)
+	case "created":
+		return true, decodeUTCDateTimePtr(value, &e.Created)
+	case "updated":
+		return true, decodeUTCDateTimePtr(value, &e.Updated)
+	case "sequence":
+		return true, decodeIntPtr(value, &e.Sequence)
+	case "method":
+		return true, decodeStringPtr(value, &e.Method)
+	case "prodId":
+		return true, decodeStringPtr(value, &e.ProdId)
+	case "title":
+		return true, decodeStringPtr(value, &e.Title)
+	case "description":
+		return true, decodeStringPtr(value, &e.Description)
+	case "descriptionContentType":
+		return true, decodeStringPtr(value, &e.DescriptionContentType)
+	case "showWithoutTime":
+		return true, decodeBoolPtr(value, &e.ShowWithoutTime)
+	case "locale":
+		return true, decodeStringPtr(value, &e.Locale)
+	case "localizations":
+		return true, decodePatches(value, &e.Localizations)
+	case "keywords":
+		return true, decodeBoolMap(value, &e.Keywords)
+	case "categories":
+		return true, decodeBoolMap(value, &e.Categories)
+	case "color":
+		return true, decodeStringPtr(value, &e.Color)
+	case "start":
+		return true, decodeLocalDateTimePtr(value, &e.Start)
+	case "duration":
+		return true, decodeStringPtr(value, &e.Duration)
+	case "timeZone":
+		return true, decodeStringPtr(value, &e.TimeZone)
+	case "timeZones":
+		return true, json.Unmarshal(value, &e.TimeZones)
+	case "recurrenceId":
+		return true, decodeLocalDateTimePtr(value, &e.RecurrenceId)
+	case "recurrenceIdTimeZone":
+		return true, decodeStringPtr(value, &e.RecurrenceIdTimeZone)
+	case "recurrenceRules":
+		return true, decodeArray(value, &e.RecurrenceRules, (*RecurrenceRule).decodeJSON)
+	case "recurrenceOverrides":
+		return true, decodePatches(value, &e.RecurrenceOverrides)
+	case "excludedRecurrenceRules":
+		return true, decodeArray(value, &e.ExcludedRecurrenceRules, (*RecurrenceRule).decodeJSON)
+	case "excluded":
+		return true, decodeBoolPtr(value, &e.Excluded)
+	case "priority":
+		return true, decodeIntPtr(value, &e.Priority)
+	case "freeBusyStatus":
+		return true, decodeStringPtr(value, &e.FreeBusyStatus)
+	case "privacy":
+		return true, decodeStringPtr(value, &e.Privacy)
+	case "replyTo":
+		return true, decodeStringMap(value, &e.ReplyTo)
+	case "sentBy":
+		return true, decodeStringPtr(value, &e.SentBy)
+	case "participants":
+		return true, decodeObjects(value, &e.Participants, (*Participant).decodeJSON)
+	case "requestStatus":
+		return true, decodeStringPtr(value, &e.RequestStatus)
+	case "mayInviteSelf":
+		return true, decodeBoolPtr(value, &e.MayInviteSelf)
+	case "mayInviteOthers":
+		return true, decodeBoolPtr(value, &e.MayInviteOthers)
+	case "hideAttendees":
+		return true, decodeBoolPtr(value, &e.HideAttendees)
+	case "useDefaultAlerts":
+		return true, decodeBoolPtr(value, &e.UseDefaultAlerts)
+	case "alerts":
+		return true, decodeObjects(value, &e.Alerts, (*Alert).decodeJSON)
+	case "links":
+		return true, decodeObjects(value, &e.Links, (*Link).decodeJSON)
+	case "locations":
+		return true, decodeObjects(value, &e.Locations, (*Location).decodeJSON)
+	case "virtualLocations":
+		return true, decodeObjects(value, &e.VirtualLocations, (*VirtualLocation).decodeJSON)
+	case "relatedTo":
+		return true, decodeObjects(value, &e.RelatedTo, (*Relation).decodeJSON)
+	case "status":
+		return true, decodeStringPtr(value, &e.Status)
+	case "localizedStrings":
+		return true, json.Unmarshal(value, &e.LocalizedStrings)
+	}
+	return false, nil
+}
+
+// appendJSON appends the Task as JSON
+func (t *Task) appendJSON(buf []byte) ([]byte, error) {
+	return t.appendFields(buf, nil)
+}
+
+// appendFields appends the Task as JSON with only the given top-level
+// properties, or all of them if fields is nil
+func (t *Task) appendFields(buf []byte, fields []string) ([]byte, error) {
+	o := objectEncoder{buf: buf, fields: fields}
+	o.begin()
+	o.str("@type", t.Type)
+	o.str("uid", t.UID)
+	o.utcDateTimePtr("created", t.Created)
+	o.utcDateTimePtr("updated", t.Updated)
+	o.intPtr("sequence", t.Sequence)
+	o.stringPtr("method", t.Method)
+	o.stringPtr("prodId", t.ProdId)
+	o.stringPtr("title", t.Title)
+	o.stringPtr("description", t.Description)
+	o.stringPtr("descriptionContentType", t.DescriptionContentType)
+	o.boolPtr("showWithoutTime", t.ShowWithoutTime)
+	o.stringPtr("locale", t.Locale)
+	if len(t.Localizations) > 0 {
+		o.value("localizations", t.Localizations)
+	}
+	o.boolMap("keywords", t.Keywords)
+	o.boolMap("categories", t.Categories)
+	o.stringPtr("color", t.Color)
+	o.localDateTimePtr("start", t.Start)
+	o.localDateTimePtr("due", t.Due)
+	o.stringPtr("estimatedDuration", t.EstimatedDuration)
+	o.stringPtr("timeZone", t.TimeZone)
+	if len(t.TimeZones) > 0 {
+		o.value("timeZones", t.TimeZones)
+	}
+	o.intPtr("percentComplete", t.PercentComplete)
+	o.stringPtr("progress", t.Progress)
+	o.utcDateTimePtr("progressUpdated", t.ProgressUpdated)
+	o.localDateTimePtr("recurrenceId", t.RecurrenceId)
+	o.stringPtr("recurrenceIdTimeZone", t.RecurrenceIdTimeZone)
+	appendArray(&o, "recurrenceRules", t.RecurrenceRules, (*RecurrenceRule).appendJSON)
+	if len(t.RecurrenceOverrides) > 0 {
+		o.value("recurrenceOverrides", t.RecurrenceOverrides)
+	}
+	appendArray(&o, "excludedRecurrenceRules", t.ExcludedRecurrenceRules, (*RecurrenceRule).appendJSON)
+	o.boolPtr("excluded", t.Excluded)
+	o.intPtr("priority", t.Priority)
+	o.stringPtr("freeBusyStatus", t.FreeBusyStatus)
+	o.stringPtr("privacy", t.Privacy)
+	o.stringMap("replyTo", t.ReplyTo)
+	o.stringPtr("sentBy", t.SentBy)
+	appendObjects(&o, "participants", t.Participants, (*Participant).appendJSON)
+	o.stringPtr("requestStatus", t.RequestStatus)
+	o.boolPtr("mayInviteSelf", t.MayInviteSelf)
+	o.boolPtr("mayInviteOthers", t.MayInviteOthers)
+	o.boolPtr("hideAttendees", t.HideAttendees)
+	o.boolPtr("useDefaultAlerts", t.UseDefaultAlerts)
+	appendObjects(&o, "alerts", t.Alerts, (*Alert).appendJSON)
+	appendObjects(&o, "links", t.Links, (*Link).appendJSON)
+	appendObjects(&o, "locations", t.Locations, (*Location).appendJSON)
+	appendObjects(&o, "virtualLocations", t.VirtualLocations, (*VirtualLocation).appendJSON)
+	appendObjects(&o, "relatedTo", t.RelatedTo, (*Relation).appendJSON)
+	o.stringPtr("status", t.Status)
+	if len(t.LocalizedStrings) > 0 {
+		o.value("localizedStrings", t.LocalizedStrings)
+	}
+	o.extensions(t.Extensions, taskType)
+	return o.end()
+}
+
+// decodeJSON decodes the Task from JSON without reflection where possible
+func (t *Task) decodeJSON(data []byte) error {
+	if !json.Valid(data) {
+		return errNotSimple
+	}
+	var extensions map[string]interface{}
+	if err := decodeObject(data, taskType, t.decodeMember, &extensions); err != nil {
+		return err
+	}
+	t.Extensions = extensions
+	return nil
+}
+
+// decodeMember decodes a declared property of the Task
+func (t *Task) decodeMember(key string, value []byte) (bool, error) {
+	switch key {
+	case "@type":
+		return true, decodeString(value, &t.Type)
+	case "uid":
+		return true, decodeString(value, &t.UID)
+	case "created":
+		return true, decodeUTCDateTimePtr(value, &t.Created)
+	case "updated":
+		return true, decodeUTCDateTimePtr(value, &t.Updated)
+	case "sequence":
+		return true, decodeIntPtr(value, &t.Sequence)
+	case "method":
+		return true, decodeStringPtr(value, &t.Method)
+	case "prodId":
+		return true, decodeStringPtr(value, &t.ProdId)
+	case "title":
+		return true, decodeStringPtr(value, &t.Title)
+	case "description":
+		return true, decodeStringPtr(value, &t.Description)
+	case "descriptionContentType":
+		return true, decodeStringPtr(value, &t.DescriptionContentType)
+	case "showWithoutTime":
+		return true, decodeBoolPtr(value, &t.ShowWithoutTime)
+	case "locale":
+		return true, decodeStringPtr(value, &t.Locale)
+	case "localizations":
+		return true, decodePatches(value, &t.Localizations)
+	case "keywords":
+		return true, decodeBoolMap(value, &t.Keywords)
+	case "categories":
+		return true, decodeBoolMap(value, &t.Categories)
+	case "color":
+		return true, decodeStringPtr(value, &t.Color)
+	case "start":
+		return true, decodeLocalDateTimePtr(value, &t.Start)
+	case "due":
+		return true, decodeLocalDateTimePtr(value, &t.Due)
+	case "estimatedDuration":
+		return true, decodeStringPtr(value, &t.EstimatedDuration)
+	case "timeZone":
+		return true, decodeStringPtr(value, &t.TimeZone)
+	case "timeZones":
+		return true, json.Unmarshal(value, &t.TimeZones)
+	case "percentComplete":
+		return true, decodeIntPtr(value, &t.PercentComplete)
+	case "progress":
+		return true, decodeStringPtr(value, &t.Progress)
+	case "progressUpdated":
+		return true, decodeUTCDateTimePtr(value, &t.ProgressUpdated)
+	case "recurrenceId":
+		return true, decodeLocalDateTimePtr(value, &t.RecurrenceId)
+	case "recurrenceIdTimeZone":
+		return true, decodeStringPtr(value, &t.RecurrenceIdTimeZone)
+	case "recurrenceRules":
+		return true, decodeArray(value, &t.RecurrenceRules, (*RecurrenceRule).decodeJSON)
+	case "recurrenceOverrides":
+		return true, decodePatches(value, &t.RecurrenceOverrides)
+	case "excludedRecurrenceRules":
+		return true, decodeArray(value, &t.ExcludedRecurrenceRules, (*RecurrenceRule).decodeJSON)
+	case "excluded":
+		return true, decodeBoolPtr(value, &t.Excluded)
+	case "priority":
+		return true, decodeIntPtr(value, &t.Priority)
+	case "freeBusyStatus":
+		return true, decodeStringPtr(value, &t.FreeBusyStatus)
+	case "privacy":
+		return true, decodeStringPtr(value, &t.Privacy)
+	case "replyTo":
+		return true, decodeStringMap(value, &t.ReplyTo)
+	case "sentBy":
+		return true, decodeStringPtr(value, &t.SentBy)
+	case "participants":
+		return true, decodeObjects(value, &t.Participants, (*Participant).decodeJSON)
+	case "requestStatus":
+		return true, decodeStringPtr(value, &t.RequestStatus)
+	case "mayInviteSelf":
+		return true, decodeBoolPtr(value, &t.MayInviteSelf)
+	case "mayInviteOthers":
+		return true, decodeBoolPtr(value, &t.MayInviteOthers)
+	case "hideAttendees":
+		return true, decodeBoolPtr(value, &t.HideAttendees)
+	case "useDefaultAlerts":
+		return true, decodeBoolPtr(value, &t.UseDefaultAlerts)
+	case "alerts":
+		return true, decodeObjects(value, &t.Alerts, (*Alert).decodeJSON)
+	case "links":
+		return true, decodeObjects(value, &t.Links, (*Link).decodeJSON)
+	case "locations":
+		return true, decodeObjects(value, &t.Locations, (*Location).decodeJSON)
+	case "virtualLocations":
+		return true, decodeObjects(value, &t.VirtualLocations, (*VirtualLocation).decodeJSON)
+	case "relatedTo":
+		return true, decodeObjects(value, &t.RelatedTo, (*Relation).decodeJSON)
+	case "status":
+		return true, decodeStringPtr(value, &t.Status)
+	case "localizedStrings":
+		return true, json.Unmarshal(value, &t.LocalizedStrings)
+	}
+	return false, nil
+}
+
+// appendJSON appends the Participant as JSON
+func (p *Participant) appendJSON(buf []byte) ([]byte, error) {
+	o := objectEncoder{buf: buf}
+	o.begin()
+	o.stringPtr("@type", p.Type)
+	o.stringPtr("name", p.Name)
+	o.stringPtr("email", p.Email)
+	o.stringMap("sendTo", p.SendTo)
+	o.stringPtr("kind", p.Kind)
+	o.boolMap("roles", p.Roles)
+	o.stringPtr("locationId", p.LocationId)
+	o.stringPtr("language", p.Language)
+	o.stringPtr("participationStatus", p.ParticipationStatus)
+	o.stringPtr("participationComment", p.ParticipationComment)
+	o.boolPtr("expectReply", p.ExpectReply)
+	o.stringPtr("scheduleAgent", p.ScheduleAgent)
+	o.boolPtr("scheduleForceSend", p.ScheduleForceSend)
+	o.intPtr("scheduleSequence", p.ScheduleSequence)
+	o.strings("scheduleStatus", p.ScheduleStatus)
+	o.utcDateTimePtr("scheduleUpdated", p.ScheduleUpdated)
+	o.stringPtr("sentBy", p.SentBy)
+	o.stringPtr("invitedBy", p.InvitedBy)
+	o.boolMap("delegatedTo", p.DelegatedTo)
+	o.boolMap("delegatedFrom", p.DelegatedFrom)
+	o.boolMap("memberOf", p.MemberOf)
+	appendObjects(&o, "links", p.Links, (*Link).appendJSON)
+	o.stringPtr("progress", p.Progress)
+	o.intPtr("percentComplete", p.PercentComplete)
+	o.utcDateTimePtr("progressUpdated", p.ProgressUpdated)
+	return o.end()
+}
+
+// decodeJSON decodes the Participant from JSON, which the caller has
+// checked to be valid. Like the other nested objects it has no extensions.
+func (p *Participant) decodeJSON(data []byte) error {
+	return decodeObject(data, participantType, p.decodeMember, nil)
+}
+
+// decodeMember decodes a declared property of the Participant
+func (p *Participant) decodeMember(key string, value []byte) (bool, error) {
+	switch key {
+	case "@type":
+		return true, decodeStringPtr(value, &p.Type)
+	case "name":
+		return true, decodeStringPtr(value, &p.Name)
+	case "email":
+		return true, decodeStringPtr(value, &p.Email)
+	case "sendTo":
+		return true, decodeStringMap(value, &p.SendTo)
+	case "kind":
+		return true, decodeStringPtr(value, &p.Kind)
+	case "roles":
+		return true, decodeBoolMap(value, &p.Roles)
+	case "locationId":
+		return true, decodeStringPtr(value, &p.LocationId)
+	case "language":
+		return true, decodeStringPtr(value, &p.Language)
+	case "participationStatus":
+		return true, decodeStringPtr(value, &p.ParticipationStatus)
+	case "participationComment":
+		return true, decodeStringPtr(value, &p.ParticipationComment)
+	case "expectReply":
+		return true, decodeBoolPtr(value, &p.ExpectReply)
+	case "scheduleAgent":
+		return true, decodeStringPtr(value, &p.ScheduleAgent)
+	case "scheduleForceSend":
+		return true, decodeBoolPtr(value, &p.ScheduleForceSend)
+	case "scheduleSequence":
+		return true, decodeIntPtr(value, &p.ScheduleSequence)
+	case "scheduleStatus":
+		return true, decodeStrings(value, &p.ScheduleStatus)
+	case "scheduleUpdated":
+		return true, decodeUTCDateTimePtr(value, &p.ScheduleUpdated)
+	case "sentBy":
+		return true, decodeStringPtr(value, &p.SentBy)
+	case "invitedBy":
+		return true, decodeStringPtr(value, &p.InvitedBy)
+	case "delegatedTo":
+		return true, decodeBoolMap(value, &p.DelegatedTo)
+	case "delegatedFrom":
+		return true, decodeBoolMap(value, &p.DelegatedFrom)
+	case "memberOf":
+		return true, decodeBoolMap(value, &p.MemberOf)
+	case "links":
+		return true, decodeObjects(value, &p.Links, (*Link).decodeJSON)
+	case "progress":
+		return true, decodeStringPtr(value, &p.Progress)
+	case "percentComplete":
+		return true, decodeIntPtr(value, &p.PercentComplete)
+	case "progressUpdated":
+		return true, decodeUTCDateTimePtr(value, &p.ProgressUpdated)
+	}
+	return false, nil
+}
+
+// appendJSON appends the Location as JSON
+func (l *Location) appendJSON(buf []byte) ([]byte, error) {
+	o := objectEncoder{buf: buf}
+	o.begin()
+	o.stringPtr("@type", l.Type)
+	o.stringPtr("name", l.Name)
+	o.stringPtr("description", l.Description)
+	o.boolMap("locationTypes", l.LocationTypes)
+	o.stringPtr("relativeTo", l.RelativeTo)
+	o.stringPtr("timeZone", l.TimeZone)
+	o.stringPtr("coordinates", l.Coordinates)
+	if l.Address != nil {
+		o.key("address")
+		o.buf, o.err = l.Address.appendJSON(o.buf)
+	}
+	appendObjects(&o, "links", l.Links, (*Link).appendJSON)
+	o.stringPtr("rel", l.Rel)
+	o.stringPtr("title", l.Title)
+	return o.end()
+}
+
+// decodeJSON decodes the Location from JSON
+func (l *Location) decodeJSON(data []byte) error {
+	return decodeObject(data, locationType, l.decodeMember, nil)
+}
+
+// decodeMember decodes a declared property of the Location
+func (l *Location) decodeMember(key string, value []byte) (bool, error) {
+	switch key {
+	case "@type":
+		return true, decodeStringPtr(value, &l.Type)
+	case "name":
+		return true, decodeStringPtr(value, &l.Name)
+	case "description":
+		return true, decodeStringPtr(value, &l.Description)
+	case "locationTypes":
+		return true, decodeBoolMap(value, &l.LocationTypes)
+	case "relativeTo":
+		return true, decodeStringPtr(value, &l.RelativeTo)
+	case "timeZone":
+		return true, decodeStringPtr(value, &l.TimeZone)
+	case "coordinates":
+		return true, decodeStringPtr(value, &l.Coordinates)
+	case "address":
+		if isNull(value) {
+			l.Address = nil
+			return true, nil
+		}
+		l.Address = new(Address)
+		return true, l.Address.UnmarshalJSON(value)
+	case "links":
+		return true, decodeObjects(value, &l.Links, (*Link).decodeJSON)
+	case "rel":
+		return true, decodeStringPtr(value, &l.Rel)
+	case "title":
+		return true, decodeStringPtr(value, &l.Title)
+	}
+	return false, nil
+}
+
+// appendJSON appends the VirtualLocation as JSON
+func (v *VirtualLocation) appendJSON(buf []byte) ([]byte, error) {
+	o := objectEncoder{buf: buf}
+	o.begin()
+	o.str("@type", v.Type)
+	o.stringPtr("name", v.Name)
+	o.stringPtr("description", v.Description)
+	o.str("uri", v.URI)
+	o.boolMap("features", v.Features)
+	return o.end()
+}
+
+// decodeJSON decodes the VirtualLocation from JSON
+func (v *VirtualLocation) decodeJSON(data []byte) error {
+	return decodeObject(data, virtualLocationType, v.decodeMember, nil)
+}
+
+// decodeMember decodes a declared property of the VirtualLocation
+func (v *VirtualLocation) decodeMember(key string, value []byte) (bool, error) {
+	switch key {
+	case "@type":
+		return true, decodeString(value, &v.Type)
+	case "name":
+		return true, decodeStringPtr(value, &v.Name)
+	case "description":
+		return true, decodeStringPtr(value, &v.Description)
+	case "uri":
+		return true, decodeString(value, &v.URI)
+	case "features":
+		return true, decodeBoolMap(value, &v.Features)
+	}
+	return false, nil
+}
+
+// appendJSON appends the Link as JSON
+func (l *Link) appendJSON(buf []byte) ([]byte, error) {
+	o := objectEncoder{buf: buf}
+	o.begin()
+	o.stringPtr("@type", l.Type)
+	o.str("href", l.Href)
+	o.stringPtr("cid", l.Cid)
+	o.stringPtr("contentType", l.ContentType)
+	o.intPtr("size", l.Size)
+	o.stringPtr("rel", l.Rel)
+	o.stringPtr("display", l.Display)
+	o.stringPtr("title", l.Title)
+	return o.end()
+}
+
+// decodeJSON decodes the Link from JSON
+func (l *Link) decodeJSON(data []byte) error {
+	return decodeObject(data, linkType, l.decodeMember, nil)
+}
+
+// decodeMember decodes a declared property of the Link
+func (l *Link) decodeMember(key string, value []byte) (bool, error) {
+	switch key {
+	case "@type":
+		return true, decodeStringPtr(value, &l.Type)
+	case "href":
+		return true, decodeString(value, &l.Href)
+	case "cid":
+		return true, decodeStringPtr(value, &l.Cid)
+	case "contentType":
+		return true, decodeStringPtr(value, &l.ContentType)
+	case "size":
+		return true, decodeIntPtr(value, &l.Size)
+	case "rel":
+		return true, decodeStringPtr(value, &l.Rel)
+	case "display":
+		return true, decodeStringPtr(value, &l.Display)
+	case "title":
+		return true, decodeStringPtr(value, &l.Title)
+	}
+	return false, nil
+}
+
+// appendJSON appends the Relation as JSON
+func (r *Relation) appendJSON(buf []byte) ([]byte, error) {
+	o := objectEncoder{buf: buf}
+	o.begin()
+	o.str("@type", r.Type)
+	o.boolMap("relation", r.Relation)
+	return o.end()
+}
+
+// decodeJSON decodes the Relation from JSON
+func (r *Relation) decodeJSON(data []byte) error {
+	return decodeObject(data, relationType, r.decodeMember, nil)
+}
+
+// decodeMember decodes a declared property of the Relation
+func (r *Relation) decodeMember(key string, value []byte) (bool, error) {
+	switch key {
+	case "@type":
+		return true, decodeString(value, &r.Type)
+	case "relation":
+		return true, decodeBoolMap(value, &r.Relation)
+	}
+	return false, nil
+}
+
+// appendJSON appends the RecurrenceRule as JSON
+func (r *RecurrenceRule) appendJSON(buf []byte) ([]byte, error) {
+	o := objectEncoder{buf: buf}
+	o.begin()
+	o.str("@type", r.Type)
+	o.str("frequency", r.Frequency)
+	o.intPtr("interval", r.Interval)
+	o.stringPtr("rscale", r.RScale)
+	o.stringPtr("skip", r.Skip)
+	o.intPtr("firstDayOfWeek", r.FirstDayOfWeek)
+	appendArray(&o, "byDay", r.ByDay, (*NDay).appendJSON)
+	o.ints("byMonthDay", r.ByMonthDay)
+	o.strings("byMonth", r.ByMonth)
+	o.ints("byYearDay", r.ByYearDay)
+	o.ints("byWeekNo", r.ByWeekNo)
+	o.ints("byHour", r.ByHour)
+	o.ints("byMinute", r.ByMinute)
+	o.ints("bySecond", r.BySecond)
+	o.ints("bySetPos", r.BySetPos)
+	o.intPtr("count", r.Count)
+	o.localDateTimePtr("until", r.Until)
+	return o.end()
+}
+
+// decodeJSON decodes the RecurrenceRule from JSON
+func (r *RecurrenceRule) decodeJSON(data []byte) error {
+	return decodeObject(data, recurrenceRuleType, r.decodeMember, nil)
+}
+
+// decodeMember decodes a declared property of the RecurrenceRule
+func (r *RecurrenceRule) decodeMember(key string, value []byte) (bool, error) {
+	switch key {
+	case "@type":
+		return true, decodeString(value, &r.Type)
+	case "frequency":
+		return true, decodeString(value, &r.Frequency)
+	case "interval":
+		return true, decodeIntPtr(value, &r.Interval)
+	case "rscale":
+		return true, decodeStringPtr(value, &r.RScale)
+	case "skip":
+		return true, decodeStringPtr(value, &r.Skip)
+	case "firstDayOfWeek":
+		return true, decodeIntPtr(value, &r.FirstDayOfWeek)
+	case "byDay":
+		return true, decodeArray(value, &r.ByDay, (*NDay).decodeJSON)
+	case "byMonthDay":
+		return true, decodeInts(value, &r.ByMonthDay)
+	case "byMonth":
+		return true, decodeStrings(value, &r.ByMonth)
+	case "byYearDay":
+		return true, decodeInts(value, &r.ByYearDay)
+	case "byWeekNo":
+		return true, decodeInts(value, &r.ByWeekNo)
+	case "byHour":
+		return true, decodeInts(value, &r.ByHour)
+	case "byMinute":
+		return true, decodeInts(value, &r.ByMinute)
+	case "bySecond":
+		return true, decodeInts(value, &r.BySecond)
+	case "bySetPos":
+		return true, decodeInts(value, &r.BySetPos)
+	case "count":
+		return true, decodeIntPtr(value, &r.Count)
+	case "until":
+		return true, decodeLocalDateTimePtr(value, &r.Until)
+	}
+	return false, nil
+}
+
+// appendJSON appends the NDay as JSON
+func (n *NDay) appendJSON(buf []byte) ([]byte, error) {
+	o := objectEncoder{buf: buf}
+	o.begin()
+	o.str("day", n.Day)
+	o.intPtr("nthOfPeriod", n.NthOfPeriod)
+	return o.end()
+}
+
+// decodeJSON decodes the NDay from JSON
+func (n *NDay) decodeJSON(data []byte) error {
+	return decodeObject(data, nDayType, n.decodeMember, nil)
+}
+
+// decodeMember decodes a declared property of the NDay
+func (n *NDay) decodeMember(key string, value []byte) (bool, error) {
+	switch key {
+	case "day":
+		return true, decodeString(value, &n.Day)
+	case "nthOfPeriod":
+		return true, decodeIntPtr(value, &n.NthOfPeriod)
+	}
+	return false, nil
+}
+
+// appendJSON appends the Alert as JSON. Only offset triggers have a fast
+// path; the others marshal themselves.
+func (a *Alert) appendJSON(buf []byte) ([]byte, error) {
+	o := objectEncoder{buf: buf}
+	o.begin()
+	o.str("@type", a.Type)
+	if trigger, ok := a.Trigger.(*OffsetTrigger); ok && trigger != nil {
+		o.key("trigger")
+		o.buf = trigger.appendJSON(o.buf)
+	} else if a.Trigger != nil {
+		o.value("trigger", a.Trigger)
+	}
+	o.utcDateTimePtr("acknowledged", a.Acknowledged)
+	appendObjects(&o, "relatedTo", a.RelatedTo, (*Relation).appendJSON)
+	o.stringPtr("action", a.Action)
+	return o.end()
+}
+
+// decodeJSON decodes the Alert from JSON like its UnmarshalJSON
+func (a *Alert) decodeJSON(data []byte) error {
+	a.Trigger = nil
+	return decodeObject(data, alertType, a.decodeMember, nil)
+}
+
+// decodeMember decodes a declared property of the Alert
+func (a *Alert) decodeMember(key string, value []byte) (bool, error) {
+	switch key {
+	case "@type":
+		return true, decodeString(value, &a.Type)
+	case "trigger":
+		return true, a.decodeTrigger(value)
+	case "acknowledged":
+		return true, decodeUTCDateTimePtr(value, &a.Acknowledged)
+	case "relatedTo":
+		return true, decodeObjects(value, &a.RelatedTo, (*Relation).decodeJSON)
+	case "action":
+		return true, decodeStringPtr(value, &a.Action)
+	}
+	return false, nil
+}
+
+// decodeTrigger decodes offset triggers directly and leaves the others to
+// decodeTrigger
+func (a *Alert) decodeTrigger(value []byte) error {
+	if isNull(value) {
+		a.Trigger = nil
+		return nil
+	}
+	offset := true
+	err := scanObject(value, func(key string, value []byte) error {
+		switch key {
+		case "@type":
+			s, ok := simpleString(value)
+			offset = ok && s == "OffsetTrigger"
+		case "when":
+			offset = false
+		}
+		return nil
+	})
+	if err != nil || !offset {
+		trigger, err := decodeTrigger(value)
+		if err != nil {
+			return err
+		}
+		a.Trigger = trigger
+		return nil
+	}
+	trigger := &OffsetTrigger{}
+	if err := decodeObject(value, offsetTriggerType, trigger.decodeMember, nil); err != nil {
+		return err
+	}
+	a.Trigger = trigger
+	return nil
+}
+
+// appendJSON appends the OffsetTrigger as JSON
+func (t *OffsetTrigger) appendJSON(buf []byte) []byte {
+	o := objectEncoder{buf: buf}
+	o.begin()
+	o.str("@type", t.Type)
+	o.str("offset", t.Offset)
+	o.stringPtr("relativeTo", t.RelativeTo)
+	return append(o.buf, '}')
+}
+
+// decodeMember decodes a declared property of the OffsetTrigger
+func (t *OffsetTrigger) decodeMember(key string, value []byte) (bool, error) {
+	switch key {
+	case "@type":
+		return true, decodeString(value, &t.Type)
+	case "offset":
+		return true, decodeString(value, &t.Offset)
+	case "relativeTo":
+		return true, decodeStringPtr(value, &t.RelativeTo)
+	}
+	return false, nil
+}
@@ -0,0 +1,470 @@
+package jscal
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// benchmarkEvent is a typical scheduled meeting, with a vendor extension
+var benchmarkEvent = []byte(`{
+  "@type": "Event",
+  "uid": "team-meeting-weekly-2020",
+  "created": "2020-01-06T10:30:00Z",
+  "updated": "2020-01-07T14:00:00Z",
+  "sequence": 3,
+  "prodId": "-//Example Corp//Calendar 1.0//EN",
+  "title": "FooBar team meeting",
+  "description": "Weekly sync <agenda & notes>",
+  "start": "2020-01-08T09:00:00",
+  "timeZone": "Africa/Johannesburg",
+  "duration": "PT1H",
+  "categories": {"work": true, "meetings": true},
+  "keywords": {"sync": true},
+  "locations": {
+    "l1": {"@type": "Location", "name": "Room 4B", "coordinates": "geo:-26.2041,28.0473"}
+  },
+  "virtualLocations": {
+    "0": {"@type": "VirtualLocation", "name": "ChatMe meeting room", "uri": "https://chatme.example.com?id=1234567&pw=a8a24627b63d"}
+  },
+  "recurrenceRules": [{"@type": "RecurrenceRule", "frequency": "weekly", "byDay": [{"@type": "NDay", "day": "we"}]}],
+  "replyTo": {"imip": "mailto:f245f875-7f63-4a5e-a2c8@schedule.example.com"},
+  "participants": {
+    "dG9tQGZvb2Jhci5xlLmNvbQ": {
+      "@type": "Participant",
+      "name": "Tom Tool",
+      "email": "tom@foobar.example.com",
+      "sendTo": {"imip": "mailto:tom@calendar.example.com"},
+      "participationStatus": "accepted",
+      "expectReply": true,
+      "scheduleSequence": 2,
+      "scheduleUpdated": "2020-01-07T14:00:00Z",
+      "roles": {"attendee": true}
+    },
+    "em9lQGZvb2GFtcGxlLmNvbQ": {
+      "@type": "Participant",
+      "name": "Zoe Zelda",
+      "email": "zoe@foobar.example.com",
+      "sendTo": {"imip": "mailto:zoe@foobar.example.com"},
+      "participationStatus": "accepted",
+      "roles": {"owner": true, "attendee": true, "chair": true}
+    }
+  },
+  "alerts": {
+    "a1": {"@type": "Alert", "trigger": {"@type": "OffsetTrigger", "offset": "-PT15M"}, "action": "display"}
+  },
+  "recurrenceOverrides": {
+    "2020-03-04T09:00:00": {"participants/dG9tQGZvb2Jhci5xlLmNvbQ/participationStatus": "declined"}
+  },
+  "example.com:room": {"building": "HQ", "floor": 4}
+}`)
+
+// benchmarkTask is a task with progress and an estimate
+var benchmarkTask = []byte(`{
+  "@type": "Task",
+  "uid": "write-report",
+  "created": "2020-01-06T10:30:00Z",
+  "updated": "2020-01-07T14:00:00Z",
+  "title": "Write the quarterly report",
+  "due": "2020-01-10T17:00:00",
+  "timeZone": "Europe/Berlin",
+  "estimatedDuration": "PT4H",
+  "percentComplete": 40,
+  "progress": "in-process",
+  "priority": 2,
+  "categories": {"work": true},
+  "participants": {
+    "p1": {"@type": "Participant", "name": "Ann", "email": "ann@example.com", "roles": {"owner": true}}
+  },
+  "example.com:cost-center": "4711"
+}`)
+
+// The reference implementations are what the encoding/json reflection path
+// produces; the fast paths must match them exactly
+
+func referenceMarshalEvent(e *Event) ([]byte, error) {
+	type Alias Event
+	data, err := json.Marshal((*Alias)(e))
+	if err != nil {
+		return nil, err
+	}
+	return appendExtensions(data, e.Extensions, reflect.TypeOf(Alias{}))
+}
+
+func referenceUnmarshalEvent(data []byte, e *Event) error {
+	type Alias Event
+	if err := json.Unmarshal(data, (*Alias)(e)); err != nil {
+		return err
+	}
+	extensions, err := extractExtensions(data, reflect.TypeOf(Alias{}))
+	if err != nil {
+		return err
+	}
+	e.Extensions = extensions
+	return nil
+}
+
+func referenceMarshalTask(t *Task) ([]byte, error) {
+	type Alias Task
+	data, err := json.Marshal((*Alias)(t))
+	if err != nil {
+		return nil, err
+	}
+	return appendExtensions(data, t.Extensions, reflect.TypeOf(Alias{}))
+}
+
+func referenceUnmarshalTask(data []byte, t *Task) error {
+	type Alias Task
+	if err := json.Unmarshal(data, (*Alias)(t)); err != nil {
+		return err
+	}
+	extensions, err := extractExtensions(data, reflect.TypeOf(Alias{}))
+	if err != nil {
+		return err
+	}
+	t.Extensions = extensions
+	return nil
+}
+
+func BenchmarkEventMarshal(b *testing.B) {
+	var event Event
+	if err := event.UnmarshalJSON(benchmarkEvent); err != nil {
+		b.Fatal(err)
+	}
+	b.Run("reflection", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := referenceMarshalEvent(&event); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("jscal", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := event.MarshalJSON(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkEventUnmarshal(b *testing.B) {
+	b.Run("reflection", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var event Event
+			if err := referenceUnmarshalEvent(benchmarkEvent, &event); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("jscal", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var event Event
+			if err := event.UnmarshalJSON(benchmarkEvent); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkTaskMarshal(b *testing.B) {
+	var task Task
+	if err := task.UnmarshalJSON(benchmarkTask); err != nil {
+		b.Fatal(err)
+	}
+	b.Run("reflection", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := referenceMarshalTask(&task); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("jscal", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := task.MarshalJSON(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkTaskUnmarshal(b *testing.B) {
+	b.Run("reflection", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var task Task
+			if err := referenceUnmarshalTask(benchmarkTask, &task); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("jscal", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var task Task
+			if err := task.UnmarshalJSON(benchmarkTask); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestMarshalMatchesReflection(t *testing.T) {
+	files, _ := filepath.Glob("testdata/rfc8984/examples/*.json")
+	inputs := [][]byte{benchmarkEvent, benchmarkTask}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		inputs = append(inputs, data)
+	}
+
+	for i, data := range inputs {
+		var head struct {
+			Type string `json:"@type"`
+		}
+		if err := json.Unmarshal(data, &head); err != nil {
+			t.Fatal(err)
+		}
+		switch head.Type {
+		case "Event":
+			var got, want Event
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("input %d: %v", i, err)
+			}
+			if err := referenceUnmarshalEvent(data, &want); err != nil {
+				t.Fatalf("input %d: %v", i, err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("input %d: Expected %+v, got %+v", i, want, got)
+			}
+			gotJSON, err := json.Marshal(&got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantJSON, _ := referenceMarshalEvent(&want)
+			if string(gotJSON) != string(wantJSON) {
+				t.Errorf("input %d: Expected\n%s\ngot\n%s", i, wantJSON, gotJSON)
+			}
+		case "Task":
+			var got, want Task
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("input %d: %v", i, err)
+			}
+			if err := referenceUnmarshalTask(data, &want); err != nil {
+				t.Fatalf("input %d: %v", i, err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("input %d: Expected %+v, got %+v", i, want, got)
+			}
+			gotJSON, err := json.Marshal(&got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantJSON, _ := referenceMarshalTask(&want)
+			if string(gotJSON) != string(wantJSON) {
+				t.Errorf("input %d: Expected\n%s\ngot\n%s", i, wantJSON, gotJSON)
+			}
+		}
+	}
+}
+
+func TestUnmarshalEdgeCasesMatchReflection(t *testing.T) {
+	inputs := []string{
+		`{"@type": "Event", "uid": "esc\"apedé", "title": "café <b>", "description": "line\nbreak"}`,
+		"{\"@type\": \"Event\", \"uid\": \"bad\xffutf8\"}",
+		`{"@type": "Event", "UID": "folded", "Title": "Case", "example.com:x": [1, {"a": null}]}`,
+		`{"@type": "Event", "uid": "nulls", "title": null, "categories": null, "participants": {"p": null}}`,
+		`{"@type": "Event", "uid": "dupes", "title": "a", "title": "b", "categories": {"x": true}, "categories": {"y": false}}`,
+		`{"@type": "Event", "uid": "numbers", "sequence": -3, "priority": 1e2}`,
+		`{"@type": "Event", "uid": "types", "title": 5}`,
+		`{"@type": "Event", "uid": "nested", "participants": {"p": {"name": 5}}}`,
+		`{"@type": "Event", "uid": "maps", "categories": {"x": null}, "replyTo": {"imip": 1}}`,
+		`{"@type": "Event", "uid": "times", "created": "2020-01-01T00:00:00+01:00", "start": "2020-01-01T09:00:00"}`,
+		`{"@type": "Event", "uid": "badtime", "created": "yesterday"}`,
+		`{"@type": "Event", "uid": "part", "participants": {"p": {"NAME": "Folded", "roles": {"owner": true}, "scheduleStatus": ["2.0"], "x": 1}}}`,
+		`{"@type": "Event", "uid": "rules", "recurrenceRules": [{"@type": "RecurrenceRule", "frequency": "monthly", "byDay": [{"day": "mo", "nthOfPeriod": -1}], "byMonthDay": [1, -1], "byHour": [], "byMonth": ["1L"], "until": "2021-01-01T00:00:00"}], "excludedRecurrenceRules": []}`,
+		`{"@type": "Event", "uid": "badrule", "recurrenceRules": [{"frequency": "daily", "byHour": [1.5]}]}`,
+		`{"@type": "Event", "uid": "nullrule", "recurrenceRules": [null], "relatedTo": {"r": {"@type": "Relation", "relation": {"parent": true}}}}`,
+		`{"@type": "Event", "uid": "alerts", "alerts": {"a": {"@type": "Alert", "TRIGGER": {"offset": "-PT5M", "RelativeTo": "end"}}, "b": {"trigger": {"@type": "AbsoluteTrigger", "when": "2020-01-01T10:00:00Z"}}, "c": {"trigger": {"when": "2020-01-01T10:00:00Z"}}, "d": {"trigger": {"@type": "X-Custom", "x": 1}}, "e": {"trigger": null, "acknowledged": "2020-01-01T10:00:00Z"}, "f": null}}`,
+		`{"@type": "Event", "uid": "badtrigger", "alerts": {"a": {"trigger": {"@type": "OffsetTrigger", "offset": 5}}}}`,
+		`{"@type": "Event", "uid": "places", "locations": {"l": {"name": "Room", "links": {"k": {"href": "https://example.com", "size": 10}}, "x": true}}, "virtualLocations": {"v": {"@type": "VirtualLocation", "uri": "tel:123", "features": {"audio": true}}}, "links": {"n": null}}`,
+		`{"@type": "Event", "uid": "patches", "recurrenceOverrides": {"a": {"title": "x", "n": [1.5, -0, 2e3, {"k": null}], "esc": "\u00e9\n"}, "b": null, "c": {}}, "localizations": {"de": {"title": "Treffen"}}}`,
+		`{"@type": "Event", "uid": "badpatch", "recurrenceOverrides": {"a": 5}}`,
+		`{"@type": "Event", "uid": "bignum", "example.com:n": 1e400}`,
+		`{"@type": "Event", "uid": "ext", "example.com:x": {"b": [true, false, null, "s", 0.1], "a": -12}, "Example.com:x": "case"}`,
+		`{}`,
+		`null`,
+		`"string"`,
+		`[1, 2]`,
+		`{"@type": "Event",`,
+	}
+
+	for _, input := range inputs {
+		var got, want Event
+		gotErr := json.Unmarshal([]byte(input), &got)
+		wantErr := referenceUnmarshalEvent([]byte(input), &want)
+		if (gotErr == nil) != (wantErr == nil) || (gotErr != nil && gotErr.Error() != wantErr.Error()) {
+			t.Errorf("%s: Expected error %v, got %v", input, wantErr, gotErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: Expected %+v, got %+v", input, want, got)
+		}
+		if gotErr != nil {
+			continue
+		}
+		gotJSON, gotErr := json.Marshal(&got)
+		wantJSON, wantErr := referenceMarshalEvent(&want)
+		if (gotErr == nil) != (wantErr == nil) || string(gotJSON) != string(wantJSON) {
+			t.Errorf("%s: Expected\n%s (%v)\ngot\n%s (%v)", input, wantJSON, wantErr, gotJSON, gotErr)
+		}
+	}
+}
+
+func TestParticipantMarshalMatchesReflection(t *testing.T) {
+	type alias Participant
+	var event Event
+	if err := event.UnmarshalJSON(benchmarkEvent); err != nil {
+		t.Fatal(err)
+	}
+	event.Participants["p3"] = &Participant{Name: String("Ünïcode & <tags>"), ScheduleStatus: []string{"2.0", "3.1"},
+		DelegatedTo: map[string]bool{"b": true, "a": false}, Links: map[string]*Link{"l": {Href: "https://example.com"}},
+		Progress: String(ProgressInProcess), PercentComplete: Int(40), ProgressUpdated: NewUTCDateTime(time.Time{})}
+
+	for id, p := range event.Participants {
+		got, err := json.Marshal(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, _ := json.Marshal((*alias)(p))
+		if string(got) != string(want) {
+			t.Errorf("%s: Expected\n%s\ngot\n%s", id, want, got)
+		}
+	}
+}
+
+func TestAppendJSONString(t *testing.T) {
+	tests := []string{"", "plain", `quo"te`, `back\slash`, "<a href='x'>&amp;</a>", "café ☕ 日本",
+		"tab\tnew\nline", "\x00\x1f", "bad\xff", "line sep ", "\U0001F600"}
+	for _, s := range tests {
+		want, _ := json.Marshal(s)
+		if got := appendJSONString([]byte("prefix"), s); string(got) != "prefix"+string(want) {
+			t.Errorf("%q: Expected %s, got %s", s, want, got[len("prefix"):])
+		}
+	}
+}
+
+func TestAppendValue(t *testing.T) {
+	tests := []interface{}{
+		nil, "s<>", true, 0.0, math.Copysign(0, -1), -5.0, 42.0, 1.5, 1e21, 1e-7, 123456789012345678.0,
+		map[string]interface{}{"b": []interface{}{1.0, "x", nil, map[string]interface{}{}}, "a": false},
+		map[string]interface{}(nil), []interface{}(nil), []interface{}{},
+		map[string]map[string]interface{}{"k": {"x/y": "z"}, "n": nil},
+		map[string]int{"typed": 1}, struct{ A int }{1},
+	}
+	for _, v := range tests {
+		want, _ := json.Marshal(v)
+		got, err := appendValue(nil, v)
+		if err != nil || string(got) != string(want) {
+			t.Errorf("%#v: Expected %s, got %s (%v)", v, want, got, err)
+		}
+	}
+	if _, err := appendValue(nil, math.Inf(1)); err == nil {
+		t.Error("Expected error for infinity")
+	}
+}
+
+func TestMarshalPartial(t *testing.T) {
+	var event Event
+	if err := json.Unmarshal(benchmarkEvent, &event); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := event.MarshalPartial([]string{"title", "start", "duration"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"title":"FooBar team meeting","start":"2020-01-08T09:00:00","duration":"PT1H"}`
+	if string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+
+	tests := [][]string{
+		nil,
+		{"uid", "participants", "example.com:room", "unknown"},
+		{"recurrenceOverrides", "alerts", "created"},
+	}
+	full, _ := toJSONObject(&event)
+	for _, fields := range tests {
+		data, err := event.MarshalPartial(fields)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got map[string]interface{}
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]interface{}{}
+		for _, field := range fields {
+			if v, ok := full[field]; ok {
+				want[field] = v
+			}
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%v: Expected %v, got %v", fields, want, got)
+		}
+	}
+
+	task := &Task{Type: "Task", UID: "t1", Title: String("Report"), Progress: String("completed")}
+	if data, _ := task.MarshalPartial([]string{"progress", "uid"}); string(data) != `{"uid":"t1","progress":"completed"}` {
+		t.Errorf("Expected uid and progress, got %s", data)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		event.MarshalPartial([]string{"title", "start", "duration"})
+	})
+	if allocs > 2 {
+		t.Errorf("Expected at most 2 allocations, got %v", allocs)
+	}
+}
+
+func TestEncoderUTCDateTime(t *testing.T) {
+	zone := time.FixedZone("", -5*3600)
+	tests := []time.Time{
+		time.Date(2020, 1, 8, 9, 0, 0, 0, time.UTC),
+		time.Date(2020, 1, 8, 9, 0, 0, 120000000, zone),
+		time.Date(1, 1, 1, 0, 0, 0, 1, time.UTC),
+		time.Date(12345, 1, 1, 0, 0, 0, 0, zone),
+	}
+	for _, tm := range tests {
+		o := objectEncoder{}
+		o.utcDateTimePtr("t", NewUTCDateTime(tm))
+		want, wantErr := json.Marshal(NewUTCDateTime(tm))
+		if (o.err == nil) != (wantErr == nil) {
+			t.Errorf("%v: Expected error %v, got %v", tm, wantErr, o.err)
+		} else if wantErr == nil && string(o.buf) != `"t":`+string(want) {
+			t.Errorf("%v: Expected %s, got %s", tm, want, o.buf)
+		}
+	}
+}
+
+func TestEncoderLocalDateTime(t *testing.T) {
+	zone := time.FixedZone("", -5*3600)
+	tests := []time.Time{
+		time.Date(2020, 1, 8, 9, 0, 0, 0, time.UTC),
+		time.Date(2020, 1, 8, 9, 0, 0, 120000000, zone),
+		time.Date(1, 1, 1, 0, 0, 0, 1, time.UTC),
+		time.Date(12345, 1, 1, 0, 0, 0, 0, zone),
+	}
+	for _, tm := range tests {
+		o := objectEncoder{}
+		o.localDateTimePtr("t", NewLocalDateTime(tm))
+		want, _ := json.Marshal(NewLocalDateTime(tm))
+		if got := string(o.buf); got != `"t":`+string(want) {
+			t.Errorf("%v: Expected %s, got %s", tm, want, got)
+		}
+	}
+}
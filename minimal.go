@@ -0,0 +1,223 @@
+package jscal
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Size limits of the MinimalEvent profile
+const (
+	MaxMinimalTitleLength = 64  // Title bytes, longer titles are truncated
+	MaxMinimalAlerts      = 4   // Alerts kept per event, earliest first
+	MaxMinimalEventSize   = 512 // Bytes of the compact JSON encoding
+)
+
+// MinimalEvent is the alarm-only profile of an Event for wearables and other
+// constrained devices: timing, title and display alerts, encoded with short
+// JSON keys
+type MinimalEvent struct {
+	UID      string         `json:"u"`
+	Title    string         `json:"t"`
+	Start    LocalDateTime  `json:"s"`
+	TimeZone string         `json:"z,omitempty"` // Empty for floating time
+	Duration string         `json:"d,omitempty"`
+	AllDay   bool           `json:"a,omitempty"`
+	Alerts   []MinimalAlert `json:"al,omitempty"`
+}
+
+// MinimalAlert is an offset alert of a MinimalEvent
+type MinimalAlert struct {
+	Offset     string `json:"o"`           // ISO 8601 signed duration
+	RelativeTo string `json:"r,omitempty"` // Empty for start, or "end"
+}
+
+// Minimize reduces the Event to the MinimalEvent profile. Acknowledged and
+// non-display alerts are dropped, absolute triggers become offsets from the
+// start (floating events drop them), the title falls back as in
+// EffectiveTitle and is truncated, and at most MaxMinimalAlerts alerts are kept.
+func (e *Event) Minimize(locale string) (*MinimalEvent, error) {
+	if e == nil {
+		return nil, fmt.Errorf("event is nil")
+	}
+	if e.Start == nil {
+		return nil, ValidationError{Field: "start", Message: "is required"}
+	}
+
+	m := &MinimalEvent{
+		UID:    e.UID,
+		Title:  truncateUTF8(e.EffectiveTitle(locale), MaxMinimalTitleLength),
+		Start:  *e.Start,
+		AllDay: e.IsAllDay(),
+	}
+	if e.TimeZone != nil {
+		m.TimeZone = *e.TimeZone
+	}
+	if e.Duration != nil && *e.Duration != "PT0S" {
+		m.Duration = *e.Duration
+	}
+
+	for _, id := range sortedKeys(e.Alerts) {
+		a := e.Alerts[id]
+		if a == nil || a.Trigger == nil || a.Acknowledged != nil {
+			continue
+		}
+		if a.Action != nil && *a.Action != AlertActionDisplay {
+			continue
+		}
+		var alert MinimalAlert
+		switch t := a.Trigger.(type) {
+		case *OffsetTrigger:
+			alert.Offset = t.Offset
+			if t.RelativeTo != nil && *t.RelativeTo == RelativeToEnd {
+				alert.RelativeTo = RelativeToEnd
+			}
+		case *AbsoluteTrigger:
+			// The profile only has offsets, so express the time relative to the start
+			offset, ok := e.offsetFromStart(t.When.Time())
+			if !ok {
+				continue
+			}
+			alert.Offset = offset
+		default:
+			continue
+		}
+		m.Alerts = append(m.Alerts, alert)
+	}
+
+	// Keep the alerts that fire first
+	sort.SliceStable(m.Alerts, func(i, j int) bool {
+		return m.Alerts[i].fireOffset(m.Duration) < m.Alerts[j].fireOffset(m.Duration)
+	})
+	if len(m.Alerts) > MaxMinimalAlerts {
+		m.Alerts = m.Alerts[:MaxMinimalAlerts]
+	}
+
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// offsetFromStart returns the offset of an instant from the event start,
+// which requires the start to be in a known time zone
+func (e *Event) offsetFromStart(when time.Time) (string, bool) {
+	if e.TimeZone == nil {
+		return "", false
+	}
+	loc, err := time.LoadLocation(*e.TimeZone)
+	if err != nil {
+		return "", false
+	}
+	return FormatDuration(when.Sub(e.Start.In(loc))), true
+}
+
+// fireOffset returns when the alert fires relative to the event start
+func (a MinimalAlert) fireOffset(duration string) float64 {
+	offset, err := parseISO8601Duration(a.Offset)
+	if err != nil {
+		return 0
+	}
+	if a.RelativeTo == RelativeToEnd && duration != "" {
+		if d, err := parseISO8601Duration(duration); err == nil {
+			offset += d
+		}
+	}
+	return offset.Seconds()
+}
+
+// Validate enforces the MinimalEvent profile, including its size limit
+func (m *MinimalEvent) Validate() error {
+	if m == nil {
+		return ValidationError{Field: "event", Message: "event is nil"}
+	}
+
+	var errors ValidationErrors
+	if m.UID == "" {
+		errors = append(errors, ValidationError{Field: "uid", Message: "is required"})
+	}
+	if m.Title == "" {
+		errors = append(errors, ValidationError{Field: "title", Message: "is required"})
+	} else if len(m.Title) > MaxMinimalTitleLength {
+		errors = append(errors, ValidationError{
+			Field:   "title",
+			Value:   m.Title,
+			Message: fmt.Sprintf("exceeds maximum length of %d characters", MaxMinimalTitleLength),
+		})
+	}
+	if m.Start.IsZero() {
+		errors = append(errors, ValidationError{Field: "start", Message: "is required"})
+	}
+	if m.TimeZone != "" && !timezonePattern.MatchString(m.TimeZone) {
+		errors = append(errors, ValidationError{Field: "timeZone", Value: m.TimeZone, Message: "invalid IANA timezone identifier"})
+	}
+	if m.Duration != "" && !isDuration(m.Duration) {
+		errors = append(errors, ValidationError{Field: "duration", Value: m.Duration, Message: "invalid ISO 8601 duration format"})
+	}
+
+	if len(m.Alerts) > MaxMinimalAlerts {
+		errors = append(errors, ValidationError{
+			Field:   "alerts",
+			Value:   len(m.Alerts),
+			Message: fmt.Sprintf("exceeds maximum of %d alerts", MaxMinimalAlerts),
+		})
+	}
+	for i, a := range m.Alerts {
+		if !isSignedDuration(a.Offset) {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("alerts[%d].offset", i),
+				Value:   a.Offset,
+				Message: "invalid ISO 8601 duration format",
+			})
+		}
+		if a.RelativeTo != "" && a.RelativeTo != RelativeToEnd {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("alerts[%d].relativeTo", i),
+				Value:   a.RelativeTo,
+				Message: "must be empty or 'end'",
+			})
+		}
+	}
+
+	if len(errors) == 0 {
+		data, err := json.Marshal(m)
+		if err != nil {
+			return fmt.Errorf("failed to encode minimal event: %w", err)
+		}
+		if len(data) > MaxMinimalEventSize {
+			errors = append(errors, ValidationError{
+				Field:   "event",
+				Value:   len(data),
+				Message: fmt.Sprintf("encoded size of %d bytes exceeds maximum of %d", len(data), MaxMinimalEventSize),
+			})
+		}
+	}
+
+	if len(errors) > 0 {
+		return errors
+	}
+	return nil
+}
+
+// JSON returns the compact encoding of the MinimalEvent
+func (m *MinimalEvent) JSON() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// MinimizeAll reduces events to the MinimalEvent profile, skipping events
+// that have no start, and returns the compact JSON array
+func MinimizeAll(events []*Event, locale string) ([]byte, error) {
+	minimal := make([]*MinimalEvent, 0, len(events))
+	for _, e := range events {
+		if e == nil || e.Start == nil {
+			continue
+		}
+		m, err := e.Minimize(locale)
+		if err != nil {
+			return nil, fmt.Errorf("failed to minimize event %s: %w", e.UID, err)
+		}
+		minimal = append(minimal, m)
+	}
+	return json.Marshal(minimal)
+}
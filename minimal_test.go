@@ -0,0 +1,162 @@
+package jscal
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newOffsetAlert(offset string, relativeTo *string) *Alert {
+	return &Alert{
+		Type:    "Alert",
+		Trigger: &OffsetTrigger{Type: "OffsetTrigger", Offset: offset, RelativeTo: relativeTo},
+	}
+}
+
+func TestMinimize(t *testing.T) {
+	event := NewEvent("minimal-test", "Dentist")
+	event.Start = NewLocalDateTime(time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC))
+	event.Duration = String("PT1H")
+	event.TimeZone = String("Europe/Berlin")
+	event.Description = String("Bring insurance card")
+	event.AddParticipant("p1", NewParticipant("Ann", "ann@example.com"))
+	event.AddAlert("a", newOffsetAlert("-PT15M", nil))
+	event.AddAlert("b", newOffsetAlert("-P1D", nil))
+	event.AddAlert("c", newOffsetAlert("PT0S", String(RelativeToEnd)))
+	email := newOffsetAlert("-PT30M", nil)
+	email.Action = String(AlertActionEmail)
+	event.AddAlert("email", email)
+	acked := newOffsetAlert("-PT5M", nil)
+	acked.Acknowledged = NewUTCDateTime(time.Now())
+	event.AddAlert("acked", acked)
+
+	m, err := event.Minimize("")
+	if err != nil {
+		t.Fatalf("Minimize failed: %v", err)
+	}
+
+	if m.UID != "minimal-test" || m.Title != "Dentist" || m.TimeZone != "Europe/Berlin" || m.Duration != "PT1H" {
+		t.Errorf("Unexpected minimal event: %+v", m)
+	}
+	if len(m.Alerts) != 3 {
+		t.Fatalf("Expected 3 display alerts, got %+v", m.Alerts)
+	}
+	if m.Alerts[0].Offset != "-P1D" || m.Alerts[1].Offset != "-PT15M" || m.Alerts[2].RelativeTo != RelativeToEnd {
+		t.Errorf("Expected alerts ordered by firing time, got %+v", m.Alerts)
+	}
+
+	data, err := m.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "Bring insurance") || strings.Contains(string(data), "ann@example.com") {
+		t.Errorf("Expected description and participants to be stripped, got %s", data)
+	}
+	if len(data) > MaxMinimalEventSize {
+		t.Errorf("Expected at most %d bytes, got %d", MaxMinimalEventSize, len(data))
+	}
+
+	var decoded MinimalEvent
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to decode compact JSON: %v", err)
+	}
+	if decoded.Start.String() != "2025-03-01T09:00:00" {
+		t.Errorf("Expected start to round-trip, got %s", decoded.Start.String())
+	}
+}
+
+func TestMinimizeLimits(t *testing.T) {
+	event := NewEvent("minimal-test", strings.Repeat("ü", MaxMinimalTitleLength))
+	event.Start = NewLocalDateTime(time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC))
+	for _, id := range []string{"a", "b", "c", "d", "e", "f"} {
+		event.AddAlert(id, newOffsetAlert("-PT1M", nil))
+	}
+
+	m, err := event.Minimize("")
+	if err != nil {
+		t.Fatalf("Minimize failed: %v", err)
+	}
+	if len(m.Title) > MaxMinimalTitleLength {
+		t.Errorf("Expected title truncated to %d bytes, got %d", MaxMinimalTitleLength, len(m.Title))
+	}
+	if len(m.Alerts) != MaxMinimalAlerts {
+		t.Errorf("Expected %d alerts, got %d", MaxMinimalAlerts, len(m.Alerts))
+	}
+}
+
+func TestMinimizeFallbackTitle(t *testing.T) {
+	event := NewEvent("minimal-test", "")
+	event.Start = NewLocalDateTime(time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC))
+	event.Title = nil
+
+	m, err := event.Minimize("")
+	if err != nil {
+		t.Fatalf("Minimize failed: %v", err)
+	}
+	if m.Title != NoTitle {
+		t.Errorf("Expected %q, got %q", NoTitle, m.Title)
+	}
+}
+
+func TestMinimalEventValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		event   *MinimalEvent
+		wantErr string
+	}{
+		{
+			name:    "missing start",
+			event:   &MinimalEvent{UID: "a", Title: "A"},
+			wantErr: "start is required",
+		},
+		{
+			name: "bad relativeTo",
+			event: &MinimalEvent{
+				UID:    "a",
+				Title:  "A",
+				Start:  LocalDateTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+				Alerts: []MinimalAlert{{Offset: "-PT5M", RelativeTo: "start"}},
+			},
+			wantErr: "must be empty or 'end'",
+		},
+		{
+			name: "encoded size",
+			event: &MinimalEvent{
+				UID:      strings.Repeat("u", 255),
+				Title:    "A",
+				Start:    LocalDateTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+				TimeZone: strings.Repeat("Z", 300),
+			},
+			wantErr: "exceeds maximum of 512",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.event.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestMinimizeAll(t *testing.T) {
+	event := NewEvent("minimal-test", "Dentist")
+	event.Start = NewLocalDateTime(time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC))
+	withoutStart := NewEvent("no-start", "Floating idea")
+	withoutStart.Start = nil
+	data, err := MinimizeAll([]*Event{event, withoutStart}, "")
+	if err != nil {
+		t.Fatalf("MinimizeAll failed: %v", err)
+	}
+
+	var decoded []MinimalEvent
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 1 || decoded[0].UID != "minimal-test" {
+		t.Errorf("Expected only the timed event, got %+v", decoded)
+	}
+}
@@ -0,0 +1,323 @@
+package jscal
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// weekDurationPattern matches ISO 8601 durations with a week component, which
+// many iCalendar and JSCalendar consumers reject when combined with days
+var weekDurationPattern = regexp.MustCompile(`^(-?)P(\d+)W(?:(\d+)D)?(T.*)?$`)
+
+// NormalizeOptions configures Normalize. The zero value applies every fix.
+type NormalizeOptions struct {
+	// SkipDedupeParticipants keeps participants that share an email address
+	SkipDedupeParticipants bool
+
+	// SkipTrim keeps title and description values that exceed the length limits
+	SkipTrim bool
+}
+
+// NormalizeChange describes one modification made by Normalize
+type NormalizeChange struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// String formats the change as "field: message"
+func (c NormalizeChange) String() string {
+	return fmt.Sprintf("%s: %s", c.Field, c.Message)
+}
+
+// Normalize fixes common interoperability issues in place and returns what it
+// changed. It only rewrites values into their canonical form and never drops
+// information other than trimming over-long text, which can be disabled.
+func (e *Event) Normalize(opts NormalizeOptions) []NormalizeChange {
+	if e == nil {
+		return nil
+	}
+
+	var changes []NormalizeChange
+	change := func(field, format string, args ...interface{}) {
+		changes = append(changes, NormalizeChange{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if e.Type == "" {
+		e.Type = "Event"
+		change("@type", "set to 'Event'")
+	}
+
+	// Enumerated values are case-sensitive in RFC 8984
+	for _, field := range []struct {
+		name  string
+		value *string
+	}{
+		{"status", e.Status},
+		{"privacy", e.Privacy},
+		{"freeBusyStatus", e.FreeBusyStatus},
+	} {
+		if field.value != nil {
+			if lower := strings.ToLower(*field.value); lower != *field.value {
+				change(field.name, "lowercased %q to %q", *field.value, lower)
+				*field.value = lower
+			}
+		}
+	}
+
+	if e.Locale != nil {
+		if tag, err := CanonicalLanguageTag(*e.Locale); err == nil && tag != *e.Locale {
+			change("locale", "canonicalized %q to %s", *e.Locale, tag)
+			e.Locale = &tag
+		}
+	}
+	for _, key := range sortedKeys(e.Localizations) {
+		tag, err := CanonicalLanguageTag(key)
+		if _, exists := e.Localizations[tag]; err != nil || exists {
+			continue
+		}
+		change(fmt.Sprintf("localizations[%s]", key), "canonicalized %q to %s", key, tag)
+		e.Localizations[tag] = e.Localizations[key]
+		delete(e.Localizations, key)
+	}
+
+	if e.Color != nil {
+		if hex, err := NormalizeColor(*e.Color); err == nil && hex != *e.Color {
+			change("color", "converted %q to %s", *e.Color, hex)
+			e.Color = &hex
+		}
+	}
+
+	if e.Duration != nil {
+		if days, ok := convertWeekDuration(*e.Duration); ok {
+			change("duration", "converted %s to %s", *e.Duration, days)
+			e.Duration = &days
+		}
+	}
+
+	if !opts.SkipDedupeParticipants {
+		changes = append(changes, dedupeParticipants(e.Participants)...)
+	}
+
+	for _, id := range sortedKeys(e.Participants) {
+		p := e.Participants[id]
+		if p == nil {
+			continue
+		}
+		prefix := fmt.Sprintf("participants[%s]", id)
+		if p.Type == nil || *p.Type == "" {
+			p.Type = String("Participant")
+			change(prefix+".@type", "set to 'Participant'")
+		}
+		if p.Language != nil {
+			if tag, err := CanonicalLanguageTag(*p.Language); err == nil && tag != *p.Language {
+				change(prefix+".language", "canonicalized %q to %s", *p.Language, tag)
+				p.Language = &tag
+			}
+		}
+		for _, field := range []struct {
+			name  string
+			value *string
+		}{{"email", p.Email}, {"sentBy", p.SentBy}} {
+			if field.value == nil {
+				continue
+			}
+			if address, err := NormalizeEmail(*field.value, EmailOptions{}); err == nil && address != *field.value {
+				change(prefix+"."+field.name, "normalized %q to %s", *field.value, address)
+				*field.value = address
+			}
+		}
+		normalizeLinkTypes(prefix, p.Links, change)
+	}
+	for _, id := range sortedKeys(e.Locations) {
+		l := e.Locations[id]
+		if l == nil {
+			continue
+		}
+		prefix := fmt.Sprintf("locations[%s]", id)
+		if l.Type == nil || *l.Type == "" {
+			l.Type = String("Location")
+			change(prefix+".@type", "set to 'Location'")
+		}
+		normalizeLinkTypes(prefix, l.Links, change)
+	}
+	for _, id := range sortedKeys(e.VirtualLocations) {
+		if vl := e.VirtualLocations[id]; vl != nil && vl.Type == "" {
+			vl.Type = "VirtualLocation"
+			change(fmt.Sprintf("virtualLocations[%s].@type", id), "set to 'VirtualLocation'")
+		}
+	}
+	normalizeLinkTypes("", e.Links, change)
+	for _, id := range sortedKeys(e.TimeZones) {
+		if tz := e.TimeZones[id]; tz != nil && (tz.Type == nil || *tz.Type == "") {
+			tz.Type = String("TimeZone")
+			change(fmt.Sprintf("timeZones[%s].@type", id), "set to 'TimeZone'")
+		}
+	}
+	for i := range e.RecurrenceRules {
+		if e.RecurrenceRules[i].Type == "" {
+			e.RecurrenceRules[i].Type = "RecurrenceRule"
+			change(fmt.Sprintf("recurrenceRules[%d].@type", i), "set to 'RecurrenceRule'")
+		}
+	}
+
+	for _, id := range sortedKeys(e.Alerts) {
+		a := e.Alerts[id]
+		if a == nil {
+			continue
+		}
+		prefix := fmt.Sprintf("alerts[%s]", id)
+		if a.Type == "" {
+			a.Type = "Alert"
+			change(prefix+".@type", "set to 'Alert'")
+		}
+		switch t := a.Trigger.(type) {
+		case *OffsetTrigger:
+			if t.Type == "" {
+				t.Type = "OffsetTrigger"
+				change(prefix+".trigger.@type", "set to 'OffsetTrigger'")
+			}
+			if days, ok := convertWeekDuration(t.Offset); ok {
+				change(prefix+".trigger.offset", "converted %s to %s", t.Offset, days)
+				t.Offset = days
+			}
+		case *AbsoluteTrigger:
+			if t.Type == "" {
+				t.Type = "AbsoluteTrigger"
+				change(prefix+".trigger.@type", "set to 'AbsoluteTrigger'")
+			}
+		}
+	}
+
+	if !opts.SkipTrim {
+		if e.Title != nil && len(*e.Title) > MaxTitleLength {
+			trimmed := truncateUTF8(*e.Title, MaxTitleLength)
+			change("title", "trimmed from %d to %d bytes", len(*e.Title), len(trimmed))
+			e.Title = &trimmed
+		}
+		if e.Description != nil && len(*e.Description) > MaxDescriptionLength {
+			trimmed := truncateUTF8(*e.Description, MaxDescriptionLength)
+			change("description", "trimmed from %d to %d bytes", len(*e.Description), len(trimmed))
+			e.Description = &trimmed
+		}
+	}
+
+	return changes
+}
+
+// normalizeLinkTypes fills in missing @type on links under the given prefix
+func normalizeLinkTypes(prefix string, links map[string]*Link, change func(string, string, ...interface{})) {
+	if prefix != "" {
+		prefix += "."
+	}
+	for _, id := range sortedKeys(links) {
+		if l := links[id]; l != nil && (l.Type == nil || *l.Type == "") {
+			l.Type = String("Link")
+			change(fmt.Sprintf("%slinks[%s].@type", prefix, id), "set to 'Link'")
+		}
+	}
+}
+
+// dedupeParticipants merges participants sharing an address into the one
+// with the lowest id, see mergeParticipant, and repoints references
+func dedupeParticipants(participants map[string]*Participant) []NormalizeChange {
+	var changes []NormalizeChange
+	byAddress := make(map[string]string)
+	merged := make(map[string]string)
+
+	for _, id := range sortedKeys(participants) {
+		p := participants[id]
+		if p == nil {
+			continue
+		}
+		address := participantAddress(p)
+		if address == "" {
+			continue
+		}
+		keepId, ok := byAddress[address]
+		if !ok {
+			byAddress[address] = id
+			continue
+		}
+
+		mergeParticipant(participants[keepId], p)
+		delete(participants, id)
+		merged[id] = keepId
+		changes = append(changes, NormalizeChange{
+			Field:   fmt.Sprintf("participants[%s]", id),
+			Message: fmt.Sprintf("merged into participants[%s] (same address %s)", keepId, address),
+		})
+	}
+
+	if len(merged) == 0 {
+		return changes
+	}
+
+	// Repoint references to merged participants
+	for _, p := range participants {
+		if p == nil {
+			continue
+		}
+		if p.InvitedBy != nil {
+			if keepId, ok := merged[*p.InvitedBy]; ok {
+				p.InvitedBy = String(keepId)
+			}
+		}
+		for _, set := range []map[string]bool{p.DelegatedTo, p.DelegatedFrom, p.MemberOf} {
+			for id := range set {
+				if keepId, ok := merged[id]; ok {
+					delete(set, id)
+					set[keepId] = true
+				}
+			}
+		}
+	}
+
+	return changes
+}
+
+// convertWeekDuration rewrites a duration with a week component in days,
+// e.g. P2W to P14D and P1W2DT3H to P9DT3H
+func convertWeekDuration(duration string) (string, bool) {
+	m := weekDurationPattern.FindStringSubmatch(duration)
+	if m == nil {
+		return "", false
+	}
+	weeks, err := strconv.Atoi(m[2])
+	if err != nil {
+		return "", false
+	}
+	days := weeks * 7
+	if m[3] != "" {
+		extra, err := strconv.Atoi(m[3])
+		if err != nil {
+			return "", false
+		}
+		days += extra
+	}
+	return fmt.Sprintf("%sP%dD%s", m[1], days, m[4]), true
+}
+
+// truncateUTF8 shortens s to at most max bytes without splitting a character
+func truncateUTF8(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+	return s[:max]
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,133 @@
+package jscal
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNormalize(t *testing.T) {
+	event := &Event{
+		UID:            "normalize-test",
+		Start:          NewLocalDateTime(time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)),
+		Status:         String("CONFIRMED"),
+		Privacy:        String("Private"),
+		FreeBusyStatus: String("busy"),
+		Duration:       String("P1W2DT3H"),
+		Color:          String("Teal"),
+		Title:          String(strings.Repeat("é", MaxTitleLength)),
+		Participants: map[string]*Participant{
+			"a": {Email: String("ann@example.com"), Roles: map[string]bool{"attendee": true}},
+			"b": {Name: String("Ann"), Email: String("ANN@example.com"), Roles: map[string]bool{"chair": true}},
+			"c": {Email: String("bob@example.com"), DelegatedFrom: map[string]bool{"b": true}},
+		},
+		Locations: map[string]*Location{
+			"loc": {Name: String("Room"), Links: map[string]*Link{"map": {Href: "https://example.com/map"}}},
+		},
+		RecurrenceRules: []RecurrenceRule{{Frequency: "weekly"}},
+		Alerts: map[string]*Alert{
+			"a1": {Trigger: &OffsetTrigger{Offset: "-P1W"}},
+		},
+	}
+
+	changes := event.Normalize(NormalizeOptions{})
+
+	if event.Type != "Event" {
+		t.Errorf("Expected @type 'Event', got %q", event.Type)
+	}
+	if *event.Status != "confirmed" || *event.Privacy != "private" {
+		t.Errorf("Expected lowercased status and privacy, got %q %q", *event.Status, *event.Privacy)
+	}
+	if *event.Duration != "P9DT3H" {
+		t.Errorf("Expected duration P9DT3H, got %s", *event.Duration)
+	}
+	if *event.Color != "#008080" {
+		t.Errorf("Expected color #008080, got %s", *event.Color)
+	}
+	trigger := event.Alerts["a1"].Trigger.(*OffsetTrigger)
+	if event.Alerts["a1"].Type != "Alert" || trigger.Type != "OffsetTrigger" {
+		t.Errorf("Expected alert types to be filled in, got %+v", event.Alerts["a1"])
+	}
+	if trigger.Offset != "-P7D" {
+		t.Errorf("Expected offset -P7D, got %s", trigger.Offset)
+	}
+	if event.RecurrenceRules[0].Type != "RecurrenceRule" {
+		t.Errorf("Expected recurrence rule @type to be filled in")
+	}
+	if l := event.Locations["loc"]; *l.Type != "Location" || *l.Links["map"].Type != "Link" {
+		t.Errorf("Expected location and nested link types to be filled in")
+	}
+
+	if len(event.Participants) != 2 {
+		t.Fatalf("Expected duplicate participant to be merged, got %d participants", len(event.Participants))
+	}
+	ann := event.Participants["a"]
+	if !ann.Roles["attendee"] || !ann.Roles["chair"] || ann.Name == nil || *ann.Name != "Ann" {
+		t.Errorf("Expected merged roles and name, got %+v", ann)
+	}
+	if !event.Participants["c"].DelegatedFrom["a"] || event.Participants["c"].DelegatedFrom["b"] {
+		t.Errorf("Expected delegatedFrom to point at merged participant, got %v", event.Participants["c"].DelegatedFrom)
+	}
+
+	if len(*event.Title) > MaxTitleLength || !strings.HasSuffix(*event.Title, "é") {
+		t.Errorf("Expected title trimmed on a character boundary, got %d bytes", len(*event.Title))
+	}
+
+	if len(changes) == 0 {
+		t.Fatal("Expected changes to be reported")
+	}
+	var sawMerge bool
+	for _, c := range changes {
+		if c.Field == "participants[b]" {
+			sawMerge = true
+		}
+	}
+	if !sawMerge {
+		t.Errorf("Expected merge to be reported, got %v", changes)
+	}
+
+	if err := event.Validate(); err != nil {
+		t.Errorf("Expected normalized event to be valid, got %v", err)
+	}
+
+	if again := event.Normalize(NormalizeOptions{}); len(again) != 0 {
+		t.Errorf("Expected Normalize to be idempotent, got %v", again)
+	}
+}
+
+func TestNormalizeOptions(t *testing.T) {
+	event := NewEvent("normalize-opts", strings.Repeat("a", MaxTitleLength+10))
+	event.AddParticipant("a", NewParticipant("Ann", "ann@example.com"))
+	event.AddParticipant("b", NewParticipant("Ann", "ann@example.com"))
+
+	event.Normalize(NormalizeOptions{SkipDedupeParticipants: true, SkipTrim: true})
+
+	if len(event.Participants) != 2 {
+		t.Errorf("Expected participants to be kept, got %d", len(event.Participants))
+	}
+	if len(*event.Title) != MaxTitleLength+10 {
+		t.Errorf("Expected title to be kept, got %d bytes", len(*event.Title))
+	}
+}
+
+func TestConvertWeekDuration(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		ok    bool
+	}{
+		{"P2W", "P14D", true},
+		{"-P1W", "-P7D", true},
+		{"P1W2D", "P9D", true},
+		{"P1WT12H", "P7DT12H", true},
+		{"P3D", "", false},
+		{"PT1H", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := convertWeekDuration(tt.input)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("convertWeekDuration(%q) = %q, %v, want %q, %v", tt.input, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+// Package observe lets services embedding jscal see what it does: the
+// iCalendar converter, the store and feeds log through a *slog.Logger,
+// start a span for each conversion batch, store operation and feed fetch,
+// and count the data they drop.
+//
+//	hooks := &observe.Hooks{Logger: slog.Default(), Tracer: tracer, Counter: counter}
+//	converter := &ical.Converter{Hooks: hooks}
+//	s := store.New()
+//	s.Hooks = hooks
+//
+// Tracer and Counter are small interfaces with attributes as slog.Attr, so
+// jscal depends on no tracing library. Adapting OpenTelemetry takes a few
+// lines:
+//
+//	type otelTracer struct{ trace.Tracer }
+//
+//	func (t otelTracer) Start(ctx context.Context, name string, attrs ...slog.Attr) (context.Context, observe.Span) {
+//		ctx, span := t.Tracer.Start(ctx, name, trace.WithAttributes(toKeyValues(attrs)...))
+//		return ctx, otelSpan{span}
+//	}
+//
+// A nil *Hooks, and any nil field, does nothing.
+package observe
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// Counter names
+const (
+	// UnsupportedProperties counts source properties a converter has no
+	// mapping for and drops, with a "property" attribute
+	UnsupportedProperties = "jscal.convert.unsupported_properties"
+
+	// UnsupportedParameters counts parameters of mapped properties a
+	// converter drops, with a "parameter" attribute such as "ATTENDEE;RSVP"
+	UnsupportedParameters = "jscal.convert.unsupported_parameters"
+
+	// DroppedItems counts items a conversion skips: components such as
+	// VJOURNAL, items that fail to convert in lenient mode and attachments
+	// over the size limit, with an "item" attribute
+	DroppedItems = "jscal.convert.dropped_items"
+
+	// StoreConflicts counts conditional store writes rejected because the
+	// object changed
+	StoreConflicts = "jscal.store.conflicts"
+)
+
+// Tracer starts spans, e.g. by wrapping an OpenTelemetry trace.Tracer
+type Tracer interface {
+	// Start starts a span named name as a child of any span in ctx, and
+	// returns a context holding it
+	Start(ctx context.Context, name string, attrs ...slog.Attr) (context.Context, Span)
+}
+
+// Span is an operation started by a Tracer
+type Span interface {
+	// SetAttributes adds attributes known once the operation ran, such as
+	// the number of events converted
+	SetAttributes(attrs ...slog.Attr)
+
+	// End ends the span, marking it failed if err is not nil
+	End(err error)
+}
+
+// Counter adds to named counters, e.g. OpenTelemetry Int64Counters
+type Counter interface {
+	Add(ctx context.Context, name string, n int64, attrs ...slog.Attr)
+}
+
+// Hooks connects jscal to logging, tracing and metrics
+type Hooks struct {
+	Logger  *slog.Logger // Debug records for each operation, warnings for dropped data
+	Tracer  Tracer
+	Counter Counter
+}
+
+// Start starts a span with the Tracer, logging the operation at debug
+// level. The returned span is never nil.
+func (h *Hooks) Start(ctx context.Context, name string, attrs ...slog.Attr) (context.Context, Span) {
+	if h == nil {
+		return ctx, noopSpan{}
+	}
+	if h.Logger != nil {
+		h.Logger.LogAttrs(ctx, slog.LevelDebug, name, attrs...)
+	}
+	if h.Tracer == nil {
+		return ctx, noopSpan{}
+	}
+	return h.Tracer.Start(ctx, name, attrs...)
+}
+
+// Add adds n to the counter name
+func (h *Hooks) Add(ctx context.Context, name string, n int64, attrs ...slog.Attr) {
+	if h != nil && h.Counter != nil && n != 0 {
+		h.Counter.Add(ctx, name, n, attrs...)
+	}
+}
+
+// Warn logs a warning, e.g. about data that was dropped
+func (h *Hooks) Warn(ctx context.Context, msg string, attrs ...slog.Attr) {
+	if h != nil && h.Logger != nil {
+		h.Logger.LogAttrs(ctx, slog.LevelWarn, msg, attrs...)
+	}
+}
+
+// noopSpan is the Span of Hooks without a Tracer
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...slog.Attr) {}
+func (noopSpan) End(error)                  {}
+
+// Recorder is a Tracer and Counter keeping spans and counts in memory, for
+// tests. Read its fields once the operations have finished.
+type Recorder struct {
+	mu        sync.Mutex
+	Spans     []*RecordedSpan
+	additions []addition
+}
+
+// addition is a call of Recorder.Add
+type addition struct {
+	name  string
+	n     int64
+	attrs []slog.Attr
+}
+
+// RecordedSpan is a span started by a Recorder
+type RecordedSpan struct {
+	recorder *Recorder
+	Name     string
+	Attrs    []slog.Attr
+	Err      error
+	Ended    bool
+}
+
+// Start records a span
+func (r *Recorder) Start(ctx context.Context, name string, attrs ...slog.Attr) (context.Context, Span) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	span := &RecordedSpan{recorder: r, Name: name, Attrs: attrs}
+	r.Spans = append(r.Spans, span)
+	return ctx, span
+}
+
+// Add records an addition to a counter
+func (r *Recorder) Add(ctx context.Context, name string, n int64, attrs ...slog.Attr) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.additions = append(r.additions, addition{name: name, n: n, attrs: attrs})
+}
+
+// Count returns the total added to the counter name with all of the given
+// attributes
+func (r *Recorder) Count(name string, attrs ...slog.Attr) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var total int64
+	for _, a := range r.additions {
+		if a.name == name && hasAttrs(a.attrs, attrs) {
+			total += a.n
+		}
+	}
+	return total
+}
+
+// hasAttrs reports whether attrs includes every attribute of want
+func hasAttrs(attrs, want []slog.Attr) bool {
+	for _, w := range want {
+		found := false
+		for _, attr := range attrs {
+			found = found || attr.Equal(w)
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// SetAttributes records attributes
+func (s *RecordedSpan) SetAttributes(attrs ...slog.Attr) {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+	s.Attrs = append(s.Attrs, attrs...)
+}
+
+// End records the end of the span
+func (s *RecordedSpan) End(err error) {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+	s.Err, s.Ended = err, true
+}
+
+// Attr returns the value of the last attribute with the given key
+func (s *RecordedSpan) Attr(key string) slog.Value {
+	var value slog.Value
+	for _, attr := range s.Attrs {
+		if attr.Key == key {
+			value = attr.Value
+		}
+	}
+	return value
+}
@@ -0,0 +1,57 @@
+package observe
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestNilHooks(t *testing.T) {
+	var h *Hooks
+	ctx, span := h.Start(context.Background(), "op")
+	span.SetAttributes(slog.Int("n", 1))
+	span.End(errors.New("failed"))
+	h.Add(ctx, "counter", 1)
+	h.Warn(ctx, "warning")
+
+	_, span = (&Hooks{}).Start(context.Background(), "op")
+	span.End(nil)
+}
+
+func TestHooks(t *testing.T) {
+	var buf bytes.Buffer
+	recorder := &Recorder{}
+	h := &Hooks{
+		Logger:  slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+		Tracer:  recorder,
+		Counter: recorder,
+	}
+
+	ctx, span := h.Start(context.Background(), "jscal.test", slog.String("uid", "e1"))
+	span.SetAttributes(slog.Int("events", 3))
+	span.End(errors.New("failed"))
+	h.Add(ctx, UnsupportedProperties, 2, slog.String("property", "X-FOO"))
+	h.Add(ctx, UnsupportedProperties, 0)
+	h.Warn(ctx, "dropped", slog.String("property", "X-FOO"))
+
+	if len(recorder.Spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(recorder.Spans))
+	}
+	got := recorder.Spans[0]
+	if got.Name != "jscal.test" || !got.Ended || got.Err == nil || got.Attr("events").Int64() != 3 || got.Attr("uid").String() != "e1" {
+		t.Errorf("Unexpected span %+v", got)
+	}
+	if n := recorder.Count(UnsupportedProperties, slog.String("property", "X-FOO")); n != 2 {
+		t.Errorf("Expected count 2, got %d", n)
+	}
+	if n := recorder.Count(UnsupportedProperties, slog.String("property", "X-BAR")); n != 0 {
+		t.Errorf("Expected no count for X-BAR, got %d", n)
+	}
+	logs := buf.String()
+	if !strings.Contains(logs, "level=DEBUG msg=jscal.test uid=e1") || !strings.Contains(logs, "level=WARN msg=dropped property=X-FOO") {
+		t.Errorf("Unexpected log output %q", logs)
+	}
+}
@@ -0,0 +1,138 @@
+package jscal
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// PatchObject is a patch as used by recurrenceOverrides and localizations
+// (RFC 8984 Section 1.4.9): JSON pointers, with the leading "/" implicit,
+// mapped to the value to set, or nil to remove the property
+type PatchObject = map[string]interface{}
+
+// overrideKey returns the key of the recurrenceOverrides entry for id,
+// comparing keys by wall clock time so that "2025-03-04T09:00:00Z" and
+// "2025-03-04T09:00:00" are the same instance
+func overrideKey(overrides map[string]map[string]interface{}, id LocalDateTime) (string, bool) {
+	key := LocalDateTime(wallClock(id)).String()
+	if _, ok := overrides[key]; ok {
+		return key, true
+	}
+	for k := range overrides {
+		if overrideId, err := ParseLocalDateTime(k); err == nil && wallClock(*overrideId).Equal(wallClock(id)) {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+// checkOverridePatch rejects patches with an empty path or a path into a
+// property overrides must not patch (RFC 8984 Section 4.3.5)
+func checkOverridePatch(patch PatchObject) error {
+	for _, path := range sortedKeys(patch) {
+		segments := splitPointer(path)
+		if len(segments) == 0 {
+			return fmt.Errorf("invalid patch path %q", path)
+		}
+		if unpatchableProperties[segments[0]] {
+			return fmt.Errorf("recurrenceOverrides must not patch %s", segments[0])
+		}
+	}
+	return nil
+}
+
+// setOverride stores patch under the canonical key of id, replacing an
+// entry for the same instance keyed differently
+func setOverride(overrides *map[string]map[string]interface{}, id LocalDateTime, patch PatchObject) error {
+	if err := checkOverridePatch(patch); err != nil {
+		return err
+	}
+	if key, ok := overrideKey(*overrides, id); ok {
+		delete(*overrides, key)
+	}
+	if *overrides == nil {
+		*overrides = make(map[string]map[string]interface{})
+	}
+	(*overrides)[LocalDateTime(wallClock(id)).String()] = patch
+	return nil
+}
+
+// deleteOverride removes the entry for id and reports whether there was one
+func deleteOverride(overrides *map[string]map[string]interface{}, id LocalDateTime) bool {
+	key, ok := overrideKey(*overrides, id)
+	if !ok {
+		return false
+	}
+	delete(*overrides, key)
+	if len(*overrides) == 0 {
+		*overrides = nil
+	}
+	return true
+}
+
+// overrideIDs returns the recurrence ids of overrides in order
+func overrideIDs(overrides map[string]map[string]interface{}) ([]LocalDateTime, error) {
+	ids := make([]LocalDateTime, 0, len(overrides))
+	for key := range overrides {
+		id, err := ParseLocalDateTime(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recurrenceOverrides key %s", key)
+		}
+		ids = append(ids, LocalDateTime(wallClock(*id)))
+	}
+	sort.Slice(ids, func(i, j int) bool { return time.Time(ids[i]).Before(time.Time(ids[j])) })
+	return ids, nil
+}
+
+// SetOverride sets the recurrenceOverrides patch of the instance with the
+// given recurrence id, keyed in the canonical LocalDateTime form. Patches
+// of properties overrides must not change, such as uid, are an error.
+func (e *Event) SetOverride(id LocalDateTime, patch PatchObject) error {
+	return setOverride(&e.RecurrenceOverrides, id, patch)
+}
+
+// GetOverride returns the recurrenceOverrides patch of the instance with
+// the given recurrence id, whatever the format of its key
+func (e *Event) GetOverride(id LocalDateTime) (PatchObject, bool) {
+	key, ok := overrideKey(e.RecurrenceOverrides, id)
+	return e.RecurrenceOverrides[key], ok
+}
+
+// DeleteOverride removes the override of the instance with the given
+// recurrence id and reports whether there was one
+func (e *Event) DeleteOverride(id LocalDateTime) bool {
+	return deleteOverride(&e.RecurrenceOverrides, id)
+}
+
+// ListOverrideIDs returns the recurrence ids of the event's overrides in
+// chronological order. A key that isn't a LocalDateTime is an error.
+func (e *Event) ListOverrideIDs() ([]LocalDateTime, error) {
+	return overrideIDs(e.RecurrenceOverrides)
+}
+
+// SetOverride sets the recurrenceOverrides patch of the instance with the
+// given recurrence id, keyed in the canonical LocalDateTime form. Patches
+// of properties overrides must not change, such as uid, are an error.
+func (t *Task) SetOverride(id LocalDateTime, patch PatchObject) error {
+	return setOverride(&t.RecurrenceOverrides, id, patch)
+}
+
+// GetOverride returns the recurrenceOverrides patch of the instance with
+// the given recurrence id, whatever the format of its key
+func (t *Task) GetOverride(id LocalDateTime) (PatchObject, bool) {
+	key, ok := overrideKey(t.RecurrenceOverrides, id)
+	return t.RecurrenceOverrides[key], ok
+}
+
+// DeleteOverride removes the override of the instance with the given
+// recurrence id and reports whether there was one
+func (t *Task) DeleteOverride(id LocalDateTime) bool {
+	return deleteOverride(&t.RecurrenceOverrides, id)
+}
+
+// ListOverrideIDs returns the recurrence ids of the task's overrides in
+// chronological order. A key that isn't a LocalDateTime is an error.
+func (t *Task) ListOverrideIDs() ([]LocalDateTime, error) {
+	return overrideIDs(t.RecurrenceOverrides)
+}
@@ -0,0 +1,102 @@
+package jscal
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEventOverrides(t *testing.T) {
+	event := newDailyStandup()
+	second := LocalDateTime(time.Date(2025, 3, 4, 9, 0, 0, 0, time.UTC))
+	first := LocalDateTime(time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC))
+
+	// A key stored in another format is found and replaced
+	event.RecurrenceOverrides = map[string]map[string]interface{}{
+		"2025-03-04T09:00:00Z": {"title": "Short"},
+	}
+	patch, ok := event.GetOverride(second)
+	if !ok || patch["title"] != "Short" {
+		t.Errorf("Expected the override for 2025-03-04T09:00:00Z, got %v, %v", patch, ok)
+	}
+	if err := event.SetOverride(second, PatchObject{"title": "Long"}); err != nil {
+		t.Fatalf("SetOverride failed: %v", err)
+	}
+	if len(event.RecurrenceOverrides) != 1 || event.RecurrenceOverrides["2025-03-04T09:00:00"]["title"] != "Long" {
+		t.Errorf("Expected a single canonical key, got %v", event.RecurrenceOverrides)
+	}
+
+	if err := event.SetOverride(first, PatchObject{"excluded": true}); err != nil {
+		t.Fatalf("SetOverride failed: %v", err)
+	}
+	ids, err := event.ListOverrideIDs()
+	if err != nil {
+		t.Fatalf("ListOverrideIDs failed: %v", err)
+	}
+	if len(ids) != 2 || ids[0].String() != first.String() || ids[1].String() != second.String() {
+		t.Errorf("Expected ids in order, got %v", ids)
+	}
+
+	// The accessors keep the JSON form
+	data, err := json.Marshal(event)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !strings.Contains(string(data), `"2025-03-03T09:00:00":{"excluded":true}`) {
+		t.Errorf("Expected the override in the JSON, got %s", data)
+	}
+
+	if !event.DeleteOverride(first) || event.DeleteOverride(first) {
+		t.Error("Expected DeleteOverride to remove the override once")
+	}
+	event.DeleteOverride(second)
+	if event.RecurrenceOverrides != nil {
+		t.Errorf("Expected no overrides left, got %v", event.RecurrenceOverrides)
+	}
+	if _, ok := event.GetOverride(second); ok {
+		t.Error("Expected no override after DeleteOverride")
+	}
+}
+
+func TestSetOverrideInvalid(t *testing.T) {
+	id := LocalDateTime(time.Date(2025, 3, 4, 9, 0, 0, 0, time.UTC))
+	tests := []struct {
+		name    string
+		patch   PatchObject
+		wantErr string
+	}{
+		{name: "uid", patch: PatchObject{"uid": "other"}, wantErr: "must not patch uid"},
+		{name: "nested rule", patch: PatchObject{"recurrenceRules/0/count": 2}, wantErr: "must not patch recurrenceRules"},
+		{name: "empty path", patch: PatchObject{"": "x"}, wantErr: "invalid patch path"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := newDailyStandup()
+			err := event.SetOverride(id, tt.patch)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+			if patch, _ := event.GetOverride(id); patch["title"] != "Standup (short)" {
+				t.Errorf("Expected the existing override to be kept, got %v", patch)
+			}
+		})
+	}
+}
+
+func TestListOverrideIDsInvalidKey(t *testing.T) {
+	task := NewTask("overrides-task", "Water plants")
+	task.RecurrenceOverrides = map[string]map[string]interface{}{"next tuesday": {}}
+	if _, err := task.ListOverrideIDs(); err == nil {
+		t.Error("Expected an error for a key that isn't a LocalDateTime")
+	}
+
+	task.RecurrenceOverrides = nil
+	id := LocalDateTime(time.Date(2025, 3, 4, 9, 0, 0, 0, time.UTC))
+	if err := task.SetOverride(id, PatchObject{"progress": ProgressCompleted}); err != nil {
+		t.Fatalf("SetOverride failed: %v", err)
+	}
+	if patch, ok := task.GetOverride(id); !ok || patch["progress"] != ProgressCompleted {
+		t.Errorf("Expected the task override, got %v, %v", patch, ok)
+	}
+}
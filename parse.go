@@ -0,0 +1,382 @@
+package jscal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrTooLarge is returned, wrapped, for input longer than ParseOptions.MaxBytes
+// and for files over the AttachOptions limits
+var ErrTooLarge = errors.New("input too large")
+
+// ParseOptions configures a Parser. The zero value parses exactly like
+// Parse() and the other package-level functions.
+type ParseOptions struct {
+	// DisallowUnknownFields rejects properties RFC 8984 doesn't define, at
+	// any depth. Vendor extension properties ("example.com:name") are still
+	// accepted.
+	DisallowUnknownFields bool
+
+	// MaxBytes rejects input longer than this many bytes; 0 means no limit
+	MaxBytes int64
+
+	// SkipValidation returns objects without validating them, e.g. to
+	// repair them before calling Validate
+	SkipValidation bool
+
+	// DefaultType is the @type Parse and ParseAll assume for top-level
+	// objects without one, e.g. "Event". ParseEvent, ParseTask, ParseGroup
+	// and their ParseAll variants always assume their own type.
+	DefaultType string
+}
+
+// Parser parses JSCalendar JSON with configurable limits and strictness
+type Parser struct {
+	opts ParseOptions
+}
+
+// NewParser creates a Parser with the given options
+func NewParser(opts ParseOptions) *Parser {
+	return &Parser{opts: opts}
+}
+
+// defaultParser backs the package-level Parse functions
+var defaultParser = NewParser(ParseOptions{})
+
+// ParseWithOptions parses any JSCalendar object based on @type field,
+// applying opts
+func ParseWithOptions(data []byte, opts ParseOptions) (CalendarObject, error) {
+	return NewParser(opts).Parse(data)
+}
+
+// Parse parses any JSCalendar object based on @type field
+func (p *Parser) Parse(data []byte) (CalendarObject, error) {
+	if err := p.checkSize(data); err != nil {
+		return nil, err
+	}
+
+	// First, unmarshal to a map to check the @type field
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", withKind(ErrInvalidJSON, err))
+	}
+
+	// Get the @type field
+	typeField, ok := raw["@type"].(string)
+	if _, set := raw["@type"]; !set && p.opts.DefaultType != "" {
+		typeField, ok = p.opts.DefaultType, true
+	}
+	if !ok {
+		return nil, withKind(ErrUnknownType, errors.New("missing or invalid @type field"))
+	}
+
+	// Parse based on type
+	switch typeField {
+	case "Event":
+		return p.ParseEvent(data)
+	case "Task":
+		return p.ParseTask(data)
+	case "Group":
+		return p.ParseGroup(data)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownType, typeField)
+	}
+}
+
+// ParseAll parses multiple JSCalendar objects of any type
+func (p *Parser) ParseAll(data []byte) ([]CalendarObject, error) {
+	if err := p.checkSize(data); err != nil {
+		return nil, err
+	}
+
+	// First, unmarshal to array of raw JSON
+	var rawArray []json.RawMessage
+	if err := json.Unmarshal(data, &rawArray); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON array: %w", withKind(ErrInvalidJSON, err))
+	}
+
+	// Parse each object
+	objects := make([]CalendarObject, 0, len(rawArray))
+	for i, raw := range rawArray {
+		obj, err := p.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse object at index %d: %w", i, err)
+		}
+		objects = append(objects, obj)
+	}
+
+	return objects, nil
+}
+
+// ParseEvent parses JSCalendar JSON data into an Event
+func (p *Parser) ParseEvent(data []byte) (*Event, error) {
+	if err := p.checkSize(data); err != nil {
+		return nil, err
+	}
+
+	var event Event
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("failed to parse JSCalendar Event JSON: %w", withKind(ErrInvalidJSON, err))
+	}
+	if err := p.checkFields(data, reflect.TypeOf(event)); err != nil {
+		return nil, fmt.Errorf("failed to parse JSCalendar Event JSON: %w", err)
+	}
+	setDefaultType(&event.Type, "Event")
+
+	// Validate the parsed event
+	if err := p.validate(&event); err != nil {
+		return nil, fmt.Errorf("parsed JSCalendar Event is invalid: %w", err)
+	}
+
+	return &event, nil
+}
+
+// ParseAllEvents parses multiple JSCalendar events from JSON array
+func (p *Parser) ParseAllEvents(data []byte) ([]*Event, error) {
+	if err := p.checkSize(data); err != nil {
+		return nil, err
+	}
+
+	var events []*Event
+	if err := json.Unmarshal(data, &events); err != nil {
+		return nil, fmt.Errorf("failed to parse JSCalendar Event JSON array: %w", withKind(ErrInvalidJSON, err))
+	}
+	if err := p.checkFields(data, reflect.TypeOf(events)); err != nil {
+		return nil, fmt.Errorf("failed to parse JSCalendar Event JSON array: %w", err)
+	}
+
+	// Validate each event
+	for i, event := range events {
+		if event != nil {
+			setDefaultType(&event.Type, "Event")
+		}
+		if err := p.validate(event); err != nil {
+			return nil, fmt.Errorf("event at index %d is invalid: %w", i, err)
+		}
+	}
+
+	return events, nil
+}
+
+// ParseTask parses JSCalendar JSON data into a Task
+func (p *Parser) ParseTask(data []byte) (*Task, error) {
+	if err := p.checkSize(data); err != nil {
+		return nil, err
+	}
+
+	var task Task
+	if err := json.Unmarshal(data, &task); err != nil {
+		return nil, fmt.Errorf("failed to parse JSCalendar Task JSON: %w", withKind(ErrInvalidJSON, err))
+	}
+	if err := p.checkFields(data, reflect.TypeOf(task)); err != nil {
+		return nil, fmt.Errorf("failed to parse JSCalendar Task JSON: %w", err)
+	}
+	setDefaultType(&task.Type, "Task")
+
+	// Validate the parsed task
+	if err := p.validate(&task); err != nil {
+		return nil, fmt.Errorf("parsed JSCalendar Task is invalid: %w", err)
+	}
+
+	return &task, nil
+}
+
+// ParseAllTasks parses multiple JSCalendar tasks from JSON array
+func (p *Parser) ParseAllTasks(data []byte) ([]*Task, error) {
+	if err := p.checkSize(data); err != nil {
+		return nil, err
+	}
+
+	var tasks []*Task
+	if err := json.Unmarshal(data, &tasks); err != nil {
+		return nil, fmt.Errorf("failed to parse JSCalendar Task JSON array: %w", withKind(ErrInvalidJSON, err))
+	}
+	if err := p.checkFields(data, reflect.TypeOf(tasks)); err != nil {
+		return nil, fmt.Errorf("failed to parse JSCalendar Task JSON array: %w", err)
+	}
+
+	// Validate each task
+	for i, task := range tasks {
+		if task != nil {
+			setDefaultType(&task.Type, "Task")
+		}
+		if err := p.validate(task); err != nil {
+			return nil, fmt.Errorf("task at index %d is invalid: %w", i, err)
+		}
+	}
+
+	return tasks, nil
+}
+
+// ParseGroup parses JSCalendar JSON data into a Group
+func (p *Parser) ParseGroup(data []byte) (*Group, error) {
+	if err := p.checkSize(data); err != nil {
+		return nil, err
+	}
+
+	var group Group
+	if err := json.Unmarshal(data, &group); err != nil {
+		return nil, fmt.Errorf("failed to parse JSCalendar Group JSON: %w", withKind(ErrInvalidJSON, err))
+	}
+	if err := p.checkFields(data, reflect.TypeOf(group)); err != nil {
+		return nil, fmt.Errorf("failed to parse JSCalendar Group JSON: %w", err)
+	}
+	setDefaultType(&group.Type, "Group")
+
+	// Validate the parsed group
+	if err := p.validate(&group); err != nil {
+		return nil, fmt.Errorf("parsed JSCalendar Group is invalid: %w", err)
+	}
+
+	return &group, nil
+}
+
+// ParseAllGroups parses multiple JSCalendar groups from JSON array
+func (p *Parser) ParseAllGroups(data []byte) ([]*Group, error) {
+	if err := p.checkSize(data); err != nil {
+		return nil, err
+	}
+
+	var groups []*Group
+	if err := json.Unmarshal(data, &groups); err != nil {
+		return nil, fmt.Errorf("failed to parse JSCalendar Group JSON array: %w", withKind(ErrInvalidJSON, err))
+	}
+	if err := p.checkFields(data, reflect.TypeOf(groups)); err != nil {
+		return nil, fmt.Errorf("failed to parse JSCalendar Group JSON array: %w", err)
+	}
+
+	// Validate each group
+	for i, group := range groups {
+		if group != nil {
+			setDefaultType(&group.Type, "Group")
+		}
+		if err := p.validate(group); err != nil {
+			return nil, fmt.Errorf("group at index %d is invalid: %w", i, err)
+		}
+	}
+
+	return groups, nil
+}
+
+// checkSize enforces MaxBytes
+func (p *Parser) checkSize(data []byte) error {
+	if p.opts.MaxBytes > 0 && int64(len(data)) > p.opts.MaxBytes {
+		return fmt.Errorf("%w: %d bytes exceeds the limit of %d", ErrTooLarge, len(data), p.opts.MaxBytes)
+	}
+	return nil
+}
+
+// checkFields enforces DisallowUnknownFields for data decoded into type t
+func (p *Parser) checkFields(data []byte, t reflect.Type) error {
+	if !p.opts.DisallowUnknownFields {
+		return nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if path := unknownProperty(value, t, ""); path != "" {
+		return fmt.Errorf("%w '%s'", ErrUnknownProperty, path)
+	}
+	return nil
+}
+
+// setDefaultType sets the @type of an object without one to objType
+func setDefaultType(typeField *string, objType string) {
+	if *typeField == "" {
+		*typeField = objType
+	}
+}
+
+// validate validates obj unless SkipValidation is set
+func (p *Parser) validate(obj CalendarObject) error {
+	if p.opts.SkipValidation {
+		return nil
+	}
+	return withKind(ErrValidation, obj.Validate())
+}
+
+// polymorphicTypes maps the @type of objects held in interface fields, such
+// as group entries and alert triggers, to their Go type
+var polymorphicTypes = map[string]reflect.Type{
+	"Event":           reflect.TypeOf(Event{}),
+	"Task":            reflect.TypeOf(Task{}),
+	"Group":           reflect.TypeOf(Group{}),
+	"OffsetTrigger":   reflect.TypeOf(OffsetTrigger{}),
+	"AbsoluteTrigger": reflect.TypeOf(AbsoluteTrigger{}),
+}
+
+// unknownProperty returns the path of the first property in a decoded JSON
+// value that the Go type t doesn't declare, or "" if there is none. Vendor
+// extension properties are allowed everywhere.
+func unknownProperty(value interface{}, t reflect.Type, path string) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		known := knownProperties(t)
+		for _, key := range sortedKeys(obj) {
+			if !known[key] {
+				if !strings.Contains(key, ":") {
+					return path + key
+				}
+				continue
+			}
+			if found := unknownProperty(obj[key], jsonFieldType(t, key), path+key+"."); found != "" {
+				return found
+			}
+		}
+	case reflect.Map:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		for _, key := range sortedKeys(obj) {
+			if found := unknownProperty(obj[key], t.Elem(), path+key+"."); found != "" {
+				return found
+			}
+		}
+	case reflect.Slice:
+		items, ok := value.([]interface{})
+		if !ok {
+			return ""
+		}
+		prefix := strings.TrimSuffix(path, ".")
+		for i, item := range items {
+			if found := unknownProperty(item, t.Elem(), fmt.Sprintf("%s[%d].", prefix, i)); found != "" {
+				return found
+			}
+		}
+	case reflect.Interface:
+		// Free-form values such as patches have no declared properties
+		obj, ok := value.(map[string]interface{})
+		if !ok || t.NumMethod() == 0 {
+			return ""
+		}
+		objType, _ := obj["@type"].(string)
+		if concrete, ok := polymorphicTypes[objType]; ok {
+			return unknownProperty(value, concrete, path)
+		}
+	}
+	return ""
+}
+
+// jsonFieldType returns the type of the struct field with the given JSON name
+func jsonFieldType(t reflect.Type, name string) reflect.Type {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if tag, _, _ := strings.Cut(field.Tag.Get("json"), ","); tag == name {
+			return field.Type
+		}
+	}
+	return reflect.TypeOf((*interface{})(nil)).Elem()
+}
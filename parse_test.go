@@ -1,6 +1,10 @@
 package jscal
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -295,3 +299,139 @@ func TestParseValidation(t *testing.T) {
 		t.Error("Parse() should fail for invalid Group with empty UID")
 	}
 }
+
+func TestParseWithOptions(t *testing.T) {
+	event := `{"@type": "Event", "uid": "event-1", "start": "2024-01-01T10:00:00"}`
+
+	tests := []struct {
+		name    string
+		data    string
+		opts    ParseOptions
+		wantErr bool
+	}{
+		{"zero options", event, ParseOptions{}, false},
+		{"within size limit", event, ParseOptions{MaxBytes: int64(len(event))}, false},
+		{"over size limit", event, ParseOptions{MaxBytes: 10}, true},
+		{"unknown property allowed", `{"@type": "Event", "uid": "e", "start": "2024-01-01T10:00:00", "colour": "red"}`,
+			ParseOptions{}, false},
+		{"unknown property rejected", `{"@type": "Event", "uid": "e", "start": "2024-01-01T10:00:00", "colour": "red"}`,
+			ParseOptions{DisallowUnknownFields: true}, true},
+		{"vendor property accepted", `{"@type": "Event", "uid": "e", "start": "2024-01-01T10:00:00", "example.com:room": "4B"}`,
+			ParseOptions{DisallowUnknownFields: true}, false},
+		{"nested unknown property rejected", `{"@type": "Event", "uid": "e", "start": "2024-01-01T10:00:00",
+			"participants": {"p1": {"@type": "Participant", "nmae": "Jane"}}}`,
+			ParseOptions{DisallowUnknownFields: true}, true},
+		{"unknown property in group entry rejected", `{"@type": "Group", "uid": "g",
+			"entries": [{"@type": "Event", "uid": "e", "start": "2024-01-01T10:00:00", "colour": "red"}]}`,
+			ParseOptions{DisallowUnknownFields: true}, true},
+		{"missing type", `{"uid": "event-1", "start": "2024-01-01T10:00:00"}`, ParseOptions{}, true},
+		{"default type", `{"uid": "event-1", "start": "2024-01-01T10:00:00"}`, ParseOptions{DefaultType: "Event"}, false},
+		{"invalid", `{"@type": "Event", "uid": "", "start": "2024-01-01T10:00:00"}`, ParseOptions{}, true},
+		{"invalid without validation", `{"@type": "Event", "uid": "", "start": "2024-01-01T10:00:00"}`,
+			ParseOptions{SkipValidation: true}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj, err := ParseWithOptions([]byte(tt.data), tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && obj.GetType() != "Event" && obj.GetType() != "Group" {
+				t.Errorf("Expected an Event or Group, got %s", obj.GetType())
+			}
+		})
+	}
+}
+
+func TestParserTypedDefaultType(t *testing.T) {
+	// Typed parsers assume their own type, whatever DefaultType says
+	parser := NewParser(ParseOptions{DefaultType: "Group"})
+
+	event, err := parser.ParseEvent([]byte(`{"uid": "e1", "start": "2024-01-01T10:00:00"}`))
+	if err != nil || event.Type != "Event" {
+		t.Errorf("Expected an Event, got %v and %v", event, err)
+	}
+	task, err := parser.ParseTask([]byte(`{"uid": "t1"}`))
+	if err != nil || task.Type != "Task" {
+		t.Errorf("Expected a Task, got %v and %v", task, err)
+	}
+	tasks, err := parser.ParseAllTasks([]byte(`[{"uid": "t1"}, {"@type": "Task", "uid": "t2"}]`))
+	if err != nil || tasks[0].Type != "Task" {
+		t.Errorf("Expected Tasks, got %v and %v", tasks, err)
+	}
+	group, err := NewParser(ParseOptions{DefaultType: "Event"}).ParseGroup([]byte(`{"uid": "g1", "entries": []}`))
+	if err != nil || group.Type != "Group" {
+		t.Errorf("Expected a Group, got %v and %v", group, err)
+	}
+
+	// Parse and ParseAll apply DefaultType
+	objects, err := NewParser(ParseOptions{DefaultType: "Task"}).ParseAll([]byte(`[{"uid": "t1"}]`))
+	if err != nil || objects[0].GetType() != "Task" {
+		t.Errorf("Expected a Task, got %v and %v", objects, err)
+	}
+}
+
+func TestParserErrors(t *testing.T) {
+	parser := NewParser(ParseOptions{MaxBytes: 16, DisallowUnknownFields: true})
+
+	_, err := parser.ParseAllEvents([]byte(`[{"@type": "Event", "uid": "e1"}]`))
+	if !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Expected ErrTooLarge, got %v", err)
+	}
+
+	parser = NewParser(ParseOptions{DisallowUnknownFields: true})
+	_, err = parser.ParseAllTasks([]byte(`[{"@type": "Task", "uid": "t1", "alerts": {"a1": {"@type": "Alert",
+		"trigger": {"@type": "OffsetTrigger", "offset": "-PT5M", "when": "2024-01-01T10:00:00Z"}}}}]`))
+	if err == nil || !strings.Contains(err.Error(), "unknown property '[0].alerts.a1.trigger.when'") {
+		t.Errorf("Expected the path of the unknown property, got %v", err)
+	}
+}
+
+func TestParserStrictExamples(t *testing.T) {
+	files, err := filepath.Glob("testdata/rfc8984/examples/*.json")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("Expected RFC 8984 examples, got %v", err)
+	}
+
+	parser := NewParser(ParseOptions{DisallowUnknownFields: true})
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := parser.Parse(data); err != nil {
+			t.Errorf("%s: %v", filepath.Base(file), err)
+		}
+	}
+}
+
+// FuzzParseEvent feeds arbitrary JSON to ParseEvent and checks that any
+// event it accepts survives a JSON round trip with the same UID. The seed
+// corpus is the RFC 8984 examples.
+func FuzzParseEvent(f *testing.F) {
+	files, _ := filepath.Glob("testdata/rfc8984/examples/*.json")
+	for _, file := range files {
+		if data, err := os.ReadFile(file); err == nil {
+			f.Add(data)
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		event, err := ParseEvent(data)
+		if err != nil {
+			return
+		}
+		encoded, err := event.JSON()
+		if err != nil {
+			t.Fatalf("Failed to encode parsed event: %v", err)
+		}
+		reparsed, err := ParseEvent(encoded)
+		if err != nil {
+			t.Fatalf("Failed to reparse encoded event: %v\n%s", err, encoded)
+		}
+		if reparsed.UID != event.UID {
+			t.Errorf("UID changed in round trip: %q -> %q", event.UID, reparsed.UID)
+		}
+	})
+}
@@ -0,0 +1,201 @@
+package jscal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Directory maps email addresses to the people they belong to, e.g. an
+// LDAP or HR lookup resolving aliases to primary mailboxes
+type Directory interface {
+	// Lookup returns the entry for a lowercased address, false if the
+	// address isn't known
+	Lookup(email string) (DirectoryEntry, bool)
+}
+
+// DirectoryEntry is a person as known to a Directory
+type DirectoryEntry struct {
+	Email string // Canonical address
+	Name  string // Display name, used for participants without one
+}
+
+// DirectoryFunc adapts a function to the Directory interface
+type DirectoryFunc func(email string) (DirectoryEntry, bool)
+
+// Lookup calls f(email)
+func (f DirectoryFunc) Lookup(email string) (DirectoryEntry, bool) {
+	return f(email)
+}
+
+// ParticipantOptions configures NormalizeParticipants
+type ParticipantOptions struct {
+	// Directory, if set, maps addresses to their canonical form before
+	// duplicates are looked for
+	Directory Directory
+}
+
+// participationPrecedence orders participation statuses for merging: a
+// definite reply wins over a tentative one, and any reply over none
+var participationPrecedence = []string{
+	ParticipationAccepted,
+	ParticipationDeclined,
+	ParticipationTentative,
+	ParticipationDelegated,
+	ParticipationNeedsAction,
+}
+
+// NormalizeParticipants canonicalizes the participants' email addresses and
+// imip sendTo URIs to lowercase, maps them through the directory if one is
+// given, and merges participants with the same address into the one with
+// the lowest id. Merging combines roles, keeps the highest participation
+// status by precedence (accepted, declined, tentative, delegated,
+// needs-action) and repoints references to the merged participants. It
+// returns what it changed.
+func NormalizeParticipants(e *Event, opts ParticipantOptions) []NormalizeChange {
+	if e == nil {
+		return nil
+	}
+
+	var changes []NormalizeChange
+	for _, id := range sortedKeys(e.Participants) {
+		p := e.Participants[id]
+		if p == nil {
+			continue
+		}
+		field := fmt.Sprintf("participants[%s]", id)
+
+		if p.Email != nil {
+			if email := canonicalEmail(*p.Email); email != *p.Email {
+				changes = append(changes, NormalizeChange{Field: field + ".email", Message: fmt.Sprintf("canonicalized %q to %s", *p.Email, email)})
+				p.Email = String(email)
+			}
+		}
+		if uri, ok := p.SendTo[ReplyMethodImip]; ok && hasMailtoScheme(uri) {
+			if canonical := "mailto:" + canonicalEmail(uri); canonical != uri {
+				changes = append(changes, NormalizeChange{Field: field + ".sendTo[imip]", Message: fmt.Sprintf("canonicalized %q to %s", uri, canonical)})
+				p.SendTo[ReplyMethodImip] = canonical
+			}
+		}
+
+		if opts.Directory == nil {
+			continue
+		}
+		address := participantAddress(p)
+		if address == "" {
+			continue
+		}
+		entry, ok := opts.Directory.Lookup(address)
+		if !ok {
+			continue
+		}
+		if email := canonicalEmail(entry.Email); email != "" && email != address {
+			changes = append(changes, NormalizeChange{Field: field, Message: fmt.Sprintf("resolved %s to %s", address, email)})
+			if p.Email != nil {
+				p.Email = String(email)
+			}
+			if _, ok := p.SendTo[ReplyMethodImip]; ok || p.Email == nil {
+				if p.SendTo == nil {
+					p.SendTo = make(map[string]string)
+				}
+				p.SendTo[ReplyMethodImip] = "mailto:" + email
+			}
+		}
+		if p.Name == nil && entry.Name != "" {
+			p.Name = String(entry.Name)
+		}
+	}
+
+	return append(changes, dedupeParticipants(e.Participants)...)
+}
+
+// canonicalEmail trims and lowercases an address and drops a mailto: prefix
+func canonicalEmail(address string) string {
+	address = strings.TrimSpace(address)
+	if hasMailtoScheme(address) {
+		address = address[len("mailto:"):]
+	}
+	return strings.ToLower(address)
+}
+
+// hasMailtoScheme reports whether uri starts with mailto:, in any case
+func hasMailtoScheme(uri string) bool {
+	return len(uri) >= 7 && strings.EqualFold(uri[:7], "mailto:")
+}
+
+// participantAddress returns the lowercased address identifying a
+// participant: its email, or else its imip sendTo address
+func participantAddress(p *Participant) string {
+	if p.Email != nil && *p.Email != "" {
+		return canonicalEmail(*p.Email)
+	}
+	if uri, ok := p.SendTo[ReplyMethodImip]; ok && hasMailtoScheme(uri) {
+		return canonicalEmail(uri)
+	}
+	return ""
+}
+
+// mergeParticipant folds a duplicate into the participant that is kept.
+// Roles and the other sets are combined; single values of keep win, with
+// participationStatus decided by participationPrecedence.
+func mergeParticipant(keep, dup *Participant) {
+	for role, v := range dup.Roles {
+		if v {
+			if keep.Roles == nil {
+				keep.Roles = make(map[string]bool)
+			}
+			keep.Roles[role] = true
+		}
+	}
+	if keep.Name == nil && dup.Name != nil {
+		keep.Name = dup.Name
+	}
+	if keep.Email == nil && dup.Email != nil {
+		keep.Email = dup.Email
+	}
+	for method, uri := range dup.SendTo {
+		if _, ok := keep.SendTo[method]; !ok {
+			if keep.SendTo == nil {
+				keep.SendTo = make(map[string]string)
+			}
+			keep.SendTo[method] = uri
+		}
+	}
+
+	if dup.ParticipationStatus != nil && statusRank(participationStatus(dup)) < statusRank(participationStatus(keep)) {
+		keep.ParticipationStatus = dup.ParticipationStatus
+		if dup.ParticipationComment != nil {
+			keep.ParticipationComment = dup.ParticipationComment
+		}
+	}
+	if dup.ExpectReply != nil && *dup.ExpectReply {
+		keep.ExpectReply = Bool(true)
+	}
+
+	keep.DelegatedTo = mergeSet(keep.DelegatedTo, dup.DelegatedTo)
+	keep.DelegatedFrom = mergeSet(keep.DelegatedFrom, dup.DelegatedFrom)
+	keep.MemberOf = mergeSet(keep.MemberOf, dup.MemberOf)
+}
+
+// statusRank returns the position of a participation status in
+// participationPrecedence; unknown statuses rank last
+func statusRank(status string) int {
+	for i, s := range participationPrecedence {
+		if s == status {
+			return i
+		}
+	}
+	return len(participationPrecedence)
+}
+
+// mergeSet adds the true entries of src to dst, allocating it if needed
+func mergeSet(dst, src map[string]bool) map[string]bool {
+	for key, v := range src {
+		if v {
+			if dst == nil {
+				dst = make(map[string]bool)
+			}
+			dst[key] = true
+		}
+	}
+	return dst
+}
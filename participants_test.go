@@ -0,0 +1,82 @@
+package jscal
+
+import (
+	"testing"
+)
+
+func TestNormalizeParticipants(t *testing.T) {
+	event := NewEvent("invite", "Planning")
+	event.Participants = map[string]*Participant{
+		"a": {
+			Email:               String("Ann@Example.com"),
+			Roles:               map[string]bool{RoleAttendee: true},
+			ParticipationStatus: String(ParticipationNeedsAction),
+		},
+		"b": {
+			Name:                String("Ann Lee"),
+			SendTo:              map[string]string{ReplyMethodImip: "MAILTO:ann@EXAMPLE.com"},
+			Roles:               map[string]bool{RoleChair: true},
+			ParticipationStatus: String(ParticipationAccepted),
+		},
+		"c": {
+			Email:         String("bob@example.com"),
+			DelegatedFrom: map[string]bool{"b": true},
+		},
+		"d": {
+			Email: String("robert@example.com"),
+			Roles: map[string]bool{RoleOptional: true},
+		},
+	}
+
+	directory := DirectoryFunc(func(email string) (DirectoryEntry, bool) {
+		if email == "robert@example.com" || email == "bob@example.com" {
+			return DirectoryEntry{Email: "Bob@Example.com", Name: "Bob Smith"}, true
+		}
+		return DirectoryEntry{}, false
+	})
+	changes := NormalizeParticipants(event, ParticipantOptions{Directory: directory})
+	if len(changes) == 0 {
+		t.Fatal("Expected changes")
+	}
+
+	if len(event.Participants) != 2 {
+		t.Fatalf("Expected 2 participants after merging, got %d: %v", len(event.Participants), changes)
+	}
+	ann := event.Participants["a"]
+	if ann.GetEmail() != "ann@example.com" {
+		t.Errorf("Expected lowercased email, got %s", ann.GetEmail())
+	}
+	if !ann.Roles[RoleAttendee] || !ann.Roles[RoleChair] {
+		t.Errorf("Expected combined roles, got %v", ann.Roles)
+	}
+	if participationStatus(ann) != ParticipationAccepted {
+		t.Errorf("Expected accepted to win, got %s", participationStatus(ann))
+	}
+	if ann.GetName() != "Ann Lee" || ann.SendTo[ReplyMethodImip] != "mailto:ann@example.com" {
+		t.Errorf("Expected name and sendTo from the duplicate, got %+v", ann)
+	}
+
+	bob := event.Participants["c"]
+	if bob.GetEmail() != "bob@example.com" || bob.GetName() != "Bob Smith" || !bob.Roles[RoleOptional] {
+		t.Errorf("Expected the alias merged into the directory entry, got %+v", bob)
+	}
+	if !bob.DelegatedFrom["a"] || bob.DelegatedFrom["b"] {
+		t.Errorf("Expected delegatedFrom to point at the merged participant, got %v", bob.DelegatedFrom)
+	}
+}
+
+func TestStatusRank(t *testing.T) {
+	tests := []struct {
+		higher, lower string
+	}{
+		{ParticipationAccepted, ParticipationTentative},
+		{ParticipationDeclined, ParticipationNeedsAction},
+		{ParticipationTentative, ParticipationDelegated},
+		{ParticipationNeedsAction, "x-unknown"},
+	}
+	for _, tt := range tests {
+		if statusRank(tt.higher) >= statusRank(tt.lower) {
+			t.Errorf("Expected %s to take precedence over %s", tt.higher, tt.lower)
+		}
+	}
+}
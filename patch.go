@@ -0,0 +1,56 @@
+package jscal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// applyPatch applies a PatchObject (RFC 8984 Section 1.4.9) to the JSON
+// representation of an object. Keys are JSON pointers, with the leading "/"
+// implicit; a null value removes the property. Every parent of a patched
+// property must exist.
+func applyPatch(obj map[string]interface{}, patch map[string]interface{}) error {
+	paths := make([]string, 0, len(patch))
+	for path := range patch {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		segments := splitPointer(path)
+		if len(segments) == 0 {
+			return fmt.Errorf("invalid patch path %q", path)
+		}
+
+		parent := obj
+		for _, segment := range segments[:len(segments)-1] {
+			child, ok := parent[segment].(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("invalid patch path %q: %s does not exist", path, segment)
+			}
+			parent = child
+		}
+
+		name := segments[len(segments)-1]
+		if value := patch[path]; value == nil {
+			delete(parent, name)
+		} else {
+			parent[name] = value
+		}
+	}
+	return nil
+}
+
+// splitPointer splits a JSON pointer into unescaped segments
+func splitPointer(path string) []string {
+	path = strings.TrimPrefix(path, "/")
+	if path == "" {
+		return nil
+	}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(segment)
+	}
+	return segments
+}
@@ -0,0 +1,86 @@
+package jscal
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// PriorityLevel maps a priority to the three-level scale of RFC 5545
+// Section 3.8.1.9: 1-4 is PriorityHigh, 5 PriorityNormal and 6-9
+// PriorityLow. Undefined and out of range priorities give 0.
+func PriorityLevel(priority int) int {
+	switch {
+	case priority >= 1 && priority <= 4:
+		return PriorityHigh
+	case priority == 5:
+		return PriorityNormal
+	case priority >= 6 && priority <= 9:
+		return PriorityLow
+	}
+	return PriorityDefault
+}
+
+// PriorityName returns "high", "normal" or "low" for a priority, or "" if
+// it is undefined
+func PriorityName(priority int) string {
+	switch PriorityLevel(priority) {
+	case PriorityHigh:
+		return "high"
+	case PriorityNormal:
+		return "normal"
+	case PriorityLow:
+		return "low"
+	}
+	return ""
+}
+
+// ParsePriorityName returns the priority for a level name as apps show it:
+// high, urgent or important, normal or medium, and low, ignoring case. An
+// empty name or "none" is the undefined priority 0.
+func ParsePriorityName(name string) (int, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "none":
+		return PriorityDefault, nil
+	case "high", "urgent", "important":
+		return PriorityHigh, nil
+	case "normal", "medium":
+		return PriorityNormal, nil
+	case "low":
+		return PriorityLow, nil
+	}
+	return 0, fmt.Errorf("unknown priority %q", name)
+}
+
+// PriorityFromScale maps a value on a scale of steps levels, 1 being the
+// highest as in most apps, linearly to a priority from 1 to 9. On a scale
+// of 3 that is 1, 5 and 9; of 4, as in Todoist, 1, 4, 6 and 9. Values
+// outside the scale, and scales of fewer than 2 steps, give 0.
+func PriorityFromScale(value, steps int) int {
+	if steps < 2 || value < 1 || value > steps {
+		return PriorityDefault
+	}
+	return 1 + int(math.Round(float64(value-1)*8/float64(steps-1)))
+}
+
+// PriorityToScale maps a priority to a scale of steps levels, 1 being the
+// highest, the inverse of PriorityFromScale. Undefined priorities and
+// scales of fewer than 2 steps give 0.
+func PriorityToScale(priority, steps int) int {
+	if steps < 2 || priority < 1 || priority > PriorityMax {
+		return 0
+	}
+	return 1 + int(math.Round(float64(priority-1)*float64(steps-1)/8))
+}
+
+// GetPriorityLevel returns the event's priority on the three-level scale,
+// see PriorityLevel
+func (e *Event) GetPriorityLevel() int {
+	return PriorityLevel(e.GetPriority())
+}
+
+// GetPriorityLevel returns the task's priority on the three-level scale,
+// see PriorityLevel
+func (t *Task) GetPriorityLevel() int {
+	return PriorityLevel(t.GetPriority())
+}
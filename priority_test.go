@@ -0,0 +1,112 @@
+package jscal
+
+import "testing"
+
+func TestPriorityLevel(t *testing.T) {
+	tests := []struct {
+		priority int
+		level    int
+		name     string
+	}{
+		{0, PriorityDefault, ""},
+		{1, PriorityHigh, "high"},
+		{4, PriorityHigh, "high"},
+		{5, PriorityNormal, "normal"},
+		{6, PriorityLow, "low"},
+		{9, PriorityLow, "low"},
+		{10, PriorityDefault, ""},
+		{-1, PriorityDefault, ""},
+	}
+
+	for _, tt := range tests {
+		if got := PriorityLevel(tt.priority); got != tt.level {
+			t.Errorf("PriorityLevel(%d): expected %d, got %d", tt.priority, tt.level, got)
+		}
+		if got := PriorityName(tt.priority); got != tt.name {
+			t.Errorf("PriorityName(%d): expected %q, got %q", tt.priority, tt.name, got)
+		}
+	}
+
+	event := NewEvent("priority-test", "Priority")
+	if event.GetPriorityLevel() != PriorityDefault {
+		t.Errorf("Expected undefined priority level, got %d", event.GetPriorityLevel())
+	}
+	event.SetPriority(3)
+	if event.GetPriorityLevel() != PriorityHigh {
+		t.Errorf("Expected high priority level, got %d", event.GetPriorityLevel())
+	}
+	task := NewTask("priority-task", "Priority")
+	task.SetPriority(7)
+	if task.GetPriorityLevel() != PriorityLow {
+		t.Errorf("Expected low priority level, got %d", task.GetPriorityLevel())
+	}
+}
+
+func TestParsePriorityName(t *testing.T) {
+	tests := []struct {
+		name     string
+		priority int
+		wantErr  bool
+	}{
+		{"High", PriorityHigh, false},
+		{"urgent", PriorityHigh, false},
+		{"medium", PriorityNormal, false},
+		{" normal ", PriorityNormal, false},
+		{"LOW", PriorityLow, false},
+		{"", PriorityDefault, false},
+		{"none", PriorityDefault, false},
+		{"critical", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParsePriorityName(tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParsePriorityName(%q): expected error %v, got %v", tt.name, tt.wantErr, err)
+			continue
+		}
+		if got != tt.priority {
+			t.Errorf("ParsePriorityName(%q): expected %d, got %d", tt.name, tt.priority, got)
+		}
+	}
+}
+
+func TestPriorityScale(t *testing.T) {
+	tests := []struct {
+		steps      int
+		priorities []int
+	}{
+		{2, []int{1, 9}},
+		{3, []int{1, 5, 9}},
+		{4, []int{1, 4, 6, 9}},
+		{5, []int{1, 3, 5, 7, 9}},
+		{9, []int{1, 2, 3, 4, 5, 6, 7, 8, 9}},
+	}
+
+	for _, tt := range tests {
+		for i, want := range tt.priorities {
+			value := i + 1
+			if got := PriorityFromScale(value, tt.steps); got != want {
+				t.Errorf("PriorityFromScale(%d, %d): expected %d, got %d", value, tt.steps, want, got)
+			}
+			if got := PriorityToScale(want, tt.steps); got != value {
+				t.Errorf("PriorityToScale(%d, %d): expected %d, got %d", want, tt.steps, value, got)
+			}
+		}
+	}
+
+	if got := PriorityToScale(4, 3); got != 2 {
+		t.Errorf("PriorityToScale(4, 3): expected 2, got %d", got)
+	}
+	for _, got := range []int{
+		PriorityFromScale(0, 3),
+		PriorityFromScale(4, 3),
+		PriorityFromScale(1, 1),
+		PriorityToScale(0, 3),
+		PriorityToScale(10, 3),
+		PriorityToScale(5, 1),
+	} {
+		if got != 0 {
+			t.Errorf("Expected 0 outside the scale, got %d", got)
+		}
+	}
+}
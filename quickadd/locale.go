@@ -0,0 +1,123 @@
+package quickadd
+
+import (
+	"time"
+
+	"github.com/airtrafik/jscal"
+)
+
+// Keyword is the role of a word in quick add text
+type Keyword int
+
+const (
+	KeywordNone     Keyword = iota
+	KeywordToday            // The current day
+	KeywordTomorrow         // The day after the current day
+	KeywordNext             // "next friday": the first friday after today
+	KeywordEvery            // Starts a recurrence, "every friday"
+	KeywordWeekday          // "every weekday": Monday to Friday
+	KeywordOn               // Introduces a date, "on friday"
+	KeywordAt               // Introduces a time or a location
+	KeywordFrom             // Introduces a time range, "from 3pm to 4pm"
+	KeywordTo               // Ends a time range
+	KeywordFor              // Introduces a duration, "for 2 hours"
+	KeywordIn               // Introduces a relative date, "in 3 days"
+	KeywordAnd              // Joins weekdays, "every monday and friday"
+	KeywordNoon             // 12:00
+	KeywordMidnight         // 00:00
+)
+
+// Unit is a unit of time that durations, relative dates and recurrence
+// intervals are counted in
+type Unit struct {
+	Duration  time.Duration // Length of the unit, zero for months and years
+	Frequency string        // Recurrence frequency, such as jscal.FrequencyWeekly
+}
+
+// Locale holds the words quick add text is written in. Words are matched in
+// lower case.
+type Locale struct {
+	Keywords  map[string]Keyword
+	Weekdays  map[string]time.Weekday
+	Months    map[string]time.Month
+	Units     map[string]Unit
+	Frequency map[string]string // Words that are a recurrence by themselves, such as "daily"
+	AM, PM    []string          // Suffixes of 12-hour clock times
+}
+
+// Units shared by locales
+var (
+	minute = Unit{Duration: time.Minute, Frequency: jscal.FrequencyMinutely}
+	hour   = Unit{Duration: time.Hour, Frequency: jscal.FrequencyHourly}
+	day    = Unit{Duration: 24 * time.Hour, Frequency: jscal.FrequencyDaily}
+	week   = Unit{Duration: 7 * 24 * time.Hour, Frequency: jscal.FrequencyWeekly}
+	month  = Unit{Frequency: jscal.FrequencyMonthly}
+	year   = Unit{Frequency: jscal.FrequencyYearly}
+)
+
+// English is the default locale
+var English = &Locale{
+	Keywords: map[string]Keyword{
+		"today":    KeywordToday,
+		"tomorrow": KeywordTomorrow,
+		"tmrw":     KeywordTomorrow,
+		"next":     KeywordNext,
+		"every":    KeywordEvery,
+		"weekday":  KeywordWeekday,
+		"weekdays": KeywordWeekday,
+		"on":       KeywordOn,
+		"at":       KeywordAt,
+		"@":        KeywordAt,
+		"from":     KeywordFrom,
+		"to":       KeywordTo,
+		"until":    KeywordTo,
+		"till":     KeywordTo,
+		"-":        KeywordTo,
+		"for":      KeywordFor,
+		"in":       KeywordIn,
+		"and":      KeywordAnd,
+		"noon":     KeywordNoon,
+		"midday":   KeywordNoon,
+		"midnight": KeywordMidnight,
+	},
+	Weekdays: map[string]time.Weekday{
+		"monday": time.Monday, "mon": time.Monday,
+		"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+		"wednesday": time.Wednesday, "wed": time.Wednesday,
+		"thursday": time.Thursday, "thu": time.Thursday, "thurs": time.Thursday,
+		"friday": time.Friday, "fri": time.Friday,
+		"saturday": time.Saturday, "sat": time.Saturday,
+		"sunday": time.Sunday, "sun": time.Sunday,
+	},
+	Months: map[string]time.Month{
+		"january": time.January, "jan": time.January,
+		"february": time.February, "feb": time.February,
+		"march": time.March, "mar": time.March,
+		"april": time.April, "apr": time.April,
+		"may":  time.May,
+		"june": time.June, "jun": time.June,
+		"july": time.July, "jul": time.July,
+		"august": time.August, "aug": time.August,
+		"september": time.September, "sep": time.September, "sept": time.September,
+		"october": time.October, "oct": time.October,
+		"november": time.November, "nov": time.November,
+		"december": time.December, "dec": time.December,
+	},
+	Units: map[string]Unit{
+		"m": minute, "min": minute, "mins": minute, "minute": minute, "minutes": minute,
+		"h": hour, "hr": hour, "hrs": hour, "hour": hour, "hours": hour,
+		"d": day, "day": day, "days": day,
+		"w": week, "wk": week, "week": week, "weeks": week,
+		"month": month, "months": month,
+		"year": year, "years": year,
+	},
+	Frequency: map[string]string{
+		"daily":    jscal.FrequencyDaily,
+		"weekly":   jscal.FrequencyWeekly,
+		"monthly":  jscal.FrequencyMonthly,
+		"yearly":   jscal.FrequencyYearly,
+		"annually": jscal.FrequencyYearly,
+	},
+	AM: []string{"am", "a.m."},
+	PM: []string{"pm", "p.m."},
+}
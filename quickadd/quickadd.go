@@ -0,0 +1,625 @@
+// Package quickadd turns short natural-language text, as typed into a
+// calendar's quick add box, into JSCalendar events.
+//
+// Basic usage:
+//
+//	event, err := quickadd.Parse("Lunch with Bob tomorrow 12:30-13:30 at Cafe Roma every Friday", quickadd.Context{})
+//
+// Dates, times, durations, locations and recurrences are picked out of the
+// text and the remaining words become the title. Words are read with a
+// Locale, English by default, and relative dates such as "tomorrow" are
+// resolved against the Context's current time and time zone.
+//
+// The parser is a heuristic: a location runs from "at" to the next date,
+// time, duration or recurrence, or to the end of the text, and times without
+// am or pm are read on the 24-hour clock.
+package quickadd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/airtrafik/jscal"
+)
+
+// Context is what quick add text is read against
+type Context struct {
+	// Now is the current time relative dates are resolved against,
+	// jscal.Now() if zero
+	Now time.Time
+
+	// Location is the time zone of dates and times, Now's location if nil
+	Location *time.Location
+
+	// Locale holds the words of the text, English if nil
+	Locale *Locale
+
+	// DefaultDuration is the duration of timed events without an end or a
+	// duration, one hour if zero
+	DefaultDuration time.Duration
+}
+
+// withDefaults returns the context with its zero fields filled in
+func (ctx Context) withDefaults() Context {
+	if ctx.Now.IsZero() {
+		ctx.Now = jscal.Now()
+	}
+	if ctx.Location == nil {
+		ctx.Location = ctx.Now.Location()
+	}
+	ctx.Now = ctx.Now.In(ctx.Location)
+	if ctx.Locale == nil {
+		ctx.Locale = English
+	}
+	if ctx.DefaultDuration <= 0 {
+		ctx.DefaultDuration = time.Hour
+	}
+	return ctx
+}
+
+// Parse creates an event from quick add text. Without a date the event is
+// today, or on the first day of its weekly recurrence. Without a time it is
+// an all-day event.
+func Parse(text string, ctx Context) (*jscal.Event, error) {
+	ctx = ctx.withDefaults()
+	p := newParser(text, ctx)
+	if len(p.words) == 0 {
+		return nil, fmt.Errorf("empty quick add text")
+	}
+
+	var title []string
+	for i := 0; i < len(p.words); {
+		if n, apply := p.match(i); n > 0 {
+			apply()
+			i += n
+			continue
+		}
+		title = append(title, p.words[i])
+		i++
+	}
+
+	name := strings.TrimRight(strings.Join(title, " "), ",;")
+	if name == "" {
+		return nil, fmt.Errorf("no title in %q", text)
+	}
+	return p.event(name), nil
+}
+
+// clock is a time of day as written, before am or pm is applied
+type clock struct {
+	hour, minute int
+	meridiem     int  // 0 if not written, else meridiemAM or meridiemPM
+	strong       bool // Written with minutes, am/pm or as a word, so not just a number
+}
+
+const (
+	meridiemAM = 1
+	meridiemPM = 2
+)
+
+// offset returns the time of day as an offset from midnight
+func (c clock) offset() time.Duration {
+	hour := c.hour
+	switch c.meridiem {
+	case meridiemAM:
+		hour %= 12
+	case meridiemPM:
+		hour = hour%12 + 12
+	}
+	return time.Duration(hour)*time.Hour + time.Duration(c.minute)*time.Minute
+}
+
+// parser holds the text being read and what was found in it
+type parser struct {
+	ctx   Context
+	words []string // The text's words as written
+	lower []string // The words in lower case, without trailing commas
+
+	date     *time.Time     // Midnight of the event's day
+	start    *time.Duration // Start as an offset from midnight
+	end      *time.Duration // End as an offset from midnight
+	duration time.Duration
+	location string
+	rule     *jscal.RecurrenceRule
+}
+
+func newParser(text string, ctx Context) *parser {
+	p := &parser{ctx: ctx, words: strings.Fields(text)}
+	for _, word := range p.words {
+		p.lower = append(p.lower, strings.TrimRight(strings.ToLower(word), ","))
+	}
+	return p
+}
+
+// match returns the number of words of the date, time, duration, location
+// or recurrence starting at word i, zero if there is none, and a func
+// recording it
+func (p *parser) match(i int) (int, func()) {
+	for _, m := range []func(int) (int, func()){
+		p.matchRecurrence,
+		p.matchDate,
+		p.matchTime,
+		p.matchDuration,
+		p.matchLocation,
+	} {
+		if n, apply := m(i); n > 0 {
+			return n, apply
+		}
+	}
+	return 0, nil
+}
+
+// word returns word i in lower case, or "" past the end
+func (p *parser) word(i int) string {
+	if i < 0 || i >= len(p.lower) {
+		return ""
+	}
+	return p.lower[i]
+}
+
+// keyword returns the keyword of word i
+func (p *parser) keyword(i int) Keyword {
+	return p.ctx.Locale.Keywords[p.word(i)]
+}
+
+// number returns word i as a positive number
+func (p *parser) number(i int) (int, bool) {
+	n, err := strconv.Atoi(p.word(i))
+	return n, err == nil && n > 0
+}
+
+// today returns midnight of the current day
+func (p *parser) today() time.Time {
+	y, m, d := p.ctx.Now.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, p.ctx.Location)
+}
+
+// matchRecurrence recognizes "daily", "every friday", "every monday and
+// thursday", "every weekday" and "every 2 weeks"
+func (p *parser) matchRecurrence(i int) (int, func()) {
+	locale := p.ctx.Locale
+	if frequency, ok := locale.Frequency[p.word(i)]; ok {
+		return 1, func() { p.rule = jscal.NewRecurrenceRule(frequency) }
+	}
+	if p.keyword(i) != KeywordEvery {
+		return 0, nil
+	}
+
+	j := i + 1
+	if p.keyword(j) == KeywordWeekday {
+		return 2, func() {
+			p.rule = weeklyOn(time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday)
+		}
+	}
+
+	var days []time.Weekday
+	for {
+		day, ok := locale.Weekdays[p.word(j)]
+		if !ok {
+			break
+		}
+		days = append(days, day)
+		j++
+		if _, ok := locale.Weekdays[p.word(j+1)]; ok && p.keyword(j) == KeywordAnd {
+			j++
+		}
+	}
+	if len(days) > 0 {
+		return j - i, func() { p.rule = weeklyOn(days...) }
+	}
+
+	interval, ok := p.number(j)
+	if ok {
+		j++
+	} else {
+		interval = 1
+	}
+	unit, ok := locale.Units[p.word(j)]
+	if !ok {
+		return 0, nil
+	}
+	return j + 1 - i, func() {
+		p.rule = jscal.NewRecurrenceRule(unit.Frequency)
+		if interval > 1 {
+			p.rule.Interval = jscal.Int(interval)
+		}
+	}
+}
+
+// weeklyOn returns a weekly rule on the given days
+func weeklyOn(days ...time.Weekday) *jscal.RecurrenceRule {
+	rule := jscal.NewRecurrenceRule(jscal.FrequencyWeekly)
+	for _, day := range days {
+		rule.ByDay = append(rule.ByDay, jscal.NDay{Day: jscal.FormatDayOfWeek(day.String())})
+	}
+	return rule
+}
+
+// matchDate recognizes "today", "tomorrow", "friday", "next friday",
+// "next week", "in 3 days", "2025-03-14", "march 14[, 2025]" and "14th
+// march [2025]", optionally after "on"
+func (p *parser) matchDate(i int) (int, func()) {
+	j := i
+	if p.keyword(j) == KeywordOn {
+		j++
+	}
+	n, date := p.readDate(j)
+	if n == 0 {
+		return 0, nil
+	}
+	return j - i + n, func() { p.date = &date }
+}
+
+// readDate reads a date starting at word i
+func (p *parser) readDate(i int) (int, time.Time) {
+	locale := p.ctx.Locale
+	today := p.today()
+
+	switch p.keyword(i) {
+	case KeywordToday:
+		return 1, today
+	case KeywordTomorrow:
+		return 1, today.AddDate(0, 0, 1)
+	case KeywordNext:
+		if day, ok := locale.Weekdays[p.word(i+1)]; ok {
+			days := (int(day)-int(today.Weekday())+6)%7 + 1
+			return 2, today.AddDate(0, 0, days)
+		}
+		if unit, ok := locale.Units[p.word(i+1)]; ok {
+			if date, ok := addUnits(today, unit, 1); ok {
+				return 2, date
+			}
+		}
+		return 0, time.Time{}
+	case KeywordIn:
+		count, ok := p.number(i + 1)
+		unit, known := locale.Units[p.word(i+2)]
+		if !ok || !known {
+			return 0, time.Time{}
+		}
+		if date, ok := addUnits(today, unit, count); ok {
+			return 3, date
+		}
+		return 0, time.Time{}
+	}
+
+	if day, ok := locale.Weekdays[p.word(i)]; ok {
+		return 1, today.AddDate(0, 0, (int(day)-int(today.Weekday())+7)%7)
+	}
+	if date, err := time.ParseInLocation("2006-01-02", p.word(i), p.ctx.Location); err == nil {
+		return 1, date
+	}
+
+	// "march 14" or "14 march", with an optional year
+	var month time.Month
+	var day int
+	if m, ok := locale.Months[p.word(i)]; ok {
+		month, day = m, dayOfMonth(p.word(i+1))
+	} else if m, ok := locale.Months[p.word(i+1)]; ok {
+		month, day = m, dayOfMonth(p.word(i))
+	}
+	if day == 0 {
+		return 0, time.Time{}
+	}
+	year, n := today.Year(), 2
+	if y, err := strconv.Atoi(p.word(i + 2)); err == nil && len(p.word(i+2)) == 4 {
+		year, n = y, 3
+	}
+	date := time.Date(year, month, day, 0, 0, 0, 0, p.ctx.Location)
+	if date.Day() != day {
+		return 0, time.Time{}
+	}
+	if n == 2 && date.Before(today) {
+		date = date.AddDate(1, 0, 0)
+	}
+	return n, date
+}
+
+// dayOfMonth parses a day of the month such as "14" or "14th", zero if it
+// isn't one
+func dayOfMonth(word string) int {
+	word = strings.TrimRight(word, "abcdefghijklmnopqrstuvwxyz.")
+	day, err := strconv.Atoi(word)
+	if err != nil || day < 1 || day > 31 {
+		return 0
+	}
+	return day
+}
+
+// addUnits adds count units of at least a day to a date
+func addUnits(date time.Time, unit Unit, count int) (time.Time, bool) {
+	switch {
+	case unit.Frequency == jscal.FrequencyMonthly:
+		return date.AddDate(0, count, 0), true
+	case unit.Frequency == jscal.FrequencyYearly:
+		return date.AddDate(count, 0, 0), true
+	case unit.Duration >= 24*time.Hour && unit.Duration%(24*time.Hour) == 0:
+		return date.AddDate(0, 0, count*int(unit.Duration/(24*time.Hour))), true
+	}
+	return time.Time{}, false
+}
+
+// matchTime recognizes times and time ranges: "12:30", "3pm", "3 pm",
+// "noon", "12:30-13:30", "3-4pm", "from 3 to 4pm". Bare numbers are only
+// times after "at" or "from".
+func (p *parser) matchTime(i int) (int, func()) {
+	j := i
+	prefixed := false
+	if k := p.keyword(j); k == KeywordAt || k == KeywordFrom {
+		j++
+		prefixed = true
+	}
+
+	var start, end clock
+	hasEnd := false
+	if a, b, ok := strings.Cut(p.word(j), "-"); ok && a != "" && b != "" {
+		var okStart, okEnd bool
+		start, okStart = p.parseClock(a)
+		end, okEnd = p.parseClock(b)
+		if !okStart || !okEnd {
+			return 0, nil
+		}
+		j++
+		if m := p.meridiem(p.word(j)); m != 0 && end.meridiem == 0 {
+			end.meridiem, end.strong = m, true
+			j++
+		}
+		hasEnd = true
+	} else {
+		n := 0
+		if start, n = p.readClock(j); n == 0 {
+			return 0, nil
+		}
+		j += n
+		if p.keyword(j) == KeywordTo {
+			if end, n = p.readClock(j + 1); n > 0 {
+				j += 1 + n
+				hasEnd = true
+			}
+		}
+	}
+	if !prefixed && !start.strong && !(hasEnd && end.strong) {
+		return 0, nil
+	}
+
+	// "3-4pm" is 15:00 to 16:00, "11-1pm" is 11:00 to 13:00
+	if hasEnd && start.meridiem == 0 && end.meridiem != 0 && start.hour >= 1 && start.hour <= 12 {
+		start.meridiem = end.meridiem
+		if start.offset() > end.offset() {
+			start.meridiem = meridiemAM
+		}
+	}
+
+	return j - i, func() {
+		startOffset := start.offset()
+		p.start, p.end = &startOffset, nil
+		if hasEnd {
+			endOffset := end.offset()
+			p.end = &endOffset
+		}
+	}
+}
+
+// readClock reads a time of day starting at word i, with am or pm as the
+// next word
+func (p *parser) readClock(i int) (clock, int) {
+	switch p.keyword(i) {
+	case KeywordNoon:
+		return clock{hour: 12, strong: true}, 1
+	case KeywordMidnight:
+		return clock{strong: true}, 1
+	}
+	c, ok := p.parseClock(p.word(i))
+	if !ok {
+		return clock{}, 0
+	}
+	if m := p.meridiem(p.word(i + 1)); m != 0 && c.meridiem == 0 && c.hour >= 1 && c.hour <= 12 {
+		c.meridiem, c.strong = m, true
+		return c, 2
+	}
+	return c, 1
+}
+
+// parseClock parses "15", "15:30", "3pm", "3:30pm", "noon" or "midnight"
+func (p *parser) parseClock(word string) (clock, bool) {
+	switch p.ctx.Locale.Keywords[word] {
+	case KeywordNoon:
+		return clock{hour: 12, strong: true}, true
+	case KeywordMidnight:
+		return clock{strong: true}, true
+	}
+
+	var c clock
+	for _, suffixes := range [][]string{p.ctx.Locale.AM, p.ctx.Locale.PM} {
+		for _, suffix := range suffixes {
+			if len(word) > len(suffix) && strings.HasSuffix(word, suffix) {
+				c.meridiem = p.meridiem(suffix)
+				word = strings.TrimSuffix(word, suffix)
+				break
+			}
+		}
+		if c.meridiem != 0 {
+			break
+		}
+	}
+
+	hours, minutes, hasMinutes := strings.Cut(word, ":")
+	var err error
+	if c.hour, err = strconv.Atoi(hours); err != nil || len(hours) > 2 {
+		return clock{}, false
+	}
+	if hasMinutes {
+		if c.minute, err = strconv.Atoi(minutes); err != nil || len(minutes) != 2 || c.minute > 59 {
+			return clock{}, false
+		}
+	}
+	if c.meridiem != 0 && (c.hour < 1 || c.hour > 12) || c.hour > 23 {
+		return clock{}, false
+	}
+	c.strong = hasMinutes || c.meridiem != 0
+	return c, true
+}
+
+// meridiem returns whether word is am or pm, zero if it is neither
+func (p *parser) meridiem(word string) int {
+	for _, am := range p.ctx.Locale.AM {
+		if word == am {
+			return meridiemAM
+		}
+	}
+	for _, pm := range p.ctx.Locale.PM {
+		if word == pm {
+			return meridiemPM
+		}
+	}
+	return 0
+}
+
+// matchDuration recognizes "for 2 hours", "for 1 hour 30 minutes", "for
+// 90min", "for 1h30" and "for 3 days"
+func (p *parser) matchDuration(i int) (int, func()) {
+	if p.keyword(i) != KeywordFor {
+		return 0, nil
+	}
+
+	var total time.Duration
+	j := i + 1
+	for {
+		if count, ok := p.number(j); ok {
+			unit, known := p.ctx.Locale.Units[p.word(j+1)]
+			if !known || unit.Duration == 0 {
+				break
+			}
+			total += time.Duration(count) * unit.Duration
+			j += 2
+		} else if d, ok := p.compactDuration(p.word(j)); ok {
+			total += d
+			j++
+		} else {
+			break
+		}
+	}
+	if total == 0 {
+		return 0, nil
+	}
+	return j - i, func() { p.duration = total }
+}
+
+// compactDuration parses durations written as one word, "90min" or "1h30",
+// where trailing minutes may leave out their unit
+func (p *parser) compactDuration(word string) (time.Duration, bool) {
+	var total time.Duration
+	var last Unit
+	for word != "" {
+		digits := len(word) - len(strings.TrimLeft(word, "0123456789"))
+		if digits == 0 {
+			return 0, false
+		}
+		count, _ := strconv.Atoi(word[:digits])
+		word = word[digits:]
+		letters := len(word) - len(strings.TrimLeft(word, "abcdefghijklmnopqrstuvwxyz"))
+		name := word[:letters]
+		word = word[letters:]
+
+		unit, ok := p.ctx.Locale.Units[name]
+		if name == "" && word == "" && last.Duration == time.Hour {
+			unit, ok = Unit{Duration: time.Minute}, true
+		}
+		if !ok || unit.Duration == 0 {
+			return 0, false
+		}
+		total += time.Duration(count) * unit.Duration
+		last = unit
+	}
+	return total, total > 0
+}
+
+// matchLocation recognizes "at Cafe Roma", the location running until the
+// next phrase or the end of the text
+func (p *parser) matchLocation(i int) (int, func()) {
+	if p.keyword(i) != KeywordAt {
+		return 0, nil
+	}
+	j := i + 1
+	for j < len(p.words) {
+		if n, _ := p.match(j); n > 0 {
+			break
+		}
+		j++
+	}
+	if j == i+1 {
+		return 0, nil
+	}
+	name := strings.TrimRight(strings.Join(p.words[i+1:j], " "), ",;")
+	return j - i, func() { p.location = name }
+}
+
+// event builds the event from what was found
+func (p *parser) event(title string) *jscal.Event {
+	date := p.today()
+	if p.date != nil {
+		date = *p.date
+	}
+	if p.rule != nil && len(p.rule.ByDay) > 0 {
+		date = firstMatchingDay(date, p.rule.ByDay)
+	}
+
+	event := jscal.NewEvent(jscal.NewUID(), title)
+	if p.start == nil {
+		event.Start = jscal.NewLocalDateTime(date)
+		event.ShowWithoutTime = jscal.Bool(true)
+		days := int((p.duration + 24*time.Hour - 1) / (24 * time.Hour))
+		if days < 1 {
+			days = 1
+		}
+		event.Duration = jscal.String(jscal.FormatDuration(time.Duration(days) * 24 * time.Hour))
+	} else {
+		start := clockTime(date, *p.start)
+		duration := p.ctx.DefaultDuration
+		if p.end != nil {
+			end := clockTime(date, *p.end)
+			if !end.After(start) {
+				end = clockTime(date.AddDate(0, 0, 1), *p.end)
+			}
+			duration = end.Sub(start)
+		} else if p.duration > 0 {
+			duration = p.duration
+		}
+		event.Start = jscal.NewLocalDateTime(start)
+		event.Duration = jscal.String(jscal.FormatDuration(duration))
+		if zone := p.ctx.Location.String(); zone != "Local" {
+			event.TimeZone = jscal.String(zone)
+		}
+	}
+
+	if p.location != "" {
+		event.AddLocation("1", jscal.NewLocation(p.location))
+	}
+	if p.rule != nil {
+		event.RecurrenceRules = append(event.RecurrenceRules, *p.rule)
+	}
+	return event
+}
+
+// firstMatchingDay returns the first date on or after date falling on one
+// of the days
+func firstMatchingDay(date time.Time, days []jscal.NDay) time.Time {
+	for i := 0; i < 7; i++ {
+		candidate := date.AddDate(0, 0, i)
+		weekday := jscal.FormatDayOfWeek(candidate.Weekday().String())
+		for _, day := range days {
+			if day.Day == weekday {
+				return candidate
+			}
+		}
+	}
+	return date
+}
+
+// clockTime returns the time offset from midnight on the clock of date
+func clockTime(date time.Time, offset time.Duration) time.Time {
+	y, m, d := date.Date()
+	return time.Date(y, m, d, int(offset/time.Hour), int(offset%time.Hour/time.Minute), 0, 0, date.Location())
+}
@@ -0,0 +1,174 @@
+package quickadd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/airtrafik/jscal"
+)
+
+// monday is the current time of the tests, Monday 3 March 2025
+var monday = time.Date(2025, 3, 3, 10, 0, 0, 0, time.UTC)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		text     string
+		title    string
+		start    string
+		duration string
+		allDay   bool
+		location string
+		rule     string // Frequency and days of the recurrence rule, if any
+	}{
+		{
+			text:     "Lunch with Bob tomorrow 12:30-13:30 at Cafe Roma every Friday",
+			title:    "Lunch with Bob",
+			start:    "2025-03-07T12:30:00",
+			duration: "PT1H",
+			location: "Cafe Roma",
+			rule:     "weekly fr",
+		},
+		{text: "Dentist friday 3pm", title: "Dentist", start: "2025-03-07T15:00:00", duration: "PT1H"},
+		{text: "Standup at 9:15 for 15 min", title: "Standup", start: "2025-03-03T09:15:00", duration: "PT15M"},
+		{text: "Call at 17", title: "Call", start: "2025-03-03T17:00:00", duration: "PT1H"},
+		{text: "Review 3-4pm next monday", title: "Review", start: "2025-03-10T15:00:00", duration: "PT1H"},
+		{text: "Workshop from 11 to 1pm on march 14", title: "Workshop", start: "2025-03-14T11:00:00", duration: "PT2H"},
+		{text: "Party 22:00-02:00 on 2025-03-08", title: "Party", start: "2025-03-08T22:00:00", duration: "PT4H"},
+		{text: "Lunch noon for 1h30", title: "Lunch", start: "2025-03-03T12:00:00", duration: "PT1H30M"},
+		{text: "Meeting 4 pm @ Room 101", title: "Meeting", start: "2025-03-03T16:00:00", duration: "PT1H", location: "Room 101"},
+		{text: "Birthday 2nd february", title: "Birthday", start: "2026-02-02T00:00:00", duration: "P1D", allDay: true},
+		{text: "Conference in 2 weeks for 3 days", title: "Conference", start: "2025-03-17T00:00:00", duration: "P3D", allDay: true},
+		{text: "Gym every monday and thursday 7am", title: "Gym", start: "2025-03-03T07:00:00", duration: "PT1H", rule: "weekly mo th"},
+		{text: "Timesheet every weekday 16:45", title: "Timesheet", start: "2025-03-03T16:45:00", duration: "PT1H", rule: "weekly mo tu we th fr"},
+		{text: "Rent every month", title: "Rent", start: "2025-03-03T00:00:00", duration: "P1D", allDay: true, rule: "monthly"},
+		{text: "Backup daily at midnight", title: "Backup", start: "2025-03-03T00:00:00", duration: "PT1H", rule: "daily"},
+		{text: "Buy 2 apples", title: "Buy 2 apples", start: "2025-03-03T00:00:00", duration: "P1D", allDay: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			event, err := Parse(tt.text, Context{Now: monday})
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			if event.GetTitle() != tt.title {
+				t.Errorf("Expected title %q, got %q", tt.title, event.GetTitle())
+			}
+			if event.Start.String() != tt.start {
+				t.Errorf("Expected start %s, got %s", tt.start, event.Start.String())
+			}
+			if event.Duration == nil || *event.Duration != tt.duration {
+				t.Errorf("Expected duration %s, got %v", tt.duration, event.Duration)
+			}
+			if event.IsAllDay() != tt.allDay {
+				t.Errorf("Expected all-day %v, got %v", tt.allDay, event.IsAllDay())
+			}
+			if tt.allDay && event.TimeZone != nil {
+				t.Errorf("Expected no time zone for all-day event, got %s", *event.TimeZone)
+			}
+			if !tt.allDay && (event.TimeZone == nil || *event.TimeZone != "UTC") {
+				t.Errorf("Expected time zone UTC, got %v", event.TimeZone)
+			}
+
+			location := ""
+			if loc := event.Locations["1"]; loc != nil && loc.Name != nil {
+				location = *loc.Name
+			}
+			if location != tt.location {
+				t.Errorf("Expected location %q, got %q", tt.location, location)
+			}
+
+			rule := ""
+			if len(event.RecurrenceRules) > 0 {
+				rule = event.RecurrenceRules[0].Frequency
+				for _, day := range event.RecurrenceRules[0].ByDay {
+					rule += " " + day.Day
+				}
+			}
+			if rule != tt.rule {
+				t.Errorf("Expected rule %q, got %q", tt.rule, rule)
+			}
+
+			if errs := event.Validate(); errs != nil {
+				t.Errorf("Expected valid event, got %v", errs)
+			}
+		})
+	}
+}
+
+func TestParseInterval(t *testing.T) {
+	event, err := Parse("Payday every 2 weeks", Context{Now: monday})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(event.RecurrenceRules) != 1 {
+		t.Fatalf("Expected 1 recurrence rule, got %d", len(event.RecurrenceRules))
+	}
+	rule := event.RecurrenceRules[0]
+	if rule.Frequency != jscal.FrequencyWeekly || rule.Interval == nil || *rule.Interval != 2 {
+		t.Errorf("Expected weekly rule with interval 2, got %s %v", rule.Frequency, rule.Interval)
+	}
+}
+
+func TestParseContext(t *testing.T) {
+	zone := time.FixedZone("UTC+9", 9*60*60)
+
+	// 23:00 UTC on Monday is Tuesday morning nine hours ahead
+	now := time.Date(2025, 3, 3, 23, 0, 0, 0, time.UTC)
+	event, err := Parse("Breakfast tomorrow 8am", Context{Now: now, Location: zone, DefaultDuration: 30 * time.Minute})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if event.Start.String() != "2025-03-05T08:00:00" {
+		t.Errorf("Expected start 2025-03-05T08:00:00, got %s", event.Start.String())
+	}
+	if *event.Duration != "PT30M" {
+		t.Errorf("Expected duration PT30M, got %s", *event.Duration)
+	}
+	if *event.TimeZone != "UTC+9" {
+		t.Errorf("Expected time zone UTC+9, got %s", *event.TimeZone)
+	}
+}
+
+func TestParseLocale(t *testing.T) {
+	german := &Locale{
+		Keywords: map[string]Keyword{
+			"morgen": KeywordTomorrow,
+			"um":     KeywordAt,
+			"in":     KeywordAt,
+			"jeden":  KeywordEvery,
+		},
+		Weekdays: map[string]time.Weekday{"freitag": time.Friday},
+		Units:    map[string]Unit{"stunden": {Duration: time.Hour}},
+	}
+
+	event, err := Parse("Mittagessen morgen um 12:30 in Berlin", Context{Now: monday, Locale: german})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if event.GetTitle() != "Mittagessen" {
+		t.Errorf("Expected title Mittagessen, got %q", event.GetTitle())
+	}
+	if event.Start.String() != "2025-03-04T12:30:00" {
+		t.Errorf("Expected start 2025-03-04T12:30:00, got %s", event.Start.String())
+	}
+	if loc := event.Locations["1"]; loc == nil || *loc.Name != "Berlin" {
+		t.Errorf("Expected location Berlin, got %v", loc)
+	}
+
+	event, err = Parse("Sport jeden Freitag", Context{Now: monday, Locale: german})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(event.RecurrenceRules) != 1 || len(event.RecurrenceRules[0].ByDay) != 1 || event.RecurrenceRules[0].ByDay[0].Day != "fr" {
+		t.Errorf("Expected weekly rule on fr, got %v", event.RecurrenceRules)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, text := range []string{"", "   ", "tomorrow 3pm", "every friday at 9:00"} {
+		if _, err := Parse(text, Context{Now: monday}); err == nil {
+			t.Errorf("Expected error for %q", text)
+		}
+	}
+}
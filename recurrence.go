@@ -0,0 +1,653 @@
+package jscal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Occurrence is a single instance of a recurring event, as returned by
+// Event.Occurrences
+type Occurrence struct {
+	RecurrenceId LocalDateTime // Start produced by the rules; the key in recurrenceOverrides
+	Event        *Event        // The instance, with its override applied
+	Overridden   []string      // Paths patched by the override, sorted
+}
+
+// Start returns the start of the instance, which an override may have moved
+func (o *Occurrence) Start() LocalDateTime {
+	if o.Event.Start != nil {
+		return *o.Event.Start
+	}
+	return o.RecurrenceId
+}
+
+// End returns the start of the instance plus its duration, added in the
+// instance's time zone
+func (o *Occurrence) End() LocalDateTime {
+	start := o.Start()
+	if o.Event.Duration != nil {
+		if end, err := start.AddDurationInZone(*o.Event.Duration, eventTimeZone(o.Event)); err == nil {
+			return end
+		}
+	}
+	return start
+}
+
+// Occurrences expands the event's recurrence (RFC 8984 Section 4.3) and
+// returns the instances starting at or after from and before to, in start
+// order. The start is always the first occurrence. Instances matched by
+// excludedRecurrenceRules, or overridden with "excluded": true, are left
+// out; other overrides are applied as patches, and overrides for
+// date-times the rules don't produce add extra instances.
+//
+// A non-recurring event has a single occurrence at its start.
+func (e *Event) Occurrences(from, to LocalDateTime) ([]*Occurrence, error) {
+	return e.OccurrencesContext(context.Background(), from, to)
+}
+
+// OccurrencesContext is like Occurrences but stops with the context's error
+// once ctx is done, for rules producing many instances over a wide range
+func (e *Event) OccurrencesContext(ctx context.Context, from, to LocalDateTime) ([]*Occurrence, error) {
+	if e.Start == nil {
+		return nil, fmt.Errorf("event %s has no start", e.UID)
+	}
+
+	ids, overrides, err := recurrenceSet(ctx, *e.Start, e.RecurrenceRules, e.ExcludedRecurrenceRules, e.RecurrenceOverrides, to)
+	if err != nil {
+		return nil, fmt.Errorf("event %s: %w", e.UID, err)
+	}
+
+	master, err := instanceBase(e)
+	if err != nil {
+		return nil, err
+	}
+
+	lower, upper := wallClock(from), wallClock(to)
+	var occurrences []*Occurrence
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		// Only build the instances starting in range, which for long
+		// series are a few of the ids
+		if start, ok := overrideStart(id, overrides[id]); ok && (start.Before(lower) || !start.Before(upper)) {
+			continue
+		}
+		occurrence, err := newOccurrence(master, id, overrides[id])
+		if err != nil {
+			return nil, fmt.Errorf("event %s: %w", e.UID, err)
+		}
+		start := wallClock(occurrence.Start())
+		if !start.Before(lower) && start.Before(upper) {
+			occurrences = append(occurrences, occurrence)
+		}
+	}
+
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		return wallClock(occurrences[i].Start()).Before(wallClock(occurrences[j].Start()))
+	})
+	return occurrences, nil
+}
+
+// Instance returns the instance of the event with the given recurrence id:
+// a copy without recurrence properties, with recurrenceId and start set to
+// id and the matching recurrenceOverrides patch applied. It doesn't check
+// that the rules produce id; excluded instances are an error.
+func (e *Event) Instance(id LocalDateTime) (*Event, error) {
+	var patch map[string]interface{}
+	for key, p := range e.RecurrenceOverrides {
+		if overrideId, err := ParseLocalDateTime(key); err == nil && wallClock(*overrideId).Equal(wallClock(id)) {
+			patch = p
+			break
+		}
+	}
+	if excluded, _ := patch["excluded"].(bool); excluded {
+		return nil, fmt.Errorf("event %s: instance %s is excluded", e.UID, id)
+	}
+
+	base, err := instanceBase(e)
+	if err != nil {
+		return nil, err
+	}
+	occurrence, err := newOccurrence(base, LocalDateTime(wallClock(id)), patch)
+	if err != nil {
+		return nil, fmt.Errorf("event %s: %w", e.UID, err)
+	}
+	return occurrence.Event, nil
+}
+
+// recurrenceSet returns the sorted recurrence ids of an object up to (but
+// not including) to, along with the overrides that apply, keyed by id.
+// Overridden ids are always included so overrides moving an instance into
+// range are found.
+func recurrenceSet(ctx context.Context, start LocalDateTime, rules, excludedRules []RecurrenceRule,
+	overrides map[string]map[string]interface{}, to LocalDateTime) ([]LocalDateTime, map[LocalDateTime]map[string]interface{}, error) {
+	first := wallClock(start)
+	limit := wallClock(to)
+
+	patches := make(map[LocalDateTime]map[string]interface{}, len(overrides))
+	for key, patch := range overrides {
+		id, err := ParseLocalDateTime(key)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid recurrenceOverrides key %s", key)
+		}
+		t := wallClock(*id)
+		patches[LocalDateTime(t)] = patch
+		if !t.Before(limit) {
+			limit = t.Add(time.Nanosecond)
+		}
+	}
+
+	set := map[time.Time]bool{first: true}
+	for i := range rules {
+		times, err := expandRule(ctx, &rules[i], first, limit, true)
+		if err != nil {
+			return nil, nil, fmt.Errorf("recurrenceRules[%d]: %w", i, err)
+		}
+		for _, t := range times {
+			set[t] = true
+		}
+	}
+	for i := range excludedRules {
+		times, err := expandRule(ctx, &excludedRules[i], first, limit, false)
+		if err != nil {
+			return nil, nil, fmt.Errorf("excludedRecurrenceRules[%d]: %w", i, err)
+		}
+		for _, t := range times {
+			delete(set, t)
+		}
+	}
+
+	for id, patch := range patches {
+		if excluded, _ := patch["excluded"].(bool); excluded {
+			delete(set, time.Time(id))
+			delete(patches, id)
+			continue
+		}
+		set[time.Time(id)] = true
+	}
+
+	ids := make([]LocalDateTime, 0, len(set))
+	for t := range set {
+		ids = append(ids, LocalDateTime(t))
+	}
+	sort.Slice(ids, func(i, j int) bool { return time.Time(ids[i]).Before(time.Time(ids[j])) })
+	return ids, patches, nil
+}
+
+// instanceBase returns the JSON representation of an event without its
+// recurrence properties, from which instances are built
+func instanceBase(e *Event) (map[string]interface{}, error) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	var base map[string]interface{}
+	if err := json.Unmarshal(data, &base); err != nil {
+		return nil, err
+	}
+	for _, name := range []string{"recurrenceRules", "excludedRecurrenceRules", "recurrenceOverrides"} {
+		delete(base, name)
+	}
+	return base, nil
+}
+
+// newOccurrence builds the instance with the given recurrence id
+func newOccurrence(base map[string]interface{}, id LocalDateTime, patch map[string]interface{}) (*Occurrence, error) {
+	instance := make(map[string]interface{}, len(base)+2)
+	for k, v := range base {
+		instance[k] = v
+	}
+	instance["recurrenceId"] = id.String()
+	instance["start"] = id.String()
+
+	// Patches may modify nested objects, so give them a private copy
+	if len(patch) > 0 {
+		data, _ := json.Marshal(instance)
+		instance = nil
+		_ = json.Unmarshal(data, &instance)
+	}
+	if err := applyPatch(instance, patch); err != nil {
+		return nil, fmt.Errorf("recurrenceOverrides[%s]: %w", id, err)
+	}
+
+	data, err := json.Marshal(instance)
+	if err != nil {
+		return nil, err
+	}
+	var event Event
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("recurrenceOverrides[%s]: %w", id, err)
+	}
+	return &Occurrence{RecurrenceId: id, Event: &event, Overridden: sortedKeys(patch)}, nil
+}
+
+// overrideStart returns the wall clock start of the instance id once patch
+// is applied: id, or the start the patch moves it to. It returns false if
+// the patch sets a start that can't be read.
+func overrideStart(id LocalDateTime, patch map[string]interface{}) (time.Time, bool) {
+	value, ok := patch["start"]
+	if !ok {
+		return wallClock(id), true
+	}
+	s, ok := value.(string)
+	if !ok {
+		return time.Time{}, false
+	}
+	start, err := ParseLocalDateTime(s)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return wallClock(*start), true
+}
+
+// wallClock returns the wall clock time of a LocalDateTime in UTC, so
+// times can be compared and used as map keys regardless of the location
+// they were created in
+func wallClock(ldt LocalDateTime) time.Time {
+	t := time.Time(ldt)
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// weekdays maps NDay day names to time.Weekday
+var weekdays = map[string]time.Weekday{
+	DayMonday: time.Monday, DayTuesday: time.Tuesday, DayWednesday: time.Wednesday,
+	DayThursday: time.Thursday, DayFriday: time.Friday, DaySaturday: time.Saturday, DaySunday: time.Sunday,
+}
+
+// expandRule returns the date-times a recurrence rule produces from start
+// up to (but not including) limit. Times are wall clock times in UTC. With
+// withStart, start is the first result and counts towards the rule's count
+// whether or not the rule matches it, as for recurrenceRules; otherwise it's
+// only included if it matches, as for excludedRecurrenceRules. It returns the
+// context's error once ctx is done.
+func expandRule(ctx context.Context, rule *RecurrenceRule, start, limit time.Time, withStart bool) ([]time.Time, error) {
+	r, err := newRuleSet(rule, start)
+	if err != nil {
+		return nil, err
+	}
+
+	var until time.Time
+	if rule.Until != nil {
+		until = wallClock(*rule.Until)
+	}
+
+	var results []time.Time
+	if withStart {
+		results = append(results, start)
+	}
+	done := func() bool { return rule.Count != nil && len(results) >= *rule.Count }
+	if done() {
+		return results, nil
+	}
+
+	for n := 0; ; n++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		periodStart, candidates := r.period(n * r.interval)
+		if !periodStart.Before(limit) || (!until.IsZero() && periodStart.After(until)) {
+			break
+		}
+
+		for _, t := range r.setPos(candidates) {
+			if t.Before(start) || (withStart && t.Equal(start)) {
+				continue
+			}
+			if !t.Before(limit) || (!until.IsZero() && t.After(until)) {
+				return results, nil
+			}
+			results = append(results, t)
+			if done() {
+				return results, nil
+			}
+		}
+	}
+	return results, nil
+}
+
+// maxCountYears bounds the expansion of rules with a count in countsToUntil
+const maxCountYears = 1000
+
+// countsToUntil replaces the count of rules expanded from start with the
+// until of their last instance, so that they keep their instances when
+// expanded from a later start. withStart is as for expandRule.
+func countsToUntil(rules []RecurrenceRule, start LocalDateTime, withStart bool) error {
+	first := wallClock(start)
+	for i := range rules {
+		rule := &rules[i]
+		if rule.Count == nil {
+			continue
+		}
+		times, err := expandRule(context.Background(), rule, first, first.AddDate(maxCountYears, 0, 0), withStart)
+		if err != nil {
+			return err
+		}
+		if len(times) == 0 {
+			continue
+		}
+		rule.Until = NewLocalDateTime(times[len(times)-1])
+		rule.Count = nil
+	}
+	return nil
+}
+
+// ruleSet is a recurrence rule prepared for expansion
+type ruleSet struct {
+	rule      *RecurrenceRule
+	start     time.Time
+	interval  int
+	weekStart time.Weekday
+	months    map[time.Month]bool
+}
+
+// newRuleSet validates the parts of a rule the expansion depends on
+func newRuleSet(rule *RecurrenceRule, start time.Time) (*ruleSet, error) {
+	r := &ruleSet{rule: rule, start: start, interval: 1, weekStart: time.Monday}
+
+	switch rule.Frequency {
+	case FrequencyYearly, FrequencyMonthly, FrequencyWeekly, FrequencyDaily,
+		FrequencyHourly, FrequencyMinutely, FrequencySecondly:
+	default:
+		return nil, fmt.Errorf("unsupported frequency %q", rule.Frequency)
+	}
+	if rule.RScale != nil && *rule.RScale != "" && !strings.EqualFold(*rule.RScale, "gregorian") {
+		return nil, fmt.Errorf("unsupported rscale %q", *rule.RScale)
+	}
+	if rule.Interval != nil {
+		if *rule.Interval < 1 {
+			return nil, fmt.Errorf("interval must be at least 1")
+		}
+		r.interval = *rule.Interval
+	}
+	if rule.FirstDayOfWeek != nil {
+		r.weekStart = time.Weekday((1 + *rule.FirstDayOfWeek) % 7)
+	}
+	for _, nday := range rule.ByDay {
+		if _, ok := weekdays[strings.ToLower(nday.Day)]; !ok {
+			return nil, fmt.Errorf("invalid byDay day %q", nday.Day)
+		}
+	}
+	if len(rule.ByMonth) > 0 {
+		r.months = make(map[time.Month]bool)
+		for _, m := range rule.ByMonth {
+			// Leap months ("5L") don't exist in the Gregorian calendar
+			month, err := strconv.Atoi(m)
+			if err != nil && !strings.HasSuffix(m, "L") {
+				return nil, fmt.Errorf("invalid byMonth value %q", m)
+			}
+			if err == nil {
+				r.months[time.Month(month)] = true
+			}
+		}
+	}
+	return r, nil
+}
+
+// period returns the start of the nth period after the one containing the
+// rule's start, and the candidate date-times in it, sorted
+func (r *ruleSet) period(n int) (time.Time, []time.Time) {
+	s := r.start
+	date := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+
+	var first, end time.Time
+	switch r.rule.Frequency {
+	case FrequencyYearly:
+		first, end = date(s.Year()+n, 1, 1), date(s.Year()+n+1, 1, 1)
+		if len(r.rule.ByWeekNo) > 0 {
+			first, end = r.week1(s.Year()+n), r.week1(s.Year()+n+1)
+		}
+	case FrequencyMonthly:
+		first = date(s.Year(), s.Month()+time.Month(n), 1)
+		end = first.AddDate(0, 1, 0)
+	case FrequencyWeekly:
+		day := date(s.Year(), s.Month(), s.Day())
+		first = day.AddDate(0, 0, -int((day.Weekday()-r.weekStart+7)%7)+7*n)
+		end = first.AddDate(0, 0, 7)
+	case FrequencyDaily:
+		first = date(s.Year(), s.Month(), s.Day()+n)
+		end = first.AddDate(0, 0, 1)
+	default:
+		return r.subDailyPeriod(n)
+	}
+
+	var candidates []time.Time
+	for day := first; day.Before(end); day = day.AddDate(0, 0, 1) {
+		if r.dayMatches(day) {
+			candidates = append(candidates, r.timesOfDay(day)...)
+		}
+	}
+	return first, candidates
+}
+
+// subDailyPeriod handles hourly, minutely and secondly rules, whose periods
+// hold at most one date. byHour, byMinute and bySecond limit the periods of
+// the frequency they name and expand the shorter ones.
+func (r *ruleSet) subDailyPeriod(n int) (time.Time, []time.Time) {
+	s, rule := r.start, r.rule
+	var first time.Time
+	minutes, seconds := rule.ByMinute, rule.BySecond
+	switch rule.Frequency {
+	case FrequencyHourly:
+		first = time.Date(s.Year(), s.Month(), s.Day(), s.Hour()+n, 0, 0, 0, time.UTC)
+	case FrequencyMinutely:
+		first = time.Date(s.Year(), s.Month(), s.Day(), s.Hour(), s.Minute()+n, 0, 0, time.UTC)
+		minutes = []int{first.Minute()}
+	default:
+		first = time.Date(s.Year(), s.Month(), s.Day(), s.Hour(), s.Minute(), s.Second()+n, 0, time.UTC)
+		minutes, seconds = []int{first.Minute()}, []int{first.Second()}
+	}
+
+	day := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, time.UTC)
+	if !r.dayMatches(day) || !matchesAny(rule.ByHour, first.Hour()) ||
+		(rule.Frequency != FrequencyHourly && !matchesAny(rule.ByMinute, first.Minute())) ||
+		(rule.Frequency == FrequencySecondly && !matchesAny(rule.BySecond, first.Second())) {
+		return first, nil
+	}
+	if len(minutes) == 0 {
+		minutes = []int{s.Minute()}
+	}
+	if len(seconds) == 0 {
+		seconds = []int{s.Second()}
+	}
+
+	var candidates []time.Time
+	for _, m := range sortedInts(minutes) {
+		for _, sec := range sortedInts(seconds) {
+			if m < 60 && sec < 60 {
+				candidates = append(candidates, time.Date(first.Year(), first.Month(), first.Day(), first.Hour(), m, sec, s.Nanosecond(), time.UTC))
+			}
+		}
+	}
+	return first, candidates
+}
+
+// timesOfDay returns the times a matching day of a yearly to daily rule
+// expands to, from byHour, byMinute and bySecond or the start's time of day
+func (r *ruleSet) timesOfDay(day time.Time) []time.Time {
+	hours, minutes, seconds := r.rule.ByHour, r.rule.ByMinute, r.rule.BySecond
+	if len(hours) == 0 {
+		hours = []int{r.start.Hour()}
+	}
+	if len(minutes) == 0 {
+		minutes = []int{r.start.Minute()}
+	}
+	if len(seconds) == 0 {
+		seconds = []int{r.start.Second()}
+	}
+
+	var times []time.Time
+	for _, h := range sortedInts(hours) {
+		for _, m := range sortedInts(minutes) {
+			for _, sec := range sortedInts(seconds) {
+				if h < 24 && m < 60 && sec < 60 {
+					times = append(times, time.Date(day.Year(), day.Month(), day.Day(), h, m, sec, r.start.Nanosecond(), time.UTC))
+				}
+			}
+		}
+	}
+	return times
+}
+
+// dayMatches reports whether a day passes the rule's date parts. Without
+// any, yearly rules repeat on the start's month and day, monthly rules on
+// its day of the month and weekly rules on its weekday.
+func (r *ruleSet) dayMatches(day time.Time) bool {
+	rule := r.rule
+	if r.months != nil && !r.months[day.Month()] {
+		return false
+	}
+	if len(rule.ByWeekNo) > 0 && rule.Frequency == FrequencyYearly && !r.matchesWeekNo(day) {
+		return false
+	}
+	if len(rule.ByYearDay) > 0 && !matchesOrdinal(rule.ByYearDay, day.YearDay(), daysIn(day.Year(), 0)) {
+		return false
+	}
+	if len(rule.ByMonthDay) > 0 && !matchesOrdinal(rule.ByMonthDay, day.Day(), daysIn(day.Year(), day.Month())) {
+		return false
+	}
+	if len(rule.ByDay) > 0 && !r.matchesDay(day) {
+		return false
+	}
+
+	switch rule.Frequency {
+	case FrequencyYearly:
+		switch {
+		case len(rule.ByYearDay)+len(rule.ByMonthDay)+len(rule.ByDay) > 0:
+			return true
+		case len(rule.ByWeekNo) > 0:
+			return day.Weekday() == r.start.Weekday()
+		case r.months == nil && day.Month() != r.start.Month():
+			return false
+		}
+		return day.Day() == r.start.Day()
+	case FrequencyMonthly:
+		if len(rule.ByYearDay)+len(rule.ByMonthDay)+len(rule.ByDay) == 0 {
+			return day.Day() == r.start.Day()
+		}
+	case FrequencyWeekly:
+		if len(rule.ByDay) == 0 {
+			return day.Weekday() == r.start.Weekday()
+		}
+	}
+	return true
+}
+
+// matchesDay applies byDay. nthOfPeriod counts within the month for
+// monthly rules and yearly rules with byMonth, within the year for other
+// yearly rules, and is ignored otherwise.
+func (r *ruleSet) matchesDay(day time.Time) bool {
+	for _, nday := range r.rule.ByDay {
+		if weekdays[strings.ToLower(nday.Day)] != day.Weekday() {
+			continue
+		}
+		if nday.NthOfPeriod == nil || *nday.NthOfPeriod == 0 {
+			return true
+		}
+
+		var index, length int
+		switch {
+		case r.rule.Frequency == FrequencyMonthly || (r.rule.Frequency == FrequencyYearly && r.months != nil):
+			index, length = day.Day(), daysIn(day.Year(), day.Month())
+		case r.rule.Frequency == FrequencyYearly && len(r.rule.ByWeekNo) == 0:
+			index, length = day.YearDay(), daysIn(day.Year(), 0)
+		default:
+			return true
+		}
+		nth := *nday.NthOfPeriod
+		if (nth > 0 && (index-1)/7+1 == nth) || (nth < 0 && -((length-index)/7+1) == nth) {
+			return true
+		}
+	}
+	return false
+}
+
+// week1 returns the first day of week 1 of a year: the week, starting on
+// the rule's first day of the week, that has at least four days in the year
+func (r *ruleSet) week1(year int) time.Time {
+	jan1 := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	offset := int((jan1.Weekday() - r.weekStart + 7) % 7)
+	if offset <= 3 {
+		return jan1.AddDate(0, 0, -offset)
+	}
+	return jan1.AddDate(0, 0, 7-offset)
+}
+
+// matchesWeekNo applies byWeekNo to a day of a yearly period, which runs
+// from week 1 of one year to week 1 of the next
+func (r *ruleSet) matchesWeekNo(day time.Time) bool {
+	year := day.Year()
+	if day.Before(r.week1(year)) {
+		year--
+	} else if !day.Before(r.week1(year + 1)) {
+		year++
+	}
+	first := r.week1(year)
+	weeks := int(r.week1(year+1).Sub(first).Hours()/24) / 7
+	week := int(day.Sub(first).Hours()/24)/7 + 1
+	return matchesOrdinal(r.rule.ByWeekNo, week, weeks)
+}
+
+// setPos applies bySetPos to the sorted candidates of a period
+func (r *ruleSet) setPos(candidates []time.Time) []time.Time {
+	if len(r.rule.BySetPos) == 0 || len(candidates) == 0 {
+		return candidates
+	}
+	var selected []time.Time
+	for i, t := range candidates {
+		if matchesOrdinal(r.rule.BySetPos, i+1, len(candidates)) {
+			selected = append(selected, t)
+		}
+	}
+	return selected
+}
+
+// matchesOrdinal reports whether a 1-based position in a sequence of the
+// given length matches any value, where negative values count from the end
+func matchesOrdinal(values []int, position, length int) bool {
+	for _, v := range values {
+		if v == position || (v < 0 && length+v+1 == position) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAny reports whether values is empty or contains v
+func matchesAny(values []int, v int) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+// daysIn returns the number of days in a month, or in the year if month is 0
+func daysIn(year int, month time.Month) int {
+	if month == 0 {
+		return time.Date(year, 12, 31, 0, 0, 0, 0, time.UTC).YearDay()
+	}
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// sortedInts returns a sorted copy of values without duplicates
+func sortedInts(values []int) []int {
+	sorted := append([]int(nil), values...)
+	sort.Ints(sorted)
+	unique := sorted[:0]
+	for i, v := range sorted {
+		if i == 0 || v != sorted[i-1] {
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,386 @@
+package jscal
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// mustLocal parses a LocalDateTime or fails the test
+func mustLocal(t *testing.T, s string) LocalDateTime {
+	t.Helper()
+	ldt, err := ParseLocalDateTime(s)
+	if err != nil {
+		t.Fatalf("invalid LocalDateTime %s: %v", s, err)
+	}
+	return *ldt
+}
+
+// occurrenceStarts renders the starts of occurrences as a space separated list
+func occurrenceStarts(occurrences []*Occurrence) string {
+	starts := make([]string, len(occurrences))
+	for i, o := range occurrences {
+		starts[i] = o.Start().String()
+	}
+	return strings.Join(starts, " ")
+}
+
+func nthDay(day string, nth int) NDay {
+	return NDay{Day: day, NthOfPeriod: Int(nth)}
+}
+
+func TestOccurrences(t *testing.T) {
+	tests := []struct {
+		name  string
+		start string
+		rule  RecurrenceRule
+		from  string
+		to    string
+		want  string
+	}{
+		{
+			name:  "daily count",
+			start: "2025-01-01T09:00:00",
+			rule:  RecurrenceRule{Frequency: FrequencyDaily, Count: Int(3)},
+			from:  "2025-01-01T00:00:00",
+			to:    "2026-01-01T00:00:00",
+			want:  "2025-01-01T09:00:00 2025-01-02T09:00:00 2025-01-03T09:00:00",
+		},
+		{
+			name:  "daily window",
+			start: "2025-01-01T09:00:00",
+			rule:  RecurrenceRule{Frequency: FrequencyDaily, Interval: Int(2)},
+			from:  "2025-01-04T00:00:00",
+			to:    "2025-01-09T09:00:00",
+			want:  "2025-01-05T09:00:00 2025-01-07T09:00:00",
+		},
+		{
+			name:  "weekly byDay",
+			start: "2025-01-06T10:00:00", // Monday
+			rule:  RecurrenceRule{Frequency: FrequencyWeekly, ByDay: []NDay{{Day: DayMonday}, {Day: DayThursday}}, Count: Int(4)},
+			from:  "2025-01-01T00:00:00",
+			to:    "2026-01-01T00:00:00",
+			want:  "2025-01-06T10:00:00 2025-01-09T10:00:00 2025-01-13T10:00:00 2025-01-16T10:00:00",
+		},
+		{
+			name:  "monthly last friday",
+			start: "2025-01-31T12:00:00",
+			rule:  RecurrenceRule{Frequency: FrequencyMonthly, ByDay: []NDay{nthDay(DayFriday, -1)}, Count: Int(3)},
+			from:  "2025-01-01T00:00:00",
+			to:    "2026-01-01T00:00:00",
+			want:  "2025-01-31T12:00:00 2025-02-28T12:00:00 2025-03-28T12:00:00",
+		},
+		{
+			name:  "monthly last weekday",
+			start: "2025-01-31T08:00:00",
+			rule: RecurrenceRule{
+				Frequency: FrequencyMonthly,
+				ByDay:     []NDay{{Day: DayMonday}, {Day: DayTuesday}, {Day: DayWednesday}, {Day: DayThursday}, {Day: DayFriday}},
+				BySetPos:  []int{-1},
+			},
+			from: "2025-01-01T00:00:00",
+			to:   "2025-07-01T00:00:00",
+			want: "2025-01-31T08:00:00 2025-02-28T08:00:00 2025-03-31T08:00:00 2025-04-30T08:00:00 2025-05-30T08:00:00 2025-06-30T08:00:00",
+		},
+		{
+			name:  "monthly skips short months",
+			start: "2025-01-31T08:00:00",
+			rule:  RecurrenceRule{Frequency: FrequencyMonthly, Count: Int(3)},
+			from:  "2025-01-01T00:00:00",
+			to:    "2026-01-01T00:00:00",
+			want:  "2025-01-31T08:00:00 2025-03-31T08:00:00 2025-05-31T08:00:00",
+		},
+		{
+			name:  "yearly",
+			start: "2024-02-29T00:00:00",
+			rule:  RecurrenceRule{Frequency: FrequencyYearly},
+			from:  "2024-01-01T00:00:00",
+			to:    "2033-01-01T00:00:00",
+			want:  "2024-02-29T00:00:00 2028-02-29T00:00:00 2032-02-29T00:00:00",
+		},
+		{
+			name:  "yearly thanksgiving",
+			start: "2025-11-27T00:00:00",
+			rule:  RecurrenceRule{Frequency: FrequencyYearly, ByMonth: []string{"11"}, ByDay: []NDay{nthDay(DayThursday, 4)}},
+			from:  "2025-01-01T00:00:00",
+			to:    "2028-01-01T00:00:00",
+			want:  "2025-11-27T00:00:00 2026-11-26T00:00:00 2027-11-25T00:00:00",
+		},
+		{
+			name:  "yearly byWeekNo",
+			start: "2025-01-06T09:00:00", // Monday of week 2
+			rule:  RecurrenceRule{Frequency: FrequencyYearly, ByWeekNo: []int{2}, Count: Int(3)},
+			from:  "2025-01-01T00:00:00",
+			to:    "2030-01-01T00:00:00",
+			want:  "2025-01-06T09:00:00 2026-01-05T09:00:00 2027-01-11T09:00:00",
+		},
+		{
+			name:  "until",
+			start: "2025-03-01T18:00:00",
+			rule:  RecurrenceRule{Frequency: FrequencyWeekly, Until: NewLocalDateTime(time.Date(2025, 3, 15, 18, 0, 0, 0, time.UTC))},
+			from:  "2025-01-01T00:00:00",
+			to:    "2026-01-01T00:00:00",
+			want:  "2025-03-01T18:00:00 2025-03-08T18:00:00 2025-03-15T18:00:00",
+		},
+		{
+			name:  "hourly byMinute",
+			start: "2025-01-01T09:00:00",
+			rule:  RecurrenceRule{Frequency: FrequencyHourly, ByMinute: []int{0, 30}, Count: Int(4)},
+			from:  "2025-01-01T00:00:00",
+			to:    "2025-01-02T00:00:00",
+			want:  "2025-01-01T09:00:00 2025-01-01T09:30:00 2025-01-01T10:00:00 2025-01-01T10:30:00",
+		},
+		{
+			name:  "daily byHour",
+			start: "2025-01-01T09:00:00",
+			rule:  RecurrenceRule{Frequency: FrequencyDaily, ByHour: []int{9, 17}},
+			from:  "2025-01-01T00:00:00",
+			to:    "2025-01-02T12:00:00",
+			want:  "2025-01-01T09:00:00 2025-01-01T17:00:00 2025-01-02T09:00:00",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := mustLocal(t, tt.start)
+			event := NewEvent("recurring", "Recurring")
+			event.Start = &start
+			event.RecurrenceRules = []RecurrenceRule{tt.rule}
+
+			occurrences, err := event.Occurrences(mustLocal(t, tt.from), mustLocal(t, tt.to))
+			if err != nil {
+				t.Fatalf("Occurrences failed: %v", err)
+			}
+			if got := occurrenceStarts(occurrences); got != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestOccurrencesNonRecurring(t *testing.T) {
+	start := mustLocal(t, "2025-06-01T10:00:00")
+	event := NewEvent("single", "Single")
+	event.Start = &start
+	event.Duration = String("PT1H")
+
+	occurrences, err := event.Occurrences(mustLocal(t, "2025-01-01T00:00:00"), mustLocal(t, "2026-01-01T00:00:00"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(occurrences) != 1 || occurrences[0].End().String() != "2025-06-01T11:00:00" {
+		t.Errorf("Expected one occurrence ending at 11:00, got %s", occurrenceStarts(occurrences))
+	}
+
+	occurrences, _ = event.Occurrences(mustLocal(t, "2025-07-01T00:00:00"), mustLocal(t, "2026-01-01T00:00:00"))
+	if len(occurrences) != 0 {
+		t.Errorf("Expected no occurrences outside the range, got %s", occurrenceStarts(occurrences))
+	}
+}
+
+func TestOccurrencesExclusionsAndOverrides(t *testing.T) {
+	start := mustLocal(t, "2025-01-06T10:00:00") // Monday
+	event := NewEvent("standup", "Standup")
+	event.Start = &start
+	event.Duration = String("PT15M")
+	event.Locations = map[string]*Location{"room": {Type: String("Location"), Name: String("Room 1")}}
+	event.RecurrenceRules = []RecurrenceRule{{Frequency: FrequencyDaily}}
+	// No standups at weekends
+	event.ExcludedRecurrenceRules = []RecurrenceRule{
+		{Frequency: FrequencyWeekly, ByDay: []NDay{{Day: DaySaturday}, {Day: DaySunday}}},
+	}
+	event.RecurrenceOverrides = map[string]map[string]interface{}{
+		"2025-01-07T10:00:00": {"title": "Standup (demo)", "locations/room/name": "Room 2"},
+		"2025-01-08T10:00:00": {"excluded": true},
+		"2025-01-09T10:00:00": {"start": "2025-01-09T11:00:00"},
+		"2025-01-11T10:00:00": {"title": "Saturday standup"},
+	}
+
+	occurrences, err := event.Occurrences(mustLocal(t, "2025-01-06T00:00:00"), mustLocal(t, "2025-01-13T00:00:00"))
+	if err != nil {
+		t.Fatalf("Occurrences failed: %v", err)
+	}
+
+	want := "2025-01-06T10:00:00 2025-01-07T10:00:00 2025-01-09T11:00:00 2025-01-10T10:00:00 2025-01-11T10:00:00"
+	if got := occurrenceStarts(occurrences); got != want {
+		t.Fatalf("Expected %s, got %s", want, got)
+	}
+
+	demo := occurrences[1]
+	if *demo.Event.Title != "Standup (demo)" || *demo.Event.Locations["room"].Name != "Room 2" {
+		t.Errorf("Expected override to be applied, got %s in %s", *demo.Event.Title, *demo.Event.Locations["room"].Name)
+	}
+	if strings.Join(demo.Overridden, ",") != "locations/room/name,title" {
+		t.Errorf("Expected overridden paths, got %v", demo.Overridden)
+	}
+	if *event.Locations["room"].Name != "Room 1" {
+		t.Error("Expected override not to modify the event")
+	}
+	if demo.Event.RecurrenceId == nil || demo.Event.RecurrenceId.String() != "2025-01-07T10:00:00" || demo.Event.RecurrenceRules != nil {
+		t.Errorf("Expected instance with recurrenceId and no rules, got %+v", demo.Event)
+	}
+
+	moved := occurrences[2]
+	if moved.RecurrenceId.String() != "2025-01-09T10:00:00" || moved.End().String() != "2025-01-09T11:15:00" {
+		t.Errorf("Expected moved occurrence, got id %s ending %s", moved.RecurrenceId, moved.End())
+	}
+	if *occurrences[4].Event.Title != "Saturday standup" {
+		t.Errorf("Expected override to add an instance, got %s", *occurrences[4].Event.Title)
+	}
+}
+
+func TestInstance(t *testing.T) {
+	start := mustLocal(t, "2025-01-06T10:00:00")
+	event := NewEvent("standup", "Standup")
+	event.Start = &start
+	event.RecurrenceRules = []RecurrenceRule{{Frequency: FrequencyDaily}}
+	event.RecurrenceOverrides = map[string]map[string]interface{}{
+		"2025-01-07T10:00:00": {"title": "Standup (demo)"},
+		"2025-01-08T10:00:00": {"excluded": true},
+	}
+
+	demo, err := event.Instance(mustLocal(t, "2025-01-07T10:00:00"))
+	if err != nil {
+		t.Fatalf("Instance failed: %v", err)
+	}
+	if *demo.Title != "Standup (demo)" || demo.RecurrenceId.String() != "2025-01-07T10:00:00" || demo.RecurrenceOverrides != nil {
+		t.Errorf("Expected overridden instance, got %+v", demo)
+	}
+
+	plain, err := event.Instance(mustLocal(t, "2025-01-09T10:00:00"))
+	if err != nil || *plain.Title != "Standup" || plain.Start.String() != "2025-01-09T10:00:00" {
+		t.Errorf("Expected unmodified instance, got %+v (%v)", plain, err)
+	}
+
+	if _, err := event.Instance(mustLocal(t, "2025-01-08T10:00:00")); err == nil {
+		t.Error("Expected error for excluded instance")
+	}
+}
+
+func TestOccurrencesErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		rule      RecurrenceRule
+		overrides map[string]map[string]interface{}
+		wantErr   string
+	}{
+		{name: "frequency", rule: RecurrenceRule{Frequency: "fortnightly"}, wantErr: "unsupported frequency"},
+		{name: "interval", rule: RecurrenceRule{Frequency: FrequencyDaily, Interval: Int(0)}, wantErr: "interval"},
+		{name: "rscale", rule: RecurrenceRule{Frequency: FrequencyYearly, RScale: String("hebrew")}, wantErr: "unsupported rscale"},
+		{name: "byDay", rule: RecurrenceRule{Frequency: FrequencyWeekly, ByDay: []NDay{{Day: "xx"}}}, wantErr: "invalid byDay"},
+		{name: "byMonth", rule: RecurrenceRule{Frequency: FrequencyYearly, ByMonth: []string{"may"}}, wantErr: "invalid byMonth"},
+		{
+			name:      "override key",
+			rule:      RecurrenceRule{Frequency: FrequencyDaily},
+			overrides: map[string]map[string]interface{}{"tomorrow": {"title": "x"}},
+			wantErr:   "invalid recurrenceOverrides key",
+		},
+		{
+			name:      "override path",
+			rule:      RecurrenceRule{Frequency: FrequencyDaily},
+			overrides: map[string]map[string]interface{}{"2025-01-02T09:00:00": {"locations/missing/name": "x"}},
+			wantErr:   "does not exist",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := mustLocal(t, "2025-01-01T09:00:00")
+			event := NewEvent("broken", "Broken")
+			event.Start = &start
+			event.RecurrenceRules = []RecurrenceRule{tt.rule}
+			event.RecurrenceOverrides = tt.overrides
+
+			_, err := event.Occurrences(start, mustLocal(t, "2025-02-01T00:00:00"))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	event := NewEvent("no-start", "No start")
+	event.Start = nil
+	if _, err := event.Occurrences(LocalDateTime{}, LocalDateTime{}); err == nil {
+		t.Error("Expected error for event without start")
+	}
+}
+
+func TestApplyPatch(t *testing.T) {
+	obj := map[string]interface{}{
+		"title":     "Meeting",
+		"locations": map[string]interface{}{"a/b": map[string]interface{}{"name": "Room"}},
+	}
+	patch := map[string]interface{}{
+		"/title":                 nil,
+		"locations/a~1b/name":    "Hall",
+		"keywords":               map[string]interface{}{"x": true},
+		"locations/a~1b/~0notes": "tilde",
+	}
+	if err := applyPatch(obj, patch); err != nil {
+		t.Fatalf("applyPatch failed: %v", err)
+	}
+	if _, ok := obj["title"]; ok {
+		t.Error("Expected null to remove title")
+	}
+	location := obj["locations"].(map[string]interface{})["a/b"].(map[string]interface{})
+	if location["name"] != "Hall" || location["~notes"] != "tilde" {
+		t.Errorf("Expected escaped paths to be patched, got %v", location)
+	}
+	if obj["keywords"] == nil {
+		t.Error("Expected keywords to be added")
+	}
+
+	if err := applyPatch(obj, map[string]interface{}{"": "x"}); err == nil {
+		t.Error("Expected error for empty path")
+	}
+}
+
+func TestOccurrencesContext(t *testing.T) {
+	event := &Event{Type: "Event", UID: "daily"}
+	event.Start = NewLocalDateTime(time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC))
+	event.RecurrenceRules = []RecurrenceRule{{Type: "RecurrenceRule", Frequency: FrequencyDaily}}
+	from := *event.Start
+	to := LocalDateTime(time.Date(2025, 1, 11, 0, 0, 0, 0, time.UTC))
+
+	occurrences, err := event.OccurrencesContext(context.Background(), from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(occurrences) != 10 {
+		t.Errorf("Expected 10 occurrences, got %d", len(occurrences))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := event.OccurrencesContext(ctx, from, to); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestOccurrencesLongSeries(t *testing.T) {
+	start := mustLocal(t, "1990-01-01T09:00:00")
+	event := NewEvent("daily", "Daily")
+	event.Start = &start
+	event.RecurrenceRules = []RecurrenceRule{{Frequency: FrequencyDaily}}
+	event.RecurrenceOverrides = map[string]map[string]interface{}{
+		// Moved into the window from decades before it
+		"1990-01-02T09:00:00": {"start": "2025-06-01T12:00:00"},
+		// Moved out of it
+		"2025-06-02T09:00:00": {"start": "2030-01-01T09:00:00"},
+		"2025-06-03T09:00:00": {"title": "Demo"},
+	}
+
+	occurrences, err := event.Occurrences(mustLocal(t, "2025-06-01T00:00:00"), mustLocal(t, "2025-06-04T00:00:00"))
+	if err != nil {
+		t.Fatalf("Occurrences failed: %v", err)
+	}
+	want := "2025-06-01T09:00:00 2025-06-01T12:00:00 2025-06-03T09:00:00"
+	if got := occurrenceStarts(occurrences); got != want {
+		t.Fatalf("Expected %s, got %s", want, got)
+	}
+	if occurrences[1].RecurrenceId.String() != "1990-01-02T09:00:00" || *occurrences[2].Event.Title != "Demo" {
+		t.Errorf("Expected overrides to be applied, got id %s and title %s", occurrences[1].RecurrenceId, *occurrences[2].Event.Title)
+	}
+}
@@ -0,0 +1,105 @@
+package jscal
+
+import "encoding/json"
+
+// Viewer roles for Redact
+const (
+	ViewerOwner  = "owner"  // Owns the calendar and sees every object in full
+	ViewerSharee = "sharee" // Has the calendar shared with them; privacy applies
+)
+
+// sharedProperties are the only properties of a private object that may be
+// shared (RFC 8984 Section 4.4.3, with the recurrence properties added by
+// Erratum 6872)
+var sharedProperties = []string{
+	"@type", "created", "due", "duration", "estimatedDuration", "excluded",
+	"excludedRecurrenceRules", "freeBusyStatus", "privacy", "recurrenceId",
+	"recurrenceIdTimeZone", "recurrenceOverrides", "recurrenceRules", "sequence",
+	"showWithoutTime", "start", "timeZone", "timeZones", "uid", "updated",
+}
+
+// Redact returns the Event as a viewer with the given role may see it. The
+// owner sees a copy of the whole event, as does everyone for public events.
+// Other viewers get only the time and free-busy properties of private events,
+// and nil for secret ones. Unknown privacy values are treated as private.
+func (e *Event) Redact(viewerRole string) *Event {
+	switch redaction(viewerRole, e.Privacy) {
+	case PrivacyPublic:
+		return e.Clone()
+	case PrivacySecret:
+		return nil
+	}
+	data, _ := e.MarshalPartial(sharedProperties)
+	var redacted Event
+	_ = json.Unmarshal(data, &redacted)
+	redacted.RecurrenceOverrides = redactOverrides(redacted.RecurrenceOverrides)
+	return &redacted
+}
+
+// Redact returns the Task as a viewer with the given role may see it, like
+// Event.Redact
+func (t *Task) Redact(viewerRole string) *Task {
+	switch redaction(viewerRole, t.Privacy) {
+	case PrivacyPublic:
+		return t.Clone()
+	case PrivacySecret:
+		return nil
+	}
+	data, _ := t.MarshalPartial(sharedProperties)
+	var redacted Task
+	_ = json.Unmarshal(data, &redacted)
+	redacted.RecurrenceOverrides = redactOverrides(redacted.RecurrenceOverrides)
+	return &redacted
+}
+
+// Redact returns a copy of the Group with every entry redacted for a viewer
+// with the given role; secret entries are left out. The group's own
+// properties are kept.
+func (g *Group) Redact(viewerRole string) *Group {
+	shallow := *g
+	shallow.Entries = nil
+	redacted := shallow.Clone()
+	redacted.Entries = []CalendarObject{}
+	for _, entry := range g.Entries {
+		switch entry := entry.(type) {
+		case *Event:
+			if r := entry.Redact(viewerRole); r != nil {
+				redacted.Entries = append(redacted.Entries, r)
+			}
+		case *Task:
+			if r := entry.Redact(viewerRole); r != nil {
+				redacted.Entries = append(redacted.Entries, r)
+			}
+		case *Group:
+			redacted.Entries = append(redacted.Entries, entry.Redact(viewerRole))
+		}
+	}
+	return redacted
+}
+
+// redaction returns how much of an object with the given privacy the viewer
+// may see: everything (public), the shared properties (private) or nothing
+// (secret)
+func redaction(viewerRole string, privacy *string) string {
+	if viewerRole == ViewerOwner || privacy == nil || *privacy == PrivacyPublic {
+		return PrivacyPublic
+	}
+	if *privacy == PrivacySecret {
+		return PrivacySecret
+	}
+	return PrivacyPrivate
+}
+
+// redactOverrides keeps only the patches of shared properties. Overrides
+// left empty stay, since they still add an occurrence.
+func redactOverrides(overrides map[string]map[string]interface{}) map[string]map[string]interface{} {
+	for _, patch := range overrides {
+		for path := range patch {
+			segments := splitPointer(path)
+			if len(segments) == 0 || !contains(sharedProperties, segments[0]) {
+				delete(patch, path)
+			}
+		}
+	}
+	return overrides
+}
@@ -0,0 +1,141 @@
+package jscal
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func privateEvent(uid, privacy string) *Event {
+	event := &Event{
+		Type:           "Event",
+		UID:            uid,
+		Title:          String("Doctor's appointment"),
+		Description:    String("Bring the referral"),
+		Start:          NewLocalDateTime(time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)),
+		Duration:       String("PT1H"),
+		TimeZone:       String("Europe/Vienna"),
+		FreeBusyStatus: String(FreeBusyBusy),
+		Sequence:       Int(2),
+		RecurrenceRules: []RecurrenceRule{
+			{Type: "RecurrenceRule", Frequency: "monthly"},
+		},
+		RecurrenceOverrides: map[string]map[string]interface{}{
+			"2025-04-01T09:00:00": {"start": "2025-04-01T10:00:00", "title": "Moved", "locations/l1/name": "Clinic"},
+			"2025-05-01T09:00:00": {"excluded": true},
+			"2025-06-15T09:00:00": {"description": "Extra"},
+		},
+		Locations:    map[string]*Location{"l1": {Name: String("Clinic")}},
+		Participants: map[string]*Participant{"p1": NewParticipant("Dr. Smith", "smith@example.com")},
+		Alerts:       map[string]*Alert{"a1": {Type: "Alert", Trigger: &OffsetTrigger{Type: "OffsetTrigger", Offset: "-PT15M"}}},
+		Extensions:   map[string]interface{}{"example.com:insurance": "123"},
+	}
+	if privacy != "" {
+		event.Privacy = String(privacy)
+	}
+	return event
+}
+
+func TestEventRedact(t *testing.T) {
+	for _, privacy := range []string{"", PrivacyPublic} {
+		event := privateEvent("e1", privacy)
+		if redacted := event.Redact(ViewerSharee); !redacted.Equal(event) || redacted == event {
+			t.Errorf("privacy %q: Expected an identical copy", privacy)
+		}
+	}
+
+	event := privateEvent("e1", PrivacyPrivate)
+	if redacted := event.Redact(ViewerOwner); !redacted.Equal(event) {
+		t.Error("Expected the owner to see the whole event")
+	}
+	if redacted := privateEvent("e1", PrivacySecret).Redact(ViewerSharee); redacted != nil {
+		t.Errorf("Expected nil for a secret event, got %v", redacted)
+	}
+	if redacted := privateEvent("e1", PrivacySecret).Redact(ViewerOwner); redacted == nil {
+		t.Error("Expected the owner to see a secret event")
+	}
+
+	redacted := event.Redact(ViewerSharee)
+	obj, err := toJSONObject(redacted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var properties []string
+	for name := range obj {
+		properties = append(properties, name)
+	}
+	sort.Strings(properties)
+	expected := []string{"@type", "duration", "freeBusyStatus", "privacy", "recurrenceOverrides",
+		"recurrenceRules", "sequence", "start", "timeZone", "uid"}
+	if !reflect.DeepEqual(properties, expected) {
+		t.Errorf("Expected properties %v, got %v", expected, properties)
+	}
+
+	expectedOverrides := map[string]map[string]interface{}{
+		"2025-04-01T09:00:00": {"start": "2025-04-01T10:00:00"},
+		"2025-05-01T09:00:00": {"excluded": true},
+		"2025-06-15T09:00:00": {},
+	}
+	if !reflect.DeepEqual(redacted.RecurrenceOverrides, expectedOverrides) {
+		t.Errorf("Expected overrides %v, got %v", expectedOverrides, redacted.RecurrenceOverrides)
+	}
+	if len(event.RecurrenceOverrides["2025-04-01T09:00:00"]) != 3 || event.Title == nil {
+		t.Error("Expected Redact to leave the event unchanged")
+	}
+
+	// Unknown privacy values are treated as private
+	if redacted := privateEvent("e1", "x-confidential").Redact(ViewerSharee); redacted.Title != nil {
+		t.Error("Expected the title of an event with unknown privacy to be hidden")
+	}
+}
+
+func TestTaskRedact(t *testing.T) {
+	task := NewTask("t1", "Renew passport")
+	task.Privacy = String(PrivacyPrivate)
+	task.Due = NewLocalDateTime(time.Date(2025, 3, 1, 17, 0, 0, 0, time.UTC))
+	task.EstimatedDuration = String("PT2H")
+
+	redacted := task.Redact(ViewerSharee)
+	if redacted.Title != nil || redacted.Progress != nil {
+		t.Error("Expected the title and progress to be hidden")
+	}
+	if redacted.Due == nil || redacted.EstimatedDuration == nil || redacted.UID != "t1" {
+		t.Errorf("Expected due, estimatedDuration and uid to be shared, got %+v", redacted)
+	}
+
+	task.Privacy = String(PrivacySecret)
+	if task.Redact(ViewerSharee) != nil {
+		t.Error("Expected nil for a secret task")
+	}
+}
+
+func TestGroupRedact(t *testing.T) {
+	inner := NewGroup("inner", "Inner")
+	inner.Entries = []CalendarObject{privateEvent("e3", PrivacySecret)}
+	group := NewGroup("g1", "Calendar")
+	group.Entries = []CalendarObject{
+		privateEvent("e1", PrivacyPublic),
+		privateEvent("e2", PrivacyPrivate),
+		privateEvent("e4", PrivacySecret),
+		inner,
+	}
+
+	redacted := group.Redact(ViewerSharee)
+	if redacted.GetTitle() != "Calendar" || len(redacted.Entries) != 3 {
+		t.Fatalf("Expected the title and 3 entries, got %q and %d", redacted.GetTitle(), len(redacted.Entries))
+	}
+	if redacted.Entries[0].(*Event).Title == nil || redacted.Entries[1].(*Event).Title != nil {
+		t.Error("Expected only the private entry to be redacted")
+	}
+	if nested := redacted.Entries[2].(*Group); len(nested.Entries) != 0 {
+		t.Errorf("Expected the nested secret entry to be left out, got %d entries", len(nested.Entries))
+	}
+	if len(group.Entries) != 4 || len(inner.Entries) != 1 {
+		t.Error("Expected Redact to leave the group unchanged")
+	}
+
+	if redacted := group.Redact(ViewerOwner); len(redacted.Flatten()) != 4 {
+		t.Error("Expected the owner to see every entry")
+	}
+}
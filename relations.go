@@ -0,0 +1,117 @@
+package jscal
+
+// RelationNode is an entry of a Group with the entries that are its children,
+// as returned by ResolveRelations
+type RelationNode struct {
+	Object   CalendarObject
+	Children []*RelationNode
+}
+
+// addRelation adds a relation type to the relatedTo entry for uid, creating
+// the map and entry as needed. An empty relType only records that the objects
+// are related.
+func addRelation(relatedTo *map[string]*Relation, uid, relType string) {
+	if *relatedTo == nil {
+		*relatedTo = make(map[string]*Relation)
+	}
+	relation := (*relatedTo)[uid]
+	if relation == nil {
+		relation = &Relation{Type: "Relation"}
+		(*relatedTo)[uid] = relation
+	}
+	if relType == "" {
+		return
+	}
+	if relation.Relation == nil {
+		relation.Relation = make(map[string]bool)
+	}
+	relation.Relation[relType] = true
+}
+
+// relatedToOf returns the relatedTo property of an Event or Task
+func relatedToOf(obj CalendarObject) map[string]*Relation {
+	switch o := obj.(type) {
+	case *Event:
+		return o.RelatedTo
+	case *Task:
+		return o.RelatedTo
+	}
+	return nil
+}
+
+// ResolveRelations arranges the entries of the group into parent/child trees
+// using their parent and child relations, as used for meeting series and
+// subtasks. Either side of a relation is enough: an entry that names another
+// as its parent, or that is named as a child, becomes that entry's child.
+//
+// Roots and children keep the order of Entries. Relations to objects outside
+// the group are ignored, an entry with several parents is placed under the
+// first one found, and entries in a cycle are returned as roots.
+func (g *Group) ResolveRelations() []*RelationNode {
+	index := make(map[string]CalendarObject, len(g.Entries))
+	for _, entry := range g.Entries {
+		index[entry.GetUID()] = entry
+	}
+
+	parentOf := make(map[string]string)
+	setParent := func(child, parent string) {
+		if _, ok := index[child]; !ok || child == parent {
+			return
+		}
+		if _, ok := index[parent]; !ok {
+			return
+		}
+		if _, ok := parentOf[child]; !ok {
+			parentOf[child] = parent
+		}
+	}
+	for _, entry := range g.Entries {
+		relatedTo := relatedToOf(entry)
+		for _, uid := range sortedKeys(relatedTo) {
+			relation := relatedTo[uid]
+			if relation == nil {
+				continue
+			}
+			if relation.Relation[RelationTypeParent] {
+				setParent(entry.GetUID(), uid)
+			}
+			if relation.Relation[RelationTypeChild] {
+				setParent(uid, entry.GetUID())
+			}
+		}
+	}
+
+	children := make(map[string][]CalendarObject)
+	for _, entry := range g.Entries {
+		if parent, ok := parentOf[entry.GetUID()]; ok {
+			children[parent] = append(children[parent], entry)
+		}
+	}
+
+	visited := make(map[string]bool)
+	var build func(obj CalendarObject) *RelationNode
+	build = func(obj CalendarObject) *RelationNode {
+		visited[obj.GetUID()] = true
+		node := &RelationNode{Object: obj}
+		for _, child := range children[obj.GetUID()] {
+			if !visited[child.GetUID()] {
+				node.Children = append(node.Children, build(child))
+			}
+		}
+		return node
+	}
+
+	var roots []*RelationNode
+	for _, entry := range g.Entries {
+		if _, hasParent := parentOf[entry.GetUID()]; !hasParent && !visited[entry.GetUID()] {
+			roots = append(roots, build(entry))
+		}
+	}
+	// Entries only reachable through a cycle
+	for _, entry := range g.Entries {
+		if !visited[entry.GetUID()] {
+			roots = append(roots, build(entry))
+		}
+	}
+	return roots
+}
@@ -0,0 +1,81 @@
+package jscal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAddRelation(t *testing.T) {
+	event := NewEvent("occurrence", "Standup")
+	event.AddRelation("series", RelationTypeParent)
+	event.AddRelation("series", RelationTypeNext)
+	event.AddRelation("notes", "")
+
+	series := event.RelatedTo["series"]
+	if series == nil || series.Type != "Relation" || !series.Relation[RelationTypeParent] || !series.Relation[RelationTypeNext] {
+		t.Errorf("Expected parent and next relation to series, got %+v", series)
+	}
+	if notes := event.RelatedTo["notes"]; notes == nil || len(notes.Relation) != 0 {
+		t.Errorf("Expected unspecified relation to notes, got %+v", notes)
+	}
+
+	task := NewTask("subtask", "Write tests")
+	task.AddRelation("project", RelationTypeParent)
+	if !task.RelatedTo["project"].Relation[RelationTypeParent] {
+		t.Errorf("Expected parent relation on task, got %+v", task.RelatedTo)
+	}
+	if err := task.Validate(); err != nil {
+		t.Errorf("Expected task with relations to be valid, got %v", err)
+	}
+}
+
+// relationTree renders nodes as uid(children...) for comparison
+func relationTree(nodes []*RelationNode) string {
+	parts := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		part := node.Object.GetUID()
+		if len(node.Children) > 0 {
+			part += "(" + relationTree(node.Children) + ")"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestResolveRelations(t *testing.T) {
+	project := NewTask("project", "Release")
+	project.AddRelation("docs", RelationTypeChild)
+
+	docs := NewTask("docs", "Write docs")
+	code := NewTask("code", "Write code")
+	code.AddRelation("project", RelationTypeParent)
+	tests := NewTask("tests", "Write tests")
+	tests.AddRelation("code", RelationTypeParent)
+	tests.AddRelation("elsewhere", RelationTypeParent) // not in the group
+
+	series := NewEvent("series", "Weekly sync")
+	occurrence := NewEvent("occurrence", "Weekly sync (moved)")
+	occurrence.AddRelation("series", RelationTypeParent)
+
+	loopA := NewTask("loop-a", "A")
+	loopA.AddRelation("loop-b", RelationTypeParent)
+	loopB := NewTask("loop-b", "B")
+	loopB.AddRelation("loop-a", RelationTypeParent)
+
+	group := NewGroup("relations", "Relations")
+	for _, entry := range []CalendarObject{tests, project, docs, code, occurrence, series, loopA, loopB} {
+		if err := group.AddEntry(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := relationTree(group.ResolveRelations())
+	want := "project(docs code(tests)) series(occurrence) loop-a(loop-b)"
+	if got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+
+	if roots := NewGroup("empty", "Empty").ResolveRelations(); len(roots) != 0 {
+		t.Errorf("Expected no roots for empty group, got %d", len(roots))
+	}
+}
@@ -0,0 +1,443 @@
+package jscal
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Severity classifies a validation issue
+type Severity string
+
+// Severity levels for validation issues
+const (
+	SeverityError   Severity = "error"   // Violates RFC 8984, the object is invalid
+	SeverityWarning Severity = "warning" // Allowed but likely to cause interoperability problems
+	SeverityInfo    Severity = "info"    // Informational, e.g. a default value applies
+)
+
+// ValidationIssue is a single finding in a ValidationReport
+type ValidationIssue struct {
+	Severity Severity    `json:"severity"`
+	Pointer  string      `json:"pointer"`           // JSON pointer (RFC 6901) to the offending field
+	Section  string      `json:"section,omitempty"` // RFC 8984 section, e.g. "4.4.6"
+	Message  string      `json:"message"`
+	Value    interface{} `json:"value,omitempty"`
+}
+
+// String formats the issue as "severity pointer: message"
+func (i ValidationIssue) String() string {
+	pointer := i.Pointer
+	if pointer == "" {
+		pointer = "/"
+	}
+	return fmt.Sprintf("%s %s: %s", i.Severity, pointer, i.Message)
+}
+
+// ValidationReport is a structured validation result with per-issue severity,
+// location and RFC reference
+type ValidationReport struct {
+	Type   string            `json:"type"` // @type of the validated object
+	UID    string            `json:"uid,omitempty"`
+	Issues []ValidationIssue `json:"issues"`
+}
+
+// Valid returns true if the report contains no errors
+func (r *ValidationReport) Valid() bool {
+	return r.Count(SeverityError) == 0
+}
+
+// Count returns the number of issues with the given severity
+func (r *ValidationReport) Count(severity Severity) int {
+	count := 0
+	for _, issue := range r.Issues {
+		if issue.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+// Filter returns the issues with the given severity
+func (r *ValidationReport) Filter(severity Severity) []ValidationIssue {
+	var issues []ValidationIssue
+	for _, issue := range r.Issues {
+		if issue.Severity == severity {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// Err returns the report's errors as ValidationErrors, or nil if there are none.
+// In strict mode warnings are treated as errors.
+func (r *ValidationReport) Err(strict bool) error {
+	var errors ValidationErrors
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityError || (strict && issue.Severity == SeverityWarning) {
+			errors = append(errors, ValidationError{
+				Field:   issue.Pointer,
+				Value:   issue.Value,
+				Message: issue.Message,
+			})
+		}
+	}
+	if len(errors) > 0 {
+		return errors
+	}
+	return nil
+}
+
+// sort orders issues by severity, then by pointer, so reports are stable
+func (r *ValidationReport) sort() {
+	rank := map[Severity]int{SeverityError: 0, SeverityWarning: 1, SeverityInfo: 2}
+	sort.SliceStable(r.Issues, func(i, j int) bool {
+		if rank[r.Issues[i].Severity] != rank[r.Issues[j].Severity] {
+			return rank[r.Issues[i].Severity] < rank[r.Issues[j].Severity]
+		}
+		return r.Issues[i].Pointer < r.Issues[j].Pointer
+	})
+}
+
+func (r *ValidationReport) add(severity Severity, field string, value interface{}, message string) {
+	r.Issues = append(r.Issues, ValidationIssue{
+		Severity: severity,
+		Pointer:  fieldPointer(field),
+		Section:  rfcSection(r.Type, field),
+		Message:  message,
+		Value:    value,
+	})
+}
+
+// addErrors converts the result of Validate into error issues
+func (r *ValidationReport) addErrors(err error) {
+	switch e := err.(type) {
+	case nil:
+		return
+	case ValidationErrors:
+		for _, valErr := range e {
+			r.add(SeverityError, valErr.Field, valErr.Value, valErr.Message)
+		}
+	case ValidationError:
+		r.add(SeverityError, e.Field, e.Value, e.Message)
+	default:
+		r.add(SeverityError, "", nil, err.Error())
+	}
+}
+
+// ValidateReport validates the Event and returns a structured report. Errors
+// are the same findings returned by Validate; warnings and info items point
+// out problems that RFC 8984 tolerates.
+func (e *Event) ValidateReport() *ValidationReport {
+	return e.report(e.Validate())
+}
+
+// report builds the ValidationReport of the Event from the result of validating it
+func (e *Event) report(err error) *ValidationReport {
+	report := &ValidationReport{Type: "Event"}
+	report.addErrors(err)
+	if e == nil {
+		return report
+	}
+	report.UID = e.UID
+
+	addSubObjectWarnings(report, "", e.Participants, e.Locations, e.Links, e.TimeZones)
+	addEncodingWarnings(report, "", reflect.ValueOf(e), 0)
+	for _, id := range sortedKeys(e.Participants) {
+		if p := e.Participants[id]; p != nil && (p.Progress != nil || p.PercentComplete != nil || p.ProgressUpdated != nil) {
+			report.add(SeverityWarning, fmt.Sprintf("participants[%s].progress", id), nil, "participant progress is only defined for tasks")
+		}
+	}
+	if e.Duration == nil {
+		report.add(SeverityInfo, "duration", nil, "not set, defaults to PT0S")
+	}
+
+	report.sort()
+	return report
+}
+
+// ValidateReport validates the Task and returns a structured report
+func (t *Task) ValidateReport() *ValidationReport {
+	return t.report(t.Validate())
+}
+
+// report builds the ValidationReport of the Task from the result of validating it
+func (t *Task) report(err error) *ValidationReport {
+	report := &ValidationReport{Type: "Task"}
+	report.addErrors(err)
+	if t == nil {
+		return report
+	}
+	report.UID = t.UID
+
+	addSubObjectWarnings(report, "", t.Participants, t.Locations, t.Links, t.TimeZones)
+	addEncodingWarnings(report, "", reflect.ValueOf(t), 0)
+
+	report.sort()
+	return report
+}
+
+// ValidateReport validates the Group and its entries and returns a structured report
+func (g *Group) ValidateReport() *ValidationReport {
+	return g.report(g.Validate())
+}
+
+// report builds the ValidationReport of the Group from the result of validating it
+func (g *Group) report(err error) *ValidationReport {
+	report := &ValidationReport{Type: "Group"}
+	report.addErrors(err)
+	if g == nil {
+		return report
+	}
+	report.UID = g.UID
+
+	addSubObjectWarnings(report, "", nil, nil, g.Links, nil)
+	addEncodingWarnings(report, "", reflect.ValueOf(g), 0)
+	for i, entry := range g.Entries {
+		prefix := fmt.Sprintf("entries[%d].", i)
+		switch obj := entry.(type) {
+		case *Event:
+			addSubObjectWarnings(report, prefix, obj.Participants, obj.Locations, obj.Links, obj.TimeZones)
+		case *Task:
+			addSubObjectWarnings(report, prefix, obj.Participants, obj.Locations, obj.Links, obj.TimeZones)
+		}
+	}
+
+	report.sort()
+	return report
+}
+
+// addSubObjectWarnings warns about nested objects that omit their @type.
+// RFC 8984 makes @type mandatory on every object, but many producers leave
+// it out of participants, locations and links.
+func addSubObjectWarnings(report *ValidationReport, prefix string, participants map[string]*Participant,
+	locations map[string]*Location, links map[string]*Link, timeZones map[string]*TimeZone) {
+	for id, p := range participants {
+		if p != nil && (p.Type == nil || *p.Type == "") {
+			report.add(SeverityWarning, fmt.Sprintf("%sparticipants[%s].@type", prefix, id), nil, "missing @type, should be 'Participant'")
+		}
+	}
+	for id, l := range locations {
+		if l != nil && (l.Type == nil || *l.Type == "") {
+			report.add(SeverityWarning, fmt.Sprintf("%slocations[%s].@type", prefix, id), nil, "missing @type, should be 'Location'")
+		}
+	}
+	for id, l := range links {
+		if l != nil && (l.Type == nil || *l.Type == "") {
+			report.add(SeverityWarning, fmt.Sprintf("%slinks[%s].@type", prefix, id), nil, "missing @type, should be 'Link'")
+		}
+	}
+	for id, tz := range timeZones {
+		if tz != nil && (tz.Type == nil || *tz.Type == "") {
+			report.add(SeverityWarning, fmt.Sprintf("%stimeZones[%s].@type", prefix, id), nil, "missing @type, should be 'TimeZone'")
+		}
+	}
+}
+
+// maxEncodingDepth bounds addEncodingWarnings, so that groups nested in
+// themselves end
+const maxEncodingDepth = 32
+
+// addEncodingWarnings warns about strings under v that aren't valid UTF-8,
+// which JSON output silently replaces, or that contain U+FFFD, the mark of
+// text decoded with the wrong charset. Fields are named by their JSON
+// names; extension maps (json:"-") are read as properties of their object.
+func addEncodingWarnings(report *ValidationReport, field string, v reflect.Value, depth int) {
+	if depth > maxEncodingDepth {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			addEncodingWarnings(report, field, v.Elem(), depth+1)
+		}
+	case reflect.String:
+		switch text := v.String(); {
+		case !utf8.ValidString(text):
+			report.add(SeverityWarning, field, nil, "not valid UTF-8")
+		case strings.ContainsRune(text, utf8.RuneError):
+			report.add(SeverityWarning, field, nil, "contains U+FFFD replacement characters, the text may have been decoded with the wrong charset")
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			addEncodingWarnings(report, fmt.Sprintf("%s[%d]", field, i), v.Index(i), depth+1)
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			addEncodingWarnings(report, fmt.Sprintf("%s[%s]", field, iter.Key().String()), iter.Value(), depth+1)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" {
+				if f.Type.Kind() == reflect.Map && f.Type.Key().Kind() == reflect.String {
+					iter := v.Field(i).MapRange()
+					for iter.Next() {
+						addEncodingWarnings(report, fmt.Sprintf("%s[%s]", field, iter.Key().String()), iter.Value(), depth+1)
+					}
+				}
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			addEncodingWarnings(report, joinField(field, name), v.Field(i), depth+1)
+		}
+	}
+}
+
+// joinField appends a property name to a field path
+func joinField(field, name string) string {
+	if field == "" {
+		return name
+	}
+	return field + "." + name
+}
+
+// fieldPointer converts a ValidationError field path such as
+// "participants[p1].email" into a JSON pointer ("/participants/p1/email")
+func fieldPointer(field string) string {
+	switch field {
+	case "", "event", "task", "group":
+		return ""
+	}
+
+	var pointer, segment strings.Builder
+	flush := func() {
+		pointer.WriteString("/")
+		pointer.WriteString(escapePointerToken(segment.String()))
+		segment.Reset()
+	}
+
+	depth := 0
+	for i, r := range field {
+		switch {
+		case r == '[' && depth == 0:
+			if i > 0 && field[i-1] != ']' {
+				flush()
+			}
+			depth++
+		case r == ']' && depth == 1:
+			flush()
+			depth--
+		case r == '.' && depth == 0:
+			if i > 0 && field[i-1] != ']' {
+				flush()
+			}
+		default:
+			if r == '[' {
+				depth++
+			} else if r == ']' {
+				depth--
+			}
+			segment.WriteRune(r)
+		}
+	}
+	if segment.Len() > 0 {
+		flush()
+	}
+
+	return pointer.String()
+}
+
+// escapePointerToken escapes a JSON pointer reference token per RFC 6901
+func escapePointerToken(token string) string {
+	token = strings.ReplaceAll(token, "~", "~0")
+	return strings.ReplaceAll(token, "/", "~1")
+}
+
+// rfcSections maps top-level property names to their RFC 8984 section
+var rfcSections = map[string]string{
+	"@type":                   "4.1.1",
+	"uid":                     "4.1.2",
+	"relatedTo":               "4.1.3",
+	"prodId":                  "4.1.4",
+	"created":                 "4.1.5",
+	"updated":                 "4.1.6",
+	"sequence":                "4.1.7",
+	"method":                  "4.1.8",
+	"title":                   "4.2.1",
+	"description":             "4.2.2",
+	"descriptionContentType":  "4.2.3",
+	"showWithoutTime":         "4.2.4",
+	"locations":               "4.2.5",
+	"virtualLocations":        "4.2.6",
+	"links":                   "4.2.7",
+	"locale":                  "4.2.8",
+	"keywords":                "4.2.9",
+	"categories":              "4.2.10",
+	"color":                   "4.2.11",
+	"recurrenceId":            "4.3.1",
+	"recurrenceIdTimeZone":    "4.3.2",
+	"recurrenceRules":         "4.3.3",
+	"excludedRecurrenceRules": "4.3.4",
+	"recurrenceOverrides":     "4.3.5",
+	"excluded":                "4.3.6",
+	"priority":                "4.4.1",
+	"freeBusyStatus":          "4.4.2",
+	"privacy":                 "4.4.3",
+	"replyTo":                 "4.4.4",
+	"sentBy":                  "4.4.5",
+	"participants":            "4.4.6",
+	"requestStatus":           "4.4.7",
+	"useDefaultAlerts":        "4.5.1",
+	"alerts":                  "4.5.2",
+	"localizations":           "4.6.1",
+	"timeZone":                "4.7.1",
+	"timeZones":               "4.7.2",
+}
+
+// typeSections maps type-specific properties to their RFC 8984 section
+var typeSections = map[string]map[string]string{
+	"Event": {
+		"start":    "5.1.1",
+		"duration": "5.1.2",
+		"status":   "5.1.3",
+	},
+	"Task": {
+		"due":               "5.2.1",
+		"start":             "5.2.2",
+		"estimatedDuration": "5.2.3",
+		"percentComplete":   "5.2.4",
+		"progress":          "5.2.5",
+		"progressUpdated":   "5.2.6",
+	},
+	"Group": {
+		"entries": "5.3.1",
+		"source":  "5.3.2",
+	},
+}
+
+// rfcSection returns the RFC 8984 section for a field of the given object type
+func rfcSection(objType, field string) string {
+	name := field
+	if idx := strings.IndexAny(name, ".["); idx >= 0 {
+		name = name[:idx]
+	}
+
+	// Group entry issues are reported against the entry's own property
+	if objType == "Group" && name == "entries" && strings.Contains(field, "].") {
+		inner := field[strings.Index(field, "].")+2:]
+		for _, entryType := range []string{"Event", "Task"} {
+			if section := rfcSection(entryType, inner); section != "" {
+				return section
+			}
+		}
+	}
+
+	if section, ok := typeSections[objType][name]; ok {
+		return section
+	}
+	return rfcSections[name]
+}
@@ -0,0 +1,196 @@
+package jscal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFieldPointer(t *testing.T) {
+	tests := []struct {
+		field string
+		want  string
+	}{
+		{"", ""},
+		{"event", ""},
+		{"uid", "/uid"},
+		{"@type", "/@type"},
+		{"participants[p1].email", "/participants/p1/email"},
+		{"participants[p1].roles[chair]", "/participants/p1/roles/chair"},
+		{"alerts[a1].trigger.offset", "/alerts/a1/trigger/offset"},
+		{"recurrenceRules[0]", "/recurrenceRules/0"},
+		{"recurrenceRules[0].byDay[2].day", "/recurrenceRules/0/byDay/2/day"},
+		{"entries[1].participants[p1].kind", "/entries/1/participants/p1/kind"},
+		{"links[a/b~c].href", "/links/a~1b~0c/href"},
+		{"locations[loc].links[l1].href", "/locations/loc/links/l1/href"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			if got := fieldPointer(tt.field); got != tt.want {
+				t.Errorf("fieldPointer(%q) = %q, want %q", tt.field, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRFCSection(t *testing.T) {
+	tests := []struct {
+		objType string
+		field   string
+		want    string
+	}{
+		{"Event", "uid", "4.1.2"},
+		{"Event", "start", "5.1.1"},
+		{"Task", "start", "5.2.2"},
+		{"Task", "percentComplete", "5.2.4"},
+		{"Event", "participants[p1].email", "4.4.6"},
+		{"Event", "recurrenceRules[0].frequency", "4.3.3"},
+		{"Group", "entries[0].due", "5.2.1"},
+		{"Group", "entries[0]", "5.3.1"},
+		{"Event", "unknown", ""},
+	}
+
+	for _, tt := range tests {
+		if got := rfcSection(tt.objType, tt.field); got != tt.want {
+			t.Errorf("rfcSection(%q, %q) = %q, want %q", tt.objType, tt.field, got, tt.want)
+		}
+	}
+}
+
+func TestEventValidateReport(t *testing.T) {
+	event := NewEvent("report-test", "Report")
+	event.Duration = String("PT1H")
+	event.Status = String("bogus")
+	event.AddParticipant("p1", &Participant{
+		Type:  String("Participant"),
+		Email: String("not-an-email"),
+	})
+	event.AddParticipant("p2", NewParticipant("Bob", "bob@example.com"))
+
+	report := event.ValidateReport()
+
+	if report.Type != "Event" || report.UID != "report-test" {
+		t.Errorf("Unexpected report identity: %s %s", report.Type, report.UID)
+	}
+	if report.Valid() {
+		t.Error("Expected report to be invalid")
+	}
+	if got := report.Count(SeverityError); got != 2 {
+		t.Errorf("Expected 2 errors, got %d: %v", got, report.Issues)
+	}
+
+	errs := report.Filter(SeverityError)
+	if errs[0].Pointer != "/participants/p1/email" || errs[0].Section != "4.4.6" {
+		t.Errorf("Unexpected first error: %+v", errs[0])
+	}
+	if errs[1].Pointer != "/status" || errs[1].Section != "5.1.3" {
+		t.Errorf("Unexpected second error: %+v", errs[1])
+	}
+
+	warnings := report.Filter(SeverityWarning)
+	if len(warnings) != 1 || warnings[0].Pointer != "/participants/p2/@type" {
+		t.Errorf("Expected missing @type warning for p2, got %v", warnings)
+	}
+
+	if report.Count(SeverityInfo) != 0 {
+		t.Errorf("Expected no info items when duration is set, got %v", report.Filter(SeverityInfo))
+	}
+}
+
+func TestValidateReportStrict(t *testing.T) {
+	event := &Event{
+		Type:  "Event",
+		UID:   "strict-test",
+		Start: NewLocalDateTime(time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)),
+	}
+	event.AddLocation("loc", &Location{Name: String("Room 1")})
+
+	report := event.ValidateReport()
+	if !report.Valid() {
+		t.Fatalf("Expected valid report, got %v", report.Issues)
+	}
+	if report.Err(false) != nil {
+		t.Errorf("Expected no error in non-strict mode, got %v", report.Err(false))
+	}
+	if report.Err(true) == nil {
+		t.Error("Expected warnings to fail in strict mode")
+	}
+	if report.Count(SeverityInfo) != 1 {
+		t.Errorf("Expected duration info item, got %v", report.Issues)
+	}
+}
+
+func TestValidateReportNil(t *testing.T) {
+	var event *Event
+	report := event.ValidateReport()
+	if report.Valid() {
+		t.Error("Expected nil event report to be invalid")
+	}
+	if report.Issues[0].Pointer != "" {
+		t.Errorf("Expected root pointer, got %q", report.Issues[0].Pointer)
+	}
+}
+
+func TestGroupValidateReport(t *testing.T) {
+	group := NewGroup("group-report", "Group")
+	task := NewTask("task-1", "Task")
+	task.PercentComplete = Int(150)
+	task.AddParticipant("p1", NewParticipant("Ann", "ann@example.com"))
+	_ = group.AddEntry(task)
+
+	report := group.ValidateReport()
+	errs := report.Filter(SeverityError)
+	if len(errs) != 1 || errs[0].Pointer != "/entries/0/percentComplete" || errs[0].Section != "5.2.4" {
+		t.Errorf("Unexpected errors: %+v", errs)
+	}
+	warnings := report.Filter(SeverityWarning)
+	if len(warnings) != 1 || warnings[0].Pointer != "/entries/0/participants/p1/@type" {
+		t.Errorf("Unexpected warnings: %+v", warnings)
+	}
+}
+
+func TestValidateReportEncoding(t *testing.T) {
+	event := NewEvent("mojibake", "Caf\xe9")
+	event.Start = NewLocalDateTime(time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC))
+	event.Description = String("Zum L�wen")
+	event.AddParticipant("p1", &Participant{Type: String("Participant"), Name: String("J\xfcrgen")})
+	event.SetExtension("example.com:note", "ok \xff")
+
+	group := NewGroup("group", "Calendar")
+	group.Entries = []CalendarObject{event}
+
+	for _, report := range []*ValidationReport{event.ValidateReport(), group.ValidateReport()} {
+		prefix := ""
+		if report.Type == "Group" {
+			prefix = "/entries/0"
+		}
+		found := make(map[string]string)
+		for _, issue := range report.Filter(SeverityWarning) {
+			found[issue.Pointer] = issue.Message
+		}
+		for _, pointer := range []string{"/title", "/description", "/participants/p1/name", "/example.com:note"} {
+			if found[prefix+pointer] == "" {
+				t.Errorf("%s: expected an encoding warning at %s, got %v", report.Type, prefix+pointer, found)
+			}
+		}
+		if found[prefix+"/uid"] != "" {
+			t.Errorf("%s: unexpected warning for uid: %s", report.Type, found[prefix+"/uid"])
+		}
+	}
+}
+
+func TestValidateReportEventParticipantProgress(t *testing.T) {
+	event := NewEvent("progress", "Standup")
+	event.Start = NewLocalDateTime(time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC))
+	event.AddParticipant("p1", &Participant{Type: String("Participant"), Progress: String(ProgressCompleted)})
+
+	found := false
+	for _, issue := range event.ValidateReport().Filter(SeverityWarning) {
+		if issue.Pointer == "/participants/p1/progress" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected a warning for participant progress on an event")
+	}
+}
@@ -0,0 +1,141 @@
+package jscal
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Request status classes, the first digit of a status code (RFC 5546
+// Section 3.6)
+const (
+	RequestStatusPreliminary = 1 // Preliminary success, the request is pending
+	RequestStatusSuccess     = 2 // The request was completed
+	RequestStatusClientError = 3 // The request was malformed or not understood
+	RequestStatusServerError = 4 // The request was valid but couldn't be processed
+)
+
+// statusCodePattern matches a statcode of RFC 5545 Section 3.8.8.3
+var statusCodePattern = regexp.MustCompile(`^[0-9]+(?:\.[0-9]+){1,2}$`)
+
+// RequestStatus is a parsed requestStatus (RFC 8984 Section 4.4.7), the
+// "code;description;extra" structure shared with the iCalendar
+// REQUEST-STATUS property
+type RequestStatus struct {
+	Code        string // Hierarchical status code such as "2.0" or "3.1.1"
+	Description string // Human-readable description of the status
+	Extra       string // Optional exception data, such as the offending property
+}
+
+// ParseRequestStatus parses a requestStatus value. The description and the
+// extra data are split at the first semicolons; any further semicolons
+// belong to the extra data.
+func ParseRequestStatus(s string) (*RequestStatus, error) {
+	parts := strings.SplitN(s, ";", 3)
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("invalid request status %q: missing description", s)
+	}
+	rs := &RequestStatus{Code: parts[0], Description: parts[1]}
+	if len(parts) == 3 {
+		rs.Extra = parts[2]
+	}
+	if err := rs.Validate(); err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
+
+// NewRequestStatus creates a RequestStatus without extra data
+func NewRequestStatus(code, description string) *RequestStatus {
+	return &RequestStatus{Code: code, Description: description}
+}
+
+// String returns the requestStatus value, omitting empty extra data
+func (r *RequestStatus) String() string {
+	if r == nil {
+		return ""
+	}
+	if r.Extra == "" {
+		return r.Code + ";" + r.Description
+	}
+	return r.Code + ";" + r.Description + ";" + r.Extra
+}
+
+// Validate checks the status code and that the description can be told
+// apart from the extra data
+func (r *RequestStatus) Validate() error {
+	if r == nil {
+		return fmt.Errorf("request status is nil")
+	}
+	if !statusCodePattern.MatchString(r.Code) {
+		return fmt.Errorf("invalid request status code %q", r.Code)
+	}
+	if class := r.Class(); class < RequestStatusPreliminary || class > RequestStatusServerError {
+		return fmt.Errorf("invalid request status code %q: class must be 1 to 4", r.Code)
+	}
+	if strings.Contains(r.Description, ";") {
+		return fmt.Errorf("invalid request status description %q: contains a semicolon", r.Description)
+	}
+	return nil
+}
+
+// Class returns the first digit of the status code, or 0 if it isn't a
+// valid code
+func (r *RequestStatus) Class() int {
+	if r == nil || !statusCodePattern.MatchString(r.Code) {
+		return 0
+	}
+	class, err := strconv.Atoi(r.Code[:strings.IndexByte(r.Code, '.')])
+	if err != nil {
+		return 0
+	}
+	return class
+}
+
+// IsPending reports whether the request is preliminarily accepted (1.x)
+func (r *RequestStatus) IsPending() bool {
+	return r.Class() == RequestStatusPreliminary
+}
+
+// IsSuccess reports whether the request was completed (2.x)
+func (r *RequestStatus) IsSuccess() bool {
+	return r.Class() == RequestStatusSuccess
+}
+
+// IsClientError reports whether the request was rejected as invalid (3.x)
+func (r *RequestStatus) IsClientError() bool {
+	return r.Class() == RequestStatusClientError
+}
+
+// IsServerError reports whether the request couldn't be processed (4.x)
+func (r *RequestStatus) IsServerError() bool {
+	return r.Class() == RequestStatusServerError
+}
+
+// ParsedRequestStatus returns the parsed requestStatus, or nil if it isn't set
+func (e *Event) ParsedRequestStatus() (*RequestStatus, error) {
+	if e.RequestStatus == nil {
+		return nil, nil
+	}
+	return ParseRequestStatus(*e.RequestStatus)
+}
+
+// ParsedRequestStatus returns the parsed requestStatus, or nil if it isn't set
+func (t *Task) ParsedRequestStatus() (*RequestStatus, error) {
+	if t.RequestStatus == nil {
+		return nil, nil
+	}
+	return ParseRequestStatus(*t.RequestStatus)
+}
+
+// validateRequestStatus validates a requestStatus property value
+func validateRequestStatus(requestStatus *string) ValidationErrors {
+	if requestStatus == nil {
+		return nil
+	}
+	if _, err := ParseRequestStatus(*requestStatus); err != nil {
+		return ValidationErrors{{Field: "requestStatus", Value: *requestStatus, Message: err.Error()}}
+	}
+	return nil
+}
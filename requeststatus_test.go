@@ -0,0 +1,116 @@
+package jscal
+
+import "testing"
+
+func TestParseRequestStatus(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    RequestStatus
+		class   int
+		wantErr bool
+	}{
+		{input: "2.0;Success", want: RequestStatus{Code: "2.0", Description: "Success"}, class: 2},
+		{input: "1.1;Sent", want: RequestStatus{Code: "1.1", Description: "Sent"}, class: 1},
+		{
+			input: "3.1;Invalid property name;DTSTART:96-Apr-01",
+			want:  RequestStatus{Code: "3.1", Description: "Invalid property name", Extra: "DTSTART:96-Apr-01"},
+			class: 3,
+		},
+		{
+			input: "4.0;Event conflict;Date/time is busy;again",
+			want:  RequestStatus{Code: "4.0", Description: "Event conflict", Extra: "Date/time is busy;again"},
+			class: 4,
+		},
+		{input: "2.8.1;Success, repeating event ignored", want: RequestStatus{Code: "2.8.1", Description: "Success, repeating event ignored"}, class: 2},
+		{input: "2.0;", want: RequestStatus{Code: "2.0"}, class: 2},
+		{input: "2.0", wantErr: true},
+		{input: "", wantErr: true},
+		{input: "2;Success", wantErr: true},
+		{input: "2.0.1.1;Success", wantErr: true},
+		{input: "5.0;Unknown class", wantErr: true},
+		{input: "x.0;Success", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			rs, err := ParseRequestStatus(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error for %q, got %+v", tt.input, rs)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if *rs != tt.want {
+				t.Errorf("Expected %+v, got %+v", tt.want, *rs)
+			}
+			if rs.Class() != tt.class {
+				t.Errorf("Expected class %d, got %d", tt.class, rs.Class())
+			}
+			if rs.String() != tt.input {
+				t.Errorf("Expected String() %q, got %q", tt.input, rs.String())
+			}
+		})
+	}
+}
+
+func TestRequestStatusClasses(t *testing.T) {
+	tests := []struct {
+		code                                     string
+		pending, success, clientErr, serverError bool
+	}{
+		{"1.0", true, false, false, false},
+		{"2.0", false, true, false, false},
+		{"3.7", false, false, true, false},
+		{"4.1", false, false, false, true},
+		{"bogus", false, false, false, false},
+	}
+
+	for _, tt := range tests {
+		rs := NewRequestStatus(tt.code, "Status")
+		if rs.IsPending() != tt.pending || rs.IsSuccess() != tt.success ||
+			rs.IsClientError() != tt.clientErr || rs.IsServerError() != tt.serverError {
+			t.Errorf("Unexpected classification of %s: pending=%v success=%v client=%v server=%v",
+				tt.code, rs.IsPending(), rs.IsSuccess(), rs.IsClientError(), rs.IsServerError())
+		}
+	}
+
+	var nilStatus *RequestStatus
+	if nilStatus.IsSuccess() || nilStatus.String() != "" {
+		t.Errorf("Expected a nil status to be empty and not successful")
+	}
+	if err := (&RequestStatus{Code: "2.0", Description: "a;b"}).Validate(); err == nil {
+		t.Errorf("Expected error for a description containing a semicolon")
+	}
+}
+
+func TestRequestStatusValidation(t *testing.T) {
+	event := NewEvent("rs@example.com", "Meeting")
+	if rs, err := event.ParsedRequestStatus(); rs != nil || err != nil {
+		t.Errorf("Expected no request status, got %+v, %v", rs, err)
+	}
+
+	event.SetRequestStatus("2.0;Success")
+	if err := event.Validate(); err != nil {
+		t.Errorf("Expected valid event, got %v", err)
+	}
+	if rs, err := event.ParsedRequestStatus(); err != nil || !rs.IsSuccess() {
+		t.Errorf("Expected a success status, got %+v, %v", rs, err)
+	}
+
+	event.SetRequestStatus("Success")
+	if err := event.Validate(); err == nil {
+		t.Errorf("Expected validation error for requestStatus %q", "Success")
+	}
+
+	task := NewTask("rs-task@example.com", "Review")
+	task.SetRequestStatus("9.9;Nope")
+	if err := task.Validate(); err == nil {
+		t.Errorf("Expected validation error for requestStatus %q", "9.9;Nope")
+	}
+	if _, err := task.ParsedRequestStatus(); err == nil {
+		t.Errorf("Expected parse error for requestStatus %q", "9.9;Nope")
+	}
+}
@@ -82,7 +82,6 @@ func TestRFCErrata(t *testing.T) {
 
 	t.Run("Erratum 6872: Privacy property documentation", func(t *testing.T) {
 		// This erratum extends the list of shareable properties for private events
-		// We don't implement privacy filtering yet, but we can test that the properties exist
 
 		event := &Event{
 			Type:    "Event",
@@ -109,6 +108,16 @@ func TestRFCErrata(t *testing.T) {
 			t.Errorf("Private event with recurrence properties should validate: %v", err)
 		}
 
+		// Redaction keeps the recurrence properties and hides the title
+		redacted := event.Redact(ViewerSharee)
+		if redacted.Title != nil {
+			t.Error("Title of a private event should not be shared")
+		}
+		if redacted.Excluded == nil || redacted.RecurrenceId == nil ||
+			redacted.RecurrenceIdTimeZone == nil || len(redacted.RecurrenceRules) != 1 {
+			t.Errorf("Recurrence properties of a private event should be shared, got %+v", redacted)
+		}
+
 		// Verify privacy values are accepted
 		for _, privacy := range []string{"public", "private", "secret"} {
 			event.Privacy = String(privacy)
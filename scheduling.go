@@ -0,0 +1,245 @@
+package jscal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// replyStatuses are the participation statuses a participant can reply with
+var replyStatuses = map[string]bool{
+	ParticipationAccepted:  true,
+	ParticipationDeclined:  true,
+	ParticipationTentative: true,
+	ParticipationDelegated: true,
+}
+
+// Reply records a participant's response to the event and returns the
+// reply to send to the organizer (RFC 8984 Section 4.4.6, iTIP REPLY).
+//
+// The participant's participationStatus and participationComment are set,
+// scheduleSequence is incremented and scheduleUpdated set to now. The
+// returned Event has method "reply" and only the properties that identify
+// the event or occurrence, the replying participant and the owners.
+func (e *Event) Reply(participantID string, status string, comment *string) (*Event, error) {
+	participant := e.Participants[participantID]
+	if participant == nil {
+		return nil, fmt.Errorf("participant '%s' not found in event", participantID)
+	}
+	if !replyStatuses[status] {
+		return nil, fmt.Errorf("invalid reply status '%s': must be accepted, declined, tentative or delegated", status)
+	}
+
+	now := Now().UTC()
+	participant.ParticipationStatus = String(status)
+	participant.ParticipationComment = nil
+	if comment != nil {
+		participant.ParticipationComment = String(*comment)
+	}
+	if participant.ScheduleSequence != nil {
+		*participant.ScheduleSequence++
+	} else {
+		participant.ScheduleSequence = Int(1)
+	}
+	participant.ScheduleUpdated = NewUTCDateTime(now)
+
+	reply := &Event{
+		Type:                 "Event",
+		UID:                  e.UID,
+		Sequence:             e.Sequence,
+		Method:               String(MethodReply),
+		Updated:              NewUTCDateTime(now),
+		Title:                e.Title,
+		Start:                e.Start,
+		TimeZone:             e.TimeZone,
+		RecurrenceId:         e.RecurrenceId,
+		RecurrenceIdTimeZone: e.RecurrenceIdTimeZone,
+		Participants:         map[string]*Participant{participantID: participant},
+	}
+	for id, p := range e.Participants {
+		if p != nil && p.Roles[RoleOwner] && id != participantID {
+			reply.Participants[id] = p
+		}
+	}
+
+	// Don't share pointers with the event
+	return reply.Clone(), nil
+}
+
+// DelegateTo delegates the participant, stored in event under fromID, to the
+// participant with the given email address (RFC 8984 Section 4.4.6). An
+// existing participant with that email is reused; otherwise a new one is
+// added with the delegator's roles, using the email address as its id.
+//
+// Both sides are kept consistent: the delegator's delegatedTo and the
+// delegate's delegatedFrom reference each other, the delegator's status
+// becomes "delegated" and the delegate is asked to reply. The id of the
+// delegate is returned.
+func (p *Participant) DelegateTo(event *Event, fromID, toEmail string) (string, error) {
+	if event == nil || event.Participants[fromID] != p {
+		return "", fmt.Errorf("participant '%s' not found in event", fromID)
+	}
+	if ValidateEmail(toEmail) != nil {
+		return "", fmt.Errorf("invalid delegate email '%s'", toEmail)
+	}
+	if p.Email != nil && strings.EqualFold(*p.Email, toEmail) {
+		return "", fmt.Errorf("participant '%s' cannot delegate to itself", fromID)
+	}
+
+	toID := ""
+	for id, other := range event.Participants {
+		if other != nil && other.Email != nil && strings.EqualFold(*other.Email, toEmail) {
+			toID = id
+			break
+		}
+	}
+	if toID == "" {
+		toID = toEmail
+		if _, taken := event.Participants[toID]; taken {
+			return "", fmt.Errorf("participant id '%s' already in use", toID)
+		}
+		roles := make(map[string]bool)
+		for role, ok := range p.Roles {
+			if ok && role != RoleOwner {
+				roles[role] = true
+			}
+		}
+		event.AddParticipant(toID, &Participant{
+			Email:  String(toEmail),
+			SendTo: map[string]string{"imip": "mailto:" + toEmail},
+			Kind:   p.Kind,
+			Roles:  roles,
+		})
+	}
+
+	delegate := event.Participants[toID]
+	if p.DelegatedTo == nil {
+		p.DelegatedTo = make(map[string]bool)
+	}
+	p.DelegatedTo[toID] = true
+	p.ParticipationStatus = String(ParticipationDelegated)
+
+	if delegate.DelegatedFrom == nil {
+		delegate.DelegatedFrom = make(map[string]bool)
+	}
+	delegate.DelegatedFrom[fromID] = true
+	delegate.InvitedBy = String(fromID)
+	delegate.ParticipationStatus = String(ParticipationNeedsAction)
+	delegate.ExpectReply = Bool(true)
+	return toID, nil
+}
+
+// ParticipationSummary counts the replies of an event's attendees, as
+// returned by Event.ParticipationSummary
+type ParticipationSummary struct {
+	Total           int            // Number of attendees
+	Counts          map[string]int // Attendees by participationStatus
+	PendingRequired []string       // Ids of required attendees yet to reply, sorted
+	PendingOptional []string       // Ids of optional attendees yet to reply, sorted
+}
+
+// isAttendee reports whether a participant is expected to attend: it has the
+// attendee, chair or optional role, or no roles at all
+func isAttendee(p *Participant) bool {
+	if len(p.Roles) == 0 {
+		return true
+	}
+	return p.Roles[RoleAttendee] || p.Roles[RoleChair] || p.Roles[RoleOptional]
+}
+
+// participationStatus returns the participant's status, defaulting to
+// needs-action
+func participationStatus(p *Participant) string {
+	if p.ParticipationStatus == nil || *p.ParticipationStatus == "" {
+		return ParticipationNeedsAction
+	}
+	return *p.ParticipationStatus
+}
+
+// ParticipationSummary counts the event's attendees by participation status
+// and lists those who haven't replied yet. Participants that are only
+// owners, contacts or informational are not attendees and are left out.
+func (e *Event) ParticipationSummary() ParticipationSummary {
+	summary := ParticipationSummary{Counts: make(map[string]int)}
+	for _, id := range sortedKeys(e.Participants) {
+		p := e.Participants[id]
+		if p == nil || !isAttendee(p) {
+			continue
+		}
+
+		status := participationStatus(p)
+		summary.Total++
+		summary.Counts[status]++
+		if status == ParticipationNeedsAction {
+			if p.Roles[RoleOptional] {
+				summary.PendingOptional = append(summary.PendingOptional, id)
+			} else {
+				summary.PendingRequired = append(summary.PendingRequired, id)
+			}
+		}
+	}
+	return summary
+}
+
+// HasQuorum reports whether every participant with one of the given roles
+// has accepted. A delegated participant counts as accepted if one of its
+// delegates accepted. Without roles, all attendees that aren't optional
+// must have accepted.
+func (e *Event) HasQuorum(requiredRoles ...string) bool {
+	for _, p := range e.Participants {
+		if p == nil || !e.isRequired(p, requiredRoles) {
+			continue
+		}
+		if !e.hasAccepted(p, make(map[*Participant]bool)) {
+			return false
+		}
+	}
+	return true
+}
+
+// isRequired reports whether a participant counts towards HasQuorum
+func (e *Event) isRequired(p *Participant, requiredRoles []string) bool {
+	if len(requiredRoles) == 0 {
+		return isAttendee(p) && !p.Roles[RoleOptional]
+	}
+	for _, role := range requiredRoles {
+		if p.Roles[role] {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAccepted reports whether a participant, or one of its delegates, has
+// accepted
+func (e *Event) hasAccepted(p *Participant, seen map[*Participant]bool) bool {
+	if seen[p] {
+		return false
+	}
+	seen[p] = true
+
+	switch participationStatus(p) {
+	case ParticipationAccepted:
+		return true
+	case ParticipationDelegated:
+		for id, ok := range p.DelegatedTo {
+			if delegate := e.Participants[id]; ok && delegate != nil && e.hasAccepted(delegate, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// validateHideAttendees checks that an object hiding its attendees has an
+// owner to see them
+func validateHideAttendees(hideAttendees *bool, participants map[string]*Participant) ValidationErrors {
+	if hideAttendees == nil || !*hideAttendees || len(participants) == 0 {
+		return nil
+	}
+	for _, p := range participants {
+		if p != nil && p.Roles[RoleOwner] {
+			return nil
+		}
+	}
+	return ValidationErrors{{Field: "hideAttendees", Value: true, Message: "cannot be true without an owner participant"}}
+}
@@ -0,0 +1,266 @@
+package jscal
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func newScheduledEvent() *Event {
+	event := NewEvent("meeting-1", "Planning")
+	event.Start = NewLocalDateTime(time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC))
+	event.TimeZone = String("Europe/Berlin")
+	event.Sequence = Int(2)
+	event.Description = String("Quarterly planning")
+
+	organizer := NewParticipant("Olivia", "olivia@example.com")
+	organizer.Roles = map[string]bool{RoleOwner: true, RoleAttendee: true}
+	event.AddParticipant("olivia", organizer)
+
+	attendee := NewParticipant("Sam", "sam@example.com")
+	attendee.ParticipationStatus = String(ParticipationNeedsAction)
+	attendee.ExpectReply = Bool(true)
+	event.AddParticipant("sam", attendee)
+	event.AddParticipant("alex", NewParticipant("Alex", "alex@example.com"))
+	return event
+}
+
+func TestEventReply(t *testing.T) {
+	event := newScheduledEvent()
+
+	reply, err := event.Reply("sam", ParticipationAccepted, String("See you there"))
+	if err != nil {
+		t.Fatalf("Reply failed: %v", err)
+	}
+
+	// The event records the reply
+	sam := event.Participants["sam"]
+	if *sam.ParticipationStatus != ParticipationAccepted || *sam.ParticipationComment != "See you there" {
+		t.Errorf("Expected accepted with comment, got %v %v", *sam.ParticipationStatus, sam.ParticipationComment)
+	}
+	if sam.ScheduleSequence == nil || *sam.ScheduleSequence != 1 || sam.ScheduleUpdated == nil {
+		t.Errorf("Expected scheduleSequence 1 and scheduleUpdated, got %v %v", sam.ScheduleSequence, sam.ScheduleUpdated)
+	}
+	if event.Method != nil {
+		t.Errorf("Expected event method to be unchanged, got %s", *event.Method)
+	}
+
+	// The reply only carries what the organizer needs
+	if reply.UID != event.UID || *reply.Sequence != 2 || *reply.Method != MethodReply {
+		t.Errorf("Unexpected reply header %s %v %v", reply.UID, reply.Sequence, reply.Method)
+	}
+	if reply.Description != nil {
+		t.Error("Expected reply without description")
+	}
+	if len(reply.Participants) != 2 || reply.Participants["olivia"] == nil || reply.Participants["alex"] != nil {
+		t.Errorf("Expected reply with replier and owner, got %v", reply.Participants)
+	}
+	if reply.Participants["sam"] == sam {
+		t.Error("Expected reply not to share participants with the event")
+	}
+	if err := reply.Validate(); err != nil {
+		t.Errorf("Expected valid reply, got %v", err)
+	}
+
+	// A second reply bumps the sequence and clears the comment
+	if _, err := event.Reply("sam", ParticipationDeclined, nil); err != nil {
+		t.Fatal(err)
+	}
+	if *sam.ScheduleSequence != 2 || sam.ParticipationComment != nil {
+		t.Errorf("Expected scheduleSequence 2 without comment, got %d %v", *sam.ScheduleSequence, sam.ParticipationComment)
+	}
+}
+
+func TestEventReplyErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		participant string
+		status      string
+		wantErr     string
+	}{
+		{name: "unknown participant", participant: "nobody", status: ParticipationAccepted, wantErr: "not found"},
+		{name: "needs-action", participant: "sam", status: ParticipationNeedsAction, wantErr: "invalid reply status"},
+		{name: "unknown status", participant: "sam", status: "maybe", wantErr: "invalid reply status"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := newScheduledEvent()
+			_, err := event.Reply(tt.participant, tt.status, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+			if *event.Participants["sam"].ParticipationStatus != ParticipationNeedsAction {
+				t.Error("Expected event to be left unmodified")
+			}
+		})
+	}
+}
+
+func TestParticipantDelegateTo(t *testing.T) {
+	event := newScheduledEvent()
+	sam := event.Participants["sam"]
+
+	toID, err := sam.DelegateTo(event, "sam", "robin@example.com")
+	if err != nil {
+		t.Fatalf("DelegateTo failed: %v", err)
+	}
+	if toID != "robin@example.com" {
+		t.Errorf("Expected delegate id robin@example.com, got %s", toID)
+	}
+
+	robin := event.Participants[toID]
+	if robin == nil || *robin.Email != "robin@example.com" || robin.SendTo["imip"] != "mailto:robin@example.com" {
+		t.Fatalf("Expected new delegate participant, got %+v", robin)
+	}
+	if !sam.DelegatedTo[toID] || !robin.DelegatedFrom["sam"] {
+		t.Errorf("Expected delegatedTo/delegatedFrom to reference each other, got %v %v", sam.DelegatedTo, robin.DelegatedFrom)
+	}
+	if *sam.ParticipationStatus != ParticipationDelegated || *robin.ParticipationStatus != ParticipationNeedsAction {
+		t.Errorf("Unexpected statuses %s %s", *sam.ParticipationStatus, *robin.ParticipationStatus)
+	}
+	if !robin.Roles[RoleAttendee] || *robin.InvitedBy != "sam" || !*robin.ExpectReply {
+		t.Errorf("Expected delegate to inherit roles and be asked to reply, got %+v", robin)
+	}
+	if err := event.Validate(); err != nil {
+		t.Errorf("Expected valid event after delegation, got %v", err)
+	}
+
+	// Delegating to an existing participant reuses it
+	olivia := event.Participants["olivia"]
+	toID, err = olivia.DelegateTo(event, "olivia", "ALEX@example.com")
+	if err != nil || toID != "alex" || !event.Participants["alex"].DelegatedFrom["olivia"] {
+		t.Errorf("Expected delegation to existing participant alex, got %s (%v)", toID, err)
+	}
+	if len(event.Participants) != 4 {
+		t.Errorf("Expected 4 participants, got %d", len(event.Participants))
+	}
+}
+
+func TestParticipantDelegateToErrors(t *testing.T) {
+	event := newScheduledEvent()
+	sam := event.Participants["sam"]
+
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		wantErr string
+	}{
+		{name: "wrong id", from: "alex", to: "robin@example.com", wantErr: "not found"},
+		{name: "invalid email", from: "sam", to: "robin", wantErr: "invalid delegate email"},
+		{name: "self", from: "sam", to: "sam@example.com", wantErr: "cannot delegate to itself"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := sam.DelegateTo(event, tt.from, tt.to)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+	if len(sam.DelegatedTo) != 0 || len(event.Participants) != 3 {
+		t.Error("Expected event to be left unmodified")
+	}
+}
+
+func TestParticipationSummary(t *testing.T) {
+	event := newScheduledEvent()
+	event.Participants["olivia"].ParticipationStatus = String(ParticipationAccepted)
+	optional := NewParticipant("Kim", "kim@example.com")
+	optional.Roles = map[string]bool{RoleOptional: true}
+	event.AddParticipant("kim", optional)
+	declined := NewParticipant("Lee", "lee@example.com")
+	declined.ParticipationStatus = String(ParticipationDeclined)
+	event.AddParticipant("lee", declined)
+	room := NewParticipant("Room 1", "room1@example.com")
+	room.Roles = map[string]bool{RoleInformational: true}
+	event.AddParticipant("room", room)
+
+	summary := event.ParticipationSummary()
+	if summary.Total != 5 {
+		t.Errorf("Expected 5 attendees, got %d", summary.Total)
+	}
+	wantCounts := map[string]int{ParticipationAccepted: 1, ParticipationDeclined: 1, ParticipationNeedsAction: 3}
+	for status, want := range wantCounts {
+		if got := summary.Counts[status]; got != want {
+			t.Errorf("Expected %d %s, got %d", want, status, got)
+		}
+	}
+	if strings.Join(summary.PendingRequired, ",") != "alex,sam" {
+		t.Errorf("Expected pending required alex,sam, got %v", summary.PendingRequired)
+	}
+	if strings.Join(summary.PendingOptional, ",") != "kim" {
+		t.Errorf("Expected pending optional kim, got %v", summary.PendingOptional)
+	}
+}
+
+func TestHasQuorum(t *testing.T) {
+	event := newScheduledEvent()
+	event.Participants["olivia"].ParticipationStatus = String(ParticipationAccepted)
+	event.Participants["olivia"].Roles[RoleChair] = true
+
+	if !event.HasQuorum(RoleChair) {
+		t.Error("Expected quorum when the chair accepted")
+	}
+	if event.HasQuorum() {
+		t.Error("Expected no quorum while attendees are pending")
+	}
+
+	event.Participants["alex"].ParticipationStatus = String(ParticipationAccepted)
+	if _, err := event.Participants["sam"].DelegateTo(event, "sam", "robin@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if event.HasQuorum() {
+		t.Error("Expected no quorum while the delegate is pending")
+	}
+
+	event.Participants["robin@example.com"].ParticipationStatus = String(ParticipationAccepted)
+	if !event.HasQuorum() {
+		t.Error("Expected quorum once the delegate accepted")
+	}
+}
+
+func TestInvitePermissions(t *testing.T) {
+	data := []byte(`{"@type": "Event", "uid": "open-house", "start": "2025-03-01T14:00:00",
+		"mayInviteSelf": true, "mayInviteOthers": false, "hideAttendees": true}`)
+	event, err := ParseEvent(data)
+	if err != nil {
+		t.Fatalf("ParseEvent failed: %v", err)
+	}
+	if !event.GetMayInviteSelf() || event.GetMayInviteOthers() || !event.HasMayInviteOthers() || !event.GetHideAttendees() {
+		t.Errorf("Expected the permissions to be decoded, got %v %v %v", event.MayInviteSelf, event.MayInviteOthers, event.HideAttendees)
+	}
+	out, err := event.JSON()
+	if err != nil {
+		t.Fatalf("JSON failed: %v", err)
+	}
+	for _, want := range []string{`"mayInviteSelf":true`, `"mayInviteOthers":false`, `"hideAttendees":true`} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("Expected %s in %s", want, out)
+		}
+	}
+	if err := ValidateAgainstSchema(out); err != nil {
+		t.Errorf("Expected the event to match the schema, got %v", err)
+	}
+
+	// Hiding attendees needs an owner to see them
+	event = newScheduledEvent()
+	event.SetHideAttendees(true)
+	if err := event.Validate(); err != nil {
+		t.Errorf("Expected an event with an owner to be valid, got %v", err)
+	}
+	delete(event.Participants, "olivia")
+	err = event.Validate()
+	if err == nil || !strings.Contains(err.Error(), "hideAttendees cannot be true without an owner participant") {
+		t.Errorf("Expected a hideAttendees error, got %v", err)
+	}
+
+	task := NewTask("chores", "Chores")
+	task.SetHideAttendees(true)
+	task.AddParticipant("sam", NewParticipant("Sam", "sam@example.com"))
+	if err := task.Validate(); err == nil {
+		t.Error("Expected a hideAttendees error for the task")
+	}
+}
@@ -0,0 +1,295 @@
+package jscal
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+//go:embed schema/jscalendar.schema.json
+var schemaJSON []byte
+
+var (
+	schemaOnce sync.Once
+	schemaRoot map[string]interface{}
+	schemaErr  error
+
+	patternCache sync.Map // pattern string -> *regexp.Regexp
+)
+
+// Schema returns the embedded JSON Schema (draft 2020-12) describing the
+// Event, Task and Group objects jscal accepts
+func Schema() []byte {
+	return append([]byte(nil), schemaJSON...)
+}
+
+// ValidateAgainstSchema validates a JSON object, or an array of objects,
+// against the embedded schema. It implements the subset of JSON Schema the
+// embedded schema uses and returns ValidationErrors on failure.
+func ValidateAgainstSchema(data []byte) error {
+	schemaOnce.Do(func() {
+		schemaErr = json.Unmarshal(schemaJSON, &schemaRoot)
+	})
+	if schemaErr != nil {
+		return fmt.Errorf("failed to load schema: %w", schemaErr)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var instance interface{}
+	if err := decoder.Decode(&instance); err != nil {
+		return fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	var errors ValidationErrors
+	if items, ok := instance.([]interface{}); ok {
+		for i, item := range items {
+			errors = append(errors, validateSchemaObject(fmt.Sprintf("[%d]", i), item)...)
+		}
+	} else {
+		errors = validateSchemaObject("", instance)
+	}
+
+	if len(errors) > 0 {
+		return errors
+	}
+	return nil
+}
+
+// validateSchemaObject validates a top-level object against the definition
+// selected by its @type, which gives clearer errors than the root oneOf
+func validateSchemaObject(path string, instance interface{}) ValidationErrors {
+	obj, ok := instance.(map[string]interface{})
+	if !ok {
+		return ValidationErrors{{Field: path, Value: instance, Message: "must be a JSON object"}}
+	}
+
+	objType, _ := obj["@type"].(string)
+	defs := schemaRoot["$defs"].(map[string]interface{})
+	switch objType {
+	case "Event", "Task", "Group":
+		return validateSchema(defs[objType].(map[string]interface{}), instance, path)
+	default:
+		return ValidationErrors{{
+			Field:   joinPath(path, "@type"),
+			Value:   obj["@type"],
+			Message: "must be 'Event', 'Task' or 'Group'",
+		}}
+	}
+}
+
+// validateSchema validates instance against a schema node
+func validateSchema(schema map[string]interface{}, instance interface{}, path string) ValidationErrors {
+	var errors ValidationErrors
+	fail := func(format string, args ...interface{}) {
+		errors = append(errors, ValidationError{Field: path, Value: instance, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if ref, ok := schema["$ref"].(string); ok {
+		target, err := resolveSchemaRef(ref)
+		if err != nil {
+			fail("%v", err)
+			return errors
+		}
+		errors = append(errors, validateSchema(target, instance, path)...)
+	}
+
+	if allOf, ok := schema["allOf"].([]interface{}); ok {
+		for _, sub := range allOf {
+			errors = append(errors, validateSchema(sub.(map[string]interface{}), instance, path)...)
+		}
+	}
+
+	if oneOf, ok := schema["oneOf"].([]interface{}); ok {
+		var best ValidationErrors
+		matches := 0
+		for i, sub := range oneOf {
+			subErrors := validateSchema(sub.(map[string]interface{}), instance, path)
+			if len(subErrors) == 0 {
+				matches++
+			} else if i == 0 || len(subErrors) < len(best) {
+				best = subErrors
+			}
+		}
+		switch {
+		case matches == 0:
+			// Report the closest alternative
+			errors = append(errors, best...)
+		case matches > 1:
+			fail("matches more than one allowed schema")
+		}
+	}
+
+	if not, ok := schema["not"].(map[string]interface{}); ok && len(validateSchema(not, instance, path)) == 0 {
+		fail("must not match %s", formatSchemaValue(not))
+	}
+
+	if expected, ok := schema["type"].(string); ok && !schemaTypeMatches(expected, instance) {
+		fail("must be of type %s", expected)
+		return errors
+	}
+
+	if expected, ok := schema["const"]; ok && !schemaEqual(expected, instance) {
+		fail("must be %s", formatSchemaValue(expected))
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, v := range enum {
+			if schemaEqual(v, instance) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			values := make([]string, 0, len(enum))
+			for _, v := range enum {
+				values = append(values, formatSchemaValue(v))
+			}
+			fail("must be one of %s", strings.Join(values, ", "))
+		}
+	}
+
+	switch v := instance.(type) {
+	case string:
+		length := utf8.RuneCountInString(v)
+		if min, ok := schemaNumber(schema["minLength"]); ok && float64(length) < min {
+			fail("must be at least %v characters", min)
+		}
+		if max, ok := schemaNumber(schema["maxLength"]); ok && float64(length) > max {
+			fail("exceeds maximum length of %v characters", max)
+		}
+		if pattern, ok := schema["pattern"].(string); ok && !schemaPattern(pattern).MatchString(v) {
+			fail("invalid format, must match %s", pattern)
+		}
+	case json.Number:
+		n, _ := v.Float64()
+		if min, ok := schemaNumber(schema["minimum"]); ok && n < min {
+			fail("must be at least %v", min)
+		}
+		if max, ok := schemaNumber(schema["maximum"]); ok && n > max {
+			fail("must be at most %v", max)
+		}
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				errors = append(errors, validateSchema(items, item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	case map[string]interface{}:
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, name := range required {
+				if _, present := v[name.(string)]; !present {
+					errors = append(errors, ValidationError{Field: joinPath(path, name.(string)), Message: "is required"})
+				}
+			}
+		}
+
+		properties, _ := schema["properties"].(map[string]interface{})
+		for _, key := range sortedKeys(v) {
+			value := v[key]
+			if names, ok := schema["propertyNames"].(map[string]interface{}); ok {
+				errors = append(errors, validateSchema(names, key, fmt.Sprintf("%s[%s]", path, key))...)
+			}
+			if sub, ok := properties[key].(map[string]interface{}); ok {
+				errors = append(errors, validateSchema(sub, value, joinPath(path, key))...)
+				continue
+			}
+			switch additional := schema["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					errors = append(errors, ValidationError{Field: joinPath(path, key), Value: value, Message: "is not allowed"})
+				}
+			case map[string]interface{}:
+				// Map-valued properties use the field[id] path form of ValidationError
+				errors = append(errors, validateSchema(additional, value, fmt.Sprintf("%s[%s]", path, key))...)
+			}
+		}
+	}
+
+	return errors
+}
+
+// resolveSchemaRef resolves a local "#/$defs/Name" reference
+func resolveSchemaRef(ref string) (map[string]interface{}, error) {
+	name := strings.TrimPrefix(ref, "#/$defs/")
+	defs, _ := schemaRoot["$defs"].(map[string]interface{})
+	if target, ok := defs[name].(map[string]interface{}); ok && name != ref {
+		return target, nil
+	}
+	return nil, fmt.Errorf("unresolvable schema reference %s", ref)
+}
+
+// schemaTypeMatches reports whether instance has the JSON Schema type
+func schemaTypeMatches(expected string, instance interface{}) bool {
+	switch v := instance.(type) {
+	case string:
+		return expected == "string"
+	case bool:
+		return expected == "boolean"
+	case nil:
+		return expected == "null"
+	case []interface{}:
+		return expected == "array"
+	case map[string]interface{}:
+		return expected == "object"
+	case json.Number:
+		if expected == "number" {
+			return true
+		}
+		if expected == "integer" {
+			_, err := v.Int64()
+			return err == nil
+		}
+	}
+	return false
+}
+
+// schemaEqual compares a schema value with an instance value
+func schemaEqual(expected, instance interface{}) bool {
+	if n, ok := instance.(json.Number); ok {
+		f, err := n.Float64()
+		e, isNumber := expected.(float64)
+		return err == nil && isNumber && f == e
+	}
+	return reflect.DeepEqual(expected, instance)
+}
+
+// schemaNumber converts a numeric schema keyword value
+func schemaNumber(v interface{}) (float64, bool) {
+	n, ok := v.(float64)
+	return n, ok
+}
+
+// schemaPattern compiles and caches a schema pattern
+func schemaPattern(pattern string) *regexp.Regexp {
+	if re, ok := patternCache.Load(pattern); ok {
+		return re.(*regexp.Regexp)
+	}
+	re := regexp.MustCompile(pattern)
+	patternCache.Store(pattern, re)
+	return re
+}
+
+// formatSchemaValue formats a const or enum value for error messages
+func formatSchemaValue(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return "'" + s + "'"
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// joinPath appends a property name to a field path
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,160 @@
+package jscal
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSchemaIsValidJSON(t *testing.T) {
+	var schema map[string]interface{}
+	if err := json.Unmarshal(Schema(), &schema); err != nil {
+		t.Fatalf("Embedded schema is not valid JSON: %v", err)
+	}
+	defs, ok := schema["$defs"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Expected $defs in schema")
+	}
+	for _, name := range []string{"Event", "Task", "Group"} {
+		if _, ok := defs[name]; !ok {
+			t.Errorf("Expected definition for %s", name)
+		}
+	}
+}
+
+func TestValidateAgainstSchemaRFCExamples(t *testing.T) {
+	files, err := filepath.Glob("testdata/rfc8984/examples/*.json")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("No RFC examples found: %v", err)
+	}
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := ValidateAgainstSchema(data); err != nil {
+				t.Errorf("Expected RFC example to match schema, got %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateAgainstSchemaMarshaledObjects(t *testing.T) {
+	event := NewEvent("schema-event", "Schema")
+	event.Start = NewLocalDateTime(time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC))
+	event.Duration = String("PT1H")
+	event.TimeZone = String("Europe/Berlin")
+	event.AddParticipant("p1", NewParticipant("Ann", "ann@example.com"))
+	event.RecurrenceRules = []RecurrenceRule{{Type: "RecurrenceRule", Frequency: "weekly", ByDay: []NDay{{Day: "mo"}}}}
+	event.AddAlert("offset", &Alert{Type: "Alert", Trigger: NewOffsetTrigger("-PT15M")})
+	event.AddAlert("absolute", &Alert{Type: "Alert", Trigger: NewAbsoluteTrigger(time.Date(2025, 3, 1, 7, 0, 0, 0, time.UTC))})
+
+	group := NewGroup("schema-group", "Group")
+	_ = group.AddEntry(event)
+	_ = group.AddEntry(NewTask("schema-task", "Task"))
+
+	data, err := json.Marshal(group)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateAgainstSchema(data); err != nil {
+		t.Errorf("Expected marshaled group to match schema, got %v", err)
+	}
+}
+
+func TestValidateAgainstSchemaErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		wantField string
+		wantMsg   string
+	}{
+		{
+			name:      "missing start",
+			data:      `{"@type": "Event", "uid": "a"}`,
+			wantField: "start",
+			wantMsg:   "is required",
+		},
+		{
+			name:      "unknown type",
+			data:      `{"@type": "Journal", "uid": "a"}`,
+			wantField: "@type",
+			wantMsg:   "must be 'Event', 'Task' or 'Group'",
+		},
+		{
+			name:      "bad priority",
+			data:      `{"@type": "Event", "uid": "a", "start": "2025-01-01T09:00:00", "priority": 12}`,
+			wantField: "priority",
+			wantMsg:   "must be at most 9",
+		},
+		{
+			name:      "non-integer percentComplete",
+			data:      `{"@type": "Task", "uid": "a", "percentComplete": 1.5}`,
+			wantField: "percentComplete",
+			wantMsg:   "must be of type integer",
+		},
+		{
+			name:      "start with offset",
+			data:      `{"@type": "Event", "uid": "a", "start": "2025-01-01T09:00:00Z"}`,
+			wantField: "start",
+			wantMsg:   "invalid format",
+		},
+		{
+			name:      "updated with offset",
+			data:      `{"@type": "Event", "uid": "a", "start": "2025-01-01T09:00:00", "updated": "2025-01-01T09:00:00+01:00"}`,
+			wantField: "updated",
+			wantMsg:   "invalid format",
+		},
+		{
+			name:      "participant role",
+			data:      `{"@type": "Event", "uid": "a", "start": "2025-01-01T09:00:00", "participants": {"p1": {"roles": {"boss": true}}}}`,
+			wantField: "participants[p1].roles[boss]",
+			wantMsg:   "must be one of",
+		},
+		{
+			name:      "group entry",
+			data:      `{"@type": "Group", "uid": "g", "entries": [{"@type": "Task", "uid": "t", "progress": "done"}]}`,
+			wantField: "entries[0].progress",
+			wantMsg:   "must be one of",
+		},
+		{
+			name:      "absolute trigger without when",
+			data:      `{"@type": "Event", "uid": "a", "start": "2025-01-01T09:00:00", "alerts": {"a": {"@type": "Alert", "trigger": {"@type": "AbsoluteTrigger"}}}}`,
+			wantField: "alerts[a].trigger.when",
+			wantMsg:   "is required",
+		},
+		{
+			name:      "offset trigger without offset",
+			data:      `{"@type": "Event", "uid": "a", "start": "2025-01-01T09:00:00", "alerts": {"a": {"@type": "Alert", "trigger": {"@type": "OffsetTrigger"}}}}`,
+			wantField: "alerts[a].trigger.offset",
+			wantMsg:   "is required",
+		},
+		{
+			name:      "array of objects",
+			data:      `[{"@type": "Task", "uid": "t"}, {"@type": "Event", "uid": "e"}]`,
+			wantField: "[1].start",
+			wantMsg:   "is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAgainstSchema([]byte(tt.data))
+			errs, ok := err.(ValidationErrors)
+			if !ok {
+				t.Fatalf("Expected ValidationErrors, got %v", err)
+			}
+			for _, e := range errs {
+				if e.Field == tt.wantField && strings.Contains(e.Message, tt.wantMsg) {
+					return
+				}
+			}
+			t.Errorf("Expected %s error containing %q, got %v", tt.wantField, tt.wantMsg, errs)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package jscal
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// checkMethodURI checks the URI of a replyTo or sendTo method. imip needs a
+// mailto: URI and web an https: URI; other and vendor-specific methods take
+// any absolute URI.
+func checkMethodURI(method, uri string) error {
+	if method == "" {
+		return fmt.Errorf("method is required")
+	}
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme == "" {
+		return fmt.Errorf("invalid URI %q", uri)
+	}
+
+	switch method {
+	case ReplyMethodImip:
+		addresses, err := mailtoAddresses(uri)
+		if err != nil {
+			return fmt.Errorf("%s URI must be a mailto: address, got %q", method, uri)
+		}
+		for _, address := range addresses {
+			if err := ValidateEmail(address); err != nil {
+				return fmt.Errorf("%s URI has an invalid address %q: %v", method, address, err)
+			}
+		}
+	case ReplyMethodWeb:
+		if !strings.EqualFold(u.Scheme, "https") || u.Host == "" {
+			return fmt.Errorf("%s URI must be an https: URL, got %q", method, uri)
+		}
+	}
+	return nil
+}
+
+// AddReplyTo adds a method for replying to the organizer, such as
+// ReplyMethodImip with a mailto: URI
+func (e *Event) AddReplyTo(method, uri string) error {
+	if err := checkMethodURI(method, uri); err != nil {
+		return err
+	}
+	if e.ReplyTo == nil {
+		e.ReplyTo = make(map[string]string)
+	}
+	e.ReplyTo[method] = uri
+	return nil
+}
+
+// AddReplyTo adds a method for replying to the organizer, such as
+// ReplyMethodImip with a mailto: URI
+func (t *Task) AddReplyTo(method, uri string) error {
+	if err := checkMethodURI(method, uri); err != nil {
+		return err
+	}
+	if t.ReplyTo == nil {
+		t.ReplyTo = make(map[string]string)
+	}
+	t.ReplyTo[method] = uri
+	return nil
+}
+
+// AddSendTo adds a method for sending scheduling messages to the
+// participant, such as ReplyMethodImip with a mailto: URI
+func (p *Participant) AddSendTo(method, uri string) error {
+	if err := checkMethodURI(method, uri); err != nil {
+		return err
+	}
+	if p.SendTo == nil {
+		p.SendTo = make(map[string]string)
+	}
+	p.SendTo[method] = uri
+	return nil
+}
+
+// validateMethodURIs validates a replyTo or sendTo map at field
+func validateMethodURIs(field string, methods map[string]string) ValidationErrors {
+	var errors ValidationErrors
+	for _, method := range sortedKeys(methods) {
+		if err := checkMethodURI(method, methods[method]); err != nil {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("%s[%s]", field, method),
+				Value:   methods[method],
+				Message: err.Error(),
+			})
+		}
+	}
+	return errors
+}
@@ -0,0 +1,96 @@
+package jscal
+
+import "testing"
+
+func TestCheckMethodURI(t *testing.T) {
+	tests := []struct {
+		method  string
+		uri     string
+		wantErr bool
+	}{
+		{ReplyMethodImip, "mailto:organizer@example.com", false},
+		{ReplyMethodImip, "MAILTO:organizer@example.com", false},
+		{ReplyMethodImip, "organizer@example.com", true},
+		{ReplyMethodImip, "mailto:organizer", true},
+		{ReplyMethodImip, "https://example.com/reply", true},
+		{ReplyMethodWeb, "https://example.com/reply", false},
+		{ReplyMethodWeb, "http://example.com/reply", true},
+		{ReplyMethodWeb, "https:///reply", true},
+		{ReplyMethodOther, "urn:uuid:f81d4fae-7dec-11d0-a765-00a0c91e6bf6", false},
+		{ReplyMethodOther, "not a uri", true},
+		{"x-vendor", "xmpp:organizer@example.com", false},
+		{"", "mailto:organizer@example.com", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.uri, func(t *testing.T) {
+			err := checkMethodURI(tt.method, tt.uri)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestAddReplyToAndSendTo(t *testing.T) {
+	event := NewEvent("reply@example.com", "Meeting")
+	if err := event.AddReplyTo(ReplyMethodImip, "mailto:organizer@example.com"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := event.AddReplyTo(ReplyMethodWeb, "mailto:organizer@example.com"); err == nil {
+		t.Errorf("Expected error for a web method with a mailto: URI")
+	}
+	if len(event.ReplyTo) != 1 || event.ReplyTo[ReplyMethodImip] != "mailto:organizer@example.com" {
+		t.Errorf("Expected only the imip replyTo, got %v", event.ReplyTo)
+	}
+
+	task := NewTask("reply-task@example.com", "Review")
+	if err := task.AddReplyTo(ReplyMethodWeb, "https://example.com/rsvp"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if task.ReplyTo[ReplyMethodWeb] != "https://example.com/rsvp" {
+		t.Errorf("Expected web replyTo, got %v", task.ReplyTo)
+	}
+
+	participant := NewParticipant("Jane", "jane@example.com")
+	if err := participant.AddSendTo(ReplyMethodImip, "jane@example.com"); err == nil {
+		t.Errorf("Expected error for an imip address without mailto:")
+	}
+	if err := participant.AddSendTo(ReplyMethodImip, "mailto:jane@example.com"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if participant.SendTo[ReplyMethodImip] != "mailto:jane@example.com" {
+		t.Errorf("Expected imip sendTo, got %v", participant.SendTo)
+	}
+}
+
+func TestValidateMethodURIs(t *testing.T) {
+	event := NewEvent("validate-reply@example.com", "Meeting")
+	event.ReplyTo = map[string]string{ReplyMethodImip: "organizer@example.com"}
+	event.AddParticipant("p1", &Participant{
+		SendTo: map[string]string{ReplyMethodWeb: "ftp://example.com/rsvp", ReplyMethodImip: "mailto:p1@example.com"},
+	})
+
+	err := event.Validate()
+	errs, ok := err.(ValidationErrors)
+	if !ok {
+		t.Fatalf("Expected ValidationErrors, got %v", err)
+	}
+	fields := map[string]bool{}
+	for _, e := range errs {
+		fields[e.Field] = true
+	}
+	if len(errs) != 2 || !fields["replyTo[imip]"] || !fields["participants[p1].sendTo[web]"] {
+		t.Errorf("Expected errors for replyTo[imip] and participants[p1].sendTo[web], got %v", errs)
+	}
+
+	task := NewTask("validate-reply-task@example.com", "Review")
+	task.ReplyTo = map[string]string{ReplyMethodWeb: "https://example.com/rsvp"}
+	if err := task.Validate(); err != nil {
+		t.Errorf("Expected valid task, got %v", err)
+	}
+	task.ReplyTo[ReplyMethodOther] = ""
+	if err := task.Validate(); err == nil {
+		t.Errorf("Expected error for an empty replyTo URI")
+	}
+}
@@ -0,0 +1,251 @@
+package jscal
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Shift moves the event by d, keeping its recurrence consistent: start, the
+// recurrenceRules and excludedRecurrenceRules, the recurrenceOverrides keys
+// and the start they patch all move by d, in wall clock time, so that every
+// instance moves by d. In rules, until moves by d and the byDay, byMonthDay,
+// byYearDay and byHour parts move by the days and hours the instances move.
+// Alerts move with the event, so absolute triggers and acknowledgments are
+// shifted as well and an acknowledged alert stays acknowledged.
+//
+// Rules whose instances wouldn't all move by d once their parts are moved,
+// such as a byMonthDay of 31 moved by a day or a byDay with nthOfPeriod,
+// can't be shifted; Shift then returns an error and leaves the event as it
+// is. The recurrenceId of an instance is its identity and isn't changed.
+func (e *Event) Shift(d time.Duration) error {
+	if d == 0 {
+		return nil
+	}
+	rules, err := shiftRules(e.RecurrenceRules, e.Start, d)
+	if err != nil {
+		return fmt.Errorf("event %s: %w", e.UID, err)
+	}
+	excluded, err := shiftRules(e.ExcludedRecurrenceRules, e.Start, d)
+	if err != nil {
+		return fmt.Errorf("event %s: excluded %w", e.UID, err)
+	}
+	e.RecurrenceRules, e.ExcludedRecurrenceRules = rules, excluded
+	if e.Start != nil {
+		start := e.Start.Add(d)
+		e.Start = &start
+	}
+
+	if len(e.RecurrenceOverrides) > 0 {
+		overrides := make(map[string]map[string]interface{}, len(e.RecurrenceOverrides))
+		for key, patch := range e.RecurrenceOverrides {
+			if id, err := ParseLocalDateTime(key); err == nil {
+				key = LocalDateTime(wallClock(*id).Add(d)).String()
+			}
+			if s, ok := patch["start"].(string); ok {
+				if start, err := ParseLocalDateTime(s); err == nil {
+					patch["start"] = LocalDateTime(wallClock(*start).Add(d)).String()
+				}
+			}
+			overrides[key] = patch
+		}
+		e.RecurrenceOverrides = overrides
+	}
+
+	for _, alert := range e.Alerts {
+		if alert == nil {
+			continue
+		}
+		if trigger, ok := alert.Trigger.(*AbsoluteTrigger); ok {
+			trigger.When = trigger.When.Add(d)
+		}
+		if alert.Acknowledged != nil {
+			acknowledged := alert.Acknowledged.Add(d)
+			alert.Acknowledged = &acknowledged
+		}
+	}
+	e.Touch()
+	return nil
+}
+
+// shiftRules returns copies of rules moved by d for an event starting at
+// start, or an error if one of them can't be moved
+func shiftRules(rules []RecurrenceRule, start *LocalDateTime, d time.Duration) ([]RecurrenceRule, error) {
+	if rules == nil {
+		return nil, nil
+	}
+	shifted := make([]RecurrenceRule, len(rules))
+	for i, rule := range rules {
+		var err error
+		if shifted[i], err = shiftRule(rule, start, d); err != nil {
+			return nil, fmt.Errorf("recurrence rule %d: %w", i, err)
+		}
+	}
+	return shifted, nil
+}
+
+// shiftRule returns a copy of rule moved by d. The instances of rules with
+// byHour move by the whole days and hours d adds to those hours, the
+// others by the days d adds to the start. Sub-daily rules without byHour
+// repeat at every hour, so their date parts can only move by whole days.
+func shiftRule(rule RecurrenceRule, start *LocalDateTime, d time.Duration) (RecurrenceRule, error) {
+	if rule.Until != nil {
+		until := rule.Until.Add(d)
+		rule.Until = &until
+	}
+
+	subDaily := rule.Frequency == FrequencyHourly || rule.Frequency == FrequencyMinutely || rule.Frequency == FrequencySecondly
+	hasDateParts := len(rule.ByDay)+len(rule.ByMonthDay)+len(rule.ByYearDay)+len(rule.ByWeekNo)+len(rule.ByMonth) > 0
+
+	var days int
+	switch {
+	case len(rule.ByHour)+len(rule.ByMinute)+len(rule.BySecond) > 0 && d%time.Hour != 0:
+		return rule, fmt.Errorf("byHour, byMinute and bySecond can only move by whole hours, not %s", d)
+	case len(rule.ByHour) > 0:
+		hours := int((d % (24 * time.Hour)) / time.Hour)
+		byHour := make([]int, len(rule.ByHour))
+		for i, h := range rule.ByHour {
+			carry := floorDiv(h+hours, 24)
+			if i > 0 && carry != days {
+				return rule, fmt.Errorf("moving byHour by %dh moves some instances to another day", hours)
+			}
+			days, byHour[i] = carry, h+hours-24*carry
+		}
+		days += int(d / (24 * time.Hour))
+		rule.ByHour = byHour
+	case subDaily && hasDateParts && d%(24*time.Hour) != 0:
+		return rule, fmt.Errorf("the date parts of a %s rule can only move by whole days, not %s", rule.Frequency, d)
+	case subDaily:
+		days = int(d / (24 * time.Hour))
+	case start != nil:
+		from := wallClock(*start)
+		to := from.Add(d)
+		days = int(civilDate(to).Sub(civilDate(from)) / (24 * time.Hour))
+	}
+	if days == 0 {
+		return rule, nil
+	}
+
+	switch {
+	case len(rule.BySetPos) > 0:
+		return rule, fmt.Errorf("a rule with bySetPos can't move by %d days", days)
+	case len(rule.ByWeekNo) > 0:
+		return rule, fmt.Errorf("a rule with byWeekNo can't move by %d days", days)
+	case len(rule.ByMonth) > 0 && len(rule.ByMonthDay) == 0:
+		return rule, fmt.Errorf("a rule with byMonth and no byMonthDay can't move by %d days", days)
+	}
+
+	if len(rule.ByDay) > 0 {
+		if (rule.Frequency == FrequencyMonthly || rule.Frequency == FrequencyYearly) &&
+			rule.Interval != nil && *rule.Interval > 1 && len(rule.ByMonthDay)+len(rule.ByYearDay) == 0 {
+			return rule, fmt.Errorf("byDay of a %s rule with an interval can't move by %d days", rule.Frequency, days)
+		}
+		byDay := make([]NDay, len(rule.ByDay))
+		for i, nday := range rule.ByDay {
+			if nday.NthOfPeriod != nil && *nday.NthOfPeriod != 0 {
+				return rule, fmt.Errorf("byDay with nthOfPeriod can't move by %d days", days)
+			}
+			weekday, ok := weekdays[strings.ToLower(nday.Day)]
+			if !ok {
+				return rule, fmt.Errorf("invalid byDay day %q", nday.Day)
+			}
+			byDay[i] = NDay{Day: weekdayNames[((int(weekday)+days)%7+7)%7], NthOfPeriod: nday.NthOfPeriod}
+		}
+		rule.ByDay = byDay
+
+		// Weekly rules with an interval group their days by week, which
+		// must start as many days later
+		if rule.Frequency == FrequencyWeekly && rule.Interval != nil && *rule.Interval > 1 {
+			first := 0
+			if rule.FirstDayOfWeek != nil {
+				first = *rule.FirstDayOfWeek
+			}
+			first = ((first+days)%7 + 7) % 7
+			rule.FirstDayOfWeek = &first
+		}
+	}
+
+	if len(rule.ByMonthDay) > 0 {
+		byMonthDay := make([]int, len(rule.ByMonthDay))
+		for i, day := range rule.ByMonthDay {
+			// Only days that every month has stay in their month
+			moved := day + days
+			if day == 0 || day < -28 || day > 28 || moved == 0 || moved < -28 || moved > 28 || (day > 0) != (moved > 0) {
+				return rule, fmt.Errorf("byMonthDay %d can't move by %d days", day, days)
+			}
+			byMonthDay[i] = moved
+		}
+		rule.ByMonthDay = byMonthDay
+	}
+
+	if len(rule.ByYearDay) > 0 {
+		byYearDay := make([]int, len(rule.ByYearDay))
+		for i, day := range rule.ByYearDay {
+			moved := day + days
+			if day == 0 || day < -365 || day > 365 || moved == 0 || moved < -365 || moved > 365 || (day > 0) != (moved > 0) {
+				return rule, fmt.Errorf("byYearDay %d can't move by %d days", day, days)
+			}
+			byYearDay[i] = moved
+		}
+		rule.ByYearDay = byYearDay
+	}
+
+	// Without date parts, monthly and yearly rules repeat on the start's
+	// day of the month, which must exist in every month
+	if start != nil && len(rule.ByDay)+len(rule.ByMonthDay)+len(rule.ByYearDay) == 0 &&
+		(rule.Frequency == FrequencyMonthly || rule.Frequency == FrequencyYearly) {
+		from := wallClock(*start)
+		to := from.AddDate(0, 0, days)
+		if from.Day() > 28 || to.Day() > 28 {
+			return rule, fmt.Errorf("a %s rule starting on day %d can't move to day %d", rule.Frequency, from.Day(), to.Day())
+		}
+	}
+	return rule, nil
+}
+
+// weekdayNames maps time.Weekday to NDay day names
+var weekdayNames = [7]string{DaySunday, DayMonday, DayTuesday, DayWednesday, DayThursday, DayFriday, DaySaturday}
+
+// civilDate returns the midnight starting the day of t
+func civilDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// floorDiv divides a by b, rounding toward negative infinity
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+// RescheduleTo moves the event to start at newStart, with Shift. With
+// keepDuration the event keeps its length; otherwise it keeps its end and
+// the duration changes, which fails if newStart is after the end.
+func (e *Event) RescheduleTo(newStart LocalDateTime, keepDuration bool) error {
+	if e.Start == nil {
+		return fmt.Errorf("event %s has no start", e.UID)
+	}
+	d := wallClock(newStart).Sub(wallClock(*e.Start))
+
+	var duration time.Duration
+	if !keepDuration {
+		if e.Duration != nil {
+			var err error
+			if duration, err = e.GetDuration(); err != nil {
+				return fmt.Errorf("event %s: %w", e.UID, err)
+			}
+		}
+		if d > duration {
+			return fmt.Errorf("event %s: new start %s is after the end", e.UID, newStart)
+		}
+	}
+	if err := e.Shift(d); err != nil {
+		return err
+	}
+	if !keepDuration {
+		e.Duration = String(FormatDuration(duration - d))
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package jscal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEventShift(t *testing.T) {
+	event := newDailyStandup()
+	until := NewLocalDateTime(time.Date(2025, 3, 20, 9, 0, 0, 0, time.UTC))
+	event.RecurrenceRules[0].Count = nil
+	event.RecurrenceRules[0].Until = until
+	acknowledged := time.Date(2025, 3, 3, 8, 50, 0, 0, time.UTC)
+	event.Alerts = map[string]*Alert{
+		"relative": {Type: "Alert", Trigger: NewOffsetTrigger("-PT10M"), Acknowledged: NewUTCDateTime(acknowledged)},
+		"absolute": {Type: "Alert", Trigger: &AbsoluteTrigger{Type: "AbsoluteTrigger", When: UTCDateTime(time.Date(2025, 3, 3, 8, 0, 0, 0, time.UTC))}},
+	}
+	before, _ := event.Clone().Occurrences(*NewLocalDateTime(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)),
+		*NewLocalDateTime(time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)))
+
+	if err := event.Shift(90 * time.Minute); err != nil {
+		t.Fatalf("Shift failed: %v", err)
+	}
+
+	if event.Start.String() != "2025-03-03T10:30:00" {
+		t.Errorf("Expected start 2025-03-03T10:30:00, got %s", event.Start)
+	}
+	if got := event.RecurrenceRules[0].Until.String(); got != "2025-03-20T10:30:00" {
+		t.Errorf("Expected until 2025-03-20T10:30:00, got %s", got)
+	}
+	for _, key := range []string{"2025-03-04T10:30:00", "2025-03-08T10:30:00", "2025-03-10T10:30:00"} {
+		if _, ok := event.RecurrenceOverrides[key]; !ok {
+			t.Errorf("Expected override %s, got %v", key, event.RecurrenceOverrides)
+		}
+	}
+	if got := event.RecurrenceOverrides["2025-03-10T10:30:00"]["start"]; got != "2025-03-10T11:30:00" {
+		t.Errorf("Expected the patched start shifted to 2025-03-10T11:30:00, got %v", got)
+	}
+	if got := event.Alerts["absolute"].Trigger.(*AbsoluteTrigger).When; !got.Time().Equal(time.Date(2025, 3, 3, 9, 30, 0, 0, time.UTC)) {
+		t.Errorf("Expected the absolute trigger shifted, got %s", got)
+	}
+	if got := *event.Alerts["relative"].Acknowledged; !got.Equal(NewUTCDateTime(acknowledged.Add(90 * time.Minute))) {
+		t.Errorf("Expected the acknowledgment shifted, got %s", got)
+	}
+
+	// Every occurrence, overridden or not, moves by the same amount
+	after, _ := event.Occurrences(*NewLocalDateTime(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)),
+		*NewLocalDateTime(time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)))
+	if len(after) != len(before) {
+		t.Fatalf("Expected %d occurrences, got %d", len(before), len(after))
+	}
+	for i := range after {
+		if got := after[i].Start().Sub(before[i].Start()); got != 90*time.Minute {
+			t.Errorf("Occurrence %d: expected to move 1h30m, moved %s", i, got)
+		}
+		if after[i].Event.GetTitle() != before[i].Event.GetTitle() {
+			t.Errorf("Occurrence %d: expected title %q, got %q", i, before[i].Event.GetTitle(), after[i].Event.GetTitle())
+		}
+	}
+}
+
+func TestEventShiftByDay(t *testing.T) {
+	event := NewEvent("weekly", "Planning")
+	event.Start = NewLocalDateTime(time.Date(2025, 3, 3, 10, 0, 0, 0, time.UTC))
+	rule := NewRecurrenceRule(FrequencyWeekly)
+	rule.ByDay = []NDay{{Day: DayMonday}}
+	event.RecurrenceRules = []RecurrenceRule{*rule}
+	event.RecurrenceOverrides = map[string]map[string]interface{}{
+		"2025-03-17T10:00:00": {"title": "Special"},
+	}
+	from := *NewLocalDateTime(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+	to := *NewLocalDateTime(time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC))
+
+	if err := event.Shift(24 * time.Hour); err != nil {
+		t.Fatalf("Shift failed: %v", err)
+	}
+	if got := event.RecurrenceRules[0].ByDay; len(got) != 1 || got[0].Day != DayTuesday {
+		t.Errorf("Expected byDay tu, got %+v", got)
+	}
+	occurrences, err := event.Occurrences(from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"2025-03-04T10:00:00", "2025-03-11T10:00:00", "2025-03-18T10:00:00", "2025-03-25T10:00:00"}
+	if len(occurrences) != len(expected) {
+		t.Fatalf("Expected %d occurrences, got %d", len(expected), len(occurrences))
+	}
+	for i, occurrence := range occurrences {
+		if got := occurrence.RecurrenceId.String(); got != expected[i] {
+			t.Errorf("Occurrence %d: expected %s, got %s", i, expected[i], got)
+		}
+		title := "Planning"
+		if expected[i] == "2025-03-18T10:00:00" {
+			title = "Special"
+		}
+		if occurrence.Event.GetTitle() != title {
+			t.Errorf("Occurrence %d: expected title %q, got %q", i, title, occurrence.Event.GetTitle())
+		}
+	}
+
+	// Back across midnight by an hour, days and hours both move
+	event.RecurrenceRules[0].ByHour = []int{0}
+	event.Start = NewLocalDateTime(time.Date(2025, 3, 4, 0, 30, 0, 0, time.UTC))
+	if err := event.Shift(-time.Hour); err != nil {
+		t.Fatalf("Shift failed: %v", err)
+	}
+	if got := event.RecurrenceRules[0]; got.ByDay[0].Day != DayMonday || got.ByHour[0] != 23 {
+		t.Errorf("Expected byDay mo and byHour 23, got %+v", got)
+	}
+}
+
+func TestEventShiftErrors(t *testing.T) {
+	nth := 2
+	tests := []struct {
+		name  string
+		rule  RecurrenceRule
+		shift time.Duration
+	}{
+		{"nthOfPeriod", RecurrenceRule{Frequency: FrequencyMonthly, ByDay: []NDay{{Day: DayMonday, NthOfPeriod: &nth}}}, 24 * time.Hour},
+		{"byMonthDay past 28", RecurrenceRule{Frequency: FrequencyMonthly, ByMonthDay: []int{28}}, 24 * time.Hour},
+		{"byHour across midnight for some", RecurrenceRule{Frequency: FrequencyDaily, ByHour: []int{9, 23}}, 2 * time.Hour},
+		{"byHour by minutes", RecurrenceRule{Frequency: FrequencyDaily, ByHour: []int{9}}, 30 * time.Minute},
+		{"bySetPos", RecurrenceRule{Frequency: FrequencyMonthly, ByDay: []NDay{{Day: DayMonday}}, BySetPos: []int{-1}}, 24 * time.Hour},
+		{"start on the 31st", RecurrenceRule{Frequency: FrequencyMonthly}, -24 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := NewEvent("shift", "Shift")
+			event.Start = NewLocalDateTime(time.Date(2025, 1, 31, 10, 0, 0, 0, time.UTC))
+			tt.rule.Type = "RecurrenceRule"
+			event.RecurrenceRules = []RecurrenceRule{tt.rule}
+			if err := event.Shift(tt.shift); err == nil {
+				t.Fatalf("Expected an error, got rule %+v", event.RecurrenceRules[0])
+			}
+			if event.Start.String() != "2025-01-31T10:00:00" {
+				t.Errorf("Expected the event unchanged, got start %s", event.Start)
+			}
+		})
+	}
+}
+
+func TestEventRescheduleTo(t *testing.T) {
+	tests := []struct {
+		name         string
+		newStart     time.Time
+		keepDuration bool
+		wantDuration string
+		wantErr      bool
+	}{
+		{"keep duration", time.Date(2025, 3, 4, 14, 0, 0, 0, time.UTC), true, "PT1H", false},
+		{"keep end", time.Date(2025, 3, 3, 9, 15, 0, 0, time.UTC), false, "PT45M", false},
+		{"earlier, keep end", time.Date(2025, 3, 3, 8, 30, 0, 0, time.UTC), false, "PT1H30M", false},
+		{"after the end", time.Date(2025, 3, 3, 11, 0, 0, 0, time.UTC), false, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := newDailyStandup()
+			event.Duration = String("PT1H")
+			err := event.RescheduleTo(*NewLocalDateTime(tt.newStart), tt.keepDuration)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr {
+				if event.Start.String() != "2025-03-03T09:00:00" {
+					t.Errorf("Expected the event unchanged, got start %s", event.Start)
+				}
+				return
+			}
+			if event.Start.String() != NewLocalDateTime(tt.newStart).String() || *event.Duration != tt.wantDuration {
+				t.Errorf("Expected %s for %s, got %s for %s", NewLocalDateTime(tt.newStart), tt.wantDuration, event.Start, *event.Duration)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package jscal
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// AcknowledgeAlert records that the user dismissed the alert with the given
+// id at the time at, in its acknowledged property. Acknowledging a snooze
+// alert acknowledges the alert it snoozed; either way the pending snooze
+// alerts of that alert are removed.
+func (e *Event) AcknowledgeAlert(id string, at time.Time) error {
+	if err := acknowledgeAlert(e.Alerts, id, at); err != nil {
+		return fmt.Errorf("event %s: %w", e.UID, err)
+	}
+	return nil
+}
+
+// SnoozeAlert snoozes the alert with the given id until the given time as
+// RFC 8984 Section 4.5.2 describes: the alert is acknowledged now and a
+// snooze alert firing at until is added, with a "parent" relation to it.
+// A previous snooze alert is replaced, and snoozing a snooze alert snoozes
+// the alert it belongs to. The id of the snooze alert is returned.
+func (e *Event) SnoozeAlert(id string, until time.Time) (string, error) {
+	snoozeID, err := snoozeAlert(e.Alerts, id, until, Now())
+	if err != nil {
+		return "", fmt.Errorf("event %s: %w", e.UID, err)
+	}
+	return snoozeID, nil
+}
+
+// SnoozeUntil returns when the alert with the given id, or the alert a
+// snooze alert with that id belongs to, fires again after being snoozed,
+// and false if it isn't snoozed
+func (e *Event) SnoozeUntil(id string) (time.Time, bool) {
+	return snoozeUntil(e.Alerts, id)
+}
+
+// AcknowledgeAlert records that the user dismissed an alert, see
+// Event.AcknowledgeAlert
+func (t *Task) AcknowledgeAlert(id string, at time.Time) error {
+	if err := acknowledgeAlert(t.Alerts, id, at); err != nil {
+		return fmt.Errorf("task %s: %w", t.UID, err)
+	}
+	return nil
+}
+
+// SnoozeAlert snoozes an alert until the given time, see Event.SnoozeAlert
+func (t *Task) SnoozeAlert(id string, until time.Time) (string, error) {
+	snoozeID, err := snoozeAlert(t.Alerts, id, until, Now())
+	if err != nil {
+		return "", fmt.Errorf("task %s: %w", t.UID, err)
+	}
+	return snoozeID, nil
+}
+
+// SnoozeUntil returns when a snoozed alert fires again, see
+// Event.SnoozeUntil
+func (t *Task) SnoozeUntil(id string) (time.Time, bool) {
+	return snoozeUntil(t.Alerts, id)
+}
+
+// snoozedAlert returns the id of the alert a snooze alert belongs to, the
+// alert its "parent" relation names, or id itself for other alerts
+func snoozedAlert(alerts map[string]*Alert, id string) string {
+	if alert := alerts[id]; alert != nil {
+		for parentID, relation := range alert.RelatedTo {
+			if relation != nil && relation.Relation[RelationTypeParent] && alerts[parentID] != nil && parentID != id {
+				return parentID
+			}
+		}
+	}
+	return id
+}
+
+// snoozesOf returns the ids of the snooze alerts of an alert, sorted
+func snoozesOf(alerts map[string]*Alert, id string) []string {
+	var ids []string
+	for snoozeID, alert := range alerts {
+		if snoozeID == id || alert == nil {
+			continue
+		}
+		if relation := alert.RelatedTo[id]; relation != nil && relation.Relation[RelationTypeParent] {
+			ids = append(ids, snoozeID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func acknowledgeAlert(alerts map[string]*Alert, id string, at time.Time) error {
+	if alerts[id] == nil {
+		return fmt.Errorf("alert %s not found", id)
+	}
+	id = snoozedAlert(alerts, id)
+	for _, snoozeID := range snoozesOf(alerts, id) {
+		delete(alerts, snoozeID)
+	}
+	alerts[id].Acknowledged = NewUTCDateTime(at)
+	return nil
+}
+
+func snoozeAlert(alerts map[string]*Alert, id string, until, now time.Time) (string, error) {
+	if alerts[id] == nil {
+		return "", fmt.Errorf("alert %s not found", id)
+	}
+	if !until.After(now) {
+		return "", fmt.Errorf("alert %s: cannot snooze until %s, which has passed", id, until.UTC().Format(time.RFC3339))
+	}
+	id = snoozedAlert(alerts, id)
+	if err := acknowledgeAlert(alerts, id, now); err != nil {
+		return "", err
+	}
+
+	snoozeID := id + "-snooze"
+	for i := 2; alerts[snoozeID] != nil; i++ {
+		snoozeID = fmt.Sprintf("%s-snooze%d", id, i)
+	}
+	alerts[snoozeID] = &Alert{
+		Type:      "Alert",
+		Trigger:   NewAbsoluteTrigger(until),
+		Action:    alerts[id].Action,
+		RelatedTo: map[string]*Relation{id: {Type: "Relation", Relation: map[string]bool{RelationTypeParent: true}}},
+	}
+	return snoozeID, nil
+}
+
+func snoozeUntil(alerts map[string]*Alert, id string) (time.Time, bool) {
+	var until time.Time
+	for _, snoozeID := range snoozesOf(alerts, snoozedAlert(alerts, id)) {
+		alert := alerts[snoozeID]
+		if trigger, ok := alert.Trigger.(*AbsoluteTrigger); ok && alert.Acknowledged == nil && trigger.When.Time().After(until) {
+			until = trigger.When.Time()
+		}
+	}
+	return until, !until.IsZero()
+}
@@ -0,0 +1,122 @@
+package jscal
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestSnoozeAlert(t *testing.T) {
+	event := NewEvent("snooze-test", "Dentist")
+	event.Start = NewLocalDateTime(time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC))
+	event.AddAlert("15-min", &Alert{Type: "Alert", Trigger: NewOffsetTrigger("-PT15M"), Action: String(AlertActionEmail)})
+	until := time.Now().Add(10 * time.Minute).Truncate(time.Second)
+
+	snoozeID, err := event.SnoozeAlert("15-min", until)
+	if err != nil {
+		t.Fatalf("SnoozeAlert failed: %v", err)
+	}
+	if snoozeID != "15-min-snooze" {
+		t.Errorf("Expected snooze alert 15-min-snooze, got %s", snoozeID)
+	}
+	if event.Alerts["15-min"].Acknowledged == nil {
+		t.Error("Expected the snoozed alert to be acknowledged")
+	}
+	snooze := event.Alerts[snoozeID]
+	if trigger, ok := snooze.Trigger.(*AbsoluteTrigger); !ok || !trigger.When.Time().Equal(until) {
+		t.Errorf("Expected an absolute trigger at %s, got %+v", until, snooze.Trigger)
+	}
+	if !snooze.RelatedTo["15-min"].Relation[RelationTypeParent] || snooze.GetAction() != AlertActionEmail {
+		t.Errorf("Expected a parent relation and the email action, got %+v", snooze)
+	}
+	if got, ok := event.SnoozeUntil("15-min"); !ok || !got.Equal(until) {
+		t.Errorf("Expected SnoozeUntil %s, got %s, %v", until, got, ok)
+	}
+	if err := event.Validate(); err != nil {
+		t.Errorf("Expected the snoozed event to be valid, got %v", err)
+	}
+
+	// Snoozing the snooze alert replaces it
+	later := until.Add(time.Hour)
+	again, err := event.SnoozeAlert(snoozeID, later)
+	if err != nil {
+		t.Fatalf("SnoozeAlert of the snooze alert failed: %v", err)
+	}
+	if len(event.Alerts) != 2 || again != "15-min-snooze" {
+		t.Errorf("Expected one snooze alert, got %d alerts and %s", len(event.Alerts), again)
+	}
+	if got, _ := event.SnoozeUntil(again); !got.Equal(later) {
+		t.Errorf("Expected SnoozeUntil %s, got %s", later, got)
+	}
+
+	// The state survives JSON
+	data, err := json.Marshal(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseEvent(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := parsed.SnoozeUntil("15-min"); !ok || !got.Equal(later) {
+		t.Errorf("Expected SnoozeUntil %s after JSON, got %s, %v", later, got, ok)
+	}
+}
+
+func TestAcknowledgeAlert(t *testing.T) {
+	event := NewEvent("snooze-test", "Dentist")
+	event.Start = NewLocalDateTime(time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC))
+	event.AddAlert("15-min", &Alert{Type: "Alert", Trigger: NewOffsetTrigger("-PT15M"), Action: String(AlertActionEmail)})
+	at := time.Date(2025, 3, 10, 8, 50, 0, 0, time.UTC)
+	if err := event.AcknowledgeAlert("15-min", at); err != nil {
+		t.Fatalf("AcknowledgeAlert failed: %v", err)
+	}
+	if got := event.Alerts["15-min"].Acknowledged.Time(); !got.Equal(at) {
+		t.Errorf("Expected acknowledged %s, got %s", at, got)
+	}
+
+	// Dismissing a snooze alert removes it and acknowledges its parent
+	snoozeID, err := event.SnoozeAlert("15-min", time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := event.AcknowledgeAlert(snoozeID, at); err != nil {
+		t.Fatalf("AcknowledgeAlert of the snooze alert failed: %v", err)
+	}
+	if _, ok := event.Alerts[snoozeID]; ok || len(event.Alerts) != 1 {
+		t.Errorf("Expected the snooze alert to be removed, got %v", event.Alerts)
+	}
+	if _, ok := event.SnoozeUntil("15-min"); ok {
+		t.Error("Expected the alert not to be snoozed")
+	}
+	if got := event.Alerts["15-min"].Acknowledged.Time(); !got.Equal(at) {
+		t.Errorf("Expected acknowledged %s, got %s", at, got)
+	}
+}
+
+func TestSnoozeAlertErrors(t *testing.T) {
+	event := NewEvent("snooze-test", "Dentist")
+	event.Start = NewLocalDateTime(time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC))
+	event.AddAlert("15-min", &Alert{Type: "Alert", Trigger: NewOffsetTrigger("-PT15M"), Action: String(AlertActionEmail)})
+	if _, err := event.SnoozeAlert("missing", time.Now().Add(time.Hour)); err == nil {
+		t.Error("Expected an error for an unknown alert")
+	}
+	if _, err := event.SnoozeAlert("15-min", time.Now().Add(-time.Minute)); err == nil {
+		t.Error("Expected an error for a time that has passed")
+	}
+	if err := event.AcknowledgeAlert("missing", time.Now()); err == nil {
+		t.Error("Expected an error for an unknown alert")
+	}
+	if event.Alerts["15-min"].Acknowledged != nil || len(event.Alerts) != 1 {
+		t.Errorf("Expected the event to be unchanged, got %v", event.Alerts)
+	}
+
+	task := NewTask("snooze-task", "Call back")
+	task.AddAlert("a", &Alert{Type: "Alert", Trigger: NewOffsetTrigger("-PT5M")})
+	if _, err := task.SnoozeAlert("a", time.Now().Add(time.Hour)); err != nil {
+		t.Errorf("Expected a task alert to snooze, got %v", err)
+	}
+	if _, ok := task.SnoozeUntil("a"); !ok {
+		t.Error("Expected the task alert to be snoozed")
+	}
+}
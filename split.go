@@ -0,0 +1,92 @@
+package jscal
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// SplitAt splits a recurring event at one of its instances, as calendars do
+// to edit "this and following events". The event keeps the instances before
+// at: its rules end with until at the last of them and overrides from at on
+// are removed. They move to the returned series, a copy with a new uid
+// starting at at, whose rules with a count are rewritten with until so that
+// it has the same instances as before. The event names the series as next,
+// the series names the event as prior.
+//
+// at must be an instance of the event other than the first.
+func (e *Event) SplitAt(at LocalDateTime) (*Event, error) {
+	if len(e.RecurrenceRules) == 0 {
+		return nil, fmt.Errorf("event %s is not recurring", e.UID)
+	}
+	if e.Start == nil {
+		return nil, fmt.Errorf("event %s has no start", e.UID)
+	}
+	first, split := wallClock(*e.Start), wallClock(at)
+	if !split.After(first) {
+		return nil, fmt.Errorf("event %s: split point %s must be after the first instance", e.UID, at)
+	}
+
+	ids, _, err := recurrenceSet(context.Background(), *e.Start, e.RecurrenceRules, e.ExcludedRecurrenceRules,
+		e.RecurrenceOverrides, LocalDateTime(split.Add(time.Nanosecond)))
+	if err != nil {
+		return nil, fmt.Errorf("event %s: %w", e.UID, err)
+	}
+	found := false
+	for _, id := range ids {
+		found = found || wallClock(id).Equal(split)
+	}
+	if !found {
+		return nil, fmt.Errorf("event %s: %s is not an instance", e.UID, at)
+	}
+
+	// The series carries the rules on from the split point
+	series := e.Clone()
+	series.UID = NewUID()
+	series.Start = NewLocalDateTime(split)
+	if err := countsToUntil(series.RecurrenceRules, *e.Start, true); err != nil {
+		return nil, fmt.Errorf("event %s: %w", e.UID, err)
+	}
+	if err := countsToUntil(series.ExcludedRecurrenceRules, *e.Start, false); err != nil {
+		return nil, fmt.Errorf("event %s: %w", e.UID, err)
+	}
+
+	// The event ends before it
+	for i := range e.RecurrenceRules {
+		rule := &e.RecurrenceRules[i]
+		times, err := expandRule(context.Background(), rule, first, split, true)
+		if err != nil {
+			return nil, fmt.Errorf("event %s: recurrenceRules[%d]: %w", e.UID, i, err)
+		}
+		rule.Until = NewLocalDateTime(times[len(times)-1])
+		rule.Count = nil
+	}
+
+	// Overrides go with the part their instance belongs to
+	for key := range e.RecurrenceOverrides {
+		id, err := ParseLocalDateTime(key)
+		if err != nil {
+			continue
+		}
+		if wallClock(*id).Before(split) {
+			delete(series.RecurrenceOverrides, key)
+		} else {
+			delete(e.RecurrenceOverrides, key)
+		}
+	}
+	if len(e.RecurrenceOverrides) == 0 {
+		e.RecurrenceOverrides = nil
+	}
+	if len(series.RecurrenceOverrides) == 0 {
+		series.RecurrenceOverrides = nil
+	}
+
+	now := Now().UTC()
+	series.Created = NewUTCDateTime(now)
+	series.Updated = NewUTCDateTime(now)
+	series.Sequence = nil
+	series.AddRelation(e.UID, RelationTypePrior)
+	e.AddRelation(series.UID, RelationTypeNext)
+	e.Touch()
+	return series, nil
+}
@@ -0,0 +1,97 @@
+package jscal
+
+import (
+	"testing"
+	"time"
+)
+
+func newDailyStandup() *Event {
+	event := NewEvent("standup", "Standup")
+	event.Start = NewLocalDateTime(time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC))
+	event.TimeZone = String("Europe/Berlin")
+	count := 10
+	rule := NewRecurrenceRule("daily")
+	rule.Count = &count
+	event.RecurrenceRules = []RecurrenceRule{*rule}
+	event.RecurrenceOverrides = map[string]map[string]interface{}{
+		"2025-03-04T09:00:00": {"title": "Standup (short)"},
+		"2025-03-08T09:00:00": {"excluded": true},
+		"2025-03-10T09:00:00": {"start": "2025-03-10T10:00:00"},
+	}
+	return event
+}
+
+func TestSplitAt(t *testing.T) {
+	event := newDailyStandup()
+	all, err := event.Clone().Occurrences(*NewLocalDateTime(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)),
+		*NewLocalDateTime(time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)))
+	if err != nil {
+		t.Fatalf("Occurrences failed: %v", err)
+	}
+
+	at, _ := ParseLocalDateTime("2025-03-07T09:00:00")
+	series, err := event.SplitAt(*at)
+	if err != nil {
+		t.Fatalf("SplitAt failed: %v", err)
+	}
+
+	if rule := event.RecurrenceRules[0]; rule.Count != nil || rule.Until == nil || rule.Until.String() != "2025-03-06T09:00:00" {
+		t.Errorf("Expected the original to end on 2025-03-06T09:00:00, got %+v", rule)
+	}
+	if _, ok := event.RecurrenceOverrides["2025-03-04T09:00:00"]; !ok || len(event.RecurrenceOverrides) != 1 {
+		t.Errorf("Expected the original to keep only earlier overrides, got %v", event.RecurrenceOverrides)
+	}
+
+	if series.UID == event.UID || series.Start.String() != "2025-03-07T09:00:00" {
+		t.Errorf("Expected a new series starting at the split point, got %s at %s", series.UID, series.Start)
+	}
+	if rule := series.RecurrenceRules[0]; rule.Count != nil || rule.Until == nil || rule.Until.String() != "2025-03-12T09:00:00" {
+		t.Errorf("Expected the series count rewritten as until 2025-03-12T09:00:00, got %+v", rule)
+	}
+	if len(series.RecurrenceOverrides) != 2 {
+		t.Errorf("Expected the series to carry the later overrides, got %v", series.RecurrenceOverrides)
+	}
+
+	if !event.RelatedTo[series.UID].Relation[RelationTypeNext] || !series.RelatedTo[event.UID].Relation[RelationTypePrior] {
+		t.Error("Expected the original and the series to be related as next and prior")
+	}
+
+	// Together the two parts have the same occurrences as before
+	from := *NewLocalDateTime(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+	to := *NewLocalDateTime(time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC))
+	before, _ := event.Occurrences(from, to)
+	after, _ := series.Occurrences(from, to)
+	if len(before) != 4 || len(before)+len(after) != len(all) {
+		t.Fatalf("Expected %d occurrences split 4 and %d, got %d and %d", len(all), len(all)-4, len(before), len(after))
+	}
+	for i, occurrence := range append(before, after...) {
+		if occurrence.Start().String() != all[i].Start().String() {
+			t.Errorf("Occurrence %d: expected start %s, got %s", i, all[i].Start(), occurrence.Start())
+		}
+	}
+}
+
+func TestSplitAtErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		at   string
+	}{
+		{"first instance", "2025-03-03T09:00:00"},
+		{"not an instance", "2025-03-05T10:00:00"},
+		{"excluded instance", "2025-03-08T09:00:00"},
+		{"after the last instance", "2025-03-20T09:00:00"},
+	}
+	for _, tt := range tests {
+		event := newDailyStandup()
+		at, _ := ParseLocalDateTime(tt.at)
+		if _, err := event.SplitAt(*at); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+
+	single := NewEvent("once", "Once")
+	single.Start = NewLocalDateTime(time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC))
+	if _, err := single.SplitAt(*single.Start); err == nil {
+		t.Error("Expected an error splitting a non-recurring event")
+	}
+}
@@ -0,0 +1,192 @@
+package jscal
+
+import (
+	"time"
+)
+
+// GroupStats summarizes the entries of a group, as returned by Group.Stats
+type GroupStats struct {
+	Total      int            `json:"total"`      // Events and tasks, including those of nested groups
+	ByType     map[string]int `json:"byType"`     // Count by @type
+	Recurring  int            `json:"recurring"`  // Events with recurrence rules
+	ByStatus   map[string]int `json:"byStatus"`   // Count by status, "confirmed" when not set
+	ByCategory map[string]int `json:"byCategory"` // Count by category; entries may have several
+	Overdue    int            `json:"overdue"`    // Tasks past their due time and not completed
+
+	// Instances is the number of event instances overlapping the window,
+	// with recurrences expanded and cancelled instances left out
+	Instances int `json:"instances"`
+
+	// ScheduledTime is the summed duration of the timed instances within
+	// the window. All-day events don't count, and overlapping instances
+	// count twice.
+	ScheduledTime time.Duration `json:"scheduledTime"`
+
+	// BusiestDay is the date ("2006-01-02") on which the most instances
+	// start in the window, the earliest on ties, "" if there are none
+	BusiestDay      string `json:"busiestDay,omitempty"`
+	BusiestDayCount int    `json:"busiestDayCount,omitempty"`
+
+	// FirstDay and LastDay are the dates ("2006-01-02") of the first and
+	// last instance starting in the window, "" if there are none
+	FirstDay string `json:"firstDay,omitempty"`
+	LastDay  string `json:"lastDay,omitempty"`
+
+	// ByWeekday counts the instances starting in the window by weekday
+	// ("Monday"), and ByHour the timed ones by hour of the day (0-23)
+	ByWeekday map[string]int `json:"byWeekday"`
+	ByHour    map[int]int    `json:"byHour"`
+
+	// AverageDuration is the mean duration of the timed instances
+	// overlapping the window, 0 if there are none
+	AverageDuration time.Duration `json:"averageDuration"`
+
+	// ByParticipant counts the entries each participant takes part in, by
+	// lowercased email address or, without one, by name
+	ByParticipant map[string]int `json:"byParticipant"`
+}
+
+// Stats summarizes the group's events and tasks and its nested groups'.
+// The instance counts cover the window [from, to), reading floating and
+// all-day times and counting days, weekdays and hours in the location of
+// from.
+func (g *Group) Stats(from, to time.Time) (*GroupStats, error) {
+	stats := &GroupStats{
+		ByType:        make(map[string]int),
+		ByStatus:      make(map[string]int),
+		ByCategory:    make(map[string]int),
+		ByWeekday:     make(map[string]int),
+		ByHour:        make(map[int]int),
+		ByParticipant: make(map[string]int),
+	}
+	days := make(map[string]int)
+	var timed instanceLengths
+
+	for _, entry := range g.Flatten() {
+		stats.Total++
+		stats.ByType[entry.GetType()]++
+
+		var status string
+		var categories map[string]bool
+		var participants map[string]*Participant
+		switch obj := entry.(type) {
+		case *Event:
+			status, categories, participants = obj.GetStatus(), obj.Categories, obj.Participants
+			if obj.IsRecurring() {
+				stats.Recurring++
+			}
+			if err := stats.addInstances(obj, from, to, days, &timed); err != nil {
+				return nil, err
+			}
+		case *Task:
+			status, categories, participants = obj.GetStatus(), obj.Categories, obj.Participants
+			if obj.IsOverdue() {
+				stats.Overdue++
+			}
+		}
+		if status == "" {
+			status = StatusConfirmed
+		}
+		stats.ByStatus[status]++
+		for category, ok := range categories {
+			if ok {
+				stats.ByCategory[category]++
+			}
+		}
+		stats.addParticipants(participants)
+	}
+
+	for i, day := range sortedKeys(days) {
+		if i == 0 {
+			stats.FirstDay = day
+		}
+		stats.LastDay = day
+		if days[day] > stats.BusiestDayCount {
+			stats.BusiestDay, stats.BusiestDayCount = day, days[day]
+		}
+	}
+	if timed.count > 0 {
+		stats.AverageDuration = timed.total / time.Duration(timed.count)
+	}
+	return stats, nil
+}
+
+// instanceLengths sums the durations of timed instances for the average
+type instanceLengths struct {
+	total time.Duration
+	count int
+}
+
+// addParticipants counts the participants of an entry, each once
+func (s *GroupStats) addParticipants(participants map[string]*Participant) {
+	seen := make(map[string]bool)
+	for _, p := range participants {
+		if p == nil {
+			continue
+		}
+		key := participantAddress(p)
+		if key == "" {
+			key = p.GetName()
+		}
+		if key != "" && !seen[key] {
+			seen[key] = true
+			s.ByParticipant[key]++
+		}
+	}
+}
+
+// addInstances counts the instances of e overlapping [from, to), adding
+// their start dates to days and the lengths of timed ones to timed
+func (s *GroupStats) addInstances(e *Event, from, to time.Time, days map[string]int, timed *instanceLengths) error {
+	if e.Start == nil {
+		return nil
+	}
+	loc := from.Location()
+	zone := timeZoneOf(e.TimeZone, e.IsAllDay(), loc)
+
+	// Instances starting up to a duration before from still overlap it;
+	// the extra day covers overrides with longer durations
+	duration, _ := e.GetDuration()
+	lower := NewLocalDateTime(from.In(zone).Add(-duration - 24*time.Hour))
+	upper := NewLocalDateTime(to.In(zone).Add(24 * time.Hour))
+	occurrences, err := e.Occurrences(*lower, *upper)
+	if err != nil {
+		return err
+	}
+
+	for _, occurrence := range occurrences {
+		if occurrence.Event.GetStatus() == StatusCancelled {
+			continue
+		}
+		instanceStart := occurrence.Start()
+		start := instanceStart.In(zone)
+		duration, _ := occurrence.Event.GetDuration()
+		end := start.Add(duration)
+		if !start.Before(to) || (duration > 0 && !end.After(from)) || (duration <= 0 && start.Before(from)) {
+			continue
+		}
+
+		s.Instances++
+		allDay := occurrence.Event.IsAllDay()
+		if !start.Before(from) {
+			local := start.In(loc)
+			days[local.Format("2006-01-02")]++
+			s.ByWeekday[local.Weekday().String()]++
+			if !allDay {
+				s.ByHour[local.Hour()]++
+			}
+		}
+		if !allDay && duration > 0 {
+			timed.total += duration
+			timed.count++
+			if start.Before(from) {
+				start = from
+			}
+			if end.After(to) {
+				end = to
+			}
+			s.ScheduledTime += end.Sub(start)
+		}
+	}
+	return nil
+}
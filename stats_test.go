@@ -0,0 +1,113 @@
+package jscal
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGroupStats(t *testing.T) {
+	standup := NewEvent("standup", "Standup")
+	standup.Start = NewLocalDateTime(time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC))
+	standup.Duration = String("PT30M")
+	standup.RecurrenceRules = []RecurrenceRule{*NewRecurrenceRule("daily")}
+	standup.RecurrenceOverrides = map[string]map[string]interface{}{
+		"2025-03-11T09:00:00": {"status": StatusCancelled},
+	}
+	standup.AddCategory("work")
+	standup.AddParticipant("lead", NewParticipant("Lead", "Lead@example.com"))
+	standup.AddParticipant("guest", &Participant{Name: String("Guest")})
+
+	review := NewEvent("review", "Review")
+	review.Start = NewLocalDateTime(time.Date(2025, 3, 12, 14, 0, 0, 0, time.UTC))
+	review.Duration = String("PT2H")
+	review.Status = String(StatusTentative)
+	review.AddCategory("work")
+	review.AddParticipant("lead", NewParticipant("Team lead", "lead@example.com"))
+
+	// Ends an hour into the window
+	launch := NewEvent("launch", "Launch")
+	launch.Start = NewLocalDateTime(time.Date(2025, 3, 9, 23, 0, 0, 0, time.UTC))
+	launch.Duration = String("PT2H")
+
+	holiday := NewEvent("holiday", "Holiday")
+	holiday.Start = NewLocalDateTime(time.Date(2025, 3, 13, 0, 0, 0, 0, time.UTC))
+	holiday.ShowWithoutTime = Bool(true)
+	holiday.Duration = String("P1D")
+
+	overdue := NewTask("overdue", "File taxes")
+	overdue.Due = NewLocalDateTime(time.Date(2020, 4, 15, 0, 0, 0, 0, time.UTC))
+	done := NewTask("done", "Renew passport")
+	done.Due = NewLocalDateTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	done.Progress = String(ProgressCompleted)
+
+	nested := NewGroup("nested", "Personal")
+	nested.Entries = []CalendarObject{holiday, overdue, done}
+	group := NewGroup("group", "Team")
+	group.Entries = []CalendarObject{standup, review, launch, nested}
+
+	from := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	stats, err := group.Stats(from, from.AddDate(0, 0, 5))
+	if err != nil {
+		t.Fatalf("Stats failed: %v", err)
+	}
+
+	if stats.Total != 6 {
+		t.Errorf("Expected 6 entries, got %d", stats.Total)
+	}
+	if stats.ByType["Event"] != 4 || stats.ByType["Task"] != 2 {
+		t.Errorf("Expected 4 events and 2 tasks, got %v", stats.ByType)
+	}
+	if stats.ByStatus[StatusConfirmed] != 5 || stats.ByStatus[StatusTentative] != 1 {
+		t.Errorf("Expected 5 confirmed and 1 tentative, got %v", stats.ByStatus)
+	}
+	if stats.ByCategory["work"] != 2 {
+		t.Errorf("Expected 2 work entries, got %v", stats.ByCategory)
+	}
+	if stats.Overdue != 1 {
+		t.Errorf("Expected 1 overdue task, got %d", stats.Overdue)
+	}
+
+	// 4 standups (one cancelled), the review, the launch and the holiday
+	if stats.Instances != 7 {
+		t.Errorf("Expected 7 instances, got %d", stats.Instances)
+	}
+	want := 4*30*time.Minute + 2*time.Hour + time.Hour
+	if stats.ScheduledTime != want {
+		t.Errorf("Expected scheduled time %s, got %s", want, stats.ScheduledTime)
+	}
+	if stats.BusiestDay != "2025-03-12" || stats.BusiestDayCount != 2 {
+		t.Errorf("Expected busiest day 2025-03-12 with 2, got %s with %d", stats.BusiestDay, stats.BusiestDayCount)
+	}
+	if stats.FirstDay != "2025-03-10" || stats.LastDay != "2025-03-14" {
+		t.Errorf("Expected days 2025-03-10 to 2025-03-14, got %s to %s", stats.FirstDay, stats.LastDay)
+	}
+	if stats.Recurring != 1 {
+		t.Errorf("Expected 1 recurring event, got %d", stats.Recurring)
+	}
+
+	// The launch starts before the window and the holiday has no time
+	weekdays := map[string]int{"Monday": 1, "Wednesday": 2, "Thursday": 2, "Friday": 1}
+	if !reflect.DeepEqual(stats.ByWeekday, weekdays) {
+		t.Errorf("Expected weekdays %v, got %v", weekdays, stats.ByWeekday)
+	}
+	if hours := map[int]int{9: 4, 14: 1}; !reflect.DeepEqual(stats.ByHour, hours) {
+		t.Errorf("Expected hours %v, got %v", hours, stats.ByHour)
+	}
+	if stats.AverageDuration != time.Hour {
+		t.Errorf("Expected an average duration of 1h, got %s", stats.AverageDuration)
+	}
+	if participants := map[string]int{"lead@example.com": 2, "Guest": 1}; !reflect.DeepEqual(stats.ByParticipant, participants) {
+		t.Errorf("Expected participants %v, got %v", participants, stats.ByParticipant)
+	}
+}
+
+func TestGroupStatsEmpty(t *testing.T) {
+	stats, err := NewGroup("empty", "Empty").Stats(time.Now(), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Stats failed: %v", err)
+	}
+	if stats.Total != 0 || stats.BusiestDay != "" || stats.ScheduledTime != 0 {
+		t.Errorf("Expected empty stats, got %+v", stats)
+	}
+}
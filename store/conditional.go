@@ -0,0 +1,244 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/airtrafik/jscal"
+)
+
+// ErrConflict is returned, as a *ConflictError, by conditional writes whose
+// precondition doesn't hold
+var ErrConflict = errors.New("precondition failed")
+
+// Precondition is the version of an object, or of the whole store, a
+// conditional write expects to replace. Empty fields aren't checked.
+//
+// An ETag changes with any change to the object and maps onto the
+// If-Match header of CalDAV; State maps onto ifInState in JMAP. Sequence
+// only protects against lost updates if every update increments it, as
+// RFC 8984 only requires that for significant changes.
+type Precondition struct {
+	// ETag must match the ETag of the stored object; "*" matches any
+	// stored object
+	ETag string
+
+	// Sequence must equal the sequence of the stored object
+	Sequence *int
+
+	// State must equal the store's State
+	State string
+
+	// Absent requires that no object with the UID is stored, as the
+	// If-None-Match: * header does, to create objects without replacing
+	Absent bool
+}
+
+// ConflictError is returned when a precondition doesn't hold. It holds the
+// current version, so that the caller can merge and retry.
+type ConflictError struct {
+	UID string
+
+	// Current is a copy of the stored object, nil if there is none
+	Current jscal.CalendarObject
+
+	// ETag is the ETag of Current, "" if there is none
+	ETag string
+
+	// State is the store's State when the write was refused
+	State string
+}
+
+func (e *ConflictError) Error() string {
+	if e.Current == nil {
+		return fmt.Sprintf("%s: %s is not stored", ErrConflict, e.UID)
+	}
+	return fmt.Sprintf("%s: %s has changed, current etag %s", ErrConflict, e.UID, e.ETag)
+}
+
+// Unwrap returns ErrConflict
+func (e *ConflictError) Unwrap() error {
+	return ErrConflict
+}
+
+// ETag returns a strong HTTP entity tag for the content of an object, the
+// quoted hash of its canonical JSON. Objects that are equivalent, as
+// Event.EquivalentTo reports, have the same ETag.
+func ETag(obj jscal.CalendarObject) (string, error) {
+	hasher, ok := obj.(interface{ Hash() (string, error) })
+	if !ok {
+		return "", fmt.Errorf("cannot compute etag of %T", obj)
+	}
+	hash, err := hasher.Hash()
+	if err != nil {
+		return "", err
+	}
+	return `"` + hash + `"`, nil
+}
+
+// ETag returns the ETag of the object with the given UID
+func (s *Store) ETag(uid string) (string, bool) {
+	s.mu.RLock()
+	obj, ok := s.objects[uid]
+	s.mu.RUnlock()
+	if !ok {
+		return "", false
+	}
+	etag, err := ETag(obj)
+	return etag, err == nil
+}
+
+// UpdateIf is Update for an object matching the precondition; otherwise
+// it returns a *ConflictError with the stored version and leaves the
+// store unchanged. Like Update it returns ErrNotFound for UIDs the store
+// doesn't hold.
+func (s *Store) UpdateIf(uid string, cond Precondition, fn func(obj jscal.CalendarObject) error) (err error) {
+	done := s.trace("update_if", slog.String("uid", uid))
+	defer func() { done(err) }()
+	unlock := s.locks.lock(uid)
+	defer unlock()
+
+	s.mu.RLock()
+	current, ok := s.objects[uid]
+	s.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, uid)
+	}
+	if err := s.check(uid, current, cond); err != nil {
+		return err
+	}
+
+	updated := clone(current)
+	if err := fn(updated); err != nil {
+		return err
+	}
+	if updated.GetUID() != uid {
+		return fmt.Errorf("%w: update changed uid from '%s' to '%s'", ErrInvalidObject, uid, updated.GetUID())
+	}
+	return s.commit(uid, updated, cond.State)
+}
+
+// PutIf is Put if the precondition holds for the stored object with the
+// same UID; otherwise it returns a *ConflictError. A precondition on the
+// ETag or Sequence fails if no object is stored.
+func (s *Store) PutIf(obj jscal.CalendarObject, cond Precondition) (err error) {
+	done := s.trace("put_if", slog.String("uid", uidOf(obj)))
+	defer func() { done(err) }()
+	if obj == nil {
+		return fmt.Errorf("%w: cannot store nil object", ErrInvalidObject)
+	}
+	uid := obj.GetUID()
+	if uid == "" {
+		return fmt.Errorf("%w: cannot store %s without uid", ErrInvalidObject, obj.GetType())
+	}
+
+	stored := clone(obj)
+	unlock := s.locks.lock(uid)
+	defer unlock()
+
+	s.mu.RLock()
+	current := s.objects[uid]
+	s.mu.RUnlock()
+	if err := s.check(uid, current, cond); err != nil {
+		return err
+	}
+	return s.commit(uid, stored, cond.State)
+}
+
+// DeleteIf is Delete if the precondition holds; otherwise it returns a
+// *ConflictError. It returns ErrNotFound for UIDs the store doesn't hold.
+func (s *Store) DeleteIf(uid string, cond Precondition) (err error) {
+	done := s.trace("delete_if", slog.String("uid", uid))
+	defer func() { done(err) }()
+	unlock := s.locks.lock(uid)
+	defer unlock()
+
+	s.mu.RLock()
+	current, ok := s.objects[uid]
+	s.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, uid)
+	}
+	if err := s.check(uid, current, cond); err != nil {
+		return err
+	}
+	return s.commit(uid, nil, cond.State)
+}
+
+// check tests the object conditions of a precondition against the stored
+// object, nil if there is none. The caller holds the lock of uid; State is
+// checked by commit.
+func (s *Store) check(uid string, current jscal.CalendarObject, cond Precondition) error {
+	if current == nil {
+		if cond.ETag != "" || cond.Sequence != nil {
+			return s.conflict(uid, nil)
+		}
+		return nil
+	}
+	if cond.Absent {
+		return s.conflict(uid, current)
+	}
+	if cond.Sequence != nil {
+		sequencer, ok := current.(interface{ GetSequence() int })
+		if !ok || sequencer.GetSequence() != *cond.Sequence {
+			return s.conflict(uid, current)
+		}
+	}
+	if cond.ETag != "" && cond.ETag != "*" {
+		etag, err := ETag(current)
+		if err != nil {
+			return err
+		}
+		if !etagMatches(cond.ETag, etag) {
+			return s.conflict(uid, current)
+		}
+	}
+	return nil
+}
+
+// commit stores an object, or deletes it if obj is nil, if the store's
+// state is the expected one or no state is expected
+func (s *Store) commit(uid string, obj jscal.CalendarObject, state string) error {
+	s.mu.Lock()
+	if state != "" && state != strconv.FormatUint(s.state, 10) {
+		current := s.objects[uid]
+		s.mu.Unlock()
+		return s.conflict(uid, current)
+	}
+	before := s.objects[uid]
+	s.detach()
+	if obj == nil {
+		delete(s.objects, uid)
+	} else {
+		s.objects[uid] = obj
+	}
+	s.state++
+	s.notify(uid, before, obj)
+	s.mu.Unlock()
+	return nil
+}
+
+// conflict returns the *ConflictError for uid, given the stored object or
+// nil if there is none
+func (s *Store) conflict(uid string, current jscal.CalendarObject) *ConflictError {
+	err := &ConflictError{UID: uid, State: s.State()}
+	if current != nil {
+		err.Current = clone(current)
+		err.ETag, _ = ETag(current)
+	}
+	return err
+}
+
+// etagMatches reports whether an If-Match value, a list of entity tags,
+// names etag. If-Match compares strongly, so weak tags never match.
+func etagMatches(ifMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifMatch, ",") {
+		if strings.TrimSpace(candidate) == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,188 @@
+package store
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/airtrafik/jscal"
+)
+
+func retitle(title string) func(obj jscal.CalendarObject) error {
+	return func(obj jscal.CalendarObject) error {
+		obj.(*jscal.Event).Title = jscal.String(title)
+		return nil
+	}
+}
+
+func TestUpdateIfETag(t *testing.T) {
+	s := New()
+	if err := s.Put(newEvent("e1", "Review")); err != nil {
+		t.Fatal(err)
+	}
+	etag, ok := s.ETag("e1")
+	if !ok || len(etag) != 66 || etag[0] != '"' {
+		t.Fatalf("Expected a quoted etag, got %q", etag)
+	}
+	if _, ok := s.ETag("missing"); ok {
+		t.Error("Expected no etag for a missing object")
+	}
+
+	if err := s.UpdateIf("e1", Precondition{ETag: etag}, retitle("First")); err != nil {
+		t.Fatalf("Expected the update with the current etag to succeed, got %v", err)
+	}
+
+	// A second client still holding the old etag
+	err := s.UpdateIf("e1", Precondition{ETag: etag}, retitle("Second"))
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || !errors.Is(err, ErrConflict) {
+		t.Fatalf("Expected a *ConflictError, got %v", err)
+	}
+	if conflict.UID != "e1" || conflict.Current.(*jscal.Event).GetTitle() != "First" {
+		t.Errorf("Expected the current copy in the conflict, got %+v", conflict)
+	}
+	if current, _ := s.ETag("e1"); conflict.ETag != current || current == etag {
+		t.Errorf("Expected the new etag %s in the conflict, got %s", current, conflict.ETag)
+	}
+	if obj, _ := s.Get("e1"); obj.(*jscal.Event).GetTitle() != "First" {
+		t.Error("Expected a conflict to leave the store unchanged")
+	}
+
+	// Retrying with the etag from the conflict succeeds, as does "*" and a list
+	if err := s.UpdateIf("e1", Precondition{ETag: conflict.ETag}, retitle("Second")); err != nil {
+		t.Errorf("Expected the retry to succeed, got %v", err)
+	}
+	if err := s.UpdateIf("e1", Precondition{ETag: "*"}, retitle("Third")); err != nil {
+		t.Errorf("Expected * to match, got %v", err)
+	}
+	current, _ := s.ETag("e1")
+	if err := s.UpdateIf("e1", Precondition{ETag: `"other", ` + current}, retitle("Fourth")); err != nil {
+		t.Errorf("Expected a list of etags to match, got %v", err)
+	}
+	current, _ = s.ETag("e1")
+	if err := s.UpdateIf("e1", Precondition{ETag: "W/" + current}, retitle("Fifth")); !errors.Is(err, ErrConflict) {
+		t.Errorf("Expected a weak etag not to match, got %v", err)
+	}
+
+	if err := s.UpdateIf("missing", Precondition{ETag: "*"}, retitle("x")); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestUpdateIfSequence(t *testing.T) {
+	s := New()
+	event := newEvent("e1", "Review")
+	event.Sequence = jscal.Int(2)
+	if err := s.Put(event); err != nil {
+		t.Fatal(err)
+	}
+
+	bump := func(obj jscal.CalendarObject) error {
+		event := obj.(*jscal.Event)
+		event.SetSequence(event.GetSequence() + 1)
+		return nil
+	}
+	if err := s.UpdateIf("e1", Precondition{Sequence: jscal.Int(2)}, bump); err != nil {
+		t.Fatalf("Expected the update at sequence 2 to succeed, got %v", err)
+	}
+	err := s.UpdateIf("e1", Precondition{Sequence: jscal.Int(2)}, bump)
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || conflict.Current.(*jscal.Event).GetSequence() != 3 {
+		t.Errorf("Expected a conflict at sequence 3, got %v", err)
+	}
+}
+
+func TestPutIfAndDeleteIf(t *testing.T) {
+	s := New()
+	create := Precondition{Absent: true}
+	if err := s.PutIf(newEvent("e1", "Review"), create); err != nil {
+		t.Fatalf("Expected the create to succeed, got %v", err)
+	}
+	if err := s.PutIf(newEvent("e1", "Other"), create); !errors.Is(err, ErrConflict) {
+		t.Errorf("Expected a conflict creating an existing object, got %v", err)
+	}
+	if err := s.PutIf(newEvent("e2", "New"), Precondition{ETag: "*"}); !errors.Is(err, ErrConflict) {
+		t.Errorf("Expected a conflict replacing a missing object, got %v", err)
+	}
+	if err := s.PutIf(nil, Precondition{}); err == nil {
+		t.Error("Expected error for nil object")
+	}
+
+	etag, _ := s.ETag("e1")
+	if err := s.PutIf(newEvent("e1", "Replaced"), Precondition{ETag: etag}); err != nil {
+		t.Errorf("Expected the replace to succeed, got %v", err)
+	}
+	if err := s.DeleteIf("e1", Precondition{ETag: etag}); !errors.Is(err, ErrConflict) {
+		t.Errorf("Expected a conflict deleting a changed object, got %v", err)
+	}
+	etag, _ = s.ETag("e1")
+	if err := s.DeleteIf("e1", Precondition{ETag: etag}); err != nil || s.Len() != 0 {
+		t.Errorf("Expected the delete to succeed, got %v", err)
+	}
+	if err := s.DeleteIf("e1", Precondition{}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestState(t *testing.T) {
+	s := New()
+	if err := s.Put(newEvent("e1", "Review")); err != nil {
+		t.Fatal(err)
+	}
+	state := s.State()
+	snapshot := s.Snapshot()
+	if snapshot.State() != state {
+		t.Errorf("Expected the snapshot state %s, got %s", state, snapshot.State())
+	}
+
+	if err := s.UpdateIf("e1", Precondition{State: state}, retitle("First")); err != nil {
+		t.Fatalf("Expected the update in the current state to succeed, got %v", err)
+	}
+	if s.State() == state {
+		t.Error("Expected the state to change with a write")
+	}
+
+	// Any write, to any object, changes the state
+	if err := s.Put(newEvent("e2", "Other")); err != nil {
+		t.Fatal(err)
+	}
+	newState := s.State()
+	s.Delete("e2")
+	if err := s.UpdateIf("e1", Precondition{State: newState}, retitle("Second")); !errors.Is(err, ErrConflict) {
+		t.Errorf("Expected a conflict after a delete, got %v", err)
+	}
+	if snapshot.State() != state {
+		t.Error("Expected the snapshot state to stay the same")
+	}
+}
+
+func TestUpdateIfConcurrent(t *testing.T) {
+	s := New()
+	if err := s.Put(newEvent("e1", "Review")); err != nil {
+		t.Fatal(err)
+	}
+	etag, _ := s.ETag("e1")
+
+	// Of many clients updating from the same version, exactly one wins
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	wins, conflicts := 0, 0
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := s.UpdateIf("e1", Precondition{ETag: etag}, retitle(string(rune('a'+i))))
+			mu.Lock()
+			defer mu.Unlock()
+			if err == nil {
+				wins++
+			} else if errors.Is(err, ErrConflict) {
+				conflicts++
+			}
+		}(i)
+	}
+	wg.Wait()
+	if wins != 1 || conflicts != 7 {
+		t.Errorf("Expected 1 win and 7 conflicts, got %d and %d", wins, conflicts)
+	}
+}
@@ -0,0 +1,35 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/airtrafik/jscal"
+	"github.com/airtrafik/jscal/observe"
+)
+
+// trace starts the span of a store operation and returns the function
+// ending it with the operation's error. Store methods take no context, so
+// the spans have no parent.
+func (s *Store) trace(op string, attrs ...slog.Attr) func(err error) {
+	if s.Hooks == nil {
+		return func(error) {}
+	}
+	ctx, span := s.Hooks.Start(context.Background(), "jscal.store."+op, attrs...)
+	return func(err error) {
+		var conflict *ConflictError
+		if errors.As(err, &conflict) {
+			s.Hooks.Add(ctx, observe.StoreConflicts, 1, slog.String("operation", op))
+		}
+		span.End(err)
+	}
+}
+
+// uidOf returns the UID of obj, or "" for nil
+func uidOf(obj jscal.CalendarObject) string {
+	if obj == nil {
+		return ""
+	}
+	return obj.GetUID()
+}
@@ -0,0 +1,42 @@
+package store
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/airtrafik/jscal/observe"
+)
+
+func TestStoreHooks(t *testing.T) {
+	recorder := &observe.Recorder{}
+	s := New()
+	s.Hooks = &observe.Hooks{Tracer: recorder, Counter: recorder}
+
+	if err := s.Put(newEvent("e1", "Review")); err != nil {
+		t.Fatal(err)
+	}
+	s.Get("e1")
+	if err := s.PutIf(newEvent("e1", "Again"), Precondition{Absent: true}); !errors.Is(err, ErrConflict) {
+		t.Fatalf("Expected a conflict, got %v", err)
+	}
+	if err := s.Update("missing", retitle("Missing")); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound, got %v", err)
+	}
+
+	expected := []string{"jscal.store.put", "jscal.store.get", "jscal.store.put_if", "jscal.store.update"}
+	if len(recorder.Spans) != len(expected) {
+		t.Fatalf("Expected %d spans, got %d", len(expected), len(recorder.Spans))
+	}
+	for i, name := range expected {
+		span := recorder.Spans[i]
+		if span.Name != name || !span.Ended || span.Attr("uid").String() == "" {
+			t.Errorf("Expected span %s, got %+v", name, span)
+		}
+	}
+	if recorder.Spans[0].Err != nil || recorder.Spans[3].Err == nil {
+		t.Errorf("Expected only the failed operations to record errors")
+	}
+	if n := recorder.Count(observe.StoreConflicts); n != 1 {
+		t.Errorf("Expected 1 conflict, got %d", n)
+	}
+}
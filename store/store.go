@@ -0,0 +1,301 @@
+// Package store keeps JSCalendar objects in memory, keyed by UID, for
+// servers embedding jscal. A Store is safe for concurrent use:
+//
+//	s := store.New()
+//	if err := s.Put(event); err != nil {
+//		return err
+//	}
+//	err := s.Update(event.UID, func(obj jscal.CalendarObject) error {
+//		obj.(*jscal.Event).Title = jscal.String("Moved")
+//		return nil
+//	})
+//
+// The store holds its own copies: Put stores a clone, Get returns one, and
+// Update passes one to the update function, so callers never share an
+// object with other goroutines. Writes to the same UID are serialized;
+// writes to different UIDs only contend for the short time it takes to
+// swap the stored pointer.
+//
+// Snapshot returns a consistent, immutable view for iteration. Taking one
+// is cheap: the store copies its index on the next write instead.
+//
+// UpdateIf, PutIf and DeleteIf write only if the stored object still has
+// the ETag or sequence the caller read, or the store the same State, and
+// return a *ConflictError with the current version otherwise. They map onto
+// CalDAV If-Match and JMAP ifInState.
+//
+// Watch streams the changes made to the store, with the object before and
+// after each change, and a Webhook posts them to a URL.
+package store
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/airtrafik/jscal"
+	"github.com/airtrafik/jscal/observe"
+)
+
+// ErrNotFound is returned for UIDs the store doesn't hold
+var ErrNotFound = errors.New("object not found")
+
+// ErrInvalidObject is returned, wrapped, for nil objects, objects without
+// a UID and updates that change the UID
+var ErrInvalidObject = errors.New("invalid object")
+
+// Store is an in-memory, concurrency-safe collection of calendar objects
+type Store struct {
+	mu      sync.RWMutex
+	objects map[string]jscal.CalendarObject
+	shared  bool   // objects is referenced by a Snapshot and must be copied before writing
+	state   uint64 // incremented by every write, see State
+
+	locks    uidLocks
+	watchers map[*watcher]struct{}
+
+	// Hooks receives a span for each operation and counts conflicts; set
+	// it before the store is used
+	Hooks *observe.Hooks
+}
+
+// New creates an empty Store
+func New() *Store {
+	return &Store{objects: make(map[string]jscal.CalendarObject)}
+}
+
+// Get returns a copy of the object with the given UID
+func (s *Store) Get(uid string) (jscal.CalendarObject, bool) {
+	defer s.trace("get", slog.String("uid", uid))(nil)
+	s.mu.RLock()
+	obj, ok := s.objects[uid]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	return clone(obj), true
+}
+
+// Put stores a copy of obj, replacing any object with the same UID
+func (s *Store) Put(obj jscal.CalendarObject) (err error) {
+	done := s.trace("put", slog.String("uid", uidOf(obj)))
+	defer func() { done(err) }()
+	if obj == nil {
+		return fmt.Errorf("%w: cannot store nil object", ErrInvalidObject)
+	}
+	uid := obj.GetUID()
+	if uid == "" {
+		return fmt.Errorf("%w: cannot store %s without uid", ErrInvalidObject, obj.GetType())
+	}
+
+	stored := clone(obj)
+	unlock := s.locks.lock(uid)
+	defer unlock()
+	s.set(uid, stored)
+	return nil
+}
+
+// Update applies fn to a copy of the object with the given UID and stores
+// the result. Updates of the same UID run one at a time, so read-modify-write
+// cycles don't lose changes. If fn returns an error the store is unchanged.
+func (s *Store) Update(uid string, fn func(obj jscal.CalendarObject) error) (err error) {
+	done := s.trace("update", slog.String("uid", uid))
+	defer func() { done(err) }()
+	unlock := s.locks.lock(uid)
+	defer unlock()
+
+	s.mu.RLock()
+	current, ok := s.objects[uid]
+	s.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, uid)
+	}
+
+	updated := clone(current)
+	if err := fn(updated); err != nil {
+		return err
+	}
+	if updated.GetUID() != uid {
+		return fmt.Errorf("%w: update changed uid from '%s' to '%s'", ErrInvalidObject, uid, updated.GetUID())
+	}
+	s.set(uid, updated)
+	return nil
+}
+
+// Delete removes the object with the given UID and reports whether it was
+// there
+func (s *Store) Delete(uid string) bool {
+	defer s.trace("delete", slog.String("uid", uid))(nil)
+	unlock := s.locks.lock(uid)
+	defer unlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	before, ok := s.objects[uid]
+	if !ok {
+		return false
+	}
+	s.detach()
+	delete(s.objects, uid)
+	s.state++
+	s.notify(uid, before, nil)
+	return true
+}
+
+// Len returns the number of objects in the store
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.objects)
+}
+
+// State returns an opaque string that changes with every write to the
+// store, like a JMAP state: a client holding the state of its last read
+// knows nothing changed since while the state is the same
+func (s *Store) State() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return strconv.FormatUint(s.state, 10)
+}
+
+// Snapshot returns an immutable view of the store as it is now. Later writes
+// to the store don't affect it.
+func (s *Store) Snapshot() *Snapshot {
+	defer s.trace("snapshot")(nil)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.shared = true
+	return &Snapshot{objects: s.objects, state: strconv.FormatUint(s.state, 10)}
+}
+
+// set stores an object the store owns
+func (s *Store) set(uid string, obj jscal.CalendarObject) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	before := s.objects[uid]
+	s.detach()
+	s.objects[uid] = obj
+	s.state++
+	s.notify(uid, before, obj)
+}
+
+// detach copies the index if a Snapshot shares it. The caller holds s.mu.
+func (s *Store) detach() {
+	if !s.shared {
+		return
+	}
+	objects := make(map[string]jscal.CalendarObject, len(s.objects))
+	for uid, obj := range s.objects {
+		objects[uid] = obj
+	}
+	s.objects = objects
+	s.shared = false
+}
+
+// Snapshot is an immutable view of a Store, safe for concurrent use
+type Snapshot struct {
+	objects map[string]jscal.CalendarObject
+	state   string
+
+	once sync.Once
+	uids []string
+}
+
+// State returns the store's State when the snapshot was taken
+func (sn *Snapshot) State() string {
+	return sn.state
+}
+
+// Len returns the number of objects in the snapshot
+func (sn *Snapshot) Len() int {
+	return len(sn.objects)
+}
+
+// Get returns a copy of the object with the given UID
+func (sn *Snapshot) Get(uid string) (jscal.CalendarObject, bool) {
+	obj, ok := sn.objects[uid]
+	if !ok {
+		return nil, false
+	}
+	return clone(obj), true
+}
+
+// UIDs returns the UIDs in the snapshot, sorted
+func (sn *Snapshot) UIDs() []string {
+	return append([]string(nil), sn.sortedUIDs()...)
+}
+
+// Range calls fn for each object in UID order until fn returns false. The
+// objects are shared with the store and other snapshots and must not be
+// modified; Clone them to make changes.
+func (sn *Snapshot) Range(fn func(obj jscal.CalendarObject) bool) {
+	for _, uid := range sn.sortedUIDs() {
+		if !fn(sn.objects[uid]) {
+			return
+		}
+	}
+}
+
+func (sn *Snapshot) sortedUIDs() []string {
+	sn.once.Do(func() {
+		sn.uids = make([]string, 0, len(sn.objects))
+		for uid := range sn.objects {
+			sn.uids = append(sn.uids, uid)
+		}
+		sort.Strings(sn.uids)
+	})
+	return sn.uids
+}
+
+// clone returns a deep copy of an event, task or group
+func clone(obj jscal.CalendarObject) jscal.CalendarObject {
+	switch o := obj.(type) {
+	case *jscal.Event:
+		return o.Clone()
+	case *jscal.Task:
+		return o.Clone()
+	case *jscal.Group:
+		return o.Clone()
+	default:
+		return obj
+	}
+}
+
+// uidLocks hands out one mutex per UID, kept only while in use
+type uidLocks struct {
+	mu    sync.Mutex
+	locks map[string]*uidLock
+}
+
+type uidLock struct {
+	sync.Mutex
+	refs int
+}
+
+// lock locks the mutex for uid and returns the function unlocking it
+func (l *uidLocks) lock(uid string) func() {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = make(map[string]*uidLock)
+	}
+	lock, ok := l.locks[uid]
+	if !ok {
+		lock = &uidLock{}
+		l.locks[uid] = lock
+	}
+	lock.refs++
+	l.mu.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+		l.mu.Lock()
+		if lock.refs--; lock.refs == 0 {
+			delete(l.locks, uid)
+		}
+		l.mu.Unlock()
+	}
+}
@@ -0,0 +1,159 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/airtrafik/jscal"
+)
+
+func newEvent(uid, title string) *jscal.Event {
+	return &jscal.Event{Type: "Event", UID: uid, Title: jscal.String(title)}
+}
+
+func TestStore(t *testing.T) {
+	s := New()
+	event := newEvent("e1", "Review")
+	if err := s.Put(event); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put(&jscal.Event{Type: "Event"}); !errors.Is(err, ErrInvalidObject) {
+		t.Errorf("Expected ErrInvalidObject for object without uid, got %v", err)
+	}
+	if err := s.Put(nil); !errors.Is(err, ErrInvalidObject) {
+		t.Errorf("Expected ErrInvalidObject for nil object, got %v", err)
+	}
+
+	// The store keeps its own copy
+	event.Title = jscal.String("Changed")
+	obj, ok := s.Get("e1")
+	if !ok || *obj.(*jscal.Event).Title != "Review" {
+		t.Fatalf("Expected the stored title Review, got %v", obj)
+	}
+	obj.(*jscal.Event).Title = jscal.String("Changed")
+	if obj, _ := s.Get("e1"); *obj.(*jscal.Event).Title != "Review" {
+		t.Error("Expected Get to return a copy")
+	}
+
+	err := s.Update("e1", func(obj jscal.CalendarObject) error {
+		obj.(*jscal.Event).Title = jscal.String("Updated")
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj, _ := s.Get("e1"); *obj.(*jscal.Event).Title != "Updated" {
+		t.Errorf("Expected the updated title, got %s", *obj.(*jscal.Event).Title)
+	}
+
+	abort := errors.New("abort")
+	err = s.Update("e1", func(obj jscal.CalendarObject) error {
+		obj.(*jscal.Event).Title = jscal.String("Aborted")
+		return abort
+	})
+	if !errors.Is(err, abort) {
+		t.Errorf("Expected the update function's error, got %v", err)
+	}
+	if obj, _ := s.Get("e1"); *obj.(*jscal.Event).Title != "Updated" {
+		t.Error("Expected a failed update to leave the store unchanged")
+	}
+	err = s.Update("e1", func(obj jscal.CalendarObject) error {
+		obj.(*jscal.Event).UID = "e2"
+		return nil
+	})
+	if err == nil {
+		t.Error("Expected error for an update changing the uid")
+	}
+	if err := s.Update("missing", func(jscal.CalendarObject) error { return nil }); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	if !s.Delete("e1") || s.Delete("e1") || s.Len() != 0 {
+		t.Error("Expected Delete to remove the object once")
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	s := New()
+	for _, uid := range []string{"b", "a", "c"} {
+		if err := s.Put(newEvent(uid, uid)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	snapshot := s.Snapshot()
+	s.Delete("a")
+	if err := s.Put(newEvent("d", "d")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Update("b", func(obj jscal.CalendarObject) error {
+		obj.(*jscal.Event).Title = jscal.String("changed")
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if snapshot.Len() != 3 || s.Len() != 3 {
+		t.Errorf("Expected 3 objects in the snapshot and the store, got %d and %d", snapshot.Len(), s.Len())
+	}
+	var uids []string
+	snapshot.Range(func(obj jscal.CalendarObject) bool {
+		uids = append(uids, obj.GetUID())
+		return true
+	})
+	if fmt.Sprint(uids) != "[a b c]" || fmt.Sprint(snapshot.UIDs()) != "[a b c]" {
+		t.Errorf("Expected [a b c], got %v", uids)
+	}
+	if obj, ok := snapshot.Get("b"); !ok || *obj.(*jscal.Event).Title != "b" {
+		t.Error("Expected the snapshot to keep the old version of b")
+	}
+	if _, ok := snapshot.Get("d"); ok {
+		t.Error("Expected the snapshot not to see later additions")
+	}
+}
+
+func TestStoreConcurrentUpdates(t *testing.T) {
+	s := New()
+	for i := 0; i < 4; i++ {
+		event := newEvent(fmt.Sprintf("e%d", i), "Counter")
+		event.Sequence = jscal.Int(0)
+		if err := s.Put(event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	const updates = 50
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			uid := fmt.Sprintf("e%d", g%4)
+			for i := 0; i < updates; i++ {
+				err := s.Update(uid, func(obj jscal.CalendarObject) error {
+					event := obj.(*jscal.Event)
+					event.Sequence = jscal.Int(*event.Sequence + 1)
+					return nil
+				})
+				if err != nil {
+					t.Error(err)
+				}
+				if i%10 == 0 {
+					s.Snapshot().Range(func(obj jscal.CalendarObject) bool {
+						return obj.GetUID() != ""
+					})
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	for i := 0; i < 4; i++ {
+		obj, _ := s.Get(fmt.Sprintf("e%d", i))
+		if seq := *obj.(*jscal.Event).Sequence; seq != 2*updates {
+			t.Errorf("Expected sequence %d for e%d, got %d", 2*updates, i, seq)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package store
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/airtrafik/jscal"
+)
+
+// ChangeType is the kind of write a Change reports
+type ChangeType string
+
+// Change types
+const (
+	ChangeCreated ChangeType = "created"
+	ChangeUpdated ChangeType = "updated"
+	ChangeDeleted ChangeType = "deleted"
+)
+
+// Change is a write to the store, as sent to watchers. Before and After
+// are shared with the store and must not be modified; Clone them to make
+// changes.
+type Change struct {
+	Type   ChangeType           `json:"type"`
+	UID    string               `json:"uid"`
+	Before jscal.CalendarObject `json:"before,omitempty"` // nil when created
+	After  jscal.CalendarObject `json:"after,omitempty"`  // nil when deleted
+	State  string               `json:"state"`            // the store's State after the change
+}
+
+// Filter selects the changes a watcher receives
+type Filter func(c Change) bool
+
+// Types returns a Filter selecting changes of the given types
+func Types(types ...ChangeType) Filter {
+	return func(c Change) bool {
+		for _, t := range types {
+			if c.Type == t {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Cancellations is a Filter selecting updates that set the status of an
+// event or task to cancelled
+func Cancellations(c Change) bool {
+	if c.Type != ChangeUpdated {
+		return false
+	}
+	return status(c.After) == jscal.StatusCancelled && status(c.Before) != jscal.StatusCancelled
+}
+
+// status returns the status of an event or task, "" for other objects
+func status(obj jscal.CalendarObject) string {
+	if s, ok := obj.(interface{ GetStatus() string }); ok {
+		return s.GetStatus()
+	}
+	return ""
+}
+
+// Watch returns a channel receiving the changes selected by filter, or all
+// changes if it is nil, in the order they were made, and a function that
+// stops watching and closes the channel. Writers never wait for watchers:
+// changes queue up until received.
+func (s *Store) Watch(filter Filter) (<-chan Change, func()) {
+	w := &watcher{
+		filter: filter,
+		ch:     make(chan Change),
+		wake:   make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+	s.mu.Lock()
+	if s.watchers == nil {
+		s.watchers = make(map[*watcher]struct{})
+	}
+	s.watchers[w] = struct{}{}
+	s.mu.Unlock()
+	go w.run()
+
+	var once sync.Once
+	return w.ch, func() {
+		once.Do(func() {
+			s.mu.Lock()
+			delete(s.watchers, w)
+			s.mu.Unlock()
+			close(w.done)
+		})
+	}
+}
+
+// notify queues a change for the watchers. The caller holds s.mu and has
+// applied the change.
+func (s *Store) notify(uid string, before, after jscal.CalendarObject) {
+	if len(s.watchers) == 0 {
+		return
+	}
+	c := Change{UID: uid, Before: before, After: after, State: strconv.FormatUint(s.state, 10)}
+	switch {
+	case before == nil:
+		c.Type = ChangeCreated
+	case after == nil:
+		c.Type = ChangeDeleted
+	default:
+		c.Type = ChangeUpdated
+	}
+	for w := range s.watchers {
+		w.push(c)
+	}
+}
+
+// watcher queues changes for one Watch channel
+type watcher struct {
+	filter Filter
+	ch     chan Change
+	wake   chan struct{}
+	done   chan struct{}
+
+	mu    sync.Mutex
+	queue []Change
+}
+
+// push queues a change without blocking
+func (w *watcher) push(c Change) {
+	w.mu.Lock()
+	w.queue = append(w.queue, c)
+	w.mu.Unlock()
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run sends queued changes matching the filter until the watcher is
+// stopped, then closes the channel. The filter runs here rather than in
+// push, so that it can't block writers.
+func (w *watcher) run() {
+	defer close(w.ch)
+	for {
+		w.mu.Lock()
+		if len(w.queue) == 0 {
+			w.mu.Unlock()
+			select {
+			case <-w.wake:
+				continue
+			case <-w.done:
+				return
+			}
+		}
+		c := w.queue[0]
+		w.queue[0] = Change{}
+		w.queue = w.queue[1:]
+		w.mu.Unlock()
+
+		if w.filter != nil && !w.filter(c) {
+			continue
+		}
+		select {
+		case w.ch <- c:
+		case <-w.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/airtrafik/jscal"
+)
+
+// receive returns the next change, failing the test if none arrives
+func receive(t *testing.T, changes <-chan Change) Change {
+	t.Helper()
+	select {
+	case c := <-changes:
+		return c
+	case <-time.After(time.Second):
+		t.Fatal("Expected a change")
+		return Change{}
+	}
+}
+
+func TestWatch(t *testing.T) {
+	s := New()
+	changes, stop := s.Watch(nil)
+
+	if err := s.Put(newEvent("e1", "Review")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Update("e1", retitle("Moved")); err != nil {
+		t.Fatal(err)
+	}
+	etag, _ := s.ETag("e1")
+	if err := s.DeleteIf("e1", Precondition{ETag: etag}); err != nil {
+		t.Fatal(err)
+	}
+
+	created := receive(t, changes)
+	if created.Type != ChangeCreated || created.UID != "e1" || created.Before != nil || created.After.(*jscal.Event).GetTitle() != "Review" {
+		t.Errorf("Unexpected created change %+v", created)
+	}
+	updated := receive(t, changes)
+	if updated.Type != ChangeUpdated || updated.Before.(*jscal.Event).GetTitle() != "Review" || updated.After.(*jscal.Event).GetTitle() != "Moved" {
+		t.Errorf("Unexpected updated change %+v", updated)
+	}
+	deleted := receive(t, changes)
+	if deleted.Type != ChangeDeleted || deleted.Before.(*jscal.Event).GetTitle() != "Moved" || deleted.After != nil {
+		t.Errorf("Unexpected deleted change %+v", deleted)
+	}
+	if deleted.State != s.State() {
+		t.Errorf("Expected state %s, got %s", s.State(), deleted.State)
+	}
+
+	stop()
+	stop()
+	if _, ok := <-changes; ok {
+		t.Error("Expected the channel to be closed")
+	}
+	if err := s.Put(newEvent("e2", "After stop")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatchFilter(t *testing.T) {
+	s := New()
+	cancellations, stop := s.Watch(Cancellations)
+	defer stop()
+	deletions, stopDeletions := s.Watch(Types(ChangeDeleted))
+	defer stopDeletions()
+
+	if err := s.Put(newEvent("e1", "Review")); err != nil {
+		t.Fatal(err)
+	}
+	cancel := func(obj jscal.CalendarObject) error {
+		obj.(*jscal.Event).Status = jscal.String(jscal.StatusCancelled)
+		return nil
+	}
+	if err := s.Update("e1", retitle("Renamed")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Update("e1", cancel); err != nil {
+		t.Fatal(err)
+	}
+	// Already cancelled
+	if err := s.Update("e1", retitle("Cancelled")); err != nil {
+		t.Fatal(err)
+	}
+	s.Delete("e1")
+
+	if c := receive(t, cancellations); c.Type != ChangeUpdated || c.After.(*jscal.Event).GetTitle() != "Renamed" {
+		t.Errorf("Expected the cancellation, got %+v", c)
+	}
+	if c := receive(t, deletions); c.Type != ChangeDeleted {
+		t.Errorf("Expected the deletion, got %+v", c)
+	}
+	select {
+	case c := <-cancellations:
+		t.Errorf("Expected one cancellation, got another %+v", c)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestWatchDoesNotBlockWriters(t *testing.T) {
+	s := New()
+	changes, stop := s.Watch(nil)
+	defer stop()
+
+	// Nobody receives while the writes happen
+	for i := 0; i < 100; i++ {
+		if err := s.Put(newEvent("e1", "Review")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 100; i++ {
+		want := ChangeUpdated
+		if i == 0 {
+			want = ChangeCreated
+		}
+		if c := receive(t, changes); c.Type != want {
+			t.Fatalf("Change %d: expected %s, got %s", i, want, c.Type)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Webhook delivers changes as JSON POST requests to a URL, e.g. to notify
+// a chat channel when a meeting is cancelled:
+//
+//	changes, stop := s.Watch(store.Cancellations)
+//	defer stop()
+//	go (&store.Webhook{URL: hookURL}).Run(ctx, changes)
+type Webhook struct {
+	URL string
+
+	// Client sends the requests, http.DefaultClient when nil
+	Client *http.Client
+
+	// Header is added to every request, e.g. for an Authorization header
+	Header http.Header
+
+	// Attempts is how often a change is sent before giving up, 3 when 0.
+	// Network errors, 429 and 5xx responses are retried.
+	Attempts int
+
+	// Backoff is the wait before the first retry, doubling with each
+	// retry, 1s when 0
+	Backoff time.Duration
+
+	// OnError is called by Run for changes that couldn't be delivered
+	OnError func(c Change, err error)
+}
+
+// Run sends the changes from the channel until it is closed or ctx is
+// done, and returns ctx's error in the latter case. Changes that can't be
+// delivered are passed to OnError and skipped.
+func (w *Webhook) Run(ctx context.Context, changes <-chan Change) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case c, ok := <-changes:
+			if !ok {
+				return nil
+			}
+			if err := w.Send(ctx, c); err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				if w.OnError != nil {
+					w.OnError(c, err)
+				}
+			}
+		}
+	}
+}
+
+// Send posts one change, retrying as configured. Any 2xx response counts
+// as delivered.
+func (w *Webhook) Send(ctx context.Context, c Change) error {
+	body, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to encode change: %w", err)
+	}
+
+	attempts := w.Attempts
+	if attempts <= 0 {
+		attempts = 3
+	}
+	backoff := w.Backoff
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+
+	for attempt := 1; ; attempt++ {
+		retry, err := w.post(ctx, body, c)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt == attempts {
+			return fmt.Errorf("webhook %s: %w", w.URL, err)
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+// post sends one request and reports whether a failure is worth retrying
+func (w *Webhook) post(ctx context.Context, body []byte, c Change) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	for key, values := range w.Header {
+		req.Header[key] = append([]string(nil), values...)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-JSCal-Change", string(c.Type))
+
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("unexpected status %s", resp.Status)
+}
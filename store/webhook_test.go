@@ -0,0 +1,141 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWebhookSend(t *testing.T) {
+	var mu sync.Mutex
+	var requests int
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		if requests < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("Content-Type") != "application/json" || r.Header.Get("X-JSCal-Change") != "created" || r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("Unexpected headers %v", r.Header)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("Invalid body: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	hook := &Webhook{URL: server.URL, Backoff: time.Millisecond, Header: http.Header{"Authorization": {"Bearer token"}}}
+	c := Change{Type: ChangeCreated, UID: "e1", After: newEvent("e1", "Review"), State: "1"}
+	if err := hook.Send(context.Background(), c); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("Expected 3 attempts, got %d", requests)
+	}
+	after, _ := received["after"].(map[string]interface{})
+	if received["type"] != "created" || received["uid"] != "e1" || after["title"] != "Review" {
+		t.Errorf("Unexpected body %v", received)
+	}
+	if _, ok := received["before"]; ok {
+		t.Errorf("Expected no before object, got %v", received["before"])
+	}
+}
+
+func TestWebhookErrors(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path == "/gone" {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	c := Change{Type: ChangeDeleted, UID: "e1", Before: newEvent("e1", "Review")}
+	hook := &Webhook{URL: server.URL, Attempts: 2, Backoff: time.Millisecond}
+	if err := hook.Send(context.Background(), c); err == nil {
+		t.Error("Expected error after the last attempt")
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 attempts, got %d", requests)
+	}
+
+	// Client errors other than 429 aren't retried
+	requests = 0
+	hook = &Webhook{URL: server.URL + "/gone", Backoff: time.Millisecond}
+	if err := hook.Send(context.Background(), c); err == nil {
+		t.Error("Expected error for 410 Gone")
+	}
+	if requests != 1 {
+		t.Errorf("Expected 1 attempt, got %d", requests)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	hook = &Webhook{URL: server.URL, Backoff: time.Hour}
+	if err := hook.Send(ctx, c); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestWebhookRun(t *testing.T) {
+	var mu sync.Mutex
+	var uids []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var c map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&c)
+		mu.Lock()
+		uids = append(uids, c["uid"].(string))
+		mu.Unlock()
+		if c["uid"] == "bad" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	s := New()
+	changes, stop := s.Watch(Types(ChangeCreated))
+	var failed []string
+	hook := &Webhook{URL: server.URL, OnError: func(c Change, err error) { failed = append(failed, c.UID) }}
+	done := make(chan error)
+	go func() { done <- hook.Run(context.Background(), changes) }()
+
+	for _, uid := range []string{"e1", "bad", "e2"} {
+		if err := s.Put(newEvent(uid, uid)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.Delete("e1")
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		mu.Lock()
+		n := len(uids)
+		mu.Unlock()
+		if n == 3 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	stop()
+	if err := <-done; err != nil {
+		t.Errorf("Expected Run to end without error, got %v", err)
+	}
+	if len(uids) != 3 || uids[0] != "e1" || uids[2] != "e2" {
+		t.Errorf("Expected e1, bad and e2 in order, got %v", uids)
+	}
+	if len(failed) != 1 || failed[0] != "bad" {
+		t.Errorf("Expected bad to fail, got %v", failed)
+	}
+}
@@ -0,0 +1,90 @@
+package jscal
+
+import (
+	"strings"
+	"time"
+)
+
+// NoTitle is the placeholder returned by EffectiveTitle for untitled events
+const NoTitle = "(No title)"
+
+// EffectiveTitle returns the title to display for the given locale. It falls
+// back from the localized title to the title, then to the first line of the
+// description, and finally to NoTitle.
+func (e *Event) EffectiveTitle(locale string) string {
+	if title := e.localizedTitle(locale); title != "" {
+		return title
+	}
+	if e.Title != nil {
+		if title := strings.TrimSpace(*e.Title); title != "" {
+			return title
+		}
+	}
+	if e.Description != nil {
+		for _, line := range strings.Split(*e.Description, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				return line
+			}
+		}
+	}
+	return NoTitle
+}
+
+// localizedTitle returns the title patch for locale, following its lookup
+// chain from the exact tag to the primary language subtag (e.g. "de" for
+// "de-AT")
+func (e *Event) localizedTitle(locale string) string {
+	if locale == "" || len(e.Localizations) == 0 {
+		return ""
+	}
+
+	for _, tag := range matchLanguage(e.Localizations, locale) {
+		if title, ok := e.Localizations[tag]["title"].(string); ok {
+			if title = strings.TrimSpace(title); title != "" {
+				return title
+			}
+		}
+	}
+	return ""
+}
+
+// SummaryLine returns a one-line summary of an occurrence starting at
+// occurrence, e.g. "09:00–09:30 Standup (Zoom)". Times are shown in tz
+// (the occurrence's own location if nil). All-day events show "All day".
+func (e *Event) SummaryLine(occurrence time.Time, tz *time.Location, locale string) string {
+	if tz != nil {
+		occurrence = occurrence.In(tz)
+	}
+
+	var when string
+	if e.IsAllDay() {
+		when = "All day"
+	} else {
+		when = occurrence.Format("15:04")
+		if duration, err := e.GetDuration(); err == nil && duration > 0 {
+			when += "–" + occurrence.Add(duration).Format("15:04")
+		}
+	}
+
+	line := when + " " + e.EffectiveTitle(locale)
+	if where := e.summaryLocation(); where != "" {
+		line += " (" + where + ")"
+	}
+	return line
+}
+
+// summaryLocation returns the name of the first named location, preferring
+// physical locations over virtual ones
+func (e *Event) summaryLocation() string {
+	for _, id := range sortedKeys(e.Locations) {
+		if l := e.Locations[id]; l != nil && l.Name != nil && *l.Name != "" {
+			return *l.Name
+		}
+	}
+	for _, id := range sortedKeys(e.VirtualLocations) {
+		if vl := e.VirtualLocations[id]; vl != nil && vl.Name != nil && *vl.Name != "" {
+			return *vl.Name
+		}
+	}
+	return ""
+}
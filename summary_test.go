@@ -0,0 +1,105 @@
+package jscal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEffectiveTitle(t *testing.T) {
+	tests := []struct {
+		name   string
+		event  *Event
+		locale string
+		want   string
+	}{
+		{
+			name:  "title",
+			event: &Event{Title: String("Standup")},
+			want:  "Standup",
+		},
+		{
+			name: "exact localization",
+			event: &Event{
+				Title:         String("Standup"),
+				Localizations: map[string]map[string]interface{}{"de-AT": {"title": "Morgenrunde"}},
+			},
+			locale: "de-at",
+			want:   "Morgenrunde",
+		},
+		{
+			name: "language fallback",
+			event: &Event{
+				Title:         String("Standup"),
+				Localizations: map[string]map[string]interface{}{"de": {"title": "Tägliches Treffen"}},
+			},
+			locale: "de-CH",
+			want:   "Tägliches Treffen",
+		},
+		{
+			name: "script fallback",
+			event: &Event{
+				Title:         String("Standup"),
+				Localizations: map[string]map[string]interface{}{"zh": {"title": "站会"}, "zh-Hant": {"title": "站會"}},
+			},
+			locale: "zh_hant_tw",
+			want:   "站會",
+		},
+		{
+			name: "localization without title",
+			event: &Event{
+				Title:         String("Standup"),
+				Localizations: map[string]map[string]interface{}{"fr": {"description": "Réunion"}},
+			},
+			locale: "fr",
+			want:   "Standup",
+		},
+		{
+			name:  "description first line",
+			event: &Event{Title: String("  "), Description: String("\n  Call with Bob  \nAgenda: ...")},
+			want:  "Call with Bob",
+		},
+		{
+			name:  "no title",
+			event: &Event{},
+			want:  NoTitle,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.event.EffectiveTitle(tt.locale); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSummaryLine(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	occurrence := time.Date(2025, 3, 3, 8, 0, 0, 0, time.UTC)
+
+	event := NewEvent("summary-test", "Standup")
+	event.Duration = String("PT30M")
+	event.AddVirtualLocation("zoom", &VirtualLocation{Type: "VirtualLocation", Name: String("Zoom"), URI: "https://zoom.us/j/1"})
+
+	if got, want := event.SummaryLine(occurrence, berlin, ""), "09:00–09:30 Standup (Zoom)"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	if got, want := event.SummaryLine(occurrence, nil, ""), "08:00–08:30 Standup (Zoom)"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+
+	event.AddLocation("room", &Location{Name: String("Room 4")})
+	event.Duration = nil
+	if got, want := event.SummaryLine(occurrence, berlin, ""), "09:00 Standup (Room 4)"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+
+	allDay := &Event{ShowWithoutTime: Bool(true), Localizations: map[string]map[string]interface{}{"fr": {"title": "Férié"}}}
+	if got, want := allDay.SummaryLine(occurrence, berlin, "fr"), "All day Férié"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
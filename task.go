@@ -3,6 +3,7 @@ package jscal
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"time"
 )
 
@@ -15,13 +16,13 @@ var _ CalendarObject = &Task{}
 // time to complete, and recur, none of which is required.
 type Task struct {
 	// Core metadata (Section 4.1)
-	Type     string     `json:"@type"`              // Always "Task"
-	UID      string     `json:"uid"`                // Unique identifier
-	Created  *time.Time `json:"created,omitempty"`  // Creation timestamp
-	Updated  *time.Time `json:"updated,omitempty"`  // Last modification timestamp
-	Sequence *int       `json:"sequence,omitempty"` // Revision sequence number
-	Method   *string    `json:"method,omitempty"`   // iTIP method
-	ProdId   *string    `json:"prodId,omitempty"`   // Product identifier that created this
+	Type     string       `json:"@type"`              // Always "Task"
+	UID      string       `json:"uid"`                // Unique identifier
+	Created  *UTCDateTime `json:"created,omitempty"`  // Creation timestamp
+	Updated  *UTCDateTime `json:"updated,omitempty"`  // Last modification timestamp
+	Sequence *int         `json:"sequence,omitempty"` // Revision sequence number
+	Method   *string      `json:"method,omitempty"`   // iTIP method
+	ProdId   *string      `json:"prodId,omitempty"`   // Product identifier that created this
 
 	// What and Where Properties (Section 4.2)
 	Title                  *string                           `json:"title,omitempty"`                  // Task summary/title
@@ -42,9 +43,9 @@ type Task struct {
 	TimeZones         map[string]*TimeZone `json:"timeZones,omitempty"`         // Custom timezone definitions
 
 	// Task-specific progress properties (Section 5.2)
-	PercentComplete *int       `json:"percentComplete,omitempty"` // Completion percentage (0-100)
-	Progress        *string    `json:"progress,omitempty"`        // needs-action, in-process, completed, failed, cancelled
-	ProgressUpdated *time.Time `json:"progressUpdated,omitempty"` // When progress was last updated
+	PercentComplete *int         `json:"percentComplete,omitempty"` // Completion percentage (0-100)
+	Progress        *string      `json:"progress,omitempty"`        // needs-action, in-process, completed, failed, cancelled
+	ProgressUpdated *UTCDateTime `json:"progressUpdated,omitempty"` // When progress was last updated
 
 	// Recurrence Properties (Section 4.3)
 	RecurrenceId            *LocalDateTime                    `json:"recurrenceId,omitempty"`         // Recurrence instance identifier
@@ -63,6 +64,11 @@ type Task struct {
 	Participants   map[string]*Participant `json:"participants,omitempty"`   // Task participants
 	RequestStatus  *string                 `json:"requestStatus,omitempty"`  // Scheduling request status
 
+	// Invitation permissions (JMAP Calendars)
+	MayInviteSelf   *bool `json:"mayInviteSelf,omitempty"`   // Anyone may add themselves as an attendee
+	MayInviteOthers *bool `json:"mayInviteOthers,omitempty"` // Attendees may invite others
+	HideAttendees   *bool `json:"hideAttendees,omitempty"`   // Only owners see every participant
+
 	// Alerts Properties (Section 4.5)
 	UseDefaultAlerts *bool             `json:"useDefaultAlerts,omitempty"` // Use default alert settings
 	Alerts           map[string]*Alert `json:"alerts,omitempty"`           // Custom alerts
@@ -87,13 +93,13 @@ type Task struct {
 
 // NewTask creates a new JSCalendar Task with required fields
 func NewTask(uid, title string) *Task {
-	now := time.Now().UTC()
+	now := Now().UTC()
 	return &Task{
 		Type:     "Task",
 		UID:      uid,
 		Title:    &title,
-		Created:  &now,
-		Updated:  &now,
+		Created:  NewUTCDateTime(now),
+		Updated:  NewUTCDateTime(now),
 		Sequence: Int(0),
 		Progress: String(ProgressNeedsAction),
 	}
@@ -109,6 +115,55 @@ func (t *Task) PrettyJSON() ([]byte, error) {
 	return json.MarshalIndent(t, "", "  ")
 }
 
+// MarshalPartial returns the Task as JSON with only the named top-level
+// properties, e.g. "title", "start" and "duration" for sparse listings.
+// Extensions are named like any other property and unknown names are
+// ignored. The properties are written as MarshalJSON writes them, without
+// building an intermediate map.
+func (t *Task) MarshalPartial(fields []string) ([]byte, error) {
+	if fields == nil {
+		fields = []string{}
+	}
+	return t.appendFields(make([]byte, 0, 256), fields)
+}
+
+// MarshalJSON implements json.Marshaler, writing Extensions as top-level properties
+func (t Task) MarshalJSON() ([]byte, error) {
+	if data, err := t.appendJSON(make([]byte, 0, 512)); err == nil {
+		return data, nil
+	}
+
+	// Let encoding/json report the error
+	type Alias Task
+	data, err := json.Marshal(Alias(t))
+	if err != nil {
+		return nil, err
+	}
+	return appendExtensions(data, t.Extensions, reflect.TypeOf(Alias{}))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, capturing unrecognized top-level
+// properties such as "example.com:custom" into Extensions
+func (t *Task) UnmarshalJSON(data []byte) error {
+	saved := *t
+	if err := t.decodeJSON(data); err == nil {
+		return nil
+	}
+
+	// Decode again with encoding/json, which reports errors with context
+	*t = saved
+	type Alias Task
+	if err := json.Unmarshal(data, (*Alias)(t)); err != nil {
+		return err
+	}
+	extensions, err := extractExtensions(data, reflect.TypeOf(Alias{}))
+	if err != nil {
+		return err
+	}
+	t.Extensions = extensions
+	return nil
+}
+
 // Clone creates a deep copy of the Task
 func (t *Task) Clone() *Task {
 	data, _ := json.Marshal(t)
@@ -117,6 +172,15 @@ func (t *Task) Clone() *Task {
 	return &clone
 }
 
+// validProgress lists the progress values of a task and its participants
+var validProgress = map[string]bool{
+	ProgressNeedsAction: true,
+	ProgressInProcess:   true,
+	ProgressCompleted:   true,
+	ProgressFailed:      true,
+	ProgressCancelled:   true,
+}
+
 // IsCompleted returns true if the task is marked as completed
 func (t *Task) IsCompleted() bool {
 	return t.Progress != nil && *t.Progress == ProgressCompleted
@@ -129,15 +193,15 @@ func (t *Task) IsOverdue() bool {
 	}
 
 	dueTime := t.Due.Time()
-	return time.Now().After(dueTime)
+	return Now().After(dueTime)
 }
 
 // SetProgress updates the task's progress and completion percentage
 func (t *Task) SetProgress(progress string, percentComplete int) {
 	t.Progress = &progress
 	t.PercentComplete = &percentComplete
-	now := time.Now().UTC()
-	t.ProgressUpdated = &now
+	now := Now().UTC()
+	t.ProgressUpdated = NewUTCDateTime(now)
 	t.Touch()
 }
 
@@ -174,8 +238,8 @@ func (t *Task) GetTimeToComplete() (time.Duration, error) {
 
 // Touch updates the Updated timestamp and increments sequence
 func (t *Task) Touch() {
-	now := time.Now().UTC()
-	t.Updated = &now
+	now := Now().UTC()
+	t.Updated = NewUTCDateTime(now)
 	if t.Sequence != nil {
 		*t.Sequence++
 	} else {
@@ -191,6 +255,73 @@ func (t *Task) AddParticipant(id string, participant *Participant) {
 	t.Participants[id] = participant
 }
 
+// SetParticipantProgress records the progress of a participant in the task,
+// with progressUpdated set to now
+func (t *Task) SetParticipantProgress(id string, progress string, percentComplete int) error {
+	participant := t.Participants[id]
+	if participant == nil {
+		return fmt.Errorf("participant %s not found", id)
+	}
+	participant.Progress = &progress
+	participant.PercentComplete = &percentComplete
+	now := Now().UTC()
+	participant.ProgressUpdated = NewUTCDateTime(now)
+	t.Touch()
+	return nil
+}
+
+// OverallProgress returns the progress and completion percentage of the
+// task as a whole, derived from the participants that track their own: it
+// has failed if any of them has, is completed or cancelled once all of
+// them are, needs action while none has started and is in process
+// otherwise. Cancelled participants don't count towards the others. The
+// percentage is their average, with 100 for completed participants
+// without one. A task whose participants don't track progress returns its
+// own.
+func (t *Task) OverallProgress() (string, int) {
+	var tracking []*Participant
+	for _, id := range sortedKeys(t.Participants) {
+		if p := t.Participants[id]; p != nil && (p.Progress != nil || p.PercentComplete != nil) {
+			tracking = append(tracking, p)
+		}
+	}
+	if len(tracking) == 0 {
+		return t.GetProgress(), t.GetPercentComplete()
+	}
+
+	counts := make(map[string]int)
+	total, percent := 0, 0
+	for _, p := range tracking {
+		progress := deref(p.Progress)
+		if progress == "" {
+			progress = ProgressNeedsAction
+		}
+		counts[progress]++
+		if progress == ProgressCancelled {
+			continue
+		}
+		switch {
+		case p.PercentComplete != nil:
+			percent += *p.PercentComplete
+		case progress == ProgressCompleted:
+			percent += 100
+		}
+		total++
+	}
+
+	switch {
+	case total == 0:
+		return ProgressCancelled, 0
+	case counts[ProgressFailed] > 0:
+		return ProgressFailed, percent / total
+	case counts[ProgressCompleted] == total:
+		return ProgressCompleted, 100
+	case counts[ProgressNeedsAction] == total && percent == 0:
+		return ProgressNeedsAction, 0
+	}
+	return ProgressInProcess, percent / total
+}
+
 // AddLocation adds a location to the task
 func (t *Task) AddLocation(id string, location *Location) {
 	if t.Locations == nil {
@@ -215,6 +346,14 @@ func (t *Task) AddAlert(id string, alert *Alert) {
 	t.Alerts[id] = alert
 }
 
+// EffectiveAlerts returns the alerts that apply to the task: its own, or the
+// provider's defaults if useDefaultAlerts is true, in which case RFC 8984
+// says to ignore alerts. Without a provider there are then no alerts. The
+// returned map is not a copy.
+func (t *Task) EffectiveAlerts(provider DefaultAlertsProvider) map[string]*Alert {
+	return effectiveAlerts(t, t.Alerts, t.UseDefaultAlerts, provider)
+}
+
 // AddCategory adds a category to the task
 func (t *Task) AddCategory(category string) {
 	if t.Categories == nil {
@@ -239,6 +378,13 @@ func (t *Task) AddLink(id string, link *Link) {
 	t.Links[id] = link
 }
 
+// AddRelation relates the task to the object with the given UID. relType is
+// one of the RelationType constants, e.g. RelationTypeParent when uid is
+// the parent of this task; an empty relType adds an unspecified relation.
+func (t *Task) AddRelation(uid, relType string) {
+	addRelation(&t.RelatedTo, uid, relType)
+}
+
 // SetRecurrence sets the recurrence rules for the task
 func (t *Task) SetRecurrence(rules []RecurrenceRule) {
 	t.RecurrenceRules = rules
@@ -267,6 +413,21 @@ func (t *Task) GetStartTime() (*time.Time, error) {
 	return &startTime, nil
 }
 
+// GetExtension returns the extension property with the given name. Values of
+// registered extensions are decoded into a pointer to the registered Go type,
+// others are returned as parsed from JSON.
+func (t *Task) GetExtension(name string) (interface{}, error) {
+	return getExtension(t.Extensions, name)
+}
+
+// SetExtension sets an extension property
+func (t *Task) SetExtension(name string, value interface{}) {
+	if t.Extensions == nil {
+		t.Extensions = make(map[string]interface{})
+	}
+	t.Extensions[name] = value
+}
+
 // GetUID returns the task's UID (implements CalendarObject)
 func (t *Task) GetUID() string {
 	return t.UID
@@ -279,6 +440,11 @@ func (t *Task) GetType() string {
 
 // Validate validates the Task according to RFC 8984
 func (t *Task) Validate() error {
+	return t.validate(nil)
+}
+
+// validate validates the Task with the given options (nil for defaults)
+func (t *Task) validate(opts *ValidationOptions) error {
 	if t == nil {
 		return ValidationError{
 			Field:   "task",
@@ -307,7 +473,7 @@ func (t *Task) Validate() error {
 	}
 
 	// Validate UID length (RFC 8984: max 255 octets)
-	if len(t.UID) > MaxUIDLength {
+	if opts.enforceLengths() && len(t.UID) > MaxUIDLength {
 		errors = append(errors, ValidationError{
 			Field:   "uid",
 			Value:   t.UID,
@@ -315,8 +481,13 @@ func (t *Task) Validate() error {
 		})
 	}
 
+	// Validate timestamps
+	errors = append(errors, validateUTCDateTime("created", t.Created)...)
+	errors = append(errors, validateUTCDateTime("updated", t.Updated)...)
+	errors = append(errors, validateUTCDateTime("progressUpdated", t.ProgressUpdated)...)
+
 	// Validate title length if present
-	if t.Title != nil && len(*t.Title) > MaxTitleLength {
+	if opts.enforceLengths() && t.Title != nil && len(*t.Title) > MaxTitleLength {
 		errors = append(errors, ValidationError{
 			Field:   "title",
 			Value:   *t.Title,
@@ -325,7 +496,7 @@ func (t *Task) Validate() error {
 	}
 
 	// Validate description length if present
-	if t.Description != nil && len(*t.Description) > MaxDescriptionLength {
+	if opts.enforceLengths() && t.Description != nil && len(*t.Description) > MaxDescriptionLength {
 		errors = append(errors, ValidationError{
 			Field:   "description",
 			Value:   *t.Description,
@@ -333,16 +504,18 @@ func (t *Task) Validate() error {
 		})
 	}
 
+	// Validate locale
+	if t.Locale != nil && opts.enforceLanguageTags() && !IsLanguageTag(*t.Locale) {
+		errors = append(errors, ValidationError{
+			Field:   "locale",
+			Value:   *t.Locale,
+			Message: "invalid language tag",
+		})
+	}
+
 	// Validate progress
 	if t.Progress != nil {
-		validProgress := map[string]bool{
-			ProgressNeedsAction: true,
-			ProgressInProcess:   true,
-			ProgressCompleted:   true,
-			ProgressFailed:      true,
-			ProgressCancelled:   true,
-		}
-		if !validProgress[*t.Progress] {
+		if opts.enforceEnums() && !validProgress[*t.Progress] {
 			errors = append(errors, ValidationError{
 				Field:   "progress",
 				Value:   *t.Progress,
@@ -364,7 +537,7 @@ func (t *Task) Validate() error {
 
 	// Validate estimatedDuration format if present
 	if t.EstimatedDuration != nil {
-		if !durationPattern.MatchString(*t.EstimatedDuration) {
+		if !isDuration(*t.EstimatedDuration) {
 			errors = append(errors, ValidationError{
 				Field:   "estimatedDuration",
 				Value:   *t.EstimatedDuration,
@@ -394,7 +567,7 @@ func (t *Task) Validate() error {
 			"completed":    true,
 			"cancelled":    true,
 		}
-		if !validStatus[*t.Status] {
+		if opts.enforceEnums() && !validStatus[*t.Status] {
 			errors = append(errors, ValidationError{
 				Field:   "status",
 				Value:   *t.Status,
@@ -422,7 +595,7 @@ func (t *Task) Validate() error {
 			FreeBusyTentative:   true,
 			FreeBusyUnavailable: true,
 		}
-		if !validFreeBusy[*t.FreeBusyStatus] {
+		if opts.enforceEnums() && !validFreeBusy[*t.FreeBusyStatus] {
 			errors = append(errors, ValidationError{
 				Field:   "freeBusyStatus",
 				Value:   *t.FreeBusyStatus,
@@ -438,7 +611,7 @@ func (t *Task) Validate() error {
 			PrivacyPrivate: true,
 			PrivacySecret:  true,
 		}
-		if !validPrivacy[*t.Privacy] {
+		if opts.enforceEnums() && !validPrivacy[*t.Privacy] {
 			errors = append(errors, ValidationError{
 				Field:   "privacy",
 				Value:   *t.Privacy,
@@ -456,16 +629,26 @@ func (t *Task) Validate() error {
 		})
 	}
 
+	// Validate requestStatus
+	errors = append(errors, validateRequestStatus(t.RequestStatus)...)
+	errors = append(errors, validateHideAttendees(t.HideAttendees, t.Participants)...)
+
+	// Validate replyTo and sentBy
+	errors = append(errors, validateMethodURIs("replyTo", t.ReplyTo)...)
+	if t.SentBy != nil {
+		errors = append(errors, validateEmailAt("sentBy", *t.SentBy)...)
+	}
+
 	// Validate participants
 	for id, participant := range t.Participants {
-		if errs := validateParticipant(id, participant); len(errs) > 0 {
+		if errs := validateParticipant(id, participant, opts); len(errs) > 0 {
 			errors = append(errors, errs...)
 		}
 	}
 
 	// Validate locations
 	for id, location := range t.Locations {
-		if errs := validateLocation(id, location); len(errs) > 0 {
+		if errs := validateLocation(id, location, opts); len(errs) > 0 {
 			errors = append(errors, errs...)
 		}
 	}
@@ -479,7 +662,7 @@ func (t *Task) Validate() error {
 
 	// Validate alerts
 	for id, alert := range t.Alerts {
-		if errs := validateAlert(id, alert); len(errs) > 0 {
+		if errs := validateAlert(id, alert, opts); len(errs) > 0 {
 			errors = append(errors, errs...)
 		}
 	}
@@ -493,10 +676,21 @@ func (t *Task) Validate() error {
 
 	// Validate recurrence rules
 	for i, rule := range t.RecurrenceRules {
-		if errs := validateRecurrenceRule(fmt.Sprintf("recurrenceRules[%d]", i), &rule); len(errs) > 0 {
+		if errs := validateRecurrenceRule(fmt.Sprintf("recurrenceRules[%d]", i), &rule, opts); len(errs) > 0 {
 			errors = append(errors, errs...)
 		}
 	}
+	for i, rule := range t.ExcludedRecurrenceRules {
+		if errs := validateRecurrenceRule(fmt.Sprintf("excludedRecurrenceRules[%d]", i), &rule, opts); len(errs) > 0 {
+			errors = append(errors, errs...)
+		}
+	}
+
+	// Validate localizations
+	errors = append(errors, validateLocalizations(t, t.Localizations, opts)...)
+
+	// Validate registered extension properties
+	errors = append(errors, validateExtensions(t.Extensions)...)
 
 	if len(errors) > 0 {
 		return errors
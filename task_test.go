@@ -259,7 +259,7 @@ func TestTaskSetProgress(t *testing.T) {
 		t.Error("Expected ProgressUpdated to be set")
 	}
 
-	if task.Updated.Equal(*originalUpdated) {
+	if task.Updated.Equal(originalUpdated) {
 		t.Error("Expected Updated timestamp to change")
 	}
 
@@ -269,6 +269,109 @@ func TestTaskSetProgress(t *testing.T) {
 	}
 }
 
+func TestTaskSetParticipantProgress(t *testing.T) {
+	task := NewTask("task-123", "Test Task")
+	task.AddParticipant("alice", &Participant{Name: String("Alice")})
+
+	if err := task.SetParticipantProgress("alice", ProgressInProcess, 60); err != nil {
+		t.Fatalf("SetParticipantProgress failed: %v", err)
+	}
+	if err := task.SetParticipantProgress("bob", ProgressCompleted, 100); err == nil {
+		t.Error("Expected an error for an unknown participant")
+	}
+
+	data, err := json.Marshal(task)
+	if err != nil {
+		t.Fatalf("Failed to marshal task: %v", err)
+	}
+	parsed, err := ParseTask(data)
+	if err != nil {
+		t.Fatalf("Failed to parse task: %v", err)
+	}
+	alice := parsed.Participants["alice"]
+	if alice.Progress == nil || *alice.Progress != ProgressInProcess {
+		t.Errorf("Expected progress '%s', got '%v'", ProgressInProcess, alice.Progress)
+	}
+	if alice.PercentComplete == nil || *alice.PercentComplete != 60 {
+		t.Errorf("Expected percentComplete 60, got %v", alice.PercentComplete)
+	}
+	if alice.ProgressUpdated == nil {
+		t.Error("Expected progressUpdated to be set")
+	}
+}
+
+func TestTaskOverallProgress(t *testing.T) {
+	tests := []struct {
+		name         string
+		participants map[string]*Participant
+		wantProgress string
+		wantPercent  int
+	}{
+		{
+			name:         "no participant progress",
+			participants: map[string]*Participant{"a": {Name: String("A")}},
+			wantProgress: ProgressInProcess,
+			wantPercent:  10,
+		},
+		{
+			name: "none started",
+			participants: map[string]*Participant{
+				"a": {Progress: String(ProgressNeedsAction)},
+				"b": {Progress: String(ProgressNeedsAction)},
+			},
+			wantProgress: ProgressNeedsAction,
+			wantPercent:  0,
+		},
+		{
+			name: "partly done",
+			participants: map[string]*Participant{
+				"a": {Progress: String(ProgressCompleted)},
+				"b": {Progress: String(ProgressInProcess), PercentComplete: Int(50)},
+			},
+			wantProgress: ProgressInProcess,
+			wantPercent:  75,
+		},
+		{
+			name: "all done but the cancelled",
+			participants: map[string]*Participant{
+				"a": {Progress: String(ProgressCompleted), PercentComplete: Int(100)},
+				"b": {Progress: String(ProgressCancelled)},
+			},
+			wantProgress: ProgressCompleted,
+			wantPercent:  100,
+		},
+		{
+			name: "one failed",
+			participants: map[string]*Participant{
+				"a": {Progress: String(ProgressCompleted)},
+				"b": {Progress: String(ProgressFailed), PercentComplete: Int(20)},
+			},
+			wantProgress: ProgressFailed,
+			wantPercent:  60,
+		},
+		{
+			name:         "all cancelled",
+			participants: map[string]*Participant{"a": {Progress: String(ProgressCancelled)}},
+			wantProgress: ProgressCancelled,
+			wantPercent:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := NewTask("task-123", "Test Task")
+			task.Progress = String(ProgressInProcess)
+			task.PercentComplete = Int(10)
+			task.Participants = tt.participants
+
+			progress, percent := task.OverallProgress()
+			if progress != tt.wantProgress || percent != tt.wantPercent {
+				t.Errorf("Expected %s at %d%%, got %s at %d%%", tt.wantProgress, tt.wantPercent, progress, percent)
+			}
+		})
+	}
+}
+
 func TestTaskGetEstimatedDuration(t *testing.T) {
 	task := NewTask("task-123", "Test Task")
 
@@ -0,0 +1,256 @@
+package jscal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// taskSearchYears is how far ahead NextOccurrence looks for an open
+// instance before giving up
+const taskSearchYears = 100
+
+// Completion is a completed instance of a recurring task, as recorded by
+// CompleteOccurrence
+type Completion struct {
+	RecurrenceId LocalDateTime
+	At           *time.Time // progressUpdated of the instance, nil if not recorded
+}
+
+// recurrenceAnchor returns the date-time a task's recurrence rules expand
+// from: its start, or its due if it has none (RFC 8984 Section 4.3.1)
+func (t *Task) recurrenceAnchor() (LocalDateTime, error) {
+	switch {
+	case t.Start != nil:
+		return *t.Start, nil
+	case t.Due != nil:
+		return *t.Due, nil
+	}
+	return LocalDateTime{}, fmt.Errorf("task %s has neither start nor due", t.UID)
+}
+
+// Occurrence returns the instance of the task with the given recurrence id:
+// a copy without recurrence properties, with recurrenceId set to id, start
+// and due moved by the distance from the anchor to id, and the matching
+// recurrenceOverrides patch applied. It doesn't check that the rules
+// produce id.
+func (t *Task) Occurrence(id LocalDateTime) (*Task, error) {
+	anchor, err := t.recurrenceAnchor()
+	if err != nil {
+		return nil, err
+	}
+	var patch map[string]interface{}
+	for key, p := range t.RecurrenceOverrides {
+		if overrideId, err := ParseLocalDateTime(key); err == nil && wallClock(*overrideId).Equal(wallClock(id)) {
+			patch = p
+			break
+		}
+	}
+
+	data, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	var instance map[string]interface{}
+	if err := json.Unmarshal(data, &instance); err != nil {
+		return nil, err
+	}
+	for _, name := range []string{"recurrenceRules", "excludedRecurrenceRules", "recurrenceOverrides"} {
+		delete(instance, name)
+	}
+
+	offset := wallClock(id).Sub(wallClock(anchor))
+	instance["recurrenceId"] = LocalDateTime(wallClock(id)).String()
+	if t.Start != nil {
+		instance["start"] = t.Start.Add(offset).String()
+	}
+	if t.Due != nil {
+		instance["due"] = t.Due.Add(offset).String()
+	}
+	if err := applyPatch(instance, patch); err != nil {
+		return nil, fmt.Errorf("task %s: recurrenceOverrides[%s]: %w", t.UID, id, err)
+	}
+
+	data, err = json.Marshal(instance)
+	if err != nil {
+		return nil, err
+	}
+	var task Task
+	if err := json.Unmarshal(data, &task); err != nil {
+		return nil, fmt.Errorf("task %s: recurrenceOverrides[%s]: %w", t.UID, id, err)
+	}
+	return &task, nil
+}
+
+// isOpen reports whether a task instance still needs doing
+func (t *Task) isOpen() bool {
+	switch t.GetProgress() {
+	case ProgressCompleted, ProgressCancelled, ProgressFailed:
+		return false
+	}
+	return true
+}
+
+// NextOccurrence returns the first instance of the task with a recurrence
+// id after the given time that is still open, i.e. not completed, failed
+// or cancelled, or nil if there is none. A task without recurrence rules
+// has a single instance, the task itself.
+func (t *Task) NextOccurrence(after LocalDateTime) (*Task, error) {
+	return t.nextOpen(wallClock(after), false)
+}
+
+// nextOpen returns the first open instance with a recurrence id after
+// from, or at it if inclusive
+func (t *Task) nextOpen(from time.Time, inclusive bool) (*Task, error) {
+	anchor, err := t.recurrenceAnchor()
+	if err != nil {
+		return nil, err
+	}
+	if len(t.RecurrenceRules) == 0 {
+		first := wallClock(anchor)
+		if t.isOpen() && (first.After(from) || (inclusive && first.Equal(from))) {
+			return t, nil
+		}
+		return nil, nil
+	}
+
+	// Search a year ahead, doubling the window, so that infinite rules end
+	base := from
+	if first := wallClock(anchor); first.After(base) {
+		base = first
+	}
+	for years := 1; ; years *= 2 {
+		window := base.AddDate(years, 0, 0)
+		ids, _, err := recurrenceSet(context.Background(), anchor, t.RecurrenceRules, t.ExcludedRecurrenceRules, t.RecurrenceOverrides, LocalDateTime(window))
+		if err != nil {
+			return nil, fmt.Errorf("task %s: %w", t.UID, err)
+		}
+		for _, id := range ids {
+			if wallClock(id).Before(from) || (!inclusive && wallClock(id).Equal(from)) {
+				continue
+			}
+			instance, err := t.Occurrence(id)
+			if err != nil {
+				return nil, err
+			}
+			if instance.isOpen() {
+				return instance, nil
+			}
+		}
+		if years >= taskSearchYears {
+			return nil, nil
+		}
+	}
+}
+
+// CompleteOccurrence completes the current instance of a recurring task,
+// the first open one from its anchor on, as to-do apps do when a
+// repeating item is checked off: the completion is recorded in
+// recurrenceOverrides, with progressUpdated set to at, and the task's
+// start and due move on to the next open instance. Rules with a count are
+// rewritten with the equivalent until first, so moving doesn't extend
+// them. Once no instance is left the task itself is completed.
+//
+// A task without recurrence rules is simply completed. The completed
+// instance is returned.
+func (t *Task) CompleteOccurrence(at time.Time) (*Task, error) {
+	at = at.UTC()
+	if len(t.RecurrenceRules) == 0 {
+		t.Progress = String(ProgressCompleted)
+		t.ProgressUpdated = NewUTCDateTime(at)
+		t.Touch()
+		return t, nil
+	}
+
+	anchor, err := t.recurrenceAnchor()
+	if err != nil {
+		return nil, err
+	}
+	current, err := t.nextOpen(wallClock(anchor), true)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, fmt.Errorf("task %s has no open instance", t.UID)
+	}
+	id := *current.RecurrenceId
+
+	// Record the completion, keeping other changes to the instance
+	key := id.String()
+	if t.RecurrenceOverrides == nil {
+		t.RecurrenceOverrides = make(map[string]map[string]interface{})
+	}
+	patch := t.RecurrenceOverrides[key]
+	if patch == nil {
+		patch = make(map[string]interface{})
+		t.RecurrenceOverrides[key] = patch
+	}
+	patch["progress"] = ProgressCompleted
+	patch["progressUpdated"] = NewUTCDateTime(at).String()
+	current.Progress = String(ProgressCompleted)
+	current.ProgressUpdated = NewUTCDateTime(at)
+
+	next, err := t.nextOpen(wallClock(id), false)
+	if err != nil {
+		return nil, err
+	}
+	if next == nil {
+		t.Progress = String(ProgressCompleted)
+		t.ProgressUpdated = NewUTCDateTime(at)
+	} else {
+		if err := t.countsToUntil(anchor); err != nil {
+			return nil, err
+		}
+		offset := wallClock(*next.RecurrenceId).Sub(wallClock(anchor))
+		if t.Start != nil {
+			start := t.Start.Add(offset)
+			t.Start = &start
+		}
+		if t.Due != nil {
+			due := t.Due.Add(offset)
+			t.Due = &due
+		}
+	}
+	t.Touch()
+	return current, nil
+}
+
+// countsToUntil replaces the count of the task's rules with the until of
+// their last instance from anchor, so that the anchor can move
+func (t *Task) countsToUntil(anchor LocalDateTime) error {
+	if err := countsToUntil(t.RecurrenceRules, anchor, true); err != nil {
+		return fmt.Errorf("task %s: %w", t.UID, err)
+	}
+	if err := countsToUntil(t.ExcludedRecurrenceRules, anchor, false); err != nil {
+		return fmt.Errorf("task %s: %w", t.UID, err)
+	}
+	return nil
+}
+
+// CompletionHistory returns the completed instances of a recurring task,
+// oldest first
+func (t *Task) CompletionHistory() []Completion {
+	var history []Completion
+	for key, patch := range t.RecurrenceOverrides {
+		if progress, _ := patch["progress"].(string); progress != ProgressCompleted {
+			continue
+		}
+		id, err := ParseLocalDateTime(key)
+		if err != nil {
+			continue
+		}
+		completion := Completion{RecurrenceId: *id}
+		if s, ok := patch["progressUpdated"].(string); ok {
+			if at, err := time.Parse(time.RFC3339, s); err == nil {
+				completion.At = &at
+			}
+		}
+		history = append(history, completion)
+	}
+	sort.Slice(history, func(i, j int) bool {
+		return wallClock(history[i].RecurrenceId).Before(wallClock(history[j].RecurrenceId))
+	})
+	return history
+}
@@ -0,0 +1,103 @@
+package jscal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTaskNextOccurrence(t *testing.T) {
+	task := NewTask("bins", "Take out the bins")
+	task.Due = NewLocalDateTime(time.Date(2025, 3, 3, 20, 0, 0, 0, time.UTC))
+	task.RecurrenceRules = []RecurrenceRule{*NewRecurrenceRule("weekly")}
+	task.RecurrenceOverrides = map[string]map[string]interface{}{
+		"2025-03-10T20:00:00": {"progress": ProgressCompleted},
+		"2025-03-17T20:00:00": {"excluded": true},
+	}
+
+	tests := []struct {
+		after string
+		want  string
+	}{
+		{"2025-03-01T00:00:00", "2025-03-03T20:00:00"},
+		{"2025-03-03T20:00:00", "2025-03-24T20:00:00"},
+	}
+	for _, tt := range tests {
+		after, _ := ParseLocalDateTime(tt.after)
+		next, err := task.NextOccurrence(*after)
+		if err != nil {
+			t.Fatalf("NextOccurrence(%s) failed: %v", tt.after, err)
+		}
+		if next == nil || next.Due.String() != tt.want || next.RecurrenceId.String() != tt.want {
+			t.Errorf("NextOccurrence(%s): expected %s, got %+v", tt.after, tt.want, next)
+		}
+		if next != nil && len(next.RecurrenceRules) != 0 {
+			t.Error("Expected an instance without recurrence rules")
+		}
+	}
+
+	count := 2
+	task.RecurrenceRules[0].Count = &count
+	after, _ := ParseLocalDateTime("2025-03-12T00:00:00")
+	if next, err := task.NextOccurrence(*after); err != nil || next != nil {
+		t.Errorf("Expected no instance after the last, got %+v and %v", next, err)
+	}
+}
+
+func TestTaskCompleteOccurrence(t *testing.T) {
+	task := NewTask("bins", "Take out the bins")
+	task.Due = NewLocalDateTime(time.Date(2025, 3, 3, 20, 0, 0, 0, time.UTC))
+	task.RecurrenceRules = []RecurrenceRule{*NewRecurrenceRule("weekly")}
+	task.Start = NewLocalDateTime(time.Date(2025, 3, 3, 18, 0, 0, 0, time.UTC))
+	count := 3
+	task.RecurrenceRules[0].Count = &count
+	done := time.Date(2025, 3, 3, 19, 30, 0, 0, time.UTC)
+
+	completed, err := task.CompleteOccurrence(done)
+	if err != nil {
+		t.Fatalf("CompleteOccurrence failed: %v", err)
+	}
+	if completed.RecurrenceId.String() != "2025-03-03T18:00:00" || !completed.IsCompleted() {
+		t.Errorf("Expected the first instance completed, got %+v", completed)
+	}
+	if task.Start.String() != "2025-03-10T18:00:00" || task.Due.String() != "2025-03-10T20:00:00" {
+		t.Errorf("Expected start and due to advance a week, got %s and %s", task.Start, task.Due)
+	}
+	rule := task.RecurrenceRules[0]
+	if rule.Count != nil || rule.Until == nil || rule.Until.String() != "2025-03-17T18:00:00" {
+		t.Errorf("Expected count rewritten as until 2025-03-17T18:00:00, got %+v", rule)
+	}
+	if task.IsCompleted() {
+		t.Error("Expected the series to stay open")
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := task.CompleteOccurrence(done.AddDate(0, 0, 7*(i+1))); err != nil {
+			t.Fatalf("CompleteOccurrence failed: %v", err)
+		}
+	}
+	if !task.IsCompleted() {
+		t.Error("Expected the series completed after its last instance")
+	}
+	if _, err := task.CompleteOccurrence(done); err == nil {
+		t.Error("Expected an error completing a finished series")
+	}
+
+	history := task.CompletionHistory()
+	if len(history) != 3 {
+		t.Fatalf("Expected 3 completions, got %d", len(history))
+	}
+	if history[0].RecurrenceId.String() != "2025-03-03T18:00:00" || history[2].RecurrenceId.String() != "2025-03-17T18:00:00" {
+		t.Errorf("Expected completions in order, got %v", history)
+	}
+	if history[0].At == nil || !history[0].At.Equal(done) {
+		t.Errorf("Expected completion time %s, got %v", done, history[0].At)
+	}
+}
+
+func TestTaskCompleteOccurrenceSingle(t *testing.T) {
+	task := NewTask("once", "File taxes")
+	task.Due = NewLocalDateTime(time.Date(2025, 4, 15, 0, 0, 0, 0, time.UTC))
+	if _, err := task.CompleteOccurrence(time.Now()); err != nil || !task.IsCompleted() {
+		t.Errorf("Expected the task completed, got %v", err)
+	}
+}
@@ -0,0 +1,367 @@
+// Package tasks orders JSCalendar tasks by the dependencies expressed in
+// their relatedTo property and computes the critical path of a project from
+// their estimated durations.
+//
+// A task depends on the tasks that must be finished before it can start:
+//
+//   - its children (subtasks finish before their parent), whether the
+//     parent names them as children or they name the parent as parent
+//   - the tasks that name it as "next"
+//
+// Relations to tasks outside the given set are ignored.
+//
+// Basic usage:
+//
+//	plan, err := tasks.Schedule(project.GetTasks())
+//	for _, t := range plan.CriticalPath {
+//		fmt.Println(*t.Title, plan.EarliestStart[t.UID])
+//	}
+package tasks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/airtrafik/jscal"
+)
+
+// CycleError is returned when tasks depend on each other in a cycle
+type CycleError struct {
+	UIDs []string // Tasks in the cycle, in dependency order
+}
+
+func (e *CycleError) Error() string {
+	if len(e.UIDs) == 0 {
+		return "dependency cycle"
+	}
+	path := append(append([]string{}, e.UIDs...), e.UIDs[0])
+	return "dependency cycle: " + strings.Join(path, " -> ")
+}
+
+// Plan is the result of Schedule. Times are offsets from the start of the
+// project.
+type Plan struct {
+	Order          []*jscal.Task            // Tasks in dependency order
+	EarliestStart  map[string]time.Duration // Earliest start by UID
+	EarliestFinish map[string]time.Duration // Earliest finish by UID
+	CriticalPath   []*jscal.Task            // Longest chain of dependent tasks
+	Duration       time.Duration            // Earliest finish of the whole project
+}
+
+// graph holds the dependencies between tasks by index
+type graph struct {
+	tasks []*jscal.Task
+	preds [][]int // tasks that must finish first
+	succs [][]int // tasks waiting on this one
+}
+
+// newGraph builds the dependency graph of a set of tasks
+func newGraph(tasks []*jscal.Task) (*graph, error) {
+	g := &graph{
+		tasks: tasks,
+		preds: make([][]int, len(tasks)),
+		succs: make([][]int, len(tasks)),
+	}
+
+	index := make(map[string]int, len(tasks))
+	for i, t := range tasks {
+		if t == nil {
+			return nil, fmt.Errorf("task %d is nil", i)
+		}
+		if _, dup := index[t.UID]; dup {
+			return nil, fmt.Errorf("duplicate task UID %s", t.UID)
+		}
+		index[t.UID] = i
+	}
+
+	seen := make(map[[2]int]bool)
+	addEdge := func(before, after int) {
+		if before == after || seen[[2]int{before, after}] {
+			return
+		}
+		seen[[2]int{before, after}] = true
+		g.preds[after] = append(g.preds[after], before)
+		g.succs[before] = append(g.succs[before], after)
+	}
+
+	for i, t := range tasks {
+		uids := make([]string, 0, len(t.RelatedTo))
+		for uid := range t.RelatedTo {
+			uids = append(uids, uid)
+		}
+		sort.Strings(uids)
+
+		for _, uid := range uids {
+			j, ok := index[uid]
+			relation := t.RelatedTo[uid]
+			if !ok || relation == nil {
+				continue
+			}
+			if relation.Relation[jscal.RelationTypeParent] {
+				addEdge(i, j)
+			}
+			if relation.Relation[jscal.RelationTypeChild] {
+				addEdge(j, i)
+			}
+			if relation.Relation[jscal.RelationTypeNext] {
+				addEdge(i, j)
+			}
+		}
+	}
+	return g, nil
+}
+
+// order returns task indexes in dependency order. Tasks that are ready at
+// the same time keep their input order.
+func (g *graph) order() ([]int, error) {
+	inDegree := make([]int, len(g.tasks))
+	var ready []int
+	for i := range g.tasks {
+		inDegree[i] = len(g.preds[i])
+		if inDegree[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	order := make([]int, 0, len(g.tasks))
+	for len(ready) > 0 {
+		next := ready[0]
+		ready = ready[1:]
+		order = append(order, next)
+
+		for _, s := range g.succs[next] {
+			inDegree[s]--
+			if inDegree[s] == 0 {
+				ready = append(ready, s)
+			}
+		}
+		sort.Ints(ready)
+	}
+
+	if len(order) < len(g.tasks) {
+		return nil, g.cycle(inDegree)
+	}
+	return order, nil
+}
+
+// cycle finds a dependency cycle among the tasks order couldn't place
+func (g *graph) cycle(inDegree []int) *CycleError {
+	const (
+		unvisited = iota
+		active
+		finished
+	)
+	state := make([]int, len(g.tasks))
+	var stack []int
+
+	var visit func(i int) []int
+	visit = func(i int) []int {
+		state[i] = active
+		stack = append(stack, i)
+		for _, s := range g.succs[i] {
+			switch state[s] {
+			case active:
+				for k, v := range stack {
+					if v == s {
+						return stack[k:]
+					}
+				}
+			case unvisited:
+				if found := visit(s); found != nil {
+					return found
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[i] = finished
+		return nil
+	}
+
+	for i := range g.tasks {
+		if inDegree[i] > 0 && state[i] == unvisited {
+			if found := visit(i); found != nil {
+				err := &CycleError{}
+				for _, v := range found {
+					err.UIDs = append(err.UIDs, g.tasks[v].UID)
+				}
+				return err
+			}
+		}
+	}
+	return &CycleError{}
+}
+
+// Order returns the tasks sorted so that every task comes after the tasks it
+// depends on. Independent tasks keep their input order. A *CycleError is
+// returned if the dependencies contain a cycle.
+func Order(tasks []*jscal.Task) ([]*jscal.Task, error) {
+	g, err := newGraph(tasks)
+	if err != nil {
+		return nil, err
+	}
+	order, err := g.order()
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := make([]*jscal.Task, len(order))
+	for i, idx := range order {
+		sorted[i] = tasks[idx]
+	}
+	return sorted, nil
+}
+
+// Schedule computes the earliest start and finish of each task, assuming
+// the project starts at offset zero and every task takes its
+// estimatedDuration (zero if unset), along with the critical path: the
+// chain of dependent tasks that determines the length of the project.
+func Schedule(tasks []*jscal.Task) (*Plan, error) {
+	g, err := newGraph(tasks)
+	if err != nil {
+		return nil, err
+	}
+	order, err := g.order()
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{
+		Order:          make([]*jscal.Task, 0, len(order)),
+		EarliestStart:  make(map[string]time.Duration, len(order)),
+		EarliestFinish: make(map[string]time.Duration, len(order)),
+	}
+
+	finish := make([]time.Duration, len(tasks))
+	last := -1
+	for _, i := range order {
+		t := tasks[i]
+		duration, err := estimatedDuration(t)
+		if err != nil {
+			return nil, err
+		}
+
+		var start time.Duration
+		for _, p := range g.preds[i] {
+			if finish[p] > start {
+				start = finish[p]
+			}
+		}
+		finish[i] = start + duration
+
+		plan.Order = append(plan.Order, t)
+		plan.EarliestStart[t.UID] = start
+		plan.EarliestFinish[t.UID] = finish[i]
+		if last < 0 || finish[i] > finish[last] {
+			last = i
+		}
+	}
+
+	// Walk back from the task that finishes last through the predecessors
+	// that finish latest
+	for i := last; i >= 0; {
+		plan.CriticalPath = append([]*jscal.Task{tasks[i]}, plan.CriticalPath...)
+		next := -1
+		for _, p := range g.preds[i] {
+			if next < 0 || finish[p] > finish[next] {
+				next = p
+			}
+		}
+		i = next
+	}
+	if last >= 0 {
+		plan.Duration = finish[last]
+	}
+	return plan, nil
+}
+
+// Timeline is a Plan laid out on a business calendar, see Plan.OnCalendar
+type Timeline struct {
+	Start  map[string]time.Time // Earliest start by UID
+	Finish map[string]time.Time // Earliest finish by UID
+	Late   []*jscal.Task        // Tasks finishing after their due, in dependency order
+	End    time.Time            // Earliest finish of the whole project
+}
+
+// OnCalendar lays the plan out on a business calendar, with the project
+// starting at start and tasks only progressing during working hours. Tasks
+// whose due comes before their earliest finish are reported as late; dues
+// without a timeZone are read in the calendar's time zone.
+func (p *Plan) OnCalendar(start time.Time, cal *jscal.BusinessCalendar) (*Timeline, error) {
+	timeline := &Timeline{
+		Start:  make(map[string]time.Time, len(p.Order)),
+		Finish: make(map[string]time.Time, len(p.Order)),
+	}
+
+	// Offsets are working time, so they map to the calendar one to one
+	at := func(offset time.Duration) (time.Time, error) {
+		t, ok := cal.AddWorkingTime(start, offset)
+		if !ok {
+			return time.Time{}, fmt.Errorf("business calendar has no working time for an offset of %v", offset)
+		}
+		return t, nil
+	}
+	projectEnd, err := at(p.Duration)
+	if err != nil {
+		return nil, err
+	}
+	timeline.End = projectEnd
+
+	for _, t := range p.Order {
+		finish, err := at(p.EarliestFinish[t.UID])
+		if err != nil {
+			return nil, err
+		}
+		// A task starts once work on it can, not when its predecessor
+		// finished at the end of a working day
+		begin, err := at(p.EarliestStart[t.UID])
+		if err != nil {
+			return nil, err
+		}
+		begin, ok := cal.NextWorkingSlot(begin, 0)
+		if !ok {
+			return nil, fmt.Errorf("task %s: business calendar has no working time", t.UID)
+		}
+		if finish.Before(begin) {
+			finish = begin
+		}
+		timeline.Start[t.UID] = begin
+		timeline.Finish[t.UID] = finish
+
+		if t.Due != nil && finish.After(dueTime(t, cal)) {
+			timeline.Late = append(timeline.Late, t)
+		}
+	}
+	return timeline, nil
+}
+
+// dueTime returns the due of a task in its time zone, or in the calendar's
+// for floating dues and unknown zones
+func dueTime(t *jscal.Task, cal *jscal.BusinessCalendar) time.Time {
+	loc := cal.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	if t.TimeZone != nil && *t.TimeZone != "" {
+		if zone, err := time.LoadLocation(*t.TimeZone); err == nil {
+			loc = zone
+		}
+	}
+	return t.Due.In(loc)
+}
+
+// estimatedDuration returns the estimated duration of a task, or zero
+func estimatedDuration(t *jscal.Task) (time.Duration, error) {
+	if t.EstimatedDuration == nil || *t.EstimatedDuration == "" {
+		return 0, nil
+	}
+	d, err := jscal.ParseDuration(*t.EstimatedDuration)
+	if err != nil {
+		return 0, fmt.Errorf("task %s: invalid estimatedDuration: %w", t.UID, err)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("task %s: estimatedDuration cannot be negative", t.UID)
+	}
+	return d, nil
+}
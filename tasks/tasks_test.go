@@ -0,0 +1,193 @@
+package tasks
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/airtrafik/jscal"
+)
+
+func newTask(uid, estimate string) *jscal.Task {
+	task := jscal.NewTask(uid, uid)
+	if estimate != "" {
+		task.EstimatedDuration = jscal.String(estimate)
+	}
+	return task
+}
+
+func uids(tasks []*jscal.Task) string {
+	names := make([]string, len(tasks))
+	for i, t := range tasks {
+		names[i] = t.UID
+	}
+	return strings.Join(names, ",")
+}
+
+// newProject returns a release with two subtasks and a next chain:
+//
+//	design -> build -> test (subtasks of release), docs (subtask of release)
+func newProject() []*jscal.Task {
+	release := newTask("release", "PT1H")
+	release.AddRelation("docs", jscal.RelationTypeChild)
+
+	design := newTask("design", "P2D")
+	design.AddRelation("build", jscal.RelationTypeNext)
+	build := newTask("build", "P3D")
+	build.AddRelation("test", jscal.RelationTypeNext)
+	build.AddRelation("release", jscal.RelationTypeParent)
+	test := newTask("test", "P1D")
+	test.AddRelation("release", jscal.RelationTypeParent)
+	docs := newTask("docs", "P4D")
+
+	return []*jscal.Task{release, test, docs, build, design}
+}
+
+func TestOrder(t *testing.T) {
+	ordered, err := Order(newProject())
+	if err != nil {
+		t.Fatalf("Order failed: %v", err)
+	}
+	if got := uids(ordered); got != "docs,design,build,test,release" {
+		t.Errorf("Expected docs,design,build,test,release, got %s", got)
+	}
+
+	independent := []*jscal.Task{newTask("b", ""), newTask("a", ""), newTask("c", "")}
+	independent[0].AddRelation("elsewhere", jscal.RelationTypeParent)
+	ordered, err = Order(independent)
+	if err != nil || uids(ordered) != "b,a,c" {
+		t.Errorf("Expected input order b,a,c, got %s (%v)", uids(ordered), err)
+	}
+}
+
+func TestOrderCycle(t *testing.T) {
+	a, b, c := newTask("a", ""), newTask("b", ""), newTask("c", "")
+	a.AddRelation("b", jscal.RelationTypeNext)
+	b.AddRelation("c", jscal.RelationTypeNext)
+	c.AddRelation("a", jscal.RelationTypeNext)
+
+	_, err := Order([]*jscal.Task{newTask("free", ""), a, b, c})
+	var cycle *CycleError
+	if !errors.As(err, &cycle) {
+		t.Fatalf("Expected CycleError, got %v", err)
+	}
+	if got := strings.Join(cycle.UIDs, ","); got != "a,b,c" {
+		t.Errorf("Expected cycle a,b,c, got %s", got)
+	}
+	if err.Error() != "dependency cycle: a -> b -> c -> a" {
+		t.Errorf("Unexpected message %q", err.Error())
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	plan, err := Schedule(newProject())
+	if err != nil {
+		t.Fatalf("Schedule failed: %v", err)
+	}
+
+	day := 24 * time.Hour
+	starts := map[string]time.Duration{
+		"docs":    0,
+		"design":  0,
+		"build":   2 * day,
+		"test":    5 * day,
+		"release": 6 * day,
+	}
+	for uid, want := range starts {
+		if got := plan.EarliestStart[uid]; got != want {
+			t.Errorf("Expected %s to start at %s, got %s", uid, want, got)
+		}
+	}
+	if got := plan.EarliestFinish["docs"]; got != 4*day {
+		t.Errorf("Expected docs to finish at %s, got %s", 4*day, got)
+	}
+	if plan.Duration != 6*day+time.Hour {
+		t.Errorf("Expected project duration %s, got %s", 6*day+time.Hour, plan.Duration)
+	}
+	if got := uids(plan.CriticalPath); got != "design,build,test,release" {
+		t.Errorf("Expected critical path design,build,test,release, got %s", got)
+	}
+	if got := uids(plan.Order); got != "docs,design,build,test,release" {
+		t.Errorf("Unexpected order %s", got)
+	}
+
+	empty, err := Schedule(nil)
+	if err != nil || len(empty.CriticalPath) != 0 || empty.Duration != 0 {
+		t.Errorf("Expected empty plan, got %+v (%v)", empty, err)
+	}
+}
+
+func TestScheduleErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		tasks   []*jscal.Task
+		wantErr string
+	}{
+		{name: "invalid duration", tasks: []*jscal.Task{newTask("a", "soon")}, wantErr: "invalid estimatedDuration"},
+		{name: "negative duration", tasks: []*jscal.Task{newTask("a", "-PT1H")}, wantErr: "cannot be negative"},
+		{name: "duplicate uid", tasks: []*jscal.Task{newTask("a", ""), newTask("a", "")}, wantErr: "duplicate task UID"},
+		{name: "nil task", tasks: []*jscal.Task{nil}, wantErr: "is nil"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Schedule(tt.tasks)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestPlanOnCalendar(t *testing.T) {
+	design := newTask("design", "PT8H")
+	design.AddRelation("build", jscal.RelationTypeNext)
+	build := newTask("build", "PT16H")
+	build.AddRelation("test", jscal.RelationTypeNext)
+	test := newTask("test", "PT4H")
+	test.Due = jscal.NewLocalDateTime(time.Date(2025, 3, 6, 12, 0, 0, 0, time.UTC))
+	docs := newTask("docs", "PT2H")
+	docs.Due = jscal.NewLocalDateTime(time.Date(2025, 3, 3, 12, 0, 0, 0, time.UTC))
+
+	plan, err := Schedule([]*jscal.Task{design, build, test, docs})
+	if err != nil {
+		t.Fatalf("Schedule failed: %v", err)
+	}
+
+	// Monday 9:00, working Monday to Friday 9:00 to 17:00
+	monday := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	timeline, err := plan.OnCalendar(monday, jscal.NewBusinessCalendar(time.UTC))
+	if err != nil {
+		t.Fatalf("OnCalendar failed: %v", err)
+	}
+
+	at := func(day, hour int) time.Time { return time.Date(2025, 3, day, hour, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		uid           string
+		start, finish time.Time
+	}{
+		{"design", at(3, 9), at(3, 17)},
+		{"build", at(4, 9), at(5, 17)}, // Not Monday 17:00
+		{"test", at(6, 9), at(6, 13)},
+		{"docs", at(3, 9), at(3, 11)},
+	}
+	for _, tt := range tests {
+		if got := timeline.Start[tt.uid]; !got.Equal(tt.start) {
+			t.Errorf("Expected %s to start at %v, got %v", tt.uid, tt.start, got)
+		}
+		if got := timeline.Finish[tt.uid]; !got.Equal(tt.finish) {
+			t.Errorf("Expected %s to finish at %v, got %v", tt.uid, tt.finish, got)
+		}
+	}
+	if !timeline.End.Equal(at(6, 13)) {
+		t.Errorf("Expected the project to end at %v, got %v", at(6, 13), timeline.End)
+	}
+	if got := uids(timeline.Late); got != "test" {
+		t.Errorf("Expected test to be late, got %s", got)
+	}
+
+	if _, err := plan.OnCalendar(monday, &jscal.BusinessCalendar{}); err == nil {
+		t.Errorf("Expected error for a calendar without working hours")
+	}
+}
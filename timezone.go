@@ -1,7 +1,5 @@
 package jscal
 
-import "time"
-
 // TimeZone represents a custom timezone definition according to RFC 8984 Section 4.7.2
 type TimeZone struct {
 	// Type identifier
@@ -11,13 +9,13 @@ type TimeZone struct {
 	TzId string `json:"tzId"`
 
 	// Last modified time of the timezone definition
-	Updated *time.Time `json:"updated,omitempty"`
+	Updated *UTCDateTime `json:"updated,omitempty"`
 
 	// URL to the timezone definition
 	URL *string `json:"url,omitempty"`
 
 	// Validity period start
-	ValidUntil *time.Time `json:"validUntil,omitempty"`
+	ValidUntil *UTCDateTime `json:"validUntil,omitempty"`
 
 	// Aliases for this timezone
 	Aliases []string `json:"aliases,omitempty"`
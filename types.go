@@ -1,6 +1,7 @@
 package jscal
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -25,13 +26,23 @@ type Participant struct {
 	ScheduleForceSend    *bool             `json:"scheduleForceSend,omitempty"`
 	ScheduleSequence     *int              `json:"scheduleSequence,omitempty"`
 	ScheduleStatus       []string          `json:"scheduleStatus,omitempty"`
-	ScheduleUpdated      *time.Time        `json:"scheduleUpdated,omitempty"`
+	ScheduleUpdated      *UTCDateTime      `json:"scheduleUpdated,omitempty"`
 	SentBy               *string           `json:"sentBy,omitempty"` // Email address of sender
 	InvitedBy            *string           `json:"invitedBy,omitempty"`
 	DelegatedTo          map[string]bool   `json:"delegatedTo,omitempty"`
 	DelegatedFrom        map[string]bool   `json:"delegatedFrom,omitempty"`
 	MemberOf             map[string]bool   `json:"memberOf,omitempty"`
 	Links                map[string]*Link  `json:"links,omitempty"`
+
+	// Task participants only (RFC 8984 Section 5.2.4-5.2.6)
+	Progress        *string      `json:"progress,omitempty"` // needs-action, in-process, completed, failed, cancelled
+	PercentComplete *int         `json:"percentComplete,omitempty"`
+	ProgressUpdated *UTCDateTime `json:"progressUpdated,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler without reflection over the fields
+func (p Participant) MarshalJSON() ([]byte, error) {
+	return p.appendJSON(nil)
 }
 
 // Location represents a physical or virtual location
@@ -43,6 +54,7 @@ type Location struct {
 	RelativeTo    *string          `json:"relativeTo,omitempty"`
 	TimeZone      *string          `json:"timeZone,omitempty"`
 	Coordinates   *string          `json:"coordinates,omitempty"` // geo: URI
+	Address       *Address         `json:"address,omitempty"`     // Postal address (extension)
 	Links         map[string]*Link `json:"links,omitempty"`
 	Rel           *string          `json:"rel,omitempty"` // start, end
 	Title         *string          `json:"title,omitempty"`
@@ -99,12 +111,78 @@ type NDay struct {
 // Alert represents a notification/reminder
 type Alert struct {
 	Type         string               `json:"@type"`
-	Trigger      *OffsetTrigger       `json:"trigger,omitempty"`
-	Acknowledged *time.Time           `json:"acknowledged,omitempty"`
+	Trigger      Trigger              `json:"trigger,omitempty"` // *OffsetTrigger, *AbsoluteTrigger or *UnknownTrigger
+	Acknowledged *UTCDateTime         `json:"acknowledged,omitempty"`
 	RelatedTo    map[string]*Relation `json:"relatedTo,omitempty"`
 	Action       *string              `json:"action,omitempty"` // display, email
 }
 
+// UnmarshalJSON implements custom JSON unmarshaling for Alert to handle the
+// polymorphic trigger
+func (a *Alert) UnmarshalJSON(data []byte) error {
+	type Alias Alert
+	aux := &struct {
+		Trigger json.RawMessage `json:"trigger,omitempty"`
+		*Alias
+	}{
+		Alias: (*Alias)(a),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	a.Trigger = nil
+	if len(aux.Trigger) == 0 || string(aux.Trigger) == "null" {
+		return nil
+	}
+	trigger, err := decodeTrigger(aux.Trigger)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal trigger: %w", err)
+	}
+	a.Trigger = trigger
+	return nil
+}
+
+// DefaultAlertsProvider supplies the alerts of objects with useDefaultAlerts
+// set (RFC 8984 Section 4.5.1), such as a calendar's default reminders
+type DefaultAlertsProvider interface {
+	DefaultAlerts(obj CalendarObject) map[string]*Alert
+}
+
+// DefaultAlertsFunc adapts a function to a DefaultAlertsProvider
+type DefaultAlertsFunc func(obj CalendarObject) map[string]*Alert
+
+// DefaultAlerts calls f(obj)
+func (f DefaultAlertsFunc) DefaultAlerts(obj CalendarObject) map[string]*Alert {
+	return f(obj)
+}
+
+// StaticDefaultAlerts is a DefaultAlertsProvider with the same alerts for
+// every object
+type StaticDefaultAlerts map[string]*Alert
+
+// DefaultAlerts returns the alerts
+func (s StaticDefaultAlerts) DefaultAlerts(CalendarObject) map[string]*Alert {
+	return s
+}
+
+// effectiveAlerts resolves the alerts of obj, see Event.EffectiveAlerts
+func effectiveAlerts(obj CalendarObject, alerts map[string]*Alert, useDefaults *bool, provider DefaultAlertsProvider) map[string]*Alert {
+	if useDefaults == nil || !*useDefaults {
+		return alerts
+	}
+	if provider == nil {
+		return nil
+	}
+	return provider.DefaultAlerts(obj)
+}
+
+// Trigger defines when an Alert fires (RFC 8984 Section 4.5.2). It is one of
+// *OffsetTrigger, *AbsoluteTrigger or, for an unrecognized @type, *UnknownTrigger.
+type Trigger interface {
+	GetType() string
+}
+
 // OffsetTrigger represents when an alert should fire
 type OffsetTrigger struct {
 	Type       string  `json:"@type"`
@@ -112,19 +190,92 @@ type OffsetTrigger struct {
 	RelativeTo *string `json:"relativeTo,omitempty"` // start, end
 }
 
+// GetType returns the trigger's type (implements Trigger)
+func (t *OffsetTrigger) GetType() string {
+	return t.Type
+}
+
+// AbsoluteTrigger fires an alert at a fixed point in time
+type AbsoluteTrigger struct {
+	Type string      `json:"@type"` // Always "AbsoluteTrigger"
+	When UTCDateTime `json:"when"`
+}
+
+// GetType returns the trigger's type (implements Trigger)
+func (t *AbsoluteTrigger) GetType() string {
+	return t.Type
+}
+
+// UnknownTrigger preserves a trigger with an unrecognized @type. RFC 8984
+// requires clients to ignore alerts they don't understand, not to drop them.
+type UnknownTrigger struct {
+	Type       string
+	Properties map[string]interface{} // All properties, including @type
+}
+
+// GetType returns the trigger's type (implements Trigger)
+func (t *UnknownTrigger) GetType() string {
+	return t.Type
+}
+
+// MarshalJSON implements json.Marshaler, writing the preserved properties
+func (t UnknownTrigger) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Properties)
+}
+
+// decodeTrigger unmarshals a trigger based on its @type. Producers often
+// leave @type out; such triggers are absolute if they have a when property
+// and offset triggers otherwise.
+func decodeTrigger(data []byte) (Trigger, error) {
+	var props map[string]interface{}
+	if err := json.Unmarshal(data, &props); err != nil {
+		return nil, err
+	}
+
+	triggerType, _ := props["@type"].(string)
+	if triggerType == "" {
+		if _, ok := props["when"]; ok {
+			triggerType = "AbsoluteTrigger"
+		} else {
+			triggerType = "OffsetTrigger"
+		}
+	}
+
+	switch triggerType {
+	case "OffsetTrigger":
+		var t OffsetTrigger
+		if err := json.Unmarshal(data, &t); err != nil {
+			return nil, err
+		}
+		return &t, nil
+	case "AbsoluteTrigger":
+		var t AbsoluteTrigger
+		if err := json.Unmarshal(data, &t); err != nil {
+			return nil, err
+		}
+		return &t, nil
+	default:
+		return &UnknownTrigger{Type: triggerType, Properties: props}, nil
+	}
+}
+
 // Relation represents relationships to other objects
 type Relation struct {
 	Type     string          `json:"@type"`
 	Relation map[string]bool `json:"relation,omitempty"` // first, next, child, parent
 }
 
-// UTCDateTime represents a UTC date-time
-type UTCDateTime struct {
-	Type    string `json:"@type"`
-	UTCTime string `json:"utcTime"` // ISO 8601 format: YYYY-MM-DDTHH:MM:SSZ
+// Helper functions
+
+// NewOffsetTrigger creates a trigger firing at an offset from the start
+func NewOffsetTrigger(offset string) *OffsetTrigger {
+	return &OffsetTrigger{Type: "OffsetTrigger", Offset: offset}
 }
 
-// Helper functions
+// NewAbsoluteTrigger creates a trigger firing at a fixed time
+func NewAbsoluteTrigger(when time.Time) *AbsoluteTrigger {
+	return &AbsoluteTrigger{Type: "AbsoluteTrigger", When: *NewUTCDateTime(when)}
+}
 
 // NewParticipant creates a new participant with basic info
 func NewParticipant(name, email string) *Participant {
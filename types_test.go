@@ -1,7 +1,9 @@
 package jscal
 
 import (
+	"encoding/json"
 	"testing"
+	"time"
 )
 
 func TestNewLocation(t *testing.T) {
@@ -461,3 +463,132 @@ func TestParticipantWithAllFields(t *testing.T) {
 		t.Error("SentBy not set correctly")
 	}
 }
+
+func TestAlertTriggerJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		wantType string
+		check    func(t *testing.T, trigger Trigger)
+	}{
+		{
+			name:     "offset trigger",
+			data:     `{"@type":"Alert","trigger":{"@type":"OffsetTrigger","offset":"-PT15M","relativeTo":"end"}}`,
+			wantType: "OffsetTrigger",
+			check: func(t *testing.T, trigger Trigger) {
+				if o := trigger.(*OffsetTrigger); o.Offset != "-PT15M" || *o.RelativeTo != RelativeToEnd {
+					t.Errorf("Unexpected offset trigger %+v", o)
+				}
+			},
+		},
+		{
+			name:     "absolute trigger",
+			data:     `{"@type":"Alert","trigger":{"@type":"AbsoluteTrigger","when":"2025-03-01T07:00:00Z"}}`,
+			wantType: "AbsoluteTrigger",
+			check: func(t *testing.T, trigger Trigger) {
+				if a := trigger.(*AbsoluteTrigger); !a.When.Time().Equal(time.Date(2025, 3, 1, 7, 0, 0, 0, time.UTC)) {
+					t.Errorf("Unexpected when %s", a.When)
+				}
+			},
+		},
+		{
+			name:     "absolute trigger without @type",
+			data:     `{"@type":"Alert","trigger":{"when":"2025-03-01T07:00:00Z"}}`,
+			wantType: "",
+			check: func(t *testing.T, trigger Trigger) {
+				if _, ok := trigger.(*AbsoluteTrigger); !ok {
+					t.Errorf("Expected *AbsoluteTrigger, got %T", trigger)
+				}
+			},
+		},
+		{
+			name:     "unknown trigger",
+			data:     `{"@type":"Alert","trigger":{"@type":"LocationTrigger","radius":50}}`,
+			wantType: "LocationTrigger",
+			check: func(t *testing.T, trigger Trigger) {
+				if u := trigger.(*UnknownTrigger); u.Properties["radius"] != float64(50) {
+					t.Errorf("Expected properties to be preserved, got %+v", u.Properties)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var alert Alert
+			if err := json.Unmarshal([]byte(tt.data), &alert); err != nil {
+				t.Fatalf("Failed to unmarshal: %v", err)
+			}
+			if alert.Trigger == nil || alert.Trigger.GetType() != tt.wantType {
+				t.Fatalf("Expected trigger type %q, got %+v", tt.wantType, alert.Trigger)
+			}
+			tt.check(t, alert.Trigger)
+
+			data, err := json.Marshal(&alert)
+			if err != nil {
+				t.Fatalf("Failed to marshal: %v", err)
+			}
+			var roundTrip Alert
+			if err := json.Unmarshal(data, &roundTrip); err != nil {
+				t.Fatalf("Failed to unmarshal round trip %s: %v", data, err)
+			}
+			tt.check(t, roundTrip.Trigger)
+		})
+	}
+}
+
+func TestAbsoluteTriggerMarshalsUTC(t *testing.T) {
+	berlin := time.FixedZone("CET", 3600)
+	data, err := json.Marshal(&AbsoluteTrigger{Type: "AbsoluteTrigger", When: UTCDateTime(time.Date(2025, 3, 1, 8, 0, 0, 0, berlin))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"@type":"AbsoluteTrigger","when":"2025-03-01T07:00:00Z"}`; string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+
+	// Fractional seconds survive a round trip
+	when := `{"@type":"AbsoluteTrigger","when":"2025-03-01T07:00:00.25Z"}`
+	var trigger AbsoluteTrigger
+	if err := json.Unmarshal([]byte(when), &trigger); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := json.Marshal(&trigger); err != nil || string(data) != when {
+		t.Errorf("Expected %s, got %s (%v)", when, data, err)
+	}
+}
+
+func TestEffectiveAlerts(t *testing.T) {
+	own := map[string]*Alert{"own": {Type: "Alert"}}
+	defaults := StaticDefaultAlerts{"default": {Type: "Alert"}}
+
+	event := NewEvent("e1", "Standup")
+	event.Alerts = own
+	if alerts := event.EffectiveAlerts(defaults); alerts["own"] == nil || len(alerts) != 1 {
+		t.Errorf("Expected the event's own alerts, got %v", alerts)
+	}
+
+	event.UseDefaultAlerts = Bool(true)
+	if alerts := event.EffectiveAlerts(defaults); alerts["default"] == nil || len(alerts) != 1 {
+		t.Errorf("Expected the default alerts, got %v", alerts)
+	}
+	if alerts := event.EffectiveAlerts(nil); alerts != nil {
+		t.Errorf("Expected no alerts without a provider, got %v", alerts)
+	}
+
+	// Providers can pick defaults per object, e.g. by calendar or type
+	perType := DefaultAlertsFunc(func(obj CalendarObject) map[string]*Alert {
+		if obj.GetType() == "Task" {
+			return map[string]*Alert{"task": {Type: "Alert"}}
+		}
+		return defaults
+	})
+	task := NewTask("t1", "Report")
+	task.UseDefaultAlerts = Bool(true)
+	if alerts := task.EffectiveAlerts(perType); alerts["task"] == nil {
+		t.Errorf("Expected the task defaults, got %v", alerts)
+	}
+	if alerts := event.EffectiveAlerts(perType); alerts["default"] == nil {
+		t.Errorf("Expected the event defaults, got %v", alerts)
+	}
+}
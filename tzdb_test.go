@@ -0,0 +1,37 @@
+package jscal
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestResolveTimeZone(t *testing.T) {
+	tests := []struct {
+		tzid     string
+		expected bool
+	}{
+		{"America/New_York", true},
+		{"Europe/Berlin", true},
+		{"UTC", true},
+		{"Etc/GMT+5", true},
+		{"US/Pacific", true},
+		{"Not/A_Zone", false},
+		{"Local", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := ResolveTimeZone(tt.tzid); got != tt.expected {
+			t.Errorf("ResolveTimeZone(%q): expected %v, got %v", tt.tzid, tt.expected, got)
+		}
+		if got := knownTimeZone(tt.tzid); got != tt.expected {
+			t.Errorf("knownTimeZone(%q): expected %v, got %v", tt.tzid, tt.expected, got)
+		}
+	}
+}
+
+func TestIANATimeZonesSorted(t *testing.T) {
+	if !sort.StringsAreSorted(ianaTimeZones) {
+		t.Error("Expected ianaTimeZones to be sorted")
+	}
+}
@@ -0,0 +1,66 @@
+package jscal
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// uidNamespaceURL is the URL namespace of RFC 9562 appendix A
+var uidNamespaceURL = [16]byte{0x6b, 0xa7, 0xb8, 0x11, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+
+// uidNamespace is the namespace of the UIDs HashUID derives
+var uidNamespace = nameUUID(uidNamespaceURL, "https://github.com/airtrafik/jscal")
+
+// NewUID returns a new random UID, a UUIDv7 (RFC 9562) such as
+// "01956f3e-8a1c-7b2e-9f4d-3c5a7e1b2d60". RFC 8984 recommends UUIDs as UIDs;
+// version 7 UUIDs also sort by creation time.
+func NewUID() string {
+	var u [16]byte
+	if _, err := rand.Read(u[:]); err != nil {
+		panic(fmt.Sprintf("jscal: cannot generate UID: %v", err))
+	}
+
+	// 48 bit Unix timestamp in milliseconds, then version and variant bits
+	var ms [8]byte
+	binary.BigEndian.PutUint64(ms[:], uint64(Now().UnixMilli()))
+	copy(u[:6], ms[2:])
+	u[6] = u[6]&0x0f | 0x70
+	u[8] = u[8]&0x3f | 0x80
+	return formatUUID(u)
+}
+
+// NewUIDWithDomain returns a new random UID in the "unique@domain" form
+// iCalendar producers traditionally use, e.g. for systems that expect UIDs
+// to name their origin
+func NewUIDWithDomain(domain string) string {
+	return NewUID() + "@" + domain
+}
+
+// HashUID derives a UID from parts, a UUIDv5 (RFC 9562) such as
+// "2f1e8a3c-5b7d-5e9f-a1c3-d5e7f9b1c3d5". The same parts always give the
+// same UID, so imports that name an object by its source, e.g. the feed URL
+// and the remote id, stay idempotent.
+func HashUID(parts ...string) string {
+	return formatUUID(nameUUID(uidNamespace, strings.Join(parts, "\x00")))
+}
+
+// nameUUID returns the name-based version 5 UUID of name in namespace
+func nameUUID(namespace [16]byte, name string) [16]byte {
+	h := sha1.New()
+	h.Write(namespace[:])
+	h.Write([]byte(name))
+
+	var u [16]byte
+	copy(u[:], h.Sum(nil))
+	u[6] = u[6]&0x0f | 0x50
+	u[8] = u[8]&0x3f | 0x80
+	return u
+}
+
+// formatUUID formats a UUID in its 8-4-4-4-12 hex form
+func formatUUID(u [16]byte) string {
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16])
+}
@@ -0,0 +1,60 @@
+package jscal
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-([0-9a-f])[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestNewUID(t *testing.T) {
+	seen := make(map[string]bool)
+	previous := ""
+	for i := 0; i < 100; i++ {
+		uid := NewUID()
+		m := uuidPattern.FindStringSubmatch(uid)
+		if m == nil || m[1] != "7" {
+			t.Fatalf("Expected a version 7 UUID, got %s", uid)
+		}
+		if seen[uid] {
+			t.Fatalf("Duplicate UID %s", uid)
+		}
+		seen[uid] = true
+		// The timestamp prefix never goes backwards
+		if uid[:13] < previous {
+			t.Errorf("Expected %s to sort after %s", uid, previous)
+		}
+		previous = uid[:13]
+	}
+
+	uid := NewUIDWithDomain("example.com")
+	if !strings.HasSuffix(uid, "@example.com") || !uuidPattern.MatchString(strings.TrimSuffix(uid, "@example.com")) {
+		t.Errorf("Expected uuid@example.com, got %s", uid)
+	}
+}
+
+func TestHashUID(t *testing.T) {
+	uid := HashUID("https://example.com/feed.ics", "event-1")
+	if m := uuidPattern.FindStringSubmatch(uid); m == nil || m[1] != "5" {
+		t.Fatalf("Expected a version 5 UUID, got %s", uid)
+	}
+	if again := HashUID("https://example.com/feed.ics", "event-1"); again != uid {
+		t.Errorf("Expected the same UID, got %s and %s", uid, again)
+	}
+	if other := HashUID("https://example.com/feed.ics", "event-2"); other == uid {
+		t.Errorf("Expected different parts to give different UIDs")
+	}
+	if HashUID("a", "bc") == HashUID("ab", "c") {
+		t.Errorf("Expected part boundaries to matter")
+	}
+}
+
+func TestNameUUID(t *testing.T) {
+	// RFC 9562 appendix A.4: UUIDv5 of "www.example.com" in the DNS namespace
+	dns := uidNamespaceURL
+	dns[3] = 0x10
+	if got := formatUUID(nameUUID(dns, "www.example.com")); got != "2ed6657d-e927-568b-95e1-2665a8aea6a2" {
+		t.Errorf("Expected 2ed6657d-e927-568b-95e1-2665a8aea6a2, got %s", got)
+	}
+}
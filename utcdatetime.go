@@ -0,0 +1,106 @@
+package jscal
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// UTCDateTime represents a date-time in UTC, written with a "Z" suffix and
+// fractional seconds only if non-zero, such as "2025-03-01T09:00:00Z".
+// This type is defined in RFC 8984 Section 1.4.4.
+type UTCDateTime time.Time
+
+// NewUTCDateTime creates a UTCDateTime from the instant of a time.Time
+// value, whatever its location
+func NewUTCDateTime(t time.Time) *UTCDateTime {
+	u := UTCDateTime(t.UTC())
+	return &u
+}
+
+// Time converts the UTCDateTime to a time.Time value in UTC.
+func (u *UTCDateTime) Time() time.Time {
+	if u == nil {
+		return time.Time{}
+	}
+	return time.Time(*u).UTC()
+}
+
+// String returns the UTCDateTime in RFC 3339 format with a "Z" suffix,
+// without trailing zeros in the fractional seconds.
+func (u UTCDateTime) String() string {
+	return time.Time(u).UTC().Format(time.RFC3339Nano)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (u UTCDateTime) MarshalJSON() ([]byte, error) {
+	if year := time.Time(u).UTC().Year(); year < 0 || year > 9999 {
+		return nil, fmt.Errorf("UTCDateTime year %d outside of range [0,9999]", year)
+	}
+	return json.Marshal(u.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (u *UTCDateTime) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	parsed, err := ParseUTCDateTime(s)
+	if err != nil {
+		return err
+	}
+	*u = *parsed
+	return nil
+}
+
+// ParseUTCDateTime parses a string in RFC 3339 format. RFC 8984 requires
+// the "Z" suffix, but values with another offset are accepted and
+// converted to UTC; ValidateAgainstSchema reports them.
+func ParseUTCDateTime(s string) (*UTCDateTime, error) {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid UTCDateTime format: %s", s)
+	}
+	return NewUTCDateTime(t), nil
+}
+
+// Format formats the UTCDateTime in UTC using the given layout.
+func (u UTCDateTime) Format(layout string) string {
+	return time.Time(u).UTC().Format(layout)
+}
+
+// Equal returns true if the two UTCDateTime values represent the same instant.
+func (u *UTCDateTime) Equal(other *UTCDateTime) bool {
+	if u == nil || other == nil {
+		return u == other
+	}
+	return time.Time(*u).Equal(time.Time(*other))
+}
+
+// Before returns true if u is before other.
+func (u *UTCDateTime) Before(other *UTCDateTime) bool {
+	if u == nil || other == nil {
+		return false
+	}
+	return time.Time(*u).Before(time.Time(*other))
+}
+
+// After returns true if u is after other.
+func (u *UTCDateTime) After(other *UTCDateTime) bool {
+	if u == nil || other == nil {
+		return false
+	}
+	return time.Time(*u).After(time.Time(*other))
+}
+
+// Add returns the UTCDateTime with the given duration added.
+func (u UTCDateTime) Add(d time.Duration) UTCDateTime {
+	return UTCDateTime(time.Time(u).Add(d))
+}
+
+// Sub returns the duration between two UTCDateTime values.
+func (u UTCDateTime) Sub(other UTCDateTime) time.Duration {
+	return time.Time(u).Sub(time.Time(other))
+}
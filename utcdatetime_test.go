@@ -0,0 +1,124 @@
+package jscal
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUTCDateTimeMarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    time.Time
+		expected string
+	}{
+		{
+			name:     "Basic datetime",
+			input:    time.Date(2025, 3, 1, 14, 30, 45, 0, time.UTC),
+			expected: `"2025-03-01T14:30:45Z"`,
+		},
+		{
+			name:     "With nanoseconds",
+			input:    time.Date(2025, 3, 1, 14, 30, 45, 123000000, time.UTC),
+			expected: `"2025-03-01T14:30:45.123Z"`,
+		},
+		{
+			name:     "Different timezone",
+			input:    time.Date(2025, 3, 1, 14, 30, 45, 0, time.FixedZone("PST", -8*3600)),
+			expected: `"2025-03-01T22:30:45Z"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(NewUTCDateTime(tt.input))
+			if err != nil {
+				t.Fatalf("MarshalJSON() error = %v", err)
+			}
+			if string(data) != tt.expected {
+				t.Errorf("MarshalJSON() = %s, want %s", string(data), tt.expected)
+			}
+		})
+	}
+
+	if _, err := json.Marshal(NewUTCDateTime(time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC))); err == nil {
+		t.Error("Expected an error for a five-digit year")
+	}
+}
+
+func TestUTCDateTimeUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{name: "UTC", input: `"2025-03-01T14:30:45Z"`, expected: "2025-03-01T14:30:45Z"},
+		{name: "Fractional seconds", input: `"2025-03-01T14:30:45.500Z"`, expected: "2025-03-01T14:30:45.5Z"},
+		{name: "Offset is converted", input: `"2025-03-01T14:30:45+01:00"`, expected: "2025-03-01T13:30:45Z"},
+		{name: "Local date-time", input: `"2025-03-01T14:30:45"`, wantErr: true},
+		{name: "Not a date", input: `"yesterday"`, wantErr: true},
+		{name: "Not a string", input: `12`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var u UTCDateTime
+			err := json.Unmarshal([]byte(tt.input), &u)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && u.String() != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, u)
+			}
+		})
+	}
+}
+
+func TestUTCDateTimeFields(t *testing.T) {
+	data := `{"@type": "Event", "uid": "u", "start": "2025-03-01T09:00:00",
+		"created": "2025-03-01T08:00:00+01:00", "updated": "2025-03-01T07:30:00.250Z",
+		"participants": {"p": {"@type": "Participant", "scheduleUpdated": "2025-03-01T07:45:00Z"}},
+		"alerts": {"a": {"@type": "Alert", "trigger": {"@type": "OffsetTrigger", "offset": "-PT5M"}, "acknowledged": "2025-03-01T08:55:00-05:00"}}}`
+	var event Event
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !event.Updated.After(event.Created) {
+		t.Errorf("Expected updated after created, got %s and %s", event.Updated, event.Created)
+	}
+
+	out, err := json.Marshal(&event)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	for _, want := range []string{
+		`"created":"2025-03-01T07:00:00Z"`,
+		`"updated":"2025-03-01T07:30:00.25Z"`,
+		`"scheduleUpdated":"2025-03-01T07:45:00Z"`,
+		`"acknowledged":"2025-03-01T13:55:00Z"`,
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("Expected %s in %s", want, out)
+		}
+	}
+	if err := ValidateAgainstSchema(out); err != nil {
+		t.Errorf("Expected the marshaled event to match the schema, got %v", err)
+	}
+}
+
+func TestValidateUTCDateTime(t *testing.T) {
+	event := NewEvent("far-future", "Far future")
+	event.Updated = NewUTCDateTime(time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC))
+	err := event.Validate()
+	if err == nil || !strings.Contains(err.Error(), "updated must be between years 0 and 9999") {
+		t.Errorf("Expected an updated error, got %v", err)
+	}
+
+	task := NewTask("far-future", "Far future")
+	task.ProgressUpdated = NewUTCDateTime(time.Date(-1, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err := task.Validate(); err == nil || !strings.Contains(err.Error(), "progressUpdated") {
+		t.Errorf("Expected a progressUpdated error, got %v", err)
+	}
+}
@@ -16,16 +16,22 @@ const (
 
 // Regular expressions for validation
 var (
-	// ISO 8601 duration pattern (simplified)
-	durationPattern = regexp.MustCompile(`^-?P(?:\d+(?:\.\d+)?Y)?(?:\d+(?:\.\d+)?M)?(?:\d+(?:\.\d+)?W)?(?:\d+(?:\.\d+)?D)?(?:T(?:\d+(?:\.\d+)?H)?(?:\d+(?:\.\d+)?M)?(?:\d+(?:\.\d+)?S)?)?$`)
-
-	// Color pattern (CSS color values)
-	colorPattern = regexp.MustCompile(`^(?:#[0-9a-fA-F]{3,8}|rgb\(|rgba\(|hsl\(|hsla\(|[a-zA-Z]+)`)
-
 	// IANA timezone pattern
 	timezonePattern = regexp.MustCompile(`^[A-Za-z0-9/_+-]+$`)
 )
 
+// isDuration reports whether s is an RFC 8984 Duration: an ISO 8601
+// duration, as ParseDuration reads them, without a sign
+func isDuration(s string) bool {
+	return !strings.HasPrefix(s, "-") && !strings.HasPrefix(s, "+") && isSignedDuration(s)
+}
+
+// isSignedDuration reports whether s is an RFC 8984 SignedDuration
+func isSignedDuration(s string) bool {
+	_, err := parseISO8601Duration(s)
+	return err == nil
+}
+
 // ValidationError represents a validation error
 type ValidationError struct {
 	Field   string
@@ -68,9 +74,19 @@ func (e ValidationError) Error() string {
 	return e.Message
 }
 
+// Is reports whether target is ErrValidation
+func (e ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
 // ValidationErrors represents multiple validation errors
 type ValidationErrors []ValidationError
 
+// Is reports whether target is ErrValidation
+func (e ValidationErrors) Is(target error) bool {
+	return target == ErrValidation
+}
+
 func (e ValidationErrors) Error() string {
 	if len(e) == 0 {
 		return "no validation errors"
@@ -88,6 +104,11 @@ func (e ValidationErrors) Error() string {
 
 // Validate validates the Event according to RFC 8984
 func (e *Event) Validate() error {
+	return e.validate(nil)
+}
+
+// validate validates the Event with the given options (nil for defaults)
+func (e *Event) validate(opts *ValidationOptions) error {
 	if e == nil {
 		return ValidationError{
 			Field:   "event",
@@ -112,7 +133,7 @@ func (e *Event) Validate() error {
 			Value:   e.UID,
 			Message: "is required",
 		})
-	} else if len(e.UID) > MaxUIDLength {
+	} else if opts.enforceLengths() && len(e.UID) > MaxUIDLength {
 		errors = append(errors, ValidationError{
 			Field:   "uid",
 			Value:   e.UID,
@@ -129,8 +150,12 @@ func (e *Event) Validate() error {
 		})
 	}
 
+	// Validate timestamps
+	errors = append(errors, validateUTCDateTime("created", e.Created)...)
+	errors = append(errors, validateUTCDateTime("updated", e.Updated)...)
+
 	// Validate title length (title is optional per RFC 8984)
-	if e.Title != nil && len(*e.Title) > MaxTitleLength {
+	if opts.enforceLengths() && e.Title != nil && len(*e.Title) > MaxTitleLength {
 		errors = append(errors, ValidationError{
 			Field:   "title",
 			Value:   *e.Title,
@@ -139,7 +164,7 @@ func (e *Event) Validate() error {
 	}
 
 	// Validate description length
-	if e.Description != nil && len(*e.Description) > MaxDescriptionLength {
+	if opts.enforceLengths() && e.Description != nil && len(*e.Description) > MaxDescriptionLength {
 		errors = append(errors, ValidationError{
 			Field:   "description",
 			Value:   *e.Description,
@@ -149,7 +174,7 @@ func (e *Event) Validate() error {
 
 	// Validate duration format
 	if e.Duration != nil {
-		if !durationPattern.MatchString(*e.Duration) {
+		if !isDuration(*e.Duration) {
 			errors = append(errors, ValidationError{
 				Field:   "duration",
 				Value:   *e.Duration,
@@ -169,9 +194,18 @@ func (e *Event) Validate() error {
 		}
 	}
 
+	// Validate locale
+	if e.Locale != nil && opts.enforceLanguageTags() && !IsLanguageTag(*e.Locale) {
+		errors = append(errors, ValidationError{
+			Field:   "locale",
+			Value:   *e.Locale,
+			Message: "invalid language tag",
+		})
+	}
+
 	// Validate color
 	if e.Color != nil {
-		if !colorPattern.MatchString(*e.Color) {
+		if _, err := ParseColor(*e.Color); err != nil {
 			errors = append(errors, ValidationError{
 				Field:   "color",
 				Value:   *e.Color,
@@ -187,7 +221,7 @@ func (e *Event) Validate() error {
 			StatusTentative: true,
 			StatusCancelled: true,
 		}
-		if !validStatuses[*e.Status] {
+		if opts.enforceEnums() && !validStatuses[*e.Status] {
 			errors = append(errors, ValidationError{
 				Field:   "status",
 				Value:   *e.Status,
@@ -204,7 +238,7 @@ func (e *Event) Validate() error {
 			FreeBusyTentative:   true,
 			FreeBusyUnavailable: true,
 		}
-		if !validStatuses[*e.FreeBusyStatus] {
+		if opts.enforceEnums() && !validStatuses[*e.FreeBusyStatus] {
 			errors = append(errors, ValidationError{
 				Field:   "freeBusyStatus",
 				Value:   *e.FreeBusyStatus,
@@ -220,7 +254,7 @@ func (e *Event) Validate() error {
 			PrivacyPrivate: true,
 			PrivacySecret:  true,
 		}
-		if !validPrivacyLevels[*e.Privacy] {
+		if opts.enforceEnums() && !validPrivacyLevels[*e.Privacy] {
 			errors = append(errors, ValidationError{
 				Field:   "privacy",
 				Value:   *e.Privacy,
@@ -252,7 +286,7 @@ func (e *Event) Validate() error {
 			MethodCounter:        true,
 			MethodDeclineCounter: true,
 		}
-		if !validMethods[*e.Method] {
+		if opts.enforceEnums() && !validMethods[*e.Method] {
 			errors = append(errors, ValidationError{
 				Field:   "method",
 				Value:   *e.Method,
@@ -286,16 +320,26 @@ func (e *Event) Validate() error {
 		})
 	}
 
+	// Validate requestStatus
+	errors = append(errors, validateRequestStatus(e.RequestStatus)...)
+	errors = append(errors, validateHideAttendees(e.HideAttendees, e.Participants)...)
+
+	// Validate replyTo and sentBy
+	errors = append(errors, validateMethodURIs("replyTo", e.ReplyTo)...)
+	if e.SentBy != nil {
+		errors = append(errors, validateEmailAt("sentBy", *e.SentBy)...)
+	}
+
 	// Validate participants
 	for id, participant := range e.Participants {
-		if errs := validateParticipant(id, participant); len(errs) > 0 {
+		if errs := validateParticipant(id, participant, opts); len(errs) > 0 {
 			errors = append(errors, errs...)
 		}
 	}
 
 	// Validate locations
 	for id, location := range e.Locations {
-		if errs := validateLocation(id, location); len(errs) > 0 {
+		if errs := validateLocation(id, location, opts); len(errs) > 0 {
 			errors = append(errors, errs...)
 		}
 	}
@@ -309,7 +353,7 @@ func (e *Event) Validate() error {
 
 	// Validate alerts
 	for id, alert := range e.Alerts {
-		if errs := validateAlert(id, alert); len(errs) > 0 {
+		if errs := validateAlert(id, alert, opts); len(errs) > 0 {
 			errors = append(errors, errs...)
 		}
 	}
@@ -323,10 +367,21 @@ func (e *Event) Validate() error {
 
 	// Validate recurrence rules
 	for i, rule := range e.RecurrenceRules {
-		if errs := validateRecurrenceRule(fmt.Sprintf("recurrenceRules[%d]", i), &rule); len(errs) > 0 {
+		if errs := validateRecurrenceRule(fmt.Sprintf("recurrenceRules[%d]", i), &rule, opts); len(errs) > 0 {
 			errors = append(errors, errs...)
 		}
 	}
+	for i, rule := range e.ExcludedRecurrenceRules {
+		if errs := validateRecurrenceRule(fmt.Sprintf("excludedRecurrenceRules[%d]", i), &rule, opts); len(errs) > 0 {
+			errors = append(errors, errs...)
+		}
+	}
+
+	// Validate localizations
+	errors = append(errors, validateLocalizations(e, e.Localizations, opts)...)
+
+	// Validate registered extension properties
+	errors = append(errors, validateExtensions(e.Extensions)...)
 
 	if len(errors) > 0 {
 		return errors
@@ -335,7 +390,35 @@ func (e *Event) Validate() error {
 	return nil
 }
 
-func validateParticipant(id string, p *Participant) ValidationErrors {
+// validateUTCDateTime checks that a UTCDateTime can be written in RFC 3339
+// form, which only has four-digit years
+func validateUTCDateTime(field string, value *UTCDateTime) ValidationErrors {
+	if value == nil {
+		return nil
+	}
+	if year := value.Time().Year(); year < 0 || year > 9999 {
+		return ValidationErrors{{
+			Field:   field,
+			Value:   value.Time(),
+			Message: "must be between years 0 and 9999",
+		}}
+	}
+	return nil
+}
+
+// validateEmailAt validates an email address at field
+func validateEmailAt(field, address string) ValidationErrors {
+	if err := ValidateEmail(address); err != nil {
+		return ValidationErrors{{
+			Field:   field,
+			Value:   address,
+			Message: fmt.Sprintf("invalid email format: %v", err),
+		}}
+	}
+	return nil
+}
+
+func validateParticipant(id string, p *Participant, opts *ValidationOptions) ValidationErrors {
 	var errors ValidationErrors
 
 	if p == nil {
@@ -343,15 +426,24 @@ func validateParticipant(id string, p *Participant) ValidationErrors {
 		return errors
 	}
 
-	// Validate email format if present
+	// Validate email addresses if present
 	if p.Email != nil && *p.Email != "" {
-		if !strings.Contains(*p.Email, "@") {
-			errors = append(errors, ValidationError{
-				Field:   fmt.Sprintf("participants[%s].email", id),
-				Value:   *p.Email,
-				Message: "invalid email format",
-			})
-		}
+		errors = append(errors, validateEmailAt(fmt.Sprintf("participants[%s].email", id), *p.Email)...)
+	}
+	if p.SentBy != nil {
+		errors = append(errors, validateEmailAt(fmt.Sprintf("participants[%s].sentBy", id), *p.SentBy)...)
+	}
+
+	// Validate sendTo
+	errors = append(errors, validateMethodURIs(fmt.Sprintf("participants[%s].sendTo", id), p.SendTo)...)
+
+	// Validate language
+	if p.Language != nil && opts.enforceLanguageTags() && !IsLanguageTag(*p.Language) {
+		errors = append(errors, ValidationError{
+			Field:   fmt.Sprintf("participants[%s].language", id),
+			Value:   *p.Language,
+			Message: "invalid language tag",
+		})
 	}
 
 	// Validate participation status
@@ -363,7 +455,7 @@ func validateParticipant(id string, p *Participant) ValidationErrors {
 			"tentative":    true,
 			"delegated":    true,
 		}
-		if !validStatuses[*p.ParticipationStatus] {
+		if opts.enforceEnums() && !validStatuses[*p.ParticipationStatus] {
 			errors = append(errors, ValidationError{
 				Field:   fmt.Sprintf("participants[%s].participationStatus", id),
 				Value:   *p.ParticipationStatus,
@@ -372,6 +464,25 @@ func validateParticipant(id string, p *Participant) ValidationErrors {
 		}
 	}
 
+	errors = append(errors, validateUTCDateTime(fmt.Sprintf("participants[%s].scheduleUpdated", id), p.ScheduleUpdated)...)
+
+	// Validate the progress of a task participant
+	if p.Progress != nil && opts.enforceEnums() && !validProgress[*p.Progress] {
+		errors = append(errors, ValidationError{
+			Field:   fmt.Sprintf("participants[%s].progress", id),
+			Value:   *p.Progress,
+			Message: "invalid progress value",
+		})
+	}
+	if p.PercentComplete != nil && (*p.PercentComplete < 0 || *p.PercentComplete > 100) {
+		errors = append(errors, ValidationError{
+			Field:   fmt.Sprintf("participants[%s].percentComplete", id),
+			Value:   *p.PercentComplete,
+			Message: "must be between 0 and 100",
+		})
+	}
+	errors = append(errors, validateUTCDateTime(fmt.Sprintf("participants[%s].progressUpdated", id), p.ProgressUpdated)...)
+
 	// Validate scheduleAgent
 	if p.ScheduleAgent != nil {
 		validAgents := map[string]bool{
@@ -379,7 +490,7 @@ func validateParticipant(id string, p *Participant) ValidationErrors {
 			ScheduleAgentClient: true,
 			ScheduleAgentNone:   true,
 		}
-		if !validAgents[*p.ScheduleAgent] {
+		if opts.enforceEnums() && !validAgents[*p.ScheduleAgent] {
 			errors = append(errors, ValidationError{
 				Field:   fmt.Sprintf("participants[%s].scheduleAgent", id),
 				Value:   *p.ScheduleAgent,
@@ -397,7 +508,7 @@ func validateParticipant(id string, p *Participant) ValidationErrors {
 			KindLocation:   true,
 			KindUnknown:    true,
 		}
-		if !validKinds[*p.Kind] {
+		if opts.enforceEnums() && !validKinds[*p.Kind] {
 			errors = append(errors, ValidationError{
 				Field:   fmt.Sprintf("participants[%s].kind", id),
 				Value:   *p.Kind,
@@ -417,7 +528,7 @@ func validateParticipant(id string, p *Participant) ValidationErrors {
 			RoleContact:       true,
 		}
 		for role := range p.Roles {
-			if !validRoles[role] {
+			if opts.enforceEnums() && !validRoles[role] {
 				errors = append(errors, ValidationError{
 					Field:   fmt.Sprintf("participants[%s].roles[%s]", id, role),
 					Value:   role,
@@ -430,7 +541,7 @@ func validateParticipant(id string, p *Participant) ValidationErrors {
 	return errors
 }
 
-func validateLocation(id string, l *Location) ValidationErrors {
+func validateLocation(id string, l *Location, opts *ValidationOptions) ValidationErrors {
 	var errors ValidationErrors
 
 	if l == nil {
@@ -446,16 +557,24 @@ func validateLocation(id string, l *Location) ValidationErrors {
 				Value:   *l.Coordinates,
 				Message: "must be a geo: URI",
 			})
+		} else if _, err := ParseGeo(*l.Coordinates); err != nil {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("locations[%s].coordinates", id),
+				Value:   *l.Coordinates,
+				Message: err.Error(),
+			})
 		}
 	}
 
+	errors = append(errors, validateAddress(fmt.Sprintf("locations[%s].address", id), l.Address)...)
+
 	// Validate relativeTo field
 	if l.RelativeTo != nil {
 		validValues := map[string]bool{
 			RelativeToStart: true,
 			RelativeToEnd:   true,
 		}
-		if !validValues[*l.RelativeTo] {
+		if opts.enforceEnums() && !validValues[*l.RelativeTo] {
 			errors = append(errors, ValidationError{
 				Field:   fmt.Sprintf("locations[%s].relativeTo", id),
 				Value:   *l.RelativeTo,
@@ -482,7 +601,7 @@ func validateLocation(id string, l *Location) ValidationErrors {
 	// Validate links if present
 	if l.Links != nil {
 		for linkId, link := range l.Links {
-			linkErrors := validateLink(fmt.Sprintf("locations[%s].links[%s]", id, linkId), link)
+			linkErrors := validateLinkAt(fmt.Sprintf("locations[%s].links[%s]", id, linkId), link)
 			errors = append(errors, linkErrors...)
 		}
 	}
@@ -539,7 +658,7 @@ func validateVirtualLocation(id string, vl *VirtualLocation) ValidationErrors {
 	return errors
 }
 
-func validateAlert(id string, a *Alert) ValidationErrors {
+func validateAlert(id string, a *Alert, opts *ValidationOptions) ValidationErrors {
 	var errors ValidationErrors
 
 	if a == nil {
@@ -555,59 +674,82 @@ func validateAlert(id string, a *Alert) ValidationErrors {
 		})
 	}
 
+	errors = append(errors, validateUTCDateTime(fmt.Sprintf("alerts[%s].acknowledged", id), a.Acknowledged)...)
+
 	// Trigger is required for alerts
-	if a.Trigger == nil {
+	field := fmt.Sprintf("alerts[%s].trigger", id)
+	switch t := a.Trigger.(type) {
+	case nil:
 		errors = append(errors, ValidationError{
-			Field:   fmt.Sprintf("alerts[%s].trigger", id),
+			Field:   field,
 			Value:   nil,
 			Message: "is required",
 		})
-	} else {
-		// Validate trigger type
-		if a.Trigger.Type != "OffsetTrigger" {
+	case *OffsetTrigger:
+		if t.Type != "OffsetTrigger" {
 			errors = append(errors, ValidationError{
-				Field:   fmt.Sprintf("alerts[%s].trigger.@type", id),
-				Value:   a.Trigger.Type,
+				Field:   field + ".@type",
+				Value:   t.Type,
 				Message: "must be 'OffsetTrigger'",
 			})
 		}
 
-		// Trigger must have either offset or when
-		// Note: 'when' field is not in our current OffsetTrigger struct,
-		// but offset is required for OffsetTrigger
-		if a.Trigger.Offset == "" {
+		// Offset is required for OffsetTrigger
+		if t.Offset == "" {
 			errors = append(errors, ValidationError{
-				Field:   fmt.Sprintf("alerts[%s].trigger", id),
-				Value:   a.Trigger,
-				Message: "must have either offset or when",
+				Field:   field + ".offset",
+				Value:   t.Offset,
+				Message: "is required",
+			})
+		} else if !isSignedDuration(t.Offset) {
+			// Validate offset format (ISO 8601 duration)
+			errors = append(errors, ValidationError{
+				Field:   field + ".offset",
+				Value:   t.Offset,
+				Message: "invalid ISO 8601 duration format",
 			})
-		}
-
-		// Validate offset format (ISO 8601 duration)
-		if a.Trigger.Offset != "" {
-			if !durationPattern.MatchString(a.Trigger.Offset) {
-				errors = append(errors, ValidationError{
-					Field:   fmt.Sprintf("alerts[%s].trigger.offset", id),
-					Value:   a.Trigger.Offset,
-					Message: "invalid ISO 8601 duration format",
-				})
-			}
 		}
 
 		// Validate relativeTo
-		if a.Trigger.RelativeTo != nil {
+		if t.RelativeTo != nil {
 			validValues := map[string]bool{
 				RelativeToStart: true,
 				RelativeToEnd:   true,
 			}
-			if !validValues[*a.Trigger.RelativeTo] {
+			if opts.enforceEnums() && !validValues[*t.RelativeTo] {
 				errors = append(errors, ValidationError{
-					Field:   fmt.Sprintf("alerts[%s].trigger.relativeTo", id),
-					Value:   *a.Trigger.RelativeTo,
+					Field:   field + ".relativeTo",
+					Value:   *t.RelativeTo,
 					Message: "invalid relativeTo",
 				})
 			}
 		}
+	case *AbsoluteTrigger:
+		if t.Type != "AbsoluteTrigger" {
+			errors = append(errors, ValidationError{
+				Field:   field + ".@type",
+				Value:   t.Type,
+				Message: "must be 'AbsoluteTrigger'",
+			})
+		}
+
+		// When is required for AbsoluteTrigger
+		if t.When.Time().IsZero() {
+			errors = append(errors, ValidationError{
+				Field:   field + ".when",
+				Value:   nil,
+				Message: "is required",
+			})
+		}
+	case *UnknownTrigger:
+		// RFC 8984 Section 4.5.2: alerts with unknown triggers are ignored, not invalid
+		if t.Type == "" {
+			errors = append(errors, ValidationError{
+				Field:   field + ".@type",
+				Value:   t.Type,
+				Message: "is required",
+			})
+		}
 	}
 
 	// Validate action
@@ -616,7 +758,7 @@ func validateAlert(id string, a *Alert) ValidationErrors {
 			"display": true,
 			"email":   true,
 		}
-		if !validActions[*a.Action] {
+		if opts.enforceEnums() && !validActions[*a.Action] {
 			errors = append(errors, ValidationError{
 				Field:   fmt.Sprintf("alerts[%s].action", id),
 				Value:   *a.Action,
@@ -629,6 +771,11 @@ func validateAlert(id string, a *Alert) ValidationErrors {
 }
 
 func validateLink(id string, l *Link) ValidationErrors {
+	return validateLinkAt(fmt.Sprintf("links[%s]", id), l)
+}
+
+// validateLinkAt validates a link whose field path is given by fieldPrefix
+func validateLinkAt(fieldPrefix string, l *Link) ValidationErrors {
 	var errors ValidationErrors
 
 	if l == nil {
@@ -638,14 +785,14 @@ func validateLink(id string, l *Link) ValidationErrors {
 
 	if l.Href == "" {
 		errors = append(errors, ValidationError{
-			Field:   fmt.Sprintf("links[%s].href", id),
+			Field:   fmt.Sprintf("%s.href", fieldPrefix),
 			Value:   l.Href,
 			Message: "is required",
 		})
 	} else {
 		if _, err := url.Parse(l.Href); err != nil {
 			errors = append(errors, ValidationError{
-				Field:   fmt.Sprintf("links[%s].href", id),
+				Field:   fmt.Sprintf("%s.href", fieldPrefix),
 				Value:   l.Href,
 				Message: "invalid URL format",
 			})
@@ -655,7 +802,7 @@ func validateLink(id string, l *Link) ValidationErrors {
 	return errors
 }
 
-func validateRecurrenceRule(fieldPrefix string, rr *RecurrenceRule) ValidationErrors {
+func validateRecurrenceRule(fieldPrefix string, rr *RecurrenceRule, opts *ValidationOptions) ValidationErrors {
 	var errors ValidationErrors
 
 	if rr == nil {
@@ -740,7 +887,7 @@ func validateRecurrenceRule(fieldPrefix string, rr *RecurrenceRule) ValidationEr
 			"buddhist":      true,
 			"indian":        true,
 		}
-		if !validRScales[*rr.RScale] {
+		if opts.enforceEnums() && !validRScales[*rr.RScale] {
 			errors = append(errors, ValidationError{
 				Field:   fmt.Sprintf("%s.rscale", fieldPrefix),
 				Value:   *rr.RScale,
@@ -756,7 +903,7 @@ func validateRecurrenceRule(fieldPrefix string, rr *RecurrenceRule) ValidationEr
 			"backward": true,
 			"omit":     true,
 		}
-		if !validSkips[*rr.Skip] {
+		if opts.enforceEnums() && !validSkips[*rr.Skip] {
 			errors = append(errors, ValidationError{
 				Field:   fmt.Sprintf("%s.skip", fieldPrefix),
 				Value:   *rr.Skip,
@@ -96,6 +96,27 @@ func TestValidateEvent(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "valid named color",
+			event: &Event{
+				Type:  "Event",
+				UID:   "test-123",
+				Start: testStart,
+				Color: String("RebeccaPurple"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid color",
+			event: &Event{
+				Type:  "Event",
+				UID:   "test-123",
+				Start: testStart,
+				Color: String("rgb(300, 0, 0)"),
+			},
+			wantErr: true,
+			errMsg:  "invalid CSS color value",
+		},
 		{
 			name: "invalid status",
 			event: &Event{
@@ -635,6 +656,42 @@ func TestValidateEvent(t *testing.T) {
 	}
 }
 
+func TestValidateDurations(t *testing.T) {
+	tests := []struct {
+		value    string
+		duration bool // valid as a Duration
+		offset   bool // valid as a SignedDuration
+	}{
+		{"PT1H", true, true},
+		{"P1DT12H", true, true},
+		{"PT1.5H", true, true},
+		{"-PT15M", false, true},
+		{"+PT15M", false, true},
+		{"P", false, false},
+		{"PT", false, false},
+		{"P1DT", false, false},
+		{"P1.5W2D", false, false},
+		{"P1Z", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			event := NewEvent("duration@example.com", "Durations")
+			event.Start = NewLocalDateTime(time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC))
+			event.Duration = String(tt.value)
+			if err := event.Validate(); (err == nil) != tt.duration {
+				t.Errorf("Expected duration valid %v, got %v", tt.duration, err)
+			}
+
+			event.Duration = nil
+			event.AddAlert("1", &Alert{Type: "Alert", Trigger: NewOffsetTrigger(tt.value)})
+			if err := event.Validate(); (err == nil) != tt.offset {
+				t.Errorf("Expected offset valid %v, got %v", tt.offset, err)
+			}
+		})
+	}
+}
+
 func TestValidateParticipant(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -661,6 +718,23 @@ func TestValidateParticipant(t *testing.T) {
 			wantErr: true,
 			errMsg:  "invalid participationStatus",
 		},
+		{
+			name: "invalid participant progress",
+			participant: &Participant{
+				Progress: String("halfway"),
+			},
+			wantErr: true,
+			errMsg:  "invalid progress value",
+		},
+		{
+			name: "participant percentComplete out of range",
+			participant: &Participant{
+				Progress:        String(ProgressInProcess),
+				PercentComplete: Int(120),
+			},
+			wantErr: true,
+			errMsg:  "must be between 0 and 100",
+		},
 		{
 			name: "valid participation status accepted",
 			participant: &Participant{
@@ -835,7 +909,7 @@ func TestValidateParticipant(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := validateParticipant("test-participant", tt.participant)
+			err := validateParticipant("test-participant", tt.participant, nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("validateParticipant() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -946,6 +1020,15 @@ func TestValidateLocation(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "coordinates out of range",
+			location: &Location{
+				Name:        String("Office"),
+				Coordinates: String("geo:137.386013,-122.082932"),
+			},
+			wantErr: true,
+			errMsg:  "latitude 137.386013 out of range",
+		},
 		// Links validation tests
 		{
 			name: "location with links",
@@ -963,7 +1046,7 @@ func TestValidateLocation(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := validateLocation("test-location", tt.location)
+			err := validateLocation("test-location", tt.location, nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("validateLocation() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -1117,7 +1200,7 @@ func TestValidateAlert(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name: "alert with neither offset nor when",
+			name: "offset trigger without offset",
 			alert: &Alert{
 				Type: "Alert",
 				Trigger: &OffsetTrigger{
@@ -1125,7 +1208,41 @@ func TestValidateAlert(t *testing.T) {
 				},
 			},
 			wantErr: true,
-			errMsg:  "must have either offset or when",
+			errMsg:  "trigger.offset is required",
+		},
+		{
+			name: "alert with absolute trigger",
+			alert: &Alert{
+				Type:    "Alert",
+				Trigger: NewAbsoluteTrigger(time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC)),
+			},
+			wantErr: false,
+		},
+		{
+			name: "absolute trigger without when",
+			alert: &Alert{
+				Type:    "Alert",
+				Trigger: &AbsoluteTrigger{Type: "AbsoluteTrigger"},
+			},
+			wantErr: true,
+			errMsg:  "trigger.when is required",
+		},
+		{
+			name: "absolute trigger with wrong type",
+			alert: &Alert{
+				Type:    "Alert",
+				Trigger: &AbsoluteTrigger{Type: "OffsetTrigger", When: *NewUTCDateTime(time.Now())},
+			},
+			wantErr: true,
+			errMsg:  "must be 'AbsoluteTrigger'",
+		},
+		{
+			name: "unknown trigger is ignored",
+			alert: &Alert{
+				Type:    "Alert",
+				Trigger: &UnknownTrigger{Type: "LocationTrigger", Properties: map[string]interface{}{"@type": "LocationTrigger"}},
+			},
+			wantErr: false,
 		},
 		{
 			name: "invalid relativeTo",
@@ -1196,7 +1313,7 @@ func TestValidateAlert(t *testing.T) {
 					Type:   "OffsetTrigger",
 					Offset: "-PT15M",
 				},
-				Acknowledged: TimePtr(time.Date(2025, 3, 1, 13, 45, 0, 0, time.UTC)),
+				Acknowledged: NewUTCDateTime(time.Date(2025, 3, 1, 13, 45, 0, 0, time.UTC)),
 			},
 			wantErr: false,
 		},
@@ -1204,7 +1321,7 @@ func TestValidateAlert(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := validateAlert("test-alert", tt.alert)
+			err := validateAlert("test-alert", tt.alert, nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("validateAlert() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -1608,7 +1725,7 @@ func TestValidateRecurrenceRule(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := validateRecurrenceRule("test-rule", tt.rule)
+			err := validateRecurrenceRule("test-rule", tt.rule, nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("validateRecurrenceRule() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -1647,3 +1764,27 @@ func TestValidateEventWithRecurrenceRules(t *testing.T) {
 		t.Error("Event with invalid recurrence rule should not validate")
 	}
 }
+
+func TestValidateExcludedRecurrenceRules(t *testing.T) {
+	event := NewEvent("excluded-rules", "Standup")
+	event.Start = NewLocalDateTime(time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC))
+	event.RecurrenceRules = []RecurrenceRule{*NewRecurrenceRule(FrequencyDaily)}
+	event.ExcludedRecurrenceRules = []RecurrenceRule{{Type: "RecurrenceRule", Frequency: "sometimes"}}
+
+	task := NewTask("excluded-rules-task", "Water plants")
+	task.ExcludedRecurrenceRules = []RecurrenceRule{{Type: "RecurrenceRule"}}
+
+	for _, object := range []interface{ Validate() error }{event, task} {
+		err := object.Validate()
+		if err == nil {
+			t.Fatal("Expected validation errors")
+		}
+		found := false
+		for _, valErr := range err.(ValidationErrors) {
+			found = found || valErr.Field == "excludedRecurrenceRules[0].frequency"
+		}
+		if !found {
+			t.Errorf("Expected an error at excludedRecurrenceRules[0].frequency, got %v", err)
+		}
+	}
+}
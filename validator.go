@@ -0,0 +1,411 @@
+package jscal
+
+import "fmt"
+
+// ValidationOptions configures a Validator. The zero value validates exactly
+// like Validate().
+type ValidationOptions struct {
+	// SkipLengthLimits disables the uid, title and description length limits
+	SkipLengthLimits bool
+
+	// AllowUnknownEnums accepts values outside the RFC 8984 registries for
+	// extensible properties such as status, privacy and participant roles
+	AllowUnknownEnums bool
+
+	// RequireTitle rejects objects without a non-empty title
+	RequireTitle bool
+
+	// RequireTimeZone rejects timed (non showWithoutTime) events and tasks
+	// that don't set a timeZone, i.e. floating times
+	RequireTimeZone bool
+
+	// UnknownTimeZones checks timeZone, recurrenceIdTimeZone and the
+	// locations' timeZone against the time zone database. SeverityError
+	// fails validation, SeverityWarning only reports them in
+	// Validator.ValidateReport. Empty checks the identifier format only.
+	UnknownTimeZones Severity
+
+	// TimeZoneResolver decides which zones exist, ResolveTimeZone if nil
+	TimeZoneResolver TimeZoneResolver
+
+	// Profile applies the quirks of a specific downstream system
+	Profile *Profile
+
+	// AllowInvalidLanguageTags accepts locale, participant language and
+	// localization keys that aren't RFC 5646 language tags
+	AllowInvalidLanguageTags bool
+
+	// SkipLocalizationPatches accepts localization patches without checking
+	// that they apply to the object
+	SkipLocalizationPatches bool
+
+	// CheckMethod applies the iTIP rules of the object's method (RFC 5546),
+	// e.g. that a request has an owner and attendees and a reply exactly
+	// one attendee with its answer
+	CheckMethod bool
+}
+
+// enforceLengths reports whether length limits apply
+func (o *ValidationOptions) enforceLengths() bool {
+	return o == nil || !o.SkipLengthLimits
+}
+
+// enforceEnums reports whether enumerated values must be registered ones
+func (o *ValidationOptions) enforceEnums() bool {
+	return o == nil || !o.AllowUnknownEnums
+}
+
+// enforceLanguageTags reports whether localization keys must be language tags
+func (o *ValidationOptions) enforceLanguageTags() bool {
+	return o == nil || !o.AllowInvalidLanguageTags
+}
+
+// enforceLocalizationPatches reports whether localization patches are checked
+func (o *ValidationOptions) enforceLocalizationPatches() bool {
+	return o == nil || !o.SkipLocalizationPatches
+}
+
+// Profile describes what a downstream calendar system accepts beyond RFC 8984
+type Profile struct {
+	Name string
+
+	// RecurringRequiresTimeZone rejects timed recurring events without a timeZone
+	RecurringRequiresTimeZone bool
+
+	// UnsupportedFrequencies lists recurrence frequencies the system rejects
+	UnsupportedFrequencies []string
+
+	// UnsupportedPrivacy lists privacy values the system can't represent
+	UnsupportedPrivacy []string
+
+	// UnsupportedAlertActions lists alert actions the system ignores or rejects
+	UnsupportedAlertActions []string
+}
+
+// Predefined client profiles
+var (
+	// ProfileApple matches iCloud, which does not deliver email alerts
+	ProfileApple = &Profile{
+		Name:                    "apple",
+		UnsupportedAlertActions: []string{"email"},
+	}
+
+	// ProfileGoogle matches Google Calendar, which requires a time zone on
+	// recurring events, has no sub-daily recurrence and no secret visibility
+	ProfileGoogle = &Profile{
+		Name:                      "google",
+		RecurringRequiresTimeZone: true,
+		UnsupportedFrequencies:    []string{"hourly", "minutely", "secondly"},
+		UnsupportedPrivacy:        []string{PrivacySecret},
+	}
+
+	// ProfileFastmail matches Fastmail, which implements JSCalendar natively
+	// and adds no restrictions beyond RFC 8984
+	ProfileFastmail = &Profile{
+		Name: "fastmail",
+	}
+)
+
+// Profiles lists the predefined profiles by name
+var Profiles = map[string]*Profile{
+	ProfileApple.Name:    ProfileApple,
+	ProfileGoogle.Name:   ProfileGoogle,
+	ProfileFastmail.Name: ProfileFastmail,
+}
+
+// Validator validates JSCalendar objects with configurable options
+type Validator struct {
+	opts ValidationOptions
+}
+
+// NewValidator creates a Validator with the given options
+func NewValidator(opts ValidationOptions) *Validator {
+	return &Validator{opts: opts}
+}
+
+// Validate validates any JSCalendar object
+func (v *Validator) Validate(obj CalendarObject) error {
+	switch o := obj.(type) {
+	case *Event:
+		return v.ValidateEvent(o)
+	case *Task:
+		return v.ValidateTask(o)
+	case *Group:
+		return v.ValidateGroup(o)
+	case nil:
+		return ValidationError{Field: "object", Message: "object is nil"}
+	default:
+		return obj.Validate()
+	}
+}
+
+// ValidateEvent validates an Event
+func (v *Validator) ValidateEvent(e *Event) error {
+	if e == nil {
+		return e.validate(&v.opts)
+	}
+	return v.combine(e.validate(&v.opts), v.checkEvent(e))
+}
+
+// ValidateTask validates a Task
+func (v *Validator) ValidateTask(t *Task) error {
+	if t == nil {
+		return t.validate(&v.opts)
+	}
+	return v.combine(t.validate(&v.opts), v.checkTask(t))
+}
+
+// ValidateGroup validates a Group and its entries, including those of
+// nested groups
+func (v *Validator) ValidateGroup(g *Group) error {
+	if g == nil {
+		return g.validate(&v.opts)
+	}
+	return v.combine(g.validate(&v.opts), v.checkGroup(g, nil))
+}
+
+// ValidateReport validates any JSCalendar object like Validate and returns a
+// structured report. With UnknownTimeZones set to SeverityWarning, unknown
+// time zones are reported as warnings.
+func (v *Validator) ValidateReport(obj CalendarObject) *ValidationReport {
+	var report *ValidationReport
+	switch o := obj.(type) {
+	case *Event:
+		report = o.report(v.ValidateEvent(o))
+		if o != nil {
+			v.addTimeZoneWarnings(report, "", o.TimeZone, o.RecurrenceIdTimeZone, o.Locations, o.TimeZones)
+		}
+	case *Task:
+		report = o.report(v.ValidateTask(o))
+		if o != nil {
+			v.addTimeZoneWarnings(report, "", o.TimeZone, o.RecurrenceIdTimeZone, o.Locations, o.TimeZones)
+		}
+	case *Group:
+		report = o.report(v.ValidateGroup(o))
+		if o != nil {
+			v.addGroupTimeZoneWarnings(report, "", o, nil)
+		}
+	default:
+		report = &ValidationReport{}
+		report.addErrors(v.Validate(obj))
+	}
+	report.sort()
+	return report
+}
+
+// addTimeZoneWarnings adds unknown time zones to a report as warnings
+func (v *Validator) addTimeZoneWarnings(report *ValidationReport, prefix string, timeZone, recurrenceIdTimeZone *string,
+	locations map[string]*Location, custom map[string]*TimeZone) {
+	if v.opts.UnknownTimeZones != SeverityWarning {
+		return
+	}
+	for _, err := range v.checkTimeZones(timeZone, recurrenceIdTimeZone, locations, custom) {
+		report.add(SeverityWarning, prefix+err.Field, err.Value, err.Message)
+	}
+}
+
+// addGroupTimeZoneWarnings adds the unknown time zones of a group's entries,
+// and those of nested groups, to a report as warnings
+func (v *Validator) addGroupTimeZoneWarnings(report *ValidationReport, prefix string, g *Group, ancestors []*Group) {
+	for i, entry := range g.Entries {
+		entryPrefix := fmt.Sprintf("%sentries[%d].", prefix, i)
+		switch e := entry.(type) {
+		case *Event:
+			v.addTimeZoneWarnings(report, entryPrefix, e.TimeZone, e.RecurrenceIdTimeZone, e.Locations, e.TimeZones)
+		case *Task:
+			v.addTimeZoneWarnings(report, entryPrefix, e.TimeZone, e.RecurrenceIdTimeZone, e.Locations, e.TimeZones)
+		case *Group:
+			if e != nil && e.UID != g.UID && !isAncestor(e, append(ancestors, g)) {
+				v.addGroupTimeZoneWarnings(report, entryPrefix, e, append(ancestors, g))
+			}
+		}
+	}
+}
+
+// combine merges the result of the RFC checks with option and profile checks
+func (v *Validator) combine(err error, extra ValidationErrors) error {
+	if len(extra) == 0 {
+		return err
+	}
+
+	var errors ValidationErrors
+	switch e := err.(type) {
+	case nil:
+	case ValidationErrors:
+		errors = append(errors, e...)
+	case ValidationError:
+		errors = append(errors, e)
+	default:
+		return err
+	}
+	return append(errors, extra...)
+}
+
+// checkGroup applies option and profile checks to a Group and to its
+// entries, recursing into nested groups below ancestors. Cycles are
+// reported by Group.validate and not followed.
+func (v *Validator) checkGroup(g *Group, ancestors []*Group) ValidationErrors {
+	var errors ValidationErrors
+	if v.opts.RequireTitle && (g.Title == nil || *g.Title == "") {
+		errors = append(errors, ValidationError{Field: "title", Message: "is required"})
+	}
+	for i, entry := range g.Entries {
+		var entryErrors ValidationErrors
+		switch obj := entry.(type) {
+		case *Event:
+			entryErrors = v.checkEvent(obj)
+		case *Task:
+			entryErrors = v.checkTask(obj)
+		case *Group:
+			if obj != nil && obj.UID != g.UID && !isAncestor(obj, append(ancestors, g)) {
+				entryErrors = v.checkGroup(obj, append(ancestors, g))
+			}
+		}
+		for _, err := range entryErrors {
+			err.Field = fmt.Sprintf("entries[%d].%s", i, err.Field)
+			errors = append(errors, err)
+		}
+	}
+	return errors
+}
+
+// checkEvent applies option and profile checks to an Event
+func (v *Validator) checkEvent(e *Event) ValidationErrors {
+	var errors ValidationErrors
+
+	if v.opts.RequireTitle && (e.Title == nil || *e.Title == "") {
+		errors = append(errors, ValidationError{Field: "title", Message: "is required"})
+	}
+
+	timed := e.ShowWithoutTime == nil || !*e.ShowWithoutTime
+	hasTimeZone := e.TimeZone != nil && *e.TimeZone != ""
+	if timed && !hasTimeZone {
+		if v.opts.RequireTimeZone {
+			errors = append(errors, ValidationError{Field: "timeZone", Message: "must be set for timed events"})
+		} else if p := v.opts.Profile; p != nil && p.RecurringRequiresTimeZone && len(e.RecurrenceRules) > 0 {
+			errors = append(errors, ValidationError{
+				Field:   "timeZone",
+				Message: fmt.Sprintf("must be set for recurring events in the %s profile", p.Name),
+			})
+		}
+	}
+
+	if v.opts.UnknownTimeZones == SeverityError {
+		errors = append(errors, v.checkTimeZones(e.TimeZone, e.RecurrenceIdTimeZone, e.Locations, e.TimeZones)...)
+	}
+
+	if v.opts.CheckMethod {
+		errors = append(errors, checkMethod(e.Method, e.Participants, e.ReplyTo)...)
+	}
+
+	return append(errors, v.checkProfile(e.Privacy, e.RecurrenceRules, e.Alerts)...)
+}
+
+// checkTask applies option and profile checks to a Task
+func (v *Validator) checkTask(t *Task) ValidationErrors {
+	var errors ValidationErrors
+
+	if v.opts.RequireTitle && (t.Title == nil || *t.Title == "") {
+		errors = append(errors, ValidationError{Field: "title", Message: "is required"})
+	}
+
+	// Tasks without start or due have no time to anchor
+	timed := (t.Start != nil || t.Due != nil) && (t.ShowWithoutTime == nil || !*t.ShowWithoutTime)
+	if v.opts.RequireTimeZone && timed && (t.TimeZone == nil || *t.TimeZone == "") {
+		errors = append(errors, ValidationError{Field: "timeZone", Message: "must be set for timed tasks"})
+	}
+
+	if v.opts.UnknownTimeZones == SeverityError {
+		errors = append(errors, v.checkTimeZones(t.TimeZone, t.RecurrenceIdTimeZone, t.Locations, t.TimeZones)...)
+	}
+
+	if v.opts.CheckMethod {
+		errors = append(errors, checkMethod(t.Method, t.Participants, t.ReplyTo)...)
+	}
+
+	return append(errors, v.checkProfile(t.Privacy, t.RecurrenceRules, t.Alerts)...)
+}
+
+// checkTimeZones reports time zone ids the resolver doesn't know. Ids of
+// custom zones defined in the object's timeZones are known.
+func (v *Validator) checkTimeZones(timeZone, recurrenceIdTimeZone *string, locations map[string]*Location,
+	custom map[string]*TimeZone) ValidationErrors {
+	resolve := v.opts.TimeZoneResolver
+	if resolve == nil {
+		resolve = ResolveTimeZone
+	}
+
+	var errors ValidationErrors
+	check := func(field string, tzid *string) {
+		if tzid == nil || *tzid == "" || custom[*tzid] != nil || resolve(*tzid) {
+			return
+		}
+		errors = append(errors, ValidationError{Field: field, Value: *tzid, Message: "unknown time zone"})
+	}
+
+	check("timeZone", timeZone)
+	check("recurrenceIdTimeZone", recurrenceIdTimeZone)
+	for _, id := range sortedKeys(locations) {
+		if l := locations[id]; l != nil {
+			check(fmt.Sprintf("locations[%s].timeZone", id), l.TimeZone)
+		}
+	}
+	return errors
+}
+
+// checkProfile rejects values the configured profile does not support
+func (v *Validator) checkProfile(privacy *string, rules []RecurrenceRule, alerts map[string]*Alert) ValidationErrors {
+	p := v.opts.Profile
+	if p == nil {
+		return nil
+	}
+
+	var errors ValidationErrors
+	if privacy != nil && contains(p.UnsupportedPrivacy, *privacy) {
+		errors = append(errors, ValidationError{
+			Field:   "privacy",
+			Value:   *privacy,
+			Message: fmt.Sprintf("unsupported privacy %q for the %s profile", *privacy, p.Name),
+		})
+	}
+	for i, rule := range rules {
+		if contains(p.UnsupportedFrequencies, rule.Frequency) {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("recurrenceRules[%d].frequency", i),
+				Value:   rule.Frequency,
+				Message: fmt.Sprintf("unsupported frequency %q for the %s profile", rule.Frequency, p.Name),
+			})
+		}
+	}
+	for _, id := range sortedKeys(alerts) {
+		if alert := alerts[id]; alert != nil && alert.Action != nil && contains(p.UnsupportedAlertActions, *alert.Action) {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("alerts[%s].action", id),
+				Value:   *alert.Action,
+				Message: fmt.Sprintf("unsupported action %q for the %s profile", *alert.Action, p.Name),
+			})
+		}
+	}
+	return errors
+}
+
+// validateEntry validates a group entry with the given options
+func validateEntry(entry CalendarObject, opts *ValidationOptions) error {
+	switch obj := entry.(type) {
+	case *Event:
+		return obj.validate(opts)
+	case *Task:
+		return obj.validate(opts)
+	default:
+		return entry.Validate()
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
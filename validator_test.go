@@ -0,0 +1,264 @@
+package jscal
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidatorZeroOptionsMatchesValidate(t *testing.T) {
+	event := NewEvent("validator-test", "Validator")
+	event.Start = NewLocalDateTime(time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC))
+	event.Status = String("postponed")
+	event.Title = String(strings.Repeat("a", MaxTitleLength+1))
+
+	want := event.Validate()
+	got := NewValidator(ValidationOptions{}).ValidateEvent(event)
+	if want == nil || got == nil || want.Error() != got.Error() {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestValidatorOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    ValidationOptions
+		modify  func(*Event)
+		wantErr string
+	}{
+		{
+			name:    "length limit enforced by default",
+			modify:  func(e *Event) { e.Description = String(strings.Repeat("a", MaxDescriptionLength+1)) },
+			wantErr: "exceeds maximum length",
+		},
+		{
+			name:   "skip length limits",
+			opts:   ValidationOptions{SkipLengthLimits: true},
+			modify: func(e *Event) { e.Description = String(strings.Repeat("a", MaxDescriptionLength+1)) },
+		},
+		{
+			name: "allow unknown enums",
+			opts: ValidationOptions{AllowUnknownEnums: true},
+			modify: func(e *Event) {
+				e.Status = String("x-postponed")
+				e.AddParticipant("p1", &Participant{Roles: map[string]bool{"x-host": true}})
+			},
+		},
+		{
+			name:    "unknown enums still reject malformed values",
+			opts:    ValidationOptions{AllowUnknownEnums: true},
+			modify:  func(e *Event) { e.Duration = String("1 hour") },
+			wantErr: "invalid ISO 8601 duration format",
+		},
+		{
+			name:    "require title",
+			opts:    ValidationOptions{RequireTitle: true},
+			modify:  func(e *Event) { e.Title = nil },
+			wantErr: "title is required",
+		},
+		{
+			name:    "require timeZone for timed events",
+			opts:    ValidationOptions{RequireTimeZone: true},
+			modify:  func(e *Event) {},
+			wantErr: "timeZone must be set for timed events",
+		},
+		{
+			name:   "require timeZone ignores all-day events",
+			opts:   ValidationOptions{RequireTimeZone: true},
+			modify: func(e *Event) { e.ShowWithoutTime = Bool(true) },
+		},
+		{
+			name: "google rejects secret and sub-daily recurrence",
+			opts: ValidationOptions{Profile: ProfileGoogle},
+			modify: func(e *Event) {
+				e.TimeZone = String("Europe/Paris")
+				e.Privacy = String(PrivacySecret)
+				e.RecurrenceRules = []RecurrenceRule{{Type: "RecurrenceRule", Frequency: "hourly"}}
+			},
+			wantErr: `unsupported frequency "hourly" for the google profile`,
+		},
+		{
+			name: "google requires timeZone on recurring events",
+			opts: ValidationOptions{Profile: ProfileGoogle},
+			modify: func(e *Event) {
+				e.RecurrenceRules = []RecurrenceRule{{Type: "RecurrenceRule", Frequency: "weekly"}}
+			},
+			wantErr: "timeZone must be set for recurring events in the google profile",
+		},
+		{
+			name: "apple rejects email alerts",
+			opts: ValidationOptions{Profile: ProfileApple},
+			modify: func(e *Event) {
+				e.AddAlert("a1", &Alert{
+					Type:    "Alert",
+					Trigger: &OffsetTrigger{Type: "OffsetTrigger", Offset: "-PT15M"},
+					Action:  String("email"),
+				})
+			},
+			wantErr: `unsupported action "email" for the apple profile`,
+		},
+		{
+			name: "fastmail accepts RFC-valid events",
+			opts: ValidationOptions{Profile: ProfileFastmail},
+			modify: func(e *Event) {
+				e.Privacy = String(PrivacySecret)
+				e.RecurrenceRules = []RecurrenceRule{{Type: "RecurrenceRule", Frequency: "hourly"}}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := NewEvent("validator-test", "Validator")
+			event.Start = NewLocalDateTime(time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC))
+			tt.modify(event)
+
+			err := NewValidator(tt.opts).ValidateEvent(event)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidatorGroupEntries(t *testing.T) {
+	group := NewGroup("validator-group", "Group")
+	task := NewTask("validator-task", "")
+	task.Status = String("x-blocked")
+	task.Due = NewLocalDateTime(time.Date(2025, 3, 1, 17, 0, 0, 0, time.UTC))
+	_ = group.AddEntry(task)
+
+	if err := group.Validate(); err == nil {
+		t.Error("Expected default validation to reject unknown task status")
+	}
+
+	validator := NewValidator(ValidationOptions{AllowUnknownEnums: true, RequireTitle: true, RequireTimeZone: true})
+	err := validator.Validate(group)
+	errs, ok := err.(ValidationErrors)
+	if !ok {
+		t.Fatalf("Expected ValidationErrors, got %v", err)
+	}
+
+	fields := make(map[string]bool)
+	for _, e := range errs {
+		fields[e.Field] = true
+	}
+	if len(errs) != 2 || !fields["entries[0].title"] || !fields["entries[0].timeZone"] {
+		t.Errorf("Expected title and timeZone errors on entries[0], got %v", errs)
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	for _, name := range []string{"apple", "google", "fastmail"} {
+		if p, ok := Profiles[name]; !ok || p.Name != name {
+			t.Errorf("Expected profile %q to be registered", name)
+		}
+	}
+}
+
+func TestValidatorUnknownTimeZones(t *testing.T) {
+	event := NewEvent("validator-test", "Validator")
+	event.Start = NewLocalDateTime(time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC))
+	event.TimeZone = String("Not/A_Zone")
+	event.RecurrenceIdTimeZone = String("/example.com/Custom")
+	event.TimeZones = map[string]*TimeZone{"/example.com/Custom": NewTimeZone("/example.com/Custom")}
+	location := NewLocation("Office")
+	location.TimeZone = String("Europe/Atlantis")
+	event.AddLocation("office", location)
+
+	if err := event.Validate(); err != nil {
+		t.Fatalf("Expected format-only validation to pass, got %v", err)
+	}
+
+	err := NewValidator(ValidationOptions{UnknownTimeZones: SeverityError}).ValidateEvent(event)
+	errs, ok := err.(ValidationErrors)
+	if !ok {
+		t.Fatalf("Expected ValidationErrors, got %v", err)
+	}
+	if len(errs) != 2 || errs[0].Field != "timeZone" || errs[1].Field != "locations[office].timeZone" {
+		t.Errorf("Expected timeZone and locations[office].timeZone errors, got %v", errs)
+	}
+
+	report := NewValidator(ValidationOptions{UnknownTimeZones: SeverityWarning}).ValidateReport(event)
+	if !report.Valid() {
+		t.Errorf("Expected warnings only, got %v", report.Issues)
+	}
+	warnings := report.Filter(SeverityWarning)
+	if len(warnings) != 2 || warnings[0].Pointer != "/locations/office/timeZone" || warnings[1].Pointer != "/timeZone" {
+		t.Errorf("Expected two time zone warnings, got %v", warnings)
+	}
+
+	resolver := func(tzid string) bool { return tzid == "Not/A_Zone" || tzid == "Europe/Atlantis" }
+	validator := NewValidator(ValidationOptions{UnknownTimeZones: SeverityError, TimeZoneResolver: resolver})
+	if err := validator.ValidateEvent(event); err != nil {
+		t.Errorf("Expected the custom resolver to accept the zones, got %v", err)
+	}
+}
+
+func TestValidatorReportGroupTimeZones(t *testing.T) {
+	group := NewGroup("validator-group", "Group")
+	task := NewTask("validator-task", "Task")
+	task.TimeZone = String("Mars/Olympus_Mons")
+	_ = group.AddEntry(task)
+
+	report := NewValidator(ValidationOptions{UnknownTimeZones: SeverityWarning}).ValidateReport(group)
+	warnings := report.Filter(SeverityWarning)
+	if len(warnings) != 1 || warnings[0].Pointer != "/entries/0/timeZone" {
+		t.Errorf("Expected a warning on /entries/0/timeZone, got %v", report.Issues)
+	}
+}
+
+func TestValidatorNestedGroups(t *testing.T) {
+	inner := NewGroup("validator-inner", "")
+	event := NewEvent("validator-test", "Validator")
+	event.Start = NewLocalDateTime(time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC))
+	event.Title = nil
+	event.TimeZone = String("Mars/Olympus_Mons")
+	_ = inner.AddEntry(event)
+	outer := NewGroup("validator-outer", "Outer")
+	_ = outer.AddEntry(inner)
+
+	validator := NewValidator(ValidationOptions{RequireTitle: true, UnknownTimeZones: SeverityWarning})
+	errs, ok := validator.Validate(outer).(ValidationErrors)
+	if !ok {
+		t.Fatalf("Expected ValidationErrors, got %v", validator.Validate(outer))
+	}
+	if len(errs) != 2 || errs[0].Field != "entries[0].title" || errs[1].Field != "entries[0].entries[0].title" {
+		t.Errorf("Expected title errors on the nested group and its event, got %v", errs)
+	}
+
+	warnings := validator.ValidateReport(outer).Filter(SeverityWarning)
+	if len(warnings) != 1 || warnings[0].Pointer != "/entries/0/entries/0/timeZone" {
+		t.Errorf("Expected a warning on /entries/0/entries/0/timeZone, got %v", warnings)
+	}
+}
+
+func TestValidatorProfileAlertOrder(t *testing.T) {
+	event := NewEvent("validator-test", "Validator")
+	event.Start = NewLocalDateTime(time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC))
+	for _, id := range []string{"c", "a", "d", "b"} {
+		event.AddAlert(id, &Alert{
+			Type:    "Alert",
+			Trigger: &OffsetTrigger{Type: "OffsetTrigger", Offset: "-PT15M"},
+			Action:  String("email"),
+		})
+	}
+
+	for i := 0; i < 5; i++ {
+		errs, ok := NewValidator(ValidationOptions{Profile: ProfileApple}).ValidateEvent(event).(ValidationErrors)
+		if !ok || len(errs) != 4 {
+			t.Fatalf("Expected 4 errors, got %v", errs)
+		}
+		for j, id := range []string{"a", "b", "c", "d"} {
+			if want := "alerts[" + id + "].action"; errs[j].Field != want {
+				t.Fatalf("Expected %s at %d, got %v", want, j, errs)
+			}
+		}
+	}
+}
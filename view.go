@@ -0,0 +1,156 @@
+package jscal
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// AudienceProperty is the extension property restricting alerts and links
+// to some participants. It maps "alerts/<id>" and "links/<id>" to the set
+// of participant ids the alert or link is for, e.g.
+// {"alerts/a1": {"p1": true}}. Alerts and links without an entry are for
+// everyone.
+const AudienceProperty = "airtrafik.com:audience"
+
+// SetAlertAudience restricts the alert with the given id to some
+// participants; none removes the restriction
+func (e *Event) SetAlertAudience(alertID string, participantIDs ...string) {
+	e.setAudience("alerts/"+alertID, participantIDs)
+}
+
+// SetLinkAudience restricts the link with the given id to some
+// participants; none removes the restriction
+func (e *Event) SetLinkAudience(linkID string, participantIDs ...string) {
+	e.setAudience("links/"+linkID, participantIDs)
+}
+
+// setAudience sets the participants of an audience entry
+func (e *Event) setAudience(path string, participantIDs []string) {
+	audience := e.audience()
+	if len(participantIDs) == 0 {
+		delete(audience, path)
+	} else {
+		audience[path] = make(map[string]bool, len(participantIDs))
+		for _, id := range participantIDs {
+			audience[path][id] = true
+		}
+	}
+	if len(audience) == 0 {
+		delete(e.Extensions, AudienceProperty)
+		return
+	}
+	e.SetExtension(AudienceProperty, audience)
+}
+
+// audience returns the event's AudienceProperty, whether set from Go or
+// decoded from JSON
+func (e *Event) audience() map[string]map[string]bool {
+	audience := make(map[string]map[string]bool)
+	if value, ok := e.Extensions[AudienceProperty]; ok {
+		data, _ := json.Marshal(value)
+		_ = json.Unmarshal(data, &audience)
+	}
+	return audience
+}
+
+// ViewForParticipant returns the event as the participant with the given id
+// sees it, e.g. to render their invitation:
+//
+//   - the localization matching the participant's language is applied,
+//     and localizations are removed
+//   - if the participant has a locationId, it is the only location
+//   - alerts and links restricted to other participants by
+//     AudienceProperty are removed, as is the property
+//   - if hideAttendees is true, participants other than the owners and
+//     the participant itself are removed
+//   - the scheduling internals, requestStatus and every participant's
+//     schedule* properties, are removed
+func (e *Event) ViewForParticipant(id string) (*Event, error) {
+	participant := e.Participants[id]
+	if participant == nil {
+		return nil, fmt.Errorf("participant '%s' not found in event", id)
+	}
+
+	view := e.Clone()
+	if participant.Language != nil {
+		if tag := localizationTag(view.Localizations, *participant.Language); tag != "" {
+			localized, err := localize(view, view.Localizations[tag])
+			if err != nil {
+				return nil, fmt.Errorf("localizations[%s]: %w", tag, err)
+			}
+			view = localized
+			view.Locale = String(tag)
+		}
+	}
+	view.Localizations = nil
+
+	if participant.LocationId != nil {
+		if location, ok := view.Locations[*participant.LocationId]; ok {
+			view.Locations = map[string]*Location{*participant.LocationId: location}
+		}
+	}
+
+	audience := view.audience()
+	for alertID := range view.Alerts {
+		if ids, ok := audience["alerts/"+alertID]; ok && !ids[id] {
+			delete(view.Alerts, alertID)
+		}
+	}
+	for linkID := range view.Links {
+		if ids, ok := audience["links/"+linkID]; ok && !ids[id] {
+			delete(view.Links, linkID)
+		}
+	}
+	delete(view.Extensions, AudienceProperty)
+
+	if view.GetHideAttendees() {
+		for pid, p := range view.Participants {
+			if pid != id && (p == nil || !p.Roles[RoleOwner]) {
+				delete(view.Participants, pid)
+			}
+		}
+	}
+
+	view.RequestStatus = nil
+	for _, p := range view.Participants {
+		p.ScheduleAgent = nil
+		p.ScheduleForceSend = nil
+		p.ScheduleSequence = nil
+		p.ScheduleStatus = nil
+		p.ScheduleUpdated = nil
+	}
+	return view, nil
+}
+
+// localizationTag returns the key of the localization for a language tag,
+// following its lookup chain from the exact tag to the primary language
+// subtag, or ""
+func localizationTag(localizations map[string]map[string]interface{}, language string) string {
+	if matches := matchLanguage(localizations, language); len(matches) > 0 {
+		return matches[0]
+	}
+	return ""
+}
+
+// localize returns a copy of the event with a localization patch applied
+func localize(e *Event, patch map[string]interface{}) (*Event, error) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+	if err := applyPatch(obj, patch); err != nil {
+		return nil, err
+	}
+	if data, err = json.Marshal(obj); err != nil {
+		return nil, err
+	}
+	var localized Event
+	if err := json.Unmarshal(data, &localized); err != nil {
+		return nil, err
+	}
+	return &localized, nil
+}
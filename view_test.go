@@ -0,0 +1,155 @@
+package jscal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestViewForParticipant(t *testing.T) {
+	event := NewEvent("view-test", "Team offsite")
+	event.Description = String("Two days of planning")
+	event.RequestStatus = String("2.0;Success")
+	event.AddLocation("vienna", NewLocation("Vienna office"))
+	event.AddLocation("berlin", NewLocation("Berlin office"))
+	event.AddAlert("all", &Alert{Type: "Alert", Trigger: NewOffsetTrigger("-PT15M")})
+	event.AddAlert("organizer", &Alert{Type: "Alert", Trigger: NewOffsetTrigger("-P1D")})
+	event.Links = map[string]*Link{
+		"agenda": {Href: "https://example.com/agenda"},
+		"budget": {Href: "https://example.com/budget"},
+	}
+	event.Localizations = map[string]map[string]interface{}{
+		"de": {"title": "Teamklausur", "description": "Zwei Tage Planung"},
+	}
+
+	now := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	event.Participants = map[string]*Participant{
+		"anna": {
+			Name:             String("Anna"),
+			Language:         String("de-AT"),
+			LocationId:       String("vienna"),
+			ScheduleAgent:    String("server"),
+			ScheduleSequence: Int(2),
+			ScheduleStatus:   []string{"2.0"},
+			ScheduleUpdated:  NewUTCDateTime(now),
+		},
+		"olga": {Name: String("Olga"), Roles: map[string]bool{"owner": true}},
+	}
+	event.SetAlertAudience("organizer", "olga")
+	event.SetLinkAudience("budget", "olga")
+
+	view, err := event.ViewForParticipant("anna")
+	if err != nil {
+		t.Fatalf("ViewForParticipant failed: %v", err)
+	}
+	if view.GetTitle() != "Teamklausur" || view.GetDescription() != "Zwei Tage Planung" {
+		t.Errorf("Expected German title and description, got %q and %q", view.GetTitle(), view.GetDescription())
+	}
+	if view.GetLocale() != "de" {
+		t.Errorf("Expected locale de, got %q", view.GetLocale())
+	}
+	if view.Localizations != nil {
+		t.Errorf("Expected localizations to be removed, got %v", view.Localizations)
+	}
+	if len(view.Locations) != 1 || view.Locations["vienna"] == nil {
+		t.Errorf("Expected only the vienna location, got %v", view.Locations)
+	}
+	if len(view.Alerts) != 1 || view.Alerts["all"] == nil {
+		t.Errorf("Expected only the shared alert, got %v", view.Alerts)
+	}
+	if len(view.Links) != 1 || view.Links["agenda"] == nil {
+		t.Errorf("Expected only the shared link, got %v", view.Links)
+	}
+	if _, ok := view.Extensions[AudienceProperty]; ok {
+		t.Errorf("Expected %s to be removed", AudienceProperty)
+	}
+	if view.RequestStatus != nil {
+		t.Errorf("Expected requestStatus to be removed, got %s", *view.RequestStatus)
+	}
+	anna := view.Participants["anna"]
+	if anna.ScheduleAgent != nil || anna.ScheduleSequence != nil || anna.ScheduleStatus != nil || anna.ScheduleUpdated != nil {
+		t.Errorf("Expected schedule properties to be removed, got %+v", anna)
+	}
+
+	// The event itself is unchanged
+	if event.GetTitle() != "Team offsite" || len(event.Locations) != 2 || len(event.Alerts) != 2 || event.RequestStatus == nil {
+		t.Errorf("Expected the event to be unchanged")
+	}
+	if event.Participants["anna"].ScheduleSequence == nil {
+		t.Errorf("Expected the participant's scheduleSequence to be kept")
+	}
+
+	// A participant without a language gets the unlocalized event
+	view, err = event.ViewForParticipant("olga")
+	if err != nil {
+		t.Fatalf("ViewForParticipant failed: %v", err)
+	}
+	if view.GetTitle() != "Team offsite" {
+		t.Errorf("Expected the title unlocalized, got %q", view.GetTitle())
+	}
+	if len(view.Locations) != 2 {
+		t.Errorf("Expected both locations, got %d", len(view.Locations))
+	}
+	if len(view.Alerts) != 2 || len(view.Links) != 2 {
+		t.Errorf("Expected all alerts and links, got %d alerts and %d links", len(view.Alerts), len(view.Links))
+	}
+
+	// The audience survives a JSON round trip
+	data, err := event.JSON()
+	if err != nil {
+		t.Fatalf("JSON failed: %v", err)
+	}
+	parsed, err := ParseEvent(data)
+	if err != nil {
+		t.Fatalf("ParseEvent failed: %v", err)
+	}
+	view, err = parsed.ViewForParticipant("anna")
+	if err != nil {
+		t.Fatalf("ViewForParticipant failed: %v", err)
+	}
+	if len(view.Alerts) != 1 || len(view.Links) != 1 {
+		t.Errorf("Expected the audience to survive JSON, got %d alerts and %d links", len(view.Alerts), len(view.Links))
+	}
+
+	// hideAttendees limits the participants to the viewer and owners
+	hidden := event.Clone()
+	hidden.Participants["ben"] = &Participant{Name: String("Ben")}
+	hidden.SetHideAttendees(true)
+
+	view, err = hidden.ViewForParticipant("anna")
+	if err != nil {
+		t.Fatalf("ViewForParticipant failed: %v", err)
+	}
+	if len(view.Participants) != 2 || view.Participants["anna"] == nil || view.Participants["olga"] == nil {
+		t.Errorf("Expected only anna and the owner, got %v", view.Participants)
+	}
+	if len(hidden.Participants) != 3 {
+		t.Errorf("Expected the event to keep every participant, got %d", len(hidden.Participants))
+	}
+
+	hidden.SetHideAttendees(false)
+	if view, _ = hidden.ViewForParticipant("anna"); len(view.Participants) != 3 {
+		t.Errorf("Expected every participant without hideAttendees, got %d", len(view.Participants))
+	}
+
+	// Unknown participants and broken localizations fail
+	if _, err := event.ViewForParticipant("nobody"); err == nil {
+		t.Error("Expected error for unknown participant")
+	}
+
+	event.Localizations["de"]["locations/missing/name"] = "Fehlt"
+	if _, err := event.ViewForParticipant("anna"); err == nil {
+		t.Error("Expected error for invalid localization patch")
+	}
+}
+
+func TestSetAudience(t *testing.T) {
+	event := NewEvent("audience-test", "Audience")
+	event.SetAlertAudience("a1", "p1", "p2")
+	if audience := event.audience(); !audience["alerts/a1"]["p1"] || !audience["alerts/a1"]["p2"] {
+		t.Errorf("Expected alert audience p1 and p2, got %v", audience)
+	}
+	event.SetAlertAudience("a1")
+	if _, ok := event.Extensions[AudienceProperty]; ok {
+		t.Errorf("Expected %s to be removed with the last entry", AudienceProperty)
+	}
+}
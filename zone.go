@@ -0,0 +1,147 @@
+package jscal
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// loadZone returns the location named tz. The empty name is floating time,
+// which is read as UTC.
+func loadZone(tz string) (*time.Location, error) {
+	if tz == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %s", tz)
+	}
+	return loc, nil
+}
+
+// InZone returns the instant at which the wall clock time occurs in the
+// time zone tz. An empty tz is floating time and is read as UTC. Times
+// skipped by a DST transition are normalized as by time.Date.
+func (ldt LocalDateTime) InZone(tz string) (time.Time, error) {
+	loc, err := loadZone(tz)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return ldt.In(loc), nil
+}
+
+// AddInZone adds d as exact time in the time zone tz and returns the wall
+// clock time reached: in Europe/Berlin, 2025-03-30T01:30:00 plus one hour
+// is 03:30:00, as the clocks skip 02:00 to 03:00 that night.
+func (ldt LocalDateTime) AddInZone(d time.Duration, tz string) (LocalDateTime, error) {
+	t, err := ldt.InZone(tz)
+	if err != nil {
+		return LocalDateTime{}, err
+	}
+	return LocalDateTime(wallClock(LocalDateTime(t.Add(d)))), nil
+}
+
+// AddDurationInZone adds an ISO 8601 duration in the time zone tz as RFC
+// 8984 Section 1.4.6 describes: weeks and days are nominal and move the
+// wall clock by calendar days, the time part is exact. "P1D" is therefore
+// the same time on the next day, and "PT24H" may not be across a DST change.
+func (ldt LocalDateTime) AddDurationInZone(duration, tz string) (LocalDateTime, error) {
+	days, exact, err := splitDuration(duration)
+	if err != nil {
+		return LocalDateTime{}, err
+	}
+	return LocalDateTime(wallClock(ldt).AddDate(0, 0, days)).AddInZone(exact, tz)
+}
+
+// splitDuration returns the whole days of the date part of an ISO 8601
+// duration and the rest as exact time
+func splitDuration(duration string) (int, time.Duration, error) {
+	total, err := parseISO8601Duration(duration)
+	if err != nil {
+		return 0, 0, err
+	}
+	nominal := total
+	if i := strings.IndexByte(duration, 'T'); i >= 0 {
+		nominal = 0
+		if date := duration[:i]; !strings.HasSuffix(date, "P") {
+			if nominal, err = parseISO8601Duration(date); err != nil {
+				return 0, 0, err
+			}
+		}
+	}
+	days := nominal / (24 * time.Hour)
+	return int(days), total - days*24*time.Hour, nil
+}
+
+// eventTimeZone returns the time zone of an event's times, or "" for
+// floating and all-day times and zones time.LoadLocation doesn't know
+func eventTimeZone(e *Event) string {
+	if timeZoneOf(e.TimeZone, e.IsAllDay(), nil) == nil {
+		return ""
+	}
+	return *e.TimeZone
+}
+
+// ZonedTime returns the instant of a date-time of the event, such as its
+// start or a recurrence id, in the event's time zone. Floating and all-day
+// times, and times in zones that can't be loaded, are read as UTC.
+func (e *Event) ZonedTime(ldt LocalDateTime) time.Time {
+	t, _ := ldt.InZone(eventTimeZone(e))
+	return t
+}
+
+// OccursOn reports whether an instance of the event takes place on the
+// calendar day of date in the time zone tz, comparing instants: an event
+// at 23:30 in New York occurs on the next day in Berlin. Floating events
+// are read in tz, and all-day events are compared by date.
+func (e *Event) OccursOn(date LocalDateTime, tz string) (bool, error) {
+	loc, err := loadZone(tz)
+	if err != nil {
+		return false, err
+	}
+	if e.Start == nil {
+		return false, fmt.Errorf("event %s has no start", e.UID)
+	}
+	var length time.Duration
+	if e.Duration != nil {
+		if length, err = e.GetDuration(); err != nil {
+			return false, fmt.Errorf("event %s: %w", e.UID, err)
+		}
+	}
+
+	d := time.Time(date)
+	day := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC)
+	// Zone offsets are within a day, so instances overlapping the day start
+	// within a day of it, or earlier by the event's length
+	occurrences, err := e.Occurrences(LocalDateTime(day.Add(-length).AddDate(0, 0, -2)), LocalDateTime(day.AddDate(0, 0, 2)))
+	if err != nil {
+		return false, err
+	}
+
+	zone := eventTimeZone(e)
+	dayStart := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+	dayEnd := time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, loc)
+	for _, occurrence := range occurrences {
+		start, end := occurrence.Start(), occurrence.End()
+		if e.IsAllDay() {
+			first, last := wallClock(start).Truncate(24*time.Hour), wallClock(end)
+			if last.Equal(first) {
+				last = last.Add(time.Nanosecond)
+			}
+			if !first.After(day) && last.After(day) {
+				return true, nil
+			}
+			continue
+		}
+
+		from, to := start.In(loc), end.In(loc)
+		if zone != "" {
+			from, _ = start.InZone(zone)
+			to, _ = end.InZone(zone)
+		}
+		if from.Before(dayEnd) && (to.After(dayStart) || !from.Before(dayStart)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
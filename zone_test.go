@@ -0,0 +1,164 @@
+package jscal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAddInZone(t *testing.T) {
+	if !hasTimeZoneDatabase() {
+		t.Skip("time zone database not available")
+	}
+	tests := []struct {
+		name     string
+		start    string
+		add      func(ldt LocalDateTime) (LocalDateTime, error)
+		expected string
+	}{
+		{
+			name:  "exact hour across spring forward",
+			start: "2025-03-30T01:30:00",
+			add: func(ldt LocalDateTime) (LocalDateTime, error) {
+				return ldt.AddInZone(time.Hour, "Europe/Berlin")
+			},
+			expected: "2025-03-30T03:30:00",
+		},
+		{
+			name:  "exact hour in floating time",
+			start: "2025-03-30T01:30:00",
+			add: func(ldt LocalDateTime) (LocalDateTime, error) {
+				return ldt.AddInZone(time.Hour, "")
+			},
+			expected: "2025-03-30T02:30:00",
+		},
+		{
+			name:  "nominal day across spring forward",
+			start: "2025-03-29T09:00:00",
+			add: func(ldt LocalDateTime) (LocalDateTime, error) {
+				return ldt.AddDurationInZone("P1D", "Europe/Berlin")
+			},
+			expected: "2025-03-30T09:00:00",
+		},
+		{
+			name:  "24 hours across spring forward",
+			start: "2025-03-29T09:00:00",
+			add: func(ldt LocalDateTime) (LocalDateTime, error) {
+				return ldt.AddDurationInZone("PT24H", "Europe/Berlin")
+			},
+			expected: "2025-03-30T10:00:00",
+		},
+		{
+			name:  "exact hours across fall back",
+			start: "2025-10-25T23:00:00",
+			add: func(ldt LocalDateTime) (LocalDateTime, error) {
+				return ldt.AddDurationInZone("PT5H", "Europe/Berlin")
+			},
+			expected: "2025-10-26T03:00:00",
+		},
+		{
+			name:  "day and hours across fall back",
+			start: "2025-10-25T23:00:00",
+			add: func(ldt LocalDateTime) (LocalDateTime, error) {
+				return ldt.AddDurationInZone("P1DT3H", "Europe/Berlin")
+			},
+			expected: "2025-10-27T02:00:00",
+		},
+		{
+			name:  "two weeks",
+			start: "2025-03-20T09:00:00",
+			add: func(ldt LocalDateTime) (LocalDateTime, error) {
+				return ldt.AddDurationInZone("P2W", "America/New_York")
+			},
+			expected: "2025-04-03T09:00:00",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.add(mustLocal(t, tt.start))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got.String() != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+
+	if _, err := mustLocal(t, "2025-03-30T01:30:00").AddInZone(time.Hour, "Mars/Olympus_Mons"); err == nil {
+		t.Error("Expected an error for an unknown time zone")
+	}
+}
+
+func TestEventEndTimeAcrossDST(t *testing.T) {
+	if !hasTimeZoneDatabase() {
+		t.Skip("time zone database not available")
+	}
+	event := NewEvent("dst-end", "Night shift")
+	start := mustLocal(t, "2025-03-30T01:00:00")
+	event.Start = &start
+	event.Duration = String("PT3H")
+	event.TimeZone = String("Europe/Berlin")
+
+	end, err := event.GetEndTime()
+	if err != nil {
+		t.Fatalf("GetEndTime failed: %v", err)
+	}
+	if got := end.Format("15:04 MST"); got != "05:00 CEST" {
+		t.Errorf("Expected the shift to end at 05:00 CEST, got %s", got)
+	}
+	if d := end.Sub(event.ZonedTime(start)); d != 3*time.Hour {
+		t.Errorf("Expected 3h between start and end, got %v", d)
+	}
+}
+
+func TestOccursOn(t *testing.T) {
+	if !hasTimeZoneDatabase() {
+		t.Skip("time zone database not available")
+	}
+	late := NewEvent("late-call", "Late call")
+	lateStart := mustLocal(t, "2025-03-03T23:30:00")
+	late.Start = &lateStart
+	late.Duration = String("PT30M")
+	late.TimeZone = String("America/New_York")
+	late.RecurrenceRules = []RecurrenceRule{{Frequency: FrequencyWeekly}}
+
+	holiday := NewEvent("holiday", "Holiday")
+	holidayStart := mustLocal(t, "2025-03-05T00:00:00")
+	holiday.Start = &holidayStart
+	holiday.Duration = String("P2D")
+	holiday.ShowWithoutTime = Bool(true)
+
+	tests := []struct {
+		name  string
+		event *Event
+		date  string
+		tz    string
+		want  bool
+	}{
+		{name: "same day in the event zone", event: late, date: "2025-03-03T00:00:00", tz: "America/New_York", want: true},
+		{name: "next day in Berlin", event: late, date: "2025-03-04T00:00:00", tz: "Europe/Berlin", want: true},
+		{name: "not the day before in Berlin", event: late, date: "2025-03-03T00:00:00", tz: "Europe/Berlin", want: false},
+		{name: "later instance", event: late, date: "2025-03-18T00:00:00", tz: "Europe/Berlin", want: true},
+		{name: "no instance", event: late, date: "2025-03-12T00:00:00", tz: "Europe/Berlin", want: false},
+		{name: "all-day first day", event: holiday, date: "2025-03-05T00:00:00", tz: "Asia/Tokyo", want: true},
+		{name: "all-day second day", event: holiday, date: "2025-03-06T12:00:00", tz: "America/New_York", want: true},
+		{name: "all-day end is exclusive", event: holiday, date: "2025-03-07T00:00:00", tz: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.event.OccursOn(mustLocal(t, tt.date), tt.tz)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := late.OccursOn(lateStart, "Nowhere/Special"); err == nil {
+		t.Error("Expected an error for an unknown time zone")
+	}
+}